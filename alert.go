@@ -0,0 +1,574 @@
+package glyph
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// AlertSeverity is how serious an alert is.
+type AlertSeverity int
+
+const (
+	AlertInfo AlertSeverity = iota
+	AlertWarning
+	AlertCritical
+)
+
+func (s AlertSeverity) String() string {
+	switch s {
+	case AlertInfo:
+		return "info"
+	case AlertWarning:
+		return "warning"
+	case AlertCritical:
+		return "critical"
+	}
+	return "unknown"
+}
+
+// Alert is a rule that has fired, or fired and since resolved.
+type Alert struct {
+	Rule     string
+	Severity AlertSeverity
+	Message  string
+	Since    time.Time // when it was raised
+	Resolved time.Time // when its rule stopped holding, zero while active
+	Acked    bool
+	Muted    bool
+}
+
+// AlertRule is a condition that raises an alert while it holds. Rules are
+// made by an Alerts' Above, Below, When and Expr.
+type AlertRule struct {
+	name     string
+	severity AlertSeverity
+	cond     func() bool
+	message  func() string
+	hold     time.Duration
+
+	pending    time.Time // when the condition started holding, zero if not
+	mutedUntil time.Time // zero when not muted
+	mutedEver  bool      // muted until Unmute
+	active     *Alert
+}
+
+// Severity sets the severity of the rule's alerts, AlertWarning by default.
+func (r *AlertRule) Severity(s AlertSeverity) *AlertRule {
+	r.severity = s
+	return r
+}
+
+// For makes the condition hold for d before the alert is raised, so a
+// brief spike doesn't raise one.
+func (r *AlertRule) For(d time.Duration) *AlertRule {
+	r.hold = d
+	return r
+}
+
+// Message sets how the alert's message is worded, read whenever the rule
+// is checked while it holds.
+func (r *AlertRule) Message(fn func() string) *AlertRule {
+	r.message = fn
+	return r
+}
+
+// Alerts is a small rules engine over values the app already has: each
+// rule is checked against the values bound to it, raising an Alert while
+// it holds and resolving it when it stops:
+//
+//	alerts := NewAlerts()
+//	alerts.Above("replica lag", &repl.Data.LagSeconds, 30).Severity(AlertCritical).For(time.Minute)
+//	alerts.When("CI unreachable", func() bool { return ci.State == SourceError })
+//	alerts.Bind("mem", &mem).Bind("swap", &swap)
+//	alerts.Expr("memory pressure", "mem > 80 && swap > 0")
+//	alerts.OnRaise(func(a Alert) { app.Notify(a.Rule, a.Message) })
+//
+//	AlertList(alerts).BindNav("j", "k").BindAck("a").BindMute("m", time.Hour)
+//	Badge(Text("Alerts"), &alerts.Unacked)
+//
+// Rules are checked by Check, which an AlertList calls each frame; call it
+// yourself, on the UI goroutine, if there's no list on screen. Like a
+// DataSource, the results are plain fields for the view to bind to.
+type Alerts struct {
+	Active   []Alert // firing, most severe first, then newest
+	Resolved []Alert // newest first
+	Unacked  int     // active alerts neither acknowledged nor muted
+
+	rules     []*AlertRule
+	values    map[string]any
+	history   int
+	clock     Clock
+	onRaise   func(Alert)
+	onResolve func(Alert)
+}
+
+// NewAlerts creates an engine with no rules, keeping the last 20 resolved
+// alerts.
+func NewAlerts() *Alerts {
+	return &Alerts{values: map[string]any{}, history: 20}
+}
+
+// Bind makes the value v points to available to Expr rules as name.
+func (a *Alerts) Bind(name string, v any) *Alerts {
+	a.values[name] = v
+	return a
+}
+
+// History sets how many resolved alerts are kept.
+func (a *Alerts) History(n int) *Alerts {
+	a.history = max(n, 0)
+	return a
+}
+
+// OnRaise sets a function called when an alert is raised, to notify or
+// ring the bell. It isn't called for muted rules.
+func (a *Alerts) OnRaise(fn func(Alert)) *Alerts {
+	a.onRaise = fn
+	return a
+}
+
+// OnResolve sets a function called when an alert resolves.
+func (a *Alerts) OnResolve(fn func(Alert)) *Alerts {
+	a.onResolve = fn
+	return a
+}
+
+// SetClock sets the clock rules are timed by, for tests.
+func (a *Alerts) SetClock(c Clock) *Alerts {
+	a.clock = c
+	return a
+}
+
+// When adds a rule that holds while cond returns true.
+func (a *Alerts) When(name string, cond func() bool) *AlertRule {
+	r := &AlertRule{name: name, severity: AlertWarning, cond: cond, message: func() string { return name }}
+	a.rules = append(a.rules, r)
+	return r
+}
+
+// Above adds a rule that holds while the number value points to is over
+// limit. Any integer or float type will do, or a numeric string.
+func (a *Alerts) Above(name string, value any, limit float64) *AlertRule {
+	return a.threshold(name, value, limit, ">", func(n float64) bool { return n > limit })
+}
+
+// Below adds a rule that holds while the number value points to is under
+// limit.
+func (a *Alerts) Below(name string, value any, limit float64) *AlertRule {
+	return a.threshold(name, value, limit, "<", func(n float64) bool { return n < limit })
+}
+
+func (a *Alerts) threshold(name string, value any, limit float64, op string, test func(float64) bool) *AlertRule {
+	r := a.When(name, func() bool {
+		n, ok := alertNumber(value)
+		return ok && test(n)
+	})
+	r.message = func() string {
+		n, _ := alertNumber(value)
+		return fmt.Sprintf("%s %s %s %s", name, alertFormat(n), op, alertFormat(limit))
+	}
+	return r
+}
+
+// Expr adds a rule that holds while expr, in the LogFilter language, is
+// true of the bound values:
+//
+//	alerts.Expr("slow and failing", "latency_ms >= 500 && errors > 0")
+//
+// Comparisons with numbers compare numerically, and bare words and ~
+// match the values' text.
+func (a *Alerts) Expr(name, expr string) (*AlertRule, error) {
+	filter, err := ParseLogFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+	r := a.When(name, func() bool { return filter.MatchFields("", a.fields()) })
+	r.message = func() string { return name + ": " + filter.String() }
+	return r, nil
+}
+
+// fields are the bound values as text, for Expr rules.
+func (a *Alerts) fields() LogFields {
+	f := make(LogFields, len(a.values))
+	for name, v := range a.values {
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Pointer && !rv.IsNil() {
+			rv = rv.Elem()
+		}
+		if !rv.IsValid() {
+			continue
+		}
+		if n, ok := alertNumber(rv.Interface()); ok {
+			f[name] = alertFormat(n)
+		} else {
+			f[name] = fmt.Sprint(rv.Interface())
+		}
+	}
+	return f
+}
+
+// Ack acknowledges the rule's active alert: it stays listed, dimmed, and
+// stops counting in Unacked until it resolves.
+func (a *Alerts) Ack(rule string) {
+	if r := a.rule(rule); r != nil && r.active != nil {
+		r.active.Acked = true
+		a.collect()
+	}
+}
+
+// Mute silences a rule for d, or until Unmute if d is 0: it raises no
+// OnRaise calls and its alert doesn't count in Unacked.
+func (a *Alerts) Mute(rule string, d time.Duration) {
+	r := a.rule(rule)
+	if r == nil {
+		return
+	}
+	if d > 0 {
+		r.mutedUntil, r.mutedEver = a.now().Add(d), false
+	} else {
+		r.mutedUntil, r.mutedEver = time.Time{}, true
+	}
+	if r.active != nil {
+		r.active.Muted = true
+	}
+	a.collect()
+}
+
+// Unmute ends a Mute.
+func (a *Alerts) Unmute(rule string) {
+	if r := a.rule(rule); r != nil {
+		r.mutedUntil, r.mutedEver = time.Time{}, false
+		if r.active != nil {
+			r.active.Muted = false
+		}
+		a.collect()
+	}
+}
+
+// Check checks every rule, raising and resolving alerts.
+func (a *Alerts) Check() {
+	a.check()
+}
+
+// check checks the rules and returns how long until a rule's For runs out
+// or a mute ends, 0 if none is waiting.
+func (a *Alerts) check() (next time.Duration) {
+	now := a.now()
+	soonest := func(d time.Duration) {
+		if d > 0 && (next == 0 || d < next) {
+			next = d
+		}
+	}
+	for _, r := range a.rules {
+		if !r.mutedUntil.IsZero() {
+			if now.Before(r.mutedUntil) {
+				soonest(r.mutedUntil.Sub(now))
+			} else {
+				r.mutedUntil = time.Time{}
+			}
+		}
+		muted := r.mutedEver || !r.mutedUntil.IsZero()
+		if !r.cond() {
+			r.pending = time.Time{}
+			if r.active != nil {
+				resolved := *r.active
+				resolved.Resolved = now
+				r.active = nil
+				a.Resolved = slices.Insert(a.Resolved, 0, resolved)
+				if len(a.Resolved) > a.history {
+					a.Resolved = a.Resolved[:a.history]
+				}
+				if a.onResolve != nil {
+					a.onResolve(resolved)
+				}
+			}
+			continue
+		}
+		if r.active != nil {
+			r.active.Message, r.active.Muted = r.message(), muted
+			continue
+		}
+		if r.pending.IsZero() {
+			r.pending = now
+		}
+		if wait := r.pending.Add(r.hold).Sub(now); wait > 0 {
+			soonest(wait)
+			continue
+		}
+		r.active = &Alert{Rule: r.name, Severity: r.severity, Message: r.message(), Since: now, Muted: muted}
+		if a.onRaise != nil && !muted {
+			a.onRaise(*r.active)
+		}
+	}
+	a.collect()
+	return next
+}
+
+// collect gathers the active alerts into the exported fields.
+func (a *Alerts) collect() {
+	a.Active, a.Unacked = a.Active[:0], 0
+	for _, r := range a.rules {
+		if r.active != nil {
+			a.Active = append(a.Active, *r.active)
+			if !r.active.Acked && !r.active.Muted {
+				a.Unacked++
+			}
+		}
+	}
+	slices.SortStableFunc(a.Active, func(x, y Alert) int {
+		if x.Severity != y.Severity {
+			return int(y.Severity - x.Severity)
+		}
+		return y.Since.Compare(x.Since)
+	})
+}
+
+func (a *Alerts) rule(name string) *AlertRule {
+	for _, r := range a.rules {
+		if r.name == name {
+			return r
+		}
+	}
+	return nil
+}
+
+func (a *Alerts) now() time.Time {
+	if a.clock == nil {
+		return time.Now()
+	}
+	return a.clock.Now()
+}
+
+// alertNumber reads the number v is or points to.
+func alertNumber(v any) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return 0, false
+		}
+		v = rv.Elem().Interface()
+	}
+	return flashNumber(v)
+}
+
+func alertFormat(n float64) string {
+	return strconv.FormatFloat(n, 'f', -1, 64)
+}
+
+// ============================================================================
+// AlertList
+// ============================================================================
+
+// AlertListC lists an Alerts' active alerts, most severe first, with the
+// recently resolved ones under them, and keys to acknowledge or mute the
+// selected one:
+//
+//	◆ CRITICAL  replica lag 42 > 30                    3m ago
+//	● WARNING   memory pressure: mem > 80 && swap > 0  muted
+//
+// It checks the rules each frame, and re-renders itself when a rule's For
+// or a mute runs out and as the ages change.
+type AlertListC struct {
+	ticking
+	alerts       *Alerts
+	cursor       int
+	showResolved int
+	styles       [3]Style // by severity
+	resolved     Style
+	acked        Style
+	selected     Style
+
+	declaredBindings []binding
+}
+
+// AlertList creates a list of alerts' alerts.
+func AlertList(alerts *Alerts) *AlertListC {
+	return &AlertListC{
+		alerts:       alerts,
+		showResolved: 5,
+		styles: [3]Style{
+			AlertInfo:     {FG: Cyan},
+			AlertWarning:  {FG: Yellow, Attr: AttrBold},
+			AlertCritical: {FG: Red, Attr: AttrBold},
+		},
+		resolved: Style{FG: Green},
+		acked:    Style{Attr: AttrDim},
+		selected: Style{Attr: AttrInverse},
+	}
+}
+
+// SeverityStyle sets the style of a severity's icon and label.
+func (l *AlertListC) SeverityStyle(s AlertSeverity, st Style) *AlertListC {
+	if s >= AlertInfo && s <= AlertCritical {
+		l.styles[s] = st
+	}
+	return l
+}
+
+// ShowResolved sets how many resolved alerts are listed under the active
+// ones, 5 by default.
+func (l *AlertListC) ShowResolved(n int) *AlertListC {
+	l.showResolved = max(n, 0)
+	return l
+}
+
+// SelectedStyle sets the style of the selected alert, inverse by default.
+func (l *AlertListC) SelectedStyle(s Style) *AlertListC {
+	l.selected = s
+	return l
+}
+
+// BindNav registers keys that move the selection down and up the active
+// alerts.
+func (l *AlertListC) BindNav(down, up string) *AlertListC {
+	l.declaredBindings = append(l.declaredBindings,
+		binding{pattern: down, handler: func() { l.cursor++ }},
+		binding{pattern: up, handler: func() { l.cursor = max(l.cursor-1, 0) }},
+	)
+	return l
+}
+
+// BindAck registers a key that acknowledges the selected alert.
+func (l *AlertListC) BindAck(key string) *AlertListC {
+	l.declaredBindings = append(l.declaredBindings, binding{pattern: key, handler: func() {
+		if a, ok := l.Selected(); ok {
+			l.alerts.Ack(a.Rule)
+		}
+	}})
+	return l
+}
+
+// BindMute registers a key that mutes the selected alert's rule for d, or
+// unmutes it if it's muted.
+func (l *AlertListC) BindMute(key string, d time.Duration) *AlertListC {
+	l.declaredBindings = append(l.declaredBindings, binding{pattern: key, handler: func() {
+		a, ok := l.Selected()
+		switch {
+		case !ok:
+		case a.Muted:
+			l.alerts.Unmute(a.Rule)
+		default:
+			l.alerts.Mute(a.Rule, d)
+		}
+	}})
+	return l
+}
+
+func (l *AlertListC) bindings() []binding { return l.declaredBindings }
+
+// Selected returns the selected active alert, if there are any.
+func (l *AlertListC) Selected() (Alert, bool) {
+	active := l.alerts.Active
+	if len(active) == 0 {
+		return Alert{}, false
+	}
+	l.cursor = min(l.cursor, len(active)-1)
+	return active[l.cursor], true
+}
+
+func (t *Template) compileAlertListC(l *AlertListC, parent int16, depth int) int16 {
+	t.collectUpdateHook(l)
+	t.collectClockUser(l)
+	t.collectBindings(l)
+	return t.compileCustom(Widget(l.measure, l.render), parent, depth)
+}
+
+// alertIcons mark alerts by severity.
+var alertIcons = [3]string{AlertInfo: "○", AlertWarning: "●", AlertCritical: "◆"}
+
+// alertRow is a line of an AlertList.
+type alertRow struct {
+	icon, label, message, note string
+	style                      Style // icon and label
+	dim                        bool
+}
+
+func (l *AlertListC) rows(now time.Time) (rows []alertRow, next time.Duration) {
+	for _, a := range l.alerts.Active {
+		age, n := relativeTime(now.Sub(a.Since), time.Minute)
+		next = minPositive(next, n)
+		note := age
+		switch {
+		case a.Muted:
+			note = "muted"
+		case a.Acked:
+			note = "acked · " + age
+		}
+		rows = append(rows, alertRow{alertIcons[a.Severity], strings.ToUpper(a.Severity.String()), a.Message, note, l.styles[a.Severity], a.Acked || a.Muted})
+	}
+	if len(rows) == 0 {
+		rows = append(rows, alertRow{message: "no active alerts", dim: true})
+	}
+	for _, a := range l.alerts.Resolved[:min(l.showResolved, len(l.alerts.Resolved))] {
+		age, n := relativeTime(now.Sub(a.Resolved), time.Minute)
+		next = minPositive(next, n)
+		rows = append(rows, alertRow{"✓", "OK", a.Message, age, l.resolved, true})
+	}
+	return rows, next
+}
+
+func minPositive(a, b time.Duration) time.Duration {
+	if a <= 0 || (b > 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+func (l *AlertListC) measure(availW int16) (w, h int16) {
+	l.alerts.clock = l.clock
+	if next := l.alerts.check(); next > 0 { // checked here so the height fits
+		l.after(next)
+	}
+	rows, _ := l.rows(l.now())
+	return availW, int16(len(rows))
+}
+
+func (l *AlertListC) render(buf *Buffer, x, y, w, h int16) {
+	rows, next := l.rows(l.now())
+	if next > 0 {
+		l.after(next)
+	}
+	const labelW = len("CRITICAL")
+	_, selected := l.Selected()
+	for i, r := range rows {
+		if i >= int(h) {
+			break
+		}
+		row, cx, width := int(y)+i, int(x), int(w)
+		text := Style{}
+		if r.dim {
+			text = l.acked
+		}
+		if r.icon != "" {
+			buf.WriteStringFast(cx, row, r.icon, r.style, width)
+			buf.WriteStringFast(cx+2, row, r.label, r.style, width-2)
+			cx += 2 + labelW + 2
+		}
+		msgW := int(x) + width - cx
+		if r.note != "" {
+			noteW := runewidth.StringWidth(r.note)
+			msgW -= noteW + 2
+			buf.WriteStringFast(int(x)+width-noteW, row, r.note, Style{Attr: AttrDim}, noteW)
+		}
+		buf.WriteStringFast(cx, row, TruncateEllipsis(r.message, max(msgW, 0)), text, max(msgW, 0))
+		if selected && i == l.cursor {
+			for cx := int(x); cx < int(x)+width; cx++ {
+				c := buf.Get(cx, row)
+				c.Style.Attr |= l.selected.Attr
+				if l.selected.BG != (Color{}) {
+					c.Style.BG = l.selected.BG
+				}
+				if c.Rune == 0 {
+					c.Rune = ' '
+				}
+				buf.SetFast(cx, row, c)
+			}
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package glyph
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAlerts(t *testing.T) {
+	clock := NewFakeClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	var lag int
+	var mem, swap float64
+	var state SourceState
+	raised, resolved := []string{}, []string{}
+
+	alerts := NewAlerts().SetClock(clock).
+		Bind("mem", &mem).Bind("swap", &swap).
+		OnRaise(func(a Alert) { raised = append(raised, a.Rule) }).
+		OnResolve(func(a Alert) { resolved = append(resolved, a.Rule) })
+	alerts.Above("lag", &lag, 30).Severity(AlertCritical).For(time.Minute)
+	alerts.When("down", func() bool { return state == SourceError }).Severity(AlertInfo)
+	if _, err := alerts.Expr("pressure", "mem > 80 && swap > 0"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := alerts.Expr("bad", "mem >"); err == nil {
+		t.Error("bad expression accepted")
+	}
+
+	lag, mem, state = 45, 90, SourceError
+	if next := alerts.check(); next != time.Minute {
+		t.Errorf("next check in %v, want the For", next)
+	}
+	if !reflect.DeepEqual(raised, []string{"down"}) || alerts.Unacked != 1 {
+		t.Fatalf("raised %q, unacked %d", raised, alerts.Unacked)
+	}
+
+	swap = 1
+	clock.Advance(time.Minute)
+	alerts.Check()
+	if !reflect.DeepEqual(raised, []string{"down", "lag", "pressure"}) {
+		t.Fatalf("raised %q", raised)
+	}
+	var order []string
+	for _, a := range alerts.Active {
+		order = append(order, a.Rule+": "+a.Message)
+	}
+	want := []string{"lag: lag 45 > 30", "pressure: pressure: mem > 80 && swap > 0", "down: down"}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("active = %q, want %q", order, want)
+	}
+
+	alerts.Ack("lag")
+	alerts.Mute("pressure", time.Hour)
+	if alerts.Unacked != 1 || !alerts.Active[0].Acked || !alerts.Active[1].Muted {
+		t.Errorf("after ack and mute: unacked %d, %+v", alerts.Unacked, alerts.Active)
+	}
+
+	// resolving and raising again starts afresh
+	mem = 50
+	alerts.Check()
+	mem = 95
+	alerts.Check()
+	if len(raised) != 3 || !reflect.DeepEqual(resolved, []string{"pressure"}) {
+		t.Errorf("muted rule raised: %q, resolved %q", raised, resolved)
+	}
+	clock.Advance(time.Hour)
+	alerts.Check()
+	if a := alerts.Active[1]; a.Rule != "pressure" || a.Muted || alerts.Unacked != 2 {
+		t.Errorf("mute didn't run out: %+v, unacked %d", a, alerts.Unacked)
+	}
+
+	lag = 10
+	alerts.Check()
+	if len(alerts.Resolved) != 2 || alerts.Resolved[0].Rule != "lag" || alerts.Resolved[0].Resolved != clock.Now() {
+		t.Errorf("resolved = %+v", alerts.Resolved)
+	}
+}
+
+func TestAlertList(t *testing.T) {
+	clock := NewFakeClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	var cpu, disk float64
+	alerts := NewAlerts()
+	alerts.Above("cpu", &cpu, 90).Severity(AlertCritical)
+	alerts.Below("disk free", &disk, 10).For(time.Minute)
+	list := AlertList(alerts).BindNav("j", "k").BindAck("a").BindMute("m", 0)
+	tmpl := Build(VBox(list))
+	tmpl.SetClock(clock)
+	renders := 0
+	for _, u := range tmpl.pendingUpdates {
+		*u = func() { renders++ }
+	}
+	frame := func() []string {
+		buf := NewBuffer(40, 3)
+		tmpl.Execute(buf, 40, 3)
+		return []string{buf.GetLine(0), buf.GetLine(1), buf.GetLine(2)}
+	}
+
+	if got := frame(); got[0] != "no active alerts" {
+		t.Errorf("empty = %q", got)
+	}
+
+	cpu, disk = 97, 5
+	frame()
+	clock.Advance(time.Minute) // the For runs out, and the list re-renders for it
+	if renders == 0 {
+		t.Error("no render when the For ran out")
+	}
+	got := frame()
+	want := []string{
+		"◆ CRITICAL  cpu 97 > 90           1m ago",
+		"● WARNING   disk free 5 < 10    just now",
+		"",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("list =\n%q\nwant\n%q", got, want)
+	}
+
+	buf := NewBuffer(40, 3)
+	tmpl.Execute(buf, 40, 3)
+	if buf.Get(0, 0).Style.Attr&AttrInverse == 0 || buf.Get(0, 0).Style.FG != Red {
+		t.Errorf("selected critical row style = %+v", buf.Get(0, 0).Style)
+	}
+
+	list.declaredBindings[0].handler.(func())() // down
+	list.declaredBindings[2].handler.(func())() // ack
+	list.declaredBindings[3].handler.(func())() // mute
+	if got := frame()[1]; got != "● WARNING   disk free 5 < 10       muted" || alerts.Unacked != 1 {
+		t.Errorf("muted row = %q, unacked %d", got, alerts.Unacked)
+	}
+	list.declaredBindings[3].handler.(func())() // unmute
+	if got := frame()[1]; got != "● WARNING   disk free…  acked · just now" {
+		t.Errorf("acked row = %q", got)
+	}
+
+	cpu = 50
+	if got := frame(); got[1] != "✓ OK        cpu 97 > 90         just now" {
+		t.Errorf("resolved row = %q", got)
+	}
+}
@@ -0,0 +1,196 @@
+package glyph
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// Alignment helpers measure text in terminal columns, the way the buffer
+// lays it out, rather than in bytes. len("●") is 3 but it takes one
+// column; "日本" takes four.
+
+// StringWidth returns the number of columns s takes when written with
+// WriteSpans or a Text component. Zero-width runes take a column.
+func StringWidth(s string) int {
+	w := 0
+	for _, r := range s {
+		w += max(runewidth.RuneWidth(r), 1)
+	}
+	return w
+}
+
+// Truncate cuts s to at most width columns. A double-width rune that
+// would straddle the limit is dropped, and so is a character that would
+// lose part of itself: an accent, a skin tone or the rest of an emoji
+// sequence.
+func Truncate(s string, width int) string {
+	w := 0
+	for i := 0; i < len(s); {
+		n := clusterLen(s[i:])
+		cw := StringWidth(s[i : i+n])
+		if w+cw > width {
+			return s[:i]
+		}
+		w += cw
+		i += n
+	}
+	return s
+}
+
+// TruncateEllipsis cuts s to at most width columns like Truncate, ending
+// it with "…" if anything was cut.
+func TruncateEllipsis(s string, width int) string {
+	if StringWidth(s) <= width {
+		return s
+	}
+	if width < 1 {
+		return ""
+	}
+	return Truncate(s, width-1) + "…"
+}
+
+// clusterLen returns the length in bytes of the character s starts with:
+// its first rune and any that attach to it, such as combining marks,
+// variation selectors and skin tones, the second half of a flag, or the
+// rest of a zero-width-joined emoji sequence.
+func clusterLen(s string) int {
+	first, i := utf8.DecodeRuneInString(s)
+	flag := isRegionalIndicator(first)
+	for i < len(s) {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == '\u200d': // the joiner takes the next rune with it
+			i += size
+			if i < len(s) {
+				_, size = utf8.DecodeRuneInString(s[i:])
+			} else {
+				size = 0
+			}
+		case flag && isRegionalIndicator(r):
+			flag = false
+		case !attaches(r):
+			return i
+		}
+		i += size
+	}
+	return i
+}
+
+// attaches reports whether r modifies the rune before it rather than
+// standing on its own.
+func attaches(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Me) ||
+		r >= 0xFE00 && r <= 0xFE0F || // variation selectors
+		r >= 0x1F3FB && r <= 0x1F3FF || // skin tones
+		r >= 0xE0020 && r <= 0xE007F // tags
+}
+
+func isRegionalIndicator(r rune) bool { return r >= 0x1F1E6 && r <= 0x1F1FF }
+
+// PadRight left-justifies s in width columns, truncating if it's wider.
+// The Pad functions treat a negative width, as status-bar arithmetic gives
+// on a narrow terminal, as zero.
+func PadRight(s string, width int) string {
+	width = max(width, 0)
+	s = Truncate(s, width)
+	return s + strings.Repeat(" ", width-StringWidth(s))
+}
+
+// PadLeft right-justifies s in width columns, truncating if it's wider.
+func PadLeft(s string, width int) string {
+	width = max(width, 0)
+	s = Truncate(s, width)
+	return strings.Repeat(" ", width-StringWidth(s)) + s
+}
+
+// PadCenter centres s in width columns, truncating if it's wider. Odd
+// padding goes on the right.
+func PadCenter(s string, width int) string {
+	width = max(width, 0)
+	s = Truncate(s, width)
+	gap := width - StringWidth(s)
+	return strings.Repeat(" ", gap/2) + s + strings.Repeat(" ", gap-gap/2)
+}
+
+// AlignDecimals pads numbers so their decimal points line up. Every
+// result has the same width, so they can go straight into a column:
+//
+//	AlignDecimals([]string{"3.14", "120", "-0.5"})
+//	// "  3.14"
+//	// "120   "
+//	// " -0.5 "
+func AlignDecimals(values []string) []string {
+	intW, fracW := 0, 0
+	for _, v := range values {
+		i, f := splitDecimal(v)
+		intW = max(intW, StringWidth(i))
+		fracW = max(fracW, StringWidth(f))
+	}
+	out := make([]string, len(values))
+	for n, v := range values {
+		i, f := splitDecimal(v)
+		out[n] = PadLeft(i, intW) + PadRight(f, fracW)
+	}
+	return out
+}
+
+// splitDecimal splits v before its decimal point; the point stays with
+// the fraction.
+func splitDecimal(v string) (string, string) {
+	if i := strings.LastIndexByte(v, '.'); i >= 0 {
+		return v[:i], v[i:]
+	}
+	return v, ""
+}
+
+// ExpandTabs replaces tabs with spaces up to the next multiple of
+// tabWidth columns. Columns restart after each newline.
+func ExpandTabs(s string, tabWidth int) string {
+	tabWidth = max(tabWidth, 1)
+	return expandTabsTo(s, func(col int) int {
+		return col + tabWidth - col%tabWidth
+	})
+}
+
+// ExpandTabStops replaces tabs with spaces up to the next of the given
+// column stops, which must be ascending. A tab past the last stop
+// becomes a single space.
+//
+//	ExpandTabStops("GET\t/api\t200", []int{8, 32})
+func ExpandTabStops(s string, stops []int) string {
+	return expandTabsTo(s, func(col int) int {
+		for _, stop := range stops {
+			if stop > col {
+				return stop
+			}
+		}
+		return col + 1
+	})
+}
+
+func expandTabsTo(s string, next func(col int) int) string {
+	if !strings.ContainsRune(s, '\t') {
+		return s
+	}
+	var b strings.Builder
+	b.Grow(len(s))
+	col := 0
+	for _, r := range s {
+		switch r {
+		case '\t':
+			to := next(col)
+			b.WriteString(strings.Repeat(" ", to-col))
+			col = to
+		case '\n':
+			b.WriteRune(r)
+			col = 0
+		default:
+			b.WriteRune(r)
+			col += max(runewidth.RuneWidth(r), 1)
+		}
+	}
+	return b.String()
+}
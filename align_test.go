@@ -0,0 +1,108 @@
+package glyph
+
+import "testing"
+
+func TestStringWidth(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"abc", 3},
+		{"●", 1},
+		{"日本", 4},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if got := StringWidth(tt.in); got != tt.want {
+			t.Errorf("StringWidth(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestPad(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(string, int) string
+		in   string
+		w    int
+		want string
+	}{
+		{"right", PadRight, "ok", 5, "ok   "},
+		{"right wide", PadRight, "日本", 6, "日本  "},
+		{"left", PadLeft, "●", 3, "  ●"},
+		{"left truncates", PadLeft, "abcdef", 3, "abc"},
+		{"center", PadCenter, "ab", 5, " ab  "},
+		{"straddle", PadRight, "日本", 3, "日 "},
+		{"right negative", PadRight, "ok", -2, ""},
+		{"left negative", PadLeft, "ok", -1, ""},
+		{"center negative", PadCenter, "ok", -3, ""},
+	}
+	for _, tt := range tests {
+		if got := tt.fn(tt.in, tt.w); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		in   string
+		w    int
+		want string
+	}{
+		{"hello", 3, "hel"},
+		{"日本", 3, "日"},
+		{"cafe\u0301!", 4, "caf"}, // the accent stays with its e
+		{"ok 👍🏽", 4, "ok "},       // and the skin tone with its thumb
+		{"👩\u200d💻 dev", 2, ""},   // a joined sequence goes whole
+		{"🇬🇧🇫🇷", 3, "🇬🇧"},         // as does a flag
+		{"👩\u200d💻 dev", 10, "👩\u200d💻 dev"},
+	}
+	for _, tt := range tests {
+		if got := Truncate(tt.in, tt.w); got != tt.want {
+			t.Errorf("Truncate(%q, %d) = %q, want %q", tt.in, tt.w, got, tt.want)
+		}
+	}
+
+	if got := TruncateEllipsis("status: ok", 7); got != "status…" {
+		t.Errorf("ellipsis %q", got)
+	}
+	if got := TruncateEllipsis("ok", 2); got != "ok" {
+		t.Errorf("fits %q", got)
+	}
+	if got := TruncateEllipsis("日本語", 4); got != "日…" {
+		t.Errorf("wide %q", got)
+	}
+}
+
+func TestAlignDecimals(t *testing.T) {
+	got := AlignDecimals([]string{"3.14", "120", "-0.5"})
+	want := []string{"  3.14", "120   ", " -0.5 "}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("row %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestExpandTabs(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"a\tb", "a   b"},
+		{"abcd\tb", "abcd    b"},
+		{"日\tx\n\ty", "日  x\n    y"},
+		{"none", "none"},
+	}
+	for _, tt := range tests {
+		if got := ExpandTabs(tt.in, 4); got != tt.want {
+			t.Errorf("ExpandTabs(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	got := ExpandTabStops("GET\t/api\t200\tx", []int{6, 12})
+	if want := "GET   /api  200 x"; got != want {
+		t.Errorf("ExpandTabStops = %q, want %q", got, want)
+	}
+}
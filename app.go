@@ -1,9 +1,12 @@
 package glyph
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/kungfusheep/riffkey"
@@ -38,6 +41,10 @@ type App struct {
 	input  *riffkey.Input
 	reader *riffkey.Reader
 
+	// Output stage (terminal by default)
+	backend Backend
+	frame   Frame // reused each render
+
 	// Template + BufferPool (for SetView single-view mode)
 	template *Template
 	pool     *BufferPool
@@ -75,7 +82,6 @@ type App struct {
 	// Inline mode
 	inline         bool
 	clearOnExit    bool
-	linesUsed      int
 	viewHeight     int16 // Height of the view for inline mode
 	nonInteractive bool  // True when running via RunNonInteractive
 
@@ -86,6 +92,64 @@ type App struct {
 	// SetView limit (for catching anti-patterns)
 	setViewCount int
 	setViewLimit int // 0 = unlimited
+
+	// Work posted from other goroutines, run before the next frame
+	postMu sync.Mutex
+	posted []func()
+
+	// Cancelled by Stop, and replaced by the next run
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// Last rendered template, unmounted when another replaces it
+	mounted *Template
+
+	// Shutdown hooks and the reason passed to them
+	onShutdown []func(ShutdownReason)
+	stopReason *ShutdownReason
+	signals    chan os.Signal
+
+	// Time source for animations and tickers; nil means SystemClock
+	clock Clock
+
+	// Command registry, made on first use
+	commands *Commands
+
+	// Development aids drawn over each frame
+	widthAudit    *widthAudit
+	layoutOverlay bool
+	inspector     *inspector
+	inspectorKey  string
+
+	// Input-to-frame timing, and whether it's drawn over each frame
+	latency        inputLatency
+	latencyOverlay bool
+
+	// Adaptive quality: the level in effect, the frame time it's kept
+	// within, and how recent frames have done against it
+	quality     atomic.Uint32
+	frameBudget time.Duration
+	overBudget  int       // frames over budget in a row
+	calmSince   time.Time // since when frames have taken under half the budget
+	onQuality   func(Quality)
+
+	bell bell // visual bell under way, and how the bell rings
+
+	// Terminal features, when SetCapabilities overrides detection
+	caps    Capabilities
+	capsSet bool
+
+	// Plugins in use, their hooks, and the loader their components join
+	plugins    []Plugin
+	hooks      []Hooks
+	viewLoader *ViewLoader
+
+	// Keys read on the input goroutine, waiting for the render goroutine to
+	// dispatch them (see BatchKeys)
+	batchKeys bool
+	keyMu     sync.Mutex
+	keyQueue  []riffkey.Key
+	keySpare  []riffkey.Key
 }
 
 // NewApp creates a new TUI application (fullscreen, alternate buffer).
@@ -97,17 +161,17 @@ func NewApp() (*App, error) {
 
 	router := riffkey.NewRouter()
 	input := riffkey.NewInput(router)
-	reader := riffkey.NewReader(os.Stdin).SetUTF8(true)
 
 	app := &App{
 		screen:     screen,
+		backend:    &terminalBackend{screen: screen},
 		router:     router,
 		input:      input,
-		reader:     reader,
 		renderChan: make(chan struct{}, 1),
 		jumpMode:   &JumpMode{},
 		jumpStyle:  DefaultJumpStyle,
 	}
+	app.reader = riffkeyReader(app.latency.stamp(os.Stdin))
 
 	return app, nil
 }
@@ -156,16 +220,20 @@ func (a *App) RunNonInteractive() error {
 		return fmt.Errorf("RunNonInteractive only works with inline apps")
 	}
 
+	a.startContext()
 	a.running = true
 	a.nonInteractive = true
+	defer a.shutdown()
+	defer a.watchSignals()()
 
 	// Clean up buffer pool on exit
 	if a.pool != nil {
 		defer a.pool.Stop()
 	}
+	defer a.unmountView()
 
 	// Enter inline mode (raw mode without alternate buffer)
-	if err := a.screen.EnterInlineMode(); err != nil {
+	if err := a.backend.Start(true); err != nil {
 		return err
 	}
 
@@ -183,7 +251,7 @@ func (a *App) RunNonInteractive() error {
 	}
 
 	// Clean up
-	a.screen.ExitInlineMode(a.linesUsed, a.clearOnExit)
+	a.backend.Stop(a.clearOnExit)
 	return nil
 }
 
@@ -225,7 +293,7 @@ func (a *App) SetView(view any) *App {
 	a.template.SetApp(a) // Link for jump mode support
 	a.wireBindings(a.template, a.router)
 	// Create buffer pool for async clearing (or reuse existing)
-	size := a.screen.Size()
+	size := a.backend.Size()
 	if a.pool == nil {
 		a.pool = NewBufferPool(size.Width, size.Height)
 	} else if a.pool.Width() != size.Width || a.pool.Height() != size.Height {
@@ -237,17 +305,7 @@ func (a *App) SetView(view any) *App {
 // wireBindings registers all declarative component bindings on the given router.
 func (a *App) wireBindings(tmpl *Template, router *riffkey.Router) {
 	for _, b := range tmpl.pendingBindings {
-		switch h := b.handler.(type) {
-		case func(riffkey.Match):
-			pattern := b.pattern
-			router.Handle(pattern, func(m riffkey.Match) { h(m); a.RequestRender() })
-		case func(any):
-			pattern := b.pattern
-			router.Handle(pattern, func(_ riffkey.Match) { h(nil); a.RequestRender() })
-		case func():
-			pattern := b.pattern
-			router.Handle(pattern, func(_ riffkey.Match) { h(); a.RequestRender() })
-		}
+		routeBinding(router, b, a.RequestRender)
 	}
 	// focus manager takes precedence over single pendingTIB
 	if fm := tmpl.pendingFocusManager; fm != nil {
@@ -301,8 +359,7 @@ func (a *App) wireBindings(tmpl *Template, router *riffkey.Router) {
 
 			if item.tib != nil {
 				// text input: route unmatched keys to TextHandler
-				th := fm.handlers[i]
-				sub.HandleUnmatched(th.HandleKey)
+				sub.HandleUnmatched(fm.handlers[i])
 				sub.NoCounts()
 			}
 
@@ -311,15 +368,40 @@ func (a *App) wireBindings(tmpl *Template, router *riffkey.Router) {
 
 		fm.initialPush()
 	} else if tmpl.pendingTIB != nil {
-		th := riffkey.NewTextHandler(tmpl.pendingTIB.value, tmpl.pendingTIB.cursor)
-		th.OnChange = tmpl.pendingTIB.onChange
-		router.HandleUnmatched(th.HandleKey)
+		router.HandleUnmatched(tmpl.pendingTIB.handler())
+		router.NoCounts()
+	} else if tmpl.pendingKeyHandler != nil {
+		router.HandleUnmatched(tmpl.pendingKeyHandler)
 		router.NoCounts()
 	}
 	// wire Log invalidation
 	for _, lv := range tmpl.pendingLogs {
 		lv.onUpdate = a.RequestRender
 	}
+	for _, hook := range tmpl.pendingUpdates {
+		if *hook == nil {
+			*hook = a.RequestRender
+		}
+	}
+	for _, m := range tmpl.pendingModals {
+		m.setModal(a.Push, a.Pop)
+	}
+	for _, s := range tmpl.pendingScopes {
+		s.attachRouter(router, a.Push)
+	}
+}
+
+// routeBinding registers a declarative binding on router, calling after
+// once its handler has run.
+func routeBinding(router *riffkey.Router, b binding, after func()) {
+	switch h := b.handler.(type) {
+	case func(riffkey.Match):
+		router.Handle(b.pattern, func(m riffkey.Match) { h(m); after() })
+	case func(any):
+		router.Handle(b.pattern, func(_ riffkey.Match) { h(nil); after() })
+	case func():
+		router.Handle(b.pattern, func(_ riffkey.Match) { h(); after() })
+	}
 }
 
 // ViewBuilder allows chaining Handle() calls after View().
@@ -348,7 +430,7 @@ func (a *App) View(name string, view any) *ViewBuilder {
 
 	// Create buffer pool if not exists (shared across all views)
 	if a.pool == nil {
-		size := a.screen.Size()
+		size := a.backend.Size()
 		a.pool = NewBufferPool(size.Width, size.Height)
 	}
 
@@ -493,6 +575,25 @@ func (a *App) HandleNamed(name, pattern string, handler func(riffkey.Match)) *Ap
 	return a
 }
 
+// Command registers a command in the app's registry; see Commands. Keys
+// given with Key are bound on the app's router and request a render once
+// the command has run.
+//
+//	app.Command("goto", "Go to a line", func(line int) { ... })
+//	app.Command("quit", "Quit", app.Stop).Key("<C-q>")
+func (a *App) Command(name, desc string, fn any) *Command {
+	return a.Commands().Add(name, desc, fn)
+}
+
+// Commands returns the app's command registry, for running lines of text
+// and for CommandPalette.
+func (a *App) Commands() *Commands {
+	if a.commands == nil {
+		a.commands = NewCommands().Bind(a.router, a.RequestRender)
+	}
+	return a.commands
+}
+
 // BindField routes unmatched keys to a text input field.
 func (a *App) BindField(f *InputState) *App {
 	a.router.TextInput(&f.Value, &f.Cursor)
@@ -589,6 +690,50 @@ func (a *App) RequestRender() {
 	}
 }
 
+// Post queues fn to run before the next frame, serialized with rendering,
+// and requests a render. Safe to call from any goroutine; use it to apply
+// results from background work to view state.
+func (a *App) Post(fn func()) {
+	a.postMu.Lock()
+	a.posted = append(a.posted, fn)
+	a.postMu.Unlock()
+	a.RequestRender()
+}
+
+// runPosted runs queued Post callbacks. Called with renderMu held.
+func (a *App) runPosted() {
+	a.postMu.Lock()
+	fns := a.posted
+	a.posted = nil
+	a.postMu.Unlock()
+	for _, fn := range fns {
+		fn()
+	}
+}
+
+// Context returns a context that is cancelled when the app stops.
+// Tie background work to it so it ends with the app. Each run has its
+// own; after a stop, Context returns the stopped run's until the next.
+func (a *App) Context() context.Context {
+	a.postMu.Lock()
+	defer a.postMu.Unlock()
+	if a.ctx == nil {
+		a.ctx, a.cancel = context.WithCancel(context.Background())
+	}
+	return a.ctx
+}
+
+// startContext returns the context for a run starting now, replacing the
+// last run's if it was cancelled.
+func (a *App) startContext() context.Context {
+	a.postMu.Lock()
+	defer a.postMu.Unlock()
+	if a.ctx == nil || a.ctx.Err() != nil {
+		a.ctx, a.cancel = context.WithCancel(context.Background())
+	}
+	return a.ctx
+}
+
 // RenderNow performs a render immediately without channel coordination.
 // Use this from dedicated update goroutines to avoid scheduler overhead.
 // The render is mutex-protected so it's safe to call concurrently.
@@ -600,6 +745,9 @@ func (a *App) RenderNow() {
 func (a *App) render() {
 	a.renderMu.Lock()
 	defer a.renderMu.Unlock()
+	start := time.Now()
+
+	a.runPosted()
 
 	var t0, t1 time.Time
 	if DebugTiming {
@@ -614,11 +762,16 @@ func (a *App) render() {
 	if a.onBeforeRender != nil {
 		a.onBeforeRender()
 	}
+	for _, h := range a.hooks {
+		if h.BeforeRender != nil {
+			h.BeforeRender()
+		}
+	}
 
 	// clear active layer before render (will be set if a layer has visible cursor)
 	a.activeLayer = nil
 
-	size := a.screen.Size()
+	size := a.backend.Size()
 	buf := a.pool.Current()
 
 	// For inline mode, use view height instead of terminal height
@@ -653,7 +806,39 @@ func (a *App) render() {
 			return // No view set
 		}
 	}
+	if a.mounted != activeTmpl {
+		if a.mounted != nil {
+			a.mounted.unmountAll()
+		}
+		a.mounted = activeTmpl
+	}
+	if activeTmpl.clock != a.clock {
+		activeTmpl.SetClock(a.clock)
+	}
+	if q := a.Quality(); activeTmpl.quality != q {
+		activeTmpl.SetQuality(q)
+	}
+	inspecting := a.inspector != nil && a.inspector.open
+	switch tracing := a.widthAudit != nil || a.layoutOverlay || inspecting; {
+	case tracing && activeTmpl.trace == nil:
+		activeTmpl.trace = &renderTrace{}
+	case !tracing:
+		activeTmpl.trace = nil
+	}
 	activeTmpl.Execute(buf, int16(size.Width), renderHeight)
+	a.bell.draw(buf, a.Clock().Now())
+	if a.widthAudit != nil {
+		a.widthAudit.check(activeTmpl, buf)
+	}
+	if a.layoutOverlay && activeTmpl.trace != nil {
+		drawLayoutOverlay(buf, activeTmpl.trace)
+	}
+	if inspecting && activeTmpl.trace != nil {
+		a.inspector.draw(buf, activeTmpl.trace)
+	}
+	if a.latencyOverlay {
+		drawLatencyOverlay(buf, a.latency.stats())
+	}
 
 	// for inline auto-size, use content height instead of full terminal height
 	if a.inline && a.viewHeight == 0 {
@@ -676,6 +861,11 @@ func (a *App) render() {
 	if a.onAfterRender != nil {
 		a.onAfterRender()
 	}
+	for _, h := range a.hooks {
+		if h.AfterRender != nil {
+			h.AfterRender()
+		}
+	}
 
 	if DebugTiming {
 		t1 = time.Now()
@@ -684,43 +874,25 @@ func (a *App) render() {
 		lastRenderTime = t1.Sub(t0)
 	}
 
-	// Copy to screen's back buffer for flush
-	a.copyToScreen(buf)
-
-	if a.inline {
-		// Inline mode: render at cursor position
-		a.linesUsed = a.screen.FlushInline(int(renderHeight), a.linesUsed)
-		a.pool.Swap() // Queue async clear
-	} else {
-		// Fullscreen mode
-		if DebugFullRedraw {
-			// Full redraw mode - for debugging rendering issues
-			a.screen.FlushFull()
-		} else {
-			// Normal diff-based update
-			a.screen.Flush() // Builds buffer but doesn't write
-		}
-		a.pool.Swap() // Queue async clear
-
-		// Add cursor ops to same buffer - one syscall for everything
-		if a.cursorColorSet {
-			a.screen.BufferCursorColor(a.cursorColor)
-		}
-		a.screen.BufferCursor(a.cursorX, a.cursorY, a.cursorVisible, a.cursorShape)
-		a.screen.FlushBuffer() // Single syscall for content + cursor
+	a.frame = Frame{
+		Buffer: buf,
+		Lines:  int(renderHeight),
+		Cursor: Cursor{X: a.cursorX, Y: a.cursorY, Style: a.cursorShape, Visible: a.cursorVisible},
+	}
+	if a.cursorColorSet {
+		a.frame.CursorColor = &a.cursorColor
 	}
+	a.backend.Present(&a.frame)
+	a.latency.framed()
+	now := time.Now()
+	a.frameTook(now.Sub(start), now)
+	a.pool.Swap() // Queue async clear
 
 	if DebugTiming {
 		lastFlushTime = time.Since(t1)
 	}
 }
 
-// copyToScreen copies pool buffer to screen's back buffer.
-func (a *App) copyToScreen(src *Buffer) {
-	dst := a.screen.Buffer()
-	dst.CopyFrom(src) // Fast bulk copy
-}
-
 // TimingString returns a formatted timing string.
 func TimingString() string {
 	return fmt.Sprintf("build:%v layout:%v render:%v flush:%v",
@@ -761,7 +933,10 @@ func (a *App) RunFrom(startView string) error {
 }
 
 func (a *App) run(startView string) error {
+	ctx := a.startContext()
 	a.running = true
+	defer a.shutdown()
+	defer a.watchSignals()()
 
 	// Set up starting view if specified
 	if startView != "" && a.viewTemplates != nil {
@@ -775,39 +950,38 @@ func (a *App) run(startView string) error {
 	if a.pool != nil {
 		defer a.pool.Stop()
 	}
+	defer a.unmountView()
 
 	// Enter raw mode (inline or fullscreen)
-	if a.inline {
-		if err := a.screen.EnterInlineMode(); err != nil {
-			return err
-		}
-		// Use closure so linesUsed is read at defer time, not now (when it's 0)
-		defer func() { a.screen.ExitInlineMode(a.linesUsed, a.clearOnExit) }()
-	} else {
-		if err := a.screen.EnterRawMode(); err != nil {
-			return err
-		}
-		defer a.screen.ExitRawMode()
+	if err := a.backend.Start(a.inline); err != nil {
+		return err
 	}
+	defer func() { a.backend.Stop(a.clearOnExit) }()
 
 	// Handle resize
-	go a.handleResize()
+	go a.handleResize(ctx, a.backend.ResizeChan())
 
 	// Handle async render requests (from timers, data updates, etc)
-	go a.handleRenderRequests()
+	go a.handleRenderRequests(ctx)
 
 	// Initial render
 	a.render()
 
 	// Run riffkey input loop
 	// afterDispatch is called after every key - perfect for rendering
-	err := a.input.Run(a.reader, func(handled bool) {
-		if !a.running {
-			return
-		}
-		// Always render after input (state may have changed)
-		a.render()
-	})
+	var err error
+	if a.batchKeys {
+		err = a.queueKeys()
+	} else {
+		err = a.input.Run(a.reader, func(handled bool) {
+			if !a.running {
+				return
+			}
+			a.latency.handle()
+			// Always render after input (state may have changed)
+			a.render()
+		})
+	}
 
 	// Normal termination via Stop() causes reader to return error
 	if !a.running {
@@ -820,14 +994,34 @@ func (a *App) run(startView string) error {
 	return err
 }
 
-// handleRenderRequests processes async render requests.
-func (a *App) handleRenderRequests() {
+func riffkeyReader(r io.Reader) *riffkey.Reader {
+	return riffkey.NewReader(r).SetUTF8(true)
+}
+
+// unmountView unmounts the Lifecycle children of the last rendered view.
+func (a *App) unmountView() {
+	a.renderMu.Lock()
+	defer a.renderMu.Unlock()
+	if a.mounted != nil {
+		a.mounted.unmountAll()
+		a.mounted = nil
+	}
+}
+
+// handleRenderRequests processes async render requests until ctx, the
+// run's, is cancelled.
+func (a *App) handleRenderRequests(ctx context.Context) {
 	for {
 		select {
+		case <-ctx.Done():
+			return
 		case <-a.renderChan:
 			if !a.running {
 				return
 			}
+			if a.dispatchQueued(); !a.running {
+				return
+			}
 			a.render()
 		}
 	}
@@ -835,9 +1029,25 @@ func (a *App) handleRenderRequests() {
 
 // Stop signals the application to stop.
 func (a *App) Stop() {
+	a.stop(ShutdownReason{})
+}
+
+func (a *App) stop(reason ShutdownReason) {
+	a.postMu.Lock()
+	if a.stopReason == nil {
+		a.stopReason = &reason
+	}
+	a.postMu.Unlock()
+
 	a.running = false
-	// Close stdin to unblock the input reader (not needed for non-interactive)
-	if !a.nonInteractive {
+	a.Context() // ensure cancel exists
+	a.cancel()
+	// Close the input to unblock the reader (not needed for non-interactive)
+	if in, ok := a.backend.(InputBackend); ok {
+		if c, ok := in.Input().(io.Closer); ok {
+			c.Close()
+		}
+	} else if !a.nonInteractive {
 		os.Stdin.Close()
 	}
 }
@@ -851,9 +1061,20 @@ func reopenStdin() {
 	}
 }
 
-// handleResize watches for terminal resize events.
-func (a *App) handleResize() {
-	for size := range a.screen.ResizeChan() {
+// handleResize watches for terminal resize events until ctx, the run's,
+// is cancelled. The channel belongs to the backend, which may outlive the
+// run, so it's left open.
+func (a *App) handleResize(ctx context.Context, resize <-chan Size) {
+	for {
+		var size Size
+		select {
+		case <-ctx.Done():
+			return
+		case size = <-resize:
+			if ctx.Err() != nil {
+				return // the next run starts at the current size anyway
+			}
+		}
 		// Resize the buffer pool to match new terminal dimensions
 		if a.pool != nil {
 			a.pool.Resize(size.Width, size.Height)
@@ -862,19 +1083,35 @@ func (a *App) handleResize() {
 		if a.onResize != nil {
 			a.onResize(size.Width, size.Height)
 		}
+		for _, h := range a.hooks {
+			if h.Resize != nil {
+				h.Resize(size.Width, size.Height)
+			}
+		}
 		a.RequestRender()
 	}
 }
 
 // Size returns the current screen size.
 func (a *App) Size() Size {
-	return a.screen.Size()
+	return a.backend.Size()
 }
 
 // =============================================================================
 // Jump Labels
 // =============================================================================
 
+// AuditWidths turns on a debug mode that checks every frame for cells
+// whose width won't match what the terminal draws, such as an emoji
+// written into a single cell, which push the rest of their row out of
+// line. The cells at fault are marked in white on red, and report, if
+// given, is called with the issues and the components that drew them
+// whenever they change. See AuditWidths for the check alone.
+func (a *App) AuditWidths(report func([]WidthIssue)) *App {
+	a.widthAudit = &widthAudit{report: report}
+	return a
+}
+
 // JumpKey registers a key pattern to trigger jump mode.
 // This is a convenience method that calls EnterJumpMode when the key is pressed.
 func (a *App) JumpKey(pattern string) *App {
@@ -0,0 +1,77 @@
+package glyph
+
+import "unsafe"
+
+// AsyncBoundaryC shows a placeholder in place of its child until a
+// DataSource has its first result, so a dashboard starts with the shape of
+// its panels rather than empty ones:
+//
+//	builds := PollJSON[[]Build](url, 15*time.Second)
+//	go builds.Run(app.Context(), app.Post)
+//
+//	AsyncBoundary(builds, List(&builds.Data).Render(buildRow)).
+//	    Loading(Skeleton(30, 24, 30, 18))
+//
+// If the first fetch fails it shows the error instead. Once the source has
+// been ready the child stays, showing the last good data through later
+// errors.
+type AsyncBoundaryC struct {
+	child   any
+	state   *SourceState
+	errText *string
+	loading any
+	failed  any
+
+	ready bool        // the source has had data
+	shown SourceState // which of child, loading and failed to draw
+}
+
+// AsyncBoundary wraps child so it shows once src has data.
+func AsyncBoundary[T any](src *DataSource[T], child any) *AsyncBoundaryC {
+	return &AsyncBoundaryC{
+		child:   child,
+		state:   &src.State,
+		errText: &src.ErrText,
+	}
+}
+
+// Loading sets what's shown until the first result. A one-row Skeleton by
+// default.
+func (b *AsyncBoundaryC) Loading(view any) *AsyncBoundaryC {
+	b.loading = view
+	return b
+}
+
+// Error sets what's shown when the source fails before it has had data.
+// The error text in red by default.
+func (b *AsyncBoundaryC) Error(view any) *AsyncBoundaryC {
+	b.failed = view
+	return b
+}
+
+// drain works out what to show, before each frame.
+func (b *AsyncBoundaryC) drain() {
+	if *b.state == SourceReady {
+		b.ready = true
+	}
+	b.shown = *b.state
+	if b.ready {
+		b.shown = SourceReady
+	}
+}
+
+func (t *Template) compileAsyncBoundaryC(b *AsyncBoundaryC, parent int16, depth int, elemBase unsafe.Pointer, elemSize uintptr) int16 {
+	t.addLive(b)
+	loading, failed := b.loading, b.failed
+	if loading == nil {
+		loading = Skeleton()
+	}
+	if failed == nil {
+		failed = Text(b.errText).FG(Red)
+	}
+	view := Switch(&b.shown).
+		Case(SourceLoading, loading).
+		Case(SourceError, failed).
+		Default(b.child)
+	return t.compile(view, parent, depth, elemBase, elemSize)
+}
@@ -0,0 +1,36 @@
+package glyph
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAsyncBoundary(t *testing.T) {
+	src := &DataSource[string]{}
+	tmpl := Build(AsyncBoundary(src, Text(&src.Data)).Loading(Text("loading")))
+	frame := func() string {
+		buf := NewBuffer(20, 1)
+		tmpl.Execute(buf, 20, 1)
+		return strings.TrimSpace(buf.GetLine(0))
+	}
+
+	if got := frame(); got != "loading" {
+		t.Errorf("before data = %q", got)
+	}
+
+	src.State, src.Err, src.ErrText = SourceError, errors.New("refused"), "refused"
+	if got := frame(); got != "refused" {
+		t.Errorf("failing first = %q", got)
+	}
+
+	src.State, src.Data = SourceReady, "3 builds"
+	if got := frame(); got != "3 builds" {
+		t.Errorf("ready = %q", got)
+	}
+
+	src.State, src.ErrText = SourceError, "timeout"
+	if got := frame(); got != "3 builds" {
+		t.Errorf("failing after data = %q, want the stale data", got)
+	}
+}
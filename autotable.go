@@ -17,6 +17,15 @@ type ColumnConfig struct {
 	hasAlign bool // true if explicitly set (vs type default)
 	format   func(any) string
 	style    func(any) Style
+	rules    []styleRule
+}
+
+// styleRule is a conditional format: the first rule whose test passes layers
+// its style over the cell's.
+type styleRule struct {
+	cell  func(v any) bool   // tests the cell value
+	row   func(row any) bool // tests the whole row, when cell is nil
+	style Style
 }
 
 // Align sets the column alignment.
@@ -28,6 +37,49 @@ func (c *ColumnConfig) Format(fn func(any) string) { c.format = fn }
 // Style sets a function that returns a per-cell style based on the field value.
 func (c *ColumnConfig) Style(fn func(any) Style) { c.style = fn }
 
+// When adds a conditional format: cells whose value passes test take s on
+// top of their usual style, so unset colours keep the row's. Rules are
+// tried in the order they were added and the first match wins.
+//
+//	c.When(func(v any) bool { return v.(float64) < 0 }, Style{FG: Red})
+func (c *ColumnConfig) When(test func(v any) bool, s Style) {
+	c.rules = append(c.rules, styleRule{cell: test, style: s})
+}
+
+// WhenRow is When with a test that sees the whole row, a pointer to the
+// struct, for formats that depend on another field.
+func (c *ColumnConfig) WhenRow(test func(row any) bool, s Style) {
+	c.rules = append(c.rules, styleRule{row: test, style: s})
+}
+
+// cellStyle resolves the style of a cell with value v in row, starting from
+// the row's style.
+func (c *ColumnConfig) cellStyle(base Style, v any, row reflect.Value) Style {
+	if c == nil {
+		return base
+	}
+	if c.style != nil {
+		base = c.style(v)
+	}
+	for _, r := range c.rules {
+		if r.cell != nil && !r.cell(v) {
+			continue
+		}
+		if r.cell == nil && !r.row(rowPointer(row)) {
+			continue
+		}
+		if r.style.FG.Mode != ColorDefault {
+			base.FG = r.style.FG
+		}
+		if r.style.BG.Mode != ColorDefault {
+			base.BG = r.style.BG
+		}
+		base.Attr |= r.style.Attr
+		break
+	}
+	return base
+}
+
 // ----------------------------------------------------------------------------
 // canned format presets
 // ----------------------------------------------------------------------------
@@ -155,6 +207,66 @@ func StyleThreshold(low, high float64, belowStyle, betweenStyle, aboveStyle Styl
 	}
 }
 
+// rowPointer returns a pointer to the struct in row, copying it when the
+// row isn't addressable (static slices).
+func rowPointer(row reflect.Value) any {
+	if row.CanAddr() {
+		return row.Addr().Interface()
+	}
+	p := reflect.New(row.Type())
+	p.Elem().Set(row)
+	return p.Interface()
+}
+
+// StyleWhen applies s to cells whose value passes test.
+func StyleWhen(test func(v any) bool, s Style) ColumnOption {
+	return func(c *ColumnConfig) { c.When(test, s) }
+}
+
+// StyleAbove applies s to numeric cells greater than limit.
+func StyleAbove(limit float64, s Style) ColumnOption {
+	return StyleWhen(func(v any) bool { return toFloat64(v) > limit }, s)
+}
+
+// StyleBelow applies s to numeric cells less than limit.
+func StyleBelow(limit float64, s Style) ColumnOption {
+	return StyleWhen(func(v any) bool { return toFloat64(v) < limit }, s)
+}
+
+// ----------------------------------------------------------------------------
+// canned aggregates
+// ----------------------------------------------------------------------------
+
+// Aggregator reduces a column's values to the one shown in a footer row.
+// The result goes through the column's Format like any other cell.
+type Aggregator func(values []any) any
+
+// Sum totals numeric values.
+func Sum() Aggregator {
+	return func(values []any) any {
+		total := 0.0
+		for _, v := range values {
+			total += toFloat64(v)
+		}
+		return total
+	}
+}
+
+// Avg averages numeric values.
+func Avg() Aggregator {
+	return func(values []any) any {
+		if len(values) == 0 {
+			return 0.0
+		}
+		return Sum()(values).(float64) / float64(len(values))
+	}
+}
+
+// Count counts values.
+func Count() Aggregator {
+	return func(values []any) any { return len(values) }
+}
+
 // ----------------------------------------------------------------------------
 // internal helpers
 // ----------------------------------------------------------------------------
@@ -283,13 +395,15 @@ type autoTableSortState struct {
 // autoTableScroll manages viewport scrolling for AutoTable.
 // renders all rows to an internal buffer, blits the visible window to screen.
 type autoTableScroll struct {
-	offset     int     // first visible data row
+	offset     int     // first visible line
+	lines      int     // lines drawn last frame, counting group headers and footers
 	maxVisible int     // viewport height in data rows (excludes header)
 	buf        *Buffer // internal buffer for all data rows (nil until first render)
 	bufW       int     // width of internal buffer (for resize detection)
 }
 
 func (s *autoTableScroll) scrollDown(n int, total int) {
+	total = max(total, s.lines)
 	s.offset += n
 	if max := total - s.maxVisible; max > 0 {
 		if s.offset > max {
@@ -323,6 +437,48 @@ func (s *autoTableScroll) clamp(total int) {
 	}
 }
 
+// autoTableHScroll tracks frozen columns and how far the rest have scrolled.
+// allocated once by FreezeColumns or BindHScroll, shared via pointer.
+type autoTableHScroll struct {
+	frozen int // leading columns that never scroll
+	offset int // scrollable columns hidden off the left edge
+}
+
+func (s *autoTableHScroll) clamp(scrollable int) {
+	s.offset = max(min(s.offset, scrollable-1), 0)
+}
+
+// autoTableGroups tracks the group-by field and which groups are collapsed.
+// allocated once by GroupBy, shared via pointer through value copies.
+type autoTableGroups struct {
+	field     string          // struct field to group rows by
+	collapsed map[string]bool // keyed by group value
+	all       bool            // collapse groups not yet seen too
+}
+
+// autoTableLine is one display line below the header.
+type autoTableLine struct {
+	kind  autoTableLineKind
+	row   int  // data row index, for data lines
+	group int  // index into the groups, for header and footer lines
+	alt   bool // alternate row styling, for data lines
+}
+
+type autoTableLineKind uint8
+
+const (
+	autoTableDataLine autoTableLineKind = iota
+	autoTableGroupLine
+	autoTableFooterLine
+)
+
+// autoTableGroup is a run of rows sharing a group-by value.
+type autoTableGroup struct {
+	key    string
+	rows   []int
+	footer []string // aggregate text per column, nil without aggregates
+}
+
 type AutoTableC struct {
 	data        any      // slice of structs
 	columns     []string // field names to display (nil = all exported)
@@ -338,6 +494,10 @@ type AutoTableC struct {
 
 	sortState        *autoTableSortState // nil unless Sortable called
 	scroll           *autoTableScroll    // nil unless Scrollable called
+	hscroll          *autoTableHScroll   // nil unless FreezeColumns or BindHScroll called
+	layout           *TableLayout        // nil unless Layout called
+	groups           *autoTableGroups    // nil unless GroupBy called
+	aggregates       map[string]Aggregator
 	declaredBindings []binding
 }
 
@@ -375,10 +535,17 @@ func (t AutoTableC) Headers(names ...string) AutoTableC {
 //	        c.Align(AlignCenter)
 //	        c.Format(func(v any) string { ... })
 //	    })
+//
+// Options given for the same column more than once apply in order, so
+// formats and conditional styles can be stacked.
 func (t AutoTableC) Column(name string, opt ColumnOption) AutoTableC {
 	if t.columnConfigs == nil {
 		t.columnConfigs = make(map[string]ColumnOption)
 	}
+	if prev, ok := t.columnConfigs[name]; ok {
+		next := opt
+		opt = func(c *ColumnConfig) { prev(c); next(c) }
+	}
 	t.columnConfigs[name] = opt
 	return t
 }
@@ -455,6 +622,33 @@ func (t AutoTableC) Scrollable(maxVisible int) AutoTableC {
 	return t
 }
 
+// FreezeColumns pins the first n columns to the left edge so horizontal
+// scrolling moves only the columns after them, with a rule marking the join.
+// Use it for tables wider than the screen:
+//
+//	AutoTable(&quotes).FreezeColumns(1).BindHScroll("h", "l")
+func (t AutoTableC) FreezeColumns(n int) AutoTableC {
+	if t.hscroll == nil {
+		t.hscroll = &autoTableHScroll{}
+	}
+	t.hscroll.frozen = n
+	return t
+}
+
+// BindHScroll registers key bindings for scrolling the unfrozen columns
+// left/right by one column.
+func (t AutoTableC) BindHScroll(left, right string) AutoTableC {
+	if t.hscroll == nil {
+		t.hscroll = &autoTableHScroll{}
+	}
+	hs := t.hscroll
+	t.declaredBindings = append(t.declaredBindings,
+		binding{pattern: left, handler: func() { hs.offset = max(hs.offset-1, 0) }},
+		binding{pattern: right, handler: func() { hs.offset++ }},
+	)
+	return t
+}
+
 // BindNav registers key bindings for scrolling down/up by one row.
 // the closures capture the scroll pointer and data pointer, reading the
 // current slice length at invocation time for correct clamping.
@@ -507,8 +701,189 @@ func (t AutoTableC) BindVimNav() AutoTableC {
 	return t.BindNav("j", "k").BindPageNav("<C-d>", "<C-u>")
 }
 
+// GroupBy groups rows by the value of a struct field, each group under a
+// header row showing its value and size. Groups appear in the order their
+// first row does, so they follow the table's sort. Group headers collapse
+// and expand through jump labels, or all at once with BindCollapseAll.
+// Only pointer-backed tables (*[]T) group.
+func (t AutoTableC) GroupBy(field string) AutoTableC {
+	if t.groups == nil {
+		t.groups = &autoTableGroups{collapsed: make(map[string]bool)}
+	}
+	t.groups.field = field
+	return t
+}
+
+// Aggregate adds a footer row totalling a column with fn, one per group when
+// grouped or one for the whole table otherwise:
+//
+//	AutoTable(&orders).GroupBy("Region").
+//	    Aggregate("Qty", Sum()).
+//	    Aggregate("Price", Avg()).
+//	    Column("Price", Currency("$", 2))
+func (t AutoTableC) Aggregate(field string, fn Aggregator) AutoTableC {
+	if t.aggregates == nil {
+		t.aggregates = make(map[string]Aggregator)
+	}
+	t.aggregates[field] = fn
+	return t
+}
+
+// BindCollapseAll registers key bindings that collapse and expand every group.
+func (t AutoTableC) BindCollapseAll(collapse, expand string) AutoTableC {
+	gs := t.groups
+	set := func(v bool) func() {
+		return func() {
+			if gs == nil {
+				return
+			}
+			gs.all = v
+			clear(gs.collapsed)
+		}
+	}
+	t.declaredBindings = append(t.declaredBindings,
+		binding{pattern: collapse, handler: set(true)},
+		binding{pattern: expand, handler: set(false)},
+	)
+	return t
+}
+
+// Layout lets the user rearrange the columns at runtime: select, resize,
+// reorder and hide them through the BindColumn* keys or a ColumnChooser.
+// The arrangement lives in l, so saving l keeps it across runs. Only
+// pointer-backed tables (*[]T) honour the layout.
+func (t AutoTableC) Layout(l *TableLayout) AutoTableC {
+	t.layout = l
+	return t
+}
+
+// BindColumnSelect registers key bindings for moving the column selection.
+func (t AutoTableC) BindColumnSelect(prev, next string) AutoTableC {
+	return t.bindLayout(prev, next, func(l *TableLayout, d int) { l.Select(d) })
+}
+
+// BindColumnResize registers key bindings for narrowing and widening the
+// selected column.
+func (t AutoTableC) BindColumnResize(narrower, wider string) AutoTableC {
+	return t.bindLayout(narrower, wider, func(l *TableLayout, d int) { l.Resize(d) })
+}
+
+// BindColumnMove registers key bindings for moving the selected column
+// left and right.
+func (t AutoTableC) BindColumnMove(left, right string) AutoTableC {
+	return t.bindLayout(left, right, func(l *TableLayout, d int) { l.Move(d) })
+}
+
+// BindColumnHide registers a key binding that hides the selected column.
+func (t AutoTableC) BindColumnHide(key string) AutoTableC {
+	l := t.layout
+	t.declaredBindings = append(t.declaredBindings,
+		binding{pattern: key, handler: func() {
+			if l != nil {
+				l.Hide()
+			}
+		}},
+	)
+	return t
+}
+
+func (t AutoTableC) bindLayout(back, forward string, fn func(*TableLayout, int)) AutoTableC {
+	l := t.layout
+	t.declaredBindings = append(t.declaredBindings,
+		binding{pattern: back, handler: func() {
+			if l != nil {
+				fn(l, -1)
+			}
+		}},
+		binding{pattern: forward, handler: func() {
+			if l != nil {
+				fn(l, 1)
+			}
+		}},
+	)
+	return t
+}
+
 func (t AutoTableC) bindings() []binding { return t.declaredBindings }
 
+func (g *autoTableGroups) isCollapsed(key string) bool { return g.all != g.collapsed[key] }
+
+// autoTableCellText formats a cell value with the column's Format, if any.
+func autoTableCellText(cfg *ColumnConfig, val any) string {
+	if cfg != nil && cfg.format != nil {
+		return cfg.format(val)
+	}
+	return fmt.Sprintf("%v", val)
+}
+
+// autoTableLines lays out the display lines below the header: the data rows,
+// split into groups with header rows when grouped, and footer rows when any
+// column aggregates.
+func autoTableLines(op *Op, rv reflect.Value) ([]autoTableLine, []autoTableGroup) {
+	nRows := rv.Len()
+	gs := op.AutoTableGroups
+	grouped := gs != nil && op.AutoTableGroupBy >= 0
+
+	var groups []autoTableGroup
+	if grouped {
+		index := make(map[string]int)
+		for i := 0; i < nRows; i++ {
+			key := fmt.Sprintf("%v", derefValue(rv.Index(i)).Field(op.AutoTableGroupBy).Interface())
+			g, ok := index[key]
+			if !ok {
+				g = len(groups)
+				index[key] = g
+				groups = append(groups, autoTableGroup{key: key})
+			}
+			groups[g].rows = append(groups[g].rows, i)
+		}
+	} else {
+		all := make([]int, nRows)
+		for i := range all {
+			all[i] = i
+		}
+		groups = []autoTableGroup{{rows: all}}
+	}
+
+	if op.AutoTableAggs != nil {
+		values := make([]any, 0, nRows)
+		for g := range groups {
+			footer := make([]string, len(op.AutoTableFields))
+			for j, fn := range op.AutoTableAggs {
+				if fn == nil {
+					continue
+				}
+				values = values[:0]
+				for _, i := range groups[g].rows {
+					values = append(values, derefValue(rv.Index(i)).Field(op.AutoTableFields[j]).Interface())
+				}
+				footer[j] = autoTableCellText(op.AutoTableColCfgs[j], fn(values))
+			}
+			groups[g].footer = footer
+		}
+	}
+
+	lines := make([]autoTableLine, 0, nRows+2*len(groups))
+	for g, grp := range groups {
+		if grouped {
+			lines = append(lines, autoTableLine{kind: autoTableGroupLine, group: g})
+			if gs.isCollapsed(grp.key) {
+				if grp.footer != nil {
+					lines = append(lines, autoTableLine{kind: autoTableFooterLine, group: g})
+				}
+				continue
+			}
+		}
+		for n, i := range grp.rows {
+			lines = append(lines, autoTableLine{kind: autoTableDataLine, row: i, group: g, alt: n%2 == 1})
+		}
+		if grp.footer != nil {
+			lines = append(lines, autoTableLine{kind: autoTableFooterLine, group: g})
+		}
+	}
+	return lines, groups
+}
+
 // autoTableSort sorts a *[]T slice in-place by the given struct field index.
 func autoTableSort(data any, fieldIdx int, asc bool) {
 	rv := reflect.ValueOf(data)
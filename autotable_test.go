@@ -1,6 +1,10 @@
 package glyph
 
-import "testing"
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
 
 func TestInsertCommas(t *testing.T) {
 	tests := []struct {
@@ -237,3 +241,158 @@ func TestToFloat64(t *testing.T) {
 		}
 	}
 }
+
+type quoteRow struct {
+	Sym  string
+	Bid  int
+	Ask  int
+	Last int
+}
+
+func TestAutoTableFreezeColumns(t *testing.T) {
+	rows := []quoteRow{{"AAPL", 101, 102, 103}}
+	tmpl := Build(VBox(AutoTable(&rows).FreezeColumns(1).BindHScroll("h", "l")))
+	frame := func() *Buffer {
+		buf := NewBuffer(16, 2)
+		tmpl.Execute(buf, 16, 2)
+		return buf
+	}
+
+	buf := frame()
+	if got := buf.GetLine(0); got != "Sym  │ Bid Ask L" {
+		t.Errorf("header = %q", got)
+	}
+	if got := buf.GetLine(1); got != "AAPL │ 101 102 1" {
+		t.Errorf("row = %q", got)
+	}
+
+	tmpl.pendingBindings[1].handler.(func())()
+	buf = frame()
+	if got := buf.GetLine(0); got != "Sym  │ Ask Last" {
+		t.Errorf("scrolled header = %q", got)
+	}
+	if got := buf.GetLine(1); got != "AAPL │ 102  103" {
+		t.Errorf("scrolled row = %q", got)
+	}
+
+	// scrolling stops with the last column still showing
+	for range 5 {
+		tmpl.pendingBindings[1].handler.(func())()
+	}
+	buf = frame()
+	if got := buf.GetLine(0); got != "Sym  │ Last" {
+		t.Errorf("end header = %q", got)
+	}
+}
+
+type orderRow struct {
+	Region string
+	Item   string
+	Qty    int
+}
+
+func TestAutoTableGroupBy(t *testing.T) {
+	rows := []orderRow{{"EU", "tea", 2}, {"US", "pie", 5}, {"EU", "jam", 3}}
+	table := AutoTable(&rows).Columns("Item", "Qty").
+		GroupBy("Region").
+		Aggregate("Qty", Sum()).
+		BindCollapseAll("zM", "zR")
+	tmpl := Build(VBox(table))
+	frame := func() []string {
+		buf := NewBuffer(12, 10)
+		tmpl.Execute(buf, 12, 10)
+		var lines []string
+		for y := 0; y < 10 && buf.GetLine(y) != ""; y++ {
+			lines = append(lines, buf.GetLine(y))
+		}
+		return lines
+	}
+
+	want := []string{
+		"Item    Qty",
+		"▼ EU (2)",
+		"tea       2",
+		"jam       3",
+		"          5",
+		"▼ US (1)",
+		"pie       5",
+		"          5",
+	}
+	if got := frame(); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("grouped =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// collapsed groups keep their footers
+	tmpl.pendingBindings[0].handler.(func())()
+	want = []string{
+		"Item    Qty",
+		"▶ EU (2)",
+		"          5",
+		"▶ US (1)",
+		"          5",
+	}
+	if got := frame(); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("collapsed =\n%s", strings.Join(got, "\n"))
+	}
+}
+
+func TestAutoTableAggregateFooter(t *testing.T) {
+	rows := []orderRow{{"EU", "tea", 2}, {"US", "pie", 4}}
+	tmpl := Build(VBox(AutoTable(&rows).Columns("Item", "Qty").
+		Aggregate("Item", Count()).
+		Aggregate("Qty", Avg())))
+	buf := NewBuffer(12, 4)
+	tmpl.Execute(buf, 12, 4)
+	if got := buf.GetLine(3); got != "2         3" {
+		t.Errorf("footer = %q", got)
+	}
+}
+
+func TestStyleRules(t *testing.T) {
+	cfg := &ColumnConfig{}
+	StyleBelow(0, Style{FG: Red})(cfg)
+	StyleAbove(100, Style{FG: Yellow, Attr: AttrBold})(cfg)
+
+	base := Style{BG: Blue}
+	if s := cfg.cellStyle(base, -2.5, reflect.Value{}); s.FG != Red || s.BG != Blue {
+		t.Errorf("below = %+v, want red on the row's blue", s)
+	}
+	if s := cfg.cellStyle(base, 150, reflect.Value{}); s.FG != Yellow || !s.Attr.Has(AttrBold) {
+		t.Errorf("above = %+v", s)
+	}
+	if s := cfg.cellStyle(base, 50, reflect.Value{}); s != base {
+		t.Errorf("between = %+v, want base", s)
+	}
+}
+
+type tickRow struct {
+	Sym    string
+	Change string
+	delta  float64
+}
+
+func TestAutoTableStyleRules(t *testing.T) {
+	rows := []tickRow{{"AAPL", "+1.20", 1.2}, {"MSFT", "-0.40", -0.4}, {"NVDA", "0.00", 0}}
+	up, down := Style{FG: Green}, Style{FG: Red}
+	tmpl := Build(VBox(AutoTable(&rows).
+		AltRowStyle(Style{BG: Blue}).
+		Column("Change", func(c *ColumnConfig) {
+			c.WhenRow(func(row any) bool { return row.(*tickRow).delta > 0 }, up)
+		}).
+		Column("Change", func(c *ColumnConfig) {
+			c.WhenRow(func(row any) bool { return row.(*tickRow).delta < 0 }, down)
+		})))
+	buf := NewBuffer(12, 4)
+	tmpl.Execute(buf, 12, 4)
+
+	cell := func(y int) Style { return buf.Get(5, y).Style }
+	if cell(1).FG != Green {
+		t.Errorf("rising = %+v", cell(1))
+	}
+	if cell(2).FG != Red || cell(2).BG != Blue {
+		t.Errorf("falling alt row = %+v, want red on blue", cell(2))
+	}
+	if cell(3).FG != (Color{}) {
+		t.Errorf("flat = %+v", cell(3))
+	}
+}
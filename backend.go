@@ -0,0 +1,382 @@
+package glyph
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Backend is the output stage of an App. It reports the display size and
+// presents each finished frame. The default backend writes ANSI to the
+// terminal; use App.SetBackend to render somewhere else: a test buffer,
+// an HTML export, or a remote client.
+type Backend interface {
+	// Size returns the current display size.
+	Size() Size
+
+	// ResizeChan delivers new sizes when the display changes.
+	ResizeChan() <-chan Size
+
+	// Start prepares the display. Inline apps render below the cursor
+	// instead of taking over the whole screen.
+	Start(inline bool) error
+
+	// Stop restores the display. clear asks inline backends to erase
+	// what they drew.
+	Stop(clear bool) error
+
+	// Present shows a finished frame. The frame's buffer is reused after
+	// Present returns, so copy anything kept.
+	Present(f *Frame) error
+}
+
+// InputBackend is a Backend that also supplies key input, replacing stdin.
+// If the reader is an io.Closer, App.Stop closes it.
+type InputBackend interface {
+	Backend
+	Input() io.Reader
+}
+
+// Frame is one rendered frame handed to a Backend.
+type Frame struct {
+	Buffer      *Buffer
+	Lines       int // rows in use, for inline apps
+	Cursor      Cursor
+	CursorColor *Color // nil leaves the terminal's cursor colour alone
+}
+
+// SetBackend replaces the terminal output with b. Call it before SetView
+// and Run.
+func (a *App) SetBackend(b Backend) *App {
+	a.backend = b
+	if in, ok := b.(InputBackend); ok {
+		a.reader = riffkeyReader(a.latency.stamp(in.Input()))
+	}
+	if a.pool != nil {
+		size := b.Size()
+		a.pool.Resize(size.Width, size.Height)
+	}
+	return a
+}
+
+// Backend returns the app's output backend.
+func (a *App) Backend() Backend {
+	return a.backend
+}
+
+// terminalBackend presents frames on a Screen.
+type terminalBackend struct {
+	screen *Screen
+	inline bool
+	lines  int // rows used by the last inline flush
+}
+
+func (t *terminalBackend) Size() Size              { return t.screen.Size() }
+func (t *terminalBackend) ResizeChan() <-chan Size { return t.screen.ResizeChan() }
+
+func (t *terminalBackend) Start(inline bool) error {
+	t.inline = inline
+	if inline {
+		return t.screen.EnterInlineMode()
+	}
+	return t.screen.EnterRawMode()
+}
+
+func (t *terminalBackend) Stop(clear bool) error {
+	if t.inline {
+		return t.screen.ExitInlineMode(t.lines, clear)
+	}
+	return t.screen.ExitRawMode()
+}
+
+func (t *terminalBackend) Present(f *Frame) error {
+	t.screen.Buffer().CopyFrom(f.Buffer) // fast bulk copy
+
+	if t.inline {
+		// Inline mode: render at cursor position
+		t.lines = t.screen.FlushInline(f.Lines, t.lines)
+		return nil
+	}
+
+	if DebugFullRedraw {
+		// Full redraw mode - for debugging rendering issues
+		t.screen.FlushFull()
+	} else {
+		// Normal diff-based update
+		t.screen.Flush() // Builds buffer but doesn't write
+	}
+
+	// Add cursor ops to same buffer - one syscall for everything
+	if f.CursorColor != nil {
+		t.screen.BufferCursorColor(*f.CursorColor)
+	}
+	t.screen.BufferCursor(f.Cursor.X, f.Cursor.Y, f.Cursor.Visible, f.Cursor.Style)
+	t.screen.FlushBuffer()
+	return nil
+}
+
+// BufferBackend keeps frames in memory instead of drawing them, and takes
+// key input from Type. Use it to drive a whole App from tests or to run
+// one headless.
+//
+//	be := NewBufferBackend(80, 24)
+//	app.SetBackend(be)
+//	go app.Run()
+//	be.Type("j")
+//	be.WaitFor(func(b *Buffer) bool { return strings.Contains(b.GetLine(1), "> two") }, time.Second)
+type BufferBackend struct {
+	mu      sync.Mutex
+	changed *sync.Cond
+	size    Size
+	frame   *Buffer
+	cursor  Cursor
+	frames  int
+
+	resize chan Size
+	in     *io.PipeReader
+	out    *io.PipeWriter
+}
+
+// NewBufferBackend creates an in-memory backend of the given size.
+func NewBufferBackend(width, height int) *BufferBackend {
+	in, out := io.Pipe()
+	b := &BufferBackend{
+		size:   Size{Width: width, Height: height},
+		frame:  NewBuffer(width, height),
+		resize: make(chan Size, 1),
+		in:     in,
+		out:    out,
+	}
+	b.changed = sync.NewCond(&b.mu)
+	return b
+}
+
+func (b *BufferBackend) Size() Size {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.size
+}
+
+func (b *BufferBackend) ResizeChan() <-chan Size { return b.resize }
+func (b *BufferBackend) Start(inline bool) error { return nil }
+func (b *BufferBackend) Input() io.Reader        { return b.in }
+
+func (b *BufferBackend) Stop(clear bool) error {
+	b.out.Close()
+	return nil
+}
+
+func (b *BufferBackend) Present(f *Frame) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.frame.Width() != f.Buffer.Width() || b.frame.Height() != f.Buffer.Height() {
+		b.frame = NewBuffer(f.Buffer.Width(), f.Buffer.Height())
+	}
+	b.frame.CopyFrom(f.Buffer)
+	b.cursor = f.Cursor
+	b.frames++
+	b.changed.Broadcast()
+	return nil
+}
+
+// Resize changes the display size, as a terminal resize would.
+func (b *BufferBackend) Resize(width, height int) {
+	b.mu.Lock()
+	b.size = Size{Width: width, Height: height}
+	b.mu.Unlock()
+	select {
+	case b.resize <- Size{Width: width, Height: height}:
+	default:
+	}
+}
+
+// Type sends raw terminal input, e.g. "j", "\r" or "\x1b[A".
+func (b *BufferBackend) Type(keys string) error {
+	_, err := io.WriteString(b.out, keys)
+	return err
+}
+
+// Frame returns a copy of the last presented frame.
+func (b *BufferBackend) Frame() *Buffer {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	out := NewBuffer(b.frame.Width(), b.frame.Height())
+	out.CopyFrom(b.frame)
+	return out
+}
+
+// Frames returns how many frames have been presented.
+func (b *BufferBackend) Frames() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.frames
+}
+
+// Cursor returns the cursor of the last presented frame.
+func (b *BufferBackend) Cursor() Cursor {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.cursor
+}
+
+// WaitFor blocks until a presented frame satisfies cond or timeout passes,
+// and reports whether it was satisfied.
+func (b *BufferBackend) WaitFor(cond func(*Buffer) bool, timeout time.Duration) bool {
+	expired := false
+	timer := time.AfterFunc(timeout, func() {
+		b.mu.Lock()
+		expired = true
+		b.changed.Broadcast()
+		b.mu.Unlock()
+	})
+	defer timer.Stop()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for !cond(b.frame) {
+		if expired {
+			return false
+		}
+		b.changed.Wait()
+	}
+	return true
+}
+
+// HTMLBackend renders frames in memory and writes the last one as a
+// standalone HTML page when the app stops.
+type HTMLBackend struct {
+	*BufferBackend
+	w io.Writer
+}
+
+// NewHTMLBackend creates a backend of the given size that exports to w.
+func NewHTMLBackend(w io.Writer, width, height int) *HTMLBackend {
+	return &HTMLBackend{BufferBackend: NewBufferBackend(width, height), w: w}
+}
+
+func (h *HTMLBackend) Stop(clear bool) error {
+	h.BufferBackend.Stop(clear)
+	fmt.Fprint(h.w, "<!DOCTYPE html>\n<html><head><meta charset=\"utf-8\"></head>\n<body style=\"background:#000;color:#ccc\">\n")
+	if err := WriteHTML(h.w, h.Frame()); err != nil {
+		return err
+	}
+	_, err := fmt.Fprint(h.w, "</body></html>\n")
+	return err
+}
+
+// WriteHTML writes buf as a <pre> block with inline styles.
+func WriteHTML(w io.Writer, buf *Buffer) error {
+	var sb strings.Builder
+	sb.WriteString("<pre style=\"font-family:monospace;line-height:1.2\">\n")
+	for y := 0; y < buf.Height(); y++ {
+		var run strings.Builder
+		var runStyle Style
+		flush := func() {
+			if run.Len() == 0 {
+				return
+			}
+			if css := styleCSS(runStyle); css != "" {
+				fmt.Fprintf(&sb, "<span style=\"%s\">%s</span>", css, html.EscapeString(run.String()))
+			} else {
+				sb.WriteString(html.EscapeString(run.String()))
+			}
+			run.Reset()
+		}
+		for x := 0; x < buf.Width(); x++ {
+			c := buf.Get(x, y)
+			r := c.Rune
+			if r == 0 {
+				r = ' '
+			}
+			if c.Style != runStyle {
+				flush()
+				runStyle = c.Style
+			}
+			run.WriteRune(r)
+		}
+		flush()
+		sb.WriteByte('\n')
+	}
+	sb.WriteString("</pre>\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// styleCSS converts a cell style to inline CSS.
+func styleCSS(s Style) string {
+	var parts []string
+	fg, bg := s.FG, s.BG
+	if s.Attr.Has(AttrInverse) {
+		fg, bg = bg, fg
+	}
+	if c, ok := colorCSS(fg); ok {
+		parts = append(parts, "color:"+c)
+	}
+	if c, ok := colorCSS(bg); ok {
+		parts = append(parts, "background:"+c)
+	}
+	if s.Attr.Has(AttrBold) {
+		parts = append(parts, "font-weight:bold")
+	}
+	if s.Attr.Has(AttrDim) {
+		parts = append(parts, "opacity:0.6")
+	}
+	if s.Attr.Has(AttrItalic) {
+		parts = append(parts, "font-style:italic")
+	}
+	var deco []string
+	if s.Attr.Has(AttrUnderline) {
+		deco = append(deco, "underline")
+	}
+	if s.Attr.Has(AttrStrikethrough) {
+		deco = append(deco, "line-through")
+	}
+	if len(deco) > 0 {
+		parts = append(parts, "text-decoration:"+strings.Join(deco, " "))
+	}
+	return strings.Join(parts, ";")
+}
+
+// ansi16 are the xterm defaults for the 16 basic colours.
+var ansi16 = [16]string{
+	"#000000", "#cd0000", "#00cd00", "#cdcd00", "#0000ee", "#cd00cd", "#00cdcd", "#e5e5e5",
+	"#7f7f7f", "#ff0000", "#00ff00", "#ffff00", "#5c5cff", "#ff00ff", "#00ffff", "#ffffff",
+}
+
+func colorCSS(c Color) (string, bool) {
+	switch c.Mode {
+	case ColorRGB:
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B), true
+	case Color16:
+		return ansi16[c.Index&15], true
+	case Color256:
+		r, g, b := palette256(c.Index)
+		return fmt.Sprintf("#%02x%02x%02x", r, g, b), true
+	}
+	return "", false
+}
+
+// palette256 returns the RGB value of an xterm 256-colour index.
+func palette256(i uint8) (r, g, b uint8) {
+	switch {
+	case i < 16:
+		var v uint32
+		fmt.Sscanf(ansi16[i], "#%06x", &v)
+		return uint8(v >> 16), uint8(v >> 8), uint8(v)
+	case i < 232:
+		i -= 16
+		level := func(n uint8) uint8 {
+			if n == 0 {
+				return 0
+			}
+			return 55 + n*40
+		}
+		return level(i / 36), level(i / 6 % 6), level(i % 6)
+	default:
+		v := 8 + (i-232)*10
+		return v, v, v
+	}
+}
@@ -0,0 +1,104 @@
+package glyph
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBufferBackendDrivesApp(t *testing.T) {
+	app, err := NewApp()
+	if err != nil {
+		t.Fatal(err)
+	}
+	be := NewBufferBackend(20, 5)
+	count := 0
+	label := "count 0"
+	app.SetBackend(be).SetView(VBox(Text(&label)))
+	app.Handle("j", func() {
+		count++
+		label = "count " + string(rune('0'+count))
+	})
+
+	done := make(chan error, 1)
+	go func() { done <- app.Run() }()
+
+	hasLine := func(want string) func(*Buffer) bool {
+		return func(b *Buffer) bool { return strings.TrimSpace(b.GetLine(0)) == want }
+	}
+	if !be.WaitFor(hasLine("count 0"), time.Second) {
+		t.Fatalf("initial frame = %q", be.Frame().GetLine(0))
+	}
+
+	be.Type("jj")
+	if !be.WaitFor(hasLine("count 2"), time.Second) {
+		t.Fatalf("after jj frame = %q", be.Frame().GetLine(0))
+	}
+	if be.Frames() < 2 {
+		t.Errorf("frames = %d, want at least 2", be.Frames())
+	}
+
+	app.Stop()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Run returned %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after Stop")
+	}
+}
+
+func TestBufferBackendResize(t *testing.T) {
+	be := NewBufferBackend(10, 2)
+	be.Resize(30, 4)
+	if got := be.Size(); got.Width != 30 || got.Height != 4 {
+		t.Errorf("Size = %+v, want 30x4", got)
+	}
+	select {
+	case s := <-be.ResizeChan():
+		if s.Width != 30 {
+			t.Errorf("resize event width = %d", s.Width)
+		}
+	default:
+		t.Error("expected a resize event")
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	buf := NewBuffer(12, 2)
+	buf.WriteString(0, 0, "ok", Style{FG: Green, Attr: AttrBold})
+	buf.WriteString(3, 0, "<tag>", Style{})
+
+	var out bytes.Buffer
+	if err := WriteHTML(&out, buf); err != nil {
+		t.Fatal(err)
+	}
+	html := out.String()
+	if !strings.Contains(html, `<span style="color:#00cd00;font-weight:bold">ok</span>`) {
+		t.Errorf("missing styled span:\n%s", html)
+	}
+	if !strings.Contains(html, "&lt;tag&gt;") {
+		t.Errorf("text not escaped:\n%s", html)
+	}
+}
+
+func TestPalette256(t *testing.T) {
+	tests := []struct {
+		i       uint8
+		r, g, b uint8
+	}{
+		{1, 0xcd, 0, 0},
+		{16, 0, 0, 0},
+		{196, 255, 0, 0},
+		{232, 8, 8, 8},
+		{255, 238, 238, 238},
+	}
+	for _, tt := range tests {
+		r, g, b := palette256(tt.i)
+		if r != tt.r || g != tt.g || b != tt.b {
+			t.Errorf("palette256(%d) = %d,%d,%d want %d,%d,%d", tt.i, r, g, b, tt.r, tt.g, tt.b)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package glyph
+
+import (
+	"strconv"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// BadgeC decorates a node with a small counter on its top-right corner,
+// the unread count on a tab or pending items on a menu entry. It reads a
+// bound int each frame and disappears at zero:
+//
+//	Badge(VBox.Border(BorderRounded).Title("Inbox")(inbox), &unread)
+//	Badge(Text("Alerts"), &alerts).After().Dot()
+//
+// The child is compiled on its own, so it can't bind to ForEach item
+// fields.
+type BadgeC struct {
+	child any
+	count *int
+	sub   *Template
+	style Style
+	max   int
+	dot   bool
+	after bool
+}
+
+// Badge wraps child with a counter bound to count.
+func Badge(child any, count *int) *BadgeC {
+	return &BadgeC{
+		child: child,
+		count: count,
+		style: Style{FG: BrightWhite, BG: Red, Attr: AttrBold},
+		max:   99,
+	}
+}
+
+// Dot shows a red dot rather than the number. Set Style after Dot to
+// recolour it.
+func (b *BadgeC) Dot() *BadgeC {
+	b.dot = true
+	b.style = Style{FG: Red}
+	return b
+}
+
+// Max caps the number shown: above it the badge reads "99+". 99 by default.
+func (b *BadgeC) Max(n int) *BadgeC {
+	b.max = n
+	return b
+}
+
+// After places the badge just past the child's right edge instead of over
+// its corner, for one-line children whose last characters matter.
+func (b *BadgeC) After() *BadgeC {
+	b.after = true
+	return b
+}
+
+// Style sets the badge style. White on red by default.
+func (b *BadgeC) Style(s Style) *BadgeC {
+	b.style = s
+	return b
+}
+
+func (t *Template) compileBadgeC(b *BadgeC, parent int16, depth int) int16 {
+	b.sub = newSubTemplate(b.child, nil, 0)
+	t.pendingBindings = append(t.pendingBindings, b.sub.pendingBindings...)
+	t.adoptLive(b.sub)
+	return t.compileCustom(Widget(b.measure, b.render), parent, depth)
+}
+
+// label returns the badge text and its style, or "" when there's nothing
+// to show.
+func (b *BadgeC) label() (string, Style) {
+	n := 0
+	if b.count != nil {
+		n = *b.count
+	}
+	if n <= 0 {
+		return "", b.style
+	}
+	if b.dot {
+		return "●", b.style
+	}
+	if b.max > 0 && n > b.max {
+		return strconv.Itoa(b.max) + "+", b.style
+	}
+	return strconv.Itoa(n), b.style
+}
+
+// labelWidth is the badge's drawn width: numbers get a space either side.
+func (b *BadgeC) labelWidth() int16 {
+	s, _ := b.label()
+	switch {
+	case s == "":
+		return 0
+	case b.dot:
+		return 1
+	}
+	return int16(runewidth.StringWidth(s)) + 2
+}
+
+func (b *BadgeC) measure(availW int16) (w, h int16) {
+	extra := int16(0)
+	if b.after {
+		extra = b.labelWidth()
+		if extra > 0 {
+			extra++ // gap
+		}
+	}
+	probe := availW < 0
+	if probe {
+		availW = subProbeWidth
+	}
+	b.sub.distributeWidths(max(availW-extra, 0), nil)
+	b.sub.layout(0)
+	if len(b.sub.geom) == 0 {
+		return extra, 1
+	}
+	w = b.sub.geom[0].W
+	if probe && w >= subProbeWidth-extra {
+		return -1, b.sub.Height() // the child fills
+	}
+	return w + extra, max(b.sub.Height(), 1)
+}
+
+func (b *BadgeC) render(buf *Buffer, x, y, w, h int16) {
+	bw := b.labelWidth()
+	childW := w
+	if b.after && bw > 0 {
+		childW = max(w-bw-1, 0)
+	}
+	b.sub.clipMaxY = y + h
+	b.sub.render(buf, x, y, childW)
+	if bw == 0 {
+		return
+	}
+
+	s, style := b.label()
+	if !b.dot {
+		s = " " + s + " "
+	}
+	bx := x + w - bw
+	if !b.after && len(b.sub.geom) > 0 {
+		// sit on the child's own corner when it's narrower than the slot,
+		// one in from the edge so a border keeps its corner
+		right := x + min(b.sub.geom[0].W, w)
+		bx = right - bw
+		if right-x > bw+1 {
+			bx--
+		}
+	}
+	buf.WriteStringFast(int(max(bx, x)), int(y), s, style, int(bw))
+}
@@ -0,0 +1,36 @@
+package glyph
+
+import "testing"
+
+func TestBadge(t *testing.T) {
+	n := 0
+	tmpl := Build(VBox(
+		HBox(Badge(VBox.Width(12).Border(BorderRounded)(Text("mail")), &n)),
+		HBox(Badge(Text("Alerts"), &n).After(), Text("|")),
+		HBox(Badge(Text("Tab"), &n).After().Dot(), Text("|")),
+	))
+	frame := func() []string {
+		buf := NewBuffer(20, 5)
+		tmpl.Execute(buf, 20, 5)
+		return []string{buf.GetLine(0), buf.GetLine(3), buf.GetLine(4)}
+	}
+
+	got := frame()
+	if got[0] != "╭──────────╮" || got[1] != "Alerts|" || got[2] != "Tab|" {
+		t.Errorf("zero count shows a badge: %q", got)
+	}
+
+	n = 3
+	got = frame()
+	if got[0] != "╭─────── 3 ╮" {
+		t.Errorf("corner = %q", got[0])
+	}
+	if got[1] != "Alerts  3 |" || got[2] != "Tab ●|" {
+		t.Errorf("after = %q", got[1:])
+	}
+
+	n = 150
+	if got := frame(); got[1] != "Alerts  99+ |" {
+		t.Errorf("capped = %q", got[1])
+	}
+}
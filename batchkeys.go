@@ -0,0 +1,55 @@
+package glyph
+
+// BatchKeys reads input on its own goroutine and queues it, so that a
+// burst of keys, such as a held arrow key, is handled in one go rather
+// than waiting for a frame per key.
+//
+// Normally each key is handled and a frame drawn before the next key is
+// read. Batched, the render goroutine handles every key queued since the
+// last frame and then draws one frame for them all, so the screen is never
+// more than a frame behind the keyboard. Layout itself still runs on the
+// render goroutine: a slow frame still delays the keys read during it,
+// it just doesn't add a frame per key on top.
+//
+// Key handlers still never run during layout: they, Post callbacks and
+// hooks all run on the render goroutine between frames, so the view's
+// bound values can't change under it. Other goroutines must keep changing
+// them through Post, or feed them through channels and atomics.
+func (a *App) BatchKeys(on bool) *App {
+	a.batchKeys = on
+	return a
+}
+
+// queueKeys reads keys for the render goroutine until input ends.
+func (a *App) queueKeys() error {
+	for {
+		k, err := a.reader.ReadKey()
+		if err != nil {
+			return err
+		}
+		a.keyMu.Lock()
+		a.keyQueue = append(a.keyQueue, k)
+		a.keyMu.Unlock()
+		a.RequestRender()
+	}
+}
+
+// dispatchQueued handles the keys read since the last frame, stopping
+// early if one stops the app.
+func (a *App) dispatchQueued() {
+	a.keyMu.Lock()
+	keys := a.keyQueue
+	a.keyQueue = a.keySpare[:0]
+	a.keyMu.Unlock()
+
+	for _, k := range keys {
+		if !a.running {
+			break
+		}
+		a.input.Dispatch(k)
+	}
+	if len(keys) > 0 {
+		a.latency.handle()
+	}
+	a.keySpare = keys[:0]
+}
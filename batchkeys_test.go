@@ -0,0 +1,51 @@
+package glyph
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBatchKeys(t *testing.T) {
+	app, _ := NewApp()
+	be := NewBufferBackend(20, 3)
+	app.SetBackend(be)
+	app.BatchKeys(true)
+
+	count := 0
+	status := ""
+	app.OnBeforeRender(func() { status = fmt.Sprintf("count %d", count) })
+	app.SetView(VBox(
+		Text(&status),
+		// a slow frame, so keys pile up behind it
+		Widget(func(availW int16) (int16, int16) { return availW, 1 },
+			func(*Buffer, int16, int16, int16, int16) { time.Sleep(20 * time.Millisecond) }),
+	))
+	app.Handle("j", func() { count++ })
+	app.Handle("q", app.Stop)
+
+	done := make(chan error, 1)
+	go func() { done <- app.Run() }()
+	if !be.WaitFor(func(b *Buffer) bool { return strings.HasPrefix(b.GetLine(0), "count 0") }, time.Second) {
+		t.Fatal("no first frame")
+	}
+	start := be.Frames()
+	be.Type(strings.Repeat("j", 20))
+	if !be.WaitFor(func(b *Buffer) bool { return strings.HasPrefix(b.GetLine(0), "count 20") }, 2*time.Second) {
+		t.Fatalf("keys lost: %q", be.Frame().GetLine(0))
+	}
+	if n := be.Frames() - start; n >= 20 {
+		t.Errorf("drew %d frames for a burst of 20 keys, want them batched", n)
+	}
+
+	be.Type("q")
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("q didn't stop the app")
+	}
+}
@@ -0,0 +1,109 @@
+package glyph
+
+import (
+	"sync"
+	"time"
+)
+
+// BellMode is how App.Bell gets attention.
+type BellMode uint8
+
+const (
+	BellSound BellMode = iota // the terminal's bell, BEL
+	BellFlash                 // a visual bell: the screen or a region flashes inverse
+	BellBoth                  // sound and flash
+	BellOff                   // nothing
+)
+
+func (m BellMode) String() string {
+	switch m {
+	case BellSound:
+		return "sound"
+	case BellFlash:
+		return "flash"
+	case BellBoth:
+		return "both"
+	case BellOff:
+		return "off"
+	}
+	return "unknown"
+}
+
+// bellFlash is how long a visual bell shows.
+const bellFlash = 150 * time.Millisecond
+
+// bell is a visual bell under way.
+type bell struct {
+	mu     sync.Mutex
+	mode   BellMode
+	region Rect // where to flash; empty for the whole screen
+	until  time.Time
+}
+
+// SetBell sets how Bell and BellAt get attention. BellSound by default.
+func (a *App) SetBell(mode BellMode) *App {
+	a.bell.mu.Lock()
+	a.bell.mode = mode
+	a.bell.mu.Unlock()
+	return a
+}
+
+// Bell gets the user's attention as SetBell says, flashing the whole screen
+// for a visual bell. Safe to call from any goroutine.
+func (a *App) Bell() {
+	a.BellAt(Rect{})
+}
+
+// BellAt rings the bell like Bell, but a visual bell flashes only r, such
+// as the field that refused a key:
+//
+//	if !valid(r) {
+//	    app.BellAt(fieldRect)
+//	    return
+//	}
+func (a *App) BellAt(r Rect) {
+	a.bell.mu.Lock()
+	mode := a.bell.mode
+	if mode == BellFlash || mode == BellBoth {
+		a.bell.region = r
+		a.bell.until = a.Clock().Now().Add(bellFlash)
+	}
+	a.bell.mu.Unlock()
+
+	if mode == BellSound || mode == BellBoth {
+		if t, ok := a.backend.(*terminalBackend); ok {
+			t.screen.Bell()
+		}
+	}
+	if mode != BellOff {
+		a.RequestRender()
+	}
+	if mode == BellFlash || mode == BellBoth {
+		// one more frame to clear the flash
+		a.Clock().AfterFunc(bellFlash, a.RequestRender)
+	}
+}
+
+// draw inverts the flashing region while a visual bell is under way.
+func (b *bell) draw(buf *Buffer, now time.Time) {
+	b.mu.Lock()
+	r, until := b.region, b.until
+	b.mu.Unlock()
+	if !now.Before(until) {
+		return
+	}
+	if r.W <= 0 || r.H <= 0 {
+		r = Rect{W: buf.Width(), H: buf.Height()}
+	}
+	buf.mapRect(r.X, r.Y, r.W, r.H, func(c Cell) Cell {
+		c.Style.Attr ^= AttrInverse
+		return c
+	})
+}
+
+// Bell queues the terminal's bell, sent with the next frame.
+func (s *Screen) Bell() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending = append(s.pending, '\a')
+}
@@ -0,0 +1,49 @@
+package glyph
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestVisualBell(t *testing.T) {
+	app, err := NewApp()
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := NewFakeClock(time.Time{})
+	be := NewBufferBackend(10, 2)
+	app.SetBackend(be).SetClock(clock).SetView(VBox(Text("name"), Text("email")))
+	app.SetBell(BellFlash)
+
+	app.BellAt(Rect{X: 0, Y: 1, W: 5, H: 1})
+	app.RenderNow()
+	if be.Frame().Get(0, 0).Style.Attr.Has(AttrInverse) {
+		t.Error("bell flashed outside its region")
+	}
+	if !be.Frame().Get(0, 1).Style.Attr.Has(AttrInverse) {
+		t.Error("bell region not flashed")
+	}
+
+	clock.Advance(bellFlash)
+	app.RenderNow()
+	if be.Frame().Get(0, 1).Style.Attr.Has(AttrInverse) {
+		t.Error("flash not cleared")
+	}
+
+	app.Bell()
+	app.RenderNow()
+	if !be.Frame().Get(9, 0).Style.Attr.Has(AttrInverse) {
+		t.Error("whole-screen bell not flashed")
+	}
+}
+
+func TestScreenBell(t *testing.T) {
+	var out bytes.Buffer
+	s := NewScreenSize(&out, 10, 2)
+	s.Bell()
+	s.FlushBuffer()
+	if got := out.String(); got != "\a" {
+		t.Errorf("got %q, want BEL", got)
+	}
+}
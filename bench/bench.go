@@ -0,0 +1,241 @@
+// Package bench holds glyph's benchmark scenarios as library code, so
+// rendering regressions show up in go test -bench rather than by eye.
+//
+// Each scenario builds a realistic view, mutates its state deterministically
+// between frames, and renders into a fixed-size fake terminal that
+// discards output but counts the bytes it would have written:
+//
+//	func BenchmarkDashboard(b *testing.B) { bench.Run(b, bench.Dashboard) }
+//
+// Run them all with:
+//
+//	go test -bench . github.com/kungfusheep/glyph/bench
+package bench
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/kungfusheep/glyph"
+)
+
+// Scenario is a reproducible rendering workload.
+type Scenario struct {
+	Name          string
+	Width, Height int
+
+	// Setup builds the view and returns it with a step function that
+	// advances its state by one frame. rng is seeded the same every run.
+	Setup func(rng *rand.Rand) (view any, step func(frame int))
+}
+
+// Scenarios lists the built-in workloads.
+func Scenarios() []Scenario {
+	return []Scenario{Dashboard, ScrollingLog, Grid}
+}
+
+// Terminal is a fake terminal of a fixed size. Frames go through the same
+// diff and flush path as a real one, with output counted and discarded.
+type Terminal struct {
+	screen *glyph.Screen
+	buf    *glyph.Buffer
+	out    counter
+	frames int
+}
+
+type counter struct{ n int64 }
+
+func (c *counter) Write(p []byte) (int, error) {
+	c.n += int64(len(p))
+	return len(p), nil
+}
+
+// NewTerminal creates a fake terminal of the given size.
+func NewTerminal(width, height int) *Terminal {
+	t := &Terminal{buf: glyph.NewBuffer(width, height)}
+	t.screen = glyph.NewScreenSize(&t.out, width, height)
+	return t
+}
+
+// Render executes tmpl and flushes the result, as App does for each frame.
+func (t *Terminal) Render(tmpl *glyph.Template) {
+	t.buf.ClearDirty()
+	tmpl.Execute(t.buf, int16(t.screen.Width()), int16(t.screen.Height()))
+	t.screen.Buffer().CopyFrom(t.buf)
+	t.screen.Flush()
+	t.screen.FlushBuffer()
+	t.frames++
+}
+
+// Buffer returns the last rendered frame.
+func (t *Terminal) Buffer() *glyph.Buffer { return t.buf }
+
+// Bytes returns the total bytes written so far.
+func (t *Terminal) Bytes() int64 { return t.out.n }
+
+// Frames returns how many frames have been rendered.
+func (t *Terminal) Frames() int { return t.frames }
+
+// Run benchmarks s, one frame per iteration, and reports the bytes each
+// frame would have sent to the terminal.
+func Run(b *testing.B, s Scenario) {
+	view, step := s.Setup(rand.New(rand.NewSource(1)))
+	tmpl := glyph.Build(view)
+	term := NewTerminal(s.Width, s.Height)
+	term.Render(tmpl) // first frame draws everything; measure steady state
+	start := term.Bytes()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		step(i)
+		term.Render(tmpl)
+	}
+	b.StopTimer()
+	b.ReportMetric(float64(term.Bytes()-start)/float64(b.N), "bytes/frame")
+}
+
+// labels are preformatted so steps don't allocate.
+var labels = func() [101]string {
+	var l [101]string
+	for i := range l {
+		l[i] = fmt.Sprintf("%3d", i)
+	}
+	return l
+}()
+
+type proc struct {
+	Name string
+	CPU  int
+	Load string
+}
+
+// Dashboard is a full-screen monitoring view: per-core meters, a memory
+// sparkline and a process table, all changing every frame.
+var Dashboard = Scenario{
+	Name:   "dashboard",
+	Width:  120,
+	Height: 40,
+	Setup: func(rng *rand.Rand) (any, func(int)) {
+		title := "glyph dashboard"
+		cores := make([]int, 16)
+		mem := make([]float64, 60)
+		procs := make([]proc, 25)
+		for i := range procs {
+			procs[i].Name = fmt.Sprintf("worker-%02d", i)
+		}
+
+		view := glyph.VBox(
+			glyph.Text(&title),
+			glyph.HBox(
+				glyph.VBox.Border(glyph.BorderRounded).Title("cpu").Grow(1)(
+					glyph.ForEach(&cores, func(c *int) any { return glyph.Progress(c).Width(40) }),
+				),
+				glyph.VBox.Border(glyph.BorderRounded).Title("memory").Grow(1)(
+					glyph.Sparkline(&mem).Width(56),
+				),
+			),
+			glyph.VBox.Border(glyph.BorderRounded).Title("processes")(
+				glyph.ForEach(&procs, func(p *proc) any {
+					return glyph.HBox.Gap(1)(
+						glyph.Text(&p.Name),
+						glyph.Text(&p.Load),
+						glyph.Progress(&p.CPU).Width(30),
+					)
+				}),
+			),
+		)
+
+		step := func(frame int) {
+			for i := range cores {
+				cores[i] = rng.Intn(101)
+			}
+			copy(mem, mem[1:])
+			mem[len(mem)-1] = rng.Float64() * 100
+			for i := range procs {
+				procs[i].CPU = rng.Intn(101)
+				procs[i].Load = labels[procs[i].CPU]
+			}
+		}
+		step(0)
+		return view, step
+	},
+}
+
+// ScrollingLog is a tailing log where every frame appends a line and the
+// whole visible window shifts up by one.
+var ScrollingLog = Scenario{
+	Name:   "scrolling-log",
+	Width:  120,
+	Height: 40,
+	Setup: func(rng *rand.Rand) (any, func(int)) {
+		levels := []string{"INFO ", "DEBUG", "WARN ", "ERROR"}
+		lines := make([]string, 512)
+		for i := range lines {
+			lines[i] = fmt.Sprintf("2024-01-01T00:00:%02d %s request %d handled in %dms path=/api/v1/items/%d",
+				i%60, levels[rng.Intn(len(levels))], i, rng.Intn(500), rng.Intn(10000))
+		}
+		window := make([]string, 39)
+		next := 0
+		status := "following"
+
+		view := glyph.VBox(
+			glyph.ForEach(&window, func(l *string) any { return glyph.Text(l) }),
+			glyph.Text(&status),
+		)
+
+		step := func(frame int) {
+			copy(window, window[1:])
+			window[len(window)-1] = lines[next%len(lines)]
+			next++
+		}
+		for range window {
+			step(0)
+		}
+		return view, step
+	},
+}
+
+const gridCols = 24
+
+type gridRow struct {
+	Cells [gridCols]string
+}
+
+// Grid is a dense table of numbers where a quarter of the cells change
+// every frame, like a market ticker.
+var Grid = Scenario{
+	Name:   "grid",
+	Width:  120,
+	Height: 40,
+	Setup: func(rng *rand.Rand) (any, func(int)) {
+		rows := make([]gridRow, 38)
+		for i := range rows {
+			for j := range rows[i].Cells {
+				rows[i].Cells[j] = labels[rng.Intn(101)]
+			}
+		}
+
+		view := glyph.VBox(
+			glyph.ForEach(&rows, func(row *gridRow) any {
+				cells := make([]any, gridCols)
+				for j := range cells {
+					cells[j] = glyph.Text(&row.Cells[j])
+				}
+				return glyph.HBox.Gap(2)(cells...)
+			}),
+		)
+
+		step := func(frame int) {
+			for i := range rows {
+				for j := range rows[i].Cells {
+					if rng.Intn(4) == 0 {
+						rows[i].Cells[j] = labels[rng.Intn(101)]
+					}
+				}
+			}
+		}
+		return view, step
+	},
+}
@@ -0,0 +1,51 @@
+package bench
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/kungfusheep/glyph"
+)
+
+func BenchmarkDashboard(b *testing.B)    { Run(b, Dashboard) }
+func BenchmarkScrollingLog(b *testing.B) { Run(b, ScrollingLog) }
+func BenchmarkGrid(b *testing.B)         { Run(b, Grid) }
+
+func TestScenariosRender(t *testing.T) {
+	for _, s := range Scenarios() {
+		t.Run(s.Name, func(t *testing.T) {
+			view, step := s.Setup(rand.New(rand.NewSource(1)))
+			tmpl := glyph.Build(view)
+			term := NewTerminal(s.Width, s.Height)
+			term.Render(tmpl)
+			first := term.Bytes()
+			if first == 0 || strings.TrimSpace(term.Buffer().GetLine(0)) == "" {
+				t.Fatalf("first frame empty (%d bytes)", first)
+			}
+			step(1)
+			term.Render(tmpl)
+			if term.Bytes() == first {
+				t.Error("step changed nothing on screen")
+			}
+		})
+	}
+}
+
+func TestScenariosReproducible(t *testing.T) {
+	render := func(s Scenario) string {
+		view, step := s.Setup(rand.New(rand.NewSource(1)))
+		tmpl := glyph.Build(view)
+		term := NewTerminal(s.Width, s.Height)
+		for i := 0; i < 3; i++ {
+			step(i)
+			term.Render(tmpl)
+		}
+		return term.Buffer().String()
+	}
+	for _, s := range Scenarios() {
+		if render(s) != render(s) {
+			t.Errorf("%s: frames differ between runs", s.Name)
+		}
+	}
+}
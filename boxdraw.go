@@ -0,0 +1,120 @@
+package glyph
+
+// Box-drawing characters are described by their four arms (up, right,
+// down, left), each none, light, heavy or double. Joining two characters
+// overlays their arms and picks the character that draws the result, so
+// a │ drawn over a ─ becomes ┼ and a ┐ drawn over a ┌ becomes ┬.
+
+type boxArms [4]uint8 // up, right, down, left; 0 none, 1 light, 2 heavy, 3 double
+
+var (
+	runeArms = map[rune]boxArms{}
+	armsRune = map[boxArms]rune{}
+)
+
+func init() {
+	for _, e := range boxTable {
+		var a boxArms
+		for i := range a {
+			a[i] = e.arms[i] - '0'
+		}
+		runeArms[e.r] = a
+		if _, ok := armsRune[a]; !ok {
+			armsRune[a] = e.r // square corners win over the arcs listed after them
+		}
+	}
+}
+
+// JoinRunes returns the character for over drawn on top of under. When
+// both are box-drawing characters their lines are joined into a junction;
+// otherwise, or when no single character draws the join (heavy meeting
+// double, say), over is returned unchanged.
+func JoinRunes(under, over rune) rune {
+	u, ok := runeArms[under]
+	if !ok {
+		return over
+	}
+	o, ok := runeArms[over]
+	if !ok {
+		return over
+	}
+	j := u
+	for i, w := range o {
+		if w != 0 {
+			j[i] = w
+		}
+	}
+	if j == o {
+		return over // keeps rounded corners rounded
+	}
+	if r, ok := armsRune[j]; ok {
+		return r
+	}
+	return over
+}
+
+// JoinCell sets the cell at (x, y) to r, joining it with any box-drawing
+// character already there.
+func (b *Buffer) JoinCell(x, y int, r rune, style Style) {
+	if !b.InBounds(x, y) {
+		return
+	}
+	b.Set(x, y, NewCell(JoinRunes(b.Get(x, y).Rune, r), style))
+}
+
+// JoinHLine draws a horizontal line of r that joins any lines it crosses
+// or touches.
+func (b *Buffer) JoinHLine(x, y, length int, r rune, style Style) {
+	for i := range length {
+		b.JoinCell(x+i, y, r, style)
+	}
+}
+
+// JoinVLine draws a vertical line of r that joins any lines it crosses
+// or touches.
+func (b *Buffer) JoinVLine(x, y, length int, r rune, style Style) {
+	for i := range length {
+		b.JoinCell(x, y+i, r, style)
+	}
+}
+
+// DrawBox is DrawBorder for boxes that share edges: where the border
+// lands on existing lines the two are joined with the right junction
+// characters instead of one overwriting the other.
+//
+//	buf.DrawBox(0, 0, 10, 5, BorderSingle, style)
+//	buf.DrawBox(9, 0, 10, 5, BorderSingle, style) // shared edge gets ┬ and ┴
+func (b *Buffer) DrawBox(x, y, width, height int, border BorderStyle, style Style) {
+	if width < 2 || height < 2 {
+		return
+	}
+	b.JoinHLine(x+1, y, width-2, border.Horizontal, style)
+	b.JoinHLine(x+1, y+height-1, width-2, border.Horizontal, style)
+	b.JoinVLine(x, y+1, height-2, border.Vertical, style)
+	b.JoinVLine(x+width-1, y+1, height-2, border.Vertical, style)
+	b.JoinCell(x, y, border.TopLeft, style)
+	b.JoinCell(x+width-1, y, border.TopRight, style)
+	b.JoinCell(x, y+height-1, border.BottomLeft, style)
+	b.JoinCell(x+width-1, y+height-1, border.BottomRight, style)
+}
+
+var boxTable = []struct {
+	r    rune
+	arms string
+}{
+	{'─', "0101"}, {'━', "0202"}, {'│', "1010"}, {'┃', "2020"}, {'┌', "0110"}, {'┍', "0210"}, {'┎', "0120"}, {'┏', "0220"},
+	{'┐', "0011"}, {'┑', "0012"}, {'┒', "0021"}, {'┓', "0022"}, {'└', "1100"}, {'┕', "1200"}, {'┖', "2100"}, {'┗', "2200"},
+	{'┘', "1001"}, {'┙', "1002"}, {'┚', "2001"}, {'┛', "2002"}, {'├', "1110"}, {'┝', "1210"}, {'┞', "2110"}, {'┟', "1120"},
+	{'┠', "2120"}, {'┡', "2210"}, {'┢', "1220"}, {'┣', "2220"}, {'┤', "1011"}, {'┥', "1012"}, {'┦', "2011"}, {'┧', "1021"},
+	{'┨', "2021"}, {'┩', "2012"}, {'┪', "1022"}, {'┫', "2022"}, {'┬', "0111"}, {'┭', "0112"}, {'┮', "0211"}, {'┯', "0212"},
+	{'┰', "0121"}, {'┱', "0122"}, {'┲', "0221"}, {'┳', "0222"}, {'┴', "1101"}, {'┵', "1102"}, {'┶', "1201"}, {'┷', "1202"},
+	{'┸', "2101"}, {'┹', "2102"}, {'┺', "2201"}, {'┻', "2202"}, {'┼', "1111"}, {'┽', "1112"}, {'┾', "1211"}, {'┿', "1212"},
+	{'╀', "2111"}, {'╁', "1121"}, {'╂', "2121"}, {'╃', "2112"}, {'╄', "2211"}, {'╅', "1122"}, {'╆', "1221"}, {'╇', "2212"},
+	{'╈', "1222"}, {'╉', "2122"}, {'╊', "2221"}, {'╋', "2222"}, {'═', "0303"}, {'║', "3030"}, {'╒', "0310"}, {'╓', "0130"},
+	{'╔', "0330"}, {'╕', "0013"}, {'╖', "0031"}, {'╗', "0033"}, {'╘', "1300"}, {'╙', "3100"}, {'╚', "3300"}, {'╛', "1003"},
+	{'╜', "3001"}, {'╝', "3003"}, {'╞', "1310"}, {'╟', "3130"}, {'╠', "3330"}, {'╡', "1013"}, {'╢', "3031"}, {'╣', "3033"},
+	{'╤', "0313"}, {'╥', "0131"}, {'╦', "0333"}, {'╧', "1303"}, {'╨', "3101"}, {'╩', "3303"}, {'╪', "1313"}, {'╫', "3131"},
+	{'╬', "3333"}, {'╭', "0110"}, {'╮', "0011"}, {'╯', "1001"}, {'╰', "1100"}, {'╴', "0001"}, {'╵', "1000"}, {'╶', "0100"},
+	{'╷', "0010"}, {'╸', "0002"}, {'╹', "2000"}, {'╺', "0200"}, {'╻', "0020"}, {'╼', "0201"}, {'╽', "1020"}, {'╾', "0102"},
+	{'╿', "2010"},
+}
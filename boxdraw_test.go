@@ -0,0 +1,65 @@
+package glyph
+
+import "testing"
+
+func TestJoinRunes(t *testing.T) {
+	tests := []struct {
+		under, over, want rune
+	}{
+		{'─', '│', '┼'},
+		{'┐', '┌', '┬'},
+		{'┘', '└', '┴'},
+		{'│', '┌', '├'},
+		{'═', '║', '╬'},
+		{'│', '═', '╪'},
+		{'╭', '─', '┬'},
+		{' ', '╭', '╭'},
+		{'x', '│', '│'},
+		{'│', 'x', 'x'},
+		{'━', '║', '║'}, // heavy meeting double has no junction
+	}
+	for _, tt := range tests {
+		if got := JoinRunes(tt.under, tt.over); got != tt.want {
+			t.Errorf("JoinRunes(%q, %q) = %q, want %q", tt.under, tt.over, got, tt.want)
+		}
+	}
+}
+
+func TestDrawBoxSharedEdge(t *testing.T) {
+	buf := NewBuffer(7, 3)
+	buf.DrawBox(0, 0, 4, 3, BorderSingle, Style{})
+	buf.DrawBox(3, 0, 4, 3, BorderSingle, Style{})
+	want := []string{
+		"┌──┬──┐",
+		"│  │  │",
+		"└──┴──┘",
+	}
+	for y, line := range want {
+		if got := buf.GetLine(y); got != line {
+			t.Errorf("line %d = %q, want %q", y, got, line)
+		}
+	}
+}
+
+func TestJoinBorders(t *testing.T) {
+	buf := NewBuffer(12, 5)
+	Build(HBox.JoinBorders()(
+		VBox.Border(BorderSingle).Grow(1)(Text("a")),
+		VBox.JoinBorders().Grow(1)(
+			VBox.Border(BorderSingle)(Text("b")),
+			VBox.Border(BorderSingle)(Text("c")),
+		),
+	)).Execute(buf, 12, 5)
+	want := []string{
+		"┌────┬─────┐",
+		"│a   │b    │",
+		"│    ├─────┤",
+		"│    │c    │",
+		"└────┴─────┘",
+	}
+	for y, line := range want {
+		if got := buf.GetLine(y); got != line {
+			t.Errorf("line %d = %q, want %q", y, got, line)
+		}
+	}
+}
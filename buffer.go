@@ -19,6 +19,10 @@ type Buffer struct {
 	// Row-level dirty tracking for efficient flush
 	dirtyRows []bool
 	allDirty  bool // true after Clear() - all rows need checking
+
+	// Regions kept as they were when protected, and their cells then
+	protected      []Rect
+	protectedCells []Cell
 }
 
 // emptyBufferCache is a pre-filled buffer of empty cells for fast clearing via copy()
@@ -297,6 +301,13 @@ var sparklineChars = []rune{'▁', '▂', '▃', '▄', '▅', '▆', '▇', '
 
 // WriteSparkline writes a sparkline chart using Unicode block characters.
 func (b *Buffer) WriteSparkline(x, y int, values []float64, width int, min, max float64, style Style) {
+	b.writeSparkline(x, y, values, width, min, max, style, false)
+}
+
+// writeSparkline writes a sparkline, coarsely if asked: a bar per two
+// cells and four heights instead of eight, so fewer cells change from one
+// frame to the next.
+func (b *Buffer) writeSparkline(x, y int, values []float64, width int, min, max float64, style Style, coarse bool) {
 	if y < 0 || y >= b.height || len(values) == 0 {
 		return
 	}
@@ -329,7 +340,11 @@ func (b *Buffer) WriteSparkline(x, y int, values []float64, width int, min, max
 
 	for i := 0; i < width && x+i < b.width; i++ {
 		// Map position to data index (handles width != len(values))
-		dataIdx := i * dataLen / width
+		col := i
+		if coarse {
+			col &^= 1
+		}
+		dataIdx := col * dataLen / width
 		if dataIdx >= dataLen {
 			dataIdx = dataLen - 1
 		}
@@ -345,6 +360,9 @@ func (b *Buffer) WriteSparkline(x, y int, values []float64, width int, min, max
 		if charIdx > 7 {
 			charIdx = 7
 		}
+		if coarse {
+			charIdx |= 1
+		}
 
 		if x+i >= 0 {
 			b.cells[base+x+i] = Cell{Rune: sparklineChars[charIdx], Style: style}
@@ -1040,6 +1058,29 @@ func (b *Buffer) StringTrimmed() string {
 // width, height: size of region to copy
 // Copies row-by-row using copy().
 func (b *Buffer) Blit(src *Buffer, srcX, srcY, dstX, dstY, width, height int) {
+	srcX, srcY, dstX, dstY, width, height = b.clipBlit(src, srcX, srcY, dstX, dstY, width, height)
+
+	// Nothing to copy
+	if width <= 0 || height <= 0 {
+		return
+	}
+
+	// row-by-row copy
+	for y := 0; y < height; y++ {
+		srcStart := (srcY+y)*src.width + srcX
+		dstStart := (dstY+y)*b.width + dstX
+		copy(b.cells[dstStart:dstStart+width], src.cells[srcStart:srcStart+width])
+		b.dirtyRows[dstY+y] = true
+	}
+
+	// Update dirty tracking
+	if dstY+height-1 > b.dirtyMaxY {
+		b.dirtyMaxY = dstY + height - 1
+	}
+}
+
+// clipBlit clips a region copied from src to the bounds of both buffers.
+func (b *Buffer) clipBlit(src *Buffer, srcX, srcY, dstX, dstY, width, height int) (int, int, int, int, int, int) {
 	// Clip to source bounds
 	if srcX < 0 {
 		width += srcX
@@ -1075,24 +1116,7 @@ func (b *Buffer) Blit(src *Buffer, srcX, srcY, dstX, dstY, width, height int) {
 	if dstY+height > b.height {
 		height = b.height - dstY
 	}
-
-	// Nothing to copy
-	if width <= 0 || height <= 0 {
-		return
-	}
-
-	// row-by-row copy
-	for y := 0; y < height; y++ {
-		srcStart := (srcY+y)*src.width + srcX
-		dstStart := (dstY+y)*b.width + dstX
-		copy(b.cells[dstStart:dstStart+width], src.cells[srcStart:srcStart+width])
-		b.dirtyRows[dstY+y] = true
-	}
-
-	// Update dirty tracking
-	if dstY+height-1 > b.dirtyMaxY {
-		b.dirtyMaxY = dstY + height - 1
-	}
+	return srcX, srcY, dstX, dstY, width, height
 }
 
 // CopyFrom copies all cells from src to b using a single bulk copy.
@@ -0,0 +1,159 @@
+package glyph
+
+import (
+	"os"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// Capabilities describes what the terminal supports, so apps and widgets
+// can check for a feature rather than guess at it.
+//
+//	if app.Capabilities().Clipboard {
+//	    app.Handle("y", copySelection)
+//	}
+//
+// Features are worked out from the environment, by recognising the
+// terminal and any multiplexer in between, so they're a best guess:
+// unknown terminals get only what nearly every terminal has, and a
+// multiplexer hides whatever it doesn't pass on. Use SetCapabilities to
+// correct them.
+type Capabilities struct {
+	Terminal string // terminal recognised, such as "kitty" or "iterm2"; "" if not
+
+	TrueColor     bool // 24-bit RGB colour
+	Mouse         bool // SGR mouse reporting
+	KittyKeyboard bool // kitty's progressive keyboard enhancement protocol
+	Sixel         bool // sixel images
+	KittyGraphics bool // kitty's graphics protocol
+	Clipboard     bool // setting the clipboard with OSC 52
+	SyncOutput    bool // synchronized output, mode 2026
+	Notifications bool // desktop notifications with OSC 9 or 777
+
+	// Size of a cell in pixels, 0 when the terminal doesn't report it
+	CellWidth, CellHeight int
+}
+
+// Capabilities returns what the terminal supports. Apps on a backend other
+// than the terminal report nothing unless SetCapabilities says otherwise.
+func (a *App) Capabilities() Capabilities {
+	if a.capsSet {
+		return a.caps
+	}
+	tb, ok := a.backend.(*terminalBackend)
+	if !ok {
+		return Capabilities{}
+	}
+	caps := detectCapabilities(os.Getenv, tb.screen.Quirks())
+	caps.CellWidth, caps.CellHeight = tb.screen.CellSize()
+	return caps
+}
+
+// SetCapabilities overrides the detected capabilities. The screen follows
+// SyncOutput.
+func (a *App) SetCapabilities(c Capabilities) *App {
+	a.caps, a.capsSet = c, true
+	if a.screen != nil {
+		a.screen.SetSyncOutput(c.SyncOutput)
+	}
+	return a
+}
+
+// CellSize returns the size of a cell in pixels, or 0, 0 if the terminal
+// doesn't report its size in pixels.
+func (s *Screen) CellSize() (width, height int) {
+	ws, err := unix.IoctlGetWinsize(s.fd, unix.TIOCGWINSZ)
+	if err != nil || ws.Col == 0 || ws.Row == 0 {
+		return 0, 0
+	}
+	return int(ws.Xpixel / ws.Col), int(ws.Ypixel / ws.Row)
+}
+
+// terminalCaps are the features of terminals that can be recognised.
+var terminalCaps = map[string]Capabilities{
+	"kitty":     {TrueColor: true, Mouse: true, KittyKeyboard: true, KittyGraphics: true, Clipboard: true, SyncOutput: true, Notifications: true},
+	"ghostty":   {TrueColor: true, Mouse: true, KittyKeyboard: true, KittyGraphics: true, Clipboard: true, SyncOutput: true, Notifications: true},
+	"wezterm":   {TrueColor: true, Mouse: true, Sixel: true, KittyGraphics: true, Clipboard: true, SyncOutput: true, Notifications: true},
+	"iterm2":    {TrueColor: true, Mouse: true, Sixel: true, Clipboard: true, SyncOutput: true, Notifications: true},
+	"foot":      {TrueColor: true, Mouse: true, KittyKeyboard: true, Sixel: true, Clipboard: true, SyncOutput: true, Notifications: true},
+	"alacritty": {TrueColor: true, Mouse: true, KittyKeyboard: true, Clipboard: true, SyncOutput: true},
+	"contour":   {TrueColor: true, Mouse: true, Sixel: true, Clipboard: true, SyncOutput: true, Notifications: true},
+	"windows":   {TrueColor: true, Mouse: true, Clipboard: true},
+	"vscode":    {TrueColor: true, Mouse: true, SyncOutput: true},
+	"konsole":   {TrueColor: true, Mouse: true, Sixel: true},
+	"vte":       {TrueColor: true, Mouse: true},
+	"apple":     {Mouse: true},
+	"xterm":     {TrueColor: true, Mouse: true},
+}
+
+// recogniseTerminal names the terminal from the variables terminals set.
+func recogniseTerminal(getenv func(string) string) string {
+	term := getenv("TERM")
+	switch {
+	case getenv("KITTY_WINDOW_ID") != "" || term == "xterm-kitty":
+		return "kitty"
+	case getenv("GHOSTTY_RESOURCES_DIR") != "" || term == "xterm-ghostty":
+		return "ghostty"
+	case getenv("WEZTERM_EXECUTABLE") != "" || term == "wezterm":
+		return "wezterm"
+	case getenv("ALACRITTY_WINDOW_ID") != "" || term == "alacritty":
+		return "alacritty"
+	case getenv("WT_SESSION") != "":
+		return "windows"
+	case getenv("KONSOLE_VERSION") != "":
+		return "konsole"
+	case strings.HasPrefix(term, "foot"):
+		return "foot"
+	case strings.HasPrefix(term, "contour"):
+		return "contour"
+	}
+	switch getenv("TERM_PROGRAM") {
+	case "iTerm.app":
+		return "iterm2"
+	case "WezTerm":
+		return "wezterm"
+	case "ghostty":
+		return "ghostty"
+	case "vscode":
+		return "vscode"
+	case "Apple_Terminal":
+		return "apple"
+	}
+	switch {
+	case getenv("VTE_VERSION") != "":
+		return "vte"
+	case getenv("XTERM_VERSION") != "":
+		return "xterm"
+	}
+	return ""
+}
+
+func detectCapabilities(getenv func(string) string, q Quirks) Capabilities {
+	name := recogniseTerminal(getenv)
+	caps := terminalCaps[name]
+	caps.Terminal = name
+	term := getenv("TERM")
+	if name == "" && term != "" && term != "dumb" && term != "linux" {
+		// anything calling itself an xterm or the like has the mouse
+		caps.Mouse = true
+	}
+	colorterm := strings.ToLower(getenv("COLORTERM"))
+	if colorterm == "truecolor" || colorterm == "24bit" {
+		caps.TrueColor = true
+	}
+	if q.NoTrueColor {
+		caps.TrueColor = false
+	}
+	if q.Mux != MuxNone {
+		// the multiplexer, not the terminal outside it, decides what gets
+		// through; mosh forwards OSC 52 and none pass on the rest
+		caps = Capabilities{
+			Terminal:  caps.Terminal,
+			TrueColor: caps.TrueColor,
+			Mouse:     term != "dumb",
+			Clipboard: q.Mux == MuxMosh,
+		}
+	}
+	return caps
+}
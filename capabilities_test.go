@@ -0,0 +1,51 @@
+package glyph
+
+import "testing"
+
+func TestDetectCapabilities(t *testing.T) {
+	tests := []struct {
+		name  string
+		env   map[string]string
+		quirk Quirks
+		want  Capabilities
+	}{
+		{"unknown", map[string]string{"TERM": "xterm-256color"}, Quirks{},
+			Capabilities{Mouse: true}},
+		{"unknown truecolor", map[string]string{"TERM": "xterm-256color", "COLORTERM": "truecolor"}, Quirks{},
+			Capabilities{TrueColor: true, Mouse: true}},
+		{"dumb", map[string]string{"TERM": "dumb"}, Quirks{}, Capabilities{}},
+		{"kitty", map[string]string{"TERM": "xterm-kitty", "KITTY_WINDOW_ID": "1"}, Quirks{},
+			Capabilities{Terminal: "kitty", TrueColor: true, Mouse: true, KittyKeyboard: true, KittyGraphics: true, Clipboard: true, SyncOutput: true, Notifications: true}},
+		{"iterm2", map[string]string{"TERM": "xterm-256color", "TERM_PROGRAM": "iTerm.app"}, Quirks{},
+			Capabilities{Terminal: "iterm2", TrueColor: true, Mouse: true, Sixel: true, Clipboard: true, SyncOutput: true, Notifications: true}},
+		{"apple", map[string]string{"TERM": "xterm-256color", "TERM_PROGRAM": "Apple_Terminal"}, Quirks{},
+			Capabilities{Terminal: "apple", Mouse: true}},
+		{"kitty in tmux", map[string]string{"TERM": "tmux-256color", "TMUX": "x", "KITTY_WINDOW_ID": "1"}, Quirks{Mux: MuxTmux, NoTrueColor: true},
+			Capabilities{Terminal: "kitty", Mouse: true}},
+		{"wezterm in mosh", map[string]string{"TERM": "xterm-256color", "WEZTERM_EXECUTABLE": "/usr/bin/wezterm"}, Quirks{Mux: MuxMosh, NoTrueColor: true},
+			Capabilities{Terminal: "wezterm", Mouse: true, Clipboard: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := detectCapabilities(func(k string) string { return tt.env[k] }, tt.quirk)
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetCapabilities(t *testing.T) {
+	app, err := NewApp()
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.SetBackend(NewBufferBackend(10, 2))
+	if got := app.Capabilities(); got != (Capabilities{}) {
+		t.Errorf("buffer backend reported %+v", got)
+	}
+	want := Capabilities{Terminal: "kitty", Clipboard: true, CellWidth: 8, CellHeight: 16}
+	if got := app.SetCapabilities(want).Capabilities(); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
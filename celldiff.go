@@ -0,0 +1,45 @@
+package glyph
+
+import (
+	"bytes"
+	"unsafe"
+)
+
+// Flush compares frames row by row before cell by cell. Rows and runs of
+// cells are compared as the memory they're stored in, which the runtime
+// does a vector at a time, so an unchanged row costs a few dozen
+// instructions however wide it is. This skips the same rows hashing them
+// would, without the chance of a collision hiding a change.
+
+// diffBlock is how many cells firstDiff compares at once.
+const diffBlock = 8
+
+// cellBytes returns the memory of cells.
+func cellBytes(cells []Cell) []byte {
+	return unsafe.Slice((*byte)(unsafe.Pointer(unsafe.SliceData(cells))), len(cells)*int(unsafe.Sizeof(Cell{})))
+}
+
+// sameCells reports whether a and b hold the same cells. Cells have no
+// pointers, so equal memory means equal cells; the padding at the end of
+// each can only make equal cells look different, which costs rewriting
+// them, never missing a change.
+func sameCells(a, b []Cell) bool {
+	return bytes.Equal(cellBytes(a), cellBytes(b))
+}
+
+// firstDiff returns the index of the first cell that differs between a and
+// b, or len(a) if none does. b is at least as long as a.
+func firstDiff(a, b []Cell) int {
+	i := 0
+	for ; i+diffBlock <= len(a); i += diffBlock {
+		if !sameCells(a[i:i+diffBlock], b[i:i+diffBlock]) {
+			break
+		}
+	}
+	for ; i < len(a); i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return i
+}
@@ -0,0 +1,157 @@
+package glyph
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFirstDiff(t *testing.T) {
+	a := make([]Cell, 50)
+	for i := range a {
+		a[i] = Cell{Rune: 'a', Style: DefaultStyle()}
+	}
+	b := append([]Cell(nil), a...)
+	if !sameCells(a, b) {
+		t.Fatal("equal rows should be the same")
+	}
+	if got := firstDiff(a, b); got != len(a) {
+		t.Errorf("equal rows: got %d, want %d", got, len(a))
+	}
+	for _, at := range []int{0, 7, 8, 23, 49} {
+		b := append([]Cell(nil), a...)
+		b[at].Style.FG = Red
+		if sameCells(a, b) {
+			t.Errorf("cell %d differs but rows are the same", at)
+		}
+		if got := firstDiff(a, b); got != at {
+			t.Errorf("got %d, want %d", got, at)
+		}
+	}
+}
+
+func TestFlushWritesOnlyChangedCells(t *testing.T) {
+	s, out := newTestScreen(80, 4)
+	for y := range 4 {
+		s.back.WriteString(0, y, strings.Repeat("x", 80), DefaultStyle())
+	}
+	s.Flush()
+
+	// a new frame is copied in whole, marking every row dirty
+	s.back.MarkAllDirty()
+	s.back.Set(50, 2, Cell{Rune: 'y', Style: DefaultStyle()})
+	out.Reset()
+	s.Flush()
+	s.FlushBuffer()
+
+	got := out.String()
+	if strings.Count(got, "\x1b[") != 2 || !strings.Contains(got, "\x1b[3;51Hy") {
+		t.Errorf("expected one cell written at 3;51, got %q", got)
+	}
+}
+
+// dashboardFrames returns frames like a monitoring dashboard's: a full
+// screen of styled text of which a clock, a few counters and a sparkline
+// change from one frame to the next.
+func dashboardFrames(width, height, n int) []*Buffer {
+	frames := make([]*Buffer, n)
+	for i := range frames {
+		f := NewBuffer(width, height)
+		f.WriteString(0, 0, " dashboard", Style{Attr: AttrBold})
+		f.WriteString(width-9, 0, "12:00:0", Style{Attr: AttrDim})
+		f.WriteInt(width-2, 0, i%10, Style{Attr: AttrDim}, 1)
+		for y := 2; y < height; y++ {
+			f.WriteString(0, y, " service-", Style{FG: Cyan})
+			f.WriteInt(9, y, y, Style{FG: Cyan}, 3)
+			f.WriteString(20, y, "healthy", Style{FG: Green})
+			f.WriteString(40, y, strings.Repeat("─", width-60), Style{Attr: AttrDim})
+			if y%8 == 0 {
+				f.WriteInt(width-12, y, i*y, Style{}, 10)
+			}
+		}
+		f.Set(40+i%(width-60), height-1, Cell{Rune: '█', Style: Style{FG: Yellow}})
+		frames[i] = f
+	}
+	return frames
+}
+
+// flushCellByCell is Flush's diff before rows were compared whole.
+func flushCellByCell(back, front *Buffer) (changed int) {
+	for y := 0; y < back.height; y++ {
+		if !back.RowDirty(y) {
+			continue
+		}
+		base := y * back.width
+		for x := 0; x < back.width; x++ {
+			if back.cells[base+x] == front.cells[base+x] {
+				continue
+			}
+			front.cells[base+x] = back.cells[base+x]
+			changed++
+		}
+	}
+	back.ClearDirtyFlags()
+	return changed
+}
+
+// flushByRow is Flush's diff.
+func flushByRow(back, front *Buffer) (changed int) {
+	for y := 0; y < back.height; y++ {
+		if !back.RowDirty(y) {
+			continue
+		}
+		b := back.cells[y*back.width:][:back.width]
+		f := front.cells[y*front.width:][:front.width]
+		if sameCells(b, f) {
+			continue
+		}
+		for x := 0; x < len(b); x++ {
+			if x += firstDiff(b[x:], f[x:]); x >= len(b) {
+				break
+			}
+			f[x] = b[x]
+			changed++
+		}
+	}
+	back.ClearDirtyFlags()
+	return changed
+}
+
+func BenchmarkCellDiff(b *testing.B) {
+	for _, size := range []struct{ w, h int }{{120, 40}, {250, 70}} {
+		frames := dashboardFrames(size.w, size.h, 16)
+		for _, diff := range []struct {
+			name string
+			fn   func(back, front *Buffer) int
+		}{
+			{"cells", flushCellByCell},
+			{"rows", flushByRow},
+		} {
+			b.Run(fmt.Sprintf("%s/%dx%d", diff.name, size.w, size.h), func(b *testing.B) {
+				back, front := NewBuffer(size.w, size.h), NewBuffer(size.w, size.h)
+				b.ReportAllocs()
+				for i := 0; b.Loop(); i++ {
+					// as the terminal backend presents a frame
+					back.CopyFrom(frames[i%len(frames)])
+					diff.fn(back, front)
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkFlushDashboard flushes dashboard frames presented as the
+// terminal backend presents them, each copied whole into the back buffer.
+func BenchmarkFlushDashboard(b *testing.B) {
+	frames := dashboardFrames(120, 40, 16)
+	w := &mockWriter{}
+	s := &Screen{width: 120, height: 40, back: NewBuffer(120, 40), front: NewBuffer(120, 40), writer: w}
+	b.ReportAllocs()
+	for i := 0; b.Loop(); i++ {
+		s.back.CopyFrom(frames[i%len(frames)])
+		w.n = 0
+		s.Flush()
+		s.FlushBuffer()
+	}
+	b.ReportMetric(float64(w.n), "bytes/op")
+}
@@ -0,0 +1,373 @@
+package glyph
+
+import (
+	_ "embed"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/kungfusheep/riffkey"
+	"github.com/mattn/go-runewidth"
+)
+
+//go:embed charnames.txt
+var charNamesData string
+
+// CharInfo is a character the CharPicker knows by name.
+type CharInfo struct {
+	Rune  rune
+	Name  string // lower case, as in "black up-pointing triangle"
+	Block string // the group it's listed under, as in "Geometric Shapes"
+}
+
+var charTable struct {
+	once  sync.Once
+	list  []CharInfo
+	names map[rune]string
+}
+
+// Chars returns the characters the picker searches: Latin-1 and Greek
+// letters, punctuation, currency, arrows, maths, technical symbols, box
+// drawing, blocks, shapes, dingbats and the like.
+func Chars() []CharInfo {
+	charTable.once.Do(func() {
+		charTable.names = map[rune]string{}
+		block := ""
+		for _, line := range strings.Split(charNamesData, "\n") {
+			if b, ok := strings.CutPrefix(line, "# "); ok {
+				block = b
+				continue
+			}
+			hex, name, ok := strings.Cut(line, " ")
+			if !ok {
+				continue
+			}
+			cp, err := strconv.ParseUint(hex, 16, 32)
+			if err != nil {
+				continue
+			}
+			charTable.list = append(charTable.list, CharInfo{Rune: rune(cp), Name: name, Block: block})
+			charTable.names[rune(cp)] = name
+		}
+	})
+	return charTable.list
+}
+
+// CharName returns the name of r if Chars lists it.
+func CharName(r rune) (string, bool) {
+	Chars()
+	name, ok := charTable.names[r]
+	return name, ok
+}
+
+// SearchChars returns the characters whose name or block match an fzf
+// query, best first. A code point typed as "U+25B2" or "25b2" finds that
+// character whether it's listed or not.
+func SearchChars(query string) []CharInfo {
+	var out []CharInfo
+	if r, ok := parseCodePoint(query); ok {
+		name, _ := CharName(r)
+		out = append(out, CharInfo{Rune: r, Name: name})
+	}
+	q := ParseFzfQuery(query)
+	exact := strings.ToLower(strings.TrimSpace(query))
+	if q.Empty() {
+		return append(out, Chars()...)
+	}
+	type scored struct {
+		c     CharInfo
+		score int
+	}
+	var hits []scored
+	for _, c := range Chars() {
+		if len(out) > 0 && c.Rune == out[0].Rune {
+			continue
+		}
+		score, ok := q.Score(c.Name)
+		if !ok {
+			if score, ok = q.Score(c.Name + " " + c.Block); !ok {
+				continue
+			}
+		} else {
+			score += 1000 // the name alone beats the name and block
+			if c.Name == exact {
+				score += 1000
+			}
+		}
+		hits = append(hits, scored{c, score})
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].score != hits[j].score {
+			return hits[i].score > hits[j].score
+		}
+		return len(hits[i].c.Name) < len(hits[j].c.Name)
+	})
+	for _, h := range hits {
+		out = append(out, h.c)
+	}
+	return out
+}
+
+// parseCodePoint reads "U+25B2", or a bare hex code point of four to six
+// digits with a decimal digit among them, so that words like "face" are
+// left as words.
+func parseCodePoint(s string) (rune, bool) {
+	s = strings.TrimSpace(s)
+	hex, prefixed := strings.CutPrefix(strings.ToUpper(s), "U+")
+	if !prefixed && (len(hex) < 4 || !strings.ContainsAny(hex, "0123456789")) || len(hex) > 6 {
+		return 0, false
+	}
+	cp, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || cp > 0x10FFFF || cp < 0x20 {
+		return 0, false
+	}
+	return rune(cp), true
+}
+
+// CharPickerC is a searchable list of characters that floats near the top
+// of the screen while open, for typing symbols without knowing their code
+// points. It takes no space in the layout:
+//
+//	VBox(
+//	    TextArea(buf).Bind(),
+//	    CharPicker(buf.TypeRune).BindOpen("<C-u>"),
+//	)
+//
+// Typing searches names and blocks, such as "triangle up" or "box heavy";
+// a code point such as "U+2603" or "2603" finds that character. The characters
+// picked recently are listed first. Up/down or Ctrl-p/Ctrl-n move, Enter
+// picks and Esc closes.
+type CharPickerC struct {
+	onPick func(rune)
+
+	query   string
+	cursor  int
+	matches []CharInfo
+	sel     int
+	open    bool
+	recent  []rune
+
+	width    int16
+	rows     int
+	style    Style
+	selStyle Style
+	dimStyle Style
+	border   BorderStyle
+
+	router           *riffkey.Router
+	push             func(*riffkey.Router)
+	pop              func()
+	declaredBindings []binding
+}
+
+// CharPicker creates a picker that calls onPick with the character chosen.
+func CharPicker(onPick func(rune)) *CharPickerC {
+	return &CharPickerC{
+		onPick:   onPick,
+		width:    50,
+		rows:     12,
+		selStyle: Style{Attr: AttrInverse},
+		dimStyle: Style{Attr: AttrDim},
+		border:   BorderRounded,
+	}
+}
+
+// Width sets the widest the picker gets. 50 by default.
+func (cp *CharPickerC) Width(w int16) *CharPickerC {
+	cp.width = w
+	return cp
+}
+
+// Rows sets how many characters are listed at once. 12 by default.
+func (cp *CharPickerC) Rows(n int) *CharPickerC {
+	cp.rows = max(n, 1)
+	return cp
+}
+
+// Style sets the picker body and border style.
+func (cp *CharPickerC) Style(s Style) *CharPickerC {
+	cp.style = s
+	return cp
+}
+
+// SelectedStyle sets the style of the row under the cursor. Inverse by
+// default.
+func (cp *CharPickerC) SelectedStyle(s Style) *CharPickerC {
+	cp.selStyle = s
+	return cp
+}
+
+// Border sets the picker border. BorderRounded by default.
+func (cp *CharPickerC) Border(b BorderStyle) *CharPickerC {
+	cp.border = b
+	return cp
+}
+
+// BindOpen registers a key that opens the picker.
+func (cp *CharPickerC) BindOpen(key string) *CharPickerC {
+	cp.declaredBindings = append(cp.declaredBindings, binding{key, cp.Open})
+	return cp
+}
+
+// Open opens the picker with an empty search.
+func (cp *CharPickerC) Open() {
+	if !cp.open && cp.push != nil {
+		cp.push(cp.keys())
+	}
+	cp.open = true
+	cp.query, cp.cursor = "", 0
+	cp.search(cp.query)
+}
+
+// Close closes the picker and hands the keyboard back.
+func (cp *CharPickerC) Close() {
+	if !cp.open {
+		return
+	}
+	cp.open = false
+	if cp.pop != nil {
+		cp.pop()
+	}
+}
+
+// IsOpen reports whether the picker is open.
+func (cp *CharPickerC) IsOpen() bool { return cp.open }
+
+// Recent returns the characters picked recently, newest first.
+func (cp *CharPickerC) Recent() []rune { return cp.recent }
+
+// Selected returns the character under the cursor.
+func (cp *CharPickerC) Selected() (CharInfo, bool) {
+	if cp.sel < len(cp.matches) {
+		return cp.matches[cp.sel], true
+	}
+	return CharInfo{}, false
+}
+
+func (cp *CharPickerC) search(q string) {
+	cp.matches = SearchChars(q)
+	cp.sel = 0
+	if strings.TrimSpace(q) != "" || len(cp.recent) == 0 {
+		return
+	}
+	lead := make([]CharInfo, 0, len(cp.recent)+len(cp.matches))
+	seen := map[rune]bool{}
+	for _, r := range cp.recent {
+		name, _ := CharName(r)
+		lead = append(lead, CharInfo{Rune: r, Name: name, Block: "Recent"})
+		seen[r] = true
+	}
+	for _, c := range cp.matches {
+		if !seen[c.Rune] {
+			lead = append(lead, c)
+		}
+	}
+	cp.matches = lead
+}
+
+func (cp *CharPickerC) move(dir int) {
+	if n := len(cp.matches); n > 0 {
+		cp.sel = min(max(cp.sel+dir, 0), n-1)
+	}
+}
+
+// pick closes the picker and hands over the selected character.
+func (cp *CharPickerC) pick() {
+	c, ok := cp.Selected()
+	if !ok {
+		return
+	}
+	cp.Close()
+	recent := []rune{c.Rune}
+	for _, r := range cp.recent {
+		if r != c.Rune && len(recent) < 16 {
+			recent = append(recent, r)
+		}
+	}
+	cp.recent = recent
+	if cp.onPick != nil {
+		cp.onPick(c.Rune)
+	}
+}
+
+// keys returns the router pushed while the picker is open.
+func (cp *CharPickerC) keys() *riffkey.Router {
+	if cp.router != nil {
+		return cp.router
+	}
+	r := riffkey.NewRouter().NoCounts()
+	down := func(riffkey.Match) { cp.move(1) }
+	up := func(riffkey.Match) { cp.move(-1) }
+	r.Handle("<Down>", down)
+	r.Handle("<C-n>", down)
+	r.Handle("<Up>", up)
+	r.Handle("<C-p>", up)
+	r.Handle("<PageDown>", func(riffkey.Match) { cp.move(cp.rows) })
+	r.Handle("<PageUp>", func(riffkey.Match) { cp.move(-cp.rows) })
+	r.Handle("<Enter>", func(riffkey.Match) { cp.pick() })
+	r.Handle("<Esc>", func(riffkey.Match) { cp.Close() })
+	th := riffkey.NewTextHandler(&cp.query, &cp.cursor)
+	th.OnChange = cp.search
+	r.HandleUnmatched(func(k riffkey.Key) bool {
+		th.HandleKey(k)
+		return true
+	})
+	cp.router = r
+	return r
+}
+
+func (cp *CharPickerC) bindings() []binding {
+	return cp.declaredBindings
+}
+
+func (cp *CharPickerC) setModal(push func(*riffkey.Router), pop func()) {
+	cp.push, cp.pop = push, pop
+}
+
+func (t *Template) compileCharPickerC(cp *CharPickerC, parent int16, depth int) int16 {
+	measure := func(int16) (w, h int16) { return 0, 0 }
+	render := func(buf *Buffer, x, y, w, h int16) {
+		if cp.open {
+			t.pendingOverlays = append(t.pendingOverlays, pendingOverlay{draw: cp.draw})
+		}
+	}
+	return t.compileCustom(Widget(measure, render), parent, depth)
+}
+
+func (cp *CharPickerC) draw(buf *Buffer, screenW, screenH int16) {
+	w := min(cp.width, screenW-2)
+	rows := min(cp.rows, max(len(cp.matches), 1), max(int(screenH)-4, 0))
+	h := int16(rows + 3)
+	if w < 16 || h > screenH {
+		return
+	}
+	x, y := (screenW-w)/2, min(screenH/6, screenH-h)
+	bx, by, bw := int(x), int(y), int(w)
+	buf.FillRect(bx, by, bw, int(h), Cell{Rune: ' ', Style: cp.style})
+	buf.DrawBorder(bx, by, bw, int(h), cp.border, cp.style)
+	buf.WriteStringFast(bx+2, by+1, "> ", cp.style, bw-4)
+	drawLine(buf, bx+4, by+1, bw-6, cp.query, cp.cursor, cp.style)
+
+	if len(cp.matches) == 0 {
+		buf.WriteStringFast(bx+2, by+2, "no matching characters", cp.dimStyle, bw-4)
+		return
+	}
+	top := max(cp.sel-rows+1, 0)
+	for i := 0; i < rows && top+i < len(cp.matches); i++ {
+		c := cp.matches[top+i]
+		row := by + 2 + i
+		style, dim := cp.style, cp.dimStyle
+		if top+i == cp.sel {
+			style = cp.selStyle
+			dim = style.Dim()
+			buf.FillRect(bx+1, row, bw-2, 1, Cell{Rune: ' ', Style: style})
+		}
+		buf.SetFast(bx+2, row, Cell{Rune: c.Rune, Style: style})
+		blockW := min(runewidth.StringWidth(c.Block), (bw-6)/3)
+		buf.WriteStringFast(bx+5, row, fmt.Sprintf("U+%04X", c.Rune), dim, 7)
+		buf.WriteStringFast(bx+13, row, c.Name, style, bw-16-blockW)
+		buf.WriteStringFast(bx+bw-2-blockW, row, c.Block, dim, blockW)
+	}
+}
@@ -0,0 +1,74 @@
+package glyph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+func TestSearchChars(t *testing.T) {
+	if len(Chars()) < 1000 {
+		t.Fatalf("only %d characters", len(Chars()))
+	}
+	if name, ok := CharName('●'); !ok || name != "black circle" {
+		t.Errorf("CharName = %q %v", name, ok)
+	}
+	if got := SearchChars("black circle"); len(got) == 0 || got[0].Rune != '●' {
+		t.Errorf("black circle: %+v", got[:min(len(got), 3)])
+	}
+	found := false
+	for _, c := range SearchChars("triangle up") {
+		if c.Rune == '▲' {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("triangle up didn't find ▲")
+	}
+	for _, q := range []string{"U+25B2", "25b2"} {
+		if got := SearchChars(q); len(got) == 0 || got[0].Rune != '▲' || got[0].Name == "" {
+			t.Errorf("%s: %+v", q, got[:min(len(got), 1)])
+		}
+	}
+	if got := SearchChars("face"); len(got) > 0 && got[0].Rune == 0xFACE {
+		t.Error("a word was read as a code point")
+	}
+}
+
+func TestCharPicker(t *testing.T) {
+	var picked []rune
+	cp := CharPicker(func(r rune) { picked = append(picked, r) }).BindOpen("<C-u>")
+	tmpl := Build(VBox(Text("body"), cp))
+	if len(tmpl.pendingBindings) != 1 || len(tmpl.pendingModals) != 1 {
+		t.Fatalf("bindings not collected")
+	}
+	var stack []*riffkey.Router
+	cp.setModal(func(r *riffkey.Router) { stack = append(stack, r) }, func() { stack = stack[:len(stack)-1] })
+
+	cp.Open()
+	in := riffkey.NewInput(stack[0])
+	typeKeys(in, "black star")
+	screen := strings.Join(messageLines(tmpl, 60, 20), "\n")
+	if !strings.Contains(screen, "★  U+2605  black star") {
+		t.Errorf("picker:\n%s", screen)
+	}
+	in.Dispatch(riffkey.Key{Special: riffkey.SpecialEnter})
+	if cp.IsOpen() || len(stack) != 0 || len(picked) != 1 || picked[0] != '★' {
+		t.Fatalf("open=%v picked %q", cp.IsOpen(), picked)
+	}
+
+	// the characters picked lately lead an empty search
+	cp.Open()
+	in = riffkey.NewInput(stack[0])
+	typeKeys(in, "snowman")
+	in.Dispatch(riffkey.Key{Special: riffkey.SpecialEnter})
+	cp.Open()
+	if c, _ := cp.Selected(); c.Rune != '☃' || cp.matches[1].Rune != '★' || cp.matches[1].Block != "Recent" {
+		t.Errorf("recent: %+v", cp.matches[:2])
+	}
+	cp.Close()
+	if len(stack) != 0 {
+		t.Error("close didn't pop")
+	}
+}
@@ -0,0 +1,232 @@
+package glyph
+
+import (
+	"strings"
+
+	"github.com/kungfusheep/riffkey"
+	"github.com/mattn/go-runewidth"
+)
+
+// CheatsheetC lists a router's named actions with the keys they're bound to
+// now, grouped and flowed into as many columns as the width allows. Bind
+// actions with HandleNamed and a "group.action" name:
+//
+//	app.HandleNamed("file.save", "<C-s>", save)
+//	app.HandleNamed("view.zoom_in", "+", zoomIn)
+//
+//	If(&showHelp).Then(Overlay.Centered()(
+//	    VBox.Width(70).Border(BorderRounded).Title("Keys")(Cheatsheet(app.Router())),
+//	))
+//
+// Rebinding through the router or a config file shows up on the next
+// render. Names without a dot are listed first, under no heading.
+type CheatsheetC struct {
+	router *riffkey.Router
+	names  func(name string) (group, label string)
+
+	headerStyle Style
+	keyStyle    Style
+	labelStyle  Style
+	gap         int16
+}
+
+// Cheatsheet creates a cheatsheet of router's named bindings.
+func Cheatsheet(router *riffkey.Router) *CheatsheetC {
+	return &CheatsheetC{
+		router:      router,
+		names:       cheatsheetName,
+		headerStyle: Style{Attr: AttrBold},
+		keyStyle:    Style{FG: Cyan},
+		gap:         3,
+	}
+}
+
+// Names sets how a binding name splits into its group and the label shown.
+// By default "file.save_as" is "Save as" under "File".
+func (c *CheatsheetC) Names(fn func(name string) (group, label string)) *CheatsheetC {
+	c.names = fn
+	return c
+}
+
+// HeaderStyle sets the group heading style. Bold by default.
+func (c *CheatsheetC) HeaderStyle(s Style) *CheatsheetC {
+	c.headerStyle = s
+	return c
+}
+
+// KeyStyle sets the key style. Cyan by default.
+func (c *CheatsheetC) KeyStyle(s Style) *CheatsheetC {
+	c.keyStyle = s
+	return c
+}
+
+// LabelStyle sets the action label style.
+func (c *CheatsheetC) LabelStyle(s Style) *CheatsheetC {
+	c.labelStyle = s
+	return c
+}
+
+// Gap sets the space between columns. 3 by default.
+func (c *CheatsheetC) Gap(n int16) *CheatsheetC {
+	c.gap = n
+	return c
+}
+
+// cheatsheetName splits "file.save_as" into "File" and "Save as".
+func cheatsheetName(name string) (group, label string) {
+	group, label, ok := strings.Cut(name, ".")
+	if !ok {
+		group, label = "", name
+	}
+	return upperFirst(group), upperFirst(strings.NewReplacer("_", " ", "-", " ").Replace(label))
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+type cheatGroup struct {
+	name string
+	rows []cheatRow
+}
+
+type cheatRow struct {
+	key, label string
+}
+
+func (g cheatGroup) height() int16 {
+	h := int16(len(g.rows))
+	if g.name != "" {
+		h++
+	}
+	return h
+}
+
+// groups reads the current bindings, in the order groups first appear.
+func (c *CheatsheetC) groups() []cheatGroup {
+	if c.router == nil {
+		return nil
+	}
+	var out []cheatGroup
+	index := map[string]int{}
+	for _, b := range c.router.Bindings() {
+		if b.Pattern == "" {
+			continue
+		}
+		group, label := c.names(b.Name)
+		i, ok := index[group]
+		if !ok {
+			i = len(out)
+			index[group] = i
+			out = append(out, cheatGroup{name: group})
+		}
+		out[i].rows = append(out[i].rows, cheatRow{keyLabel(b.Pattern), label})
+	}
+	// the ungrouped entries lead
+	if i, ok := index[""]; ok && i > 0 {
+		g := out[i]
+		copy(out[1:i+1], out[:i])
+		out[0] = g
+	}
+	return out
+}
+
+// cheatLayout is the groups split into columns for a width.
+type cheatLayout struct {
+	columns [][]cheatGroup
+	keyW    int16
+	colW    int16
+	height  int16
+}
+
+func (c *CheatsheetC) layout(w int16) cheatLayout {
+	groups := c.groups()
+	var l cheatLayout
+	labelW, total := int16(0), int16(0)
+	for i, g := range groups {
+		labelW = max(labelW, int16(runewidth.StringWidth(g.name)))
+		for _, r := range g.rows {
+			l.keyW = max(l.keyW, int16(runewidth.StringWidth(r.key)))
+			labelW = max(labelW, int16(runewidth.StringWidth(r.label)))
+		}
+		total += g.height()
+		if i > 0 {
+			total++ // blank line between groups
+		}
+	}
+	if len(groups) == 0 {
+		return l
+	}
+	l.colW = l.keyW + 2 + labelW
+
+	n := int16(1)
+	if l.colW > 0 && w > 0 {
+		n = max((w+c.gap)/(l.colW+c.gap), 1)
+	}
+	n = min(n, int16(len(groups)))
+	target := (total + n - 1) / n
+
+	// fill columns in order, moving on once the next group would overshoot
+	col, h := []cheatGroup(nil), int16(0)
+	for _, g := range groups {
+		gh := g.height()
+		if len(col) > 0 {
+			gh++
+		}
+		if len(col) > 0 && h+gh > target && int16(len(l.columns)) < n-1 {
+			l.columns = append(l.columns, col)
+			l.height = max(l.height, h)
+			col, h, gh = nil, 0, g.height()
+		}
+		col = append(col, g)
+		h += gh
+	}
+	l.columns = append(l.columns, col)
+	l.height = max(l.height, h)
+	return l
+}
+
+func (t *Template) compileCheatsheetC(c *CheatsheetC, parent int16, depth int) int16 {
+	measure := func(availW int16) (int16, int16) {
+		l := c.layout(availW)
+		if availW < 0 {
+			return l.colW, l.height // one column
+		}
+		return -1, l.height
+	}
+	return t.compileCustom(Widget(measure, c.render), parent, depth)
+}
+
+func (c *CheatsheetC) render(buf *Buffer, x, y, w, h int16) {
+	l := c.layout(w)
+	for i, col := range l.columns {
+		cx := x + int16(i)*(l.colW+c.gap)
+		if cx >= x+w {
+			break
+		}
+		colW := int(min(l.colW, x+w-cx))
+		row := y
+		for j, g := range col {
+			if j > 0 {
+				row++
+			}
+			if g.name != "" && row < y+h {
+				buf.WriteStringFast(int(cx), int(row), g.name, c.headerStyle, colW)
+				row++
+			}
+			for _, r := range g.rows {
+				if row >= y+h {
+					break
+				}
+				buf.WriteStringFast(int(cx), int(row), r.key, c.keyStyle, colW)
+				if lx := l.keyW + 2; int(lx) < colW {
+					buf.WriteStringFast(int(cx+lx), int(row), r.label, c.labelStyle, colW-int(lx))
+				}
+				row++
+			}
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package glyph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+func cheatsheetRouter() *riffkey.Router {
+	r := riffkey.NewRouter()
+	nop := func(riffkey.Match) {}
+	r.HandleNamed("file.open", "<C-o>", nop)
+	r.HandleNamed("file.save", "<C-s>", nop)
+	r.HandleNamed("view.zoom_in", "+", nop)
+	r.HandleNamed("view.zoom_out", "-", nop)
+	r.HandleNamed("quit", "q", nop)
+	return r
+}
+
+func TestCheatsheetColumns(t *testing.T) {
+	r := cheatsheetRouter()
+	tmpl := Build(VBox(Cheatsheet(r)))
+
+	got := messageLines(tmpl, 40, 4)
+	want := []string{
+		"q       Quit       View",
+		"                   +       Zoom in",
+		"File               -       Zoom out",
+		"Ctrl+O  Open",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("wide:\ngot:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// narrow: one column, and a rebinding shows straight away
+	r.Rebind("file.save", "<C-w>")
+	got = messageLines(tmpl, 16, 10)
+	want = []string{
+		"q       Quit",
+		"",
+		"File",
+		"Ctrl+O  Open",
+		"Ctrl+W  Save",
+		"",
+		"View",
+		"+       Zoom in",
+		"-       Zoom out",
+		"",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("narrow:\ngot:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestCheatsheetNames(t *testing.T) {
+	tests := []struct{ in, group, label string }{
+		{"file.save_as", "File", "Save as"},
+		{"quit", "", "Quit"},
+		{"git.push-force", "Git", "Push force"},
+	}
+	for _, tt := range tests {
+		if g, l := cheatsheetName(tt.in); g != tt.group || l != tt.label {
+			t.Errorf("cheatsheetName(%q) = %q, %q", tt.in, g, l)
+		}
+	}
+}
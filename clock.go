@@ -0,0 +1,185 @@
+package glyph
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock is where animations and timers get the time. Apps use the system
+// clock unless SetClock installs another, such as a FakeClock in tests.
+type Clock interface {
+	Now() time.Time
+	AfterFunc(d time.Duration, fn func()) Timer
+}
+
+// Timer is a pending AfterFunc call.
+type Timer interface {
+	Stop() bool
+}
+
+// SystemClock is the real wall clock.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                             { return time.Now() }
+func (systemClock) AfterFunc(d time.Duration, fn func()) Timer { return time.AfterFunc(d, fn) }
+
+// clockUser is a compiled node that reads the time.
+type clockUser interface {
+	setClock(Clock)
+}
+
+func (t *Template) collectClockUser(node any) {
+	if c, ok := node.(clockUser); ok {
+		t.clockUsers = append(t.clockUsers, c)
+	}
+}
+
+// SetClock switches every time-based node in the template, transitions
+// included, to c. App does this for you; call it when executing a
+// template directly.
+func (t *Template) SetClock(c Clock) {
+	t.clock = c
+	for _, u := range t.clockUsers {
+		u.setClock(c)
+	}
+}
+
+// SetClock replaces the system clock for animations and Every tickers.
+// Call it before Run.
+func (a *App) SetClock(c Clock) *App {
+	a.clock = c
+	return a
+}
+
+// Clock returns the app's clock.
+func (a *App) Clock() Clock {
+	if a.clock == nil {
+		return SystemClock
+	}
+	return a.clock
+}
+
+// Every calls fn on the render loop every d, followed by a render, until
+// the returned stop func is called or the app stops. Use it to drive
+// spinners and other periodic updates:
+//
+//	app.Every(80*time.Millisecond, func() { frame++ })
+//
+// Below QualityFull the ticks come at half or a quarter of the rate.
+func (a *App) Every(d time.Duration, fn func()) (stop func()) {
+	var mu sync.Mutex
+	stopped := false
+	var timer Timer
+	var tick func()
+	tick = func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if stopped || a.Context().Err() != nil {
+			return
+		}
+		a.Post(fn)
+		timer = a.Clock().AfterFunc(a.tickEvery(d), tick)
+	}
+	mu.Lock()
+	timer = a.Clock().AfterFunc(a.tickEvery(d), tick)
+	mu.Unlock()
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		stopped = true
+		timer.Stop()
+	}
+}
+
+// FakeClock is a Clock that only moves when told to, so animations and
+// tickers can be stepped frame by frame in tests:
+//
+//	clock := NewFakeClock(time.Time{})
+//	app.SetClock(clock)
+//	clock.Advance(100 * time.Millisecond) // fires whatever was due
+type FakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+	seq    int
+}
+
+type fakeTimer struct {
+	clock *FakeClock
+	at    time.Time
+	seq   int // creation order breaks ties
+	fn    func()
+}
+
+// NewFakeClock creates a fake clock reading start.
+func NewFakeClock(start time.Time) *FakeClock {
+	return &FakeClock{now: start}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *FakeClock) AfterFunc(d time.Duration, fn func()) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.seq++
+	t := &fakeTimer{clock: c, at: c.now.Add(d), seq: c.seq, fn: fn}
+	c.timers = append(c.timers, t)
+	return t
+}
+
+func (t *fakeTimer) Stop() bool {
+	c := t.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, other := range c.timers {
+		if other == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Advance moves the clock forward by d. Timers fall due in order, each
+// seeing Now() at its own deadline, and run on the calling goroutine.
+// Timers they schedule within the window fire too.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	end := c.now.Add(d)
+	c.mu.Unlock()
+
+	for {
+		c.mu.Lock()
+		sort.Slice(c.timers, func(i, j int) bool {
+			a, b := c.timers[i], c.timers[j]
+			if !a.at.Equal(b.at) {
+				return a.at.Before(b.at)
+			}
+			return a.seq < b.seq
+		})
+		if len(c.timers) == 0 || c.timers[0].at.After(end) {
+			c.now = end
+			c.mu.Unlock()
+			return
+		}
+		t := c.timers[0]
+		c.timers = c.timers[1:]
+		c.now = t.at
+		c.mu.Unlock()
+		t.fn()
+	}
+}
+
+// Pending returns how many timers are waiting to fire.
+func (c *FakeClock) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.timers)
+}
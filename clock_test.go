@@ -0,0 +1,104 @@
+package glyph
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFakeClockAdvance(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewFakeClock(start)
+
+	var fired []time.Duration
+	record := func() { fired = append(fired, c.Now().Sub(start)) }
+	c.AfterFunc(30*time.Millisecond, record)
+	c.AfterFunc(10*time.Millisecond, func() {
+		record()
+		c.AfterFunc(10*time.Millisecond, record) // due within the window
+	})
+	stopped := c.AfterFunc(15*time.Millisecond, record)
+	if !stopped.Stop() {
+		t.Error("Stop should report a pending timer")
+	}
+
+	c.Advance(25 * time.Millisecond)
+	if want := []time.Duration{10 * time.Millisecond, 20 * time.Millisecond}; !durationsEqual(fired, want) {
+		t.Errorf("fired at %v, want %v", fired, want)
+	}
+	if got := c.Now().Sub(start); got != 25*time.Millisecond {
+		t.Errorf("Now = +%v, want +25ms", got)
+	}
+	if c.Pending() != 1 {
+		t.Errorf("Pending = %d, want 1", c.Pending())
+	}
+}
+
+func durationsEqual(a, b []time.Duration) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestAppEveryWithFakeClock(t *testing.T) {
+	app, err := NewApp()
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock := NewFakeClock(time.Time{})
+	be := NewBufferBackend(10, 1)
+	frames := []string{"-", "\\", "|", "/"}
+	frame := 0
+	label := frames[0]
+	app.SetBackend(be).SetClock(clock).SetView(Text(&label))
+
+	stop := app.Every(100*time.Millisecond, func() {
+		frame++
+		label = frames[frame%len(frames)]
+	})
+	clock.Advance(250 * time.Millisecond)
+	app.RenderNow()
+	if frame != 2 || strings.TrimSpace(be.Frame().GetLine(0)) != "|" {
+		t.Errorf("after 250ms frame = %d, line = %q, want 2 and |", frame, be.Frame().GetLine(0))
+	}
+
+	stop()
+	clock.Advance(time.Second)
+	app.RenderNow()
+	if frame != 2 {
+		t.Errorf("ticker kept running after stop: frame = %d", frame)
+	}
+}
+
+func TestTransitionUsesTemplateClock(t *testing.T) {
+	clock := NewFakeClock(time.Time{})
+	show := false
+	panel := If(&show).Then(VBox(Text("a"), Text("b"), Text("c"), Text("d"))).Transition(Slide)
+	tmpl := Build(VBox(panel, Text("after")))
+	tmpl.SetClock(clock)
+	wireLive(tmpl)
+
+	frame := func() *Buffer {
+		buf := NewBuffer(10, 6)
+		tmpl.Execute(buf, 10, 6)
+		return buf
+	}
+	frame()
+	show = true
+	frame()
+
+	clock.Advance(Slide.Duration / 2)
+	if got := strings.TrimSpace(frame().GetLine(2)); got != "after" {
+		t.Errorf("halfway line 2 = %q, want after", got)
+	}
+	clock.Advance(Slide.Duration / 2)
+	if got := strings.TrimSpace(frame().GetLine(4)); got != "after" {
+		t.Errorf("settled line 4 = %q, want after", got)
+	}
+}
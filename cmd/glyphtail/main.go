@@ -0,0 +1,39 @@
+// glyphtail follows a log file like tail -F, with scrolling.
+//
+//	glyphtail /var/log/app.log
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	. "github.com/kungfusheep/glyph"
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: glyphtail <file>")
+		os.Exit(2)
+	}
+	path := os.Args[1]
+
+	app, err := NewApp()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	tail := TailFile(path)
+	defer tail.Close()
+
+	app.SetView(VBox(
+		HBox(Text(path).Bold(), Space(), Text("j/k C-d/C-u g/G scroll · q quit").FG(BrightBlack)),
+		Log(tail).BindVimNav().Grow(1),
+	))
+
+	app.Handle("q", app.Stop)
+	app.Handle("<C-c>", app.Stop)
+	if err := app.Run(); err != nil {
+		log.Fatal(err)
+	}
+}
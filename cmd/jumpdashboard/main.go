@@ -5,6 +5,7 @@ package main
 import (
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/kungfusheep/riffkey"
 	. "github.com/kungfusheep/glyph"
@@ -31,6 +32,11 @@ func main() {
 	selectedTab := 0
 	status := "Press 'g' for jump mode | q to quit"
 
+	now := time.Now()
+	loginAt := now.Add(-2 * time.Minute)
+	apiCallAt := now.Add(-5 * time.Minute)
+	cacheMissAt := now.Add(-8 * time.Minute)
+
 	menuItems := []menuItem{
 		{"Dashboard", "dashboard"},
 		{"Analytics", "analytics"},
@@ -172,9 +178,9 @@ func main() {
 						SpaceH(1),
 						Text("Recent Activity").FG(White).Bold(),
 						SpaceH(1),
-						jumpRow("User login - john@example.com", HBox(Text("User login  ").FG(Green), Text("john@example.com    ").FG(White), Text("2m ago").FG(BrightBlack))),
-						jumpRow("API call - GET /users", HBox(Text("API call    ").FG(Cyan), Text("GET /users          ").FG(White), Text("5m ago").FG(BrightBlack))),
-						jumpRow("Cache miss - session:abc123", HBox(Text("Cache miss  ").FG(Yellow), Text("session:abc123      ").FG(White), Text("8m ago").FG(BrightBlack))),
+						jumpRow("User login - john@example.com", HBox(Text("User login  ").FG(Green), Text("john@example.com    ").FG(White), TimeAgo(&loginAt).FG(BrightBlack))),
+						jumpRow("API call - GET /users", HBox(Text("API call    ").FG(Cyan), Text("GET /users          ").FG(White), TimeAgo(&apiCallAt).FG(BrightBlack))),
+						jumpRow("Cache miss - session:abc123", HBox(Text("Cache miss  ").FG(Yellow), Text("session:abc123      ").FG(White), TimeAgo(&cacheMissAt).FG(BrightBlack))),
 					)).
 					Case(1, VBox(
 						Text("Performance Metrics").FG(White).Bold(),
@@ -207,9 +207,10 @@ type Editor struct {
 	StatusLine string // command/message line (bottom)
 
 	// Display options
-	relativeNumber bool // show relative line numbers (like vim's relativenumber)
-	cursorLine     bool // highlight the entire cursor line (like vim's cursorline)
-	showSignColumn bool // show git gutter signs column
+	relativeNumber bool             // show relative line numbers (like vim's relativenumber)
+	cursorLine     bool             // highlight the entire cursor line (like vim's cursorline)
+	showSignColumn bool             // show git gutter signs column
+	indent         glyph.IndentFunc // autoindent for new lines (nil disables)
 
 	// Search (global)
 	searchPattern   string
@@ -609,14 +610,19 @@ func (ed *Editor) InsertNewline() {
 	after := line[ed.win().Col:]
 	ed.buf().Lines[ed.win().Cursor] = before
 
+	indent := ""
+	if ed.indent != nil {
+		indent = ed.indent(before, after)
+	}
+
 	// Insert new line after
 	newLines := make([]string, len(ed.buf().Lines)+1)
 	copy(newLines[:ed.win().Cursor+1], ed.buf().Lines[:ed.win().Cursor+1])
-	newLines[ed.win().Cursor+1] = after
+	newLines[ed.win().Cursor+1] = indent + after
 	copy(newLines[ed.win().Cursor+2:], ed.buf().Lines[ed.win().Cursor+1:])
 	ed.buf().Lines = newLines
 	ed.win().Cursor++
-	ed.win().Col = 0
+	ed.win().Col = len(indent)
 }
 
 // DeleteToLineStart deletes from cursor to start of line (C-u in insert mode)
@@ -1624,6 +1630,7 @@ func main() {
 		relativeNumber: true,
 		cursorLine:     true,
 		showSignColumn: true,
+		indent:         glyph.KeepIndent,
 		macros:         make(map[rune]riffkey.Macro),
 	}
 
@@ -10,7 +10,7 @@ import (
 	. "github.com/kungfusheep/glyph"
 )
 
-type MenuItem struct {
+type MenuEntry struct {
 	Icon     string
 	Label    string
 	Shortcut string
@@ -40,7 +40,7 @@ func main() {
 	}
 	demoName := demoNames[0]
 
-	menuItems := []MenuItem{
+	menuItems := []MenuEntry{
 		{Icon: "*", Label: "New File", Shortcut: "Ctrl+N"},
 		{Icon: "#", Label: "Open", Shortcut: "Ctrl+O"},
 		{Icon: "!", Label: "Save", Shortcut: "Ctrl+S"},
@@ -63,7 +63,7 @@ func main() {
 		Style(Style{BG: PaletteColor(235)}).
 		SelectedStyle(Style{BG: PaletteColor(240)}).
 		MarkerStyle(Style{FG: Cyan}).
-		Render(func(item *MenuItem) any {
+		Render(func(item *MenuEntry) any {
 			return HBox.Gap(1)(
 				Text(&item.Icon).FG(Yellow),
 				Text(&item.Label),
@@ -342,7 +342,7 @@ func main() {
 							Text("Right Panel").FG(Cyan).Bold(),
 							HRule(),
 							VBox.Border(BorderRounded)(
-								ForEach(&menuItems, func(item *MenuItem) any {
+								ForEach(&menuItems, func(item *MenuEntry) any {
 									return HBox.Gap(1)(
 										Text(&item.Icon),
 										Text(&item.Label),
@@ -28,6 +28,7 @@ func main() {
 		switch y % 10 {
 		case 0:
 			line = fmt.Sprintf("═══════════════════ Section %d ═══════════════════", y/10+1)
+			layer.StickyHeader(y)
 		case 1:
 			line = fmt.Sprintf("  Line %03d: %s", y, strings.Repeat("▓", 40))
 		case 2:
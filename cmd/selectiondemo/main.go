@@ -9,14 +9,14 @@ import (
 	. "github.com/kungfusheep/glyph"
 )
 
-type Command struct {
+type PaletteItem struct {
 	Icon     string
 	Name     string
 	Shortcut string
 }
 
 func main() {
-	commands := []Command{
+	commands := []PaletteItem{
 		{Icon: "[O]", Name: "Open File", Shortcut: "Ctrl+O"},
 		{Icon: "[S]", Name: "Save", Shortcut: "Ctrl+S"},
 		{Icon: "[A]", Name: "Save As", Shortcut: "Ctrl+Shift+S"},
@@ -45,7 +45,7 @@ func main() {
 		Marker("> ").
 		MaxVisible(8).
 		SelectedStyle(Style{BG: PaletteColor(236)}).
-		Render(func(cmd *Command) any {
+		Render(func(cmd *PaletteItem) any {
 			return HBox.Gap(2)(
 				Text(&cmd.Icon),
 				Text(&cmd.Name),
@@ -0,0 +1,67 @@
+// sysmon: a small system monitor on the sysmetrics package.
+package main
+
+import (
+	"log"
+	"time"
+
+	. "github.com/kungfusheep/glyph"
+	"github.com/kungfusheep/glyph/sysmetrics"
+)
+
+func main() {
+	m := sysmetrics.New()
+	procs := sysmetrics.NewProcessTable(sysmetrics.NewProcesses()).MaxVisible(15)
+	status := ""
+
+	panel := func(title string, pct *int, label *string, history *[]float64, col Color) any {
+		return VBox.Grow(1).Border(BorderRounded).Title(title)(
+			HBox.Gap(1)(Progress(pct).Width(30).FG(col), Text(label).FG(BrightBlack)),
+			Sparkline(history).Range(0, 100).FG(col),
+		)
+	}
+
+	app, err := NewApp()
+	if err != nil {
+		log.Fatal(err)
+	}
+	app.SetView(
+		VBox(
+			HBox.Gap(1)(
+				panel("cpu", &m.CPU, &m.CPUText, &m.CPUHistory, Cyan),
+				panel("memory", &m.Mem, &m.MemText, &m.MemHistory, Magenta),
+			),
+			HBox.Gap(1)(
+				VBox.Grow(1).Border(BorderRounded).Title("network")(
+					Text(&m.NetText).FG(Green),
+					Sparkline(&m.NetRxHistory).FG(Green),
+				),
+				VBox.Grow(1).Border(BorderRounded).Title("disk")(
+					HBox.Gap(1)(Progress(&m.Disk).Width(30).FG(Yellow), Text(&m.DiskText).FG(BrightBlack)),
+				),
+			),
+			procs.View(),
+			Text(&status).FG(Red),
+			Text("ctrl+q quit").FG(BrightBlack),
+		),
+	)
+
+	go func() {
+		m.Run(app.Context(), time.Second, func(fn func()) {
+			app.Post(func() {
+				fn()
+				status = ""
+				if m.Err != nil {
+					status = m.Err.Error()
+				}
+			})
+		})
+	}()
+
+	go procs.Run(app.Context(), 2*time.Second, app.Post)
+
+	app.Handle("<C-q>", app.Stop)
+	if err := app.Run(); err != nil {
+		log.Fatal(err)
+	}
+}
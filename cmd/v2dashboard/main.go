@@ -366,10 +366,8 @@ func main() {
 					Case("graphs", Text("─── Graphs Mode ───")).
 					Default(Text("Unknown view mode")),
 
-				If(&state.ShowGraph).Eq(true).Then(
-					VBox.Border(BorderRounded).Title("CPU History").BorderFG(cpuStyle.FG)(
-						MiniGraph{Values: &state.CPUHistory, Width: 60, Height: 4, Style: cpuStyle},
-					),
+				VBox.Border(BorderRounded).Title("CPU History").BorderFG(cpuStyle.FG).Collapsible(&state.ShowGraph).BindCollapse("g")(
+					MiniGraph{Values: &state.CPUHistory, Width: 60, Height: 4, Style: cpuStyle},
 				),
 			),
 		),
@@ -410,9 +408,6 @@ func main() {
 		Handle("q", func(_ riffkey.Match) {
 			app.Stop()
 		}).
-		Handle("g", func(_ riffkey.Match) {
-			state.ShowGraph = !state.ShowGraph
-		}).
 		Handle("p", func(_ riffkey.Match) {
 			state.ShowProcs = !state.ShowProcs
 		}).
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	. "github.com/kungfusheep/glyph"
+	"github.com/kungfusheep/glyph/format"
 	"github.com/kungfusheep/riffkey"
 )
 
@@ -77,7 +78,7 @@ func (m *Market) update() {
 	m.Bid = formatPrice(m.bidVal)
 	m.Ask = formatPrice(m.askVal)
 	m.Spread = fmt.Sprintf("%.2f", (m.askVal-m.bidVal)*100)
-	m.Volume = formatVolume(m.volumeVal)
+	m.Volume = format.SI(float64(m.volumeVal), 1)
 	m.High = formatPrice(m.highVal)
 	m.Low = formatPrice(m.lowVal)
 	m.LastTrade = time.Now().Format("15:04:05")
@@ -116,15 +117,6 @@ func formatPrice(p float64) string {
 	return fmt.Sprintf("%.6f", p)
 }
 
-func formatVolume(v int) string {
-	if v >= 1_000_000 {
-		return fmt.Sprintf("%.1fM", float64(v)/1_000_000)
-	} else if v >= 1000 {
-		return fmt.Sprintf("%.1fK", float64(v)/1000)
-	}
-	return fmt.Sprintf("%d", v)
-}
-
 func main() {
 	app, err := NewApp()
 	if err != nil {
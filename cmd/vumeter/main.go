@@ -0,0 +1,72 @@
+// vumeter: level meters driven at audio-buffer rate, redrawn with
+// RenderNow so each update flushes only the rows that moved.
+package main
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	. "github.com/kungfusheep/glyph"
+)
+
+func main() {
+	master := StereoMeter().Scale().Readout()
+	buses := LevelMeter(4).Labels("kick", "bass", "keys", "vox").Hold(800 * time.Millisecond)
+	stats := ""
+
+	app, err := NewApp()
+	if err != nil {
+		log.Fatal(err)
+	}
+	app.SetView(
+		VBox(
+			VBox.Border(BorderRounded).Title("master")(master),
+			VBox.Border(BorderRounded).Title("buses")(buses),
+			Text(&stats).FG(BrightBlack),
+			Text("ctrl+q quit").FG(BrightBlack),
+		),
+	)
+
+	// a 10ms "audio buffer" of made-up signal per tick
+	go func() {
+		tick := time.NewTicker(10 * time.Millisecond)
+		defer tick.Stop()
+		start := time.Now()
+		frames := 0
+		for now := range tick.C {
+			t := now.Sub(start).Seconds()
+			beat := math.Mod(t, 0.5)
+			kick := math.Exp(-beat*12) * 0.9
+			bass := 0.35 + 0.15*math.Sin(t*1.3)
+			keys := 0.2 + 0.2*math.Abs(math.Sin(t*0.7))
+			vox := 0.0
+			if math.Mod(t, 8) < 5 {
+				vox = 0.4 + 0.3*rand.Float64()
+			}
+			buses.Set(0, kick)
+			buses.Set(1, bass)
+			buses.Set(2, keys)
+			buses.Set(3, vox)
+			mix := min(kick+bass*0.6+keys*0.4+vox*0.7, 1.2) * 0.8
+			master.Set(0, mix*(0.95+0.05*rand.Float64()))
+			master.Set(1, mix*(0.95+0.05*rand.Float64()))
+
+			frames++
+			if frames%50 == 0 {
+				s := GetFlushStats()
+				app.Post(func() {
+					stats = fmt.Sprintf("last frame: %d rows dirty, %d changed, %d bytes", s.DirtyRows, s.ChangedRows, s.Bytes)
+				})
+			}
+			app.RenderNow()
+		}
+	}()
+
+	app.Handle("<C-q>", app.Stop)
+	if err := app.Run(); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,299 @@
+package glyph
+
+import (
+	"strings"
+
+	"github.com/kungfusheep/riffkey"
+	"github.com/mattn/go-runewidth"
+)
+
+// CommandLineC is a vim-style command line: one row, usually at the bottom
+// of the screen, where a Commands line is typed after a ":" and run with
+// Enter.
+//
+//	hist, _ := OpenHistory(historyPath, 500)
+//	VBox(
+//	    editor.Grow(1),
+//	    CommandLine(app.Commands()).History(hist).BindOpen(":"),
+//	)
+//
+// Tab completes the word being typed, command names first and then
+// arguments from the command's Completer, listing the choices in a
+// wildmenu row above the line; Shift-Tab steps back. Up and down walk the
+// history through lines that start with what's typed. Ctrl-R searches the
+// history with an fzf query, newest first, in a popup over the line:
+// Ctrl-R again or up/down choose, Enter or Tab takes the line to edit and
+// Esc puts back what was there.
+//
+// While closed the row shows the last error a command returned, which
+// also goes to the registry's OnError.
+type CommandLineC struct {
+	prompt    cmdPrompt
+	promptStr string
+	open      bool
+	message   string
+
+	// walking the history with up/down: the index of the line shown, and
+	// what was typed before the walk started
+	histPos    int
+	histPrefix string
+
+	style      Style
+	errorStyle Style
+	wildStyle  Style
+	selStyle   Style
+
+	x, y, w int16 // where the line was last drawn
+
+	router           *riffkey.Router
+	push             func(*riffkey.Router)
+	pop              func()
+	declaredBindings []binding
+}
+
+// CommandLine creates a command line over cmds.
+func CommandLine(cmds *Commands) *CommandLineC {
+	cl := &CommandLineC{
+		promptStr:  ":",
+		errorStyle: Style{FG: Red},
+		wildStyle:  Style{Attr: AttrInverse},
+		selStyle:   Style{FG: Black, BG: Yellow},
+		histPos:    -1,
+	}
+	cl.prompt = cmdPrompt{cmds: cmds, changed: func() {
+		if !cl.walking() {
+			cl.histPos = -1 // typing starts a new walk
+		}
+	}}
+	return cl
+}
+
+// Prompt sets the text before the line. ":" by default.
+func (cl *CommandLineC) Prompt(s string) *CommandLineC {
+	cl.promptStr = s
+	return cl
+}
+
+// History records the lines run in h, for up/down and Ctrl-R.
+func (cl *CommandLineC) History(h *History) *CommandLineC {
+	cl.prompt.history = h
+	return cl
+}
+
+// Style sets the line's style.
+func (cl *CommandLineC) Style(s Style) *CommandLineC {
+	cl.style = s
+	return cl
+}
+
+// ErrorStyle sets the style of an error shown while closed. Red by default.
+func (cl *CommandLineC) ErrorStyle(s Style) *CommandLineC {
+	cl.errorStyle = s
+	return cl
+}
+
+// WildmenuStyle sets the completion row's style, and the style of the
+// choice Tab is on. Inverse and black on yellow by default; the history
+// popup uses them too.
+func (cl *CommandLineC) WildmenuStyle(row, selected Style) *CommandLineC {
+	cl.wildStyle, cl.selStyle = row, selected
+	return cl
+}
+
+// BindOpen registers a key that opens the command line.
+func (cl *CommandLineC) BindOpen(key string) *CommandLineC {
+	cl.declaredBindings = append(cl.declaredBindings, binding{key, cl.Open})
+	return cl
+}
+
+// Open opens the command line, empty.
+func (cl *CommandLineC) Open() {
+	if !cl.open && cl.push != nil {
+		cl.push(cl.keys())
+	}
+	cl.open = true
+	cl.message = ""
+	cl.histPos = -1
+	cl.prompt.recalling = false
+	cl.prompt.set("")
+}
+
+// Close closes the command line without running it.
+func (cl *CommandLineC) Close() {
+	if !cl.open {
+		return
+	}
+	cl.open = false
+	if cl.pop != nil {
+		cl.pop()
+	}
+}
+
+// IsOpen reports whether the command line is open.
+func (cl *CommandLineC) IsOpen() bool { return cl.open }
+
+// Line returns what's been typed.
+func (cl *CommandLineC) Line() string { return cl.prompt.line }
+
+// Message returns the error the last command returned, or "".
+func (cl *CommandLineC) Message() string { return cl.message }
+
+// enter closes the line and runs it.
+func (cl *CommandLineC) enter() {
+	line := cl.prompt.line
+	cl.Close()
+	if err := cl.prompt.run(line); err != nil {
+		cl.message = err.Error()
+		cl.prompt.cmds.report(err)
+	}
+}
+
+// walk steps through the history lines starting with what was typed,
+// older for dir -1 and newer for 1, ending back at what was typed.
+func (cl *CommandLineC) walk(dir int) {
+	h := cl.prompt.history
+	if h == nil {
+		return
+	}
+	lines := h.Lines()
+	if cl.histPos < 0 {
+		cl.histPos, cl.histPrefix = len(lines), cl.prompt.line
+	}
+	for i := cl.histPos + dir; i >= 0 && i <= len(lines); i += dir {
+		if i == len(lines) {
+			cl.histPos = i
+			cl.prompt.set(cl.histPrefix)
+			return
+		}
+		if strings.HasPrefix(lines[i], cl.histPrefix) {
+			cl.histPos = i
+			cl.prompt.set(lines[i])
+			return
+		}
+	}
+}
+
+// keys returns the router pushed while the line is open.
+func (cl *CommandLineC) keys() *riffkey.Router {
+	if cl.router != nil {
+		return cl.router
+	}
+	r := riffkey.NewRouter().NoCounts()
+	cl.prompt.bind(r, promptKeys{
+		enter:  cl.enter,
+		escape: cl.Close,
+		up:     func() { cl.walk(-1) },
+		down:   func() { cl.walk(1) },
+	})
+	cl.router = r
+	return r
+}
+
+// walking reports whether the line is one walk put there.
+func (cl *CommandLineC) walking() bool {
+	if cl.histPos < 0 || cl.prompt.history == nil {
+		return false
+	}
+	lines := cl.prompt.history.Lines()
+	if cl.histPos == len(lines) {
+		return cl.prompt.line == cl.histPrefix
+	}
+	return cl.histPos < len(lines) && cl.prompt.line == lines[cl.histPos]
+}
+
+func (cl *CommandLineC) bindings() []binding {
+	return cl.declaredBindings
+}
+
+func (cl *CommandLineC) setModal(push func(*riffkey.Router), pop func()) {
+	cl.push, cl.pop = push, pop
+}
+
+func (t *Template) compileCommandLineC(cl *CommandLineC, parent int16, depth int) int16 {
+	measure := func(availW int16) (w, h int16) { return max(availW, 0), 1 }
+	render := func(buf *Buffer, x, y, w, h int16) {
+		cl.x, cl.y, cl.w = x, y, w
+		cl.render(buf)
+		if cl.open && (cl.prompt.recalling || len(cl.prompt.comps) > 0 && cl.prompt.line != "") {
+			t.pendingOverlays = append(t.pendingOverlays, pendingOverlay{draw: cl.drawAbove})
+		}
+	}
+	return t.compileCustom(Widget(measure, render), parent, depth)
+}
+
+func (cl *CommandLineC) render(buf *Buffer) {
+	x, y, w := int(cl.x), int(cl.y), int(cl.w)
+	buf.FillRect(x, y, w, 1, Cell{Rune: ' ', Style: cl.style})
+	pr := &cl.prompt
+	switch {
+	case !cl.open:
+		buf.WriteStringFast(x, y, cl.message, cl.errorStyle, w)
+	case pr.recalling:
+		label := "(history) "
+		buf.WriteStringFast(x, y, label, cl.style, w)
+		lw := runewidth.StringWidth(label)
+		drawLine(buf, x+lw, y, w-lw, pr.recall.query, pr.recall.cursor, cl.style)
+	default:
+		buf.WriteStringFast(x, y, cl.promptStr, cl.style, w)
+		pw := runewidth.StringWidth(cl.promptStr)
+		drawLine(buf, x+pw, y, w-pw, pr.line, pr.cursor, cl.style)
+	}
+}
+
+// drawAbove draws the wildmenu, or the history popup, over the rows above
+// the line.
+func (cl *CommandLineC) drawAbove(buf *Buffer, screenW, screenH int16) {
+	x, y, w := int(cl.x), int(cl.y), int(cl.w)
+	pr := &cl.prompt
+	if pr.recalling {
+		rows := min(len(pr.recall.matches), 8, y-2)
+		if rows <= 0 {
+			return
+		}
+		top := y - rows - 2
+		buf.FillRect(x, top, w, rows+2, Cell{Rune: ' ', Style: cl.style})
+		buf.DrawBorder(x, top, w, rows+2, BorderSingle, cl.style)
+		pr.drawRecall(buf, x+1, top+1, w-2, rows, cl.style, cl.selStyle)
+		return
+	}
+	if y < 1 {
+		return
+	}
+	cl.drawWildmenu(buf, x, y-1, w)
+}
+
+// drawWildmenu lays the completions out in a row, scrolled to keep the
+// selected one in view.
+func (cl *CommandLineC) drawWildmenu(buf *Buffer, x, y, w int) {
+	pr := &cl.prompt
+	buf.FillRect(x, y, w, 1, Cell{Rune: ' ', Style: cl.wildStyle})
+	first, used := 0, 0
+	sel := max(pr.compSel, 0)
+	for i := 0; i <= sel; i++ {
+		used += runewidth.StringWidth(pr.comps[i]) + 2
+		for used > w && first < i {
+			used -= runewidth.StringWidth(pr.comps[first]) + 2
+			first++
+		}
+	}
+	cx := x
+	if first > 0 {
+		buf.WriteStringFast(cx, y, "<", cl.wildStyle, 1)
+		cx++
+	}
+	for i := first; i < len(pr.comps) && cx < x+w; i++ {
+		style := cl.wildStyle
+		if i == pr.compSel {
+			style = cl.selStyle
+		}
+		cx++
+		label := pr.comps[i]
+		lw := runewidth.StringWidth(label)
+		if cx+lw > x+w-1 && i < len(pr.comps)-1 {
+			buf.WriteStringFast(x+w-1, y, ">", cl.wildStyle, 1)
+			break
+		}
+		buf.WriteStringFast(cx, y, label, style, x+w-cx)
+		cx += lw + 1
+	}
+}
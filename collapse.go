@@ -0,0 +1,81 @@
+package glyph
+
+// Collapse markers shown before the title of a Collapsible panel.
+const (
+	collapseOpen   = "▾ "
+	collapseClosed = "▸ "
+)
+
+// collapsible is the state of a bordered container that can fold down to
+// its title bar.
+type collapsible struct {
+	open        *bool
+	closedTitle string
+	toggle      func()
+	trans       *transition // the body's slide, which also scales flex
+}
+
+// collapseBody wraps a panel's children so they slide in and out with
+// *open.
+func collapseBody(open *bool, body any) any {
+	return If(open).Then(body).Transition(Slide)
+}
+
+// markCollapsible attaches collapse state to the container at idx, whose
+// only child is the body built by collapseBody.
+func (t *Template) markCollapsible(idx int16, open *bool) {
+	op := &t.ops[idx]
+	c := &collapsible{
+		open:        open,
+		closedTitle: collapseClosed + op.Title,
+		toggle:      func() { *open = !*open },
+	}
+	for i := op.ChildStart; i < op.ChildEnd; i++ {
+		if t.ops[i].Parent == idx && t.ops[i].Kind == OpIf {
+			c.trans = t.ops[i].trans
+		}
+	}
+	op.Title = collapseOpen + op.Title
+	op.Collapse = c
+}
+
+// title returns the border title, with a closed marker if the container
+// is collapsed.
+func (op *Op) title() string {
+	if op.Collapse != nil && !*op.Collapse.open {
+		return op.Collapse.closedTitle
+	}
+	return op.Title
+}
+
+// flexGrow returns FlexGrow, scaled down while a collapsible container is
+// closing so the space it gives up slides to its siblings.
+func (op *Op) flexGrow() float32 {
+	if op.Collapse == nil || op.FlexGrow == 0 {
+		return op.FlexGrow
+	}
+	if tr := op.Collapse.trans; tr != nil && tr.started {
+		return op.FlexGrow * float32(tr.progress())
+	}
+	if !*op.Collapse.open {
+		return 0
+	}
+	return op.FlexGrow
+}
+
+// collapseJump makes the title marker at x,y a jump target that toggles
+// the container.
+func (t *Template) collapseJump(buf *Buffer, op *Op, x, y int16) {
+	if op.Collapse == nil || t.app == nil || !t.app.JumpModeActive() {
+		return
+	}
+	t.jumpTarget(buf, x, y, op.Collapse.toggle, Style{})
+}
+
+// collapseBindings returns the key binding for BindCollapse, if any.
+func collapseBindings(open *bool, key string) []binding {
+	if open == nil || key == "" {
+		return nil
+	}
+	return []binding{{pattern: key, handler: func() { *open = !*open }}}
+}
@@ -0,0 +1,83 @@
+package glyph
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCollapsiblePanel(t *testing.T) {
+	open := true
+	clk := NewFakeClock(time.Unix(0, 0))
+	tmpl := Build(VBox(
+		VBox.Border(BorderSingle).Title("Logs").Collapsible(&open).Grow(1)(Text("line")),
+		VBox.Border(BorderSingle).Title("Status").Grow(1)(Text("ok")),
+	))
+	tmpl.SetClock(clk)
+
+	frame := func() *Buffer {
+		buf := NewBuffer(20, 10)
+		tmpl.Execute(buf, 20, 10)
+		return buf
+	}
+
+	buf := frame()
+	if got := buf.GetLine(0); !strings.HasPrefix(got, "┌─ ▾ Logs ─") {
+		t.Errorf("open title = %q", got)
+	}
+	if got := strings.TrimSpace(buf.GetLine(1)); got != "│line              │" {
+		t.Errorf("open body = %q", got)
+	}
+	if got := buf.GetLine(5); !strings.HasPrefix(got, "┌─ Status") {
+		t.Errorf("status top = %q, want split in half", got)
+	}
+
+	open = false
+	frame()
+	clk.Advance(Slide.Duration)
+	buf = frame()
+	if got := buf.GetLine(0); !strings.HasPrefix(got, "┌─ ▸ Logs ─") {
+		t.Errorf("closed title = %q", got)
+	}
+	if got := buf.GetLine(1); !strings.HasPrefix(got, "└") {
+		t.Errorf("closed panel line 1 = %q, want bottom border", got)
+	}
+	if got := buf.GetLine(2); !strings.HasPrefix(got, "┌─ Status") {
+		t.Errorf("status should take the freed space, line 2 = %q", got)
+	}
+	if got := buf.GetLine(9); !strings.HasPrefix(got, "└") {
+		t.Errorf("status bottom = %q", got)
+	}
+}
+
+func TestCollapsibleJumpTarget(t *testing.T) {
+	open := true
+	app, _ := jumpTestApp(t, VBox(
+		VBox.Border(BorderSingle).Title("Logs").Collapsible(&open)(Text("line")),
+	))
+
+	app.EnterJumpMode()
+	targets := app.JumpMode().Targets
+	if len(targets) != 1 || targets[0].X != 3 || targets[0].Y != 0 {
+		t.Fatalf("targets = %+v, want the marker at 3,0", targets)
+	}
+	targets[0].OnSelect()
+	if open {
+		t.Error("jump target should collapse the panel")
+	}
+	app.ExitJumpMode()
+}
+
+func TestCollapseBindingCollected(t *testing.T) {
+	open := false
+	tmpl := Build(VBox(
+		VBox.Border(BorderSingle).Collapsible(&open).BindCollapse("c")(Text("line")),
+	))
+	if len(tmpl.pendingBindings) != 1 || tmpl.pendingBindings[0].pattern != "c" {
+		t.Fatalf("bindings = %+v, want one for c", tmpl.pendingBindings)
+	}
+	tmpl.pendingBindings[0].handler.(func())()
+	if !open {
+		t.Error("bound key should toggle the panel")
+	}
+}
@@ -0,0 +1,406 @@
+package glyph
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+// Commands is a registry of named commands run from a line of text, the way
+// ":write notes.md" runs in vim. A command's function declares its
+// arguments, and each word after the name is parsed into its parameter's
+// type:
+//
+//	cmds := NewCommands()
+//	cmds.Add("write", "Write the buffer", func(path string) error { ... })
+//	cmds.Add("goto", "Go to a line", func(line int) { ... })
+//	cmds.Add("set", "Set options", func(opts ...string) { ... })
+//	cmds.Add("quit", "Quit", app.Stop).Key("<C-q>")
+//
+//	err := cmds.Run("goto 42")
+//
+// Parameters may be strings, bools, runes, time.Durations or any int,
+// uint or float type, and the last may be variadic to take the rest of
+// the words. Words are split on spaces, and quotes keep spaces in a word.
+// A function may return an error, which Run hands back.
+//
+// A name matches exactly or by a prefix only one command has, so "wr" runs
+// "write". Find ranks commands against an fzf query, which is what
+// CommandPalette lists.
+type Commands struct {
+	list    []*Command
+	router  *riffkey.Router
+	after   func()
+	onError func(error)
+}
+
+// Command is one entry in a Commands registry.
+type Command struct {
+	Name string
+	Desc string
+
+	key        string
+	fn         reflect.Value
+	params     []reflect.Type
+	variadic   bool
+	completers []Completer
+	cmds       *Commands
+}
+
+// NewCommands creates an empty registry. App.Command keeps one per app,
+// bound to the app's router.
+func NewCommands() *Commands {
+	return &Commands{}
+}
+
+var (
+	errorType    = reflect.TypeOf((*error)(nil)).Elem()
+	durationType = reflect.TypeOf(time.Duration(0))
+	runeType     = reflect.TypeOf(rune(0))
+)
+
+// Add registers a command. fn is any function whose parameters are of the
+// types listed on Commands, returning nothing or an error. A command added
+// under a name already taken replaces it. Add panics if fn isn't such a
+// function.
+func (c *Commands) Add(name, desc string, fn any) *Command {
+	v := reflect.ValueOf(fn)
+	ft := v.Type()
+	if ft.Kind() != reflect.Func {
+		panic(fmt.Sprintf("Commands.Add %q: %T is not a function", name, fn))
+	}
+	if ft.NumOut() > 1 || ft.NumOut() == 1 && ft.Out(0) != errorType {
+		panic(fmt.Sprintf("Commands.Add %q: function may only return an error", name))
+	}
+	cmd := &Command{Name: name, Desc: desc, fn: v, variadic: ft.IsVariadic(), cmds: c}
+	for i := 0; i < ft.NumIn(); i++ {
+		t := ft.In(i)
+		if cmd.variadic && i == ft.NumIn()-1 {
+			t = t.Elem()
+		}
+		if !argSupported(t) {
+			panic(fmt.Sprintf("Commands.Add %q: unsupported parameter type %v", name, t))
+		}
+		cmd.params = append(cmd.params, t)
+	}
+
+	for i, old := range c.list {
+		if old.Name == name {
+			c.list[i] = cmd
+			return cmd
+		}
+	}
+	c.list = append(c.list, cmd)
+	return cmd
+}
+
+// Bind makes command keys work on router, calling after once a command run
+// from a key has finished. Keys given before Bind are bound now.
+func (c *Commands) Bind(router *riffkey.Router, after func()) *Commands {
+	c.router, c.after = router, after
+	for _, cmd := range c.list {
+		if cmd.key != "" {
+			cmd.bind()
+		}
+	}
+	return c
+}
+
+// OnError sets what happens to an error from a command run by a key or
+// the palette, where there's no caller to hand it to.
+func (c *Commands) OnError(fn func(error)) *Commands {
+	c.onError = fn
+	return c
+}
+
+// All returns the commands in the order they were added.
+func (c *Commands) All() []*Command {
+	return c.list
+}
+
+// Get returns the command called name, or one that name is the only
+// prefix of.
+func (c *Commands) Get(name string) (*Command, error) {
+	var found *Command
+	for _, cmd := range c.list {
+		if cmd.Name == name {
+			return cmd, nil
+		}
+		if strings.HasPrefix(cmd.Name, name) {
+			if found != nil {
+				return nil, fmt.Errorf("ambiguous command: %s", name)
+			}
+			found = cmd
+		}
+	}
+	if found == nil || name == "" {
+		return nil, fmt.Errorf("unknown command: %s", name)
+	}
+	return found, nil
+}
+
+// Run runs a line of text: a command name and its arguments.
+func (c *Commands) Run(line string) error {
+	words, err := splitCommandLine(line)
+	if err != nil {
+		return err
+	}
+	if len(words) == 0 {
+		return nil
+	}
+	cmd, err := c.Get(words[0])
+	if err != nil {
+		return err
+	}
+	return cmd.Call(words[1:]...)
+}
+
+// Find returns the commands whose name or description matches an fzf
+// query, best first. An empty query returns them all in order.
+func (c *Commands) Find(query string) []*Command {
+	q := ParseFzfQuery(query)
+	if q.Empty() {
+		return append([]*Command(nil), c.list...)
+	}
+	type scored struct {
+		cmd   *Command
+		score int
+	}
+	var hits []scored
+	for _, cmd := range c.list {
+		score, ok := q.Score(cmd.Name)
+		if !ok {
+			if score, ok = q.Score(cmd.Name + " " + cmd.Desc); !ok {
+				continue
+			}
+		} else {
+			score += 1000 // a hit on the name alone beats one on the description
+		}
+		hits = append(hits, scored{cmd, score})
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].score > hits[j].score })
+	out := make([]*Command, len(hits))
+	for i, h := range hits {
+		out[i] = h.cmd
+	}
+	return out
+}
+
+// report hands err to the OnError handler.
+func (c *Commands) report(err error) {
+	if err != nil && c.onError != nil {
+		c.onError(err)
+	}
+}
+
+// Key binds the command to a key pattern on the registry's router, under
+// the command's name so it's listed by Cheatsheet and can be rebound. The
+// command runs with no arguments.
+func (cmd *Command) Key(pattern string) *Command {
+	cmd.key = pattern
+	if cmd.cmds.router != nil {
+		cmd.bind()
+	}
+	return cmd
+}
+
+func (cmd *Command) bind() {
+	c := cmd.cmds
+	c.router.HandleNamed(cmd.Name, cmd.key, func(riffkey.Match) {
+		c.report(cmd.Call())
+		if c.after != nil {
+			c.after()
+		}
+	})
+}
+
+// KeyPattern returns the key the command is bound to now, or "" if none.
+// Rebinding the name on the router shows here.
+func (cmd *Command) KeyPattern() string {
+	if r := cmd.cmds.router; r != nil {
+		for _, b := range r.Bindings() {
+			if b.Name == cmd.Name {
+				return b.Pattern
+			}
+		}
+	}
+	return cmd.key
+}
+
+// Args returns the number of arguments the command needs, not counting a
+// variadic last parameter.
+func (cmd *Command) Args() int {
+	if cmd.variadic {
+		return len(cmd.params) - 1
+	}
+	return len(cmd.params)
+}
+
+// Usage describes the command's arguments by type, as in
+// "goto <int>" or "set <string>...".
+func (cmd *Command) Usage() string {
+	var sb strings.Builder
+	sb.WriteString(cmd.Name)
+	for i, t := range cmd.params {
+		sb.WriteString(" <")
+		sb.WriteString(argTypeName(t))
+		sb.WriteString(">")
+		if cmd.variadic && i == len(cmd.params)-1 {
+			sb.WriteString("...")
+		}
+	}
+	return sb.String()
+}
+
+// Call runs the command with args parsed into its parameters.
+func (cmd *Command) Call(args ...string) error {
+	need := cmd.Args()
+	if len(args) < need || !cmd.variadic && len(args) > need {
+		return fmt.Errorf("usage: %s", cmd.Usage())
+	}
+	in := make([]reflect.Value, len(args))
+	for i, a := range args {
+		t := cmd.params[min(i, len(cmd.params)-1)]
+		v, err := parseArg(a, t)
+		if err != nil {
+			return fmt.Errorf("%s: argument %d: %w", cmd.Name, i+1, err)
+		}
+		in[i] = v
+	}
+	out := cmd.fn.Call(in)
+	if len(out) == 1 && !out[0].IsNil() {
+		return out[0].Interface().(error)
+	}
+	return nil
+}
+
+func argSupported(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func argTypeName(t reflect.Type) string {
+	switch {
+	case t == runeType:
+		return "char"
+	case t == durationType:
+		return "duration"
+	}
+	switch t.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "int"
+	}
+	return t.Kind().String()
+}
+
+// parseArg converts a word to a parameter of type t.
+func parseArg(s string, t reflect.Type) (reflect.Value, error) {
+	v := reflect.New(t).Elem()
+	switch {
+	case t == runeType:
+		r, size := utf8.DecodeRuneInString(s)
+		if size == 0 || size != len(s) {
+			return v, fmt.Errorf("expected one character, got %q", s)
+		}
+		v.SetInt(int64(r))
+		return v, nil
+	case t == durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return v, fmt.Errorf("expected a duration, got %q", s)
+		}
+		v.SetInt(int64(d))
+		return v, nil
+	}
+	switch t.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			switch strings.ToLower(s) {
+			case "yes", "on":
+				b, err = true, nil
+			case "no", "off":
+				b, err = false, nil
+			default:
+				return v, fmt.Errorf("expected true or false, got %q", s)
+			}
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, t.Bits())
+		if err != nil {
+			return v, fmt.Errorf("expected an integer, got %q", s)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, t.Bits())
+		if err != nil {
+			return v, fmt.Errorf("expected a positive integer, got %q", s)
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, t.Bits())
+		if err != nil {
+			return v, fmt.Errorf("expected a number, got %q", s)
+		}
+		v.SetFloat(f)
+	}
+	return v, nil
+}
+
+var errUnclosedQuote = errors.New("unclosed quote")
+
+// splitCommandLine splits a line into words on spaces, keeping quoted
+// runs together.
+func splitCommandLine(line string) ([]string, error) {
+	var words []string
+	var sb strings.Builder
+	inWord := false
+	var quote rune
+	for _, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				sb.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, sb.String())
+				sb.Reset()
+				inWord = false
+			}
+		default:
+			sb.WriteRune(r)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, errUnclosedQuote
+	}
+	if inWord {
+		words = append(words, sb.String())
+	}
+	return words, nil
+}
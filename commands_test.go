@@ -0,0 +1,215 @@
+package glyph
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+func TestCommandsRun(t *testing.T) {
+	cmds := NewCommands()
+	var got []any
+	cmds.Add("goto", "Go to a line", func(line int) { got = append(got, line) })
+	cmds.Add("write", "Write the buffer", func(path string) error {
+		if path == "/" {
+			return errors.New("is a directory")
+		}
+		got = append(got, path)
+		return nil
+	})
+	cmds.Add("wait", "Sleep", func(d time.Duration, loud bool) { got = append(got, d, loud) })
+	cmds.Add("mark", "Set a mark", func(r rune) { got = append(got, r) })
+	cmds.Add("set", "Set options", func(scale float64, opts ...string) { got = append(got, scale, opts) })
+
+	for _, line := range []string{
+		"goto 42",
+		`write "my notes.md"`,
+		"wr notes.md",
+		"wait 2s on",
+		"mark ●",
+		"set 1.5 wrap 'tab stop'",
+		"",
+	} {
+		if err := cmds.Run(line); err != nil {
+			t.Errorf("Run(%q): %v", line, err)
+		}
+	}
+	want := []any{42, "my notes.md", "notes.md", 2 * time.Second, true, '●', 1.5, []string{"wrap", "tab stop"}}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if s, ok := want[i].([]string); ok {
+			if g := got[i].([]string); strings.Join(g, "|") != strings.Join(s, "|") {
+				t.Errorf("arg %d = %q, want %q", i, g, s)
+			}
+		} else if got[i] != want[i] {
+			t.Errorf("arg %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	for line, want := range map[string]string{
+		"goto x":        "goto: argument 1: expected an integer",
+		"goto":          "usage: goto <int>",
+		"goto 1 2":      "usage: goto <int>",
+		"write /":       "is a directory",
+		"w x":           "ambiguous command: w",
+		"nope":          "unknown command: nope",
+		`write "a`:      "unclosed quote",
+		"mark ab":       "expected one character",
+		"set x":         "expected a number",
+		"wait 2s maybe": "expected true or false",
+	} {
+		err := cmds.Run(line)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Run(%q) = %v, want %q", line, err, want)
+		}
+	}
+
+	if u := cmds.All()[4].Usage(); u != "set <number> <string>..." {
+		t.Errorf("usage %q", u)
+	}
+}
+
+func TestCommandsAddRejects(t *testing.T) {
+	for _, fn := range []any{42, func() int { return 0 }, func(x []int) {}} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Add(%T) didn't panic", fn)
+				}
+			}()
+			NewCommands().Add("x", "", fn)
+		}()
+	}
+}
+
+func TestCommandsFind(t *testing.T) {
+	cmds := NewCommands()
+	cmds.Add("write", "Save the buffer to disk", func() {})
+	cmds.Add("quit", "Leave without saving", func() {})
+	cmds.Add("wrap", "Toggle line wrap", func() {})
+
+	names := func(list []*Command) string {
+		var s []string
+		for _, c := range list {
+			s = append(s, c.Name)
+		}
+		return strings.Join(s, ",")
+	}
+	if got := names(cmds.Find("")); got != "write,quit,wrap" {
+		t.Errorf("empty query = %s", got)
+	}
+	if got := names(cmds.Find("wr")); got != "write,wrap" && got != "wrap,write" {
+		t.Errorf("wr = %s", got)
+	}
+	// a hit on the name ranks above one only in the description
+	if got := names(cmds.Find("sav")); got != "write,quit" && got != "quit,write" {
+		t.Errorf("sav = %s", got)
+	}
+	cmds.Add("save", "", func() {})
+	if got := names(cmds.Find("sav")); !strings.HasPrefix(got, "save,") {
+		t.Errorf("sav with a save command = %s", got)
+	}
+}
+
+func TestCommandKeys(t *testing.T) {
+	router := riffkey.NewRouter()
+	cmds := NewCommands()
+	var ran, rendered int
+	cmds.Add("file.save", "Save", func() { ran++ }).Key("<C-s>")
+	var errs []error
+	cmds.Add("fail", "", func() error { return errors.New("boom") }).Key("x")
+	cmds.OnError(func(err error) { errs = append(errs, err) })
+	cmds.Bind(router, func() { rendered++ }) // keys given before Bind are bound now
+
+	in := riffkey.NewInput(router)
+	in.Dispatch(riffkey.Key{Rune: 's', Mod: riffkey.ModCtrl})
+	in.Dispatch(riffkey.Key{Rune: 'x'})
+	if ran != 1 || rendered != 2 || len(errs) != 1 {
+		t.Fatalf("ran=%d rendered=%d errs=%v", ran, rendered, errs)
+	}
+
+	if k := cmds.All()[0].KeyPattern(); k != "<C-s>" {
+		t.Errorf("KeyPattern = %q", k)
+	}
+	// bound under the command's name, so the cheatsheet lists it
+	groups := Cheatsheet(router).groups()
+	if len(groups) != 2 || groups[1].name != "File" || groups[1].rows[0].label != "Save" {
+		t.Errorf("cheatsheet groups %+v", groups)
+	}
+}
+
+func TestCommandPalette(t *testing.T) {
+	cmds := NewCommands()
+	var log []string
+	cmds.Add("write", "Write the buffer", func() { log = append(log, "write") }).Key("<C-s>")
+	cmds.Add("goto", "Go to a line", func(n int) { log = append(log, "goto") })
+	cmds.Add("quit", "Quit", func() { log = append(log, "quit") })
+	cmds.Bind(riffkey.NewRouter(), nil)
+
+	p := CommandPalette(cmds).Width(40).BindOpen(":")
+	tmpl := Build(VBox(Text("body"), p))
+	if len(tmpl.pendingBindings) != 1 || len(tmpl.pendingModals) != 1 {
+		t.Fatalf("bindings not collected")
+	}
+
+	var stack []*riffkey.Router
+	p.setModal(func(r *riffkey.Router) { stack = append(stack, r) }, func() { stack = stack[:len(stack)-1] })
+	tmpl.pendingBindings[0].handler.(func())()
+	if !p.IsOpen() || len(stack) != 1 {
+		t.Fatalf("open=%v stack=%d", p.IsOpen(), len(stack))
+	}
+
+	lines := messageLines(tmpl, 50, 12)
+	screen := strings.Join(lines, "\n")
+	for _, want := range []string{"> ", "write", "Write the buffer", "Ctrl+S", "goto", "quit"} {
+		if !strings.Contains(screen, want) {
+			t.Errorf("palette missing %q:\n%s", want, screen)
+		}
+	}
+
+	in := riffkey.NewInput(stack[0])
+	typed := func(s string) {
+		for _, r := range s {
+			in.Dispatch(riffkey.Key{Rune: r})
+		}
+	}
+	typed("qu")
+	if sel := p.Selected(); sel == nil || sel.Name != "quit" {
+		t.Fatalf("selected %v after qu", sel)
+	}
+	in.Dispatch(riffkey.Key{Special: riffkey.SpecialEnter})
+	if p.IsOpen() || len(log) != 1 || log[0] != "quit" || len(stack) != 0 {
+		t.Fatalf("open=%v log=%v stack=%d", p.IsOpen(), log, len(stack))
+	}
+
+	// a command with arguments is filled in to be completed
+	p.Open()
+	in = riffkey.NewInput(stack[0])
+	typed("got")
+	in.Dispatch(riffkey.Key{Special: riffkey.SpecialEnter})
+	if !p.IsOpen() || p.Query() != "goto " {
+		t.Fatalf("open=%v query=%q", p.IsOpen(), p.Query())
+	}
+	typed("12")
+	in.Dispatch(riffkey.Key{Special: riffkey.SpecialEnter})
+	if p.IsOpen() || len(log) != 2 || log[1] != "goto" {
+		t.Fatalf("open=%v log=%v", p.IsOpen(), log)
+	}
+
+	// nothing matches
+	p.Open()
+	in = riffkey.NewInput(stack[0])
+	typed("zzz")
+	if !strings.Contains(strings.Join(messageLines(tmpl, 50, 12), "\n"), "no matching commands") {
+		t.Errorf("empty result not shown")
+	}
+	in.Dispatch(riffkey.Key{Special: riffkey.SpecialEscape})
+	if p.IsOpen() || len(log) != 2 {
+		t.Errorf("escape: open=%v log=%v", p.IsOpen(), log)
+	}
+}
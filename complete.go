@@ -0,0 +1,192 @@
+package glyph
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Completer offers values for a command argument, given what's been typed
+// of it so far.
+type Completer func(prefix string) []string
+
+// CompleteWords completes from a fixed set of values, such as the names of
+// an option's settings. Values are ranked against the prefix as an fzf
+// query, so "dk" offers "dark".
+func CompleteWords(words ...string) Completer {
+	return func(prefix string) []string {
+		return rankCompletions(prefix, words)
+	}
+}
+
+// CompleteFrom completes from values read when completion is asked for,
+// such as the names of open buffers.
+//
+//	app.Command("buffer", "Switch buffer", switchTo).
+//	    Complete(0, CompleteFrom(ed.BufferNames))
+func CompleteFrom(values func() []string) Completer {
+	return func(prefix string) []string {
+		return rankCompletions(prefix, values())
+	}
+}
+
+// CompletePaths completes file and directory paths, relative to the working
+// directory unless absolute or starting with ~/. Directories end in a
+// slash so completing again goes inside them, and dot files are offered
+// once a dot is typed.
+func CompletePaths() Completer {
+	return func(prefix string) []string {
+		dir, base := filepath.Split(prefix)
+		read := dir
+		if read == "" {
+			read = "."
+		} else if home, err := os.UserHomeDir(); err == nil && strings.HasPrefix(read, "~/") {
+			read = filepath.Join(home, read[2:])
+		}
+		entries, err := os.ReadDir(read)
+		if err != nil {
+			return nil
+		}
+		var out []string
+		for _, e := range entries {
+			name := e.Name()
+			if !strings.HasPrefix(name, base) || name[0] == '.' && !strings.HasPrefix(base, ".") {
+				continue
+			}
+			if e.IsDir() {
+				name += string(filepath.Separator)
+			}
+			out = append(out, dir+name)
+		}
+		return out
+	}
+}
+
+// rankCompletions returns the values matching prefix as an fzf query, best
+// first. Every value matches an empty prefix, in order.
+func rankCompletions(prefix string, values []string) []string {
+	q := ParseFzfQuery(prefix)
+	if q.Empty() {
+		return append([]string(nil), values...)
+	}
+	type scored struct {
+		v     string
+		score int
+	}
+	var hits []scored
+	for _, v := range values {
+		if score, ok := q.Score(v); ok {
+			hits = append(hits, scored{v, score})
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].score > hits[j].score })
+	out := make([]string, len(hits))
+	for i, h := range hits {
+		out[i] = h.v
+	}
+	return out
+}
+
+// Complete sets how argument i (from 0) is completed. On a variadic
+// command the last argument's completer serves all the words it takes.
+func (cmd *Command) Complete(i int, c Completer) *Command {
+	if cmd.completers == nil {
+		cmd.completers = make([]Completer, len(cmd.params))
+	}
+	if i >= 0 && i < len(cmd.completers) {
+		cmd.completers[i] = c
+	}
+	return cmd
+}
+
+// completer returns the completer for argument i, or nil.
+func (cmd *Command) completer(i int) Completer {
+	if cmd.variadic && i >= len(cmd.params) {
+		i = len(cmd.params) - 1
+	}
+	if i < 0 || i >= len(cmd.completers) {
+		return nil
+	}
+	return cmd.completers[i]
+}
+
+// Complete returns what the last word of line could be completed to, and
+// the byte offset in line where that word starts. The first word completes
+// to command names and the rest by the command's completers; a line ending
+// in a space completes a new word.
+//
+//	start, values := cmds.Complete("write no")   // 6, ["notes.md" ...]
+//	line = line[:start] + QuoteWord(values[0])
+func (c *Commands) Complete(line string) (start int, values []string) {
+	start = lastWordStart(line)
+	words, err := splitCommandLine(line[:start])
+	if err != nil {
+		return start, nil
+	}
+	word := unquoteWord(line[start:])
+	if len(words) == 0 {
+		var names []string
+		for _, cmd := range c.list {
+			if strings.HasPrefix(cmd.Name, word) {
+				names = append(names, cmd.Name)
+			}
+		}
+		return start, names
+	}
+	cmd, err := c.Get(words[0])
+	if err != nil {
+		return start, nil
+	}
+	comp := cmd.completer(len(words) - 1)
+	if comp == nil {
+		return start, nil
+	}
+	return start, comp(word)
+}
+
+// lastWordStart returns where the word being typed at the end of line
+// starts, which is the end of line if it ends in a space.
+func lastWordStart(line string) int {
+	start := 0
+	inWord := false
+	var quote rune
+	for i, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == ' ' || r == '\t':
+			inWord = false
+		default:
+			if !inWord {
+				start, inWord = i, true
+			}
+			if r == '"' || r == '\'' {
+				quote = r
+			}
+		}
+	}
+	if !inWord && quote == 0 {
+		return len(line)
+	}
+	return start
+}
+
+// unquoteWord strips the quotes from a partly typed word.
+func unquoteWord(s string) string {
+	return strings.NewReplacer(`"`, "", "'", "").Replace(s)
+}
+
+// QuoteWord quotes s if it has spaces or quotes in it, so that it stays one
+// word in a command line.
+func QuoteWord(s string) string {
+	if !strings.ContainsAny(s, " \t\"'") {
+		return s
+	}
+	if strings.Contains(s, `"`) {
+		return "'" + s + "'"
+	}
+	return `"` + s + `"`
+}
@@ -0,0 +1,126 @@
+package glyph
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+func TestCommandsComplete(t *testing.T) {
+	cmds := NewCommands()
+	cmds.Add("theme", "Set the theme", func(name string) {}).
+		Complete(0, CompleteWords("light", "dark", "solarized dark"))
+	cmds.Add("tag", "Tag buffers", func(tags ...string) {}).
+		Complete(0, CompleteFrom(func() []string { return []string{"todo", "done"} }))
+	cmds.Add("quit", "", func() {})
+
+	for _, tc := range []struct {
+		line  string
+		start int
+		want  []string
+	}{
+		{"t", 0, []string{"theme", "tag"}},
+		{"th", 0, []string{"theme"}},
+		{"theme ", 6, []string{"light", "dark", "solarized dark"}},
+		{"theme dk", 6, []string{"dark", "solarized dark"}},
+		{`theme "sol`, 6, []string{"solarized dark"}},
+		{"tag a b d", 8, []string{"done", "todo"}}, // variadic words share the completer
+		{"quit ", 5, nil},
+		{"nope ", 5, nil},
+	} {
+		start, got := cmds.Complete(tc.line)
+		if start != tc.start || !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Complete(%q) = %d %q, want %d %q", tc.line, start, got, tc.start, tc.want)
+		}
+	}
+
+	if q := QuoteWord("solarized dark"); q != `"solarized dark"` {
+		t.Errorf("QuoteWord = %s", q)
+	}
+	if q := QuoteWord(`say "hi"`); q != `'say "hi"'` {
+		t.Errorf("QuoteWord = %s", q)
+	}
+}
+
+func TestCompletePaths(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "notes.md"), nil, 0o644)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), nil, 0o644)
+	os.WriteFile(filepath.Join(dir, ".hidden"), nil, 0o644)
+	os.Mkdir(filepath.Join(dir, "nested"), 0o755)
+
+	complete := CompletePaths()
+	got := complete(dir + "/no")
+	want := []string{dir + "/notes.md", dir + "/notes.txt"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("no -> %q, want %q", got, want)
+	}
+	if got := complete(dir + "/ne"); len(got) != 1 || got[0] != dir+"/nested/" {
+		t.Errorf("directories end in a slash: %q", got)
+	}
+	if got := complete(dir + "/"); len(got) != 3 {
+		t.Errorf("dot files offered before a dot is typed: %q", got)
+	}
+	if got := complete(dir + "/."); len(got) != 1 {
+		t.Errorf("dot files not offered after a dot: %q", got)
+	}
+	if got := complete(dir + "/missing/x"); got != nil {
+		t.Errorf("missing dir -> %q", got)
+	}
+}
+
+func TestCommandPaletteTab(t *testing.T) {
+	cmds := NewCommands()
+	var theme string
+	cmds.Add("theme", "Set the theme", func(name string) { theme = name }).
+		Complete(0, CompleteWords("light", "dark", "solarized dark"))
+	cmds.Add("quit", "", func() {})
+
+	p := CommandPalette(cmds)
+	tmpl := Build(VBox(Text("body"), p))
+	var stack []*riffkey.Router
+	p.setModal(func(r *riffkey.Router) { stack = append(stack, r) }, func() { stack = stack[:len(stack)-1] })
+	p.Open()
+	in := riffkey.NewInput(stack[0])
+	typed := func(s string) {
+		for _, r := range s {
+			in.Dispatch(riffkey.Key{Rune: r})
+		}
+	}
+	tab := func() { in.Dispatch(riffkey.Key{Special: riffkey.SpecialTab}) }
+
+	typed("th")
+	tab()
+	if p.Query() != "theme " {
+		t.Fatalf("tab on a name: %q", p.Query())
+	}
+	screen := strings.Join(messageLines(tmpl, 50, 12), "\n")
+	if !strings.Contains(screen, "light") || !strings.Contains(screen, "solarized dark") {
+		t.Errorf("completions not listed:\n%s", screen)
+	}
+
+	typed("s")
+	tab()
+	if p.Query() != `theme "solarized dark"` {
+		t.Fatalf("tab on an argument: %q", p.Query())
+	}
+	in.Dispatch(riffkey.Key{Special: riffkey.SpecialEnter})
+	if theme != "solarized dark" || p.IsOpen() {
+		t.Errorf("theme=%q open=%v", theme, p.IsOpen())
+	}
+
+	// tab cycles, and wraps
+	p.Open()
+	in = riffkey.NewInput(stack[0])
+	typed("theme ")
+	for _, want := range []string{"theme light", "theme dark", `theme "solarized dark"`, "theme light"} {
+		tab()
+		if p.Query() != want {
+			t.Errorf("cycling: %q, want %q", p.Query(), want)
+		}
+	}
+}
@@ -16,6 +16,8 @@ type textInputBinding struct {
 	value    *string
 	cursor   *int
 	onChange func(string) // optional callback when value changes
+	digraphs bool         // Ctrl-K types digraphs
+	locked   func() bool  // optional; while true, keys are ignored
 }
 
 // ============================================================================
@@ -55,6 +57,7 @@ func Define(fn func() any) any {
 
 type VBoxC struct {
 	fill         Color
+	pattern      *Pattern
 	inheritStyle *Style
 	gap          int8
 	border       BorderStyle
@@ -68,8 +71,12 @@ type VBoxC struct {
 	fitContent   bool
 	margin       [4]int16 // top, right, bottom, left
 	children     []any
+	collapse     *bool
+	collapseKey  string
 }
 
+func (v VBoxC) bindings() []binding { return collapseBindings(v.collapse, v.collapseKey) }
+
 type VBoxFn func(children ...any) VBoxC
 
 // Fill sets the background fill color.
@@ -81,6 +88,16 @@ func (f VBoxFn) Fill(c Color) VBoxFn {
 	}
 }
 
+// Pattern textures the background with a tile or ramp of runes, drawn
+// over the Fill; see Pattern.
+func (f VBoxFn) Pattern(p Pattern) VBoxFn {
+	return func(children ...any) VBoxC {
+		v := f(children...)
+		v.pattern = &p
+		return v
+	}
+}
+
 // CascadeStyle sets a style pointer that children inherit.
 func (f VBoxFn) CascadeStyle(s *Style) VBoxFn {
 	return func(children ...any) VBoxC {
@@ -99,6 +116,34 @@ func (f VBoxFn) Gap(g int8) VBoxFn {
 	}
 }
 
+// JoinBorders overlaps bordered children by one cell so neighbours share
+// an edge, drawn with junctions (┬ ┼ ┴) rather than as two parallel lines.
+// It's the same as Gap(-1).
+func (f VBoxFn) JoinBorders() VBoxFn {
+	return f.Gap(-1)
+}
+
+// Collapsible lets a bordered panel fold down to its borders and title.
+// Children show while *open is true and slide in and out when it changes.
+// The title gets a ▾ or ▸ marker, which is a jump target that toggles the
+// panel. A collapsed panel gives its Grow share to its siblings.
+func (f VBoxFn) Collapsible(open *bool) VBoxFn {
+	return func(children ...any) VBoxC {
+		v := f(children...)
+		v.collapse = open
+		return v
+	}
+}
+
+// BindCollapse toggles a Collapsible panel when key is pressed.
+func (f VBoxFn) BindCollapse(key string) VBoxFn {
+	return func(children ...any) VBoxC {
+		v := f(children...)
+		v.collapseKey = key
+		return v
+	}
+}
+
 // Border sets the border style.
 func (f VBoxFn) Border(b BorderStyle) VBoxFn {
 	return func(children ...any) VBoxC {
@@ -228,6 +273,7 @@ var VBox VBoxFn = func(children ...any) VBoxC {
 
 type HBoxC struct {
 	fill         Color
+	pattern      *Pattern
 	inheritStyle *Style
 	gap          int8
 	border       BorderStyle
@@ -241,8 +287,12 @@ type HBoxC struct {
 	fitContent   bool
 	margin       [4]int16 // top, right, bottom, left
 	children     []any
+	collapse     *bool
+	collapseKey  string
 }
 
+func (v HBoxC) bindings() []binding { return collapseBindings(v.collapse, v.collapseKey) }
+
 type HBoxFn func(children ...any) HBoxC
 
 // Fill sets the background fill color.
@@ -254,6 +304,16 @@ func (f HBoxFn) Fill(c Color) HBoxFn {
 	}
 }
 
+// Pattern textures the background with a tile or ramp of runes, drawn
+// over the Fill; see Pattern.
+func (f HBoxFn) Pattern(p Pattern) HBoxFn {
+	return func(children ...any) HBoxC {
+		h := f(children...)
+		h.pattern = &p
+		return h
+	}
+}
+
 // CascadeStyle sets a style pointer that children inherit.
 func (f HBoxFn) CascadeStyle(s *Style) HBoxFn {
 	return func(children ...any) HBoxC {
@@ -272,6 +332,34 @@ func (f HBoxFn) Gap(g int8) HBoxFn {
 	}
 }
 
+// JoinBorders overlaps bordered children by one cell so neighbours share
+// an edge, drawn with junctions (┬ ┼ ┴) rather than as two parallel lines.
+// It's the same as Gap(-1).
+func (f HBoxFn) JoinBorders() HBoxFn {
+	return f.Gap(-1)
+}
+
+// Collapsible lets a bordered panel fold down to its borders and title.
+// Children show while *open is true and slide in and out when it changes.
+// The title gets a ▾ or ▸ marker, which is a jump target that toggles the
+// panel. A collapsed panel gives its Grow share to its siblings.
+func (f HBoxFn) Collapsible(open *bool) HBoxFn {
+	return func(children ...any) HBoxC {
+		h := f(children...)
+		h.collapse = open
+		return h
+	}
+}
+
+// BindCollapse toggles a Collapsible panel when key is pressed.
+func (f HBoxFn) BindCollapse(key string) HBoxFn {
+	return func(children ...any) HBoxC {
+		h := f(children...)
+		h.collapseKey = key
+		return h
+	}
+}
+
 // Border sets the border style.
 func (f HBoxFn) Border(b BorderStyle) HBoxFn {
 	return func(children ...any) HBoxC {
@@ -954,6 +1042,9 @@ type JumpC struct {
 	onSelect func()
 	style    Style
 	margin   [4]int16
+	key      string
+	hint     KeyHint
+	disabled *bool
 }
 
 // Jump wraps a child component as a jump target.
@@ -967,6 +1058,32 @@ func (j JumpC) Style(s Style) JumpC {
 	return j
 }
 
+// Key binds a key to select the target without jump mode, as for a
+// button.
+func (j JumpC) Key(pattern string) JumpC {
+	j.key = pattern
+	return j
+}
+
+// Hint shows the key from Key on the target. With HintUnderline the
+// letter is looked for in the target's first row.
+func (j JumpC) Hint(h KeyHint) JumpC {
+	j.hint = h
+	return j
+}
+
+func (j JumpC) bindings() []binding {
+	if j.key == "" || j.onSelect == nil {
+		return nil
+	}
+	onSelect, disabled := j.onSelect, j.disabled
+	return []binding{{pattern: j.key, handler: func() {
+		if !isSet(disabled) {
+			onSelect()
+		}
+	}}}
+}
+
 // Margin sets uniform margin on all sides.
 func (j JumpC) Margin(all int16) JumpC { j.margin = [4]int16{all, all, all, all}; return j }
 
@@ -981,11 +1098,15 @@ func (j JumpC) MarginTRBL(a, b, c, d int16) JumpC { j.margin = [4]int16{a, b, c,
 // ============================================================================
 
 type LayerViewC struct {
-	layer      *Layer
-	viewHeight int16
-	viewWidth  int16
-	flexGrow   float32
-	margin     [4]int16
+	layer            *Layer
+	viewHeight       int16
+	viewWidth        int16
+	flexGrow         float32
+	margin           [4]int16
+	hScrollbar       bool
+	scrollbarStyle   Style
+	pins             []layerPin
+	declaredBindings []binding
 }
 
 // LayerView displays a pre-rendered layer with scrolling support.
@@ -1011,6 +1132,56 @@ func (l LayerViewC) Grow(g float32) LayerViewC {
 	return l
 }
 
+// HScrollbar reserves the bottom row for a horizontal scrollbar showing
+// where the viewport sits across wide content.
+func (l LayerViewC) HScrollbar() LayerViewC {
+	l.hScrollbar = true
+	return l
+}
+
+// ScrollX binds the horizontal scroll offset to p, as Layer.BindScrollX.
+func (l LayerViewC) ScrollX(p *int) LayerViewC {
+	l.layer.BindScrollX(p)
+	return l
+}
+
+// ScrollbarStyle sets the style of the horizontal scrollbar.
+func (l LayerViewC) ScrollbarStyle(s Style) LayerViewC {
+	l.scrollbarStyle = s
+	return l
+}
+
+// BindScroll registers keys for line-by-line vertical scrolling.
+func (l LayerViewC) BindScroll(down, up string) LayerViewC {
+	l.declaredBindings = append(l.declaredBindings,
+		binding{pattern: down, handler: func() { l.layer.ScrollDown(1) }},
+		binding{pattern: up, handler: func() { l.layer.ScrollUp(1) }},
+	)
+	return l
+}
+
+// BindHScroll registers keys for column-by-column horizontal scrolling,
+// such as "zh" and "zl".
+func (l LayerViewC) BindHScroll(left, right string) LayerViewC {
+	l.declaredBindings = append(l.declaredBindings,
+		binding{pattern: left, handler: func() { l.layer.ScrollLeft(1) }},
+		binding{pattern: right, handler: func() { l.layer.ScrollRight(1) }},
+	)
+	return l
+}
+
+// BindHalfPageHScroll registers keys that scroll half the viewport width,
+// such as "zH" and "zL".
+func (l LayerViewC) BindHalfPageHScroll(left, right string) LayerViewC {
+	l.declaredBindings = append(l.declaredBindings,
+		binding{pattern: left, handler: l.layer.HalfPageLeft},
+		binding{pattern: right, handler: l.layer.HalfPageRight},
+	)
+	return l
+}
+
+func (l LayerViewC) bindings() []binding { return l.declaredBindings }
+
 // Margin sets uniform margin on all sides.
 func (l LayerViewC) Margin(all int16) LayerViewC { l.margin = [4]int16{all, all, all, all}; return l }
 
@@ -1107,6 +1278,8 @@ var Overlay OverlayFn = func(children ...any) OverlayC {
 type ForEachC[T any] struct {
 	items    *[]T
 	template func(item *T) any
+	live     liveSource // set by ForEachFrom
+	key      func(item *T) any
 }
 
 // ForEach renders a template for each item in a slice.
@@ -1114,9 +1287,34 @@ func ForEach[T any](items *[]T, template func(item *T) any) ForEachC[T] {
 	return ForEachC[T]{items: items, template: template}
 }
 
+// KeyBy reconciles the items of f by identity instead of position. The
+// template function is called once per key, so anything it creates (a
+// Layer, a TextArea, a local variable) belongs to that item and follows it
+// when the slice is reordered, and is dropped when the item is removed.
+// Keys must be unique within the slice.
+//
+//	KeyBy(ForEach(&todos, func(t *Todo) any { ... }), func(t *Todo) int { return t.ID })
+//
+// Keys bound inside the template are offered to each item in turn, the
+// first to use a key taking it, before the view's own bindings.
+func KeyBy[T any, K comparable](f ForEachC[T], key func(item *T) K) ForEachC[T] {
+	f.key = func(item *T) any { return key(item) }
+	return f
+}
+
 // compileTo implements forEachCompiler for template compilation
 func (f ForEachC[T]) compileTo(t *Template, parent int16, depth int) int16 {
-	return t.compileForEach(ForEachNode{Items: f.items, Render: f.template}, parent, depth)
+	if f.live != nil {
+		t.addLive(f.live)
+	}
+	idx := t.compileForEach(ForEachNode{Items: f.items, Render: f.template}, parent, depth)
+	if f.key != nil {
+		keyed := newKeyedItems(f.key, f.template)
+		t.ops[idx].keyed = keyed
+		t.mounts = append(t.mounts, keyed)
+		t.collectRouterScope(keyed)
+	}
+	return idx
 }
 
 // ============================================================================
@@ -1129,6 +1327,8 @@ type ListC[T any] struct {
 	internalSel      int // used when no external selection provided
 	render           func(*T) any
 	onSelect         func(*T)
+	onVisible        func(i int)
+	onHidden         func(i int)
 	marker           string
 	markerStyle      Style
 	maxVisible       int
@@ -1222,6 +1422,20 @@ func (l *ListC[T]) OnSelect(fn func(*T)) *ListC[T] {
 	return l
 }
 
+// OnVisible registers a callback that fires when item i scrolls into view,
+// including the items shown on first render. Use it to load details lazily;
+// it runs during render, so start slow work in the background.
+func (l *ListC[T]) OnVisible(fn func(i int)) *ListC[T] {
+	l.onVisible = fn
+	return l
+}
+
+// OnHidden registers a callback that fires when item i scrolls out of view.
+func (l *ListC[T]) OnHidden(fn func(i int)) *ListC[T] {
+	l.onHidden = fn
+	return l
+}
+
 // Marker sets the selection marker (default "> ").
 func (l *ListC[T]) Marker(m string) *ListC[T] {
 	l.marker = m
@@ -1282,6 +1496,8 @@ func (l *ListC[T]) toSelectionList() *SelectionList {
 			MaxVisible:    l.maxVisible,
 			Style:         l.style,
 			SelectedStyle: l.selectedStyle,
+			OnVisible:     l.onVisible,
+			OnHidden:      l.onHidden,
 		}
 		if l.render != nil {
 			sl.Render = l.render
@@ -1387,6 +1603,8 @@ type TabsC struct {
 	activeStyle   Style
 	inactiveStyle Style
 	margin        [4]int16
+	keys          []string
+	hint          KeyHint
 }
 
 // Tabs creates a tab header bar.
@@ -1418,6 +1636,32 @@ func (t TabsC) InactiveStyle(s Style) TabsC {
 	return t
 }
 
+// Keys binds a key to select each tab, in order. Tabs beyond the keys
+// given have none.
+func (t TabsC) Keys(patterns ...string) TabsC {
+	t.keys = patterns
+	return t
+}
+
+// Hint shows each tab's key from Keys on its label.
+func (t TabsC) Hint(h KeyHint) TabsC {
+	t.hint = h
+	return t
+}
+
+func (t TabsC) bindings() []binding {
+	if t.selected == nil {
+		return nil
+	}
+	var out []binding
+	for i, key := range t.keys[:min(len(t.keys), len(t.labels))] {
+		if key != "" {
+			out = append(out, binding{pattern: key, handler: func() { *t.selected = i }})
+		}
+	}
+	return out
+}
+
 // Margin sets uniform margin on all sides.
 func (t TabsC) Margin(all int16) TabsC { t.margin = [4]int16{all, all, all, all}; return t }
 
@@ -1526,6 +1770,8 @@ type CheckboxC struct {
 	validator  BoolValidator
 	validateOn ValidateOn
 	err        string
+
+	disabled, readOnly *bool
 }
 
 // Checkbox creates a checkbox bound to a bool pointer.
@@ -1640,8 +1886,12 @@ func (c *CheckboxC) runValidation() {
 	}
 }
 
-// Toggle flips the checked state.
+// Toggle flips the checked state, unless the checkbox is disabled or
+// read-only.
 func (c *CheckboxC) Toggle() {
+	if c.locked() {
+		return
+	}
 	*c.checked = !*c.checked
 	if c.validateOn&VOnChange != 0 {
 		c.runValidation()
@@ -1668,6 +1918,8 @@ type RadioC struct {
 	// focus
 	focused bool
 	onBlur  func()
+
+	disabled, readOnly *bool
 }
 
 // Radio creates a radio group with static options.
@@ -1764,16 +2016,24 @@ func (r *RadioC) setFocused(focused bool) {
 // Focused returns whether this radio group currently has focus.
 func (r *RadioC) Focused() bool { return r.focused }
 
-// Next moves selection to next option.
+// Next moves selection to next option, unless the group is disabled or
+// read-only.
 func (r *RadioC) Next() {
+	if r.locked() {
+		return
+	}
 	opts := r.getOptions()
 	if *r.selected < len(opts)-1 {
 		*r.selected++
 	}
 }
 
-// Prev moves selection to previous option.
+// Prev moves selection to previous option, unless the group is disabled
+// or read-only.
 func (r *RadioC) Prev() {
+	if r.locked() {
+		return
+	}
 	if *r.selected > 0 {
 		*r.selected--
 	}
@@ -2110,6 +2370,10 @@ type InputC struct {
 
 	// blur callback (wired by Form for VOnBlur validation)
 	onBlur func()
+
+	digraphs bool
+
+	disabled, readOnly *bool
 }
 
 // Input creates a text input with internal state.
@@ -2202,6 +2466,18 @@ func (i *InputC) Bind() *InputC {
 		value:    &i.field.Value,
 		cursor:   &i.field.Cursor,
 		onChange: i.handleChange,
+		digraphs: i.digraphs,
+		locked:   i.locked,
+	}
+	return i
+}
+
+// Digraphs lets Ctrl-K and two characters type a symbol, such as "->"
+// for → or "a*" for α; see Digraph.
+func (i *InputC) Digraphs() *InputC {
+	i.digraphs = true
+	if i.declaredTIB != nil {
+		i.declaredTIB.digraphs = true
 	}
 	return i
 }
@@ -2217,6 +2493,8 @@ func (i *InputC) ManagedBy(fm *FocusManager) *InputC {
 		value:    &i.field.Value,
 		cursor:   &i.field.Cursor,
 		onChange: i.handleChange,
+		digraphs: i.digraphs,
+		locked:   i.locked,
 	}
 	fm.Register(i)
 	return i
@@ -0,0 +1,170 @@
+package glyph
+
+// Compositing draws one buffer onto another, or changes a region in place,
+// adjusting cells as it goes: dimming what's behind a modal, tinting a
+// disabled panel, or ghosting a preview into the gaps of what's drawn.
+//
+//	frame.Dim(0, 0, frame.Width(), frame.Height(), 0.6)
+//	frame.Blit(dialog, 0, 0, x, y, dialog.Width(), dialog.Height())
+//
+// Colours are mixed in RGB, so palette colours come out as true colour.
+// The terminal's default colours can't be known, and are taken to be the
+// palette's white on black.
+
+// BlitFunc copies a region from src like Blit, combining each source cell
+// with the destination cell under it through fn.
+func (b *Buffer) BlitFunc(src *Buffer, srcX, srcY, dstX, dstY, width, height int, fn func(dst, src Cell) Cell) {
+	srcX, srcY, dstX, dstY, width, height = b.clipBlit(src, srcX, srcY, dstX, dstY, width, height)
+	if width <= 0 || height <= 0 {
+		return
+	}
+	for y := 0; y < height; y++ {
+		s := src.cells[(srcY+y)*src.width+srcX:][:width]
+		d := b.cells[(dstY+y)*b.width+dstX:][:width]
+		for i := range d {
+			d[i] = fn(d[i], s[i])
+		}
+		b.dirtyRows[dstY+y] = true
+	}
+	if dstY+height-1 > b.dirtyMaxY {
+		b.dirtyMaxY = dstY + height - 1
+	}
+}
+
+// BlitDim copies a region from src like Blit, darkening its colours by
+// amount, from 0 for unchanged to 1 for black.
+func (b *Buffer) BlitDim(src *Buffer, srcX, srcY, dstX, dstY, width, height int, amount float64) {
+	b.BlitFunc(src, srcX, srcY, dstX, dstY, width, height, func(_, c Cell) Cell {
+		return dimCell(c, amount)
+	})
+}
+
+// BlitTint copies a region from src like Blit, moving foreground colours
+// amount of the way toward fg and background colours toward bg. A zero
+// Color for fg or bg leaves that side as it is.
+func (b *Buffer) BlitTint(src *Buffer, srcX, srcY, dstX, dstY, width, height int, fg, bg Color, amount float64) {
+	b.BlitFunc(src, srcX, srcY, dstX, dstY, width, height, func(_, c Cell) Cell {
+		return tintCell(c, fg, bg, amount)
+	})
+}
+
+// BlitBlank copies a region from src like Blit, but only into blank cells,
+// spaces with no background as a cleared buffer holds, leaving what's
+// drawn on top. Blank source cells aren't copied either.
+func (b *Buffer) BlitBlank(src *Buffer, srcX, srcY, dstX, dstY, width, height int) {
+	b.BlitFunc(src, srcX, srcY, dstX, dstY, width, height, func(d, s Cell) Cell {
+		if isBlank(d) && !isBlank(s) {
+			return s
+		}
+		return d
+	})
+}
+
+// Dim darkens the colours of a region in place by amount, from 0 for
+// unchanged to 1 for black.
+func (b *Buffer) Dim(x, y, width, height int, amount float64) {
+	b.mapRect(x, y, width, height, func(c Cell) Cell { return dimCell(c, amount) })
+}
+
+// Tint moves the colours of a region in place amount of the way toward fg
+// for foregrounds and bg for backgrounds. A zero Color for fg or bg leaves
+// that side as it is.
+func (b *Buffer) Tint(x, y, width, height int, fg, bg Color, amount float64) {
+	b.mapRect(x, y, width, height, func(c Cell) Cell { return tintCell(c, fg, bg, amount) })
+}
+
+// mapRect replaces each cell of a region, clipped to the buffer, with
+// fn's result.
+func (b *Buffer) mapRect(x, y, width, height int, fn func(Cell) Cell) {
+	if x < 0 {
+		width += x
+		x = 0
+	}
+	if y < 0 {
+		height += y
+		y = 0
+	}
+	width = min(width, b.width-x)
+	height = min(height, b.height-y)
+	if width <= 0 || height <= 0 {
+		return
+	}
+	for row := y; row < y+height; row++ {
+		cells := b.cells[row*b.width+x:][:width]
+		for i := range cells {
+			cells[i] = fn(cells[i])
+		}
+		b.dirtyRows[row] = true
+	}
+	if y+height-1 > b.dirtyMaxY {
+		b.dirtyMaxY = y + height - 1
+	}
+}
+
+var black = RGB(0, 0, 0)
+
+func dimCell(c Cell, amount float64) Cell {
+	if amount <= 0 {
+		return c
+	}
+	keep := uint32((1 - min(amount, 1)) * 256)
+	c.Style.FG = scaleColor(rgbColor(c.Style.FG, true), keep)
+	if c.Style.BG.Mode != ColorDefault {
+		c.Style.BG = scaleColor(rgbColor(c.Style.BG, false), keep)
+	}
+	return c
+}
+
+// scaleColor multiplies an RGB colour by keep/256.
+func scaleColor(c Color, keep uint32) Color {
+	return RGB(uint8(uint32(c.R)*keep>>8), uint8(uint32(c.G)*keep>>8), uint8(uint32(c.B)*keep>>8))
+}
+
+func tintCell(c Cell, fg, bg Color, amount float64) Cell {
+	if amount <= 0 {
+		return c
+	}
+	if fg.Mode != ColorDefault {
+		c.Style.FG = LerpColor(rgbColor(c.Style.FG, true), rgbColor(fg, true), amount)
+	}
+	if bg.Mode != ColorDefault {
+		c.Style.BG = LerpColor(rgbColor(c.Style.BG, false), rgbColor(bg, false), amount)
+	}
+	return c
+}
+
+// isBlank reports whether a cell shows nothing but the terminal's
+// background.
+func isBlank(c Cell) bool {
+	return c.Rune == ' ' && c.Style.BG.Mode == ColorDefault &&
+		!c.Style.Attr.Has(AttrInverse) && !c.Style.Attr.Has(AttrUnderline)
+}
+
+// rgb16 is ansi16 as RGB.
+var rgb16 = func() (t [16]Color) {
+	for i := range t {
+		r, g, b := palette256(uint8(i))
+		t[i] = RGB(r, g, b)
+	}
+	return t
+}()
+
+// rgbColor returns c as a true colour, taking the default colour as white
+// for a foreground and black for a background.
+func rgbColor(c Color, fg bool) Color {
+	switch c.Mode {
+	case ColorRGB:
+		return c
+	case Color16:
+		return rgb16[c.Index&15]
+	case Color256:
+		if c.Index < 16 {
+			return rgb16[c.Index]
+		}
+		return RGB(palette256(c.Index))
+	}
+	if fg {
+		return rgb16[7]
+	}
+	return black
+}
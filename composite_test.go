@@ -0,0 +1,68 @@
+package glyph
+
+import "testing"
+
+func TestDim(t *testing.T) {
+	buf := NewBuffer(4, 1)
+	buf.Set(0, 0, Cell{Rune: 'a', Style: Style{FG: RGB(200, 100, 0), BG: Red}})
+	buf.Set(1, 0, Cell{Rune: 'b', Style: Style{FG: PaletteColor(196)}})
+	buf.Dim(0, 0, 1, 1, 0.5)
+	buf.Dim(1, 0, 10, 10, 1)
+
+	a := buf.Get(0, 0).Style
+	if a.FG != RGB(100, 50, 0) || a.BG != RGB(102, 0, 0) {
+		t.Errorf("half dim = %+v on %+v", a.FG, a.BG)
+	}
+	if fg := buf.Get(1, 0).Style.FG; fg != RGB(0, 0, 0) {
+		t.Errorf("full dim = %+v, want black", fg)
+	}
+	if bg := buf.Get(1, 0).Style.BG; bg.Mode != ColorDefault {
+		t.Errorf("default background dimmed to %+v", bg)
+	}
+}
+
+func TestBlitTint(t *testing.T) {
+	src := NewBuffer(2, 1)
+	src.WriteStringFast(0, 0, "ok", Style{FG: RGB(0, 0, 0), BG: RGB(0, 0, 200)}, 2)
+	dst := NewBuffer(4, 1)
+	dst.BlitTint(src, 0, 0, 1, 0, 2, 1, RGB(100, 100, 100), Color{}, 0.5)
+
+	c := dst.Get(2, 0)
+	if c.Rune != 'k' || c.Style.FG != RGB(50, 50, 50) || c.Style.BG != RGB(0, 0, 200) {
+		t.Errorf("tinted cell = %q %+v", c.Rune, c.Style)
+	}
+	if dst.Get(0, 0).Rune != ' ' {
+		t.Error("tint drew outside the region")
+	}
+}
+
+func TestBlitBlank(t *testing.T) {
+	ghost := NewBuffer(5, 1)
+	ghost.WriteStringFast(0, 0, "hello", Style{Attr: AttrDim}, 5)
+	ghost.Set(2, 0, Cell{Rune: ' ', Style: DefaultStyle()})
+	dst := NewBuffer(5, 1)
+	dst.WriteStringFast(0, 0, "H", Style{}, 1)
+	dst.Set(1, 0, Cell{Rune: ' ', Style: Style{BG: Blue}})
+	dst.Set(2, 0, Cell{Rune: 'x', Style: Style{}})
+	dst.BlitBlank(ghost, 0, 0, 0, 0, 5, 1)
+
+	if got := dst.GetLine(0); got != "H xlo" {
+		t.Errorf("BlitBlank = %q, want %q", got, "H xlo")
+	}
+	if !dst.Get(3, 0).Style.Attr.Has(AttrDim) {
+		t.Error("ghost cell lost its style")
+	}
+}
+
+func BenchmarkBlitDim(b *testing.B) {
+	src := NewBuffer(200, 60)
+	for y := 0; y < 60; y++ {
+		src.WriteStringFast(0, y, "the quick brown fox jumps over the lazy dog", Style{FG: PaletteColor(33), BG: Blue}, 200)
+	}
+	dst := NewBuffer(200, 60)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		dst.BlitDim(src, 0, 0, 0, 0, 200, 60, 0.5)
+	}
+}
@@ -110,6 +110,7 @@ type ConditionEval[T comparable] struct {
 	val    T
 	then   any
 	els    any
+	trans  *transition
 }
 
 // Then specifies what to render when true
@@ -137,8 +138,15 @@ func (e *ConditionEval[T]) evaluate() bool {
 	}
 }
 
-func (e *ConditionEval[T]) getThen() any { return e.then }
-func (e *ConditionEval[T]) getElse() any { return e.els }
+// Transition animates the Then branch in and out instead of toggling it.
+func (e *ConditionEval[T]) Transition(tr Transition) *ConditionEval[T] {
+	e.trans = &transition{spec: tr}
+	return e
+}
+
+func (e *ConditionEval[T]) getThen() any               { return e.then }
+func (e *ConditionEval[T]) getElse() any               { return e.els }
+func (e *ConditionEval[T]) getTransition() *transition { return e.trans }
 
 func (e *ConditionEval[T]) setOffset(offset uintptr) { e.offset = offset }
 func (e *ConditionEval[T]) getOffset() uintptr       { return e.offset }
@@ -168,6 +176,7 @@ type OrdConditionEval[T cmp.Ordered] struct {
 	val    T
 	then   any
 	els    any
+	trans  *transition
 }
 
 // Then specifies what to render when true
@@ -203,8 +212,15 @@ func (e *OrdConditionEval[T]) evaluate() bool {
 	}
 }
 
-func (e *OrdConditionEval[T]) getThen() any { return e.then }
-func (e *OrdConditionEval[T]) getElse() any { return e.els }
+// Transition animates the Then branch in and out instead of toggling it.
+func (e *OrdConditionEval[T]) Transition(tr Transition) *OrdConditionEval[T] {
+	e.trans = &transition{spec: tr}
+	return e
+}
+
+func (e *OrdConditionEval[T]) getThen() any               { return e.then }
+func (e *OrdConditionEval[T]) getElse() any               { return e.els }
+func (e *OrdConditionEval[T]) getTransition() *transition { return e.trans }
 
 func (e *OrdConditionEval[T]) setOffset(offset uintptr) { e.offset = offset }
 func (e *OrdConditionEval[T]) getOffset() uintptr       { return e.offset }
@@ -243,6 +259,7 @@ type conditionNode interface {
 	getPtrAddr() uintptr // get pointer address for offset calculation
 	getThen() any
 	getElse() any
+	getTransition() *transition // nil when the branch toggles instantly
 }
 
 // ensure our types implement conditionNode
@@ -0,0 +1,212 @@
+package glyph
+
+import (
+	"time"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+// ContextMenuC wraps a node with a menu of actions that floats over it when
+// opened. Open it from a key for now; OpenAt takes screen coordinates, for
+// a mouse click.
+//
+//	ContextMenu(fileList,
+//	    MenuItem{Label: "Open", Action: openSelected},
+//	    MenuItem{Label: "Rename", Key: "<F2>", Action: rename},
+//	    MenuItem{Label: "Move to", Sub: folders},
+//	    MenuSeparator(),
+//	    MenuItem{Label: "Delete", Key: "<Del>", Action: remove},
+//	).BindOpen("m")
+//
+// While open it takes the keyboard: up/down or Ctrl-p/Ctrl-n move, right
+// opens a submenu, left goes back, Enter runs the item and Esc closes.
+// Typing jumps to the item whose label starts with what was typed.
+//
+// Like MenuBar, item keys are bound as ordinary shortcuts. The child is
+// compiled on its own, so it can't bind to ForEach item fields.
+type ContextMenuC struct {
+	child any
+	items []MenuItem
+	sub   *Template
+	popup menuPopup
+	now   func() time.Time
+
+	x, y   int16 // child's position from the last render
+	ax, ay int16 // where the open menu is anchored
+	atNode bool  // anchored to the child rather than OpenAt coordinates
+
+	router           *riffkey.Router
+	push             func(*riffkey.Router)
+	pop              func()
+	declaredBindings []binding
+}
+
+// ContextMenu wraps child with a menu of items.
+func ContextMenu(child any, items ...MenuItem) *ContextMenuC {
+	return &ContextMenuC{
+		child: child,
+		items: items,
+		popup: newMenuPopup(),
+		now:   time.Now,
+	}
+}
+
+// MenuStyle sets the menu body and border style.
+func (cm *ContextMenuC) MenuStyle(s Style) *ContextMenuC {
+	cm.popup.style = s
+	return cm
+}
+
+// SelectedStyle sets the style of the item under the cursor. Inverse by
+// default.
+func (cm *ContextMenuC) SelectedStyle(s Style) *ContextMenuC {
+	cm.popup.selectedStyle = s
+	return cm
+}
+
+// DisabledStyle sets the style of disabled items. Dim by default.
+func (cm *ContextMenuC) DisabledStyle(s Style) *ContextMenuC {
+	cm.popup.disabledStyle = s
+	return cm
+}
+
+// Border sets the menu border. BorderSingle by default.
+func (cm *ContextMenuC) Border(b BorderStyle) *ContextMenuC {
+	cm.popup.border = b
+	return cm
+}
+
+// BindOpen registers a key that opens the menu at the node.
+func (cm *ContextMenuC) BindOpen(key string) *ContextMenuC {
+	cm.declaredBindings = append(cm.declaredBindings, binding{key, cm.Open})
+	return cm
+}
+
+// Open opens the menu just below the top-left corner of the node.
+func (cm *ContextMenuC) Open() {
+	cm.atNode = true
+	cm.show()
+}
+
+// OpenAt opens the menu with its corner at screen column x, row y.
+func (cm *ContextMenuC) OpenAt(x, y int) {
+	cm.atNode = false
+	cm.ax, cm.ay = int16(x), int16(y)
+	cm.show()
+}
+
+func (cm *ContextMenuC) show() {
+	if !cm.popup.isOpen() && cm.push != nil {
+		cm.push(cm.keys())
+	}
+	cm.popup.open(cm.items)
+}
+
+// Close closes the menu and hands the keyboard back.
+func (cm *ContextMenuC) Close() {
+	if !cm.popup.isOpen() {
+		return
+	}
+	cm.popup.close()
+	if cm.pop != nil {
+		cm.pop()
+	}
+}
+
+// IsOpen reports whether the menu is open.
+func (cm *ContextMenuC) IsOpen() bool { return cm.popup.isOpen() }
+
+// activate runs the selected item, or opens its submenu.
+func (cm *ContextMenuC) activate() {
+	if cm.popup.openSub() {
+		return
+	}
+	it, ok := cm.popup.selected()
+	if !ok {
+		return
+	}
+	cm.Close()
+	if it.Action != nil {
+		it.Action()
+	}
+}
+
+// keys returns the router pushed while the menu is open. Printable keys go
+// to type-ahead; anything else it doesn't know is swallowed.
+func (cm *ContextMenuC) keys() *riffkey.Router {
+	if cm.router != nil {
+		return cm.router
+	}
+	r := riffkey.NewRouter().NoCounts()
+	down := func(riffkey.Match) { cm.popup.move(1) }
+	up := func(riffkey.Match) { cm.popup.move(-1) }
+	r.Handle("<Down>", down)
+	r.Handle("<C-n>", down)
+	r.Handle("<Up>", up)
+	r.Handle("<C-p>", up)
+	r.Handle("<Right>", func(riffkey.Match) { cm.popup.openSub() })
+	r.Handle("<Left>", func(riffkey.Match) { cm.popup.back() })
+	r.Handle("<Enter>", func(riffkey.Match) { cm.activate() })
+	r.Handle("<Esc>", func(riffkey.Match) {
+		if !cm.popup.back() {
+			cm.Close()
+		}
+	})
+	r.HandleUnmatched(func(k riffkey.Key) bool {
+		if k.Rune != 0 && k.Mod == riffkey.ModNone {
+			cm.popup.typeAhead(k.Rune, cm.now())
+		}
+		return true
+	})
+	cm.router = r
+	return r
+}
+
+func (cm *ContextMenuC) bindings() []binding {
+	return appendAccelerators(append([]binding(nil), cm.declaredBindings...), cm.items)
+}
+
+func (cm *ContextMenuC) setModal(push func(*riffkey.Router), pop func()) {
+	cm.push, cm.pop = push, pop
+}
+
+func (t *Template) compileContextMenuC(cm *ContextMenuC, parent int16, depth int) int16 {
+	cm.sub = newSubTemplate(cm.child, nil, 0)
+	t.pendingBindings = append(t.pendingBindings, cm.sub.pendingBindings...)
+	t.adoptLive(cm.sub)
+
+	render := func(buf *Buffer, x, y, w, h int16) {
+		cm.x, cm.y = x, y
+		cm.sub.clipMaxY = y + h
+		cm.sub.render(buf, x, y, w)
+		if cm.popup.isOpen() {
+			t.pendingOverlays = append(t.pendingOverlays, pendingOverlay{draw: cm.draw})
+		}
+	}
+	return t.compileCustom(Widget(cm.measure, render), parent, depth)
+}
+
+func (cm *ContextMenuC) measure(availW int16) (w, h int16) {
+	probe := availW < 0
+	if probe {
+		availW = subProbeWidth
+	}
+	cm.sub.distributeWidths(availW, nil)
+	cm.sub.layout(0)
+	if len(cm.sub.geom) == 0 {
+		return 0, 0
+	}
+	w = cm.sub.geom[0].W
+	if probe && w >= subProbeWidth {
+		w = -1 // the child fills
+	}
+	return w, cm.sub.Height()
+}
+
+func (cm *ContextMenuC) draw(buf *Buffer, screenW, screenH int16) {
+	x, y := cm.ax, cm.ay
+	if cm.atNode {
+		x, y = cm.x, cm.y+1
+	}
+	cm.popup.draw(buf, x, y, screenW, screenH)
+}
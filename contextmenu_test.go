@@ -0,0 +1,117 @@
+package glyph
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+func TestMenuTypeAhead(t *testing.T) {
+	var p menuPopup
+	p.open([]MenuItem{
+		{Label: "Open"},
+		{Label: "Save"},
+		{Label: "Save As"},
+		{Label: "Settings", Disabled: true},
+		{Label: "Share"},
+	})
+	at := time.Unix(0, 0)
+	typed := func(s string) string {
+		for _, r := range s {
+			at = at.Add(100 * time.Millisecond)
+			p.typeAhead(r, at)
+		}
+		it, _ := p.selected()
+		return it.Label
+	}
+
+	if got := typed("s"); got != "Save" {
+		t.Errorf("s -> %q, want Save", got)
+	}
+	if got := typed("a"); got != "Save" {
+		t.Errorf("sa -> %q, want Save", got)
+	}
+	if got := typed("ve "); got != "Save As" {
+		t.Errorf("save  -> %q, want Save As", got)
+	}
+
+	// after a pause a letter starts afresh, and repeating it steps through
+	at = at.Add(2 * time.Second)
+	if got := typed("s"); got != "Share" {
+		t.Errorf("fresh s -> %q, want Share (Settings is disabled)", got)
+	}
+	if got := typed("s"); got != "Save" {
+		t.Errorf("s again -> %q, want Save", got)
+	}
+	if got := typed("x"); got != "Save" {
+		t.Errorf("no match moved the cursor to %q", got)
+	}
+}
+
+func TestContextMenu(t *testing.T) {
+	var log []string
+	do := func(name string) func() { return func() { log = append(log, name) } }
+	cm := ContextMenu(Text("report.txt"),
+		MenuItem{Label: "Open", Action: do("open")},
+		MenuItem{Label: "Rename", Key: "<F2>", Action: do("rename")},
+		MenuItem{Label: "Move to", Sub: []MenuItem{
+			{Label: "Archive", Action: do("archive")},
+		}},
+		MenuSeparator(),
+		MenuItem{Label: "Delete", Action: do("delete")},
+	).BindOpen("m")
+	tmpl := Build(VBox(Text("files"), cm, Text("notes.md")))
+
+	keys := map[string]func(){}
+	for _, b := range tmpl.pendingBindings {
+		keys[b.pattern] = b.handler.(func())
+	}
+	if keys["m"] == nil || keys["<F2>"] == nil || len(tmpl.pendingModals) != 1 {
+		t.Fatalf("bindings not collected")
+	}
+
+	messageLines(tmpl, 24, 9)
+	var pushed int
+	cm.setModal(func(*riffkey.Router) { pushed++ }, func() { pushed-- })
+	keys["m"]()
+	if !cm.IsOpen() || pushed != 1 {
+		t.Fatalf("open=%v pushed=%d", cm.IsOpen(), pushed)
+	}
+	got := messageLines(tmpl, 24, 9)
+	want := []string{
+		"files",
+		"report.txt",
+		"┌─────────────┐",
+		"│ Open        │",
+		"│ Rename   F2 │",
+		"│ Move to   ▸ │",
+		"├─────────────┤",
+		"│ Delete      │",
+		"└─────────────┘",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// typing m jumps to Move to
+	in := riffkey.NewInput(cm.keys())
+	for _, k := range []riffkey.Key{{Rune: 'm'}, {Special: riffkey.SpecialRight}, {Special: riffkey.SpecialEnter}} {
+		in.Dispatch(k)
+	}
+	if cm.IsOpen() || pushed != 0 || strings.Join(log, ",") != "archive" {
+		t.Errorf("open=%v pushed=%d log=%q", cm.IsOpen(), pushed, log)
+	}
+
+	// OpenAt places the corner where it's told
+	cm.OpenAt(5, 0)
+	got = messageLines(tmpl, 24, 9)
+	if !strings.HasPrefix(got[0], "files┌") {
+		t.Errorf("OpenAt(5, 0) drew %q", got[0])
+	}
+	in.Dispatch(riffkey.Key{Special: riffkey.SpecialEscape})
+	if cm.IsOpen() {
+		t.Errorf("esc didn't close")
+	}
+}
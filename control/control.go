@@ -0,0 +1,181 @@
+// Package control lets other processes drive a running glyph app over a
+// unix socket, the way nvim --remote and tmux control mode let tools
+// drive those programs. Each line sent is a command line run through the
+// app's Commands, on the UI goroutine:
+//
+//	app.Command("view", "Switch view", app.Go)
+//	app.Command("log", "Append to the log", func(words ...string) { logs.Append(strings.Join(words, " ")) })
+//	app.Command("reload", "Re-read the config", reloadConfig)
+//
+//	srv, err := control.Listen(app, "")
+//	defer srv.Close()
+//
+// and from a shell, or another program with Send:
+//
+//	$ echo 'log deploy finished' | nc -U "$GLYPH_CONTROL"
+//	ok
+package control
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/kungfusheep/glyph"
+)
+
+// EnvVar is the environment variable Listen sets to the socket's path, so
+// that processes the app starts can find it.
+const EnvVar = "GLYPH_CONTROL"
+
+// Server answers on a control socket until it's closed or the app stops.
+type Server struct {
+	app  *glyph.App
+	path string
+	ln   net.Listener
+
+	mu    sync.Mutex
+	conns map[net.Conn]bool
+	done  bool
+}
+
+// Listen creates a socket at path, readable only by the user, and serves
+// it until the app stops. An empty path makes one in the temporary
+// directory named after the process. A socket left at path by a process
+// that's gone is replaced.
+//
+// Each line a client sends is run with the app's Commands and answered
+// with "ok", or "error: " and the message.
+func Listen(app *glyph.App, path string) (*Server, error) {
+	if path == "" {
+		path = filepath.Join(os.TempDir(), fmt.Sprintf("glyph-%d.sock", os.Getpid()))
+	}
+	if c, err := net.Dial("unix", path); err == nil {
+		c.Close()
+		return nil, fmt.Errorf("control: %s is in use", path)
+	}
+	os.Remove(path)
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		ln.Close()
+		return nil, err
+	}
+	os.Setenv(EnvVar, path)
+
+	s := &Server{app: app, path: path, ln: ln, conns: map[net.Conn]bool{}}
+	go s.accept()
+	go func() {
+		<-app.Context().Done()
+		s.Close()
+	}()
+	return s, nil
+}
+
+// Path returns the socket's path.
+func (s *Server) Path() string { return s.path }
+
+// Close stops serving, hangs up on clients and removes the socket.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	if s.done {
+		s.mu.Unlock()
+		return nil
+	}
+	s.done = true
+	for c := range s.conns {
+		c.Close()
+	}
+	s.mu.Unlock()
+	err := s.ln.Close()
+	os.Remove(s.path)
+	return err
+}
+
+func (s *Server) accept() {
+	for {
+		c, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		if s.done {
+			s.mu.Unlock()
+			c.Close()
+			return
+		}
+		s.conns[c] = true
+		s.mu.Unlock()
+		go s.serve(c)
+	}
+}
+
+func (s *Server) serve(c net.Conn) {
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, c)
+		s.mu.Unlock()
+		c.Close()
+	}()
+	lines := bufio.NewScanner(c)
+	for lines.Scan() {
+		line := strings.TrimSpace(lines.Text())
+		if line == "" {
+			continue
+		}
+		reply := "ok"
+		if err := s.run(line); err != nil {
+			reply = "error: " + strings.ReplaceAll(err.Error(), "\n", " ")
+		}
+		if _, err := fmt.Fprintln(c, reply); err != nil {
+			return
+		}
+	}
+}
+
+// run runs line on the UI goroutine and waits for it.
+func (s *Server) run(line string) error {
+	result := make(chan error, 1)
+	s.app.Post(func() { result <- s.app.Commands().Run(line) })
+	select {
+	case err := <-result:
+		return err
+	case <-s.app.Context().Done():
+		return errors.New("app stopped")
+	}
+}
+
+// Send runs a command line in the app listening at path, or at the path in
+// GLYPH_CONTROL if path is empty, and returns the error it answered with.
+func Send(path, line string) error {
+	if path == "" {
+		path = os.Getenv(EnvVar)
+		if path == "" {
+			return fmt.Errorf("control: no socket given and %s is not set", EnvVar)
+		}
+	}
+	c, err := net.Dial("unix", path)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if _, err := fmt.Fprintln(c, strings.ReplaceAll(line, "\n", " ")); err != nil {
+		return err
+	}
+	reply, err := bufio.NewReader(c).ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("control: no reply: %w", err)
+	}
+	reply = strings.TrimSpace(reply)
+	if msg, ok := strings.CutPrefix(reply, "error: "); ok {
+		return errors.New(msg)
+	}
+	return nil
+}
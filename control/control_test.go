@@ -0,0 +1,91 @@
+package control
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kungfusheep/glyph"
+)
+
+func TestControl(t *testing.T) {
+	app, _ := glyph.NewApp()
+	app.SetBackend(glyph.NewBufferBackend(20, 2))
+	var logs []string
+	app.Command("log", "", func(words ...string) { logs = append(logs, strings.Join(words, " ")) })
+
+	path := filepath.Join(t.TempDir(), "app.sock")
+	srv, err := Listen(app, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if os.Getenv(EnvVar) != path {
+		t.Errorf("%s=%q", EnvVar, os.Getenv(EnvVar))
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("socket %v %v", info, err)
+	}
+	if _, err := Listen(app, path); err == nil {
+		t.Error("listened on a socket in use")
+	}
+
+	// stand in for the app's render loop, which runs posted work
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		for {
+			select {
+			case <-stop:
+				return
+			case <-time.After(time.Millisecond):
+				app.RenderNow()
+			}
+		}
+	}()
+
+	if err := Send(path, "log deploy finished"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Send("", "log via env"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Send(path, "bogus"); err == nil || err.Error() != "unknown command: bogus" {
+		t.Errorf("error %v", err)
+	}
+
+	// several lines on one connection
+	c, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Write([]byte("log one\n\nlog two\n"))
+	r := bufio.NewReader(c)
+	for range 2 {
+		if reply, _ := r.ReadString('\n'); reply != "ok\n" {
+			t.Errorf("reply %q", reply)
+		}
+	}
+	c.Close()
+
+	want := "deploy finished,via env,one,two"
+	if got := strings.Join(logs, ","); got != want {
+		t.Errorf("logged %q, want %q", got, want)
+	}
+
+	app.Stop()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("socket left behind: %v", err)
+	}
+	srv.Close()
+}
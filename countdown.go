@@ -0,0 +1,178 @@
+package glyph
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// WallClockC shows the current time in a time.Format layout, re-rendering
+// as each second or minute turns over.
+//
+//	WallClock("15:04").FG(BrightBlack)
+type WallClockC struct {
+	ticking
+	layout string
+	loc    *time.Location
+	style  Style
+}
+
+// WallClock creates a clock showing the time in layout, "15:04:05" if
+// empty.
+func WallClock(layout string) *WallClockC {
+	if layout == "" {
+		layout = time.TimeOnly
+	}
+	return &WallClockC{layout: layout}
+}
+
+// In shows the time in loc rather than local time.
+func (c *WallClockC) In(loc *time.Location) *WallClockC {
+	c.loc = loc
+	return c
+}
+
+// Style sets the text style.
+func (c *WallClockC) Style(s Style) *WallClockC {
+	c.style = s
+	return c
+}
+
+// FG sets the foreground colour.
+func (c *WallClockC) FG(col Color) *WallClockC {
+	c.style.FG = col
+	return c
+}
+
+func (t *Template) compileWallClockC(c *WallClockC, parent int16, depth int) int16 {
+	t.collectUpdateHook(c)
+	t.collectClockUser(c)
+	return t.compileCustom(Widget(c.measure, c.render), parent, depth)
+}
+
+func (c *WallClockC) text() string {
+	now := c.now()
+	if c.loc != nil {
+		now = now.In(c.loc)
+	}
+	return now.Format(c.layout)
+}
+
+func (c *WallClockC) measure(availW int16) (w, h int16) {
+	return int16(runewidth.StringWidth(c.text())), 1
+}
+
+func (c *WallClockC) render(buf *Buffer, x, y, w, h int16) {
+	buf.WriteStringFast(int(x), int(y), c.text(), c.style, int(w))
+	// layouts without seconds only change on the minute
+	step := time.Minute
+	if strings.Contains(c.layout, "05") {
+		step = time.Second
+	}
+	now := c.now()
+	c.after(now.Truncate(step).Add(step).Sub(now))
+}
+
+// CountdownC counts down to a target time, showing what's left as "4:05"
+// or "1:02:03", and calls OnExpire once when it reaches zero.
+//
+//	Countdown(&deadline).OnExpire(func() { status = "time's up" })
+//
+// Setting the target to a new time starts it again.
+type CountdownC struct {
+	ticking
+	target   *time.Time
+	format   func(time.Duration) string
+	onExpire func()
+	expired  time.Time // target that OnExpire last fired for
+	style    Style
+}
+
+// Countdown creates a countdown to *target. A zero target shows nothing.
+func Countdown(target *time.Time) *CountdownC {
+	return &CountdownC{target: target, format: formatCountdown}
+}
+
+// Format sets how the remaining time is shown. It is given the time left
+// rounded up to the second, and zero once expired.
+func (c *CountdownC) Format(fn func(time.Duration) string) *CountdownC {
+	c.format = fn
+	return c
+}
+
+// OnExpire sets a callback run on the UI goroutine when the countdown
+// reaches zero.
+func (c *CountdownC) OnExpire(fn func()) *CountdownC {
+	c.onExpire = fn
+	return c
+}
+
+// Style sets the text style.
+func (c *CountdownC) Style(s Style) *CountdownC {
+	c.style = s
+	return c
+}
+
+// FG sets the foreground colour.
+func (c *CountdownC) FG(col Color) *CountdownC {
+	c.style.FG = col
+	return c
+}
+
+func (t *Template) compileCountdownC(c *CountdownC, parent int16, depth int) int16 {
+	t.collectUpdateHook(c)
+	t.collectClockUser(c)
+	return t.compileCustom(Widget(c.measure, c.render), parent, depth)
+}
+
+// remaining returns the time left rounded up to the second, and how long
+// until that changes.
+func (c *CountdownC) remaining() (left, next time.Duration) {
+	d := c.target.Sub(c.now())
+	if d <= 0 {
+		return 0, 0
+	}
+	left = (d + time.Second - 1).Truncate(time.Second)
+	return left, d - (left - time.Second)
+}
+
+func (c *CountdownC) text() string {
+	if c.target == nil || c.target.IsZero() {
+		return ""
+	}
+	left, _ := c.remaining()
+	return c.format(left)
+}
+
+func (c *CountdownC) measure(availW int16) (w, h int16) {
+	return int16(runewidth.StringWidth(c.text())), 1
+}
+
+func (c *CountdownC) render(buf *Buffer, x, y, w, h int16) {
+	buf.WriteStringFast(int(x), int(y), c.text(), c.style, int(w))
+	if c.target == nil || c.target.IsZero() {
+		return
+	}
+	left, next := c.remaining()
+	if left > 0 {
+		c.after(next)
+		return
+	}
+	if !c.expired.Equal(*c.target) {
+		c.expired = *c.target
+		if c.onExpire != nil {
+			c.onExpire()
+		}
+	}
+}
+
+// formatCountdown shows d as m:ss, or h:mm:ss from an hour up.
+func formatCountdown(d time.Duration) string {
+	s := int(d / time.Second)
+	if s >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+	}
+	return fmt.Sprintf("%d:%02d", s/60, s%60)
+}
@@ -0,0 +1,88 @@
+package glyph
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWallClock(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 30, 58, int(500*time.Millisecond), time.UTC)
+	clock := NewFakeClock(start)
+	renders := 0
+
+	tmpl := Build(HBox(WallClock("15:04").In(time.UTC), Text("|")))
+	tmpl.SetClock(clock)
+	for _, u := range tmpl.pendingUpdates {
+		*u = func() { renders++ }
+	}
+	frame := func() string {
+		buf := NewBuffer(20, 1)
+		tmpl.Execute(buf, 20, 1)
+		return buf.GetLine(0)
+	}
+
+	if got := frame(); got != "12:30|" {
+		t.Fatalf("line = %q", got)
+	}
+	clock.Advance(time.Second)
+	if renders != 0 {
+		t.Fatalf("minute layout rendered before the minute turned")
+	}
+	clock.Advance(500 * time.Millisecond)
+	if renders != 1 || frame() != "12:31|" {
+		t.Errorf("renders = %d line = %q", renders, frame())
+	}
+}
+
+func TestCountdown(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	target := start.Add(2*time.Second + 500*time.Millisecond)
+	expired := 0
+
+	tmpl := Build(Countdown(&target).OnExpire(func() { expired++ }))
+	tmpl.SetClock(clock)
+	for _, u := range tmpl.pendingUpdates {
+		*u = func() {}
+	}
+	frame := func() string {
+		buf := NewBuffer(20, 1)
+		tmpl.Execute(buf, 20, 1)
+		return buf.GetLine(0)
+	}
+
+	if got := frame(); got != "0:03" {
+		t.Fatalf("line = %q", got)
+	}
+	clock.Advance(500 * time.Millisecond)
+	if got := frame(); got != "0:02" {
+		t.Errorf("line = %q", got)
+	}
+	clock.Advance(2 * time.Second)
+	if got := frame(); got != "0:00" || expired != 1 {
+		t.Errorf("line = %q expired = %d", got, expired)
+	}
+	frame()
+	if expired != 1 {
+		t.Errorf("OnExpire fired %d times", expired)
+	}
+
+	// a new target starts it again
+	target = clock.Now().Add(time.Hour + 61*time.Second)
+	if got := frame(); got != "1:01:01" {
+		t.Errorf("restarted = %q", got)
+	}
+}
+
+func TestFormatCountdown(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		0:                           "0:00",
+		59 * time.Second:            "0:59",
+		245 * time.Second:           "4:05",
+		2*time.Hour + 3*time.Second: "2:00:03",
+	} {
+		if got := formatCountdown(d); got != want {
+			t.Errorf("formatCountdown(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
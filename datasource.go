@@ -0,0 +1,204 @@
+package glyph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// SourceState is where a DataSource is: waiting for its first result,
+// holding data, or failing.
+type SourceState int
+
+const (
+	SourceLoading SourceState = iota
+	SourceReady
+	SourceError
+)
+
+// DataSource holds data fetched in the background along with its state, in
+// plain fields the view binds to:
+//
+//	status := PollJSON[Status]("https://ci.example.com/api/status", 10*time.Second)
+//	go status.Run(app.Context(), app.Post)
+//
+//	Switch(&status.State).
+//	    Case(SourceLoading, Spinner(&frame)).
+//	    Case(SourceError, Text(&status.ErrText).FG(Red)).
+//	    Default(Text(&status.Data.Summary))
+//
+// Data keeps the last good result when a later fetch fails, so a view can
+// show stale data beside the error.
+type DataSource[T any] struct {
+	State   SourceState
+	Data    T
+	Err     error
+	ErrText string    // Err as text, "" when there is none
+	Updated time.Time // when Data last changed
+
+	fetch    func(ctx context.Context) (apply func(*DataSource[T]), err error)
+	interval time.Duration
+	refresh  chan struct{}
+}
+
+// Refresh asks a running source to fetch now rather than waiting for the
+// next interval. Safe to call from any goroutine.
+func (s *DataSource[T]) Refresh() {
+	select {
+	case s.refresh <- struct{}{}:
+	default:
+	}
+}
+
+// Run fetches immediately and then every interval until ctx is done. Each
+// change is handed to post so it lands on the UI goroutine; pass app.Post,
+// or nil to update the fields directly. Fetches that change nothing are not
+// posted, so an unchanged endpoint costs no renders.
+func (s *DataSource[T]) Run(ctx context.Context, post func(func())) error {
+	t := time.NewTicker(s.interval)
+	defer t.Stop()
+	failing := "" // the error last posted, so a repeat isn't posted again
+	for {
+		apply, err := s.fetch(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		switch {
+		case err == nil:
+			failing = ""
+		case err.Error() == failing:
+			apply = nil
+		default:
+			failing = err.Error()
+			apply = func(s *DataSource[T]) {
+				s.State = SourceError
+				s.Err = err
+				s.ErrText = err.Error()
+			}
+		}
+		if apply != nil {
+			if post == nil {
+				apply(s)
+			} else {
+				post(func() { apply(s) })
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		case <-s.refresh:
+			t.Reset(s.interval)
+		}
+	}
+}
+
+// PollJSON creates a source that GETs url every interval and decodes the
+// JSON body into T. Responses are cached by ETag and Last-Modified, so an
+// endpoint that supports conditional requests answers 304 and sends
+// nothing.
+func PollJSON[T any](url string, interval time.Duration) *DataSource[T] {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return &DataSource[T]{
+			fetch:    func(context.Context) (func(*DataSource[T]), error) { return nil, err },
+			interval: interval,
+			refresh:  make(chan struct{}, 1),
+		}
+	}
+	return PollHTTP(req, interval, func(r io.Reader) (T, error) {
+		var v T
+		err := json.NewDecoder(r).Decode(&v)
+		return v, err
+	})
+}
+
+// PollHTTP is PollJSON for a request of your own, with headers or auth
+// set, and a decoder for bodies that aren't JSON. Each fetch sends a clone
+// of req and is cut off after interval.
+func PollHTTP[T any](req *http.Request, interval time.Duration, decode func(io.Reader) (T, error)) *DataSource[T] {
+	p := &httpPoll[T]{req: req, decode: decode, timeout: interval}
+	return &DataSource[T]{
+		fetch:    p.fetch,
+		interval: interval,
+		refresh:  make(chan struct{}, 1),
+	}
+}
+
+// httpPoll remembers enough of the last response to skip ones that
+// haven't changed.
+type httpPoll[T any] struct {
+	req     *http.Request
+	decode  func(io.Reader) (T, error)
+	timeout time.Duration
+
+	etag, modified string
+	body           []byte
+	ok             bool // last fetch succeeded
+}
+
+func (p *httpPoll[T]) fetch(ctx context.Context) (func(*DataSource[T]), error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	req := p.req.Clone(ctx)
+	if p.etag != "" {
+		req.Header.Set("If-None-Match", p.etag)
+	}
+	if p.modified != "" {
+		req.Header.Set("If-Modified-Since", p.modified)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		p.ok = false
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified:
+		return p.unchanged(), nil
+	case resp.StatusCode < 200 || resp.StatusCode > 299:
+		p.ok = false
+		return nil, fmt.Errorf("GET %s: %s", req.URL, resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		p.ok = false
+		return nil, err
+	}
+	p.etag = resp.Header.Get("ETag")
+	p.modified = resp.Header.Get("Last-Modified")
+	if p.body != nil && bytes.Equal(body, p.body) {
+		return p.unchanged(), nil
+	}
+	v, err := p.decode(bytes.NewReader(body))
+	if err != nil {
+		p.ok = false
+		return nil, fmt.Errorf("GET %s: %w", req.URL, err)
+	}
+	p.body = body
+	p.ok = true
+	return func(s *DataSource[T]) {
+		s.State = SourceReady
+		s.Data = v
+		s.Err, s.ErrText = nil, ""
+		s.Updated = time.Now()
+	}, nil
+}
+
+// unchanged returns nothing to apply, unless the last fetch failed and the
+// error needs clearing.
+func (p *httpPoll[T]) unchanged() func(*DataSource[T]) {
+	if p.ok {
+		return nil
+	}
+	p.ok = true
+	return func(s *DataSource[T]) {
+		s.State = SourceReady
+		s.Err, s.ErrText = nil, ""
+	}
+}
@@ -0,0 +1,101 @@
+package glyph
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPollJSON(t *testing.T) {
+	type status struct {
+		Build string `json:"build"`
+	}
+
+	var mu sync.Mutex
+	code, body, etag := http.StatusOK, `{"build":"green"}`, `"v1"`
+	fetches := make(chan struct{}, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() { fetches <- struct{}{} }()
+		mu.Lock()
+		defer mu.Unlock()
+		if r.Header.Get("If-None-Match") == etag && code == http.StatusOK {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.WriteHeader(code)
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+	set := func(c int, b, e string) {
+		mu.Lock()
+		code, body, etag = c, b, e
+		mu.Unlock()
+	}
+
+	src := PollJSON[status](srv.URL, time.Hour)
+	if src.State != SourceLoading {
+		t.Fatalf("initial state = %v", src.State)
+	}
+
+	posted := make(chan func(), 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go src.Run(ctx, func(fn func()) { posted <- fn })
+
+	// fetch waits for the next fetch and applies whatever it posted,
+	// reporting whether anything was
+	fetch := func() bool {
+		<-fetches
+		select {
+		case fn := <-posted:
+			fn()
+			return true
+		case <-time.After(50 * time.Millisecond):
+			return false
+		}
+	}
+
+	if !fetch() || src.State != SourceReady || src.Data.Build != "green" || src.Updated.IsZero() {
+		t.Fatalf("first fetch: %+v", src)
+	}
+
+	src.Refresh()
+	if fetch() {
+		t.Error("unchanged (304) fetch was posted")
+	}
+
+	set(http.StatusServiceUnavailable, "down", `"v1"`)
+	src.Refresh()
+	if !fetch() || src.State != SourceError || src.ErrText == "" {
+		t.Fatalf("error fetch: %+v", src)
+	}
+	if src.Data.Build != "green" {
+		t.Errorf("stale data dropped on error: %+v", src.Data)
+	}
+	src.Refresh()
+	if fetch() {
+		t.Error("repeated error was posted")
+	}
+
+	set(http.StatusOK, `{"build":"red"}`, `"v2"`)
+	src.Refresh()
+	if !fetch() || src.State != SourceReady || src.Err != nil || src.Data.Build != "red" {
+		t.Errorf("recovered fetch: %+v", src)
+	}
+}
+
+func TestPollJSONBadURL(t *testing.T) {
+	src := PollJSON[int]("://nope", time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	src.Run(ctx, func(fn func()) {
+		fn()
+		cancel()
+	})
+	if src.State != SourceError || src.Err == nil {
+		t.Errorf("state = %v err = %v", src.State, src.Err)
+	}
+}
@@ -0,0 +1,41 @@
+package glyph
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+var (
+	pasteStart = []byte("\x1b[200~")
+	pasteEnd   = []byte("\x1b[201~")
+)
+
+// DecodeInput parses raw terminal input into keys the same way App does:
+// escape sequences, bracketed paste and UTF-8 included. Sequences cut off
+// at the end of data decode as whatever prefix is there, so it never waits
+// for more input. Use it to test or fuzz input handling without a
+// terminal.
+func DecodeInput(data []byte) []riffkey.Key {
+	// the reader waits indefinitely for the end of a bracketed paste, so
+	// close one left open by the input
+	if start := bytes.LastIndex(data, pasteStart); start >= 0 && !bytes.Contains(data[start:], pasteEnd) {
+		data = append(data[:len(data):len(data)], pasteEnd...)
+	}
+	r := riffkeyReader(bytes.NewReader(data)).EscapeTimeout(10 * time.Millisecond)
+	var keys []riffkey.Key
+	// every key consumes at least one byte; the cap guards against a
+	// decoder that stops making progress
+	for i := 0; i <= len(data); i++ {
+		k, err := r.ReadKey()
+		if err != nil {
+			if k.Paste != "" {
+				keys = append(keys, k)
+			}
+			break
+		}
+		keys = append(keys, k)
+	}
+	return keys
+}
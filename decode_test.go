@@ -0,0 +1,59 @@
+package glyph
+
+import (
+	"testing"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+func TestDecodeInput(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []riffkey.Key
+	}{
+		{"runes", "ab", []riffkey.Key{{Rune: 'a'}, {Rune: 'b'}}},
+		{"arrow", "\x1b[A", []riffkey.Key{{Special: riffkey.SpecialUp}}},
+		{"utf8", "£", []riffkey.Key{{Rune: '£'}}},
+		{"paste", "\x1b[200~hi\x1b[201~", []riffkey.Key{{Paste: "hi"}}},
+		{"alt", "\x1bx", []riffkey.Key{{Rune: 'x', Mod: riffkey.ModAlt}}},
+		{"lone escape", "\x1b", []riffkey.Key{{Special: riffkey.SpecialEscape}}},
+		{"cut-off paste", "\x1b[200~ab\x1b", []riffkey.Key{{Paste: "ab\x1b"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DecodeInput([]byte(tt.in))
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d keys %v, want %v", len(got), got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("key %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func FuzzDecodeInput(f *testing.F) {
+	for _, seed := range []string{
+		"hello", "\x1b[A\x1b[B", "\x1b[1;5C", "\x1bOP", "\x1b[200~paste\x1b[201~",
+		"£€😀", "\x1b[", "\x1b[200~unterminated", "\x1b[200~ab\x1b", "\xe2\x82", "\xff\xfe\x00", "\x1b\x1b\x1b",
+		"\x1b[99999999999999999999~", "\x1b[<0;10;20M",
+	} {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		keys := DecodeInput(data)
+		if len(keys) > len(data)+1 {
+			t.Fatalf("%d keys from %d bytes", len(keys), len(data))
+		}
+		size := 0
+		for _, k := range keys {
+			size += len(k.Paste)
+		}
+		if size > len(data) {
+			t.Fatalf("paste content %d bytes from %d bytes of input", size, len(data))
+		}
+	})
+}
@@ -0,0 +1,129 @@
+package glyph
+
+import "github.com/kungfusheep/riffkey"
+
+// digraphs maps two typed characters to the one they stand for, after
+// RFC 1345 and vim's table. The pair can be typed either way round.
+var digraphs = map[[2]rune]rune{}
+
+func init() {
+	for _, d := range []struct {
+		pair string
+		r    rune
+	}{
+		// arrows
+		{"<-", '←'}, {"->", '→'}, {"-!", '↑'}, {"-v", '↓'}, {"<>", '↔'}, {"UD", '↕'},
+		{"<=", '⇐'}, {"=>", '⇒'}, {"==", '⇔'},
+		// shapes
+		{"0M", '●'}, {"0m", '○'}, {"Ob", '∘'}, {"Sb", '∙'}, {"oo", '•'},
+		{"fS", '■'}, {"OS", '□'}, {"Db", '◆'}, {"Dw", '◇'},
+		{"UT", '▲'}, {"uT", '△'}, {"PR", '▶'}, {"Tr", '▷'}, {"Dt", '▼'}, {"dT", '▽'}, {"PL", '◀'}, {"Tl", '◁'},
+		{"*2", '★'}, {"*1", '☆'}, {"cS", '♠'}, {"cH", '♥'}, {"cD", '♦'}, {"cC", '♣'},
+		{"OK", '✓'}, {"XX", '✗'},
+		// box drawing and blocks
+		{"hh", '─'}, {"vv", '│'}, {"dr", '┌'}, {"dl", '┐'}, {"ur", '└'}, {"ul", '┘'},
+		{"vr", '├'}, {"vl", '┤'}, {"dh", '┬'}, {"uh", '┴'}, {"vh", '┼'},
+		{"FB", '█'}, {"TB", '▀'}, {"LB", '▄'}, {".S", '░'}, {":S", '▒'}, {"?S", '▓'},
+		// maths
+		{"!=", '≠'}, {"=<", '≤'}, {">=", '≥'}, {"?2", '≈'}, {"=3", '≡'}, {"+-", '±'},
+		{"*X", '×'}, {"-:", '÷'}, {"RT", '√'}, {"00", '∞'}, {"FA", '∀'}, {"TE", '∃'},
+		{"dP", '∂'}, {"/0", '∅'}, {"DE", '∆'}, {"NB", '∇'}, {"(-", '∈'}, {"*P", '∏'},
+		{"+Z", '∑'}, {"In", '∫'}, {"AN", '∧'}, {"OR", '∨'}, {"(U", '∩'}, {")U", '∪'},
+		{"(C", '⊂'}, {")C", '⊃'}, {".M", '·'}, {"DG", '°'}, {"My", 'µ'},
+		{"12", '½'}, {"14", '¼'}, {"34", '¾'}, {"1S", '¹'}, {"2S", '²'}, {"3S", '³'},
+		// punctuation and signs
+		{"-N", '–'}, {"-M", '—'}, {"'6", '‘'}, {"'9", '’'}, {"\"6", '“'}, {"\"9", '”'},
+		{",.", '…'}, {"<<", '«'}, {">>", '»'}, {"!I", '¡'}, {"?I", '¿'}, {"NS", '\u00a0'},
+		{"SE", '§'}, {"PI", '¶'}, {"Co", '©'}, {"Rg", '®'}, {"TM", '™'}, {"/-", '†'}, {"/=", '‡'},
+		{"Eu", '€'}, {"Pd", '£'}, {"Ye", '¥'}, {"Ct", '¢'}, {"Cu", '¤'},
+		{"ss", 'ß'}, {"ae", 'æ'}, {"AE", 'Æ'}, {"o/", 'ø'}, {"O/", 'Ø'}, {"aa", 'å'}, {"AA", 'Å'},
+		{"c,", 'ç'}, {"C,", 'Ç'}, {"n?", 'ñ'}, {"N?", 'Ñ'},
+	} {
+		r := []rune(d.pair)
+		digraphs[[2]rune{r[0], r[1]}] = d.r
+	}
+
+	// Greek: a* is α, A* is Α
+	greek := "ABGDEZYHIKLMNCOPRSTUFXQW"
+	upper := []rune("ΑΒΓΔΕΖΗΘΙΚΛΜΝΞΟΠΡΣΤΥΦΧΨΩ")
+	lower := []rune("αβγδεζηθικλμνξοπρστυφχψω")
+	for i, c := range greek {
+		digraphs[[2]rune{c, '*'}] = upper[i]
+		digraphs[[2]rune{c + 'a' - 'A', '*'}] = lower[i]
+	}
+
+	// accents: a' is á, a! à, a> â, a: ä, a? ã
+	marks := []struct {
+		mark  rune
+		lower string
+		upper string
+	}{
+		{'\'', "aáeéiíoóuúyý", "AÁEÉIÍOÓUÚYÝ"},
+		{'!', "aàeèiìoòuù", "AÀEÈIÌOÒUÙ"},
+		{'>', "aâeêiîoôuû", "AÂEÊIÎOÔUÛ"},
+		{':', "aäeëiïoöuüyÿ", "AÄEËIÏOÖUÜ"},
+		{'?', "aãoõ", "AÃOÕ"},
+	}
+	for _, m := range marks {
+		for _, set := range []string{m.lower, m.upper} {
+			rs := []rune(set)
+			for i := 0; i+1 < len(rs); i += 2 {
+				digraphs[[2]rune{rs[i], m.mark}] = rs[i+1]
+			}
+		}
+	}
+}
+
+// Digraph returns the character two typed characters stand for, as Ctrl-K
+// takes them in text inputs: "->" is →, "a*" is α, "e'" is é and "OK" is
+// ✓. Either order works.
+func Digraph(a, b rune) (rune, bool) {
+	if r, ok := digraphs[[2]rune{a, b}]; ok {
+		return r, true
+	}
+	r, ok := digraphs[[2]rune{b, a}]
+	return r, ok
+}
+
+// AddDigraph adds or replaces the character typed with pair, which must be
+// two characters.
+func AddDigraph(pair string, r rune) {
+	rs := []rune(pair)
+	if len(rs) == 2 {
+		digraphs[[2]rune{rs[0], rs[1]}] = r
+	}
+}
+
+// digraphKeys wraps a text key handler so that Ctrl-K followed by two
+// characters types the digraph they make. An unknown pair types the
+// second character, as vim does; Esc or any other key abandons it.
+func digraphKeys(next func(riffkey.Key) bool) func(riffkey.Key) bool {
+	var pending bool
+	var first rune
+	return func(k riffkey.Key) bool {
+		switch {
+		case !pending && k.Rune == 'k' && k.Mod == riffkey.ModCtrl:
+			pending, first = true, 0
+			return true
+		case !pending:
+			return next(k)
+		case k.Rune != 0 && k.Mod&^riffkey.ModShift == 0:
+			if first == 0 {
+				first = k.Rune
+				return true
+			}
+			pending = false
+			r, ok := Digraph(first, k.Rune)
+			if !ok {
+				r = k.Rune
+			}
+			return next(riffkey.Key{Rune: r})
+		default:
+			pending = false
+			if k.Special == riffkey.SpecialEscape {
+				return true
+			}
+			return next(k)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package glyph
+
+import (
+	"testing"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+func TestDigraph(t *testing.T) {
+	for _, c := range []struct {
+		a, b rune
+		want rune
+	}{
+		{'-', '>', '→'}, {'>', '-', '→'}, {'a', '*', 'α'}, {'W', '*', 'Ω'},
+		{'e', '\'', 'é'}, {'u', ':', 'ü'}, {'U', 'T', '▲'}, {'0', 'M', '●'},
+	} {
+		if got, ok := Digraph(c.a, c.b); !ok || got != c.want {
+			t.Errorf("Digraph(%q, %q) = %q %v, want %q", c.a, c.b, got, ok, c.want)
+		}
+	}
+	if _, ok := Digraph('q', 'q'); ok {
+		t.Error("unknown pair found")
+	}
+	AddDigraph("pi", 'π')
+	if got, _ := Digraph('p', 'i'); got != 'π' {
+		t.Errorf("added digraph %q", got)
+	}
+}
+
+func TestDigraphKeys(t *testing.T) {
+	b := NewEditBuffer("")
+	keys := TextArea(b).Bind().Digraphs().keyHandler()
+	ctrlK := riffkey.Key{Rune: 'k', Mod: riffkey.ModCtrl}
+	press := func(ks ...riffkey.Key) {
+		for _, k := range ks {
+			keys(k)
+		}
+	}
+	r := func(c rune) riffkey.Key { return riffkey.Key{Rune: c} }
+
+	press(r('x'), ctrlK, r('-'), r('>'), ctrlK, r('q'), r('z'))
+	if b.Text() != "x→z" {
+		t.Errorf("typed %q", b.Text())
+	}
+	// Esc abandons the digraph without typing anything
+	press(ctrlK, r('a'), riffkey.Key{Special: riffkey.SpecialEscape}, r('!'))
+	if b.Text() != "x→z!" {
+		t.Errorf("after cancel %q", b.Text())
+	}
+
+	var value string
+	in := Input(&value).Bind().Digraphs()
+	h := in.textBinding().handler()
+	for _, k := range []riffkey.Key{ctrlK, r('a'), r('*'), r('='), ctrlK, r('O'), r('K')} {
+		h(k)
+	}
+	if in.field.Value != "α=✓" {
+		t.Errorf("input %q", in.field.Value)
+	}
+}
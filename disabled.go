@@ -0,0 +1,102 @@
+package glyph
+
+// Interactive widgets can be disabled or made read-only through a bool
+// they watch, so a form can lock a field while it saves or until another
+// is filled in:
+//
+//	Input(&email).Disabled(&saving)
+//	Checkbox(&agree, "I agree").ReadOnly(&signed)
+//	Jump(Text("Submit"), submit).Key("<CR>").Disabled(&saving)
+//
+// A disabled widget is drawn in DefaultDisabledStyle, skipped by focus
+// cycling and jump mode, and ignores its keys. A read-only one looks and
+// focuses as usual but won't change.
+
+// DefaultDisabledStyle is how disabled widgets are drawn, merged into their
+// own styles. Set it to a theme's Disabled style to follow the theme.
+var DefaultDisabledStyle = Style{Attr: AttrDim}
+
+// disabler is implemented by focusables that can be disabled, so focus
+// passes them by.
+type disabler interface {
+	isDisabled() bool
+}
+
+// isSet reports whether an optional bool binding is true.
+func isSet(b *bool) bool {
+	return b != nil && *b
+}
+
+// grayWhen draws child in DefaultDisabledStyle while disabled is true.
+func grayWhen(disabled *bool, child any) any {
+	if disabled == nil {
+		return child
+	}
+	return If(disabled).Then(HBox.CascadeStyle(&DefaultDisabledStyle)(child)).Else(child)
+}
+
+// disabledStyle merges DefaultDisabledStyle into s, as a cascaded style
+// would be.
+func disabledStyle(s Style) Style {
+	d := DefaultDisabledStyle
+	s.Attr |= d.Attr
+	if s.FG.Mode == ColorDefault {
+		s.FG = d.FG
+	}
+	if s.BG.Mode == ColorDefault {
+		s.BG = d.BG
+	}
+	return s
+}
+
+// Disabled disables the input while *b is true.
+func (i *InputC) Disabled(b *bool) *InputC {
+	i.disabled = b
+	return i
+}
+
+// ReadOnly stops the input's value being typed over while *b is true.
+func (i *InputC) ReadOnly(b *bool) *InputC {
+	i.readOnly = b
+	return i
+}
+
+func (i *InputC) isDisabled() bool { return isSet(i.disabled) }
+func (i *InputC) locked() bool     { return isSet(i.disabled) || isSet(i.readOnly) }
+
+// Disabled disables the checkbox while *b is true.
+func (c *CheckboxC) Disabled(b *bool) *CheckboxC {
+	c.disabled = b
+	return c
+}
+
+// ReadOnly stops the checkbox toggling while *b is true.
+func (c *CheckboxC) ReadOnly(b *bool) *CheckboxC {
+	c.readOnly = b
+	return c
+}
+
+func (c *CheckboxC) isDisabled() bool { return isSet(c.disabled) }
+func (c *CheckboxC) locked() bool     { return isSet(c.disabled) || isSet(c.readOnly) }
+
+// Disabled disables the radio group while *b is true.
+func (r *RadioC) Disabled(b *bool) *RadioC {
+	r.disabled = b
+	return r
+}
+
+// ReadOnly stops the selection changing while *b is true.
+func (r *RadioC) ReadOnly(b *bool) *RadioC {
+	r.readOnly = b
+	return r
+}
+
+func (r *RadioC) isDisabled() bool { return isSet(r.disabled) }
+func (r *RadioC) locked() bool     { return isSet(r.disabled) || isSet(r.readOnly) }
+
+// Disabled disables the target while *b is true: jump mode passes it by
+// and its Key does nothing.
+func (j JumpC) Disabled(b *bool) JumpC {
+	j.disabled = b
+	return j
+}
@@ -0,0 +1,110 @@
+package glyph
+
+import (
+	"testing"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+func TestDisabledCheckbox(t *testing.T) {
+	checked, disabled := false, true
+	cb := Checkbox(&checked, "agree").Disabled(&disabled)
+	tmpl := Build(cb)
+
+	buf := NewBuffer(20, 1)
+	tmpl.Execute(buf, 20, 1)
+	if !buf.Get(2, 0).Style.Attr.Has(AttrDim) {
+		t.Error("disabled checkbox not dimmed")
+	}
+	cb.Toggle()
+	if checked {
+		t.Error("disabled checkbox toggled")
+	}
+
+	disabled = false
+	buf.Clear()
+	tmpl.Execute(buf, 20, 1)
+	if buf.Get(2, 0).Style.Attr.Has(AttrDim) {
+		t.Error("enabled checkbox still dimmed")
+	}
+	cb.Toggle()
+	if !checked {
+		t.Error("enabled checkbox didn't toggle")
+	}
+}
+
+func TestReadOnlyRadio(t *testing.T) {
+	sel, readOnly := 0, true
+	r := Radio(&sel, "a", "b").ReadOnly(&readOnly)
+	r.Next()
+	if sel != 0 {
+		t.Error("read-only radio moved")
+	}
+	buf := NewBuffer(10, 2)
+	Build(r).Execute(buf, 10, 2)
+	if buf.Get(2, 0).Style.Attr.Has(AttrDim) {
+		t.Error("read-only radio dimmed")
+	}
+}
+
+func TestFocusSkipsDisabled(t *testing.T) {
+	off := true
+	fm := NewFocusManager()
+	Input().ManagedBy(fm)
+	Input().ManagedBy(fm).Disabled(&off)
+	Input().ManagedBy(fm)
+
+	fm.Next()
+	if fm.Current() != 2 {
+		t.Errorf("Next went to %d, want 2", fm.Current())
+	}
+	fm.Prev()
+	if fm.Current() != 0 {
+		t.Errorf("Prev went to %d, want 0", fm.Current())
+	}
+	fm.Focus(1)
+	if fm.Current() != 0 {
+		t.Errorf("focused disabled item %d", fm.Current())
+	}
+}
+
+func TestDisabledInput(t *testing.T) {
+	value, disabled := "hello", true
+	buf := NewBuffer(10, 1)
+	Build(Input(&value).Disabled(&disabled)).Execute(buf, 10, 1)
+	for x := range 5 {
+		if c := buf.Get(x, 0); !c.Style.Attr.Has(AttrDim) || c.Style.Attr.Has(AttrInverse) {
+			t.Errorf("cell %d of disabled input: %+v", x, c.Style)
+		}
+	}
+}
+
+func TestReadOnlyInput(t *testing.T) {
+	value, readOnly := "fixed", true
+	fm := NewFocusManager()
+	in := Input(&value).ManagedBy(fm).ReadOnly(&readOnly)
+	fm.HandleKey(riffkey.Key{Rune: 'x'})
+	if in.Value() != "fixed" {
+		t.Errorf("read-only input typed into: %q", in.Value())
+	}
+	readOnly = false
+	fm.HandleKey(riffkey.Key{Rune: 'x'})
+	if in.Value() == "fixed" {
+		t.Error("input ignored keys once writable")
+	}
+}
+
+func TestDisabledJump(t *testing.T) {
+	pressed, disabled := 0, true
+	tmpl := Build(Jump(Text("Save"), func() { pressed++ }).Key("s").Disabled(&disabled))
+	handler := tmpl.pendingBindings[0].handler.(func())
+	handler()
+	if pressed != 0 {
+		t.Error("disabled button pressed")
+	}
+	disabled = false
+	handler()
+	if pressed != 1 {
+		t.Error("enabled button not pressed")
+	}
+}
@@ -8,7 +8,7 @@ import "strings"
 // Width is the total width including label and value.
 // Deprecated: Use the Leader component for pointer binding support.
 func LeaderStr(label, value string, width int) string {
-	dots := width - len(label) - len(value)
+	dots := width - StringWidth(label) - StringWidth(value)
 	if dots < 1 {
 		dots = 1
 	}
@@ -17,7 +17,7 @@ func LeaderStr(label, value string, width int) string {
 
 // LeaderDash creates a dash-leader string: "LABEL-----------VALUE"
 func LeaderDash(label, value string, width int) string {
-	dashes := width - len(label) - len(value)
+	dashes := width - StringWidth(label) - StringWidth(value)
 	if dashes < 1 {
 		dashes = 1
 	}
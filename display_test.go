@@ -180,3 +180,9 @@ func TestExampleLayout(t *testing.T) {
 
 	t.Logf("Example layout:\n%s", buf.StringTrimmed())
 }
+
+func TestLeaderStrDisplayWidth(t *testing.T) {
+	if got := LeaderStr("●", "日", 6); got != "●...日" {
+		t.Errorf("LeaderStr = %q", got)
+	}
+}
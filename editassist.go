@@ -0,0 +1,220 @@
+package glyph
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// IndentFunc computes the indentation for a new line when the line under the
+// cursor is split. above is the text before the cursor, below the text after.
+// Providers are plain functions so language-aware rules can be plugged in.
+type IndentFunc func(above, below string) string
+
+// KeepIndent repeats the indentation of the line above.
+func KeepIndent(above, below string) string {
+	return leadingSpace(above)
+}
+
+// BlockIndent keeps the previous indentation, adds one unit after a line
+// ending in one of openers and removes one when the new line starts with
+// one of closers. Suits brace languages:
+//
+//	buf.SetIndent(BlockIndent("\t", "{[(", "}])"))
+func BlockIndent(unit, openers, closers string) IndentFunc {
+	return func(above, below string) string {
+		indent := leadingSpace(above)
+		if t := strings.TrimRight(above, " \t"); t != "" && strings.ContainsRune(openers, lastRune(t)) {
+			indent += unit
+		}
+		if t := strings.TrimLeft(below, " \t"); t != "" && strings.ContainsRune(closers, firstRune(t)) {
+			indent = strings.TrimSuffix(indent, unit)
+		}
+		return indent
+	}
+}
+
+// DefaultPairs are the pairs used by TextArea.AutoPair when none are given.
+var DefaultPairs = []string{"()", "[]", "{}", `""`, "''", "``"}
+
+// SetIndent sets the indentation provider used by InsertNewline and Paste.
+// nil disables auto-indent.
+func (b *EditBuffer) SetIndent(fn IndentFunc) *EditBuffer {
+	b.indent = fn
+	return b
+}
+
+// SetPairs enables auto-closing for the given pairs, each a two-rune string
+// such as "()" or `""`. Calling with no pairs disables auto-closing.
+func (b *EditBuffer) SetPairs(pairs ...string) *EditBuffer {
+	b.pairs = b.pairs[:0]
+	for _, p := range pairs {
+		rs := []rune(p)
+		if len(rs) == 2 {
+			b.pairs = append(b.pairs, [2]rune{rs[0], rs[1]})
+		}
+	}
+	return b
+}
+
+// TypeRune types r at every cursor, applying auto-closing when pairs are set:
+// an opener inserts its closer after the cursor, typing a closer that is
+// already next to the cursor moves over it, and an opener typed over a
+// selection wraps it.
+func (b *EditBuffer) TypeRune(r rune) {
+	if len(b.pairs) == 0 {
+		b.Insert(string(r))
+		return
+	}
+	b.checkpoint(editInsert)
+	typed := string(r)
+	b.editEachAt(func(sel Selection) textEdit {
+		start, end := sel.Range()
+		line := b.lines[start.Line]
+		next := firstRune(line[end.Col:])
+
+		for _, p := range b.pairs {
+			open, close := string(p[0]), string(p[1])
+			if r == p[1] && sel.Empty() && next == r && (p[0] != p[1] || b.pairOpenBefore(line, start.Col, p)) {
+				over := b.nextPos(start)
+				return textEdit{start: over, end: over, caret: 0}
+			}
+			if r != p[0] {
+				continue
+			}
+			if !sel.Empty() {
+				return textEdit{start: start, end: end, text: open + b.textIn(start, end) + close, caret: -1}
+			}
+			if b.shouldPair(line, start.Col, p) {
+				return textEdit{start: start, end: end, text: open + close, caret: len(open)}
+			}
+		}
+		return textEdit{start: start, end: end, text: typed, caret: -1}
+	})
+}
+
+// shouldPair reports whether typing the opener of p at col should also
+// insert the closer. Brackets pair before whitespace or a closer; quotes
+// additionally never pair next to a word character.
+func (b *EditBuffer) shouldPair(line string, col int, p [2]rune) bool {
+	next := firstRune(line[col:])
+	if next != 0 && !unicode.IsSpace(next) && !b.isCloser(next) {
+		return false
+	}
+	if p[0] == p[1] && isWordRune(lastRune(line[:col])) {
+		return false
+	}
+	return true
+}
+
+// pairOpenBefore reports whether a same-rune pair (quotes) has an odd number
+// of occurrences before col, meaning the next one closes it.
+func (b *EditBuffer) pairOpenBefore(line string, col int, p [2]rune) bool {
+	return strings.Count(line[:col], string(p[0]))%2 == 1
+}
+
+func (b *EditBuffer) isCloser(r rune) bool {
+	for _, p := range b.pairs {
+		if p[1] == r {
+			return true
+		}
+	}
+	return false
+}
+
+// emptyPairAt reports whether the cursor sits between an opener and its
+// closer, as left by auto-closing.
+func (b *EditBuffer) emptyPairAt(p TextPos) bool {
+	line := b.lines[p.Line]
+	prev, next := lastRune(line[:p.Col]), firstRune(line[p.Col:])
+	for _, pr := range b.pairs {
+		if prev == pr[0] && next == pr[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// newlineEdit splits the line at sel, indenting the new line with the
+// indentation provider. Between an empty pair the closer moves to its own
+// line and the cursor lands on an indented line in between.
+func (b *EditBuffer) newlineEdit(sel Selection) textEdit {
+	start, end := sel.Range()
+	if b.indent == nil {
+		return textEdit{start: start, end: end, text: "\n", caret: -1}
+	}
+	above := b.lines[start.Line][:start.Col]
+	below := b.lines[end.Line][end.Col:]
+	indent := b.indent(above, below)
+	if start == end && b.emptyPairAt(start) {
+		inner := b.indent(above, "")
+		return textEdit{start: start, end: end, text: "\n" + inner + "\n" + indent, caret: 1 + len(inner)}
+	}
+	return textEdit{start: start, end: end, text: "\n" + indent, caret: -1}
+}
+
+// Paste inserts s at every cursor. When an indentation provider is set,
+// multi-line text is re-indented: its common indentation is replaced with
+// the indentation of the line being pasted into.
+func (b *EditBuffer) Paste(s string) {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	if b.indent == nil || !strings.ContainsRune(s, '\n') {
+		b.Insert(s)
+		return
+	}
+	b.checkpoint(editOther)
+	b.editEachAt(func(sel Selection) textEdit {
+		start, end := sel.Range()
+		base := leadingSpace(b.lines[start.Line][:start.Col])
+		return textEdit{start: start, end: end, text: reindent(s, base), caret: -1}
+	})
+}
+
+// reindent strips the common indentation of every line after the first and
+// prefixes base instead. The first line continues the cursor's line as is.
+func reindent(s, base string) string {
+	lines := strings.Split(s, "\n")
+	common := ""
+	first := true
+	for _, l := range lines[1:] {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		ind := leadingSpace(l)
+		if first {
+			common, first = ind, false
+			continue
+		}
+		for !strings.HasPrefix(ind, common) {
+			common = common[:len(common)-1]
+		}
+	}
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == "" {
+			lines[i] = ""
+			continue
+		}
+		lines[i] = base + strings.TrimPrefix(lines[i], common)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func leadingSpace(s string) string {
+	return s[:len(s)-len(strings.TrimLeft(s, " \t"))]
+}
+
+func firstRune(s string) rune {
+	r, _ := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return 0
+	}
+	return r
+}
+
+func lastRune(s string) rune {
+	r, _ := utf8.DecodeLastRuneInString(s)
+	if r == utf8.RuneError {
+		return 0
+	}
+	return r
+}
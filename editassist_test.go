@@ -0,0 +1,126 @@
+package glyph
+
+import (
+	"testing"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+func TestKeepIndent(t *testing.T) {
+	b := NewEditBuffer("\tfoo").SetIndent(KeepIndent)
+	b.SetCursor(TextPos{Col: 4})
+	b.InsertNewline()
+	if got := b.Text(); got != "\tfoo\n\t" {
+		t.Errorf("unexpected text %q", got)
+	}
+	if b.Cursor() != (TextPos{Line: 1, Col: 1}) {
+		t.Errorf("expected cursor after indent, got %+v", b.Cursor())
+	}
+}
+
+func TestBlockIndent(t *testing.T) {
+	indent := BlockIndent("  ", "{", "}")
+	tests := []struct {
+		above, below, want string
+	}{
+		{"func f() {", "", "  "},
+		{"  x := 1", "", "  "},
+		{"  if ok {", "}", "  "},
+		{"  done", "}", ""},
+	}
+	for _, tt := range tests {
+		if got := indent(tt.above, tt.below); got != tt.want {
+			t.Errorf("indent(%q, %q) = %q, want %q", tt.above, tt.below, got, tt.want)
+		}
+	}
+}
+
+func TestAutoPairInsertAndOvertype(t *testing.T) {
+	b := NewEditBuffer("").SetPairs(DefaultPairs...)
+	b.TypeRune('(')
+	if got := b.Text(); got != "()" {
+		t.Fatalf("expected closer inserted, got %q", got)
+	}
+	b.TypeRune('x')
+	b.TypeRune(')')
+	if got := b.Text(); got != "(x)" {
+		t.Errorf("expected overtype of closer, got %q", got)
+	}
+	if b.Cursor().Col != 3 {
+		t.Errorf("expected cursor after closer, got %+v", b.Cursor())
+	}
+}
+
+func TestAutoPairQuotes(t *testing.T) {
+	b := NewEditBuffer("").SetPairs(DefaultPairs...)
+	for _, r := range `say "hi"` {
+		b.TypeRune(r)
+	}
+	if got := b.Text(); got != `say "hi"` {
+		t.Errorf("expected quote to be overtyped, got %q", got)
+	}
+
+	// no pairing straight after a word character
+	b = NewEditBuffer("don").SetPairs(DefaultPairs...)
+	b.SetCursor(TextPos{Col: 3})
+	b.TypeRune('\'')
+	if got := b.Text(); got != "don'" {
+		t.Errorf("expected apostrophe alone, got %q", got)
+	}
+}
+
+func TestAutoPairWrapsSelection(t *testing.T) {
+	b := NewEditBuffer("word").SetPairs(DefaultPairs...)
+	b.SetSelections([]Selection{{Anchor: TextPos{}, Head: TextPos{Col: 4}}})
+	b.TypeRune('[')
+	if got := b.Text(); got != "[word]" {
+		t.Errorf("expected wrapped selection, got %q", got)
+	}
+}
+
+func TestAutoPairBackspaceDeletesBoth(t *testing.T) {
+	b := NewEditBuffer("").SetPairs(DefaultPairs...)
+	b.TypeRune('{')
+	b.Backspace()
+	if got := b.Text(); got != "" {
+		t.Errorf("expected empty pair removed, got %q", got)
+	}
+}
+
+func TestNewlineBetweenPair(t *testing.T) {
+	b := NewEditBuffer("if x ").SetIndent(BlockIndent("\t", "{", "}")).SetPairs("{}")
+	b.SetCursor(TextPos{Col: 5})
+	b.TypeRune('{')
+	b.InsertNewline()
+	if got := b.Text(); got != "if x {\n\t\n}" {
+		t.Errorf("unexpected text %q", got)
+	}
+	if b.Cursor() != (TextPos{Line: 1, Col: 1}) {
+		t.Errorf("expected cursor on indented middle line, got %+v", b.Cursor())
+	}
+}
+
+func TestPasteReindents(t *testing.T) {
+	b := NewEditBuffer("\t").SetIndent(KeepIndent)
+	b.SetCursor(TextPos{Col: 1})
+	b.Paste("if x {\n        y()\n    }")
+	if got := b.Text(); got != "\tif x {\n\t    y()\n\t}" {
+		t.Errorf("unexpected text %q", got)
+	}
+
+	// a single undo reverts the whole paste
+	b.Undo()
+	if got := b.Text(); got != "\t" {
+		t.Errorf("expected paste undone, got %q", got)
+	}
+}
+
+func TestTextAreaAutoPairKeys(t *testing.T) {
+	b := NewEditBuffer("")
+	ta := TextArea(b).AutoPair().AutoIndent(KeepIndent)
+	ta.HandleKey(riffkey.Key{Rune: '('})
+	ta.HandleKey(riffkey.Key{Rune: ')'})
+	if got := b.Text(); got != "()" {
+		t.Errorf("unexpected text %q", got)
+	}
+}
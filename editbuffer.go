@@ -0,0 +1,804 @@
+package glyph
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TextPos is a position in an EditBuffer.
+// Line is 0-indexed, Col is a byte offset into the line.
+type TextPos struct {
+	Line, Col int
+}
+
+// Less reports whether p comes before q in document order.
+func (p TextPos) Less(q TextPos) bool {
+	if p.Line != q.Line {
+		return p.Line < q.Line
+	}
+	return p.Col < q.Col
+}
+
+// Selection is a cursor with an anchor. The head is where the cursor sits,
+// the anchor is where the selection started. When both are equal the
+// selection is empty and behaves as a plain cursor.
+type Selection struct {
+	Anchor TextPos
+	Head   TextPos
+}
+
+// Empty reports whether the selection covers no text.
+func (s Selection) Empty() bool {
+	return s.Anchor == s.Head
+}
+
+// Range returns the selection bounds in document order.
+func (s Selection) Range() (start, end TextPos) {
+	if s.Head.Less(s.Anchor) {
+		return s.Head, s.Anchor
+	}
+	return s.Anchor, s.Head
+}
+
+// editKind classifies the last edit for undo coalescing.
+type editKind uint8
+
+const (
+	editNone editKind = iota
+	editInsert
+	editDelete
+	editOther
+)
+
+// editSnapshot is one entry in the undo history.
+type editSnapshot struct {
+	lines   []string
+	sels    []Selection
+	primary int
+}
+
+// EditBuffer is a line-based text model with multiple cursors and undo.
+// Every editing operation applies to all cursors at once; the primary
+// cursor (the one kept on screen) is the most recently added.
+//
+//	buf := NewEditBuffer("hello\nworld")
+//	buf.AddCursorBelow()
+//	buf.Insert("> ") // inserts on both lines
+type EditBuffer struct {
+	lines   []string
+	sels    []Selection // sorted in document order, never overlapping
+	primary int         // index into sels
+
+	undo     []editSnapshot
+	redo     []editSnapshot
+	maxUndo  int
+	lastEdit editKind
+
+	groupDepth int
+	groupSaved bool
+
+	indent IndentFunc // auto-indent provider, nil keeps column 0
+	pairs  [][2]rune  // auto-closing pairs
+
+	version  uint64 // bumped on any text or cursor change
+	onChange func()
+}
+
+// NewEditBuffer creates a buffer holding text with a single cursor at the start.
+func NewEditBuffer(text string) *EditBuffer {
+	b := &EditBuffer{maxUndo: 1000}
+	b.lines = splitLines(text)
+	b.sels = []Selection{{}}
+	return b
+}
+
+func splitLines(text string) []string {
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
+
+// OnChange sets a callback fired after every text modification.
+func (b *EditBuffer) OnChange(fn func()) *EditBuffer {
+	b.onChange = fn
+	return b
+}
+
+// MaxUndo sets the maximum number of undo steps kept. Default is 1000.
+func (b *EditBuffer) MaxUndo(n int) *EditBuffer {
+	b.maxUndo = n
+	return b
+}
+
+// Text returns the full buffer contents joined with newlines.
+func (b *EditBuffer) Text() string {
+	return strings.Join(b.lines, "\n")
+}
+
+// SetText replaces the buffer contents, resets cursors and clears history.
+func (b *EditBuffer) SetText(text string) {
+	b.lines = splitLines(text)
+	b.sels = []Selection{{}}
+	b.primary = 0
+	b.undo = b.undo[:0]
+	b.redo = b.redo[:0]
+	b.lastEdit = editNone
+	b.changed()
+}
+
+// LineCount returns the number of lines.
+func (b *EditBuffer) LineCount() int {
+	return len(b.lines)
+}
+
+// Line returns line i, or "" if out of range.
+func (b *EditBuffer) Line(i int) string {
+	if i < 0 || i >= len(b.lines) {
+		return ""
+	}
+	return b.lines[i]
+}
+
+// Lines returns the underlying lines. The slice must not be modified.
+func (b *EditBuffer) Lines() []string {
+	return b.lines
+}
+
+// Version returns a counter that increases on every text or cursor change.
+// Renderers use it to skip redraws when nothing moved.
+func (b *EditBuffer) Version() uint64 {
+	return b.version
+}
+
+// ============================================================================
+// Cursors
+// ============================================================================
+
+// Cursor returns the primary cursor position.
+func (b *EditBuffer) Cursor() TextPos {
+	return b.sels[b.primary].Head
+}
+
+// SetCursor collapses to a single cursor at p.
+func (b *EditBuffer) SetCursor(p TextPos) {
+	p = b.clamp(p)
+	b.sels = append(b.sels[:0], Selection{Anchor: p, Head: p})
+	b.primary = 0
+	b.moved()
+}
+
+// Selections returns a copy of all selections in document order.
+func (b *EditBuffer) Selections() []Selection {
+	out := make([]Selection, len(b.sels))
+	copy(out, b.sels)
+	return out
+}
+
+// Primary returns the primary selection.
+func (b *EditBuffer) Primary() Selection {
+	return b.sels[b.primary]
+}
+
+// PrimaryIndex returns the index of the primary selection within Selections.
+func (b *EditBuffer) PrimaryIndex() int {
+	return b.primary
+}
+
+// SetSelections replaces all selections. The last one becomes primary.
+// Overlapping selections are merged.
+func (b *EditBuffer) SetSelections(sels []Selection) {
+	if len(sels) == 0 {
+		return
+	}
+	b.sels = b.sels[:0]
+	for _, s := range sels {
+		b.sels = append(b.sels, Selection{Anchor: b.clamp(s.Anchor), Head: b.clamp(s.Head)})
+	}
+	b.normalize(b.sels[len(b.sels)-1])
+	b.moved()
+}
+
+// CursorCount returns the number of active cursors.
+func (b *EditBuffer) CursorCount() int {
+	return len(b.sels)
+}
+
+// AddCursor adds a cursor at p and makes it primary.
+func (b *EditBuffer) AddCursor(p TextPos) {
+	p = b.clamp(p)
+	s := Selection{Anchor: p, Head: p}
+	b.sels = append(b.sels, s)
+	b.normalize(s)
+	b.moved()
+}
+
+// AddCursorAbove adds a cursor on the line above the topmost cursor.
+func (b *EditBuffer) AddCursorAbove() bool {
+	top := b.sels[0].Head
+	if top.Line == 0 {
+		return false
+	}
+	b.AddCursor(b.verticalPos(top, top.Line-1))
+	return true
+}
+
+// AddCursorBelow adds a cursor on the line below the bottommost cursor.
+func (b *EditBuffer) AddCursorBelow() bool {
+	bottom := b.sels[len(b.sels)-1].Head
+	if bottom.Line >= len(b.lines)-1 {
+		return false
+	}
+	b.AddCursor(b.verticalPos(bottom, bottom.Line+1))
+	return true
+}
+
+// AddCursorAtNextMatch selects the next occurrence of the primary selection's
+// text. If the primary selection is empty, the word under the cursor is
+// selected first instead. Search wraps around the end of the buffer.
+// Returns false if no further occurrence exists.
+func (b *EditBuffer) AddCursorAtNextMatch() bool {
+	p := b.sels[b.primary]
+	if p.Empty() {
+		start, end, ok := b.wordAt(p.Head)
+		if !ok {
+			return false
+		}
+		b.sels[b.primary] = Selection{Anchor: start, Head: end}
+		b.moved()
+		return true
+	}
+
+	needle := b.textIn(p.Range())
+	_, from := p.Range()
+	for _, hit := range b.findAll(needle, from) {
+		if b.selectionAt(hit) >= 0 {
+			continue
+		}
+		end := b.advance(hit, needle)
+		s := Selection{Anchor: hit, Head: end}
+		b.sels = append(b.sels, s)
+		b.normalize(s)
+		b.moved()
+		return true
+	}
+	return false
+}
+
+// ClearSecondaryCursors collapses to the primary cursor only.
+// Returns false if there was already a single cursor.
+func (b *EditBuffer) ClearSecondaryCursors() bool {
+	if len(b.sels) == 1 {
+		return false
+	}
+	p := b.sels[b.primary]
+	b.sels = append(b.sels[:0], p)
+	b.primary = 0
+	b.moved()
+	return true
+}
+
+// ============================================================================
+// Movement
+// ============================================================================
+
+// MoveLeft moves every cursor one character left. With extend, the
+// selections grow instead of collapsing.
+func (b *EditBuffer) MoveLeft(extend bool) {
+	b.moveEach(extend, func(s Selection) TextPos {
+		if !extend && !s.Empty() {
+			start, _ := s.Range()
+			return start
+		}
+		return b.prevPos(s.Head)
+	})
+}
+
+// MoveRight moves every cursor one character right.
+func (b *EditBuffer) MoveRight(extend bool) {
+	b.moveEach(extend, func(s Selection) TextPos {
+		if !extend && !s.Empty() {
+			_, end := s.Range()
+			return end
+		}
+		return b.nextPos(s.Head)
+	})
+}
+
+// MoveUp moves every cursor one line up, keeping the column where possible.
+func (b *EditBuffer) MoveUp(extend bool) {
+	b.moveEach(extend, func(s Selection) TextPos {
+		if s.Head.Line == 0 {
+			return TextPos{}
+		}
+		return b.verticalPos(s.Head, s.Head.Line-1)
+	})
+}
+
+// MoveDown moves every cursor one line down, keeping the column where possible.
+func (b *EditBuffer) MoveDown(extend bool) {
+	b.moveEach(extend, func(s Selection) TextPos {
+		last := len(b.lines) - 1
+		if s.Head.Line >= last {
+			return TextPos{Line: last, Col: len(b.lines[last])}
+		}
+		return b.verticalPos(s.Head, s.Head.Line+1)
+	})
+}
+
+// MoveLineStart moves every cursor to the start of its line.
+func (b *EditBuffer) MoveLineStart(extend bool) {
+	b.moveEach(extend, func(s Selection) TextPos {
+		return TextPos{Line: s.Head.Line}
+	})
+}
+
+// MoveLineEnd moves every cursor to the end of its line.
+func (b *EditBuffer) MoveLineEnd(extend bool) {
+	b.moveEach(extend, func(s Selection) TextPos {
+		return TextPos{Line: s.Head.Line, Col: len(b.lines[s.Head.Line])}
+	})
+}
+
+func (b *EditBuffer) moveEach(extend bool, fn func(Selection) TextPos) {
+	for i, s := range b.sels {
+		head := fn(s)
+		if extend {
+			b.sels[i].Head = head
+		} else {
+			b.sels[i] = Selection{Anchor: head, Head: head}
+		}
+	}
+	b.normalize(b.sels[b.primary])
+	b.lastEdit = editNone
+	b.moved()
+}
+
+// ============================================================================
+// Editing
+// ============================================================================
+
+// Insert types s at every cursor, replacing any selected text.
+// s may contain newlines.
+func (b *EditBuffer) Insert(s string) {
+	kind := editInsert
+	if strings.ContainsRune(s, '\n') {
+		kind = editOther
+	}
+	b.checkpoint(kind)
+	b.editEach(func(sel Selection) (TextPos, TextPos, string) {
+		start, end := sel.Range()
+		return start, end, s
+	})
+}
+
+// InsertNewline splits the line at every cursor, indenting the new line
+// when an indentation provider is set.
+func (b *EditBuffer) InsertNewline() {
+	b.checkpoint(editOther)
+	b.editEachAt(b.newlineEdit)
+}
+
+// Backspace deletes the selection, or the character before each cursor.
+// Inside an empty auto-closed pair both halves are deleted.
+func (b *EditBuffer) Backspace() {
+	b.checkpoint(editDelete)
+	b.editEach(func(sel Selection) (TextPos, TextPos, string) {
+		if !sel.Empty() {
+			start, end := sel.Range()
+			return start, end, ""
+		}
+		if len(b.pairs) > 0 && b.emptyPairAt(sel.Head) {
+			return b.prevPos(sel.Head), b.nextPos(sel.Head), ""
+		}
+		return b.prevPos(sel.Head), sel.Head, ""
+	})
+}
+
+// Delete deletes the selection, or the character after each cursor.
+func (b *EditBuffer) Delete() {
+	b.checkpoint(editDelete)
+	b.editEach(func(sel Selection) (TextPos, TextPos, string) {
+		if !sel.Empty() {
+			start, end := sel.Range()
+			return start, end, ""
+		}
+		return sel.Head, b.nextPos(sel.Head), ""
+	})
+}
+
+// SelectedText returns the text of each selection, in document order.
+func (b *EditBuffer) SelectedText() []string {
+	out := make([]string, len(b.sels))
+	for i, s := range b.sels {
+		out[i] = b.textIn(s.Range())
+	}
+	return out
+}
+
+// textEdit replaces the text in [start, end) with text. caret is the byte
+// offset into text where the cursor lands; -1 places it after the text.
+type textEdit struct {
+	start, end TextPos
+	text       string
+	caret      int
+}
+
+// editEach applies a replacement at every selection. fn returns the range
+// to replace and the replacement text. Selections are processed in document
+// order and later ones are shifted by the edits made before them.
+func (b *EditBuffer) editEach(fn func(Selection) (start, end TextPos, text string)) {
+	b.editEachAt(func(s Selection) textEdit {
+		start, end, text := fn(s)
+		return textEdit{start: start, end: end, text: text, caret: -1}
+	})
+}
+
+// editEachAt is editEach with control over where each cursor lands.
+// An edit with an empty range and no text only moves the cursor.
+func (b *EditBuffer) editEachAt(fn func(Selection) textEdit) {
+	changed := false
+	for i := range b.sels {
+		e := fn(b.sels[i])
+		if e.start == e.end && e.text == "" {
+			if e.caret >= 0 {
+				b.sels[i] = Selection{Anchor: e.start, Head: e.start}
+			}
+			continue
+		}
+		newEnd := b.replace(e.start, e.end, e.text)
+		head := newEnd
+		if e.caret >= 0 {
+			head = b.advance(e.start, e.text[:e.caret])
+		}
+		b.sels[i] = Selection{Anchor: head, Head: head}
+		for j := i + 1; j < len(b.sels); j++ {
+			b.sels[j].Anchor = shiftPos(b.sels[j].Anchor, e.end, newEnd)
+			b.sels[j].Head = shiftPos(b.sels[j].Head, e.end, newEnd)
+		}
+		changed = true
+	}
+	b.normalize(b.sels[b.primary])
+	if changed {
+		b.changed()
+	} else {
+		b.moved()
+	}
+}
+
+// replace swaps the text in [start, end) for text and returns the end of
+// the inserted text.
+func (b *EditBuffer) replace(start, end TextPos, text string) TextPos {
+	head := b.lines[start.Line][:start.Col]
+	tail := b.lines[end.Line][end.Col:]
+	ins := strings.Split(text, "\n")
+
+	newLines := make([]string, len(ins))
+	copy(newLines, ins)
+	newLines[0] = head + newLines[0]
+	last := len(newLines) - 1
+	newEnd := TextPos{Line: start.Line + last, Col: len(newLines[last])}
+	newLines[last] += tail
+
+	out := make([]string, 0, len(b.lines)-(end.Line-start.Line)+last)
+	out = append(out, b.lines[:start.Line]...)
+	out = append(out, newLines...)
+	out = append(out, b.lines[end.Line+1:]...)
+	b.lines = out
+	return newEnd
+}
+
+// shiftPos moves p to account for text ending at oldEnd now ending at newEnd.
+// Only positions at or after oldEnd are affected.
+func shiftPos(p, oldEnd, newEnd TextPos) TextPos {
+	if p.Less(oldEnd) {
+		return p
+	}
+	if p.Line == oldEnd.Line {
+		return TextPos{Line: newEnd.Line, Col: newEnd.Col + p.Col - oldEnd.Col}
+	}
+	p.Line += newEnd.Line - oldEnd.Line
+	return p
+}
+
+// ============================================================================
+// Undo
+// ============================================================================
+
+// BeginUndoGroup starts grouping edits so a single Undo reverts all of them.
+// Groups nest; only the outermost EndUndoGroup closes the group.
+func (b *EditBuffer) BeginUndoGroup() {
+	if b.groupDepth == 0 {
+		b.groupSaved = false
+	}
+	b.groupDepth++
+}
+
+// EndUndoGroup closes the current undo group.
+func (b *EditBuffer) EndUndoGroup() {
+	if b.groupDepth > 0 {
+		b.groupDepth--
+	}
+	if b.groupDepth == 0 {
+		b.lastEdit = editNone
+	}
+}
+
+// Undo reverts the last edit. Returns false if there is nothing to undo.
+func (b *EditBuffer) Undo() bool {
+	if len(b.undo) == 0 {
+		return false
+	}
+	b.redo = append(b.redo, b.snapshot())
+	b.restore(b.undo[len(b.undo)-1])
+	b.undo = b.undo[:len(b.undo)-1]
+	return true
+}
+
+// Redo reapplies the last undone edit. Returns false if there is nothing to redo.
+func (b *EditBuffer) Redo() bool {
+	if len(b.redo) == 0 {
+		return false
+	}
+	b.undo = append(b.undo, b.snapshot())
+	b.restore(b.redo[len(b.redo)-1])
+	b.redo = b.redo[:len(b.redo)-1]
+	return true
+}
+
+// checkpoint records the current state before an edit of the given kind.
+// Consecutive inserts (or deletes) coalesce into one undo step, as does
+// everything inside an undo group.
+func (b *EditBuffer) checkpoint(kind editKind) {
+	if b.groupDepth > 0 {
+		if b.groupSaved {
+			return
+		}
+		b.groupSaved = true
+	} else if kind != editOther && kind == b.lastEdit {
+		return
+	}
+	b.lastEdit = kind
+	b.undo = append(b.undo, b.snapshot())
+	if b.maxUndo > 0 && len(b.undo) > b.maxUndo {
+		b.undo = b.undo[len(b.undo)-b.maxUndo:]
+	}
+	b.redo = b.redo[:0]
+}
+
+func (b *EditBuffer) snapshot() editSnapshot {
+	lines := make([]string, len(b.lines))
+	copy(lines, b.lines)
+	sels := make([]Selection, len(b.sels))
+	copy(sels, b.sels)
+	return editSnapshot{lines: lines, sels: sels, primary: b.primary}
+}
+
+func (b *EditBuffer) restore(s editSnapshot) {
+	b.lines = s.lines
+	b.sels = s.sels
+	b.primary = s.primary
+	b.lastEdit = editNone
+	b.changed()
+}
+
+// ============================================================================
+// Internals
+// ============================================================================
+
+func (b *EditBuffer) changed() {
+	b.version++
+	if b.onChange != nil {
+		b.onChange()
+	}
+}
+
+func (b *EditBuffer) moved() {
+	b.version++
+}
+
+// normalize sorts selections, merges overlaps and re-locates the primary.
+func (b *EditBuffer) normalize(primary Selection) {
+	sort.SliceStable(b.sels, func(i, j int) bool {
+		si, _ := b.sels[i].Range()
+		sj, _ := b.sels[j].Range()
+		return si.Less(sj)
+	})
+	out := b.sels[:1]
+	for _, s := range b.sels[1:] {
+		last := &out[len(out)-1]
+		ls, le := last.Range()
+		ss, se := s.Range()
+		if ss.Less(le) || ss == le && (s.Empty() || last.Empty()) {
+			// overlapping or touching cursors: merge into one selection
+			if le.Less(se) {
+				le = se
+			}
+			if last.Head.Less(last.Anchor) {
+				*last = Selection{Anchor: le, Head: ls}
+			} else {
+				*last = Selection{Anchor: ls, Head: le}
+			}
+			continue
+		}
+		out = append(out, s)
+	}
+	b.sels = out
+
+	b.primary = 0
+	for i, s := range b.sels {
+		start, end := s.Range()
+		if !primary.Head.Less(start) && !end.Less(primary.Head) {
+			b.primary = i
+			break
+		}
+	}
+}
+
+func (b *EditBuffer) clamp(p TextPos) TextPos {
+	if p.Line < 0 {
+		return TextPos{}
+	}
+	if p.Line >= len(b.lines) {
+		last := len(b.lines) - 1
+		return TextPos{Line: last, Col: len(b.lines[last])}
+	}
+	line := b.lines[p.Line]
+	if p.Col < 0 {
+		p.Col = 0
+	}
+	if p.Col > len(line) {
+		p.Col = len(line)
+	}
+	for p.Col > 0 && p.Col < len(line) && !utf8.RuneStart(line[p.Col]) {
+		p.Col--
+	}
+	return p
+}
+
+func (b *EditBuffer) prevPos(p TextPos) TextPos {
+	if p.Col > 0 {
+		_, size := utf8.DecodeLastRuneInString(b.lines[p.Line][:p.Col])
+		return TextPos{Line: p.Line, Col: p.Col - size}
+	}
+	if p.Line > 0 {
+		return TextPos{Line: p.Line - 1, Col: len(b.lines[p.Line-1])}
+	}
+	return p
+}
+
+func (b *EditBuffer) nextPos(p TextPos) TextPos {
+	line := b.lines[p.Line]
+	if p.Col < len(line) {
+		_, size := utf8.DecodeRuneInString(line[p.Col:])
+		return TextPos{Line: p.Line, Col: p.Col + size}
+	}
+	if p.Line < len(b.lines)-1 {
+		return TextPos{Line: p.Line + 1}
+	}
+	return p
+}
+
+// verticalPos maps p onto another line, keeping the same rune column.
+func (b *EditBuffer) verticalPos(p TextPos, line int) TextPos {
+	col := utf8.RuneCountInString(b.lines[p.Line][:p.Col])
+	return TextPos{Line: line, Col: runeOffset(b.lines[line], col)}
+}
+
+// runeOffset returns the byte offset of rune column col in s, clamped to len(s).
+func runeOffset(s string, col int) int {
+	for i := range s {
+		if col == 0 {
+			return i
+		}
+		col--
+	}
+	return len(s)
+}
+
+func (b *EditBuffer) textIn(start, end TextPos) string {
+	if start.Line == end.Line {
+		return b.lines[start.Line][start.Col:end.Col]
+	}
+	var sb strings.Builder
+	sb.WriteString(b.lines[start.Line][start.Col:])
+	for i := start.Line + 1; i < end.Line; i++ {
+		sb.WriteByte('\n')
+		sb.WriteString(b.lines[i])
+	}
+	sb.WriteByte('\n')
+	sb.WriteString(b.lines[end.Line][:end.Col])
+	return sb.String()
+}
+
+// advance returns the position reached by walking over text from p.
+func (b *EditBuffer) advance(p TextPos, text string) TextPos {
+	n := strings.Count(text, "\n")
+	if n == 0 {
+		return TextPos{Line: p.Line, Col: p.Col + len(text)}
+	}
+	return TextPos{Line: p.Line + n, Col: len(text) - strings.LastIndexByte(text, '\n') - 1}
+}
+
+// findAll returns every occurrence of needle, starting at from and wrapping
+// around to the buffer start.
+func (b *EditBuffer) findAll(needle string, from TextPos) []TextPos {
+	if needle == "" {
+		return nil
+	}
+	text := b.Text()
+	offset := b.offsetOf(from)
+	var after, before []TextPos
+	for i := 0; i <= len(text)-len(needle); {
+		idx := strings.Index(text[i:], needle)
+		if idx < 0 {
+			break
+		}
+		pos := i + idx
+		if pos >= offset {
+			after = append(after, b.posOf(pos))
+		} else {
+			before = append(before, b.posOf(pos))
+		}
+		i = pos + len(needle)
+	}
+	return append(after, before...)
+}
+
+// offsetOf converts a position to a byte offset into Text().
+func (b *EditBuffer) offsetOf(p TextPos) int {
+	off := 0
+	for i := 0; i < p.Line; i++ {
+		off += len(b.lines[i]) + 1
+	}
+	return off + p.Col
+}
+
+// posOf converts a byte offset into Text() to a position.
+func (b *EditBuffer) posOf(off int) TextPos {
+	for i, line := range b.lines {
+		if off <= len(line) {
+			return TextPos{Line: i, Col: off}
+		}
+		off -= len(line) + 1
+	}
+	last := len(b.lines) - 1
+	return TextPos{Line: last, Col: len(b.lines[last])}
+}
+
+// selectionAt returns the index of the selection starting at p, or -1.
+func (b *EditBuffer) selectionAt(p TextPos) int {
+	for i, s := range b.sels {
+		if start, _ := s.Range(); start == p {
+			return i
+		}
+	}
+	return -1
+}
+
+// wordAt returns the bounds of the word touching p.
+func (b *EditBuffer) wordAt(p TextPos) (start, end TextPos, ok bool) {
+	line := b.lines[p.Line]
+	s, e := p.Col, p.Col
+	for s > 0 {
+		r, size := utf8.DecodeLastRuneInString(line[:s])
+		if !isWordRune(r) {
+			break
+		}
+		s -= size
+	}
+	for e < len(line) {
+		r, size := utf8.DecodeRuneInString(line[e:])
+		if !isWordRune(r) {
+			break
+		}
+		e += size
+	}
+	if s == e {
+		return p, p, false
+	}
+	return TextPos{Line: p.Line, Col: s}, TextPos{Line: p.Line, Col: e}, true
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,144 @@
+package glyph
+
+import "strings"
+
+// LineRange is an inclusive range of line indices.
+type LineRange struct {
+	From, To int
+}
+
+// SelectedLines returns the line ranges touched by the selections, merged
+// and in document order. A selection ending at column 0 does not include
+// that final line.
+func (b *EditBuffer) SelectedLines() []LineRange {
+	var out []LineRange
+	for _, s := range b.sels {
+		start, end := s.Range()
+		to := end.Line
+		if end.Col == 0 && end.Line > start.Line {
+			to--
+		}
+		if n := len(out); n > 0 && start.Line <= out[n-1].To+1 {
+			out[n-1].To = max(out[n-1].To, to)
+			continue
+		}
+		out = append(out, LineRange{From: start.Line, To: to})
+	}
+	return out
+}
+
+// IndentLines prefixes every non-blank line in [from, to] with unit.
+// The change is a single undo step.
+func (b *EditBuffer) IndentLines(from, to int, unit string) {
+	b.lineOp(from, to, func(i int) {
+		if strings.TrimSpace(b.lines[i]) != "" {
+			b.spliceLine(i, 0, 0, unit)
+		}
+	})
+}
+
+// DedentLines removes one unit of indentation from every line in [from, to].
+// Lines indented with something other than unit lose up to the same number
+// of leading spaces, or a single tab.
+func (b *EditBuffer) DedentLines(from, to int, unit string) {
+	b.lineOp(from, to, func(i int) {
+		line := b.lines[i]
+		switch {
+		case strings.HasPrefix(line, unit):
+			b.spliceLine(i, 0, len(unit), "")
+		case strings.HasPrefix(line, "\t"):
+			b.spliceLine(i, 0, 1, "")
+		default:
+			n := 0
+			for n < len(line) && n < max(len(unit), 1) && line[n] == ' ' {
+				n++
+			}
+			if n > 0 {
+				b.spliceLine(i, 0, n, "")
+			}
+		}
+	})
+}
+
+// ToggleComment comments out the lines in [from, to] with the line comment
+// prefix (for example "// " or "# "), or uncomments them if every non-blank
+// line is already commented. The prefix is inserted at the shallowest
+// indentation so the block stays aligned. Blank lines are left alone.
+func (b *EditBuffer) ToggleComment(from, to int, prefix string) {
+	from, to = max(from, 0), min(to, len(b.lines)-1)
+	marker := strings.TrimRight(prefix, " ")
+	if marker == "" {
+		return
+	}
+
+	commented := true
+	col := -1
+	for i := from; i <= to; i++ {
+		body := strings.TrimLeft(b.lines[i], " \t")
+		if body == "" {
+			continue
+		}
+		if !strings.HasPrefix(body, marker) {
+			commented = false
+		}
+		if ind := len(b.lines[i]) - len(body); col < 0 || ind < col {
+			col = ind
+		}
+	}
+	if col < 0 {
+		return
+	}
+
+	b.lineOp(from, to, func(i int) {
+		line := b.lines[i]
+		body := strings.TrimLeft(line, " \t")
+		if body == "" {
+			return
+		}
+		if !commented {
+			b.spliceLine(i, col, 0, prefix)
+			return
+		}
+		at := len(line) - len(body)
+		n := len(marker)
+		if strings.HasPrefix(body, prefix) {
+			n = len(prefix)
+		}
+		b.spliceLine(i, at, n, "")
+	})
+}
+
+// lineOp applies fn to each line in [from, to] as one undo step.
+func (b *EditBuffer) lineOp(from, to int, fn func(i int)) {
+	from, to = max(from, 0), min(to, len(b.lines)-1)
+	if from > to {
+		return
+	}
+	b.checkpoint(editOther)
+	for i := from; i <= to; i++ {
+		fn(i)
+	}
+	b.normalize(b.sels[b.primary])
+	b.changed()
+}
+
+// spliceLine replaces n bytes at col on line i with text, moving any cursor
+// on that line so it stays on the same character.
+func (b *EditBuffer) spliceLine(i, col, n int, text string) {
+	line := b.lines[i]
+	b.lines[i] = line[:col] + text + line[col+n:]
+	shift := func(p TextPos) TextPos {
+		switch {
+		case p.Line != i || p.Col < col:
+		case p.Col < col+n:
+			p.Col = col
+		default:
+			p.Col += len(text) - n
+		}
+		return p
+	}
+	for j := range b.sels {
+		b.sels[j].Anchor = shift(b.sels[j].Anchor)
+		b.sels[j].Head = shift(b.sels[j].Head)
+	}
+}
@@ -0,0 +1,80 @@
+package glyph
+
+import "testing"
+
+func TestIndentDedentLines(t *testing.T) {
+	b := NewEditBuffer("a\n\nb")
+	b.SetCursor(TextPos{Line: 2, Col: 1})
+	b.IndentLines(0, 2, "  ")
+	if got := b.Text(); got != "  a\n\n  b" {
+		t.Errorf("blank lines should stay empty, got %q", got)
+	}
+	if b.Cursor() != (TextPos{Line: 2, Col: 3}) {
+		t.Errorf("cursor should follow its character, got %+v", b.Cursor())
+	}
+
+	b.DedentLines(0, 2, "  ")
+	if got := b.Text(); got != "a\n\nb" {
+		t.Errorf("unexpected text after dedent %q", got)
+	}
+
+	b = NewEditBuffer("\tx\n y")
+	b.DedentLines(0, 1, "    ")
+	if got := b.Text(); got != "x\ny" {
+		t.Errorf("expected mixed indentation removed, got %q", got)
+	}
+}
+
+func TestToggleComment(t *testing.T) {
+	b := NewEditBuffer("\tif x {\n\t\ty()\n\n\t}")
+	b.ToggleComment(0, 3, "// ")
+	want := "\t// if x {\n\t// \ty()\n\n\t// }"
+	if got := b.Text(); got != want {
+		t.Fatalf("comment: got %q, want %q", got, want)
+	}
+
+	b.ToggleComment(0, 3, "// ")
+	if got := b.Text(); got != "\tif x {\n\t\ty()\n\n\t}" {
+		t.Errorf("uncomment: got %q", got)
+	}
+
+	// a partly commented block gets commented, not toggled per line
+	b = NewEditBuffer("# a\nb")
+	b.ToggleComment(0, 1, "# ")
+	if got := b.Text(); got != "# # a\n# b" {
+		t.Errorf("unexpected text %q", got)
+	}
+}
+
+func TestLineOpsUndoGroup(t *testing.T) {
+	b := NewEditBuffer("a\nb")
+	b.IndentLines(0, 1, "\t")
+	b.IndentLines(0, 1, "\t")
+	b.Undo()
+	if got := b.Text(); got != "\ta\n\tb" {
+		t.Errorf("each op should be its own undo step, got %q", got)
+	}
+
+	b.BeginUndoGroup()
+	b.ToggleComment(0, 0, "// ")
+	b.DedentLines(1, 1, "\t")
+	b.EndUndoGroup()
+	b.Undo()
+	if got := b.Text(); got != "\ta\n\tb" {
+		t.Errorf("group should undo in one step, got %q", got)
+	}
+}
+
+func TestSelectedLines(t *testing.T) {
+	b := NewEditBuffer("a\nb\nc\nd\ne")
+	b.SetSelections([]Selection{
+		{Anchor: TextPos{Line: 0}, Head: TextPos{Line: 2}},                 // ends at col 0: lines 0-1
+		{Anchor: TextPos{Line: 2, Col: 1}, Head: TextPos{Line: 2, Col: 1}}, // adjacent: merged
+		{Anchor: TextPos{Line: 4}, Head: TextPos{Line: 4}},
+	})
+	got := b.SelectedLines()
+	want := []LineRange{{0, 2}, {4, 4}}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
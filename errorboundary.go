@@ -0,0 +1,135 @@
+package glyph
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// ErrorBoundaryC contains panics raised while measuring or rendering its
+// child. The child is replaced by a small error box and the panic is
+// reported, so one faulty Widget doesn't bring down the whole app.
+//
+//	ErrorBoundary(chartWidget).
+//	    OnError(func(err error, stack []byte) { log.Printf("chart: %v\n%s", err, stack) })
+//
+// Once tripped the boundary keeps showing the error box until Reset. Its
+// child is compiled on its own, so it can't bind to ForEach item fields.
+type ErrorBoundaryC struct {
+	child   any
+	sub     *Template
+	err     error
+	onError func(err error, stack []byte)
+	style   Style
+	update  func() // set to app.RequestRender during wiring
+}
+
+// ErrorBoundary wraps child so its panics are caught.
+func ErrorBoundary(child any) *ErrorBoundaryC {
+	return &ErrorBoundaryC{child: child, style: Style{FG: Red}}
+}
+
+// Ref provides access to the component for external references.
+func (b *ErrorBoundaryC) Ref(f func(*ErrorBoundaryC)) *ErrorBoundaryC { f(b); return b }
+
+// OnError sets a callback for caught panics, with the goroutine stack at
+// the point of the panic. It runs on the render goroutine.
+func (b *ErrorBoundaryC) OnError(fn func(err error, stack []byte)) *ErrorBoundaryC {
+	b.onError = fn
+	return b
+}
+
+// Style sets the error box style. Red by default.
+func (b *ErrorBoundaryC) Style(s Style) *ErrorBoundaryC {
+	b.style = s
+	return b
+}
+
+// Err returns the caught error, or nil while the child is healthy.
+func (b *ErrorBoundaryC) Err() error { return b.err }
+
+// Reset clears the error so the child is tried again on the next frame.
+func (b *ErrorBoundaryC) Reset() {
+	b.err = nil
+	if b.update != nil {
+		b.update()
+	}
+}
+
+func (b *ErrorBoundaryC) updateHook() *func() { return &b.update }
+
+func (t *Template) compileErrorBoundaryC(b *ErrorBoundaryC, parent int16, depth int) int16 {
+	b.sub = newSubTemplate(b.child, nil, 0)
+	t.pendingBindings = append(t.pendingBindings, b.sub.pendingBindings...)
+	t.adoptLive(b.sub)
+	t.collectUpdateHook(b)
+	return t.compileCustom(Widget(b.measure, b.render), parent, depth)
+}
+
+// errorBoxHeight is the fallback's height: border plus one message row.
+const errorBoxHeight = 3
+
+func (b *ErrorBoundaryC) measure(availW int16) (w, h int16) {
+	if availW < 0 {
+		return -1, 0 // fill
+	}
+	if b.err != nil {
+		return availW, errorBoxHeight
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			b.fail(r)
+			w, h = availW, errorBoxHeight
+		}
+	}()
+	b.sub.distributeWidths(availW, nil)
+	b.sub.layout(0)
+	return availW, b.sub.Height()
+}
+
+func (b *ErrorBoundaryC) render(buf *Buffer, x, y, w, h int16) {
+	if b.err == nil {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					b.fail(r)
+					// the child may have drawn partway; the layout already
+					// reserved its height, so a follow-up frame resizes us
+					buf.FillRect(int(x), int(y), int(w), int(h), Cell{Rune: ' '})
+					if b.update != nil {
+						b.update()
+					}
+				}
+			}()
+			b.sub.clipMaxY = y + h
+			b.sub.render(buf, x, y, w)
+		}()
+		if b.err == nil {
+			return
+		}
+	}
+	b.drawError(buf, x, y, w, h)
+}
+
+func (b *ErrorBoundaryC) fail(r any) {
+	err, ok := r.(error)
+	if !ok {
+		err = fmt.Errorf("%v", r)
+	}
+	b.err = err
+	if b.onError != nil {
+		b.onError(err, debug.Stack())
+	}
+}
+
+func (b *ErrorBoundaryC) drawError(buf *Buffer, x, y, w, h int16) {
+	if w < 4 || h < 1 {
+		return
+	}
+	if h < errorBoxHeight {
+		buf.WriteStringFast(int(x), int(y), "error: "+b.err.Error(), b.style, int(w))
+		return
+	}
+	buf.DrawBorder(int(x), int(y), int(w), errorBoxHeight, BorderRounded, b.style)
+	buf.WriteStringFast(int(x)+2, int(y), " error ", Style{FG: b.style.FG, Attr: AttrBold}, int(w)-4)
+	buf.WriteStringFast(int(x)+2, int(y)+1, b.err.Error(), Style{}, int(w)-4)
+}
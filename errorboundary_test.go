@@ -0,0 +1,99 @@
+package glyph
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestErrorBoundaryCatchesRenderPanic(t *testing.T) {
+	var caught error
+	var stack []byte
+	boom := Widget(
+		func(availW int16) (w, h int16) { return availW, 1 },
+		func(buf *Buffer, x, y, w, h int16) { panic("chart exploded") },
+	)
+	boundary := ErrorBoundary(VBox(Text("title"), boom)).
+		OnError(func(err error, s []byte) { caught, stack = err, s })
+	tmpl := Build(VBox(boundary, Text("footer")))
+
+	buf := NewBuffer(30, 8)
+	tmpl.Execute(buf, 30, 8)
+	if caught == nil || caught.Error() != "chart exploded" {
+		t.Fatalf("caught = %v, want chart exploded", caught)
+	}
+	if len(stack) == 0 {
+		t.Error("expected a stack trace")
+	}
+
+	// next frame lays out the fallback box in place of the child
+	buf.Clear()
+	tmpl.Execute(buf, 30, 8)
+	if !strings.Contains(buf.GetLine(1), "chart exploded") {
+		t.Errorf("line 1 = %q, want error message", buf.GetLine(1))
+	}
+	if got := strings.TrimSpace(buf.GetLine(3)); got != "footer" {
+		t.Errorf("line 3 = %q, want footer", got)
+	}
+	if boundary.Err() == nil {
+		t.Error("Err() should report the caught panic")
+	}
+}
+
+func TestErrorBoundaryCatchesMeasurePanic(t *testing.T) {
+	var items []int
+	bad := Widget(
+		func(availW int16) (w, h int16) { return availW, int16(items[3]) },
+		func(buf *Buffer, x, y, w, h int16) {},
+	)
+	boundary := ErrorBoundary(bad)
+	tmpl := Build(VBox(boundary))
+
+	buf := NewBuffer(30, 4)
+	tmpl.Execute(buf, 30, 4)
+	if boundary.Err() == nil || !strings.Contains(boundary.Err().Error(), "index out of range") {
+		t.Fatalf("Err() = %v, want index out of range", boundary.Err())
+	}
+	if !strings.Contains(buf.GetLine(0), "error") {
+		t.Errorf("line 0 = %q, want error box", buf.GetLine(0))
+	}
+}
+
+func TestErrorBoundaryReset(t *testing.T) {
+	fail := true
+	w := Widget(
+		func(availW int16) (int16, int16) { return availW, 1 },
+		func(buf *Buffer, x, y, w, h int16) {
+			if fail {
+				panic("flaky")
+			}
+			buf.WriteString(int(x), int(y), "ok", Style{})
+		},
+	)
+	boundary := ErrorBoundary(w)
+	tmpl := Build(VBox(boundary))
+
+	buf := NewBuffer(20, 4)
+	tmpl.Execute(buf, 20, 4)
+	if boundary.Err() == nil {
+		t.Fatal("expected error")
+	}
+
+	fail = false
+	boundary.Reset()
+	buf.Clear()
+	tmpl.Execute(buf, 20, 4)
+	if got := strings.TrimSpace(buf.GetLine(0)); got != "ok" {
+		t.Errorf("after reset line 0 = %q, want ok", got)
+	}
+}
+
+func TestErrorBoundaryHealthyChild(t *testing.T) {
+	tmpl := Build(VBox(ErrorBoundary(VBox(Text("a"), Text("b"))), Text("c")))
+	buf := NewBuffer(10, 4)
+	tmpl.Execute(buf, 10, 4)
+	for i, want := range []string{"a", "b", "c"} {
+		if got := strings.TrimSpace(buf.GetLine(i)); got != want {
+			t.Errorf("line %d = %q, want %q", i, got, want)
+		}
+	}
+}
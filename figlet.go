@@ -0,0 +1,470 @@
+package glyph
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// FigletFont is a FIGlet font, the .flf format used by figlet(6) and
+// toilet, for drawing text as large letters made of characters. Fonts are
+// plain text, zipped or not, and hundreds are freely available; load one
+// with LoadFiglet or ParseFiglet and show text in it with Figlet.
+type FigletFont struct {
+	height    int
+	hardblank rune
+	layout    int // figSmush/figKern and the smushing rules, as the font asks
+	chars     map[rune][][]rune
+}
+
+// FigletLayout is how the letters of a FIGlet font are fitted together.
+type FigletLayout int
+
+const (
+	FigletDefault   FigletLayout = iota // as the font asks
+	FigletFullWidth                     // each letter at its full width
+	FigletKerning                       // letters moved together until they touch
+	FigletSmushing                      // letters overlapping by a column where the font's rules allow
+)
+
+// horizontal layout bits of the font header
+const (
+	figEqual     = 1  // two equal characters become one
+	figLowline   = 2  // an underscore gives way to |/\[]{}()<>
+	figHierarchy = 4  // | gives way to /\, which give way to [], {}, () and <> in turn
+	figPair      = 8  // opposing brackets become |
+	figBigX      = 16 // /\ becomes |, \/ Y and >< X
+	figHardblank = 32 // two hardblanks become one
+	figKern      = 64
+	figSmush     = 128
+	figRules     = 63
+)
+
+// LoadFiglet reads a FIGlet font from a .flf file.
+func LoadFiglet(path string) (*FigletFont, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	f, err := ParseFiglet(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return f, nil
+}
+
+// ParseFiglet reads a FIGlet font, for fonts embedded in the binary:
+//
+//	//go:embed slant.flf
+//	var slant []byte
+//
+//	font, err := ParseFiglet(bytes.NewReader(slant))
+func ParseFiglet(r io.Reader) (*FigletFont, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	// figlet 2.2 reads fonts zipped to save space; the font is the first file
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		z, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			return nil, fmt.Errorf("figlet: %w", err)
+		}
+		if len(z.File) == 0 {
+			return nil, errors.New("figlet: empty zip")
+		}
+		rc, err := z.File[0].Open()
+		if err != nil {
+			return nil, fmt.Errorf("figlet: %w", err)
+		}
+		data, err = io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("figlet: %w", err)
+		}
+	}
+
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	header := []rune(lines[0])
+	if !strings.HasPrefix(lines[0], "flf2a") || len(header) < 6 {
+		return nil, errors.New("figlet: not a FIGlet font")
+	}
+	var nums []int
+	for _, field := range strings.Fields(string(header[6:])) {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("figlet: header: %w", err)
+		}
+		nums = append(nums, n)
+	}
+	// height, baseline, max length, old layout and comment lines are
+	// required; print direction, full layout and the code tag count aren't
+	if len(nums) < 5 || nums[0] < 1 {
+		return nil, errors.New("figlet: bad header")
+	}
+	f := &FigletFont{height: nums[0], hardblank: header[5], chars: map[rune][][]rune{}}
+	switch old := nums[3]; {
+	case len(nums) >= 7:
+		f.layout = nums[6] & (figRules | figKern | figSmush)
+	case old == 0:
+		f.layout = figKern
+	case old > 0:
+		f.layout = old&figRules | figSmush
+	}
+
+	next := 1 + nums[4]
+	readChar := func() ([][]rune, bool) {
+		if next+f.height > len(lines) {
+			return nil, false
+		}
+		rows := make([][]rune, f.height)
+		width := 0
+		for i := range rows {
+			row := []rune(strings.TrimRight(lines[next+i], " \t"))
+			// each row ends in an end mark, doubled on the last row
+			if n := len(row); n > 0 {
+				mark := row[n-1]
+				for n > 0 && row[n-1] == mark {
+					n--
+				}
+				row = row[:n]
+			}
+			rows[i] = row
+			width = max(width, len(row))
+		}
+		for i, row := range rows {
+			for len(row) < width {
+				row = append(row, ' ')
+			}
+			rows[i] = row
+		}
+		next += f.height
+		return rows, true
+	}
+
+	var required []rune
+	for r := rune(32); r <= 126; r++ {
+		required = append(required, r)
+	}
+	required = append(required, 196, 214, 220, 228, 246, 252, 223) // ÄÖÜäöüß
+	for _, r := range required {
+		rows, ok := readChar()
+		if !ok {
+			if r < 127 {
+				return nil, fmt.Errorf("figlet: font ends before %q", r)
+			}
+			return f, nil // some old fonts stop after ASCII
+		}
+		f.chars[r] = rows
+	}
+	// then any others, each after a line giving its code
+	for next < len(lines) {
+		fields := strings.Fields(lines[next])
+		if len(fields) == 0 {
+			break
+		}
+		code, err := strconv.ParseInt(fields[0], 0, 32)
+		if err != nil {
+			return nil, fmt.Errorf("figlet: line %d: bad code %q", next+1, fields[0])
+		}
+		next++
+		rows, ok := readChar()
+		if !ok {
+			break
+		}
+		if code >= 0 { // negative codes are for translation tables
+			f.chars[rune(code)] = rows
+		}
+	}
+	return f, nil
+}
+
+// Height returns the number of rows in each line of text.
+func (f *FigletFont) Height() int { return f.height }
+
+// Has reports whether the font draws r. Characters it doesn't are left out
+// of Render's output.
+func (f *FigletFont) Has(r rune) bool {
+	_, ok := f.chars[r]
+	return ok
+}
+
+// Render draws text in the font, Height rows for each line of it. Hardblanks
+// come out as spaces, and the rows of a line are all the same width.
+func (f *FigletFont) Render(text string, layout FigletLayout) []string {
+	mode := f.layout
+	switch layout {
+	case FigletFullWidth:
+		mode = 0
+	case FigletKerning:
+		mode = figKern
+	case FigletSmushing:
+		mode = f.layout&figRules | figSmush
+	}
+	var rows []string
+	for _, line := range strings.Split(text, "\n") {
+		for _, row := range f.renderLine(line, mode) {
+			rows = append(rows, strings.ReplaceAll(string(row), string(f.hardblank), " "))
+		}
+	}
+	return rows
+}
+
+func (f *FigletFont) renderLine(text string, mode int) [][]rune {
+	out := make([][]rune, f.height)
+	prevWidth := 0
+	for _, r := range text {
+		if r == '\t' {
+			r = ' '
+		}
+		glyph, ok := f.chars[r]
+		if !ok {
+			if glyph, ok = f.chars[0]; !ok { // code 0 is the font's stand-in for missing characters
+				continue
+			}
+		}
+		width := len(glyph[0])
+		amt := f.overlap(out, glyph, mode, prevWidth, width)
+		for row := range out {
+			line, g := out[row], glyph[row]
+			at := len(line) - amt
+			for k := 0; k < amt; k++ {
+				if c := f.smush(line[at+k], g[k], mode, prevWidth, width); c != 0 {
+					line[at+k] = c
+				} else {
+					line[at+k] = g[k]
+				}
+			}
+			out[row] = append(line, g[amt:]...)
+		}
+		prevWidth = width
+	}
+	return out
+}
+
+// overlap is how many columns the next letter can move left into the line
+// so far, the least any row allows.
+func (f *FigletFont) overlap(out, glyph [][]rune, mode, prevWidth, width int) int {
+	if mode&(figKern|figSmush) == 0 {
+		return 0
+	}
+	amt := min(width, len(out[0]))
+	for row := range out {
+		line, g := out[row], glyph[row]
+		end := len(line) - 1 // the line's last visible column
+		for end > 0 && line[end] == ' ' {
+			end--
+		}
+		var left rune
+		if end >= 0 {
+			left = line[end]
+		}
+		start := 0 // the letter's first
+		for start < len(g) && g[start] == ' ' {
+			start++
+		}
+		var right rune
+		if start < len(g) {
+			right = g[start]
+		}
+		n := start + len(line) - 1 - end
+		if left == 0 || left == ' ' {
+			n++
+		} else if right != 0 && f.smush(left, right, mode, prevWidth, width) != 0 {
+			n++
+		}
+		amt = min(amt, n)
+	}
+	return max(amt, 0)
+}
+
+// smush returns what two overlapping characters become, or 0 if they
+// can't overlap. The rules are figlet's own.
+func (f *FigletFont) smush(l, r rune, mode, prevWidth, width int) rune {
+	if l == ' ' {
+		return r
+	}
+	if r == ' ' {
+		return l
+	}
+	if prevWidth < 2 || width < 2 || mode&figSmush == 0 {
+		return 0
+	}
+	if mode&figRules == 0 { // universal: the later letter wins, over hardblanks too
+		if l == f.hardblank {
+			return r
+		}
+		if r == f.hardblank {
+			return l
+		}
+		return r
+	}
+	if l == f.hardblank || r == f.hardblank {
+		if mode&figHardblank != 0 && l == r {
+			return l
+		}
+		return 0
+	}
+	if mode&figEqual != 0 && l == r {
+		return l
+	}
+	if mode&figLowline != 0 {
+		if l == '_' && strings.ContainsRune(`|/\[]{}()<>`, r) {
+			return r
+		}
+		if r == '_' && strings.ContainsRune(`|/\[]{}()<>`, l) {
+			return l
+		}
+	}
+	if mode&figHierarchy != 0 {
+		classes := []string{"|", `/\`, "[]", "{}", "()", "<>"}
+		cl, cr := -1, -1
+		for i, c := range classes {
+			if strings.ContainsRune(c, l) {
+				cl = i
+			}
+			if strings.ContainsRune(c, r) {
+				cr = i
+			}
+		}
+		if cl >= 0 && cr >= 0 && cl != cr {
+			if cl > cr {
+				return l
+			}
+			return r
+		}
+	}
+	if mode&figPair != 0 {
+		switch string([]rune{l, r}) {
+		case "[]", "][", "{}", "}{", "()", ")(":
+			return '|'
+		}
+	}
+	if mode&figBigX != 0 {
+		switch string([]rune{l, r}) {
+		case `/\`:
+			return '|'
+		case `\/`:
+			return 'Y'
+		case "><":
+			return 'X'
+		}
+	}
+	return 0
+}
+
+// FigletC shows text in large letters from a FIGlet font, for banners,
+// splash screens and big readouts:
+//
+//	font, err := LoadFiglet("/usr/share/figlet/standard.flf")
+//	...
+//	Figlet(font, &title).Gradient(Hex(0xff6ac1), Hex(0x57c7ff))
+//
+// Content is a string or *string, read each frame; newlines start another
+// line of letters. With no font the text is shown as it is.
+type FigletC struct {
+	font     *FigletFont
+	content  any // string or *string
+	layout   FigletLayout
+	style    Style
+	from, to Color
+	gradient bool
+
+	// the last text drawn, redone when the text changes
+	rendered    string
+	rows        []string
+	hasRendered bool
+}
+
+// Figlet creates large text of content, a string or *string, in font.
+func Figlet(font *FigletFont, content any) *FigletC {
+	return &FigletC{font: font, content: content}
+}
+
+// Layout sets how the letters fit together, as the font asks by default.
+func (c *FigletC) Layout(l FigletLayout) *FigletC {
+	c.layout = l
+	return c
+}
+
+// Style sets the style of the letters.
+func (c *FigletC) Style(s Style) *FigletC {
+	c.style = s
+	return c
+}
+
+// FG sets the color of the letters.
+func (c *FigletC) FG(col Color) *FigletC {
+	c.style.FG = col
+	return c
+}
+
+// Gradient colors the letters from one color on the left to another on the
+// right, in place of the style's foreground.
+func (c *FigletC) Gradient(from, to Color) *FigletC {
+	c.from, c.to, c.gradient = from, to, true
+	return c
+}
+
+func (c *FigletC) render() []string {
+	var text string
+	switch v := c.content.(type) {
+	case string:
+		text = v
+	case *string:
+		text = *v
+	}
+	if !c.hasRendered || text != c.rendered {
+		if c.font == nil {
+			c.rows = strings.Split(text, "\n")
+		} else {
+			c.rows = c.font.Render(text, c.layout)
+		}
+		c.rendered, c.hasRendered = text, true
+	}
+	return c.rows
+}
+
+func (c *FigletC) measure(availW int16) (int16, int16) {
+	rows := c.render()
+	w := 0
+	for _, row := range rows {
+		w = max(w, utf8.RuneCountInString(row))
+	}
+	return int16(w), int16(len(rows))
+}
+
+func (c *FigletC) draw(buf *Buffer, x, y, w, h int16) {
+	rows := c.render()
+	width, _ := c.measure(w)
+	from, to := rgbColor(c.from, true), rgbColor(c.to, true)
+	for i, row := range rows {
+		if i >= int(h) {
+			break
+		}
+		col := 0
+		for _, r := range row { // a font's characters are a column each
+			if col >= int(w) {
+				break
+			}
+			if r != ' ' {
+				st := c.style
+				if c.gradient {
+					st.FG = LerpColor(from, to, float64(col)/float64(max(width-1, 1)))
+				}
+				buf.Set(int(x)+col, int(y)+i, Cell{Rune: r, Style: st})
+			}
+			col++
+		}
+	}
+}
+
+func (t *Template) compileFigletC(v *FigletC, parent int16, depth int) int16 {
+	return t.compileCustom(Custom{Measure: v.measure, Render: v.draw}, parent, depth)
+}
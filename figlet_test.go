@@ -0,0 +1,121 @@
+package glyph
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// testFigletFont writes a two-row font: a few real letters, "?" for the
+// rest of ASCII and the German letters, then two code-tagged characters.
+func testFigletFont(fullLayout int) string {
+	glyphs := map[rune][2]string{
+		' ':  {"$$", "$$"},
+		'H':  {"H  H", "HHHH"},
+		'I':  {" I", " I"},
+		'/':  {" /", "/ "},
+		'\\': {`\ `, ` \`},
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "flf2a$ 2 2 4 -1 1 0 %d 2\ntest font\n", fullLayout)
+	char := func(g [2]string) {
+		fmt.Fprintf(&b, "%s@\n%s@@\n", g[0], g[1])
+	}
+	for r := rune(32); r <= 126; r++ {
+		g, ok := glyphs[r]
+		if !ok {
+			g = [2]string{"?", "?"}
+		}
+		char(g)
+	}
+	for range 7 {
+		char([2]string{"ß", "ß"})
+	}
+	b.WriteString("0x263A  WHITE SMILING FACE\n")
+	char([2]string{":)", ":)"})
+	b.WriteString("-2  from a translation table\n")
+	char([2]string{"xx", "xx"})
+	return b.String()
+}
+
+func TestFigletFont(t *testing.T) {
+	f, err := ParseFiglet(strings.NewReader(testFigletFont(figSmush | figBigX)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Height() != 2 || !f.Has('☺') || !f.Has('ü') || f.Has(-2) || f.Has('€') {
+		t.Fatalf("height %d, chars %d", f.Height(), len(f.chars))
+	}
+
+	for _, c := range []struct {
+		text   string
+		layout FigletLayout
+		want   []string
+	}{
+		{"HIH", FigletFullWidth, []string{"H  H IH  H", "HHHH IHHHH"}},
+		{"HIH", FigletKerning, []string{"H  HIH  H", "HHHHIHHHH"}},
+		{"HH", FigletKerning, []string{"H  HH  H", "HHHHHHHH"}},
+		{"H H", FigletKerning, []string{"H  H  H  H", "HHHH  HHHH"}}, // hardblanks keep letters apart
+		{"/\\", FigletDefault, []string{" | ", `/ \`}},
+		{"HH", FigletDefault, []string{"H  HH  H", "HHHHHHHH"}}, // no rule for H on H
+		{"H€H", FigletKerning, []string{"H  HH  H", "HHHHHHHH"}},
+		{"H\n☺", FigletFullWidth, []string{"H  H", "HHHH", ":)", ":)"}},
+	} {
+		if got := f.Render(c.text, c.layout); strings.Join(got, "|") != strings.Join(c.want, "|") {
+			t.Errorf("%q in layout %d = %q, want %q", c.text, c.layout, got, c.want)
+		}
+	}
+
+	// universal smushing: with no rules the later letter wins
+	u, _ := ParseFiglet(strings.NewReader(testFigletFont(figSmush)))
+	if got := u.Render("HH", FigletDefault); got[0] != "H  H  H" {
+		t.Errorf("universal smushing = %q", got)
+	}
+}
+
+func TestParseFiglet(t *testing.T) {
+	var zipped bytes.Buffer
+	z := zip.NewWriter(&zipped)
+	w, _ := z.Create("test.flf")
+	w.Write([]byte(testFigletFont(0)))
+	z.Close()
+	f, err := ParseFiglet(&zipped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := f.Render("HI", FigletDefault); got[0] != "H  H I" {
+		t.Errorf("zipped font renders %q", got)
+	}
+
+	for _, bad := range []string{"", "flf2a$ x 2", "flf2a$ 2 2 4", "flf2a$ 2 2 4 -1 0\nA@\nA@@\n"} {
+		if _, err := ParseFiglet(strings.NewReader(bad)); err == nil {
+			t.Errorf("%q parsed", bad)
+		}
+	}
+}
+
+func TestFiglet(t *testing.T) {
+	f, _ := ParseFiglet(strings.NewReader(testFigletFont(figKern)))
+	title := "HI"
+	tmpl := Build(VBox(Figlet(f, &title).Gradient(Hex(0x000000), Hex(0xff0000))))
+	buf := NewBuffer(20, 4)
+	tmpl.Execute(buf, 20, 4)
+	if got := buf.GetLine(1); got != "HHHHI" {
+		t.Errorf("row 1 = %q", got)
+	}
+	if got := buf.Get(4, 1).Style.FG; got != Hex(0xff0000) {
+		t.Errorf("right edge = %+v", got)
+	}
+	if got := buf.Get(0, 1).Style.FG; got != Hex(0x000000) {
+		t.Errorf("left edge = %+v", got)
+	}
+
+	title = "I"
+	buf = NewBuffer(20, 4)
+	tmpl.Execute(buf, 20, 4)
+	if got := buf.GetLine(0); got != " I" {
+		t.Errorf("after change, row 0 = %q", got)
+	}
+}
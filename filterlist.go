@@ -16,6 +16,7 @@ type FilterListC[T any] struct {
 	filter *Filter[T]
 
 	placeholder string
+	header      any
 	maxVisible  int
 	border      BorderStyle
 	title       string
@@ -57,8 +58,11 @@ func (fl *FilterListC[T]) toTemplate() any {
 			Text("> ").Bold(),
 			fl.input,
 		),
-		fl.list,
 	}
+	if fl.header != nil {
+		children = append(children, fl.header)
+	}
+	children = append(children, fl.list)
 
 	box := VBox
 	if fl.border.Horizontal != 0 {
@@ -91,6 +95,13 @@ func (fl *FilterListC[T]) Placeholder(p string) *FilterListC[T] {
 	return fl
 }
 
+// Header sets a view shown between the input and the list, such as
+// column titles.
+func (fl *FilterListC[T]) Header(view any) *FilterListC[T] {
+	fl.header = view
+	return fl
+}
+
 // Render sets the render function for each list item.
 func (fl *FilterListC[T]) Render(fn func(*T) any) *FilterListC[T] {
 	fl.list.Render(fn)
@@ -180,6 +191,18 @@ func (fl *FilterListC[T]) SelectedIndex() int {
 	return fl.filter.OriginalIndex(fl.list.Index())
 }
 
+// Refresh filters the source again after it has changed, keeping the
+// query.
+func (fl *FilterListC[T]) Refresh() {
+	fl.filter.Reset()
+	fl.sync()
+}
+
+// SetIndex selects the item at index i of the filtered items.
+func (fl *FilterListC[T]) SetIndex(i int) {
+	fl.list.SetIndex(i)
+}
+
 // Clear resets the filter and input.
 func (fl *FilterListC[T]) Clear() {
 	fl.input.Clear()
@@ -0,0 +1,591 @@
+package glyph
+
+import (
+	"bufio"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kungfusheep/glyph/format"
+	"github.com/kungfusheep/riffkey"
+)
+
+// FlameFrame is a frame of a flame graph: a function or span, the time
+// spent in it and everything it called, and the frames it called.
+type FlameFrame struct {
+	Name     string
+	Value    time.Duration // total, children included
+	Children []*FlameFrame
+}
+
+// NewFlameFrame creates an empty frame, usually the root to Add stacks to.
+func NewFlameFrame(name string) *FlameFrame {
+	return &FlameFrame{Name: name}
+}
+
+// Add records d spent in stack, outermost frame first, beneath f. Every
+// frame along the way gains d, so f's Value stays the total. Samples from
+// a pprof profile go in this way, a sample's locations reversed:
+//
+//	root := NewFlameFrame("all")
+//	for _, s := range prof.Sample {
+//	    root.Add(names(s.Location), time.Duration(s.Value[1]))
+//	}
+func (f *FlameFrame) Add(stack []string, d time.Duration) {
+	f.Value += d
+	for _, name := range stack {
+		c := f.Child(name)
+		if c == nil {
+			c = NewFlameFrame(name)
+			f.Children = append(f.Children, c)
+		}
+		c.Value += d
+		f = c
+	}
+}
+
+// Child returns the child named name, or nil.
+func (f *FlameFrame) Child(name string) *FlameFrame {
+	for _, c := range f.Children {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// Self returns the time spent in f itself rather than its children.
+func (f *FlameFrame) Self() time.Duration {
+	self := f.Value
+	for _, c := range f.Children {
+		self -= c.Value
+	}
+	return max(self, 0)
+}
+
+// ReadFoldedStacks reads stacks in the folded format flamegraph.pl takes,
+// one "main;run;parse 42" per line, into a frame named "all". Counts are
+// multiplied by unit: the sampling interval for sample counts, or
+// time.Nanosecond for stacks folded from nanoseconds.
+func ReadFoldedStacks(r io.Reader, unit time.Duration) (*FlameFrame, error) {
+	root := NewFlameFrame("all")
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" {
+			continue
+		}
+		i := strings.LastIndexByte(line, ' ')
+		if i < 0 {
+			return nil, fmt.Errorf("folded stacks: line %d: no count", n)
+		}
+		count, err := strconv.ParseFloat(line[i+1:], 64)
+		if err != nil {
+			return nil, fmt.Errorf("folded stacks: line %d: %w", n, err)
+		}
+		root.Add(strings.Split(strings.TrimSpace(line[:i]), ";"), time.Duration(count*float64(unit)))
+	}
+	return root, sc.Err()
+}
+
+// TraceSpan is a span of a trace, as an OpenTelemetry exporter gives them.
+type TraceSpan struct {
+	ID, Parent string // Parent is empty, or unknown, for a root span
+	Name       string
+	Start      time.Time
+	Duration   time.Duration
+}
+
+// FlameFromSpans builds a flame graph of a trace: each span a frame whose
+// children are the spans it parents, in the order they started. A trace
+// with several roots is gathered under one named "all".
+//
+// Spans keep their durations but not their place in time, so a graph of
+// spans shows where the time went, not when.
+func FlameFromSpans(spans []TraceSpan) *FlameFrame {
+	spans = slices.Clone(spans)
+	slices.SortStableFunc(spans, func(a, b TraceSpan) int { return a.Start.Compare(b.Start) })
+	frames := make(map[string]*FlameFrame, len(spans))
+	for _, s := range spans {
+		frames[s.ID] = &FlameFrame{Name: s.Name, Value: s.Duration}
+	}
+	var roots []*FlameFrame
+	for _, s := range spans {
+		f := frames[s.ID]
+		if p, ok := frames[s.Parent]; ok && s.Parent != s.ID {
+			p.Children = append(p.Children, f)
+		} else {
+			roots = append(roots, f)
+		}
+	}
+	if len(roots) == 1 {
+		return roots[0]
+	}
+	all := &FlameFrame{Name: "all", Children: roots}
+	for _, r := range roots {
+		all.Value += r.Value
+	}
+	return all
+}
+
+// flamePath returns the frames from root down to f, or nil if f isn't
+// beneath root.
+func flamePath(root, f *FlameFrame) []*FlameFrame {
+	if root == f {
+		return []*FlameFrame{root}
+	}
+	for _, c := range root.Children {
+		if p := flamePath(c, f); p != nil {
+			return append([]*FlameFrame{root}, p...)
+		}
+	}
+	return nil
+}
+
+// ============================================================================
+// FlameGraphC
+// ============================================================================
+
+// FlameGraphC draws a FlameFrame tree as a flame graph, the root along the
+// top and each frame's children beneath it, as wide as their share of its
+// time:
+//
+//	FlameGraph(root).
+//	    BindNav("h", "l", "k", "j").
+//	    BindZoom("<Enter>", "<BS>").
+//	    BindSearchNav("n", "N").
+//	    Grow(1)
+//
+// One frame is selected, with a tooltip giving its name and time. Zooming
+// in widens the selected frame to the full width, its ancestors above it;
+// frames narrower than a cell aren't drawn. Search highlights frames whose
+// names hold the query.
+type FlameGraphC struct {
+	layer    *Layer
+	root     *FlameFrame
+	zoom     *FlameFrame
+	selected *FlameFrame
+	query    string
+	top      int // first depth shown
+
+	boxes []flameBox // the last layout
+	width int        // the width it was laid out to
+
+	colors        []Color
+	selectedStyle Style
+	matchStyle    Style
+	tooltipStyle  Style
+
+	grow   float32
+	height int16
+
+	declaredBindings []binding
+}
+
+// flameBox is where a frame is drawn: its depth and the cells it spans.
+type flameBox struct {
+	frame  *FlameFrame
+	depth  int
+	x0, x1 int
+}
+
+// flameColors are the warm colors frames are drawn in, picked by name so a
+// function keeps its color.
+var flameColors = []Color{
+	PaletteColor(166), PaletteColor(172), PaletteColor(178), PaletteColor(202),
+	PaletteColor(208), PaletteColor(214), PaletteColor(209), PaletteColor(215),
+}
+
+// FlameGraph creates a flame graph of root.
+func FlameGraph(root *FlameFrame) *FlameGraphC {
+	g := &FlameGraphC{
+		layer:         NewLayer(),
+		width:         80,
+		colors:        flameColors,
+		selectedStyle: Style{FG: Black, BG: BrightWhite, Attr: AttrBold},
+		matchStyle:    Style{FG: Black, BG: PaletteColor(170)},
+		tooltipStyle:  Style{FG: BrightWhite, BG: PaletteColor(236)},
+	}
+	g.layer.AlwaysRender = true
+	g.layer.Render = g.sync
+	g.SetRoot(root)
+	return g
+}
+
+// Ref provides access to the component for external references.
+func (g *FlameGraphC) Ref(f func(*FlameGraphC)) *FlameGraphC { f(g); return g }
+
+// Colors sets the background colors frames are drawn in.
+func (g *FlameGraphC) Colors(c ...Color) *FlameGraphC {
+	if len(c) > 0 {
+		g.colors = c
+	}
+	return g
+}
+
+// SelectedStyle sets the style of the selected frame.
+func (g *FlameGraphC) SelectedStyle(s Style) *FlameGraphC {
+	g.selectedStyle = s
+	return g
+}
+
+// MatchStyle sets the style of frames matching the search.
+func (g *FlameGraphC) MatchStyle(s Style) *FlameGraphC {
+	g.matchStyle = s
+	return g
+}
+
+// TooltipStyle sets the style of the selected frame's tooltip.
+func (g *FlameGraphC) TooltipStyle(s Style) *FlameGraphC {
+	g.tooltipStyle = s
+	return g
+}
+
+// Grow sets the flex grow factor.
+func (g *FlameGraphC) Grow(n float32) *FlameGraphC {
+	g.grow = n
+	return g
+}
+
+// Height sets a fixed viewport height.
+func (g *FlameGraphC) Height(h int16) *FlameGraphC {
+	g.height = h
+	return g
+}
+
+// BindNav registers keys that select the frame to the left or right, the
+// caller above, or the widest callee below. A count moves that many.
+func (g *FlameGraphC) BindNav(left, right, up, down string) *FlameGraphC {
+	repeat := func(fn func()) func(riffkey.Match) {
+		return func(m riffkey.Match) {
+			for range m.Count {
+				fn()
+			}
+		}
+	}
+	g.declaredBindings = append(g.declaredBindings,
+		binding{pattern: left, handler: repeat(func() { g.step(-1) })},
+		binding{pattern: right, handler: repeat(func() { g.step(1) })},
+		binding{pattern: up, handler: repeat(g.Up)},
+		binding{pattern: down, handler: repeat(g.Down)},
+	)
+	return g
+}
+
+// BindZoom registers keys that zoom into the selected frame and back out.
+func (g *FlameGraphC) BindZoom(in, out string) *FlameGraphC {
+	g.declaredBindings = append(g.declaredBindings,
+		binding{pattern: in, handler: g.ZoomIn},
+		binding{pattern: out, handler: g.ZoomOut},
+	)
+	return g
+}
+
+// BindSearchNav registers keys that select the next and previous frames
+// matching the search, left to right.
+func (g *FlameGraphC) BindSearchNav(next, prev string) *FlameGraphC {
+	g.declaredBindings = append(g.declaredBindings,
+		binding{pattern: next, handler: func() { g.nextMatch(1) }},
+		binding{pattern: prev, handler: func() { g.nextMatch(-1) }},
+	)
+	return g
+}
+
+func (g *FlameGraphC) bindings() []binding { return g.declaredBindings }
+
+// SetRoot replaces the graph, zoomed out with the root selected. Frames
+// added to the graph on show appear on the next render without it.
+func (g *FlameGraphC) SetRoot(root *FlameFrame) {
+	g.root, g.zoom, g.selected, g.top = root, root, root, 0
+}
+
+// Root returns the graph's root frame.
+func (g *FlameGraphC) Root() *FlameFrame { return g.root }
+
+// Selected returns the selected frame.
+func (g *FlameGraphC) Selected() *FlameFrame { return g.selected }
+
+// Select selects a frame, zooming out if it's hidden beside the zoomed one.
+func (g *FlameGraphC) Select(f *FlameFrame) {
+	path := flamePath(g.root, f)
+	if path == nil {
+		return
+	}
+	if !slices.Contains(path, g.zoom) && !slices.Contains(flamePath(g.root, g.zoom), f) {
+		g.zoom = g.root
+	}
+	g.selected = f
+}
+
+// Zoomed returns the frame the graph is zoomed into, the root if none.
+func (g *FlameGraphC) Zoomed() *FlameFrame { return g.zoom }
+
+// ZoomIn widens the selected frame to the full width.
+func (g *FlameGraphC) ZoomIn() {
+	if g.selected != nil {
+		g.zoom = g.selected
+	}
+}
+
+// ZoomOut widens the zoomed frame's caller to the full width.
+func (g *FlameGraphC) ZoomOut() {
+	if path := flamePath(g.root, g.zoom); len(path) > 1 {
+		g.zoom = path[len(path)-2]
+	}
+}
+
+// Up selects the selected frame's caller.
+func (g *FlameGraphC) Up() {
+	if path := flamePath(g.root, g.selected); len(path) > 1 {
+		g.selected = path[len(path)-2]
+	}
+}
+
+// Down selects the widest frame the selected one calls that is drawn.
+func (g *FlameGraphC) Down() {
+	sel, ok := g.box(g.selected)
+	if !ok {
+		return
+	}
+	best := -1
+	for i, b := range g.boxes {
+		if b.depth == sel.depth+1 && b.x0 >= sel.x0 && b.x1 <= sel.x1 &&
+			(best < 0 || b.x1-b.x0 > g.boxes[best].x1-g.boxes[best].x0) {
+			best = i
+		}
+	}
+	if best >= 0 {
+		g.selected = g.boxes[best].frame
+	}
+}
+
+// step selects the nearest frame drawn at the same depth to the left
+// (dir < 0) or right.
+func (g *FlameGraphC) step(dir int) {
+	sel, ok := g.box(g.selected)
+	if !ok {
+		return
+	}
+	var next *flameBox
+	for i := range g.boxes {
+		b := &g.boxes[i]
+		if b.depth != sel.depth {
+			continue
+		}
+		if dir < 0 && b.x1 <= sel.x0 && (next == nil || b.x0 > next.x0) ||
+			dir > 0 && b.x0 >= sel.x1 && (next == nil || b.x0 < next.x0) {
+			next = b
+		}
+	}
+	if next != nil {
+		g.selected = next.frame
+	}
+}
+
+// Search highlights the frames whose names hold query, ignoring case. An
+// empty query clears it.
+func (g *FlameGraphC) Search(query string) {
+	g.query = strings.ToLower(query)
+}
+
+// Matched returns the time spent in frames matching the search, each
+// counted once however deeply they nest.
+func (g *FlameGraphC) Matched() time.Duration {
+	if g.query == "" || g.root == nil {
+		return 0
+	}
+	var sum func(f *FlameFrame) time.Duration
+	sum = func(f *FlameFrame) time.Duration {
+		if g.matches(f) {
+			return f.Value
+		}
+		var d time.Duration
+		for _, c := range f.Children {
+			d += sum(c)
+		}
+		return d
+	}
+	return sum(g.root)
+}
+
+func (g *FlameGraphC) matches(f *FlameFrame) bool {
+	return g.query != "" && strings.Contains(strings.ToLower(f.Name), g.query)
+}
+
+// nextMatch selects the next matching frame drawn, left to right then top
+// to bottom, wrapping around.
+func (g *FlameGraphC) nextMatch(dir int) {
+	g.layout(g.width)
+	var matches []flameBox
+	for _, b := range g.boxes {
+		if g.matches(b.frame) {
+			matches = append(matches, b)
+		}
+	}
+	if len(matches) == 0 {
+		return
+	}
+	slices.SortStableFunc(matches, func(a, b flameBox) int {
+		if a.x0 != b.x0 {
+			return a.x0 - b.x0
+		}
+		return a.depth - b.depth
+	})
+	i := slices.IndexFunc(matches, func(b flameBox) bool { return b.frame == g.selected })
+	switch {
+	case i < 0 && dir < 0:
+		i = len(matches) - 1
+	case i < 0:
+		i = 0
+	default:
+		i = (i + dir + len(matches)) % len(matches)
+	}
+	g.selected = matches[i].frame
+}
+
+// box returns where f was laid out, laying out again first so moves made
+// between frames see the graph as it now is.
+func (g *FlameGraphC) box(f *FlameFrame) (flameBox, bool) {
+	g.layout(g.width)
+	for _, b := range g.boxes {
+		if b.frame == f {
+			return b, true
+		}
+	}
+	return flameBox{}, false
+}
+
+// layout places the zoomed frame's ancestors and itself across the full
+// width, then each frame's children beneath it by their share of its time.
+func (g *FlameGraphC) layout(w int) {
+	g.boxes = g.boxes[:0]
+	if g.root == nil {
+		return
+	}
+	path := flamePath(g.root, g.zoom)
+	if path == nil {
+		g.zoom = g.root
+		path = []*FlameFrame{g.root}
+	}
+	for d, f := range path {
+		g.boxes = append(g.boxes, flameBox{frame: f, depth: d, x0: 0, x1: w})
+	}
+	var place func(f *FlameFrame, depth int, x0, x1 float64)
+	place = func(f *FlameFrame, depth int, x0, x1 float64) {
+		total := f.Value
+		var children time.Duration
+		for _, c := range f.Children {
+			children += c.Value
+		}
+		total = max(total, children)
+		if total <= 0 {
+			return
+		}
+		scale := (x1 - x0) / float64(total)
+		var at time.Duration
+		for _, c := range f.Children {
+			a := x0 + float64(at)*scale
+			at += c.Value
+			b := x0 + float64(at)*scale
+			if ia, ib := int(math.Round(a)), int(math.Round(b)); ib > ia {
+				g.boxes = append(g.boxes, flameBox{frame: c, depth: depth, x0: ia, x1: ib})
+				place(c, depth+1, a, b)
+			}
+		}
+	}
+	place(g.zoom, len(path), 0, float64(w))
+}
+
+func (g *FlameGraphC) sync() {
+	w, h := g.layer.ViewportWidth(), g.layer.ViewportHeight()
+	if w <= 0 || h <= 0 {
+		return
+	}
+	g.width = w
+	out := NewBuffer(w, h)
+	if _, ok := g.box(g.selected); !ok {
+		g.selected = g.zoom
+	}
+	sel, ok := g.box(g.selected)
+	if !ok {
+		out.WriteStringFast(0, 0, "no frames", Style{Attr: AttrDim}, w)
+		g.layer.SetBuffer(out)
+		return
+	}
+	if sel.depth < g.top {
+		g.top = sel.depth
+	} else if sel.depth >= g.top+h {
+		g.top = sel.depth - h + 1
+	}
+
+	dim := Style{FG: BrightBlack, BG: PaletteColor(236)}
+	for _, b := range g.boxes {
+		y := b.depth - g.top
+		if y < 0 || y >= h {
+			continue
+		}
+		style := Style{FG: Black, BG: g.color(b.frame.Name)}
+		switch {
+		case b.frame == g.selected:
+			style = g.selectedStyle
+		case g.matches(b.frame):
+			style = g.matchStyle
+		case g.query != "":
+			style = dim
+		}
+		width := b.x1 - b.x0
+		out.FillRect(b.x0, y, width, 1, Cell{Rune: ' ', Style: style})
+		if width > 1 {
+			out.WriteStringFast(b.x0, y, b.frame.Name, style, width-1)
+		}
+	}
+	g.drawTooltip(out, sel, w, h)
+	g.layer.SetBuffer(out)
+}
+
+// color picks a frame's color by its name.
+func (g *FlameGraphC) color(name string) Color {
+	hash := fnv.New32a()
+	io.WriteString(hash, name)
+	return g.colors[hash.Sum32()%uint32(len(g.colors))]
+}
+
+// drawTooltip labels the selected frame with its name, time and share of
+// the whole, on the row beneath it or above if it's at the bottom.
+func (g *FlameGraphC) drawTooltip(out *Buffer, sel flameBox, w, h int) {
+	f := sel.frame
+	text := fmt.Sprintf(" %s · %s", f.Name, format.Duration(f.Value))
+	if g.root.Value > 0 {
+		text += " · " + format.Percent(float64(f.Value)/float64(g.root.Value), 1)
+	}
+	if self := f.Self(); self > 0 && len(f.Children) > 0 {
+		text += " · self " + format.Duration(self)
+	}
+	text += " "
+	y := sel.depth - g.top + 1
+	if y >= h {
+		y -= 2
+	}
+	if y < 0 {
+		return
+	}
+	tw := min(StringWidth(text), w)
+	x := max(min(sel.x0, w-tw), 0)
+	out.FillRect(x, y, tw, 1, Cell{Rune: ' ', Style: g.tooltipStyle})
+	out.WriteStringFast(x, y, text, g.tooltipStyle, tw)
+}
+
+func (t *Template) compileFlameGraphC(v *FlameGraphC, parent int16, depth int) int16 {
+	t.collectBindings(v)
+	layerView := LayerView(v.layer).Grow(v.grow)
+	if v.height > 0 {
+		layerView = layerView.ViewHeight(v.height)
+	}
+	return t.compileLayerViewC(layerView, parent, depth)
+}
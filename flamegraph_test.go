@@ -0,0 +1,106 @@
+package glyph
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFlameFrame(t *testing.T) {
+	root, err := ReadFoldedStacks(strings.NewReader(`
+main;run;parse 30
+main;run;eval 50
+main;gc 20
+`), time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	main := root.Child("main")
+	if root.Value != 100*time.Millisecond || main.Value != root.Value || len(main.Children) != 2 {
+		t.Fatalf("root %v, main %+v", root.Value, main)
+	}
+	run := main.Child("run")
+	if run.Value != 80*time.Millisecond || run.Self() != 0 || main.Self() != 0 {
+		t.Errorf("run = %v, self %v", run.Value, run.Self())
+	}
+	if _, err := ReadFoldedStacks(strings.NewReader("main;run\n"), time.Millisecond); err == nil {
+		t.Error("line without a count accepted")
+	}
+
+	start := time.Unix(0, 0)
+	spans := FlameFromSpans([]TraceSpan{
+		{ID: "b", Parent: "a", Name: "db", Start: start.Add(2 * time.Millisecond), Duration: 3 * time.Millisecond},
+		{ID: "c", Parent: "a", Name: "auth", Start: start.Add(time.Millisecond), Duration: time.Millisecond},
+		{ID: "a", Name: "GET /users", Start: start, Duration: 10 * time.Millisecond},
+	})
+	if spans.Name != "GET /users" || len(spans.Children) != 2 || spans.Children[0].Name != "auth" || spans.Self() != 6*time.Millisecond {
+		t.Errorf("spans = %+v", spans)
+	}
+}
+
+func TestFlameGraph(t *testing.T) {
+	root := NewFlameFrame("all")
+	root.Add([]string{"main", "parse"}, 25*time.Millisecond)
+	root.Add([]string{"main", "eval", "lookup"}, 75*time.Millisecond)
+
+	g := FlameGraph(root).Height(4)
+	tmpl := Build(VBox(g))
+	frame := func() *Buffer {
+		buf := NewBuffer(40, 4)
+		tmpl.Execute(buf, 40, 4)
+		return buf
+	}
+
+	buf := frame()
+	if got := buf.GetLine(0); !strings.HasPrefix(got, "all") {
+		t.Errorf("row 0 = %q", got)
+	}
+	if got := buf.GetLine(1); !strings.HasPrefix(got, " all · 100ms · 100.0%") {
+		t.Errorf("tooltip = %q", got)
+	}
+	if got := buf.GetLine(2); !strings.HasPrefix(got, "parse") || !strings.Contains(got[10:], "eval") {
+		t.Errorf("row 2 = %q", got)
+	}
+	if c := buf.Get(0, 0); c.Style != g.selectedStyle {
+		t.Errorf("root not drawn selected: %+v", c.Style)
+	}
+
+	g.Down()
+	g.Down()
+	if g.Selected().Name != "eval" {
+		t.Fatalf("down selected %q, want the widest child", g.Selected().Name)
+	}
+	g.step(-1)
+	if g.Selected().Name != "parse" {
+		t.Errorf("left selected %q", g.Selected().Name)
+	}
+	g.step(1)
+	g.ZoomIn()
+	buf = frame()
+	if got := buf.GetLine(2); !strings.HasPrefix(got, "eval") {
+		t.Errorf("zoomed frame not full width: %q", got)
+	}
+	if got := buf.GetLine(3); !strings.HasPrefix(got, " eval · 75ms · 75.0%") {
+		t.Errorf("zoomed tooltip = %q", got)
+	}
+	g.ZoomOut()
+	if g.Zoomed().Name != "main" {
+		t.Errorf("zoomed out to %q", g.Zoomed().Name)
+	}
+
+	g.Search("LOOK")
+	if g.Matched() != 75*time.Millisecond {
+		t.Errorf("matched %v", g.Matched())
+	}
+	g.nextMatch(1)
+	if g.Selected().Name != "lookup" {
+		t.Errorf("next match selected %q", g.Selected().Name)
+	}
+	buf = frame()
+	if got := buf.GetLine(2); !strings.Contains(got, " lookup · 75ms") {
+		t.Errorf("tooltip on the bottom row not drawn above: %q", got)
+	}
+	if c := buf.Get(10, 1); c.Style.FG != BrightBlack {
+		t.Errorf("frame not matching the search not dimmed: %+v", c.Style)
+	}
+}
@@ -0,0 +1,224 @@
+package glyph
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// FlashC highlights its child for a moment whenever a watched value
+// changes, fading back to normal over Duration, the classic trading-grid
+// tick:
+//
+//	FlashOnChange(Text(&m.Price)).UpDown(Style{BG: RGB(0, 96, 48)}, Style{BG: RGB(128, 32, 32)})
+//
+// A Text bound to a pointer is watched automatically; anything else needs
+// Watch. Colours fade when both the flash and the cell beneath are RGB and
+// otherwise hold until the flash ends. The child is compiled on its own, so
+// it can't bind to ForEach item fields.
+type FlashC struct {
+	child    any
+	watch    any // pointer to the watched value
+	sub      *Template
+	style    Style
+	up, down *Style // directional styles for numeric values
+	duration time.Duration
+
+	started  bool
+	last     any
+	flashing bool
+	flash    Style // style of the flash under way
+	since    time.Time
+
+	update  func() // set to app.RequestRender during wiring
+	pending atomic.Bool
+	clock   Clock
+	still   bool // below QualityFull, hold the flash instead of fading it
+}
+
+// FlashOnChange wraps child so it flashes when its value changes.
+func FlashOnChange(child any) *FlashC {
+	f := &FlashC{
+		child:    child,
+		style:    Style{Attr: AttrInverse},
+		duration: 600 * time.Millisecond,
+	}
+	if t, ok := child.(TextC); ok && t.content != nil && reflect.TypeOf(t.content).Kind() == reflect.Pointer {
+		f.watch = t.content
+	}
+	return f
+}
+
+// Watch sets the value to watch, as a pointer.
+func (f *FlashC) Watch(ptr any) *FlashC {
+	f.watch = ptr
+	return f
+}
+
+// Style sets the highlight. Inverse by default.
+func (f *FlashC) Style(s Style) *FlashC {
+	f.style = s
+	return f
+}
+
+// UpDown flashes up when a numeric value rises and down when it falls.
+// Numeric strings such as "1,024.50" count as numbers.
+func (f *FlashC) UpDown(up, down Style) *FlashC {
+	f.up, f.down = &up, &down
+	return f
+}
+
+// Duration sets how long a flash takes to fade. 600ms by default.
+func (f *FlashC) Duration(d time.Duration) *FlashC {
+	f.duration = d
+	return f
+}
+
+func (f *FlashC) updateHook() *func()  { return &f.update }
+func (f *FlashC) setClock(c Clock)     { f.clock = c }
+func (f *FlashC) setQuality(q Quality) { f.still = q > QualityFull }
+
+func (f *FlashC) now() time.Time {
+	if f.clock == nil {
+		return time.Now()
+	}
+	return f.clock.Now()
+}
+
+func (t *Template) compileFlashC(f *FlashC, parent int16, depth int) int16 {
+	f.sub = newSubTemplate(f.child, nil, 0)
+	t.pendingBindings = append(t.pendingBindings, f.sub.pendingBindings...)
+	t.adoptLive(f.sub)
+	t.collectUpdateHook(f)
+	t.collectClockUser(f)
+	t.collectQualityUser(f)
+	return t.compileCustom(Widget(f.measure, f.render), parent, depth)
+}
+
+// subProbeWidth stands in for "unbounded" when a wrapper around a
+// sub-template is asked for its natural size.
+const subProbeWidth = 1 << 14
+
+func (f *FlashC) measure(availW int16) (w, h int16) {
+	probe := availW < 0
+	if probe {
+		availW = subProbeWidth
+	}
+	f.sub.distributeWidths(availW, nil)
+	f.sub.layout(0)
+	if len(f.sub.geom) == 0 {
+		return 0, 0
+	}
+	w = f.sub.geom[0].W
+	if probe && w >= subProbeWidth {
+		w = -1 // the child fills
+	}
+	return w, f.sub.Height()
+}
+
+func (f *FlashC) render(buf *Buffer, x, y, w, h int16) {
+	f.sub.clipMaxY = y + h
+	f.sub.render(buf, x, y, w)
+
+	now := f.now()
+	f.observe(now)
+	if !f.flashing {
+		return
+	}
+	p := float64(now.Sub(f.since)) / float64(max(f.duration, 1))
+	if p >= 1 {
+		f.flashing = false
+		return
+	}
+	if f.still {
+		// one frame to clear it rather than one per step of a fade
+		f.schedule(f.since.Add(f.duration).Sub(now))
+		p = 0
+	} else {
+		f.schedule(transitionFrame)
+	}
+	for row := int(y); row < int(y+h); row++ {
+		for col := int(x); col < int(x+w); col++ {
+			if !buf.InBounds(col, row) {
+				continue
+			}
+			c := buf.Get(col, row)
+			c.Style.FG = fadeColor(f.flash.FG, c.Style.FG, p)
+			c.Style.BG = fadeColor(f.flash.BG, c.Style.BG, p)
+			c.Style.Attr |= f.flash.Attr
+			buf.Set(col, row, c)
+		}
+	}
+}
+
+// observe checks the watched value, starting a flash when it has changed.
+func (f *FlashC) observe(now time.Time) {
+	if f.watch == nil {
+		return
+	}
+	cur := reflect.ValueOf(f.watch).Elem().Interface()
+	if !f.started {
+		// the first value seen is where we start, not a change
+		f.started = true
+		f.last = cur
+		return
+	}
+	if reflect.DeepEqual(cur, f.last) {
+		return
+	}
+	f.flash = f.style
+	if f.up != nil {
+		a, okA := flashNumber(f.last)
+		b, okB := flashNumber(cur)
+		if okA && okB && b > a {
+			f.flash = *f.up
+		} else if okA && okB && b < a {
+			f.flash = *f.down
+		}
+	}
+	f.last = cur
+	f.flashing = true
+	f.since = now
+}
+
+// schedule asks for one more frame after d.
+func (f *FlashC) schedule(d time.Duration) {
+	if f.update == nil || !f.pending.CompareAndSwap(false, true) {
+		return
+	}
+	update := f.update
+	clock := f.clock
+	if clock == nil {
+		clock = SystemClock
+	}
+	clock.AfterFunc(d, func() {
+		f.pending.Store(false)
+		update()
+	})
+}
+
+// fadeColor blends a flash colour back to the cell's own at progress p.
+// Only RGB pairs can blend; anything else holds the flash colour.
+func fadeColor(flash, cell Color, p float64) Color {
+	if flash.Mode == ColorDefault {
+		return cell
+	}
+	if flash.Mode == ColorRGB && cell.Mode == ColorRGB {
+		return LerpColor(flash, cell, p)
+	}
+	return flash
+}
+
+// flashNumber reads v as a number, accepting numeric strings.
+func flashNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case string:
+		f, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(n), ",", ""), 64)
+		return f, err == nil
+	case float64, float32, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return toFloat64(n), true
+	}
+	return 0, false
+}
@@ -0,0 +1,79 @@
+package glyph
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFlashOnChange(t *testing.T) {
+	clock := NewFakeClock(time.Time{})
+	price := "101.50"
+	up, down := Style{BG: RGB(0, 200, 0)}, Style{BG: RGB(200, 0, 0)}
+	tmpl := Build(HBox(
+		FlashOnChange(Text(&price)).UpDown(up, down).Duration(100*time.Millisecond),
+		Text("|"),
+	))
+	tmpl.SetClock(clock)
+	frame := func() *Buffer {
+		buf := NewBuffer(10, 1)
+		tmpl.Execute(buf, 10, 1)
+		return buf
+	}
+
+	// the first value isn't a change
+	buf := frame()
+	if got := buf.GetLine(0); got != "101.50|" {
+		t.Fatalf("line = %q", got)
+	}
+	if bg := buf.Get(0, 0).Style.BG; bg.Mode != ColorDefault {
+		t.Errorf("no flash expected, bg = %+v", bg)
+	}
+
+	price = "102.00"
+	buf = frame()
+	if bg := buf.Get(0, 0).Style.BG; bg != up.BG {
+		t.Errorf("rise bg = %+v, want green", bg)
+	}
+	if bg := buf.Get(6, 0).Style.BG; bg.Mode != ColorDefault {
+		t.Error("flash should stay within the child")
+	}
+
+	// held (no RGB beneath to fade to) until the duration is up
+	clock.Advance(50 * time.Millisecond)
+	if bg := frame().Get(0, 0).Style.BG; bg != up.BG {
+		t.Errorf("mid flash bg = %+v", bg)
+	}
+	clock.Advance(60 * time.Millisecond)
+	if bg := frame().Get(0, 0).Style.BG; bg.Mode != ColorDefault {
+		t.Errorf("after flash bg = %+v", bg)
+	}
+
+	price = "99.00"
+	if bg := frame().Get(0, 0).Style.BG; bg != down.BG {
+		t.Errorf("fall bg = %+v, want red", bg)
+	}
+}
+
+func TestFlashFade(t *testing.T) {
+	clock := NewFakeClock(time.Time{})
+	n := 1
+	tmpl := Build(VBox(
+		FlashOnChange(Text("cell").BG(RGB(0, 0, 0))).Watch(&n).
+			Style(Style{BG: RGB(200, 200, 200)}).Duration(100 * time.Millisecond),
+	))
+	tmpl.SetClock(clock)
+	frame := func() Color {
+		buf := NewBuffer(4, 1)
+		tmpl.Execute(buf, 4, 1)
+		return buf.Get(0, 0).Style.BG
+	}
+	frame()
+	n = 2
+	if bg := frame(); bg != RGB(200, 200, 200) {
+		t.Errorf("start bg = %+v", bg)
+	}
+	clock.Advance(50 * time.Millisecond)
+	if bg := frame(); bg != RGB(100, 100, 100) {
+		t.Errorf("halfway bg = %+v", bg)
+	}
+}
@@ -23,7 +23,7 @@ type focusable interface {
 type FocusManager struct {
 	items    []*focusItem
 	current  int
-	handlers []*riffkey.TextHandler
+	handlers []func(riffkey.Key) bool
 
 	nextKey  string
 	prevKey  string
@@ -66,9 +66,7 @@ func (fm *FocusManager) Register(f focusable) *FocusManager {
 
 	// create handler for this item
 	if tib != nil {
-		h := riffkey.NewTextHandler(tib.value, tib.cursor)
-		h.OnChange = tib.onChange
-		fm.handlers = append(fm.handlers, h)
+		fm.handlers = append(fm.handlers, tib.handler())
 	} else {
 		fm.handlers = append(fm.handlers, nil)
 	}
@@ -140,6 +138,17 @@ func (fm *FocusManager) moveFocus(delta int) {
 		return
 	}
 
+	next := fm.current
+	for range len(fm.items) - 1 {
+		next = (next + len(fm.items) + delta) % len(fm.items)
+		if !fm.disabled(next) {
+			break
+		}
+	}
+	if next == fm.current || fm.disabled(next) {
+		return
+	}
+
 	// pop current sub-router
 	if fm.pushed && fm.pop != nil {
 		fm.pop()
@@ -147,7 +156,7 @@ func (fm *FocusManager) moveFocus(delta int) {
 	}
 
 	fm.items[fm.current].focusable.setFocused(false)
-	fm.current = (fm.current + len(fm.items) + delta) % len(fm.items)
+	fm.current = next
 	fm.items[fm.current].focusable.setFocused(true)
 
 	// push new sub-router
@@ -163,7 +172,7 @@ func (fm *FocusManager) Focus(index int) {
 	if index < 0 || index >= len(fm.items) {
 		return
 	}
-	if fm.current == index {
+	if fm.current == index || fm.disabled(index) {
 		return
 	}
 
@@ -183,6 +192,12 @@ func (fm *FocusManager) Focus(index int) {
 	}
 }
 
+// disabled reports whether item i is disabled, so focus passes it by.
+func (fm *FocusManager) disabled(i int) bool {
+	d, ok := fm.items[i].focusable.(disabler)
+	return ok && d.isDisabled()
+}
+
 // pushCurrent pushes the sub-router for the currently focused item.
 func (fm *FocusManager) pushCurrent() {
 	if fm.push != nil && fm.current < len(fm.routers) && fm.routers[fm.current] != nil {
@@ -211,7 +226,7 @@ func (fm *FocusManager) HandleKey(k riffkey.Key) bool {
 	if h == nil {
 		return false
 	}
-	return h.HandleKey(k)
+	return h(k)
 }
 
 // bindings returns the focus cycling key bindings.
@@ -225,3 +240,19 @@ func (fm *FocusManager) bindings() []binding {
 	}
 	return binds
 }
+
+// handler returns the key handler that edits the bound text.
+func (tib *textInputBinding) handler() func(riffkey.Key) bool {
+	th := riffkey.NewTextHandler(tib.value, tib.cursor)
+	th.OnChange = tib.onChange
+	withDigraphs := digraphKeys(th.HandleKey)
+	return func(k riffkey.Key) bool {
+		if tib.locked != nil && tib.locked() {
+			return false
+		}
+		if tib.digraphs {
+			return withDigraphs(k)
+		}
+		return th.HandleKey(k)
+	}
+}
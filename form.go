@@ -262,15 +262,12 @@ func (f *FormC) toTemplate() any {
 		indicator := If(&ff.focused).
 			Then(Text("▸").Width(1)).
 			Else(Text("").Width(1))
-		rows = append(rows, HBox(indicator, label, ff.control))
-
-		// add error display if the control supports validation
-		if _, ok := ff.control.(validatable); ok {
-			spacer := Text("").Width(f.labelWidth+2).MarginTRBL(0, 1, 0, 0)
-			rows = append(rows, If(&ff.err).Then(
-				HBox(spacer, Text(&ff.err).FG(Red)),
-			))
+		control := ff.control
+		if _, ok := control.(validatable); ok {
+			// errors show beside and below the control
+			control = Validated(control).Error(&ff.err)
 		}
+		rows = append(rows, HBox(indicator, label, control))
 	}
 
 	box := VBox.Gap(f.gap)
@@ -0,0 +1,206 @@
+// Package format turns numbers and durations into short display strings for
+// dashboards: SI and IEC suffixes, grouped digits, fixed widths, percentages
+// and humanized durations.
+//
+//	format.SI(1234567, 1)           // "1.2M"
+//	format.IEC(3.4*(1<<30), 1)      // "3.4Gi"
+//	format.Duration(95*time.Second) // "1m35s"
+//
+// Values that change can be kept in a Field, whose Text binds straight to a
+// glyph Text node:
+//
+//	vol := format.NewField(func(v float64) string { return format.SI(v, 1) })
+//	Text(&vol.Text)
+//	vol.Set(float64(volume))
+package format
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+)
+
+var (
+	siSuffixes  = []string{"", "k", "M", "G", "T", "P", "E"}
+	iecSuffixes = []string{"", "Ki", "Mi", "Gi", "Ti", "Pi", "Ei"}
+)
+
+// SI scales v by powers of 1000 and appends the SI suffix: 1234 is "1.2k"
+// at one decimal. Values under 1000 print without decimals when whole.
+func SI(v float64, decimals int) string {
+	return scaled(v, 1000, decimals, siSuffixes)
+}
+
+// IEC scales v by powers of 1024 and appends the IEC suffix: 3<<30 is
+// "3.0Gi" at one decimal. Use it for byte counts.
+func IEC(v float64, decimals int) string {
+	return scaled(v, 1024, decimals, iecSuffixes)
+}
+
+func scaled(v, base float64, decimals int, suffixes []string) string {
+	neg := v < 0
+	v = math.Abs(v)
+	exp := 0
+	for v >= base && exp < len(suffixes)-1 {
+		v /= base
+		exp++
+	}
+	// rounding can carry into the next unit: 999.96k at one decimal is 1.0M
+	if exp < len(suffixes)-1 && round(v, decimals) >= base {
+		v /= base
+		exp++
+	}
+	var s string
+	if exp == 0 && v == math.Trunc(v) {
+		s = strconv.FormatFloat(v, 'f', 0, 64)
+	} else {
+		s = strconv.FormatFloat(v, 'f', decimals, 64)
+	}
+	if neg {
+		s = "-" + s
+	}
+	return s + suffixes[exp]
+}
+
+func round(v float64, decimals int) float64 {
+	p := math.Pow(10, float64(decimals))
+	return math.Round(v*p) / p
+}
+
+// Locale sets the separators used for grouped numbers.
+type Locale struct {
+	Group   string // between groups of three digits
+	Decimal string // before the fraction
+}
+
+// Common locales.
+var (
+	English  = Locale{Group: ",", Decimal: "."}
+	European = Locale{Group: ".", Decimal: ","}
+	Swiss    = Locale{Group: "'", Decimal: "."}
+)
+
+// Number formats v with digit grouping: 1234567.8 at two decimals is
+// "1,234,567.80" in English. Infinities and NaN are not grouped.
+func (l Locale) Number(v float64, decimals int) string {
+	s := strconv.FormatFloat(v, 'f', decimals, 64)
+	if math.IsInf(v, 0) || math.IsNaN(v) {
+		return s
+	}
+	neg := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+	integer, fraction, hasFraction := strings.Cut(s, ".")
+
+	var b strings.Builder
+	if neg {
+		b.WriteByte('-')
+	}
+	lead := len(integer) % 3
+	if lead == 0 {
+		lead = 3
+	}
+	b.WriteString(integer[:lead])
+	for i := lead; i < len(integer); i += 3 {
+		b.WriteString(l.Group)
+		b.WriteString(integer[i : i+3])
+	}
+	if hasFraction {
+		b.WriteString(l.Decimal)
+		b.WriteString(fraction)
+	}
+	return b.String()
+}
+
+// Number formats v with English digit grouping.
+func Number(v float64, decimals int) string { return English.Number(v, decimals) }
+
+// Fixed formats v to decimals places, right-aligned in width columns so a
+// column of values lines up. Wider values are returned as they are.
+func Fixed(v float64, width, decimals int) string {
+	return Pad(strconv.FormatFloat(v, 'f', decimals, 64), width)
+}
+
+// Pad right-aligns s in width columns, measured in display cells.
+func Pad(s string, width int) string {
+	if n := width - runewidth.StringWidth(s); n > 0 {
+		return strings.Repeat(" ", n) + s
+	}
+	return s
+}
+
+// Percent formats a ratio as a percentage: 0.1234 at one decimal is "12.3%".
+func Percent(ratio float64, decimals int) string {
+	return strconv.FormatFloat(ratio*100, 'f', decimals, 64) + "%"
+}
+
+// SignedPercent is Percent with a leading + on positive values, for changes.
+func SignedPercent(ratio float64, decimals int) string {
+	s := Percent(ratio, decimals)
+	if ratio > 0 {
+		return "+" + s
+	}
+	return s
+}
+
+// Duration humanizes d using its two largest units: "2d4h", "1h5m",
+// "1m35s", "4.2s", "350ms". Zero is "0s".
+func Duration(d time.Duration) string {
+	neg := d < 0
+	if neg {
+		d = -d
+	}
+	var s string
+	switch {
+	case d == 0:
+		s = "0s"
+	case d < time.Millisecond:
+		s = d.String()
+	case d < time.Second:
+		s = strconv.FormatInt(d.Milliseconds(), 10) + "ms"
+	case d < time.Minute:
+		s = strconv.FormatFloat(math.Floor(d.Seconds()*10)/10, 'f', -1, 64) + "s"
+	case d < time.Hour:
+		s = pair(int64(d/time.Minute), "m", int64(d%time.Minute/time.Second), "s")
+	case d < 24*time.Hour:
+		s = pair(int64(d/time.Hour), "h", int64(d%time.Hour/time.Minute), "m")
+	default:
+		s = pair(int64(d/(24*time.Hour)), "d", int64(d%(24*time.Hour)/time.Hour), "h")
+	}
+	if neg {
+		return "-" + s
+	}
+	return s
+}
+
+func pair(major int64, majorUnit string, minor int64, minorUnit string) string {
+	s := strconv.FormatInt(major, 10) + majorUnit
+	if minor > 0 {
+		s += strconv.FormatInt(minor, 10) + minorUnit
+	}
+	return s
+}
+
+// Field is a number kept alongside its formatted text. Bind Text to the
+// text and update the number with Set.
+type Field struct {
+	Text  string
+	value float64
+	fn    func(float64) string
+}
+
+// NewField creates a field formatting its value with fn, starting at zero.
+func NewField(fn func(float64) string) *Field {
+	return &Field{Text: fn(0), fn: fn}
+}
+
+// Set updates the value and its text.
+func (f *Field) Set(v float64) {
+	f.value = v
+	f.Text = f.fn(v)
+}
+
+// Value returns the current value.
+func (f *Field) Value() float64 { return f.value }
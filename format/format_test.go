@@ -0,0 +1,122 @@
+package format
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSI(t *testing.T) {
+	tests := []struct {
+		v    float64
+		dec  int
+		want string
+	}{
+		{0, 1, "0"},
+		{999, 1, "999"},
+		{12.5, 1, "12.5"},
+		{1234, 1, "1.2k"},
+		{1234567, 2, "1.23M"},
+		{999960, 1, "1.0M"},
+		{-4600, 0, "-5k"},
+		{3e18, 1, "3.0E"},
+	}
+	for _, tt := range tests {
+		if got := SI(tt.v, tt.dec); got != tt.want {
+			t.Errorf("SI(%v, %d) = %q, want %q", tt.v, tt.dec, got, tt.want)
+		}
+	}
+}
+
+func TestIEC(t *testing.T) {
+	tests := []struct {
+		v    float64
+		want string
+	}{
+		{512, "512"},
+		{1536, "1.5Ki"},
+		{3.4 * (1 << 30), "3.4Gi"},
+	}
+	for _, tt := range tests {
+		if got := IEC(tt.v, 1); got != tt.want {
+			t.Errorf("IEC(%v) = %q, want %q", tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestNumber(t *testing.T) {
+	tests := []struct {
+		l    Locale
+		v    float64
+		dec  int
+		want string
+	}{
+		{English, 1234567.8, 2, "1,234,567.80"},
+		{English, 999, 0, "999"},
+		{English, -1234, 0, "-1,234"},
+		{European, 1234567.891, 2, "1.234.567,89"},
+		{Swiss, 100000, 0, "100'000"},
+		{English, math.Inf(1), 0, "+Inf"},
+		{English, math.Inf(-1), 2, "-Inf"},
+		{English, math.NaN(), 1, "NaN"},
+	}
+	for _, tt := range tests {
+		if got := tt.l.Number(tt.v, tt.dec); got != tt.want {
+			t.Errorf("%+v.Number(%v) = %q, want %q", tt.l, tt.v, got, tt.want)
+		}
+	}
+}
+
+func TestFixedAndPercent(t *testing.T) {
+	if got := Fixed(3.14159, 8, 2); got != "    3.14" {
+		t.Errorf("Fixed = %q", got)
+	}
+	if got := Fixed(123456.7, 4, 1); got != "123456.7" {
+		t.Errorf("Fixed overflow = %q", got)
+	}
+	if got := Pad("日本", 5); got != " 日本" {
+		t.Errorf("Pad wide = %q", got)
+	}
+	if got := Percent(0.1234, 1); got != "12.3%" {
+		t.Errorf("Percent = %q", got)
+	}
+	if got := SignedPercent(0.05, 0); got != "+5%" {
+		t.Errorf("SignedPercent = %q", got)
+	}
+	if got := SignedPercent(-0.05, 0); got != "-5%" {
+		t.Errorf("SignedPercent negative = %q", got)
+	}
+}
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{500 * time.Microsecond, "500µs"},
+		{350 * time.Millisecond, "350ms"},
+		{4250 * time.Millisecond, "4.2s"},
+		{95 * time.Second, "1m35s"},
+		{time.Hour + 5*time.Minute + 9*time.Second, "1h5m"},
+		{2 * time.Hour, "2h"},
+		{52 * time.Hour, "2d4h"},
+		{-90 * time.Second, "-1m30s"},
+	}
+	for _, tt := range tests {
+		if got := Duration(tt.d); got != tt.want {
+			t.Errorf("Duration(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestField(t *testing.T) {
+	f := NewField(func(v float64) string { return SI(v, 1) })
+	if f.Text != "0" {
+		t.Errorf("initial text = %q", f.Text)
+	}
+	f.Set(2500)
+	if f.Text != "2.5k" || f.Value() != 2500 {
+		t.Errorf("after Set text = %q value = %v", f.Text, f.Value())
+	}
+}
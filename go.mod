@@ -6,6 +6,7 @@ require (
 	github.com/junegunn/fzf v0.67.0
 	github.com/kungfusheep/riffkey v0.0.0-20260216102013-df19649e3a0d
 	github.com/mattn/go-runewidth v0.0.19
+	github.com/yuin/gopher-lua v1.1.1
 	golang.org/x/sys v0.40.0
 )
 
@@ -0,0 +1,102 @@
+package glyph
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image/png"
+	"math"
+	"strings"
+	"sync/atomic"
+)
+
+// Images are shown at full resolution with the kitty graphics protocol,
+// on terminals that have it (see Capabilities.KittyGraphics). The image is
+// sent once, as PNG, and placed over the cells of the pane showing it;
+// the cells beneath are left blank.
+
+// kittyChunk is the most base64 the protocol takes in one escape.
+const kittyChunk = 4096
+
+var lastImageID atomic.Uint32
+
+// placedImage is an image shown with the kitty graphics protocol.
+type placedImage struct {
+	id         uint32
+	data       string // base64 PNG
+	cols, rows int
+	x, y       int // where it is placed, -1 before it has been
+	sent       bool
+}
+
+// newPlacedImage prepares a PNG to show in w by h cells, for cells aspect
+// times as tall as they are wide.
+func newPlacedImage(data []byte, w, h int, aspect float64) *placedImage {
+	cols, rows := w, h
+	if cfg, err := png.DecodeConfig(bytes.NewReader(data)); err == nil && cfg.Width > 0 && cfg.Height > 0 {
+		cols, rows = fitCells(cfg.Width, cfg.Height, w, h, aspect)
+	}
+	return &placedImage{
+		id:   lastImageID.Add(1),
+		data: base64.StdEncoding.EncodeToString(data),
+		cols: cols,
+		rows: rows,
+		x:    -1,
+		y:    -1,
+	}
+}
+
+// fitCells returns the most cells within w by h that an iw by ih image
+// fills without changing shape.
+func fitCells(iw, ih, w, h int, aspect float64) (cols, rows int) {
+	ratio := float64(ih) / float64(iw) / aspect // rows per column
+	cols, rows = w, int(math.Round(float64(w)*ratio))
+	if rows > h {
+		cols, rows = int(math.Round(float64(h)/ratio)), h
+	}
+	return max(min(cols, w), 1), max(rows, 1)
+}
+
+// place shows the image with its top left at x, y, sending it the first
+// time. It does nothing if the image is already there.
+func (im *placedImage) place(s *Screen, x, y int) {
+	if im.sent && im.x == x && im.y == y {
+		return
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "\x1b7\x1b[%d;%dH", y+1, x+1)
+	if im.sent {
+		fmt.Fprintf(&b, "\x1b_Ga=p,i=%d,p=1,c=%d,r=%d,C=1,q=2\x1b\\", im.id, im.cols, im.rows)
+	} else {
+		for i := 0; i < len(im.data); i += kittyChunk {
+			more := 0
+			if i+kittyChunk < len(im.data) {
+				more = 1
+			}
+			if i == 0 {
+				fmt.Fprintf(&b, "\x1b_Ga=T,f=100,i=%d,p=1,c=%d,r=%d,C=1,q=2,m=%d;", im.id, im.cols, im.rows, more)
+			} else {
+				fmt.Fprintf(&b, "\x1b_Gm=%d;", more)
+			}
+			b.WriteString(im.data[i:min(i+kittyChunk, len(im.data))])
+			b.WriteString("\x1b\\")
+		}
+	}
+	b.WriteString("\x1b8")
+	s.queueRaw(b.String())
+	im.x, im.y, im.sent = x, y, true
+}
+
+// remove deletes the image from the terminal.
+func (im *placedImage) remove(s *Screen) {
+	if im.sent {
+		s.queueRaw(fmt.Sprintf("\x1b_Ga=d,d=I,i=%d,q=2\x1b\\", im.id))
+	}
+}
+
+// queueRaw queues a sequence to send after the next frame.
+func (s *Screen) queueRaw(seq string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending = append(s.pending, seq...)
+}
@@ -0,0 +1,60 @@
+package glyph
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestFitCells(t *testing.T) {
+	for _, tc := range []struct {
+		iw, ih, w, h int
+		aspect       float64
+		cols, rows   int
+	}{
+		{100, 100, 40, 40, 2, 40, 20}, // square image in tall cells
+		{100, 100, 40, 10, 2, 20, 10}, // limited by height
+		{200, 100, 40, 40, 1, 40, 20}, // wide image in square cells
+		{10, 1000, 40, 10, 2, 1, 10},  // never narrower than a cell
+	} {
+		cols, rows := fitCells(tc.iw, tc.ih, tc.w, tc.h, tc.aspect)
+		if cols != tc.cols || rows != tc.rows {
+			t.Errorf("fitCells(%d, %d, %d, %d, %v) = %d, %d, want %d, %d",
+				tc.iw, tc.ih, tc.w, tc.h, tc.aspect, cols, rows, tc.cols, tc.rows)
+		}
+	}
+}
+
+func TestPlacedImage(t *testing.T) {
+	var data bytes.Buffer
+	png.Encode(&data, image.NewGray(image.Rect(0, 0, 8, 8)))
+	im := newPlacedImage(data.Bytes(), 20, 10, 2)
+
+	var out bytes.Buffer
+	s := NewScreenSize(&out, 40, 12)
+	im.place(s, 3, 2)
+	s.Flush()
+	s.FlushBuffer()
+	got := out.String()
+	if !strings.Contains(got, "\x1b7\x1b[3;4H\x1b_Ga=T,f=100,") || !strings.Contains(got, ",c=20,r=10,") || !strings.HasSuffix(got, "\x1b\\\x1b8") {
+		t.Errorf("first placement = %q", got)
+	}
+
+	out.Reset()
+	im.place(s, 3, 2)
+	s.Flush()
+	s.FlushBuffer()
+	if out.Len() != 0 {
+		t.Errorf("placed again where it was: %q", out.String())
+	}
+
+	im.place(s, 5, 2)
+	im.remove(s)
+	s.Flush()
+	s.FlushBuffer()
+	if got := out.String(); !strings.Contains(got, "a=p,") || strings.Contains(got, "f=100") || !strings.Contains(got, "a=d,d=I,") {
+		t.Errorf("move and remove = %q", got)
+	}
+}
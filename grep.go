@@ -0,0 +1,611 @@
+package glyph
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// GrepMatch is a single match from a project search.
+type GrepMatch struct {
+	File string // path relative to the search root
+	Line int    // 1-based line number
+	Col  int    // byte offset of the match start within Text
+	End  int    // byte offset of the match end within Text
+	Text string // the full matching line
+}
+
+// Searcher produces matches for pattern below root, sending each on out.
+// It returns when the search completes or ctx is cancelled.
+type Searcher interface {
+	Search(ctx context.Context, pattern, root string, out chan<- GrepMatch) error
+}
+
+// GoSearcher is a dependency-free Searcher that walks the tree and matches
+// each line with a regular expression. Hidden directories and binary files
+// are skipped.
+type GoSearcher struct {
+	IgnoreCase  bool
+	MaxFileSize int64 // files larger than this are skipped; 0 means 4MB
+}
+
+// Search implements Searcher.
+func (s GoSearcher) Search(ctx context.Context, pattern, root string, out chan<- GrepMatch) error {
+	if s.IgnoreCase {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+	maxSize := s.MaxFileSize
+	if maxSize <= 0 {
+		maxSize = 4 << 20
+	}
+
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // unreadable entries are skipped, not fatal
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if d.IsDir() {
+			if path != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info, err := d.Info(); err != nil || !info.Mode().IsRegular() || info.Size() > maxSize {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil || bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0 {
+			return nil
+		}
+		rel, _ := filepath.Rel(root, path)
+
+		for i, line := range strings.Split(string(data), "\n") {
+			for _, m := range re.FindAllStringIndex(line, -1) {
+				match := GrepMatch{File: rel, Line: i + 1, Col: m[0], End: m[1], Text: strings.TrimSuffix(line, "\r")}
+				select {
+				case out <- match:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// RipgrepSearcher runs ripgrep as a subprocess and parses its JSON output.
+type RipgrepSearcher struct {
+	Path string   // binary to run; default "rg"
+	Args []string // extra arguments such as "-i" or "--hidden"
+}
+
+// Search implements Searcher.
+func (s RipgrepSearcher) Search(ctx context.Context, pattern, root string, out chan<- GrepMatch) error {
+	bin := s.Path
+	if bin == "" {
+		bin = "rg"
+	}
+	args := append([]string{"--json"}, s.Args...)
+	args = append(args, "-e", pattern)
+	cmd := exec.CommandContext(ctx, bin, args...)
+	cmd.Dir = root
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		for _, m := range parseRipgrepLine(scanner.Bytes()) {
+			select {
+			case out <- m:
+			case <-ctx.Done():
+				cmd.Wait()
+				return ctx.Err()
+			}
+		}
+	}
+
+	err = cmd.Wait()
+	var exit *exec.ExitError
+	if errors.As(err, &exit) && exit.ExitCode() == 1 {
+		return nil // no matches
+	}
+	if err != nil && ctx.Err() == nil && stderr.Len() > 0 {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return err
+}
+
+// parseRipgrepLine decodes one line of rg --json output into matches.
+func parseRipgrepLine(line []byte) []GrepMatch {
+	var msg struct {
+		Type string `json:"type"`
+		Data struct {
+			Path       struct{ Text string } `json:"path"`
+			Lines      struct{ Text string } `json:"lines"`
+			LineNumber int                   `json:"line_number"`
+			Submatches []struct {
+				Start int `json:"start"`
+				End   int `json:"end"`
+			} `json:"submatches"`
+		} `json:"data"`
+	}
+	if json.Unmarshal(line, &msg) != nil || msg.Type != "match" {
+		return nil
+	}
+	text := strings.TrimRight(msg.Data.Lines.Text, "\r\n")
+	out := make([]GrepMatch, 0, len(msg.Data.Submatches))
+	for _, sm := range msg.Data.Submatches {
+		out = append(out, GrepMatch{
+			File: msg.Data.Path.Text,
+			Line: msg.Data.LineNumber,
+			Col:  min(sm.Start, len(text)),
+			End:  min(sm.End, len(text)),
+			Text: text,
+		})
+	}
+	return out
+}
+
+// GrepRunner runs searches in the background and collects their matches.
+// Starting a new search cancels the previous one.
+//
+//	runner := NewGrepRunner(nil) // GoSearcher
+//	runner.Run(`TODO\(\w+\)`, ".")
+type GrepRunner struct {
+	searcher Searcher
+	onUpdate func()        // called as matches arrive (for RequestRender)
+	sink     *QuickfixList // optional destination for matches
+
+	mu      sync.Mutex
+	root    string
+	matches []GrepMatch
+	running bool
+	err     error
+	gen     int // drops results from superseded runs
+	cancel  context.CancelFunc
+	done    chan struct{}
+}
+
+// NewGrepRunner creates a runner using s, or GoSearcher when s is nil.
+func NewGrepRunner(s Searcher) *GrepRunner {
+	if s == nil {
+		s = GoSearcher{}
+	}
+	return &GrepRunner{searcher: s}
+}
+
+// OnUpdate sets a callback fired as matches arrive and when a search ends.
+// Components using the runner wire this to app.RequestRender automatically.
+func (g *GrepRunner) OnUpdate(fn func()) *GrepRunner {
+	g.onUpdate = fn
+	return g
+}
+
+// Into also sends every match to list, which is cleared when a search starts.
+func (g *GrepRunner) Into(list *QuickfixList) *GrepRunner {
+	g.sink = list
+	return g
+}
+
+// Run starts searching root for pattern, replacing any previous results.
+func (g *GrepRunner) Run(pattern, root string) {
+	g.mu.Lock()
+	if g.cancel != nil {
+		g.cancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	g.gen++
+	gen := g.gen
+	g.matches = nil
+	g.err = nil
+	g.running = true
+	g.root = root
+	g.cancel = cancel
+	done := make(chan struct{})
+	g.done = done
+	g.mu.Unlock()
+	if g.sink != nil {
+		g.sink.Clear()
+	}
+
+	ch := make(chan GrepMatch, 64)
+	var searchErr error
+	go func() {
+		searchErr = g.searcher.Search(ctx, pattern, root, ch)
+		close(ch)
+	}()
+	go func() {
+		defer close(done)
+		for m := range ch {
+			g.mu.Lock()
+			current := g.gen == gen
+			if current {
+				g.matches = append(g.matches, m)
+			}
+			g.mu.Unlock()
+			if current && g.sink != nil {
+				g.sink.Append(m.Entry())
+			}
+			g.notify()
+		}
+		g.mu.Lock()
+		if g.gen == gen {
+			g.running = false
+			if !errors.Is(searchErr, context.Canceled) {
+				g.err = searchErr
+			}
+		}
+		g.mu.Unlock()
+		g.notify()
+	}()
+}
+
+// Cancel stops the current search, keeping the matches found so far.
+func (g *GrepRunner) Cancel() {
+	g.mu.Lock()
+	if g.cancel != nil {
+		g.cancel()
+	}
+	g.mu.Unlock()
+}
+
+// Wait blocks until the current search has finished.
+func (g *GrepRunner) Wait() {
+	g.mu.Lock()
+	done := g.done
+	g.mu.Unlock()
+	if done != nil {
+		<-done
+	}
+}
+
+// Matches returns a copy of the matches found so far.
+func (g *GrepRunner) Matches() []GrepMatch {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	out := make([]GrepMatch, len(g.matches))
+	copy(out, g.matches)
+	return out
+}
+
+// Count returns the number of matches found so far.
+func (g *GrepRunner) Count() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return len(g.matches)
+}
+
+// Match returns match i, or false if it doesn't exist (yet).
+func (g *GrepRunner) Match(i int) (GrepMatch, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if i < 0 || i >= len(g.matches) {
+		return GrepMatch{}, false
+	}
+	return g.matches[i], true
+}
+
+// Root returns the directory of the current search.
+func (g *GrepRunner) Root() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.root
+}
+
+// Running reports whether a search is in progress.
+func (g *GrepRunner) Running() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.running
+}
+
+// Err returns the error from the last completed search, if any.
+func (g *GrepRunner) Err() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.err
+}
+
+func (g *GrepRunner) notify() {
+	if g.onUpdate != nil {
+		g.onUpdate()
+	}
+}
+
+// ============================================================================
+// Grep component
+// ============================================================================
+
+// GrepC shows the matches of a GrepRunner as they stream in, with a preview
+// pane of the selected match in context.
+//
+//	Grep(runner).
+//	    BindNav("<C-n>", "<C-p>").
+//	    BindJump("<Enter>").
+//	    OnJump(func(m GrepMatch) { open(m.File, m.Line, m.Col) })
+type GrepC struct {
+	runner  *GrepRunner
+	list    *Layer
+	preview *Layer
+
+	selected    int
+	top         int
+	showPreview bool
+	onJump      func(GrepMatch)
+
+	fileStyle     Style
+	lineNoStyle   Style
+	matchStyle    Style
+	selectedStyle Style
+
+	grow   float32
+	margin [4]int16
+
+	declaredBindings []binding
+
+	// single-file cache for the preview pane
+	previewPath  string
+	previewLines []string
+}
+
+// Grep creates a results view for runner.
+func Grep(runner *GrepRunner) *GrepC {
+	g := &GrepC{
+		runner:        runner,
+		list:          NewLayer(),
+		preview:       NewLayer(),
+		showPreview:   true,
+		fileStyle:     Style{FG: Magenta},
+		lineNoStyle:   Style{FG: Green},
+		matchStyle:    Style{FG: Red, Attr: AttrBold},
+		selectedStyle: Style{BG: PaletteColor(237)},
+	}
+	g.list.AlwaysRender = true
+	g.list.Render = g.syncList
+	g.preview.AlwaysRender = true
+	g.preview.Render = g.syncPreview
+	return g
+}
+
+// Ref provides access to the component for external references.
+func (g *GrepC) Ref(f func(*GrepC)) *GrepC { f(g); return g }
+
+// Preview shows or hides the preview pane. Shown by default.
+func (g *GrepC) Preview(show bool) *GrepC {
+	g.showPreview = show
+	return g
+}
+
+// OnJump sets the callback for jumping to the selected match.
+func (g *GrepC) OnJump(fn func(GrepMatch)) *GrepC {
+	g.onJump = fn
+	return g
+}
+
+// MatchStyle sets the style of the matched text.
+func (g *GrepC) MatchStyle(s Style) *GrepC {
+	g.matchStyle = s
+	return g
+}
+
+// SelectedStyle sets the style of the selected row.
+func (g *GrepC) SelectedStyle(s Style) *GrepC {
+	g.selectedStyle = s
+	return g
+}
+
+// Grow sets the flex grow factor.
+func (g *GrepC) Grow(f float32) *GrepC {
+	g.grow = f
+	return g
+}
+
+// Margin sets uniform margin on all sides.
+func (g *GrepC) Margin(all int16) *GrepC {
+	g.margin = [4]int16{all, all, all, all}
+	return g
+}
+
+// MarginVH sets vertical and horizontal margin.
+func (g *GrepC) MarginVH(v, h int16) *GrepC {
+	g.margin = [4]int16{v, h, v, h}
+	return g
+}
+
+// MarginTRBL sets individual margins for top, right, bottom, left.
+func (g *GrepC) MarginTRBL(t, r, b, l int16) *GrepC {
+	g.margin = [4]int16{t, r, b, l}
+	return g
+}
+
+// BindNav registers keys that move the selection down and up.
+func (g *GrepC) BindNav(down, up string) *GrepC {
+	g.declaredBindings = append(g.declaredBindings,
+		binding{pattern: down, handler: func() { g.Select(g.selected + 1) }},
+		binding{pattern: up, handler: func() { g.Select(g.selected - 1) }},
+	)
+	return g
+}
+
+// BindJump registers a key that calls the OnJump callback for the selection.
+func (g *GrepC) BindJump(key string) *GrepC {
+	g.declaredBindings = append(g.declaredBindings,
+		binding{pattern: key, handler: g.Jump},
+	)
+	return g
+}
+
+func (g *GrepC) bindings() []binding { return g.declaredBindings }
+
+func (g *GrepC) updateHook() *func() { return &g.runner.onUpdate }
+
+// Select moves the selection to match i, clamped to the results.
+func (g *GrepC) Select(i int) {
+	g.selected = max(min(i, g.runner.Count()-1), 0)
+}
+
+// Selected returns the selected match.
+func (g *GrepC) Selected() (GrepMatch, bool) {
+	return g.runner.Match(g.selected)
+}
+
+// Jump calls the OnJump callback with the selected match.
+func (g *GrepC) Jump() {
+	if m, ok := g.Selected(); ok && g.onJump != nil {
+		g.onJump(m)
+	}
+}
+
+func (g *GrepC) toTemplate() any {
+	list := LayerView(g.list).Grow(1)
+	var root any = list
+	if g.showPreview {
+		root = HBox.Grow(1)(list, VRule(), LayerView(g.preview).Grow(1))
+	}
+	box := VBox
+	if g.grow > 0 {
+		box = box.Grow(g.grow)
+	}
+	if g.margin != [4]int16{} {
+		box = box.MarginTRBL(g.margin[0], g.margin[1], g.margin[2], g.margin[3])
+	}
+	return box(root)
+}
+
+// syncList draws the visible window of results.
+func (g *GrepC) syncList() {
+	w, h := g.list.ViewportWidth(), g.list.ViewportHeight()
+	if w <= 0 || h <= 0 {
+		return
+	}
+	out := NewBuffer(w, h)
+	count := g.runner.Count()
+	g.Select(g.selected)
+
+	switch {
+	case count == 0 && g.runner.Running():
+		out.WriteStringFast(0, 0, "searching...", Style{Attr: AttrDim}, w)
+	case g.runner.Err() != nil:
+		out.WriteStringFast(0, 0, g.runner.Err().Error(), Style{FG: Red}, w)
+	case count == 0:
+		out.WriteStringFast(0, 0, "no matches", Style{Attr: AttrDim}, w)
+	}
+
+	if g.selected < g.top {
+		g.top = g.selected
+	} else if g.selected >= g.top+h {
+		g.top = g.selected - h + 1
+	}
+	for y := 0; y < h; y++ {
+		m, ok := g.runner.Match(g.top + y)
+		if !ok {
+			break
+		}
+		spans := append([]Span{
+			{Text: m.File, Style: g.fileStyle},
+			{Text: ":"},
+			{Text: fmt.Sprint(m.Line), Style: g.lineNoStyle},
+			{Text: ": "},
+		}, g.matchSpans(strings.TrimLeft(m.Text, " \t"), m, len(m.Text)-len(strings.TrimLeft(m.Text, " \t")))...)
+		out.WriteSpans(0, y, spans, w)
+		if g.top+y == g.selected {
+			highlightRow(out, y, w, g.selectedStyle)
+		}
+	}
+	g.list.SetBuffer(out)
+}
+
+// syncPreview draws the file around the selected match.
+func (g *GrepC) syncPreview() {
+	w, h := g.preview.ViewportWidth(), g.preview.ViewportHeight()
+	if w <= 0 || h <= 0 {
+		return
+	}
+	out := NewBuffer(w, h)
+	m, ok := g.Selected()
+	if ok {
+		lines := g.fileLines(m.File)
+		start := max(m.Line-1-h/2, 0)
+		for y := 0; y < h && start+y < len(lines); y++ {
+			n := start + y + 1
+			spans := []Span{{Text: fmt.Sprintf("%4d ", n), Style: Style{Attr: AttrDim}}}
+			if n == m.Line {
+				spans = append(spans, g.matchSpans(m.Text, m, 0)...)
+			} else {
+				spans = append(spans, Span{Text: expandTabs(lines[start+y])})
+			}
+			out.WriteSpans(0, y, spans, w)
+			if n == m.Line {
+				highlightRow(out, y, w, g.selectedStyle)
+			}
+		}
+	}
+	g.preview.SetBuffer(out)
+}
+
+// matchSpans splits text (which starts at byte offset off of m.Text) into
+// before, match and after spans.
+func (g *GrepC) matchSpans(text string, m GrepMatch, off int) []Span {
+	col, end := max(m.Col-off, 0), max(m.End-off, 0)
+	col, end = min(col, len(text)), min(end, len(text))
+	return []Span{
+		{Text: expandTabs(text[:col])},
+		{Text: expandTabs(text[col:end]), Style: g.matchStyle},
+		{Text: expandTabs(text[end:])},
+	}
+}
+
+func (g *GrepC) fileLines(file string) []string {
+	path := filepath.Join(g.runner.Root(), file)
+	if path != g.previewPath {
+		g.previewPath = path
+		data, err := os.ReadFile(path)
+		if err != nil {
+			g.previewLines = nil
+		} else {
+			g.previewLines = strings.Split(string(data), "\n")
+		}
+	}
+	return g.previewLines
+}
+
+func expandTabs(s string) string {
+	return strings.ReplaceAll(s, "\t", "    ")
+}
+
+// highlightRow applies the background and attributes of s across row y.
+func highlightRow(out *Buffer, y, w int, s Style) {
+	for x := 0; x < w; x++ {
+		c := out.Get(x, y)
+		c.Style.BG = s.BG
+		c.Style.Attr |= s.Attr
+		if c.Rune == 0 {
+			c.Rune = ' '
+		}
+		out.SetFast(x, y, c)
+	}
+}
@@ -0,0 +1,101 @@
+package glyph
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeGrepTree(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.go":         "package a\n// TODO: one\nfunc A() {}\n",
+		"sub/b.go":     "package b\n\t// TODO: two\n",
+		".git/config":  "TODO: hidden\n",
+		"bin/blob.dat": "TODO\x00binary",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0o755)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestGrepRunnerGoSearcher(t *testing.T) {
+	dir := writeGrepTree(t)
+	updates := 0
+	r := NewGrepRunner(nil).OnUpdate(func() { updates++ })
+	r.Run(`TODO: (\w+)`, dir)
+	r.Wait()
+
+	if err := r.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if r.Running() {
+		t.Error("expected search to be finished")
+	}
+	matches := r.Matches()
+	if len(matches) != 2 {
+		t.Fatalf("expected 2 matches (hidden and binary skipped), got %+v", matches)
+	}
+	m := matches[1]
+	if m.File != filepath.Join("sub", "b.go") || m.Line != 2 || m.Text[m.Col:m.End] != "TODO: two" {
+		t.Errorf("unexpected match %+v", m)
+	}
+	if updates == 0 {
+		t.Error("expected update callbacks")
+	}
+}
+
+func TestGrepRunnerBadPattern(t *testing.T) {
+	r := NewGrepRunner(GoSearcher{})
+	r.Run("(", t.TempDir())
+	r.Wait()
+	if r.Err() == nil {
+		t.Error("expected compile error")
+	}
+}
+
+func TestParseRipgrepLine(t *testing.T) {
+	line := `{"type":"match","data":{"path":{"text":"x.go"},"lines":{"text":"foo bar foo\n"},"line_number":7,"submatches":[{"match":{"text":"foo"},"start":0,"end":3},{"match":{"text":"foo"},"start":8,"end":11}]}}`
+	got := parseRipgrepLine([]byte(line))
+	if len(got) != 2 {
+		t.Fatalf("expected one match per submatch, got %d", len(got))
+	}
+	if got[1] != (GrepMatch{File: "x.go", Line: 7, Col: 8, End: 11, Text: "foo bar foo"}) {
+		t.Errorf("unexpected match %+v", got[1])
+	}
+	if parseRipgrepLine([]byte(`{"type":"begin"}`)) != nil {
+		t.Error("non-match messages should be ignored")
+	}
+}
+
+func TestGrepComponentRendersAndJumps(t *testing.T) {
+	dir := writeGrepTree(t)
+	r := NewGrepRunner(nil)
+	r.Run("TODO: one", dir)
+	r.Wait()
+
+	var jumped GrepMatch
+	g := Grep(r).OnJump(func(m GrepMatch) { jumped = m })
+	tmpl := Build(VBox(g))
+	buf := NewBuffer(60, 4)
+	tmpl.Execute(buf, 60, 4)
+
+	if got := buf.GetLine(0); !strings.HasPrefix(got, "a.go:2: // TODO: one") {
+		t.Errorf("unexpected result row %q", got)
+	}
+	if !strings.Contains(buf.GetLine(1), "// TODO: one") {
+		t.Errorf("expected preview to show the match line, got %q", buf.GetLine(1))
+	}
+
+	g.Jump()
+	if jumped.File != "a.go" || jumped.Line != 2 {
+		t.Errorf("unexpected jump target %+v", jumped)
+	}
+}
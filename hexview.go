@@ -0,0 +1,458 @@
+package glyph
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+// HexViewC shows binary data as rows of offset, hex bytes and their text,
+// with a byte cursor:
+//
+//	f, _ := os.OpenFile(path, os.O_RDWR, 0)
+//	fi, _ := f.Stat()
+//	HexView(f, fi.Size()).Editable().Bind().Grow(1)
+//
+// Only the rows on show are read, with ReadAt, so files of any size open
+// at once. Edits are kept over the data, not written to it, until Save.
+type HexViewC struct {
+	layer   *Layer
+	r       io.ReaderAt
+	size    int64
+	perRow  int // bytes a row, 0 to fit the width
+	cursor  int64
+	top     int64 // first row shown
+	rows    int   // rows shown at the last draw, for paging
+	editing bool
+	text    bool // the cursor is in the text column
+	nibble  bool // the high half of the cursor's byte has been typed
+
+	edits map[int64]byte
+	undo  []hexEdit
+
+	offsetStyle Style
+	style       Style
+	cursorStyle Style
+	editedStyle Style
+
+	grow   float32
+	height int16
+
+	declaredBindings []binding
+	declaredKeys     func(riffkey.Key) bool
+}
+
+// hexEdit is an edit as undo restores it: the byte's edit before, if any.
+type hexEdit struct {
+	off    int64
+	prev   byte
+	edited bool
+}
+
+// HexView creates a view of the size bytes of r.
+func HexView(r io.ReaderAt, size int64) *HexViewC {
+	h := &HexViewC{
+		layer:       NewLayer(),
+		r:           r,
+		size:        size,
+		edits:       make(map[int64]byte),
+		offsetStyle: Style{Attr: AttrDim},
+		cursorStyle: Style{Attr: AttrInverse},
+		editedStyle: Style{FG: Yellow, Attr: AttrBold},
+	}
+	h.layer.AlwaysRender = true
+	h.layer.Render = h.sync
+	return h
+}
+
+// Ref provides access to the component for external references.
+func (h *HexViewC) Ref(f func(*HexViewC)) *HexViewC { f(h); return h }
+
+// BytesPerRow fixes how many bytes a row shows. By default it's 16, or 8
+// when 16 don't fit.
+func (h *HexViewC) BytesPerRow(n int) *HexViewC {
+	h.perRow = max(n, 1)
+	return h
+}
+
+// Editable lets typing change the bytes: hex digits in the hex column,
+// characters in the text column. Tab moves between the columns.
+func (h *HexViewC) Editable() *HexViewC {
+	h.editing = true
+	return h
+}
+
+// Style sets the style of the bytes.
+func (h *HexViewC) Style(s Style) *HexViewC {
+	h.style = s
+	return h
+}
+
+// CursorStyle sets the style of the byte under the cursor. Inverse by
+// default; the column the cursor isn't in shows it underlined.
+func (h *HexViewC) CursorStyle(s Style) *HexViewC {
+	h.cursorStyle = s
+	return h
+}
+
+// EditedStyle sets the style of bytes edited but not yet saved.
+func (h *HexViewC) EditedStyle(s Style) *HexViewC {
+	h.editedStyle = s
+	return h
+}
+
+// Grow sets the flex grow factor.
+func (h *HexViewC) Grow(g float32) *HexViewC {
+	h.grow = g
+	return h
+}
+
+// Height sets a fixed viewport height.
+func (h *HexViewC) Height(n int16) *HexViewC {
+	h.height = n
+	return h
+}
+
+// Bind routes unmatched keys to the view: arrows, Home and End, page up
+// and down, and when editable, typing, Tab and Backspace.
+func (h *HexViewC) Bind() *HexViewC {
+	h.declaredKeys = h.HandleKey
+	return h
+}
+
+// BindNav registers keys to move the cursor a byte left and right and a
+// row up and down. A count moves that many.
+func (h *HexViewC) BindNav(left, right, up, down string) *HexViewC {
+	h.declaredBindings = append(h.declaredBindings,
+		binding{pattern: left, handler: func(m riffkey.Match) { h.Move(-int64(m.Count)) }},
+		binding{pattern: right, handler: func(m riffkey.Match) { h.Move(int64(m.Count)) }},
+		binding{pattern: up, handler: func(m riffkey.Match) { h.Move(-int64(m.Count * h.rowBytes())) }},
+		binding{pattern: down, handler: func(m riffkey.Match) { h.Move(int64(m.Count * h.rowBytes())) }},
+	)
+	return h
+}
+
+// BindPageNav registers keys to move the cursor a page up and down.
+func (h *HexViewC) BindPageNav(pageUp, pageDown string) *HexViewC {
+	h.declaredBindings = append(h.declaredBindings,
+		binding{pattern: pageUp, handler: func() { h.Move(-h.pageBytes()) }},
+		binding{pattern: pageDown, handler: func() { h.Move(h.pageBytes()) }},
+	)
+	return h
+}
+
+// BindUndo registers a key to undo the last edit.
+func (h *HexViewC) BindUndo(key string) *HexViewC {
+	h.declaredBindings = append(h.declaredBindings, binding{pattern: key, handler: func() { h.Undo() }})
+	return h
+}
+
+func (h *HexViewC) bindings() []binding { return h.declaredBindings }
+
+func (h *HexViewC) keyHandler() func(riffkey.Key) bool { return h.declaredKeys }
+
+// HandleKey applies a key press to the view. Returns true if handled.
+func (h *HexViewC) HandleKey(k riffkey.Key) bool {
+	switch {
+	case k.Special == riffkey.SpecialLeft:
+		h.Move(-1)
+	case k.Special == riffkey.SpecialRight:
+		h.Move(1)
+	case k.Special == riffkey.SpecialUp:
+		h.Move(-int64(h.rowBytes()))
+	case k.Special == riffkey.SpecialDown:
+		h.Move(int64(h.rowBytes()))
+	case k.Special == riffkey.SpecialPageUp:
+		h.Move(-h.pageBytes())
+	case k.Special == riffkey.SpecialPageDown:
+		h.Move(h.pageBytes())
+	case k.Special == riffkey.SpecialHome:
+		h.Goto(h.cursor - h.cursor%int64(h.rowBytes()))
+	case k.Special == riffkey.SpecialEnd:
+		h.Goto(h.cursor - h.cursor%int64(h.rowBytes()) + int64(h.rowBytes()) - 1)
+	case !h.editing:
+		return false
+	case k.Special == riffkey.SpecialTab:
+		h.text = !h.text
+		h.nibble = false
+	case k.Special == riffkey.SpecialBackspace:
+		h.Move(-1)
+	case k.Special == riffkey.SpecialSpace && h.text:
+		return h.typeRune(' ')
+	case k.Rune != 0 && (k.Mod == riffkey.ModNone || k.Mod == riffkey.ModShift):
+		return h.typeRune(k.Rune)
+	default:
+		return false
+	}
+	return true
+}
+
+// typeRune edits the byte under the cursor: a hex digit sets half of it in
+// the hex column, a printable ASCII character all of it in the text column.
+func (h *HexViewC) typeRune(r rune) bool {
+	if h.cursor >= h.size {
+		return false
+	}
+	if h.text {
+		if r < 0x20 || r >= 0x7f {
+			return false
+		}
+		h.Set(h.cursor, byte(r))
+		h.Move(1)
+		return true
+	}
+	d, err := strconv.ParseUint(string(r), 16, 8)
+	if err != nil {
+		return false
+	}
+	b, _ := h.Byte(h.cursor)
+	if !h.nibble {
+		h.Set(h.cursor, b&0x0f|byte(d)<<4)
+		h.nibble = true
+		return true
+	}
+	// the second half finishes the byte the first half began, as one edit
+	h.edits[h.cursor] = b&0xf0 | byte(d)
+	h.Move(1)
+	return true
+}
+
+// Size returns the number of bytes shown.
+func (h *HexViewC) Size() int64 { return h.size }
+
+// Cursor returns the offset of the byte under the cursor.
+func (h *HexViewC) Cursor() int64 { return h.cursor }
+
+// Goto moves the cursor to off, clamped to the data, scrolling it into
+// view.
+func (h *HexViewC) Goto(off int64) {
+	h.cursor = min(max(off, 0), max(h.size-1, 0))
+	h.nibble = false
+}
+
+// GotoText moves the cursor to an offset typed by the user: decimal, hex
+// with a 0x prefix, or either with a + or - to move relative to the
+// cursor.
+func (h *HexViewC) GotoText(s string) error {
+	typed := s
+	s = strings.TrimSpace(s)
+	rel := int64(0)
+	if s != "" && (s[0] == '+' || s[0] == '-') {
+		rel = 1
+		if s[0] == '-' {
+			rel = -1
+		}
+		s = s[1:]
+	}
+	base := 10
+	if hex, ok := strings.CutPrefix(strings.ToLower(s), "0x"); ok {
+		s, base = hex, 16
+	}
+	off, err := strconv.ParseInt(s, base, 64)
+	if err != nil || off < 0 {
+		return fmt.Errorf("hexview: bad offset %q", typed)
+	}
+	if rel != 0 {
+		off = h.cursor + rel*off
+	}
+	h.Goto(off)
+	return nil
+}
+
+// Move moves the cursor delta bytes.
+func (h *HexViewC) Move(delta int64) { h.Goto(h.cursor + delta) }
+
+// Byte returns the byte at off, edited or not.
+func (h *HexViewC) Byte(off int64) (byte, error) {
+	var b [1]byte
+	_, err := h.ReadAt(b[:], off)
+	return b[0], err
+}
+
+// ReadAt reads the data as edited, so a HexViewC can be read as the file
+// it will be saved to.
+func (h *HexViewC) ReadAt(p []byte, off int64) (int, error) {
+	if off >= h.size {
+		return 0, io.EOF
+	}
+	p = p[:min(int64(len(p)), h.size-off)]
+	n, err := h.r.ReadAt(p, off)
+	if err == io.EOF && n == len(p) {
+		err = nil
+	}
+	for i := range p[:n] {
+		if b, ok := h.edits[off+int64(i)]; ok {
+			p[i] = b
+		}
+	}
+	if err == nil && n < len(p) {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
+
+// Set edits the byte at off.
+func (h *HexViewC) Set(off int64, b byte) {
+	if off < 0 || off >= h.size {
+		return
+	}
+	prev, edited := h.edits[off]
+	h.undo = append(h.undo, hexEdit{off: off, prev: prev, edited: edited})
+	h.edits[off] = b
+}
+
+// Undo reverts the last edit and moves the cursor to it. Returns false if
+// there was nothing to undo.
+func (h *HexViewC) Undo() bool {
+	if len(h.undo) == 0 {
+		return false
+	}
+	e := h.undo[len(h.undo)-1]
+	h.undo = h.undo[:len(h.undo)-1]
+	if e.edited {
+		h.edits[e.off] = e.prev
+	} else {
+		delete(h.edits, e.off)
+	}
+	h.Goto(e.off)
+	return true
+}
+
+// Modified reports whether there are edits not yet saved.
+func (h *HexViewC) Modified() bool { return len(h.edits) > 0 }
+
+// Save writes the edits to w, usually the file being viewed, a run of
+// edited bytes at a time, and forgets them and their undo history.
+func (h *HexViewC) Save(w io.WriterAt) error {
+	offs := make([]int64, 0, len(h.edits))
+	for off := range h.edits {
+		offs = append(offs, off)
+	}
+	slices.Sort(offs)
+	for i := 0; i < len(offs); {
+		j := i + 1
+		for j < len(offs) && offs[j] == offs[j-1]+1 {
+			j++
+		}
+		run := make([]byte, j-i)
+		for k := range run {
+			run[k] = h.edits[offs[i+k]]
+		}
+		if _, err := w.WriteAt(run, offs[i]); err != nil {
+			return err
+		}
+		i = j
+	}
+	clear(h.edits)
+	h.undo = nil
+	return nil
+}
+
+func (t *Template) compileHexViewC(h *HexViewC, parent int16, depth int) int16 {
+	t.collectBindings(h)
+	t.collectKeyHandler(h)
+	layerView := LayerView(h.layer).Grow(h.grow)
+	if h.height > 0 {
+		layerView = layerView.ViewHeight(h.height)
+	}
+	return t.compileLayerViewC(layerView, parent, depth)
+}
+
+// rowBytes returns the bytes a row shows at the last width drawn.
+func (h *HexViewC) rowBytes() int {
+	if h.perRow > 0 {
+		return h.perRow
+	}
+	if w := h.layer.ViewportWidth(); w > 0 && w < hexRowWidth(h.offsetDigits(), 16) {
+		return 8
+	}
+	return 16
+}
+
+// pageBytes returns the bytes a page of rows shows.
+func (h *HexViewC) pageBytes() int64 {
+	return int64(max(h.rows-1, 1) * h.rowBytes())
+}
+
+// offsetDigits returns how many hex digits offsets take, 8 at least.
+func (h *HexViewC) offsetDigits() int {
+	return max(8, len(strconv.FormatInt(max(h.size-1, 0), 16)))
+}
+
+// hexRowWidth returns the cells a row of n bytes takes: the offset, then
+// the hex in two groups, then the text between bars.
+func hexRowWidth(digits, n int) int {
+	return digits + 2 + n*3 + 1 + 1 + n + 2
+}
+
+// sync draws the rows around the cursor to fit the viewport.
+func (h *HexViewC) sync() {
+	w, rows := h.layer.ViewportWidth(), h.layer.ViewportHeight()
+	if w <= 0 || rows <= 0 {
+		return
+	}
+	h.rows = rows
+	out := NewBuffer(w, rows)
+	h.draw(out, w, rows)
+	h.layer.SetBuffer(out)
+}
+
+func (h *HexViewC) draw(buf *Buffer, w, rows int) {
+	n := h.rowBytes()
+	cursorRow := h.cursor / int64(n)
+	h.top = min(max(h.top, cursorRow-int64(rows)+1), cursorRow)
+
+	start := h.top * int64(n)
+	data := make([]byte, min(int64(rows*n), max(h.size-start, 0)))
+	read, err := h.ReadAt(data, start)
+	data = data[:read]
+	if err != nil && !errors.Is(err, io.EOF) {
+		buf.WriteStringFast(0, rows-1, err.Error(), Style{FG: Red}, w)
+		rows--
+	}
+
+	digits := h.offsetDigits()
+	hexX := digits + 2
+	textX := hexX + n*3 + 1 + 2
+	under := h.cursorStyle
+	under.Attr = AttrUnderline
+	for y := 0; y < rows && y*n < len(data); y++ {
+		row := data[y*n : min((y+1)*n, len(data))]
+		rowOff := start + int64(y*n)
+		buf.WriteStringFast(0, y, fmt.Sprintf("%0*x", digits, rowOff), h.offsetStyle, w)
+		buf.WriteStringFast(textX-2, y, " |", h.offsetStyle, w-(textX-2))
+		for i, b := range row {
+			off := rowOff + int64(i)
+			hexSt, textSt := h.style, h.style
+			if _, ok := h.edits[off]; ok {
+				hexSt, textSt = h.editedStyle, h.editedStyle
+			}
+			if off == h.cursor {
+				hexSt, textSt = h.cursorStyle, under
+				if h.text {
+					hexSt, textSt = under, h.cursorStyle
+				}
+			}
+			x := hexX + i*3
+			if i >= n/2 {
+				x++
+			}
+			buf.WriteStringFast(x, y, fmt.Sprintf("%02x", b), hexSt, w-x)
+			c := '.'
+			if b >= 0x20 && b < 0x7f {
+				c = rune(b)
+			}
+			if textX+i < w {
+				buf.Set(textX+i, y, Cell{Rune: c, Style: textSt})
+			}
+		}
+		if x := textX + len(row); x < w {
+			buf.Set(x, y, Cell{Rune: '|', Style: h.offsetStyle})
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package glyph
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+// writerAt is a []byte written in place, as a file would be.
+type writerAt []byte
+
+func (w writerAt) WriteAt(p []byte, off int64) (int, error) { return copy(w[off:], p), nil }
+
+func TestHexView(t *testing.T) {
+	data := []byte("Hello, hex view!\x00\x01\x02 more bytes here and more")
+	h := HexView(bytes.NewReader(data), int64(len(data))).Height(2).Editable().Bind()
+	tmpl := Build(VBox(h))
+	frame := func() *Buffer {
+		buf := NewBuffer(80, 2)
+		tmpl.Execute(buf, 80, 2)
+		return buf
+	}
+
+	buf := frame()
+	if got := buf.GetLine(0); !strings.HasPrefix(got, "00000000  48 65 6c 6c 6f 2c 20 68  65 78") || !strings.Contains(got, "|Hello, hex view!|") {
+		t.Fatalf("row 0 = %q", got)
+	}
+	if got := buf.GetLine(1); !strings.Contains(got, "00 01 02") || !strings.Contains(got, "|... more bytes h|") {
+		t.Errorf("row 1 = %q", got)
+	}
+	if buf.Get(10, 0).Style.Attr&AttrInverse == 0 {
+		t.Error("cursor not shown on the first byte")
+	}
+
+	h.Goto(40)
+	buf = frame()
+	if got := buf.GetLine(1); !strings.HasPrefix(got, "00000020") {
+		t.Errorf("cursor's row not scrolled into view:\n%s", buf.String())
+	}
+
+	h.HandleKey(riffkey.Key{Special: riffkey.SpecialHome})
+	if h.Cursor() != 32 {
+		t.Errorf("home moved to %d, want 32", h.Cursor())
+	}
+	h.HandleKey(riffkey.Key{Rune: 'a'})
+	h.HandleKey(riffkey.Key{Rune: 'B'})
+	if b, _ := h.Byte(32); b != 0xab || h.Cursor() != 33 {
+		t.Errorf("typed byte = %#x, cursor %d", b, h.Cursor())
+	}
+	h.HandleKey(riffkey.Key{Special: riffkey.SpecialTab})
+	h.HandleKey(riffkey.Key{Rune: 'Z'})
+	if b, _ := h.Byte(33); b != 'Z' {
+		t.Errorf("typed text = %q", b)
+	}
+	if h.HandleKey(riffkey.Key{Rune: 'é'}) {
+		t.Error("non-ASCII typed into the text column")
+	}
+
+	if !h.Undo() || h.Cursor() != 33 {
+		t.Errorf("undo moved to %d, want 33", h.Cursor())
+	}
+	if b, _ := h.Byte(33); b != data[33] {
+		t.Errorf("undone byte = %q, want %q", b, data[33])
+	}
+	if !h.Modified() {
+		t.Fatal("typed hex byte lost")
+	}
+
+	out := append(writerAt(nil), data...)
+	if err := h.Save(out); err != nil {
+		t.Fatal(err)
+	}
+	if out[32] != 0xab || out[33] != data[33] || h.Modified() || h.Undo() {
+		t.Errorf("save wrote %x and left modified=%v", out[32:34], h.Modified())
+	}
+}
+
+func TestHexViewGotoText(t *testing.T) {
+	h := HexView(bytes.NewReader(make([]byte, 4096)), 4096)
+	for _, tt := range []struct {
+		in   string
+		want int64
+	}{
+		{"100", 100},
+		{"0x100", 256},
+		{"0100", 100},
+		{"+0x10", 116},
+		{"-2", 114},
+		{"99999", 4095},
+	} {
+		if err := h.GotoText(tt.in); err != nil || h.Cursor() != tt.want {
+			t.Errorf("GotoText(%q) = %d, %v; want %d", tt.in, h.Cursor(), err, tt.want)
+		}
+	}
+	if err := h.GotoText("zz"); err == nil {
+		t.Error("bad offset accepted")
+	}
+}
+
+func TestHexViewNarrow(t *testing.T) {
+	data := bytes.Repeat([]byte{0xff}, 20)
+	h := HexView(bytes.NewReader(data), 20).Height(3)
+	tmpl := Build(VBox(h))
+	buf := NewBuffer(50, 3)
+	tmpl.Execute(buf, 50, 3)
+	if got := buf.GetLine(1); !strings.HasPrefix(got, "00000008") {
+		t.Errorf("narrow view not 8 bytes a row:\n%s", buf.String())
+	}
+	h.HandleKey(riffkey.Key{Special: riffkey.SpecialDown})
+	if h.Cursor() != 8 {
+		t.Errorf("down moved to %d, want 8", h.Cursor())
+	}
+	if h.HandleKey(riffkey.Key{Rune: 'a'}) {
+		t.Error("typing handled by a view not editable")
+	}
+}
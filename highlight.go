@@ -0,0 +1,241 @@
+package glyph
+
+import (
+	"path/filepath"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SyntaxStyles are the styles code is highlighted in.
+type SyntaxStyles struct {
+	Keyword Style
+	String  Style
+	Comment Style
+	Number  Style
+}
+
+// DefaultSyntaxStyles highlights code in the terminal's palette.
+var DefaultSyntaxStyles = SyntaxStyles{
+	Keyword: Style{FG: Magenta},
+	String:  Style{FG: Green},
+	Comment: Style{FG: BrightBlack, Attr: AttrItalic},
+	Number:  Style{FG: Yellow},
+}
+
+// syntax is what a highlighter needs to know of a language: a word-level
+// approximation that gets keywords, strings, comments and numbers right
+// for most lines without parsing.
+type syntax struct {
+	keywords     map[string]bool
+	lineComments []string
+	blockStart   string
+	blockEnd     string
+	quotes       string
+}
+
+func words(s string) map[string]bool {
+	m := make(map[string]bool)
+	for _, w := range strings.Fields(s) {
+		m[w] = true
+	}
+	return m
+}
+
+var (
+	syntaxGo = &syntax{
+		keywords: words(`break case chan const continue default defer else fallthrough for func go goto
+			if import interface map package range return select struct switch type var
+			true false nil iota`),
+		lineComments: []string{"//"}, blockStart: "/*", blockEnd: "*/", quotes: "\"'`",
+	}
+	syntaxC = &syntax{
+		keywords: words(`auto break case char const continue default do double else enum extern float for
+			goto if inline int long register return short signed sizeof static struct switch typedef
+			union unsigned void volatile while class namespace public private protected template
+			this new delete virtual override true false null nullptr #include #define #ifdef #ifndef #endif`),
+		lineComments: []string{"//"}, blockStart: "/*", blockEnd: "*/", quotes: "\"'",
+	}
+	syntaxJS = &syntax{
+		keywords: words(`async await break case catch class const continue default delete do else export
+			extends finally for from function if import in instanceof let new of return static super
+			switch this throw try typeof var void while yield true false null undefined
+			interface type enum implements readonly`),
+		lineComments: []string{"//"}, blockStart: "/*", blockEnd: "*/", quotes: "\"'`",
+	}
+	syntaxRust = &syntax{
+		keywords: words(`as async await break const continue crate dyn else enum extern fn for if impl in
+			let loop match mod move mut pub ref return self Self static struct super trait type
+			unsafe use where while true false`),
+		lineComments: []string{"//"}, blockStart: "/*", blockEnd: "*/", quotes: "\"",
+	}
+	syntaxPython = &syntax{
+		keywords: words(`and as assert async await break class continue def del elif else except finally
+			for from global if import in is lambda nonlocal not or pass raise return try while
+			with yield True False None self`),
+		lineComments: []string{"#"}, quotes: "\"'",
+	}
+	syntaxShell = &syntax{
+		keywords: words(`if then else elif fi case esac for while until do done in function return
+			local export readonly set unset shift exit echo`),
+		lineComments: []string{"#"}, quotes: "\"'",
+	}
+	syntaxSQL = &syntax{
+		keywords: words(`select from where insert into values update set delete create table drop alter
+			join left right inner outer on group by order having limit and or not null as
+			SELECT FROM WHERE INSERT INTO VALUES UPDATE SET DELETE CREATE TABLE DROP ALTER
+			JOIN LEFT RIGHT INNER OUTER ON GROUP BY ORDER HAVING LIMIT AND OR NOT NULL AS`),
+		lineComments: []string{"--"}, blockStart: "/*", blockEnd: "*/", quotes: "'\"",
+	}
+	syntaxData = &syntax{
+		keywords:     words(`true false null yes no`),
+		lineComments: []string{"#"}, quotes: "\"'",
+	}
+)
+
+var syntaxByExt = map[string]*syntax{
+	".go":   syntaxGo,
+	".c":    syntaxC,
+	".h":    syntaxC,
+	".cc":   syntaxC,
+	".cpp":  syntaxC,
+	".hpp":  syntaxC,
+	".java": syntaxC,
+	".cs":   syntaxC,
+	".js":   syntaxJS,
+	".jsx":  syntaxJS,
+	".mjs":  syntaxJS,
+	".ts":   syntaxJS,
+	".tsx":  syntaxJS,
+	".rs":   syntaxRust,
+	".py":   syntaxPython,
+	".sh":   syntaxShell,
+	".bash": syntaxShell,
+	".zsh":  syntaxShell,
+	".sql":  syntaxSQL,
+	".json": syntaxData,
+	".yaml": syntaxData,
+	".yml":  syntaxData,
+	".toml": syntaxData,
+}
+
+// syntaxFor returns the syntax of the file at path, or nil for plain text.
+func syntaxFor(path string) *syntax {
+	return syntaxByExt[strings.ToLower(filepath.Ext(path))]
+}
+
+// HighlightCode splits src into lines of spans, highlighted as the
+// language its file name's extension says, or left plain if it isn't one
+// known.
+//
+//	for y, line := range HighlightCode("main.go", src, DefaultSyntaxStyles) {
+//	    buf.WriteSpans(0, y, line, width)
+//	}
+func HighlightCode(filename, src string, styles SyntaxStyles) [][]Span {
+	syn := syntaxFor(filename)
+	lines := strings.Split(strings.TrimSuffix(src, "\n"), "\n")
+	out := make([][]Span, len(lines))
+	inBlock := false
+	for i, line := range lines {
+		line = expandTabs(line)
+		if syn == nil {
+			out[i] = []Span{{Text: line}}
+			continue
+		}
+		out[i] = syn.highlight(line, &inBlock, styles)
+	}
+	return out
+}
+
+// highlight splits a line into spans. inBlock carries an open block
+// comment from one line to the next.
+func (syn *syntax) highlight(line string, inBlock *bool, st SyntaxStyles) []Span {
+	var spans []Span
+	plain := 0 // start of the text not yet in a span
+	emit := func(start, end int, s Style) {
+		if start > plain {
+			spans = append(spans, Span{Text: line[plain:start]})
+		}
+		spans = append(spans, Span{Text: line[start:end], Style: s})
+		plain = end
+	}
+	i := 0
+	if *inBlock {
+		end := strings.Index(line, syn.blockEnd)
+		if end < 0 {
+			return []Span{{Text: line, Style: st.Comment}}
+		}
+		*inBlock = false
+		i = end + len(syn.blockEnd)
+		emit(0, i, st.Comment)
+	}
+	for i < len(line) {
+		rest := line[i:]
+		if syn.lineComment(rest) {
+			emit(i, len(line), st.Comment)
+			break
+		}
+		if syn.blockStart != "" && strings.HasPrefix(rest, syn.blockStart) {
+			end := strings.Index(rest[len(syn.blockStart):], syn.blockEnd)
+			if end < 0 {
+				*inBlock = true
+				emit(i, len(line), st.Comment)
+				break
+			}
+			n := len(syn.blockStart) + end + len(syn.blockEnd)
+			emit(i, i+n, st.Comment)
+			i += n
+			continue
+		}
+		c := line[i]
+		switch {
+		case strings.IndexByte(syn.quotes, c) >= 0:
+			end := i + 1
+			for end < len(line) && line[end] != c {
+				if line[end] == '\\' && c != '`' {
+					end++
+				}
+				end++
+			}
+			end = min(end+1, len(line))
+			emit(i, end, st.String)
+			i = end
+		case c >= '0' && c <= '9':
+			end := i
+			for end < len(line) && (isWordByte(line[end]) || line[end] == '.') {
+				end++
+			}
+			emit(i, end, st.Number)
+			i = end
+		case isWordByte(c) || c == '#':
+			end := i + 1
+			for end < len(line) && isWordByte(line[end]) {
+				end++
+			}
+			if syn.keywords[line[i:end]] {
+				emit(i, end, st.Keyword)
+			}
+			i = end
+		default:
+			_, size := utf8.DecodeRuneInString(rest)
+			i += size
+		}
+	}
+	if plain < len(line) {
+		spans = append(spans, Span{Text: line[plain:]})
+	}
+	return spans
+}
+
+func (syn *syntax) lineComment(s string) bool {
+	for _, p := range syn.lineComments {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c < utf8.RuneSelf && (unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c)))
+}
@@ -0,0 +1,40 @@
+package glyph
+
+import "testing"
+
+func TestHighlightCode(t *testing.T) {
+	lines := HighlightCode("x.go", "func f() string { return \"a // b\" } // done\n/* open\nstill */ x := 42", DefaultSyntaxStyles)
+	if len(lines) != 3 {
+		t.Fatalf("%d lines", len(lines))
+	}
+	styled := func(line []Span, text string) Style {
+		for _, s := range line {
+			if s.Text == text {
+				return s.Style
+			}
+		}
+		t.Errorf("no span %q in %+v", text, line)
+		return Style{}
+	}
+	st := DefaultSyntaxStyles
+	if styled(lines[0], "func") != st.Keyword || styled(lines[0], "return") != st.Keyword {
+		t.Error("keywords not highlighted")
+	}
+	if styled(lines[0], `"a // b"`) != st.String {
+		t.Error("string not highlighted, or a comment found inside it")
+	}
+	if styled(lines[0], "// done") != st.Comment {
+		t.Error("line comment not highlighted")
+	}
+	if styled(lines[1], "/* open") != st.Comment || styled(lines[2], "still */") != st.Comment {
+		t.Error("block comment not carried across lines")
+	}
+	if styled(lines[2], "42") != st.Number {
+		t.Error("number not highlighted")
+	}
+
+	plain := HighlightCode("notes.txt", "func", st)
+	if len(plain[0]) != 1 || plain[0][0].Style != (Style{}) {
+		t.Errorf("unknown language highlighted: %+v", plain)
+	}
+}
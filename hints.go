@@ -0,0 +1,92 @@
+package glyph
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// KeyHint is how an interactive component shows the key bound to it, so a
+// UI can be learned by looking at it:
+//
+//	Jump(Text("Save"), save).Key("s").Hint(HintUnderline)   // Save, S underlined
+//	Jump(Text("Quit"), quit).Key("<C-q>").Hint(HintSuffix)  // Quit [Ctrl+Q]
+//	Tabs(names, &tab).Keys("1", "2", "3").Hint(HintSuffix)  // Files [1]  Search [2]  ...
+//
+// Hints show the keys given to the component, the ones it binds.
+type KeyHint uint8
+
+const (
+	HintNone      KeyHint = iota // no hint
+	HintUnderline                // underline the key's letter in the label, if it's there
+	HintSuffix                   // follow the label with the key, as " [Ctrl+Q]"
+)
+
+// accelerator returns the letter a key underlines in a label: the key
+// itself for a single character, or the letter of an Alt chord such as
+// <A-f>. Other keys, which no letter of a label stands for, have none.
+func accelerator(pattern string) rune {
+	if r, size := utf8.DecodeRuneInString(pattern); size == len(pattern) && r != utf8.RuneError && unicode.IsPrint(r) && r != ' ' {
+		return r
+	}
+	lower := strings.ToLower(pattern)
+	for _, prefix := range []string{"<a-", "<m-"} {
+		if rest, ok := strings.CutPrefix(lower, prefix); ok {
+			r, size := utf8.DecodeRuneInString(rest)
+			if size > 0 && rest[size:] == ">" && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+				return r
+			}
+		}
+	}
+	return 0
+}
+
+// accelIndex returns the rune index of the first letter in label matching
+// accel, ignoring case, or -1.
+func accelIndex(label string, accel rune) int {
+	if accel == 0 {
+		return -1
+	}
+	accel = unicode.ToLower(accel)
+	i := 0
+	for _, r := range label {
+		if unicode.ToLower(r) == accel {
+			return i
+		}
+		i++
+	}
+	return -1
+}
+
+// hintSuffix is a key as HintSuffix shows it after a label.
+func hintSuffix(pattern string) string {
+	return " [" + keyLabel(pattern) + "]"
+}
+
+// underlineAccel underlines the first cell of a row, from x for width
+// cells, that shows accel.
+func underlineAccel(buf *Buffer, x, y, width int, accel rune) {
+	if accel == 0 {
+		return
+	}
+	accel = unicode.ToLower(accel)
+	for col := max(x, 0); col < min(x+width, buf.Width()); col++ {
+		c := buf.Get(col, y)
+		if unicode.ToLower(c.Rune) == accel {
+			c.Style.Attr = c.Style.Attr.With(AttrUnderline)
+			buf.Set(col, y, c)
+			return
+		}
+	}
+}
+
+// underlineCell underlines the cell at i along a label drawn from x, y.
+// i is -1 for no cell.
+func underlineCell(buf *Buffer, x, y, i int) {
+	if i < 0 || !buf.InBounds(x+i, y) {
+		return
+	}
+	c := buf.Get(x+i, y)
+	c.Style.Attr = c.Style.Attr.With(AttrUnderline)
+	buf.Set(x+i, y, c)
+}
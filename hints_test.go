@@ -0,0 +1,101 @@
+package glyph
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAccelerator(t *testing.T) {
+	tests := []struct {
+		pattern string
+		want    rune
+	}{
+		{"s", 's'},
+		{"1", '1'},
+		{"<A-f>", 'f'},
+		{"<M-F>", 'f'},
+		{"<C-s>", 0},
+		{"<F5>", 0},
+		{"gg", 0},
+		{" ", 0},
+	}
+	for _, tt := range tests {
+		if got := accelerator(tt.pattern); got != tt.want {
+			t.Errorf("accelerator(%q) = %q, want %q", tt.pattern, got, tt.want)
+		}
+	}
+}
+
+func TestJumpKeyHint(t *testing.T) {
+	buf := NewBuffer(30, 2)
+	tmpl := Build(VBox(
+		Jump(Text("Save"), func() {}).Key("a").Hint(HintUnderline),
+		Jump(Text("Quit"), func() {}).Key("<C-q>").Hint(HintSuffix),
+	))
+	tmpl.Execute(buf, 30, 2)
+
+	for x, r := range "Save" {
+		underlined := buf.Get(x, 0).Style.Attr.Has(AttrUnderline)
+		if underlined != (r == 'a') {
+			t.Errorf("%q underlined = %v", r, underlined)
+		}
+	}
+	if got := strings.TrimSpace(buf.GetLine(1)); got != "Quit [Ctrl+Q]" {
+		t.Errorf("suffix hint = %q", got)
+	}
+	if len(tmpl.pendingBindings) != 2 || tmpl.pendingBindings[1].pattern != "<C-q>" {
+		t.Errorf("bindings = %+v", tmpl.pendingBindings)
+	}
+}
+
+func TestTabsKeys(t *testing.T) {
+	app, err := NewApp()
+	if err != nil {
+		t.Fatal(err)
+	}
+	be := NewBufferBackend(40, 1)
+	tab := 0
+	app.SetBackend(be).SetView(Tabs([]string{"Files", "Search"}, &tab).Keys("1", "2").Hint(HintSuffix))
+
+	done := make(chan error, 1)
+	go func() { done <- app.Run() }()
+	defer func() {
+		app.Stop()
+		<-done
+	}()
+
+	if !be.WaitFor(func(b *Buffer) bool { return strings.Contains(b.GetLine(0), "Files [1]  Search [2]") }, time.Second) {
+		t.Fatalf("tabs = %q", be.Frame().GetLine(0))
+	}
+	be.Type("2")
+	if !be.WaitFor(func(b *Buffer) bool { return b.Get(11, 0).Style.Attr.Has(AttrUnderline) }, time.Second) {
+		t.Error("second tab not selected")
+	}
+}
+
+func TestTabsUnderlineHint(t *testing.T) {
+	buf := NewBuffer(30, 1)
+	tab := 0
+	Build(Tabs([]string{"Files", "Search"}, &tab).Kind(TabsStyleBracket).Keys("f", "<A-s>").Hint(HintUnderline)).Execute(buf, 30, 1)
+	// [Files]  [Search]
+	if !buf.Get(1, 0).Style.Attr.Has(AttrUnderline) || buf.Get(2, 0).Style.Attr.Has(AttrUnderline) {
+		t.Error("F of Files not underlined alone")
+	}
+	if !buf.Get(10, 0).Style.Attr.Has(AttrUnderline) {
+		t.Error("S of Search not underlined")
+	}
+}
+
+func TestMenuBarHint(t *testing.T) {
+	buf := NewBuffer(30, 1)
+	mb := MenuBar(Menu{Label: "File", Key: "<A-f>"}, Menu{Label: "Edit", Key: "<A-d>"}).Hint(HintUnderline)
+	Build(mb).Execute(buf, 30, 1)
+	// " File  Edit "
+	if !buf.Get(1, 0).Style.Attr.Has(AttrUnderline) {
+		t.Error("F of File not underlined")
+	}
+	if !buf.Get(8, 0).Style.Attr.Has(AttrUnderline) || buf.Get(7, 0).Style.Attr.Has(AttrUnderline) {
+		t.Error("d of Edit not underlined alone")
+	}
+}
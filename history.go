@@ -0,0 +1,109 @@
+package glyph
+
+import (
+	"bufio"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// History is the lines entered at a prompt, oldest first, optionally kept
+// in a file between runs. Give one to a CommandPalette and a CommandLine
+// and they share it:
+//
+//	hist, err := OpenHistory(filepath.Join(configDir, "history"), 500)
+//	CommandLine(app.Commands()).History(hist)
+//	CommandPalette(app.Commands()).History(hist)
+//
+// Entering a line again moves it to the end rather than keeping both.
+type History struct {
+	path  string
+	max   int
+	lines []string
+}
+
+// NewHistory creates a history kept in memory, holding at most max lines.
+// A max of 0 or less keeps them all.
+func NewHistory(max int) *History {
+	return &History{max: max}
+}
+
+// OpenHistory creates a history saved to path, reading what's there. A
+// missing file is fine; it's created on the first Add.
+func OpenHistory(path string, max int) (*History, error) {
+	h := &History{path: path, max: max}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		if line := sc.Text(); line != "" {
+			h.lines = append(h.lines, line)
+		}
+	}
+	h.trim()
+	return h, sc.Err()
+}
+
+// Add appends a line, moving it to the end if it's already there, and
+// saves the history if it has a file. Blank lines are ignored.
+func (h *History) Add(line string) error {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.ContainsAny(line, "\r\n") {
+		return nil
+	}
+	for i, l := range h.lines {
+		if l == line {
+			h.lines = append(h.lines[:i], h.lines[i+1:]...)
+			break
+		}
+	}
+	h.lines = append(h.lines, line)
+	h.trim()
+	return h.save()
+}
+
+// Lines returns the history, oldest first.
+func (h *History) Lines() []string {
+	return h.lines
+}
+
+// Search returns the lines matching an fzf query, newest first.
+func (h *History) Search(query string) []string {
+	q := ParseFzfQuery(query)
+	var out []string
+	for i := len(h.lines) - 1; i >= 0; i-- {
+		if _, ok := q.Score(h.lines[i]); ok {
+			out = append(out, h.lines[i])
+		}
+	}
+	return out
+}
+
+func (h *History) trim() {
+	if h.max > 0 && len(h.lines) > h.max {
+		h.lines = append(h.lines[:0], h.lines[len(h.lines)-h.max:]...)
+	}
+}
+
+func (h *History) save() error {
+	if h.path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o755); err != nil {
+		return err
+	}
+	data := strings.Join(h.lines, "\n") + "\n"
+	tmp := h.path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(data), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, h.path)
+}
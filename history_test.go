@@ -0,0 +1,190 @@
+package glyph
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+func TestHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sub", "history")
+	h, err := OpenHistory(path, 3)
+	if err != nil || len(h.Lines()) != 0 {
+		t.Fatalf("missing file: %v %q", err, h.Lines())
+	}
+	for _, line := range []string{"write a", "goto 3", "  ", "write a", "quit", "set wrap"} {
+		if err := h.Add(line); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the repeat moved to the end, the blank was ignored and the oldest
+	// fell off
+	want := []string{"write a", "quit", "set wrap"}
+	if !reflect.DeepEqual(h.Lines(), want) {
+		t.Errorf("lines %q, want %q", h.Lines(), want)
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "write a\nquit\nset wrap\n" {
+		t.Errorf("saved %q", data)
+	}
+	again, err := OpenHistory(path, 10)
+	if err != nil || !reflect.DeepEqual(again.Lines(), want) {
+		t.Errorf("reopened: %v %q", err, again.Lines())
+	}
+
+	if got := h.Search("w"); !reflect.DeepEqual(got, []string{"set wrap", "write a"}) {
+		t.Errorf("search newest first: %q", got)
+	}
+	if got := h.Search(""); len(got) != 3 || got[0] != "set wrap" {
+		t.Errorf("empty search: %q", got)
+	}
+}
+
+func cmdLineFixture(t *testing.T) (*CommandLineC, *Template, func() *riffkey.Input, *[]string) {
+	t.Helper()
+	var ran []string
+	cmds := NewCommands()
+	cmds.Add("write", "", func(path string) { ran = append(ran, "write "+path) })
+	cmds.Add("wrap", "", func() { ran = append(ran, "wrap") })
+	cmds.Add("theme", "", func(name string) { ran = append(ran, "theme "+name) }).
+		Complete(0, CompleteWords("light", "dark"))
+
+	cl := CommandLine(cmds).History(NewHistory(0)).BindOpen(":")
+	tmpl := Build(VBox(Text("top"), Space(), cl))
+	var stack []*riffkey.Router
+	cl.setModal(func(r *riffkey.Router) { stack = append(stack, r) }, func() { stack = stack[:len(stack)-1] })
+	open := func() *riffkey.Input {
+		cl.Open()
+		return riffkey.NewInput(stack[len(stack)-1])
+	}
+	return cl, tmpl, open, &ran
+}
+
+func typeKeys(in *riffkey.Input, s string) {
+	for _, r := range s {
+		in.Dispatch(riffkey.Key{Rune: r})
+	}
+}
+
+func TestCommandLine(t *testing.T) {
+	cl, tmpl, open, ran := cmdLineFixture(t)
+	if len(tmpl.pendingBindings) != 1 || len(tmpl.pendingModals) != 1 {
+		t.Fatalf("bindings not collected")
+	}
+	enter := riffkey.Key{Special: riffkey.SpecialEnter}
+	tab := riffkey.Key{Special: riffkey.SpecialTab}
+
+	in := open()
+	typeKeys(in, "wr")
+	lines := messageLines(tmpl, 30, 6)
+	if lines[5] != ":wr" {
+		t.Errorf("line %q", lines[5])
+	}
+	if !strings.Contains(lines[4], "write") || !strings.Contains(lines[4], "wrap") {
+		t.Errorf("wildmenu %q", lines[4])
+	}
+	in.Dispatch(tab)
+	in.Dispatch(tab)
+	if cl.Line() != "wrap" {
+		t.Fatalf("tab cycled to %q", cl.Line())
+	}
+	in.Dispatch(enter)
+	if cl.IsOpen() || len(*ran) != 1 || (*ran)[0] != "wrap" {
+		t.Fatalf("open=%v ran=%q", cl.IsOpen(), *ran)
+	}
+
+	in = open()
+	typeKeys(in, "theme d")
+	in.Dispatch(tab)
+	in.Dispatch(enter)
+	if (*ran)[1] != "theme dark" {
+		t.Errorf("argument completion ran %q", *ran)
+	}
+
+	// an error is shown once the line closes
+	in = open()
+	typeKeys(in, "nope")
+	in.Dispatch(enter)
+	if got := messageLines(tmpl, 30, 6)[5]; got != "unknown command: nope" {
+		t.Errorf("message %q", got)
+	}
+}
+
+func TestCommandLineHistory(t *testing.T) {
+	cl, tmpl, open, _ := cmdLineFixture(t)
+	enter := riffkey.Key{Special: riffkey.SpecialEnter}
+	up := riffkey.Key{Special: riffkey.SpecialUp}
+	down := riffkey.Key{Special: riffkey.SpecialDown}
+	for _, line := range []string{"write a.txt", "theme light", "write b.txt", "wrap"} {
+		in := open()
+		typeKeys(in, line)
+		in.Dispatch(enter)
+	}
+
+	// up and down walk lines starting with what's typed
+	in := open()
+	typeKeys(in, "wri")
+	var walked []string
+	for _, k := range []riffkey.Key{up, up, up, down, down} {
+		in.Dispatch(k)
+		walked = append(walked, cl.Line())
+	}
+	want := []string{"write b.txt", "write a.txt", "write a.txt", "write b.txt", "wri"}
+	if !reflect.DeepEqual(walked, want) {
+		t.Errorf("walked %q, want %q", walked, want)
+	}
+
+	// Ctrl-R searches, newest first, in a popup above the line
+	in.Dispatch(riffkey.Key{Rune: 'r', Mod: riffkey.ModCtrl})
+	typeKeys(in, "tx")
+	lines := messageLines(tmpl, 30, 6)
+	if lines[5] != "(history) tx" {
+		t.Errorf("search line %q", lines[5])
+	}
+	screen := strings.Join(lines, "\n")
+	if !strings.Contains(screen, "write b.txt") || !strings.Contains(screen, "write a.txt") || strings.Contains(screen, "wrap") {
+		t.Errorf("popup:\n%s", screen)
+	}
+	in.Dispatch(riffkey.Key{Rune: 'r', Mod: riffkey.ModCtrl}) // the next older match
+	in.Dispatch(enter)
+	if cl.Line() != "write a.txt" || !cl.IsOpen() {
+		t.Errorf("took %q open=%v", cl.Line(), cl.IsOpen())
+	}
+
+	// Esc puts the line back
+	in.Dispatch(riffkey.Key{Rune: 'r', Mod: riffkey.ModCtrl})
+	in.Dispatch(riffkey.Key{Special: riffkey.SpecialEscape})
+	if cl.Line() != "write a.txt" || !cl.IsOpen() {
+		t.Errorf("cancel left %q open=%v", cl.Line(), cl.IsOpen())
+	}
+}
+
+func TestCommandPaletteHistory(t *testing.T) {
+	cmds := NewCommands()
+	var ran []string
+	cmds.Add("goto", "", func(n int) { ran = append(ran, "goto") })
+	hist := NewHistory(0)
+	hist.Add("goto 12")
+	hist.Add("goto 40")
+
+	p := CommandPalette(cmds).History(hist)
+	var stack []*riffkey.Router
+	p.setModal(func(r *riffkey.Router) { stack = append(stack, r) }, func() { stack = stack[:len(stack)-1] })
+	p.Open()
+	in := riffkey.NewInput(stack[0])
+	in.Dispatch(riffkey.Key{Rune: 'r', Mod: riffkey.ModCtrl})
+	typeKeys(in, "12")
+	in.Dispatch(riffkey.Key{Special: riffkey.SpecialEnter})
+	if p.Query() != "goto 12" {
+		t.Fatalf("recalled %q", p.Query())
+	}
+	in.Dispatch(riffkey.Key{Special: riffkey.SpecialEnter})
+	if len(ran) != 1 || hist.Lines()[1] != "goto 12" {
+		t.Errorf("ran %q, history %q", ran, hist.Lines())
+	}
+}
@@ -0,0 +1,232 @@
+package glyph
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+// InspectorKey binds a key that opens the layout inspector, a development
+// aid listing the component tree of the current frame beside the screen:
+//
+//	app.InspectorKey("<C-S-i>")
+//
+// The component selected in the tree is outlined on screen and its
+// position, size, grow weight, margin and key bindings are shown under
+// the tree. Up/down or j/k move, left/h goes to the parent and right/l to
+// the first child, and Esc, q or the key again closes it. The tree
+// follows the screen as it changes.
+func (a *App) InspectorKey(pattern string) *App {
+	a.router.Handle(pattern, func(riffkey.Match) { a.OpenInspector() })
+	a.inspectorKey = pattern
+	return a
+}
+
+// OpenInspector opens the layout inspector and gives it the keyboard until
+// it's closed.
+func (a *App) OpenInspector() {
+	if a.inspector == nil {
+		a.inspector = &inspector{}
+	}
+	if a.inspector.open {
+		return
+	}
+	a.inspector.open = true
+	a.Push(a.inspector.keys(a))
+	a.RequestRender()
+}
+
+// CloseInspector closes the layout inspector.
+func (a *App) CloseInspector() {
+	if a.inspector == nil || !a.inspector.open {
+		return
+	}
+	a.inspector.open = false
+	a.Pop()
+	a.RequestRender()
+}
+
+// inspector is the App's layout inspector: a tree of the traced boxes.
+type inspector struct {
+	open   bool
+	sel    int // index into rows
+	top    int // first row shown
+	rows   []traceBox
+	router *riffkey.Router
+}
+
+// inspectorWidth is the widest the inspector panel gets.
+const inspectorWidth = 44
+
+// keys returns the router pushed while the inspector is open.
+func (in *inspector) keys(a *App) *riffkey.Router {
+	if in.router != nil {
+		return in.router
+	}
+	r := riffkey.NewRouter().NoCounts()
+	act := func(fn func()) func(riffkey.Match) {
+		return func(riffkey.Match) {
+			fn()
+			a.RequestRender()
+		}
+	}
+	down := act(func() { in.move(1) })
+	up := act(func() { in.move(-1) })
+	r.Handle("<Down>", down)
+	r.Handle("j", down)
+	r.Handle("<Up>", up)
+	r.Handle("k", up)
+	r.Handle("<Left>", act(in.parent))
+	r.Handle("h", act(in.parent))
+	r.Handle("<Right>", act(in.child))
+	r.Handle("l", act(in.child))
+	r.Handle("g", act(func() { in.sel = 0 }))
+	r.Handle("G", act(func() { in.sel = len(in.rows) - 1 }))
+	r.Handle("<Esc>", func(riffkey.Match) { a.CloseInspector() })
+	r.Handle("q", func(riffkey.Match) { a.CloseInspector() })
+	if a.inspectorKey != "" {
+		r.Handle(a.inspectorKey, func(riffkey.Match) { a.CloseInspector() })
+	}
+	in.router = r
+	return r
+}
+
+func (in *inspector) move(dir int) {
+	if n := len(in.rows); n > 0 {
+		in.sel = min(max(in.sel+dir, 0), n-1)
+	}
+}
+
+// parent selects the nearest row above that's one level out.
+func (in *inspector) parent() {
+	if in.sel >= len(in.rows) {
+		return
+	}
+	d := in.rows[in.sel].depth
+	for i := in.sel - 1; i >= 0; i-- {
+		if in.rows[i].depth < d {
+			in.sel = i
+			return
+		}
+	}
+}
+
+// child selects the first row inside the selected one.
+func (in *inspector) child() {
+	if in.sel+1 < len(in.rows) && in.rows[in.sel+1].depth > in.rows[in.sel].depth {
+		in.sel++
+	}
+}
+
+// update rebuilds the tree from the frame just drawn.
+func (in *inspector) update(rt *renderTrace) {
+	in.rows = append(in.rows[:0], rt.boxes...)
+	in.sel = min(in.sel, max(len(in.rows)-1, 0))
+}
+
+// draw outlines the selected box and draws the panel down one side of
+// the screen, whichever side the box leaves clearer.
+func (in *inspector) draw(buf *Buffer, rt *renderTrace) {
+	in.update(rt)
+	sw, sh := buf.Width(), buf.Height()
+	pw := min(inspectorWidth, sw/2)
+	if pw < 20 || sh < 8 {
+		return
+	}
+	px := sw - pw
+	if len(in.rows) > 0 {
+		sel := in.rows[in.sel]
+		x, y, w, h := sel.inner()
+		if w > 0 && h > 0 {
+			drawOutline(buf, x, y, w, h, Style{FG: Yellow, Attr: AttrBold})
+		}
+		if x+w/2 >= sw/2 {
+			px = 0
+		}
+	}
+
+	style := Style{}
+	buf.FillRect(px, 0, pw, sh, Cell{Rune: ' ', Style: style})
+	buf.DrawBorder(px, 0, pw, sh, BorderRounded, style)
+	buf.WriteStringFast(px+2, 0, " Inspector ", style, pw-4)
+
+	details := in.details()
+	treeH := sh - 3 - len(details)
+	if treeH < 2 {
+		details = nil
+		treeH = sh - 2
+	}
+	in.top = min(in.top, in.sel)
+	if in.sel >= in.top+treeH {
+		in.top = in.sel - treeH + 1
+	}
+	for i := 0; i < treeH && in.top+i < len(in.rows); i++ {
+		row := in.rows[in.top+i]
+		rs := Style{FG: layoutColor(row.depth)}
+		if in.top+i == in.sel {
+			rs = Style{Attr: AttrInverse}
+			buf.FillRect(px+1, 1+i, pw-2, 1, Cell{Rune: ' ', Style: rs})
+		}
+		indent := min(row.depth, (pw-12)/2)
+		_, _, w, h := row.inner()
+		label := fmt.Sprintf("%s%s %d×%d", strings.Repeat("  ", indent), row.name(), w, h)
+		buf.WriteStringFast(px+2, 1+i, label, rs, pw-4)
+	}
+	if details == nil {
+		return
+	}
+	sep := 1 + treeH
+	buf.WriteStringFast(px, sep, "├"+strings.Repeat("─", pw-2)+"┤", style, pw)
+	for i, line := range details {
+		buf.WriteStringFast(px+2, sep+1+i, line, style, pw-4)
+	}
+}
+
+// details describes the selected box, a fact per line.
+func (in *inspector) details() []string {
+	if in.sel >= len(in.rows) {
+		return []string{"nothing drawn"}
+	}
+	b := in.rows[in.sel]
+	op := b.op
+	x, y, w, h := b.inner()
+	lines := []string{
+		b.name(),
+		fmt.Sprintf("at %d,%d  size %d×%d", x, y, w, h),
+	}
+	var layout []string
+	if op.FlexGrow > 0 {
+		layout = append(layout, fmt.Sprintf("grow %g", op.FlexGrow))
+	}
+	if op.Width > 0 || op.Height > 0 {
+		layout = append(layout, fmt.Sprintf("fixed %d×%d", op.Width, op.Height))
+	}
+	if op.PercentWidth > 0 {
+		layout = append(layout, fmt.Sprintf("width %g%%", op.PercentWidth*100))
+	}
+	if op.Kind == OpContainer && op.Gap != 0 {
+		layout = append(layout, fmt.Sprintf("gap %d", op.Gap))
+	}
+	if op.FitContent {
+		layout = append(layout, "fit content")
+	}
+	if len(layout) > 0 {
+		lines = append(lines, strings.Join(layout, "  "))
+	}
+	if m := op.Margin; m != [4]int16{} {
+		lines = append(lines, fmt.Sprintf("margin %d,%d,%d,%d", m[0], m[1], m[2], m[3]))
+	}
+	if len(op.keys) > 0 {
+		lines = append(lines, "keys "+strings.Join(op.keys, " "))
+	}
+	return lines
+}
+
+// name is what the dev tools call a box.
+func (b traceBox) name() string {
+	if b.op.name == "" {
+		return "?"
+	}
+	return b.op.name
+}
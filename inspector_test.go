@@ -0,0 +1,71 @@
+package glyph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+func TestInspector(t *testing.T) {
+	app, err := NewApp()
+	if err != nil {
+		t.Fatal(err)
+	}
+	be := NewBufferBackend(60, 16)
+	app.SetBackend(be).SetView(VBox(
+		Text("title"),
+		HBox.Grow(1)(
+			VBox.Grow(2).MarginVH(0, 1)(Text("left")),
+			Input().Width(20).Bind(),
+		),
+		CharPicker(nil).BindOpen("<C-u>"),
+	)).InspectorKey("<C-g>")
+
+	screen := func() string {
+		app.RenderNow()
+		var lines []string
+		for y := range 16 {
+			lines = append(lines, be.Frame().GetLine(y))
+		}
+		return strings.Join(lines, "\n")
+	}
+	press := func(keys ...riffkey.Key) {
+		for _, k := range keys {
+			app.Input().Dispatch(k)
+		}
+	}
+	key := func(r rune) riffkey.Key { return riffkey.Key{Rune: r} }
+
+	press(riffkey.Key{Rune: 'g', Mod: riffkey.ModCtrl})
+	got := screen()
+	for _, want := range []string{"Inspector", "VBox 60×16", "  Text 5×1", "  HBox 60×15", "      Text 4×1", "    Input 20×1", "  CharPicker 0×0", "at 0,0  size 60×16"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %q in\n%s", want, got)
+		}
+	}
+
+	// down to the HBox, into its first child, and out again
+	press(key('j'), key('j'), key('l'))
+	got = screen()
+	if !strings.Contains(got, "at 1,1  size 38×15") || !strings.Contains(got, "margin 0,1,0,1") {
+		t.Errorf("child details:\n%s", got)
+	}
+	press(key('h'))
+	if got = screen(); !strings.Contains(got, "at 0,1  size 60×15") {
+		t.Errorf("parent:\n%s", got)
+	}
+	press(key('G'))
+	if got = screen(); !strings.Contains(got, "keys <C-u>") {
+		t.Errorf("bindings:\n%s", got)
+	}
+	press(key('k'))
+	if got = screen(); !strings.Contains(got, "keys typing") {
+		t.Errorf("typing:\n%s", got)
+	}
+
+	press(key('q'))
+	if got = screen(); strings.Contains(got, "Inspector") {
+		t.Errorf("still open:\n%s", got)
+	}
+}
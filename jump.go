@@ -108,3 +108,53 @@ func (jm *JumpMode) HasPartialMatch(prefix string) bool {
 	}
 	return false
 }
+
+// SpanJump makes a single Span inside RichText a jump target, so one part
+// of a line can be selected while the rest stays inert.
+type SpanJump struct {
+	OnSelect func()
+	Style    Style // label style override (zero value = use default)
+}
+
+// JumpSpan creates a span that runs onSelect when its jump label is
+// chosen.
+//
+//	Rich("panic at ", JumpSpan("main.go:42", Style{FG: Cyan}, openMain), " in worker")
+func JumpSpan(text string, style Style, onSelect func()) Span {
+	return Span{Text: text, Style: style, Jump: &SpanJump{OnSelect: onSelect}}
+}
+
+// jumpTarget registers a target at x,y and draws its label once labels
+// have been assigned.
+func (t *Template) jumpTarget(buf *Buffer, x, y int16, onSelect func(), style Style) {
+	t.app.AddJumpTarget(x, y, onSelect, style)
+
+	jm := t.app.JumpMode()
+	for i := len(jm.Targets) - 1; i >= 0; i-- {
+		target := &jm.Targets[i]
+		if target.X == x && target.Y == y && target.Label != "" {
+			labelStyle := t.app.JumpStyle().LabelStyle
+			if !target.Style.Equal(Style{}) {
+				labelStyle = target.Style
+			}
+			for j, r := range target.Label {
+				buf.Set(int(x)+j, int(y), Cell{Rune: r, Style: labelStyle})
+			}
+			break
+		}
+	}
+}
+
+// spanJumps registers the jump spans of a line drawn by WriteSpans at x,y.
+func (t *Template) spanJumps(buf *Buffer, spans []Span, x, y, maxWidth int) {
+	if t.app == nil || !t.app.JumpModeActive() {
+		return
+	}
+	written := 0
+	for _, span := range spans {
+		if span.Jump != nil && written < maxWidth && x+written < buf.Width() {
+			t.jumpTarget(buf, int16(x+written), int16(y), span.Jump.OnSelect, span.Jump.Style)
+		}
+		written += StringWidth(span.Text)
+	}
+}
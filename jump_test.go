@@ -0,0 +1,74 @@
+package glyph
+
+import (
+	"testing"
+)
+
+func jumpTestApp(t *testing.T, view any) (*App, *BufferBackend) {
+	t.Helper()
+	app, err := NewApp()
+	if err != nil {
+		t.Fatal(err)
+	}
+	be := NewBufferBackend(40, 4)
+	app.SetBackend(be).SetView(view)
+	return app, be
+}
+
+func TestJumpSpan(t *testing.T) {
+	opened := ""
+	line := Rich("panic at ", JumpSpan("main.go:42", Style{FG: Cyan}, func() { opened = "main.go:42" }), " in worker")
+	app, be := jumpTestApp(t, VBox(line))
+
+	app.EnterJumpMode()
+	targets := app.JumpMode().Targets
+	if len(targets) != 1 || targets[0].X != 9 || targets[0].Y != 0 {
+		t.Fatalf("targets = %+v, want one at 9,0", targets)
+	}
+
+	app.RenderNow()
+	if got := be.Frame().Get(9, 0).Rune; got != 'a' {
+		t.Errorf("label cell = %q, want a", got)
+	}
+	if got := be.Frame().Get(0, 0).Rune; got != 'p' {
+		t.Errorf("inert text overwritten: %q", got)
+	}
+
+	app.JumpMode().FindTarget("a").OnSelect()
+	if opened != "main.go:42" {
+		t.Errorf("OnSelect not called")
+	}
+}
+
+func TestJumpSpanInForEach(t *testing.T) {
+	type entry struct {
+		Spans []Span
+	}
+	var picked []int
+	entries := make([]entry, 3)
+	for i := range entries {
+		i := i
+		entries[i].Spans = NewRichText().
+			Add("line ").
+			Add("ref").OnSelect(func() { picked = append(picked, i) }).
+			Spans()
+	}
+	app, _ := jumpTestApp(t, VBox(ForEach(&entries, func(e *entry) any {
+		return RichTextNode{Spans: &e.Spans}
+	})))
+
+	app.EnterJumpMode()
+	targets := app.JumpMode().Targets
+	if len(targets) != 3 {
+		t.Fatalf("targets = %+v, want 3", targets)
+	}
+	for i, tg := range targets {
+		if tg.X != 5 || tg.Y != int16(i) {
+			t.Errorf("target %d at %d,%d, want 5,%d", i, tg.X, tg.Y, i)
+		}
+	}
+	targets[2].OnSelect()
+	if len(picked) != 1 || picked[0] != 2 {
+		t.Errorf("picked = %v, want [2]", picked)
+	}
+}
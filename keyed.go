@@ -0,0 +1,147 @@
+package glyph
+
+import (
+	"unsafe"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+// keyedItems holds one compiled template per key for a keyed ForEach, so
+// per-item state created by the template function survives reordering.
+type keyedItems struct {
+	key      func(elem unsafe.Pointer) any
+	build    func(elem unsafe.Pointer) any
+	elemSize uintptr
+
+	byKey map[any]*Template
+	items []*Template // template for each index, refreshed by reconcile
+	dups  []*Template // one-pass templates for repeated keys
+
+	inputs map[*Template]*riffkey.Input // keys bound inside each item
+	router *riffkey.Router
+	base   *riffkey.Input // the view's router, for keys no item used
+}
+
+func newKeyedItems[T any](key func(*T) any, build func(*T) any) *keyedItems {
+	var zero T
+	return &keyedItems{
+		key:      func(elem unsafe.Pointer) any { return key((*T)(elem)) },
+		build:    func(elem unsafe.Pointer) any { return build((*T)(elem)) },
+		elemSize: unsafe.Sizeof(zero),
+		byKey:    make(map[any]*Template),
+		inputs:   make(map[*Template]*riffkey.Input),
+	}
+}
+
+// reconcile matches the n items at data to templates by key: existing keys
+// keep their template, new keys get a freshly compiled one, and templates
+// for keys no longer present are released.
+func (k *keyedItems) reconcile(data unsafe.Pointer, n int) {
+	for _, tmpl := range k.dups {
+		tmpl.unmountAll()
+		delete(k.inputs, tmpl)
+	}
+	k.items = k.items[:0]
+	k.dups = k.dups[:0]
+	seen := make(map[any]*Template, n)
+	for i := 0; i < n; i++ {
+		elem := unsafe.Pointer(uintptr(data) + uintptr(i)*k.elemSize)
+		key := k.key(elem)
+		tmpl, dup := seen[key]
+		if dup {
+			// duplicate keys can't share geometry; give the repeat its own
+			// template, rebuilt every pass
+			tmpl = k.compile(elem)
+			k.dups = append(k.dups, tmpl)
+		} else {
+			if tmpl = k.byKey[key]; tmpl == nil {
+				tmpl = k.compile(elem)
+			}
+			seen[key] = tmpl
+		}
+		k.items = append(k.items, tmpl)
+	}
+	for key, tmpl := range k.byKey {
+		if _, ok := seen[key]; !ok {
+			tmpl.unmountAll()
+			delete(k.inputs, tmpl)
+		}
+	}
+	k.byKey = seen
+}
+
+// settle and unmount make keyedItems a mounter, forwarding to each item.
+func (k *keyedItems) settle() {
+	for _, tmpl := range k.byKey {
+		tmpl.settleMounts()
+	}
+	for _, tmpl := range k.dups {
+		tmpl.settleMounts()
+	}
+}
+
+func (k *keyedItems) unmount() {
+	for _, tmpl := range k.byKey {
+		tmpl.unmountAll()
+	}
+	for _, tmpl := range k.dups {
+		tmpl.unmountAll()
+	}
+}
+
+// compile builds the template for one item. Pointers into the item are
+// compiled as offsets, so the template stays valid when the item moves.
+func (k *keyedItems) compile(elem unsafe.Pointer) *Template {
+	return newSubTemplate(k.build(elem), elem, k.elemSize)
+}
+
+// attachRouter pushes a router that offers each key to the items in order
+// and then to base, the view's own router.
+func (k *keyedItems) attachRouter(base *riffkey.Router, push func(*riffkey.Router)) {
+	k.base = riffkey.NewInput(base)
+	if k.router == nil {
+		k.router = riffkey.NewRouter().NoCounts()
+		k.router.HandleUnmatched(k.dispatch)
+		if push != nil {
+			push(k.router)
+		}
+	}
+}
+
+func (k *keyedItems) dispatch(key riffkey.Key) bool {
+	for _, tmpl := range k.items {
+		if in := k.input(tmpl); in != nil && in.Dispatch(key) {
+			return true
+		}
+	}
+	return k.base != nil && k.base.Dispatch(key)
+}
+
+// input returns the keys bound inside an item's template, built the first
+// time the item sees a key. It's nil for an item that binds none.
+func (k *keyedItems) input(tmpl *Template) *riffkey.Input {
+	if in, ok := k.inputs[tmpl]; ok {
+		return in
+	}
+	var in *riffkey.Input
+	if len(tmpl.pendingBindings) > 0 || tmpl.pendingKeyHandler != nil {
+		r := riffkey.NewRouter().NoCounts()
+		for _, b := range tmpl.pendingBindings {
+			routeBinding(r, b, func() {})
+		}
+		if tmpl.pendingKeyHandler != nil {
+			r.HandleUnmatched(tmpl.pendingKeyHandler)
+		}
+		in = riffkey.NewInput(r)
+	}
+	k.inputs[tmpl] = in
+	return in
+}
+
+// itemTmpl returns the template to use for item i of a ForEach.
+func (op *Op) itemTmpl(i int) *Template {
+	if op.keyed != nil && i < len(op.keyed.items) {
+		return op.keyed.items[i]
+	}
+	return op.IterTmpl
+}
@@ -0,0 +1,143 @@
+package glyph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+func TestForEachKeyedPreservesItemState(t *testing.T) {
+	type item struct {
+		ID   int
+		Name string
+	}
+	items := []item{{1, "a"}, {2, "b"}, {3, "c"}}
+
+	builds := 0
+	tmpl := Build(VBox(
+		KeyBy(ForEach(&items, func(it *item) any {
+			builds++
+			// state owned by the item's template, captured when first seen
+			tag := new(string)
+			*tag = "#" + it.Name
+			return HBox.Gap(1)(Text(&it.Name), Text(tag))
+		}), func(it *item) int { return it.ID }),
+	))
+
+	render := func() []string {
+		buf := NewBuffer(20, 5)
+		tmpl.Execute(buf, 20, 5)
+		var lines []string
+		for y := 0; y < len(items); y++ {
+			lines = append(lines, strings.TrimSpace(buf.GetLine(y)))
+		}
+		return lines
+	}
+
+	builds = 0
+	got := render()
+	if want := []string{"a #a", "b #b", "c #c"}; strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("initial = %q, want %q", got, want)
+	}
+	if builds != 3 {
+		t.Errorf("initial builds = %d, want 3", builds)
+	}
+
+	// reorder: templates follow their items, nothing rebuilt
+	items[0], items[2] = items[2], items[0]
+	builds = 0
+	got = render()
+	if want := []string{"c #c", "b #b", "a #a"}; strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("reordered = %q, want %q", got, want)
+	}
+	if builds != 0 {
+		t.Errorf("reorder builds = %d, want 0", builds)
+	}
+
+	// insert: only the new item is built
+	items = append([]item{{4, "d"}}, items...)
+	builds = 0
+	got = render()
+	if want := []string{"d #d", "c #c", "b #b", "a #a"}; strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("inserted = %q, want %q", got, want)
+	}
+	if builds != 1 {
+		t.Errorf("insert builds = %d, want 1", builds)
+	}
+
+	// remove then re-add: state is released with the item
+	items = items[:3]
+	render()
+	items = append(items, item{1, "a"})
+	builds = 0
+	render()
+	if builds != 1 {
+		t.Errorf("re-add builds = %d, want 1", builds)
+	}
+}
+
+func TestForEachKeyedDuplicateKeys(t *testing.T) {
+	items := []string{"x", "x", "y"}
+	tmpl := Build(VBox(
+		KeyBy(ForEach(&items, func(s *string) any { return Text(s) }),
+			func(s *string) string { return *s }),
+	))
+	buf := NewBuffer(10, 4)
+	tmpl.Execute(buf, 10, 4)
+	for i, want := range items {
+		if got := strings.TrimSpace(buf.GetLine(i)); got != want {
+			t.Errorf("line %d = %q, want %q", i, got, want)
+		}
+	}
+}
+
+func TestForEachKeyedEmpty(t *testing.T) {
+	items := []string{"a"}
+	tmpl := Build(VBox(
+		KeyBy(ForEach(&items, func(s *string) any { return Text(s) }),
+			func(s *string) string { return *s }),
+	))
+	buf := NewBuffer(10, 2)
+	tmpl.Execute(buf, 10, 2)
+
+	items = nil
+	buf.Clear()
+	tmpl.Execute(buf, 10, 2)
+	if got := strings.TrimSpace(buf.GetLine(0)); got != "" {
+		t.Errorf("line 0 = %q, want empty", got)
+	}
+}
+
+func TestForEachKeyedRoutesItemKeys(t *testing.T) {
+	type item struct {
+		Key  string
+		Done bool
+	}
+	items := []item{{"x", false}, {"y", false}}
+	tmpl := Build(VBox(
+		KeyBy(ForEach(&items, func(it *item) any {
+			return Checkbox(&it.Done, it.Key).BindToggle(it.Key)
+		}), func(it *item) string { return it.Key }),
+	))
+	tmpl.Execute(NewBuffer(10, 2), 10, 2)
+
+	if len(tmpl.pendingScopes) != 1 {
+		t.Fatalf("keyed ForEach not collected for router wiring")
+	}
+	var got []string
+	base := riffkey.NewRouter()
+	base.Handle("q", func(riffkey.Match) { got = append(got, "view q") })
+	var pushed *riffkey.Router
+	tmpl.pendingScopes[0].attachRouter(base, func(r *riffkey.Router) { pushed = r })
+	in := riffkey.NewInput(pushed)
+
+	in.Dispatch(riffkey.Key{Rune: 'y'})
+	in.Dispatch(riffkey.Key{Rune: 'q'})
+	if items[0].Done || !items[1].Done {
+		t.Errorf("done = %v, %v; want false, true", items[0].Done, items[1].Done)
+	}
+	if strings.Join(got, ",") != "view q" {
+		t.Errorf("view keys = %q", got)
+	}
+}
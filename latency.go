@@ -0,0 +1,132 @@
+package glyph
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+// Latency sums up how long recent input took to reach the screen: from a
+// key's bytes arriving to the end of the first frame drawn after the key
+// was handled.
+type Latency struct {
+	P50, P99, Max time.Duration
+	Samples       int // keys measured, up to the last latencySamples
+}
+
+func (l Latency) String() string {
+	if l.Samples == 0 {
+		return "input latency: no keys yet"
+	}
+	ms := func(d time.Duration) float64 { return float64(d.Microseconds()) / 1000 }
+	return fmt.Sprintf("input p50 %.1fms  p99 %.1fms  max %.1fms  (%d keys)",
+		ms(l.P50), ms(l.P99), ms(l.Max), l.Samples)
+}
+
+// InputLatency reports input-to-frame latency over the most recent keys,
+// so a change that makes the app less responsive shows up as a number.
+// When several keys are handled before a frame, the earliest is measured.
+func (a *App) InputLatency() Latency {
+	return a.latency.stats()
+}
+
+// LatencyOverlayKey binds a key that shows and hides the latency overlay,
+// a development aid that reports InputLatency in the top right corner of
+// every frame:
+//
+//	app.LatencyOverlayKey("<C-S-l>")
+func (a *App) LatencyOverlayKey(pattern string) *App {
+	a.router.Handle(pattern, func(riffkey.Match) { a.ToggleLatencyOverlay() })
+	return a
+}
+
+// ToggleLatencyOverlay shows the latency overlay if it's hidden and hides
+// it if it's shown.
+func (a *App) ToggleLatencyOverlay() {
+	a.latencyOverlay = !a.latencyOverlay
+	a.RequestRender()
+}
+
+// drawLatencyOverlay writes l in the top right corner of buf.
+func drawLatencyOverlay(buf *Buffer, l Latency) {
+	text := " " + l.String() + " "
+	w := min(StringWidth(text), buf.Width())
+	buf.WriteStringFast(buf.Width()-w, 0, text, Style{Attr: AttrInverse}, w)
+}
+
+// latencySamples is how many keys InputLatency covers.
+const latencySamples = 256
+
+// inputLatency times input from arrival to the frame that shows it.
+// Input moves through it in three steps: arrive when it's read, handle
+// when its keys are dispatched, and framed when the next frame is done.
+type inputLatency struct {
+	mu      sync.Mutex
+	arrived time.Time // earliest input read but not yet handled
+	handled time.Time // earliest input handled but not yet drawn
+	samples [latencySamples]time.Duration
+	n       int // samples ever recorded; the latest are in samples[n%latencySamples]
+	sorted  []time.Duration
+}
+
+// stamp returns r, noting when input arrives through it.
+func (l *inputLatency) stamp(r io.Reader) io.Reader {
+	return &latencyReader{r: r, l: l}
+}
+
+type latencyReader struct {
+	r io.Reader
+	l *inputLatency
+}
+
+func (r *latencyReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.l.arrive()
+	}
+	return n, err
+}
+
+func (l *inputLatency) arrive() {
+	l.mu.Lock()
+	if l.arrived.IsZero() {
+		l.arrived = time.Now()
+	}
+	l.mu.Unlock()
+}
+
+func (l *inputLatency) handle() {
+	l.mu.Lock()
+	if l.handled.IsZero() {
+		l.handled = l.arrived
+	}
+	l.arrived = time.Time{}
+	l.mu.Unlock()
+}
+
+func (l *inputLatency) framed() {
+	l.mu.Lock()
+	if !l.handled.IsZero() {
+		l.samples[l.n%latencySamples] = time.Since(l.handled)
+		l.n++
+		l.handled = time.Time{}
+	}
+	l.mu.Unlock()
+}
+
+func (l *inputLatency) stats() Latency {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	n := min(l.n, latencySamples)
+	if n == 0 {
+		return Latency{}
+	}
+	l.sorted = append(l.sorted[:0], l.samples[:n]...)
+	slices.Sort(l.sorted)
+	at := func(p int) time.Duration { return l.sorted[(n-1)*p/100] }
+	return Latency{P50: at(50), P99: at(99), Max: l.sorted[n-1], Samples: n}
+}
@@ -0,0 +1,78 @@
+package glyph
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestInputLatency(t *testing.T) {
+	app, _ := NewApp()
+	be := NewBufferBackend(80, 3)
+	app.SetBackend(be)
+
+	count := 0
+	status := ""
+	app.OnBeforeRender(func() { status = fmt.Sprintf("count %d", count) })
+	app.SetView(Text(&status))
+	app.Handle("j", func() {
+		count++
+		time.Sleep(5 * time.Millisecond)
+	})
+	app.Handle("q", app.Stop)
+	app.LatencyOverlayKey("L")
+
+	done := make(chan error, 1)
+	go func() { done <- app.Run() }()
+	if !be.WaitFor(func(b *Buffer) bool { return strings.HasPrefix(b.GetLine(0), "count 0") }, time.Second) {
+		t.Fatal("no first frame")
+	}
+	if got := app.InputLatency(); got.Samples != 0 {
+		t.Errorf("latency before any keys: %+v", got)
+	}
+
+	for i := 1; i <= 3; i++ {
+		be.Type("j")
+		want := fmt.Sprintf("count %d", i)
+		if !be.WaitFor(func(b *Buffer) bool { return strings.HasPrefix(b.GetLine(0), want) }, time.Second) {
+			t.Fatalf("key %d not drawn", i)
+		}
+	}
+	// the last sample is taken just after its frame is presented
+	got := app.InputLatency()
+	for deadline := time.Now().Add(time.Second); got.Samples < 3 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+		got = app.InputLatency()
+	}
+	if got.Samples != 3 {
+		t.Errorf("got %d samples, want 3", got.Samples)
+	}
+	if got.P50 < 5*time.Millisecond || got.P99 < got.P50 || got.Max < got.P99 {
+		t.Errorf("latency doesn't cover the handler: %+v", got)
+	}
+
+	be.Type("L")
+	if !be.WaitFor(func(b *Buffer) bool { return strings.Contains(b.GetLine(0), "input p50 ") }, time.Second) {
+		t.Errorf("overlay not drawn: %q", be.Frame().GetLine(0))
+	}
+
+	be.Type("q")
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLatencyStats(t *testing.T) {
+	var l inputLatency
+	for i := 1; i <= 300; i++ {
+		l.samples[l.n%latencySamples] = time.Duration(i) * time.Millisecond
+		l.n++
+	}
+	// the oldest 44 have been overwritten, leaving 45ms to 300ms
+	got := l.stats()
+	if got.Samples != latencySamples || got.Max != 300*time.Millisecond ||
+		got.P50 != 172*time.Millisecond || got.P99 != 297*time.Millisecond {
+		t.Errorf("got %+v", got)
+	}
+}
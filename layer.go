@@ -1,5 +1,10 @@
 package glyph
 
+import (
+	"slices"
+	"sort"
+)
+
 // Layer is a pre-rendered buffer with scroll management.
 // Content is rendered once (expensive), then blitted to screen each frame (cheap).
 //
@@ -7,9 +12,12 @@ package glyph
 // the viewport dimensions change. This ensures content is always rendered at
 // the correct size without manual timing coordination.
 type Layer struct {
-	buffer    *Buffer
-	scrollY   int
-	maxScroll int
+	buffer     *Buffer
+	scrollY    int
+	maxScroll  int
+	scrollX    int
+	maxScrollX int
+	scrollXPtr *int // bound horizontal offset, if any
 
 	// Viewport dimensions (set during layout)
 	viewWidth  int
@@ -36,6 +44,10 @@ type Layer struct {
 	// AlwaysRender causes Render to fire every frame, not just on width changes.
 	// Used by components that track external pointer mutations (e.g. TextViewC).
 	AlwaysRender bool
+
+	// Sticky rows (buffer coordinates, ascending)
+	stickyHeaders []int
+	stickyFooters []int
 }
 
 // NewLayer creates a new empty layer.
@@ -49,6 +61,7 @@ func (l *Layer) SetContent(tmpl *Template, width, height int) {
 	l.buffer = NewBuffer(width, height)
 	tmpl.Execute(l.buffer, int16(width), int16(height))
 	l.scrollY = 0
+	l.scrollX = 0
 	l.updateMaxScroll()
 }
 
@@ -57,6 +70,7 @@ func (l *Layer) SetContent(tmpl *Template, width, height int) {
 func (l *Layer) SetBuffer(buf *Buffer) {
 	l.buffer = buf
 	l.scrollY = 0
+	l.scrollX = 0
 	l.updateMaxScroll()
 }
 
@@ -69,6 +83,7 @@ func (l *Layer) Buffer() *Buffer {
 func (l *Layer) updateMaxScroll() {
 	if l.buffer == nil || l.viewHeight <= 0 {
 		l.maxScroll = 0
+		l.maxScrollX = 0
 		return
 	}
 	l.maxScroll = l.buffer.Height() - l.viewHeight
@@ -79,6 +94,9 @@ func (l *Layer) updateMaxScroll() {
 	if l.scrollY > l.maxScroll {
 		l.scrollY = l.maxScroll
 	}
+	l.maxScrollX = max(l.buffer.Width()-l.viewWidth, 0)
+	l.syncScrollX()
+	l.ScrollToX(l.scrollX)
 }
 
 // SetViewport sets the viewport dimensions for the layer.
@@ -189,12 +207,139 @@ func (l *Layer) HalfPageUp() {
 	l.ScrollUp(l.viewHeight / 2)
 }
 
+// ScrollX returns the horizontal scroll position.
+func (l *Layer) ScrollX() int {
+	return l.scrollX
+}
+
+// MaxScrollX returns the maximum horizontal scroll position.
+func (l *Layer) MaxScrollX() int {
+	return l.maxScrollX
+}
+
+// ContentWidth returns the total content width.
+func (l *Layer) ContentWidth() int {
+	if l.buffer == nil {
+		return 0
+	}
+	return l.buffer.Width()
+}
+
+// ScrollToX sets the horizontal scroll position, clamping to valid range.
+func (l *Layer) ScrollToX(x int) {
+	l.scrollX = max(min(x, l.maxScrollX), 0)
+	if l.scrollXPtr != nil {
+		*l.scrollXPtr = l.scrollX
+	}
+}
+
+// BindScrollX ties the horizontal scroll position to p. Writing *p scrolls
+// the layer on the next frame, and scrolling writes the clamped position
+// back, so the offset can be saved, shared or driven from app state. The
+// bound position is kept across SetBuffer.
+func (l *Layer) BindScrollX(p *int) {
+	l.scrollXPtr = p
+}
+
+// syncScrollX picks up a change made through the bound offset.
+func (l *Layer) syncScrollX() {
+	if l.scrollXPtr != nil {
+		l.scrollX = *l.scrollXPtr
+	}
+}
+
+// ScrollRight scrolls right by n columns.
+func (l *Layer) ScrollRight(n int) {
+	l.syncScrollX()
+	l.ScrollToX(l.scrollX + n)
+}
+
+// ScrollLeft scrolls left by n columns.
+func (l *Layer) ScrollLeft(n int) {
+	l.syncScrollX()
+	l.ScrollToX(l.scrollX - n)
+}
+
+// HalfPageRight scrolls right by half the viewport width.
+func (l *Layer) HalfPageRight() {
+	l.ScrollRight(l.viewWidth / 2)
+}
+
+// HalfPageLeft scrolls left by half the viewport width.
+func (l *Layer) HalfPageLeft() {
+	l.ScrollLeft(l.viewWidth / 2)
+}
+
 // blit copies the visible portion of the layer to the destination buffer.
 func (l *Layer) blit(dst *Buffer, dstX, dstY, width, height int) {
 	if l.buffer == nil {
 		return
 	}
-	dst.Blit(l.buffer, 0, l.scrollY, dstX, dstY, width, height)
+	dst.Blit(l.buffer, l.scrollX, l.scrollY, dstX, dstY, width, height)
+	if height < 2 {
+		return
+	}
+	if y, ok := l.pinnedHeader(); ok {
+		dst.Blit(l.buffer, l.scrollX, y, dstX, dstY, width, 1)
+	}
+	if y, ok := l.pinnedFooter(height); ok {
+		dst.Blit(l.buffer, l.scrollX, y, dstX, dstY+height-1, width, 1)
+	}
+}
+
+// StickyHeader marks row y as a section header. Once it scrolls off the
+// top it stays pinned to the first row of the viewport until the next
+// header reaches the top.
+func (l *Layer) StickyHeader(y int) {
+	l.stickyHeaders = insertSorted(l.stickyHeaders, y)
+}
+
+// StickyFooter marks row y as a section footer, ending the section that
+// began after the previous footer. While that section is in view and the
+// footer is still below the viewport, it is pinned to the last row.
+func (l *Layer) StickyFooter(y int) {
+	l.stickyFooters = insertSorted(l.stickyFooters, y)
+}
+
+// ClearSticky removes all sticky headers and footers.
+func (l *Layer) ClearSticky() {
+	l.stickyHeaders = l.stickyHeaders[:0]
+	l.stickyFooters = l.stickyFooters[:0]
+}
+
+// pinnedHeader returns the header to draw over the first viewport row:
+// the last one above the viewport.
+func (l *Layer) pinnedHeader() (int, bool) {
+	i := sort.SearchInts(l.stickyHeaders, l.scrollY) // first header at or below the top
+	if i == 0 || (i < len(l.stickyHeaders) && l.stickyHeaders[i] == l.scrollY) {
+		return 0, false
+	}
+	y := l.stickyHeaders[i-1]
+	return y, y < l.buffer.Height()
+}
+
+// pinnedFooter returns the footer to draw over the last viewport row: the
+// first one below the viewport, once its section has rows in view above
+// the last one.
+func (l *Layer) pinnedFooter(height int) (int, bool) {
+	bottom := l.scrollY + height - 1
+	i := sort.SearchInts(l.stickyFooters, bottom+1) // first footer below the viewport
+	if i == len(l.stickyFooters) || l.stickyFooters[i] >= l.buffer.Height() {
+		return 0, false
+	}
+	start := 0
+	if i > 0 {
+		start = l.stickyFooters[i-1] + 1
+	}
+	return l.stickyFooters[i], start < bottom
+}
+
+func insertSorted(s []int, v int) []int {
+	i := sort.SearchInts(s, v)
+	if i < len(s) && s[i] == v {
+		return s
+	}
+	return slices.Insert(s, i, v)
 }
 
 // SetLine updates a single line in the layer buffer with styled spans.
@@ -294,16 +439,17 @@ func (l *Layer) ScreenCursor() (x, y int, visible bool) {
 		return 0, 0, false
 	}
 
-	// cursor Y relative to viewport (account for scroll)
+	// cursor relative to viewport (account for scroll)
+	viewX := l.cursor.X - l.scrollX
 	viewY := l.cursor.Y - l.scrollY
 
 	// check if cursor is within visible viewport
-	if viewY < 0 || viewY >= l.viewHeight {
+	if viewY < 0 || viewY >= l.viewHeight || viewX < 0 || (l.viewWidth > 0 && viewX >= l.viewWidth) {
 		return 0, 0, false
 	}
 
 	// translate to screen coordinates
-	x = l.screenX + l.cursor.X
+	x = l.screenX + viewX
 	y = l.screenY + viewY
 	return x, y, true
 }
@@ -391,3 +391,151 @@ func BenchmarkLayerCursorScreenTranslation(b *testing.B) {
 		_, _, _ = layer.ScreenCursor()
 	}
 }
+
+func TestLayerSticky(t *testing.T) {
+	// two sections of five rows: a header, three items and a footer
+	layer := NewLayer()
+	buf := NewBuffer(10, 10)
+	rows := []string{"H1", "a1", "a2", "a3", "F1", "H2", "b1", "b2", "b3", "F2"}
+	for y, r := range rows {
+		buf.WriteStringFast(0, y, r, Style{}, 10)
+	}
+	layer.SetBuffer(buf)
+	layer.StickyHeader(0)
+	layer.StickyHeader(5)
+	layer.StickyFooter(4)
+	layer.StickyFooter(9)
+
+	tmpl := Build(VBox(LayerView(layer).ViewHeight(3)))
+	frame := func(scroll int) (top, mid, bottom string) {
+		layer.ScrollTo(scroll)
+		screen := NewBuffer(10, 3)
+		tmpl.Execute(screen, 10, 3)
+		return screen.GetLine(0), screen.GetLine(1), screen.GetLine(2)
+	}
+
+	tests := []struct {
+		scroll           int
+		top, mid, bottom string
+	}{
+		{0, "H1", "a1", "F1"}, // footer pinned below section start
+		{2, "H1", "a3", "F1"}, // header pinned, footer in place
+		{3, "H1", "F1", "H2"}, // only the next header in view, so no footer yet
+		{4, "H1", "H2", "F2"}, // next section started, its footer pinned
+		{5, "H2", "b1", "F2"}, // new header at the top on its own
+		{7, "H2", "b3", "F2"},
+	}
+	for _, tt := range tests {
+		top, mid, bottom := frame(tt.scroll)
+		if top != tt.top || mid != tt.mid || bottom != tt.bottom {
+			t.Errorf("scroll %d = %q %q %q, want %q %q %q", tt.scroll, top, mid, bottom, tt.top, tt.mid, tt.bottom)
+		}
+	}
+
+	layer.ClearSticky()
+	if top, _, bottom := frame(2); top != "a2" || bottom != "F1" {
+		t.Errorf("after ClearSticky = %q .. %q, want a2 .. F1", top, bottom)
+	}
+}
+
+func TestLayerHorizontalScroll(t *testing.T) {
+	layer := NewLayer()
+	buf := NewBuffer(20, 2)
+	buf.WriteStringFast(0, 0, "0123456789abcdefghij", Style{}, 20)
+	buf.WriteStringFast(0, 1, "ABCDEFGHIJKLMNOPQRST", Style{}, 20)
+	layer.SetBuffer(buf)
+
+	view := LayerView(layer).ViewWidth(10).ViewHeight(3).HScrollbar().BindHScroll("zh", "zl")
+	tmpl := Build(VBox(view))
+	frame := func() *Buffer {
+		screen := NewBuffer(10, 3)
+		tmpl.Execute(screen, 10, 3)
+		return screen
+	}
+
+	screen := frame()
+	if got := screen.GetLine(0); got != "0123456789" {
+		t.Errorf("line 0 = %q", got)
+	}
+	if got := screen.GetLine(2); got != "━━━━━─────" {
+		t.Errorf("scrollbar = %q, want thumb on the left half", got)
+	}
+	if layer.MaxScrollX() != 10 {
+		t.Fatalf("MaxScrollX = %d, want 10", layer.MaxScrollX())
+	}
+
+	// bindings scroll one column at a time
+	if len(tmpl.pendingBindings) != 2 {
+		t.Fatalf("bindings = %d, want 2", len(tmpl.pendingBindings))
+	}
+	tmpl.pendingBindings[1].handler.(func())()
+	tmpl.pendingBindings[1].handler.(func())()
+	screen = frame()
+	if got := screen.GetLine(1); got != "CDEFGHIJKL" {
+		t.Errorf("after zl zl line 1 = %q", got)
+	}
+
+	layer.ScrollToX(99)
+	screen = frame()
+	if got := screen.GetLine(0); got != "abcdefghij" {
+		t.Errorf("clamped line 0 = %q", got)
+	}
+	if got := screen.GetLine(2); got != "─────━━━━━" {
+		t.Errorf("scrollbar at end = %q", got)
+	}
+	layer.HalfPageLeft()
+	if layer.ScrollX() != 5 {
+		t.Errorf("HalfPageLeft ScrollX = %d, want 5", layer.ScrollX())
+	}
+}
+
+func TestLayerScrollXBinding(t *testing.T) {
+	layer := NewLayer()
+	buf := NewBuffer(20, 1)
+	buf.WriteStringFast(0, 0, "0123456789abcdefghij", Style{}, 20)
+	layer.SetBuffer(buf)
+
+	offset := 3
+	tmpl := Build(VBox(LayerView(layer).ViewWidth(10).ViewHeight(1).ScrollX(&offset)))
+	frame := func() string {
+		screen := NewBuffer(10, 1)
+		tmpl.Execute(screen, 10, 1)
+		return screen.GetLine(0)
+	}
+
+	if got := frame(); got != "3456789abc" {
+		t.Errorf("bound offset 3: %q", got)
+	}
+	offset = 50
+	if got := frame(); got != "abcdefghij" || offset != 10 {
+		t.Errorf("offset past the end: %q, offset %d; want clamped to 10", got, offset)
+	}
+	layer.ScrollLeft(4)
+	if offset != 6 {
+		t.Errorf("ScrollLeft wrote back %d, want 6", offset)
+	}
+}
+
+func TestTextViewNoWrap(t *testing.T) {
+	content := "short\n\tindented line that runs well past the edge"
+	offset := 0
+	tv := TextView(&content).Grow(1).NoWrap().ScrollX(&offset).BindHScroll("zh", "zl")
+	tmpl := Build(VBox(tv))
+	frame := func() *Buffer {
+		screen := NewBuffer(12, 2)
+		tmpl.Execute(screen, 12, 2)
+		return screen
+	}
+
+	screen := frame()
+	if got := screen.GetLine(1); got != "    indented" {
+		t.Errorf("line 1 = %q, want unwrapped", got)
+	}
+	for range 4 {
+		tmpl.pendingBindings[1].handler.(func())()
+	}
+	screen = frame()
+	if got := screen.GetLine(1); got != "indented lin" || offset != 4 {
+		t.Errorf("after 4×zl line 1 = %q offset %d", got, offset)
+	}
+}
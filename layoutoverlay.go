@@ -0,0 +1,136 @@
+package glyph
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+// LayoutOverlayKey binds a key that shows and hides the layout overlay, a
+// development aid that draws the box of every component over the screen,
+// coloured by nesting depth, with a label giving its type, size, grow
+// weight, gap and margin:
+//
+//	app.LayoutOverlayKey("<C-l>")
+//
+// Boxes two or more rows tall are outlined and labelled; one-row boxes,
+// such as most Text, are underlined. Use it to see why a panel is the
+// size it is.
+func (a *App) LayoutOverlayKey(pattern string) *App {
+	a.router.Handle(pattern, func(riffkey.Match) { a.ToggleLayoutOverlay() })
+	return a
+}
+
+// ToggleLayoutOverlay shows the layout overlay if it's hidden and hides it
+// if it's shown.
+func (a *App) ToggleLayoutOverlay() {
+	a.layoutOverlay = !a.layoutOverlay
+	a.RequestRender()
+}
+
+// layoutColors tell nesting depths apart in the dev tools.
+var layoutColors = []Color{Cyan, Magenta, Yellow, Green, Blue, Red}
+
+func layoutColor(depth int) Color { return layoutColors[depth%len(layoutColors)] }
+
+// inner returns the box inside its margin, the part its content sees.
+func (b traceBox) inner() (x, y, w, h int) {
+	m := b.op.Margin
+	return int(b.x + m[3]), int(b.y + m[0]), max(int(b.w-m[1]-m[3]), 0), max(int(b.h-m[0]-m[2]), 0)
+}
+
+// describe sums a box up for the dev tools, as in
+// "VBox 38×12 grow 1 gap 1 margin 0,1,0,1". The size is inside the margin.
+func (b traceBox) describe() string {
+	op := b.op
+	var sb strings.Builder
+	sb.WriteString(b.name())
+	_, _, w, h := b.inner()
+	fmt.Fprintf(&sb, " %d×%d", w, h)
+	if op.FlexGrow > 0 {
+		fmt.Fprintf(&sb, " grow %g", op.FlexGrow)
+	}
+	if op.PercentWidth > 0 {
+		fmt.Fprintf(&sb, " %g%%", op.PercentWidth*100)
+	}
+	if op.Kind == OpContainer && op.Gap != 0 {
+		fmt.Fprintf(&sb, " gap %d", op.Gap)
+	}
+	if m := op.Margin; m != [4]int16{} {
+		fmt.Fprintf(&sb, " margin %d,%d,%d,%d", m[0], m[1], m[2], m[3])
+	}
+	return sb.String()
+}
+
+// drawLayoutOverlay outlines each traced box over buf and labels it. A
+// box whose top edge another label already holds, as a first child's
+// does, is labelled along its bottom edge instead.
+func drawLayoutOverlay(buf *Buffer, rt *renderTrace) {
+	type label struct {
+		text  string
+		x, w  int
+		edges [2]int
+		style Style
+	}
+	var labels []label
+	for _, b := range rt.boxes {
+		x, y, w, h := b.inner()
+		if w <= 0 || h <= 0 {
+			continue
+		}
+		style := Style{FG: layoutColor(b.depth)}
+		if h == 1 || w == 1 {
+			for cy := y; cy < y+h; cy++ {
+				for cx := x; cx < x+w; cx++ {
+					c := buf.Get(cx, cy)
+					c.Style.FG = style.FG
+					c.Style.Attr |= AttrUnderline
+					buf.SetFast(cx, cy, c)
+				}
+			}
+			continue
+		}
+		drawOutline(buf, x, y, w, h, style)
+		labels = append(labels, label{b.describe(), x + 1, w - 2, [2]int{y, y + h - 1}, style})
+	}
+
+	// labels go on after every outline, so none is drawn over
+	taken := map[[2]int]bool{}
+	for _, l := range labels {
+		text := Truncate(l.text, l.w)
+		n := StringWidth(text)
+		for _, y := range l.edges {
+			free := true
+			for cx := l.x; cx < l.x+n && free; cx++ {
+				free = !taken[[2]int{cx, y}]
+			}
+			if !free {
+				continue
+			}
+			for cx := l.x; cx < l.x+n; cx++ {
+				taken[[2]int{cx, y}] = true
+			}
+			buf.WriteStringFast(l.x, y, text, l.style, n)
+			break
+		}
+	}
+}
+
+// drawOutline draws a thin box over whatever's there, without merging
+// into the borders it crosses.
+func drawOutline(buf *Buffer, x, y, w, h int, style Style) {
+	b := BorderSingle
+	for cx := x + 1; cx < x+w-1; cx++ {
+		buf.SetFast(cx, y, Cell{Rune: b.Horizontal, Style: style})
+		buf.SetFast(cx, y+h-1, Cell{Rune: b.Horizontal, Style: style})
+	}
+	for cy := y + 1; cy < y+h-1; cy++ {
+		buf.SetFast(x, cy, Cell{Rune: b.Vertical, Style: style})
+		buf.SetFast(x+w-1, cy, Cell{Rune: b.Vertical, Style: style})
+	}
+	buf.SetFast(x, y, Cell{Rune: b.TopLeft, Style: style})
+	buf.SetFast(x+w-1, y, Cell{Rune: b.TopRight, Style: style})
+	buf.SetFast(x, y+h-1, Cell{Rune: b.BottomLeft, Style: style})
+	buf.SetFast(x+w-1, y+h-1, Cell{Rune: b.BottomRight, Style: style})
+}
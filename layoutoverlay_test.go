@@ -0,0 +1,47 @@
+package glyph
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLayoutOverlay(t *testing.T) {
+	app, err := NewApp()
+	if err != nil {
+		t.Fatal(err)
+	}
+	be := NewBufferBackend(60, 6)
+	app.SetBackend(be).SetView(VBox(
+		Text("title"),
+		HBox.Gap(1).Grow(1)(
+			VBox.Grow(2).MarginVH(0, 1)(Text("left")),
+			VBox.Grow(1)(Text("right")),
+		),
+	))
+
+	app.RenderNow()
+	plain := be.Frame().GetLine(1)
+	app.ToggleLayoutOverlay()
+	app.RenderNow()
+	buf := be.Frame()
+	var lines []string
+	for y := range 6 {
+		lines = append(lines, buf.GetLine(y))
+	}
+	screen := strings.Join(lines, "\n")
+	for _, want := range []string{"VBox 60×6", "HBox 60×5 grow 1 gap 1", "VBox 37×5 grow 2 margin 0,1,0,1", "VBox 20×5 grow 1"} {
+		if !strings.Contains(screen, want) {
+			t.Errorf("missing %q in\n%s", want, screen)
+		}
+	}
+	// one-row boxes are underlined
+	if c := buf.Get(0, 0); !c.Style.Attr.Has(AttrUnderline) {
+		t.Errorf("title cell %+v", c)
+	}
+
+	app.ToggleLayoutOverlay()
+	app.RenderNow()
+	if got := be.Frame().GetLine(1); got != plain {
+		t.Errorf("overlay left behind: %q, want %q", got, plain)
+	}
+}
@@ -0,0 +1,308 @@
+package glyph
+
+import (
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+)
+
+// LevelMeterC is an audio level meter: a bar per channel on a dB scale,
+// falling back smoothly between peaks, with each channel's recent peak held
+// for a moment:
+//
+//	vu := StereoMeter().Scale()
+//	app.SetView(VBox(vu))
+//	go func() {
+//	    for buf := range audio {
+//	        vu.Set(0, peak(buf.Left))
+//	        vu.Set(1, peak(buf.Right))
+//	        app.RenderNow()
+//	    }
+//	}()
+//
+// Set is safe from any goroutine and cheap enough to call per audio
+// buffer; the loudest level since the last frame is kept, so none is lost
+// however fast frames come. Each frame draws only the meter's own rows, so
+// driving it with app.RenderNow costs a row or two of output.
+type LevelMeterC struct {
+	channels []meterChannel
+	labels   []string
+
+	minDB  float64       // bottom of the scale
+	warnDB float64       // levels from here are in warnStyle
+	clipDB float64       // and from here in clipStyle
+	hold   time.Duration // how long a peak is held
+	fall   float64       // dB a second levels and peaks fall by
+
+	style      Style
+	warnStyle  Style
+	clipStyle  Style
+	emptyStyle Style
+	width      int16
+	scale      bool
+	readout    bool
+
+	update func() // set to app.RequestRender during wiring
+	now    func() time.Time
+}
+
+// meterChannel is one channel's level: the loudest amplitude set since the
+// last frame, and the level and peak as drawn.
+type meterChannel struct {
+	pending atomic.Uint64 // float64 bits
+	level   float64       // dB
+	peak    float64       // dB
+	peakAt  time.Time
+	drawnAt time.Time
+}
+
+// LevelMeter creates a meter of n channels, one row each, scaled from -60 dB
+// to 0.
+func LevelMeter(n int) *LevelMeterC {
+	m := &LevelMeterC{
+		channels:   make([]meterChannel, max(n, 1)),
+		minDB:      -60,
+		warnDB:     -12,
+		clipDB:     -3,
+		hold:       1500 * time.Millisecond,
+		fall:       24,
+		style:      Style{FG: Green},
+		warnStyle:  Style{FG: Yellow},
+		clipStyle:  Style{FG: Red},
+		emptyStyle: Style{FG: BrightBlack},
+		now:        time.Now,
+	}
+	for i := range m.channels {
+		m.channels[i].level, m.channels[i].peak = m.minDB, m.minDB
+	}
+	return m
+}
+
+// StereoMeter creates a two-channel meter labelled L and R.
+func StereoMeter() *LevelMeterC {
+	return LevelMeter(2).Labels("L", "R")
+}
+
+// Labels sets the labels drawn before each channel's bar.
+func (m *LevelMeterC) Labels(labels ...string) *LevelMeterC {
+	m.labels = labels
+	return m
+}
+
+// Range sets the dB at the bottom of the scale, -60 by default. The top is
+// always 0 dB, full scale.
+func (m *LevelMeterC) Range(minDB float64) *LevelMeterC {
+	m.minDB = min(minDB, -1)
+	return m
+}
+
+// Zones sets the levels from which the bar turns to the warning and clip
+// styles, -12 and -3 dB by default.
+func (m *LevelMeterC) Zones(warnDB, clipDB float64) *LevelMeterC {
+	m.warnDB, m.clipDB = warnDB, clipDB
+	return m
+}
+
+// Styles sets the bar's styles below the warning level, from it, and from
+// the clip level.
+func (m *LevelMeterC) Styles(normal, warn, clip Style) *LevelMeterC {
+	m.style, m.warnStyle, m.clipStyle = normal, warn, clip
+	return m
+}
+
+// Hold sets how long peaks are held before falling, 1.5s by default; 0
+// turns peak hold off.
+func (m *LevelMeterC) Hold(d time.Duration) *LevelMeterC {
+	m.hold = d
+	return m
+}
+
+// Fall sets how fast levels and released peaks fall, in dB a second. 24 by
+// default.
+func (m *LevelMeterC) Fall(dBPerSecond float64) *LevelMeterC {
+	m.fall = dBPerSecond
+	return m
+}
+
+// Width sets a fixed width; by default the meter fills the width it's
+// given.
+func (m *LevelMeterC) Width(w int16) *LevelMeterC {
+	m.width = w
+	return m
+}
+
+// Scale adds a row of dB marks beneath the bars.
+func (m *LevelMeterC) Scale() *LevelMeterC {
+	m.scale = true
+	return m
+}
+
+// Readout shows each channel's held peak in dB after its bar.
+func (m *LevelMeterC) Readout() *LevelMeterC {
+	m.readout = true
+	return m
+}
+
+// Set records a channel's level as a linear amplitude, 1 being full scale,
+// such as the largest absolute sample in a buffer. Safe to call from any
+// goroutine.
+func (m *LevelMeterC) Set(ch int, amplitude float64) {
+	if ch < 0 || ch >= len(m.channels) {
+		return
+	}
+	p := &m.channels[ch].pending
+	amplitude = math.Abs(amplitude)
+	for {
+		old := p.Load()
+		if amplitude <= math.Float64frombits(old) || p.CompareAndSwap(old, math.Float64bits(amplitude)) {
+			break
+		}
+	}
+	if m.update != nil {
+		m.update()
+	}
+}
+
+// SetDB records a channel's level in dB relative to full scale.
+func (m *LevelMeterC) SetDB(ch int, db float64) {
+	m.Set(ch, math.Pow(10, db/20))
+}
+
+// Level returns a channel's level and held peak in dB, as last drawn.
+func (m *LevelMeterC) Level(ch int) (level, peak float64) {
+	c := &m.channels[ch]
+	return c.level, c.peak
+}
+
+func (m *LevelMeterC) updateHook() *func() { return &m.update }
+
+// advance takes the loudest level set since the last frame, lets the level
+// and peak fall by the time since, and catches the peak up.
+func (m *LevelMeterC) advance(c *meterChannel, now time.Time) {
+	dt := 0.0
+	if !c.drawnAt.IsZero() {
+		dt = now.Sub(c.drawnAt).Seconds()
+	}
+	c.drawnAt = now
+	db := m.minDB
+	if a := math.Float64frombits(c.pending.Swap(0)); a > 0 {
+		db = max(20*math.Log10(a), m.minDB)
+	}
+	c.level = max(db, c.level-m.fall*dt, m.minDB)
+	if c.level >= c.peak {
+		c.peak, c.peakAt = c.level, now
+	} else if over := now.Sub(c.peakAt) - m.hold; over > 0 {
+		// fall only for the time since the hold ran out
+		c.peak = max(c.peak-m.fall*min(dt, over.Seconds()), c.level)
+	}
+}
+
+// pos returns where a level falls along a bar of width cells, in eighths.
+func (m *LevelMeterC) pos(db float64, width int) int {
+	f := (db - m.minDB) / -m.minDB
+	return int(math.Round(min(max(f, 0), 1) * float64(width*8)))
+}
+
+// zoneStyle returns the style of the bar at a level.
+func (m *LevelMeterC) zoneStyle(db float64) Style {
+	switch {
+	case db >= m.clipDB:
+		return m.clipStyle
+	case db >= m.warnDB:
+		return m.warnStyle
+	}
+	return m.style
+}
+
+func (m *LevelMeterC) measure(availW int16) (int16, int16) {
+	h := int16(len(m.channels))
+	if m.scale {
+		h++
+	}
+	if m.width > 0 {
+		return m.width, h
+	}
+	return -1, h
+}
+
+func (m *LevelMeterC) render(buf *Buffer, x, y, w, h int16) {
+	now := m.now()
+	labelW := 0
+	for _, l := range m.labels {
+		labelW = max(labelW, StringWidth(l)+1)
+	}
+	readoutW := 0
+	if m.readout {
+		readoutW = 7
+	}
+	barX := int(x) + labelW
+	barW := int(w) - labelW - readoutW
+	if barW <= 0 {
+		return
+	}
+	for i := range m.channels {
+		if i >= int(h) {
+			break
+		}
+		c := &m.channels[i]
+		m.advance(c, now)
+		row := int(y) + i
+		if i < len(m.labels) {
+			buf.WriteStringFast(int(x), row, m.labels[i], Style{}, labelW)
+		}
+		level := m.pos(c.level, barW)
+		peak := -1
+		if m.hold > 0 && c.peak > m.minDB {
+			peak = min(m.pos(c.peak, barW)/8, barW-1)
+		}
+		for cell := range barW {
+			style := m.zoneStyle(m.minDB + (float64(cell)+0.5)/float64(barW)*-m.minDB)
+			r := '█'
+			switch eighths := level - cell*8; {
+			case cell == peak && eighths < 8:
+				r = '▐'
+			case eighths <= 0:
+				r, style = '─', m.emptyStyle
+			case eighths < 8:
+				r = partialBlocks[eighths]
+			}
+			buf.Set(barX+cell, row, Cell{Rune: r, Style: style})
+		}
+		if m.readout {
+			text := "  -inf"
+			if c.peak > m.minDB {
+				text = fmt.Sprintf("%6.1f", c.peak)
+			}
+			buf.WriteStringFast(barX+barW, row, " "+text, m.zoneStyle(c.peak), readoutW)
+		}
+	}
+	if m.scale && len(m.channels) < int(h) {
+		m.drawScale(buf, barX, int(y)+len(m.channels), barW)
+	}
+}
+
+// drawScale marks the bar's dB levels beneath it, as many as fit, from
+// 0 dB down.
+func (m *LevelMeterC) drawScale(buf *Buffer, x, y, width int) {
+	dim := Style{FG: BrightBlack}
+	next := width + 1 // first cell written, to keep marks a cell apart
+	for _, db := range []float64{0, -3, -6, -12, -20, -30, -40, -50, -60} {
+		if db < m.minDB {
+			break
+		}
+		label := fmt.Sprint(db)
+		at := min(m.pos(db, width)/8, width-1)
+		at = max(min(at-len(label)/2, width-len(label)), 0)
+		if at+len(label) >= next {
+			continue
+		}
+		buf.WriteStringFast(x+at, y, label, dim, len(label))
+		next = at
+	}
+}
+
+func (t *Template) compileLevelMeterC(v *LevelMeterC, parent int16, depth int) int16 {
+	t.collectUpdateHook(v)
+	return t.compileCustom(Custom{Measure: v.measure, Render: v.render}, parent, depth)
+}
@@ -0,0 +1,55 @@
+package glyph
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLevelMeter(t *testing.T) {
+	now := time.Unix(0, 0)
+	m := StereoMeter().Scale().Readout()
+	m.now = func() time.Time { return now }
+	renders := 0
+	tmpl := Build(VBox(m))
+	m.update = func() { renders++ }
+	frame := func() *Buffer {
+		buf := NewBuffer(40, 3)
+		tmpl.Execute(buf, 40, 3)
+		return buf
+	}
+
+	m.SetDB(0, -30)
+	m.SetDB(0, -6) // the loudest since the last frame counts
+	m.SetDB(1, -40)
+	buf := frame()
+	if renders != 3 {
+		t.Errorf("renders requested = %d, want 3", renders)
+	}
+	// bars are 40-2-7 = 31 cells across 60 dB
+	if got := buf.GetLine(0); !strings.HasPrefix(got, "L █") || !strings.HasSuffix(got, "  -6.0") {
+		t.Errorf("left = %q", got)
+	}
+	if c := buf.Get(2+27, 0); c.Style != m.warnStyle {
+		t.Errorf("-6 dB drawn in %+v, want the warning style", c.Style)
+	}
+	if got := buf.GetLine(1); !strings.Contains(got, "─") || !strings.HasSuffix(got, " -40.0") {
+		t.Errorf("right = %q", got)
+	}
+	if got := buf.GetLine(2); !strings.HasPrefix(got, "  -60") || !strings.HasSuffix(got, "0") {
+		t.Errorf("scale = %q", got)
+	}
+
+	// silence: the level falls at 24 dB/s, the peak holds
+	now = now.Add(500 * time.Millisecond)
+	frame()
+	if level, peak := m.Level(0); math.Abs(level+18) > 1e-9 || math.Abs(peak+6) > 1e-9 {
+		t.Errorf("after 0.5s: level %v, peak %v", level, peak)
+	}
+	now = now.Add(time.Second + 250*time.Millisecond)
+	frame()
+	if level, peak := m.Level(0); math.Abs(level+48) > 1e-9 || math.Abs(peak+12) > 1e-9 {
+		t.Errorf("after the hold: level %v, peak %v", level, peak)
+	}
+}
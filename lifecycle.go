@@ -0,0 +1,129 @@
+package glyph
+
+import (
+	"context"
+	"unsafe"
+)
+
+// LifecycleC runs callbacks when its child starts and stops being rendered:
+// shown by an If, Switch or view change, or removed from a keyed ForEach.
+//
+//	Lifecycle(statsPanel).
+//	    OnMount(func() { stats.Subscribe() }).
+//	    OnUnmount(func() { stats.Unsubscribe() }).
+//	    Go(func(ctx context.Context) { pollStats(ctx) })
+//
+// Callbacks run on the render goroutine right after the frame that mounted
+// or unmounted the child, so they must not call RenderNow. Every mounted
+// child is unmounted when the app stops. In an unkeyed ForEach the child is
+// mounted while at least one item is visible.
+type LifecycleC struct {
+	child     any
+	onMount   []func()
+	onUnmount []func()
+	goFns     []func(ctx context.Context)
+}
+
+// Lifecycle wraps child with mount and unmount callbacks.
+func Lifecycle(child any) LifecycleC {
+	return LifecycleC{child: child}
+}
+
+// OnMount adds a callback run each time the child appears.
+func (l LifecycleC) OnMount(fn func()) LifecycleC {
+	l.onMount = append(l.onMount[:len(l.onMount):len(l.onMount)], fn)
+	return l
+}
+
+// OnUnmount adds a callback run each time the child disappears.
+func (l LifecycleC) OnUnmount(fn func()) LifecycleC {
+	l.onUnmount = append(l.onUnmount[:len(l.onUnmount):len(l.onUnmount)], fn)
+	return l
+}
+
+// Go starts fn on its own goroutine each time the child appears. Its
+// context is cancelled when the child disappears.
+func (l LifecycleC) Go(fn func(ctx context.Context)) LifecycleC {
+	l.goFns = append(l.goFns[:len(l.goFns):len(l.goFns)], fn)
+	return l
+}
+
+func (t *Template) compileLifecycleC(v LifecycleC, parent int16, depth int, elemBase unsafe.Pointer, elemSize uintptr) int16 {
+	idx := t.compile(v.child, parent, depth, elemBase, elemSize)
+	if idx < 0 || int(idx) >= len(t.ops) {
+		return idx
+	}
+	life := &lifecycle{onMount: v.onMount, onUnmount: v.onUnmount, goFns: v.goFns}
+	t.ops[idx].life = life
+	t.mounts = append(t.mounts, life)
+	return idx
+}
+
+// mounter is anything whose mounted state is settled after each frame.
+type mounter interface {
+	settle()  // mount or unmount based on what the frame rendered
+	unmount() // unmount unconditionally
+}
+
+// lifecycle is the runtime state of one LifecycleC.
+type lifecycle struct {
+	onMount   []func()
+	onUnmount []func()
+	goFns     []func(ctx context.Context)
+
+	seen    bool // rendered this frame
+	mounted bool
+	cancel  context.CancelFunc
+}
+
+func (l *lifecycle) settle() {
+	switch {
+	case l.seen && !l.mounted:
+		l.mount()
+	case !l.seen && l.mounted:
+		l.unmount()
+	}
+	l.seen = false
+}
+
+func (l *lifecycle) mount() {
+	l.mounted = true
+	for _, fn := range l.onMount {
+		fn()
+	}
+	if len(l.goFns) > 0 {
+		ctx, cancel := context.WithCancel(context.Background())
+		l.cancel = cancel
+		for _, fn := range l.goFns {
+			go fn(ctx)
+		}
+	}
+}
+
+func (l *lifecycle) unmount() {
+	if !l.mounted {
+		return
+	}
+	l.mounted = false
+	if l.cancel != nil {
+		l.cancel()
+		l.cancel = nil
+	}
+	for _, fn := range l.onUnmount {
+		fn()
+	}
+}
+
+// settleMounts fires mount and unmount callbacks for the frame just drawn.
+func (t *Template) settleMounts() {
+	for _, m := range t.mounts {
+		m.settle()
+	}
+}
+
+// unmountAll unmounts everything in t, e.g. when its view is replaced.
+func (t *Template) unmountAll() {
+	for _, m := range t.mounts {
+		m.unmount()
+	}
+}
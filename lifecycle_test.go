@@ -0,0 +1,103 @@
+package glyph
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLifecycleIf(t *testing.T) {
+	show := false
+	mounts, unmounts := 0, 0
+	tmpl := Build(VBox(
+		If(&show).Then(Lifecycle(Text("panel")).
+			OnMount(func() { mounts++ }).
+			OnUnmount(func() { unmounts++ })),
+	))
+	frame := func() { tmpl.Execute(NewBuffer(10, 3), 10, 3) }
+
+	frame()
+	if mounts != 0 {
+		t.Fatalf("hidden child mounted %d times", mounts)
+	}
+
+	show = true
+	frame()
+	frame()
+	if mounts != 1 || unmounts != 0 {
+		t.Errorf("after show: mounts=%d unmounts=%d, want 1 0", mounts, unmounts)
+	}
+
+	show = false
+	frame()
+	if unmounts != 1 {
+		t.Errorf("after hide: unmounts=%d, want 1", unmounts)
+	}
+
+	show = true
+	frame()
+	if mounts != 2 {
+		t.Errorf("after re-show: mounts=%d, want 2", mounts)
+	}
+
+	tmpl.unmountAll()
+	if unmounts != 2 {
+		t.Errorf("after unmountAll: unmounts=%d, want 2", unmounts)
+	}
+}
+
+func TestLifecycleGoCancelledOnUnmount(t *testing.T) {
+	show := true
+	started := make(chan struct{})
+	stopped := make(chan struct{})
+	tmpl := Build(VBox(
+		If(&show).Then(Lifecycle(Text("ticker")).Go(func(ctx context.Context) {
+			close(started)
+			<-ctx.Done()
+			close(stopped)
+		})),
+	))
+
+	tmpl.Execute(NewBuffer(10, 2), 10, 2)
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("goroutine not started on mount")
+	}
+
+	show = false
+	tmpl.Execute(NewBuffer(10, 2), 10, 2)
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("goroutine not cancelled on unmount")
+	}
+}
+
+func TestLifecycleKeyedForEach(t *testing.T) {
+	items := []string{"a", "b", "c"}
+	mounted := map[string]int{}
+	tmpl := Build(VBox(
+		KeyBy(ForEach(&items, func(s *string) any {
+			name := *s
+			return Lifecycle(Text(s)).
+				OnMount(func() { mounted[name]++ }).
+				OnUnmount(func() { mounted[name]-- })
+		}), func(s *string) string { return *s }),
+	))
+	frame := func() { tmpl.Execute(NewBuffer(10, 4), 10, 4) }
+
+	frame()
+	for _, k := range items {
+		if mounted[k] != 1 {
+			t.Errorf("%s mounted = %d, want 1", k, mounted[k])
+		}
+	}
+
+	// reordering doesn't remount; removal unmounts only the removed item
+	items = []string{"c", "a"}
+	frame()
+	if mounted["a"] != 1 || mounted["c"] != 1 || mounted["b"] != 0 {
+		t.Errorf("after removal mounted = %v, want a:1 b:0 c:1", mounted)
+	}
+}
@@ -1,6 +1,7 @@
 package glyph
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 )
@@ -177,6 +178,50 @@ func TestListCOnSelect(t *testing.T) {
 	}
 }
 
+func TestListCVisibility(t *testing.T) {
+	items := make([]TestItem, 10)
+	var list *ListC[TestItem]
+	var shown, hidden []int
+
+	tmpl := Build(VBox(List(&items).MaxVisible(3).
+		OnVisible(func(i int) { shown = append(shown, i) }).
+		OnHidden(func(i int) { hidden = append(hidden, i) }).
+		Ref(func(l *ListC[TestItem]) { list = l })))
+	frame := func() {
+		buf := NewBuffer(20, 10)
+		tmpl.Execute(buf, 20, 10)
+	}
+
+	frame()
+	if fmt.Sprint(shown) != "[0 1 2]" || len(hidden) != 0 {
+		t.Fatalf("first render shown=%v hidden=%v, want [0 1 2] []", shown, hidden)
+	}
+
+	// moving within the window changes nothing
+	shown = nil
+	list.Down(nil)
+	list.Down(nil)
+	frame()
+	if len(shown) != 0 || len(hidden) != 0 {
+		t.Errorf("no scroll: shown=%v hidden=%v", shown, hidden)
+	}
+
+	// scrolling one row swaps the edges
+	list.Down(nil)
+	frame()
+	if fmt.Sprint(shown) != "[3]" || fmt.Sprint(hidden) != "[0]" {
+		t.Errorf("scroll by one: shown=%v hidden=%v, want [3] [0]", shown, hidden)
+	}
+
+	// emptying the list hides what was left
+	shown, hidden = nil, nil
+	items = items[:0]
+	frame()
+	if fmt.Sprint(hidden) != "[1 2 3]" {
+		t.Errorf("emptied: hidden=%v, want [1 2 3]", hidden)
+	}
+}
+
 func TestListCDelete(t *testing.T) {
 	items := []TestItem{
 		{Name: "First", Done: false},
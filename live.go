@@ -0,0 +1,140 @@
+package glyph
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// Live sources let Text, Progress and ForEach bind to values owned by other
+// goroutines without a mutex. A source is copied into a template-owned value
+// at the start of every frame, on the render goroutine, so layout and render
+// only ever see a stable snapshot.
+//
+// Channels are pumped by a goroutine that keeps the latest value and requests
+// a render on each receive; older values that arrive between frames are
+// dropped. Atomic values are loaded once per frame; call app.RequestRender
+// after storing to them.
+type liveSource interface {
+	drain()
+}
+
+// liveChan keeps the most recent value received from ch.
+type liveChan[T any] struct {
+	ch     <-chan T
+	dst    *T
+	latest atomic.Pointer[T]
+	start  sync.Once
+	update func() // set to app.RequestRender during wiring
+}
+
+func (l *liveChan[T]) updateHook() *func() { return &l.update }
+
+func (l *liveChan[T]) drain() {
+	// start pumping on the first frame, once wiring has set update
+	l.start.Do(func() { go l.pump(l.update) })
+	if v := l.latest.Swap(nil); v != nil {
+		*l.dst = *v
+	}
+}
+
+func (l *liveChan[T]) pump(notify func()) {
+	for v := range l.ch {
+		l.latest.Store(&v)
+		if notify != nil {
+			notify()
+		}
+	}
+}
+
+// liveLoad copies the result of load into dst every frame.
+type liveLoad[T any] struct {
+	load func() (T, bool)
+	dst  *T
+}
+
+func (l *liveLoad[T]) drain() {
+	if v, ok := l.load(); ok {
+		*l.dst = v
+	}
+}
+
+// liveValue builds a source for v if it is a channel of T, an *atomic.Value
+// holding T, or an *atomic.Pointer[T]. It returns the template-owned value
+// the compiled op should read from.
+func liveValue[T any](v any) (*T, liveSource, bool) {
+	dst := new(T)
+	switch src := v.(type) {
+	case chan T:
+		return dst, &liveChan[T]{ch: src, dst: dst}, true
+	case <-chan T:
+		return dst, &liveChan[T]{ch: src, dst: dst}, true
+	case *atomic.Value:
+		return dst, &liveLoad[T]{dst: dst, load: func() (T, bool) {
+			x, ok := src.Load().(T)
+			return x, ok
+		}}, true
+	case *atomic.Pointer[T]:
+		return dst, &liveLoad[T]{dst: dst, load: func() (T, bool) {
+			if p := src.Load(); p != nil {
+				return *p, true
+			}
+			var zero T
+			return zero, false
+		}}, true
+	}
+	return nil, nil, false
+}
+
+// liveInt is liveValue for progress bars, which also accept the sized
+// atomic integer types.
+func liveInt(v any) (*int, liveSource, bool) {
+	dst := new(int)
+	switch src := v.(type) {
+	case *atomic.Int64:
+		return dst, &liveLoad[int]{dst: dst, load: func() (int, bool) { return int(src.Load()), true }}, true
+	case *atomic.Int32:
+		return dst, &liveLoad[int]{dst: dst, load: func() (int, bool) { return int(src.Load()), true }}, true
+	}
+	return liveValue[int](v)
+}
+
+// addLive registers a source to be drained before each frame.
+func (t *Template) addLive(src liveSource) {
+	t.live = append(t.live, src)
+	t.collectUpdateHook(src)
+}
+
+// adoptLive bubbles live sources and lifecycle hooks up from a
+// sub-template, which is never executed on its own.
+func (t *Template) adoptLive(sub *Template) {
+	t.live = append(t.live, sub.live...)
+	t.pendingUpdates = append(t.pendingUpdates, sub.pendingUpdates...)
+	t.mounts = append(t.mounts, sub.mounts...)
+	t.clockUsers = append(t.clockUsers, sub.clockUsers...)
+	t.qualityUsers = append(t.qualityUsers, sub.qualityUsers...)
+	t.pendingModals = append(t.pendingModals, sub.pendingModals...)
+}
+
+// drainLive snapshots every live source. Called at the start of Execute.
+func (t *Template) drainLive() {
+	for _, src := range t.live {
+		src.drain()
+	}
+}
+
+// ForEachFrom renders a template for each item of a slice published by
+// another goroutine. src is a <-chan []T, an *atomic.Value holding a []T or
+// an *atomic.Pointer[[]T]. The publisher must not modify a slice after
+// sending or storing it.
+//
+//	results := make(chan []Result, 1)
+//	go func() { results <- search(query) }()
+//	ForEachFrom(results, func(r *Result) any { return Text(&r.Title) })
+func ForEachFrom[T any](src any, template func(item *T) any) ForEachC[T] {
+	items, live, ok := liveValue[[]T](src)
+	if !ok {
+		panic(fmt.Sprintf("ForEachFrom: unsupported source %T", src))
+	}
+	return ForEachC[T]{items: items, template: template, live: live}
+}
@@ -0,0 +1,161 @@
+package glyph
+
+import (
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// wireLive points every update hook at a channel so tests can wait for the
+// pump goroutine, the way App.wireBindings points them at RequestRender.
+func wireLive(tmpl *Template) <-chan struct{} {
+	updated := make(chan struct{}, 16)
+	for _, hook := range tmpl.pendingUpdates {
+		*hook = func() { updated <- struct{}{} }
+	}
+	return updated
+}
+
+func waitUpdate(t *testing.T, updated <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-updated:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for live update")
+	}
+}
+
+func TestLiveTextChannel(t *testing.T) {
+	status := make(chan string)
+	tmpl := Build(VBox(Text(status)))
+	updated := wireLive(tmpl)
+
+	buf := NewBuffer(20, 2)
+	tmpl.Execute(buf, 20, 2) // starts the pump
+
+	status <- "loading"
+	waitUpdate(t, updated)
+	buf.Clear()
+	tmpl.Execute(buf, 20, 2)
+	if got := strings.TrimSpace(buf.GetLine(0)); got != "loading" {
+		t.Errorf("line 0 = %q, want loading", got)
+	}
+
+	// values that arrive between frames collapse to the latest
+	status <- "half"
+	waitUpdate(t, updated)
+	status <- "ready"
+	waitUpdate(t, updated)
+	buf.Clear()
+	tmpl.Execute(buf, 20, 2)
+	if got := strings.TrimSpace(buf.GetLine(0)); got != "ready" {
+		t.Errorf("line 0 = %q, want ready", got)
+	}
+
+	// closing keeps the last value
+	close(status)
+	buf.Clear()
+	tmpl.Execute(buf, 20, 2)
+	if got := strings.TrimSpace(buf.GetLine(0)); got != "ready" {
+		t.Errorf("after close line 0 = %q, want ready", got)
+	}
+}
+
+func TestLiveTextAtomicValue(t *testing.T) {
+	var v atomic.Value
+	tmpl := Build(VBox(Text(&v)))
+	buf := NewBuffer(20, 2)
+
+	tmpl.Execute(buf, 20, 2)
+	if got := strings.TrimSpace(buf.GetLine(0)); got != "" {
+		t.Errorf("empty atomic rendered %q", got)
+	}
+
+	v.Store("hello")
+	buf.Clear()
+	tmpl.Execute(buf, 20, 2)
+	if got := strings.TrimSpace(buf.GetLine(0)); got != "hello" {
+		t.Errorf("line 0 = %q, want hello", got)
+	}
+}
+
+func TestLiveProgressAtomicInt(t *testing.T) {
+	var pct atomic.Int64
+	tmpl := Build(VBox(Progress(&pct).Width(10)))
+	buf := NewBuffer(20, 2)
+
+	pct.Store(50)
+	tmpl.Execute(buf, 20, 2)
+	if got := strings.Count(buf.GetLine(0), "█"); got != 5 {
+		t.Errorf("filled = %d, want 5 (%q)", got, buf.GetLine(0))
+	}
+
+	pct.Store(100)
+	buf.Clear()
+	tmpl.Execute(buf, 20, 2)
+	if got := strings.Count(buf.GetLine(0), "█"); got != 10 {
+		t.Errorf("filled = %d, want 10 (%q)", got, buf.GetLine(0))
+	}
+}
+
+func TestLiveForEachFrom(t *testing.T) {
+	type row struct{ Name string }
+	var rows atomic.Pointer[[]row]
+	tmpl := Build(VBox(ForEachFrom[row](&rows, func(r *row) any { return Text(&r.Name) })))
+	buf := NewBuffer(20, 4)
+
+	rows.Store(&[]row{{"alpha"}, {"beta"}})
+	tmpl.Execute(buf, 20, 4)
+	if got := strings.TrimSpace(buf.GetLine(1)); got != "beta" {
+		t.Errorf("line 1 = %q, want beta", got)
+	}
+
+	rows.Store(&[]row{{"gamma"}})
+	buf.Clear()
+	tmpl.Execute(buf, 20, 4)
+	if got := strings.TrimSpace(buf.GetLine(0)); got != "gamma" {
+		t.Errorf("line 0 = %q, want gamma", got)
+	}
+	if got := strings.TrimSpace(buf.GetLine(1)); got != "" {
+		t.Errorf("line 1 = %q, want empty", got)
+	}
+}
+
+func TestLiveForEachFromChannel(t *testing.T) {
+	results := make(chan []string, 1)
+	tmpl := Build(VBox(ForEachFrom[string](results, func(s *string) any { return Text(s) })))
+	updated := wireLive(tmpl)
+	buf := NewBuffer(20, 4)
+
+	results <- []string{"one", "two"}
+	tmpl.Execute(buf, 20, 4)
+	waitUpdate(t, updated)
+	buf.Clear()
+	tmpl.Execute(buf, 20, 4)
+	if got := strings.TrimSpace(buf.GetLine(1)); got != "two" {
+		t.Errorf("line 1 = %q, want two", got)
+	}
+}
+
+func TestLiveInsideCondition(t *testing.T) {
+	show := true
+	var v atomic.Value
+	v.Store("nested")
+	tmpl := Build(VBox(If(&show).Then(Text(&v))))
+	buf := NewBuffer(20, 2)
+
+	tmpl.Execute(buf, 20, 2)
+	if got := strings.TrimSpace(buf.GetLine(0)); got != "nested" {
+		t.Errorf("line 0 = %q, want nested", got)
+	}
+}
+
+func TestForEachFromUnsupported(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic for unsupported source")
+		}
+	}()
+	ForEachFrom[int](42, func(*int) any { return nil })
+}
@@ -3,6 +3,8 @@ package glyph
 import (
 	"bufio"
 	"io"
+	"slices"
+	"strings"
 	"sync"
 )
 
@@ -29,6 +31,47 @@ type LogC struct {
 	started      sync.Once
 	following    bool // true = auto-scroll active, false = user scrolled away
 	newLineCount int  // lines arrived while not following (for "X new lines" indicator)
+
+	// spilling: lines live in scrollback and only the visible ones are drawn
+	spill      bool
+	spillDir   string
+	scrollback *Scrollback
+	top        int // first visible line
+	page       logPage
+
+	// filtering
+	filterExpr *string // bound expression, re-read each frame
+	filterText string  // the expression filter was parsed from
+	filter     *LogFilter
+	filterErr  error
+	matchStyle Style
+	view       []string // the lines shown when filtering without spilling
+
+	// columns: lines drawn as aligned fields
+	columns   []string // nil for lines as they are, empty to pick
+	colWidths map[string]int
+	fields    []string // fields seen, in order
+	fieldSeen map[string]bool
+	sawLevel  bool
+	rows      map[string]logRow // lines as drawn in columns
+
+	// selection and the detail pane
+	selecting     bool
+	cursor        int // selected row on screen
+	highlighted   int // buffer row drawn selected when not spilling, -1 for none
+	pageLines     []string
+	selected      string
+	selectedStyle Style
+	detail        *StructuredViewC
+	detailOpen    bool
+}
+
+// logPage is what a spilling log last drew, to skip drawing it again.
+type logPage struct {
+	lines, top, w, h int
+	following        bool
+	filter           *LogFilter
+	cursor           int
 }
 
 // Log creates a log that reads lines from the given reader.
@@ -40,6 +83,10 @@ func Log(r io.Reader) *LogC {
 		autoScroll: true,
 		layer:      NewLayer(),
 		following:  true, // start following new content
+		matchStyle: Style{FG: Yellow, Attr: AttrBold},
+
+		highlighted:   -1,
+		selectedStyle: Style{Attr: AttrInverse},
 	}
 }
 
@@ -50,6 +97,49 @@ func (lv *LogC) MaxLines(n int) *LogC {
 	return lv
 }
 
+// Spill keeps lines beyond MaxLines in a temporary file in dir,
+// os.TempDir() if empty, rather than dropping them, so scrollback is
+// limited by disk rather than memory. MaxLines becomes how many stay in
+// memory. Only the visible lines are drawn, so the view's layer scrolls
+// just the page on screen: scroll with the Bind methods, find lines with
+// Find, and give the log a Grow, as it has no natural height.
+func (lv *LogC) Spill(dir string) *LogC {
+	lv.spill, lv.spillDir = true, dir
+	return lv
+}
+
+// Filter shows only the lines matching *expr, a LogFilter expression such
+// as `level>=WARN && msg~"timeout"`, highlighting the text that matched.
+// It's read each frame, so binding it to an input filters as the user
+// types. While it doesn't parse, the last expression that did stays in
+// use and FilterErr says what's wrong.
+func (lv *LogC) Filter(expr *string) *LogC {
+	lv.filterExpr = expr
+	return lv
+}
+
+// FilterErr returns why the filter expression doesn't parse, or nil.
+func (lv *LogC) FilterErr() error {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	return lv.filterErr
+}
+
+// MatchStyle sets how text matching the filter is highlighted, bold
+// yellow by default.
+func (lv *LogC) MatchStyle(s Style) *LogC {
+	lv.matchStyle = s
+	return lv
+}
+
+// Scrollback returns the store behind a spilling log, nil until it starts
+// reading or if Spill wasn't called.
+func (lv *LogC) Scrollback() *Scrollback {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	return lv.scrollback
+}
+
 // AutoScroll controls whether the view automatically scrolls to show new lines.
 // Default is true. When the user scrolls up, auto-scroll pauses until they
 // return to the bottom.
@@ -108,10 +198,100 @@ func (lv *LogC) resume() {
 	defer lv.mu.Unlock()
 	lv.following = true
 	lv.newLineCount = 0
+	if lv.scrollback != nil {
+		return // the next render shows the end
+	}
 	lv.syncToLayer()
 	lv.layer.ScrollToEnd()
 }
 
+// scroll moves the view down n lines, or up for a negative n. Scrolling up
+// stops following.
+func (lv *LogC) scroll(n int) {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	lv.scrollLocked(n)
+}
+
+func (lv *LogC) scrollLocked(n int) {
+	if n < 0 {
+		lv.following = false
+	}
+	if lv.scrollback == nil {
+		lv.layer.ScrollDown(n)
+		return
+	}
+	if lv.filter.Empty() {
+		lv.top = max(lv.top+n, 0) // the render clamps the bottom
+		return
+	}
+	// filtered, step from match to match
+	step := 1
+	if n < 0 {
+		n, step = -n, -1
+	}
+	for ; n > 0; n-- {
+		at := lv.scrollback.Find(lv.top+step, step < 0, lv.filter.Match)
+		if at < 0 {
+			break
+		}
+		lv.top = at
+	}
+}
+
+func (lv *LogC) scrollToTop() {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	lv.following = false
+	lv.top = 0
+	lv.layer.ScrollToTop()
+}
+
+// Find scrolls to the next line after the top of the view containing
+// substr, or the previous one if backward, and reports whether there was
+// one. It searches spilled lines too, and only the lines the filter shows.
+func (lv *LogC) Find(substr string, backward bool) bool {
+	lv.mu.Lock()
+	filter := lv.filter
+	lv.mu.Unlock()
+	match := func(line string) bool { return strings.Contains(line, substr) && filter.Match(line) }
+	if lv.scrollback == nil {
+		lv.mu.Lock()
+		defer lv.mu.Unlock()
+		lines := lv.shown()
+		at := lv.layer.ScrollY()
+		if backward {
+			for at--; at >= 0 && !match(lines[at]); at-- {
+			}
+		} else {
+			for at++; at < len(lines) && !match(lines[at]); at++ {
+			}
+		}
+		if at < 0 || at >= len(lines) {
+			return false
+		}
+		lv.following = false
+		lv.layer.ScrollTo(at)
+		return true
+	}
+	lv.mu.Lock()
+	from := lv.top + 1
+	if backward {
+		from = lv.top - 1
+	}
+	lv.mu.Unlock()
+	// searching a big file takes a while; don't hold up the reader for it
+	at := lv.scrollback.Find(from, backward, match)
+	if at < 0 {
+		return false
+	}
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	lv.following = false
+	lv.top = at
+	return true
+}
+
 // OnUpdate sets a callback to be called when new lines arrive.
 // Use this with app.RequestRender to trigger redraws:
 //
@@ -121,20 +301,31 @@ func (lv *LogC) OnUpdate(f func()) *LogC {
 	return lv
 }
 
-// BindNav registers key bindings for scrolling down/up by one line.
+// BindNav registers key bindings for scrolling down/up by one line, or
+// moving the selection when BindDetail is in use.
 func (lv *LogC) BindNav(down, up string) *LogC {
 	lv.declaredBindings = append(lv.declaredBindings,
-		binding{down, func() { lv.layer.ScrollDown(1) }},
-		binding{up, func() { lv.following = false; lv.layer.ScrollUp(1) }},
+		binding{down, func() { lv.nav(1) }},
+		binding{up, func() { lv.nav(-1) }},
 	)
 	return lv
 }
 
+func (lv *LogC) nav(n int) {
+	if !lv.selecting {
+		lv.scroll(n)
+		return
+	}
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	lv.moveCursor(n)
+}
+
 // BindPageNav registers key bindings for half-page scrolling.
 func (lv *LogC) BindPageNav(down, up string) *LogC {
 	lv.declaredBindings = append(lv.declaredBindings,
-		binding{down, func() { lv.layer.HalfPageDown() }},
-		binding{up, func() { lv.following = false; lv.layer.HalfPageUp() }},
+		binding{down, func() { lv.scroll(lv.layer.ViewportHeight() / 2) }},
+		binding{up, func() { lv.scroll(-lv.layer.ViewportHeight() / 2) }},
 	)
 	return lv
 }
@@ -142,7 +333,7 @@ func (lv *LogC) BindPageNav(down, up string) *LogC {
 // BindFirstLast registers key bindings for jumping to top/bottom.
 func (lv *LogC) BindFirstLast(first, last string) *LogC {
 	lv.declaredBindings = append(lv.declaredBindings,
-		binding{first, func() { lv.following = false; lv.layer.ScrollToTop() }},
+		binding{first, lv.scrollToTop},
 		binding{last, func() { lv.resume() }},
 	)
 	return lv
@@ -162,6 +353,13 @@ func (lv *LogC) bindings() []binding {
 // start begins reading from the reader in a background goroutine.
 // Called once via sync.Once when the component is first compiled.
 func (lv *LogC) start() {
+	if lv.spill {
+		lv.scrollback = NewScrollback(lv.maxLines, lv.spillDir)
+	}
+	if lv.spill || lv.filterExpr != nil || lv.selecting {
+		lv.layer.AlwaysRender = true
+		lv.layer.Render = lv.refresh
+	}
 	go lv.readLoop()
 }
 
@@ -177,6 +375,25 @@ func (lv *LogC) readLoop() {
 	for scanner.Scan() {
 		line := scanner.Text()
 
+		if lv.columns != nil {
+			lv.mu.Lock()
+			lv.noteFields(line)
+			lv.mu.Unlock()
+		}
+
+		if lv.scrollback != nil {
+			lv.scrollback.Append(line)
+			lv.mu.Lock()
+			if !lv.following {
+				lv.newLineCount++
+			}
+			lv.mu.Unlock()
+			if lv.onUpdate != nil {
+				lv.onUpdate()
+			}
+			continue
+		}
+
 		lv.mu.Lock()
 
 		lv.lines = append(lv.lines, line)
@@ -218,19 +435,181 @@ func (lv *LogC) readLoop() {
 	}
 }
 
-// syncToLayer writes all buffered lines to the layer's buffer.
+// syncToLayer writes all buffered lines, or those matching the filter, to
+// the layer's buffer.
 func (lv *LogC) syncToLayer() {
 	if len(lv.lines) == 0 {
 		return
 	}
+	lv.view = nil
+	if !lv.filter.Empty() {
+		lv.view = []string{}
+		for _, line := range lv.lines {
+			if lv.filter.Match(line) {
+				lv.view = append(lv.view, line)
+			}
+		}
+	}
+	lines := lv.shown()
 
 	// create exact-sized buffer (EnsureSize only grows, which breaks maxScroll after ring buffer truncates)
 	const bufferWidth = 500
-	buf := NewBuffer(bufferWidth, len(lv.lines))
-	for i, line := range lv.lines {
-		buf.WriteStringFast(0, i, line, Style{}, bufferWidth)
+	buf := NewBuffer(bufferWidth, max(len(lines), 1))
+	for i, line := range lines {
+		lv.writeLine(buf, i, line, bufferWidth)
 	}
+	scrollY := lv.layer.ScrollY()
 	lv.layer.SetBuffer(buf)
+	lv.layer.ScrollTo(scrollY) // stay put when not following
+	lv.highlighted = -1
+}
+
+// shown returns the lines in the layer when not spilling.
+func (lv *LogC) shown() []string {
+	if lv.view != nil {
+		return lv.view
+	}
+	return lv.lines
+}
+
+// writeLine draws a line at row y, highlighting what the filter matched.
+func (lv *LogC) writeLine(buf *Buffer, y int, line string, w int) {
+	row := lv.row(line)
+	buf.WriteStringFast(0, y, row.text, Style{}, w)
+	if x, lw, ok := lv.levelColumn(); ok && row.level >= 0 {
+		style := logLevelStyle(row.level)
+		for ; lw > 0 && x < w; x, lw = x+1, lw-1 {
+			c := buf.Get(x, y)
+			c.Style = style
+			buf.SetFast(x, y, c)
+		}
+	}
+	text := row.text
+	for _, span := range lv.filter.Highlights(text) {
+		from, to := StringWidth(text[:span[0]]), min(StringWidth(text[:span[1]]), w)
+		for x := from; x < to; x++ {
+			c := buf.Get(x, y)
+			if lv.matchStyle.FG != (Color{}) {
+				c.Style.FG = lv.matchStyle.FG
+			}
+			if lv.matchStyle.BG != (Color{}) {
+				c.Style.BG = lv.matchStyle.BG
+			}
+			c.Style.Attr |= lv.matchStyle.Attr
+			buf.SetFast(x, y, c)
+		}
+	}
+}
+
+// refresh runs before each frame when spilling, filtering or selecting: it
+// applies a changed filter expression, draws the page of a spilling log and
+// marks the selected line.
+func (lv *LogC) refresh() {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	if lv.filterExpr != nil && *lv.filterExpr != lv.filterText {
+		lv.filterText = *lv.filterExpr
+		if f, err := ParseLogFilter(lv.filterText); err != nil {
+			lv.filterErr = err
+		} else {
+			lv.filter, lv.filterErr = f, nil
+			if lv.scrollback == nil {
+				lv.syncToLayer()
+				if lv.following {
+					lv.layer.ScrollToEnd()
+				}
+			}
+		}
+	}
+	if lv.scrollback != nil {
+		lv.renderPage()
+	} else if lv.selecting {
+		lv.selectRow()
+	}
+}
+
+// selectRow marks the selected line in the layer of a log that isn't
+// spilling, and notes it for the detail pane.
+func (lv *LogC) selectRow() {
+	lines := lv.shown()
+	top := lv.layer.ScrollY()
+	rows := min(max(lv.layer.ViewportHeight(), 1), len(lines)-top)
+	if lv.following {
+		lv.cursor = rows - 1
+	}
+	lv.cursor = max(min(lv.cursor, rows-1), 0)
+	at := top + lv.cursor
+	if at == lv.highlighted {
+		return
+	}
+	buf := lv.layer.Buffer()
+	if buf == nil || at >= len(lines) {
+		return
+	}
+	if lv.highlighted >= 0 && lv.highlighted < len(lines) {
+		lv.writeLine(buf, lv.highlighted, lines[lv.highlighted], buf.Width())
+	}
+	highlightRow(buf, at, buf.Width(), lv.selectedStyle)
+	lv.highlighted = at
+	lv.selected = lines[at]
+}
+
+// renderPage draws the visible lines of a spilling log, following the end
+// unless the user has scrolled away. Filtered, it shows the matching lines
+// from the top one on, or the last page of them when following.
+func (lv *LogC) renderPage() {
+	w, h := max(lv.layer.ViewportWidth(), 1), max(lv.layer.ViewportHeight(), 1)
+	n := lv.scrollback.Len()
+	if lv.selecting && lv.following {
+		lv.cursor = h - 1 // clamped to the lines below
+	}
+	page := logPage{lines: n, top: lv.top, w: w, h: h, following: lv.following, filter: lv.filter, cursor: lv.cursor}
+	if page == lv.page {
+		return // nothing new to show
+	}
+
+	var lines []string
+	switch {
+	case lv.filter.Empty():
+		last := max(n-h, 0)
+		if lv.following && lv.autoScroll {
+			lv.top = last
+		}
+		lv.top = min(lv.top, last)
+		lines = lv.scrollback.Lines(lv.top, h)
+	case lv.following && lv.autoScroll:
+		for at := n - 1; len(lines) < h; at-- {
+			if at = lv.scrollback.Find(at, true, lv.filter.Match); at < 0 {
+				break
+			}
+			lines = append(lines, lv.scrollback.Line(at))
+			lv.top = at
+		}
+		slices.Reverse(lines)
+	default:
+		for at := lv.top; len(lines) < h; at++ {
+			if at = lv.scrollback.Find(at, false, lv.filter.Match); at < 0 {
+				break
+			}
+			if len(lines) == 0 {
+				lv.top = at
+			}
+			lines = append(lines, lv.scrollback.Line(at))
+		}
+	}
+	buf := NewBuffer(w, h)
+	for i, line := range lines {
+		lv.writeLine(buf, i, line, w)
+	}
+	if lv.selecting && len(lines) > 0 {
+		lv.cursor = max(min(lv.cursor, len(lines)-1), 0)
+		highlightRow(buf, lv.cursor, w, lv.selectedStyle)
+		lv.selected = lines[lv.cursor]
+	}
+	lv.pageLines = lines
+	lv.layer.SetBuffer(buf)
+	page.top, page.cursor = lv.top, lv.cursor
+	lv.page = page
 }
 
 // compileLogC compiles the Log component into the template.
@@ -250,5 +629,12 @@ func (t *Template) compileLogC(lv *LogC, parent int16, depth int) int16 {
 		layerView = layerView.MarginTRBL(lv.margin[0], lv.margin[1], lv.margin[2], lv.margin[3])
 	}
 
+	if lv.selecting {
+		// the detail pane opens under the log
+		return t.compile(VBox.Grow(max(lv.grow, 1))(
+			layerView.Grow(2),
+			If(&lv.detailOpen).Then(VBox.Grow(1)(lv.Detail())),
+		), parent, depth, nil, 0)
+	}
 	return t.compileLayerViewC(layerView, parent, depth)
 }
@@ -0,0 +1,652 @@
+package glyph
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// LogFields are the fields of a log line, as ParseLogLine finds them.
+type LogFields map[string]string
+
+// ParseLogLine splits a line into fields for a LogFilter. A line holding
+// a JSON object has its members as fields, nested objects' as dotted keys
+// such as "http.status"; any other line has its key=value pairs, logfmt
+// style, values quoted or not. Two more are always there: msg, the line's
+// msg or message field or else the whole line, and level, its level, lvl
+// or severity field or else the first word that looks like a level, such as
+// "ERROR" or "[warn]". A line with no level has level "".
+func ParseLogLine(line string) LogFields {
+	_, f := logLineFields(line)
+	return withLogAliases(line, f)
+}
+
+// logLineFields returns a line's own fields and their keys in the order
+// they appear, without msg and level filled in.
+func logLineFields(line string) ([]string, LogFields) {
+	if keys, f, ok := jsonLogFields(line); ok {
+		return keys, f
+	}
+	var keys []string
+	f := LogFields{}
+	for i := 0; i < len(line); {
+		// a key runs up to '=', and must follow a space or the line's start
+		start := i
+		for i < len(line) && isLogKeyByte(line[i]) {
+			i++
+		}
+		if i == start || i >= len(line) || line[i] != '=' || (start > 0 && line[start-1] != ' ') {
+			i = max(i+1, start+1)
+			continue
+		}
+		key := line[start:i]
+		i++
+		value, n := logfmtValue(line[i:])
+		if _, dup := f[key]; !dup {
+			keys = append(keys, key)
+		}
+		f[key] = value
+		i += n
+	}
+	return keys, f
+}
+
+// withLogAliases fills in a line's msg and level fields, in place.
+func withLogAliases(line string, f LogFields) LogFields {
+	if _, ok := f["msg"]; !ok {
+		if m, ok := f["message"]; ok {
+			f["msg"] = m
+		} else {
+			f["msg"] = line
+		}
+	}
+	if _, ok := f["level"]; !ok {
+		f["level"] = f["lvl"]
+		if f["level"] == "" {
+			f["level"] = f["severity"]
+		}
+		if f["level"] == "" {
+			for _, word := range strings.Fields(line) {
+				word = strings.Trim(word, "[]():<>")
+				if logLevelRank(word) >= 0 {
+					f["level"] = word
+					break
+				}
+			}
+		}
+	}
+	return f
+}
+
+func isLogKeyByte(c byte) bool {
+	return c == '_' || c == '.' || c == '-' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// logfmtValue reads a value from the start of s, quoted or up to the next
+// space, and how many bytes it took.
+func logfmtValue(s string) (string, int) {
+	if strings.HasPrefix(s, `"`) {
+		for i := 1; i < len(s); i++ {
+			switch s[i] {
+			case '\\':
+				i++
+			case '"':
+				if v, err := strconv.Unquote(s[:i+1]); err == nil {
+					return v, i + 1
+				}
+				return s[1:i], i + 1
+			}
+		}
+	}
+	n := strings.IndexByte(s, ' ')
+	if n < 0 {
+		n = len(s)
+	}
+	return s[:n], n
+}
+
+// logLevelRank orders level names by severity, -1 for words that aren't
+// one.
+func logLevelRank(s string) int {
+	switch strings.ToLower(s) {
+	case "trace", "trc":
+		return 0
+	case "debug", "dbg":
+		return 1
+	case "info", "inf", "notice":
+		return 2
+	case "warn", "warning", "wrn":
+		return 3
+	case "error", "err", "erro":
+		return 4
+	case "fatal", "crit", "critical", "panic", "emerg", "alert":
+		return 5
+	}
+	return -1
+}
+
+// LogFilter is a parsed filter expression for log lines:
+//
+//	level>=WARN && source=="network" && msg~"timeout"
+//
+// A comparison is a field, an operator and a value, quoted or a bare word.
+// == and != compare text, and < <= > >= compare numbers, or levels by
+// severity (trace, debug, info, warn, error, fatal), or else text. ~ and
+// !~ match a regular expression. A quoted string or word on its own
+// matches lines containing it, ignoring case unless it has capitals.
+// Combine them with &&, || and !, and group them with parentheses.
+// Comparisons with a field the line doesn't have are false, except !=
+// and !~.
+type LogFilter struct {
+	src  string
+	root logExpr
+}
+
+// ParseLogFilter parses a filter expression. An empty one matches
+// everything.
+func ParseLogFilter(src string) (*LogFilter, error) {
+	p := &logFilterParser{src: src}
+	p.next()
+	f := &LogFilter{src: src}
+	if p.tok.kind == logTokEOF {
+		return f, nil
+	}
+	root, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != logTokEOF {
+		return nil, p.errorf("unexpected %q", p.tok.text)
+	}
+	f.root = root
+	return f, nil
+}
+
+// String returns the expression as written.
+func (f *LogFilter) String() string { return f.src }
+
+// Empty reports whether the filter matches everything.
+func (f *LogFilter) Empty() bool { return f == nil || f.root == nil }
+
+// Match reports whether a line matches.
+func (f *LogFilter) Match(line string) bool {
+	if f.Empty() {
+		return true
+	}
+	return f.root.match(line, ParseLogLine(line))
+}
+
+// MatchFields reports whether a line, already split into fields, matches.
+func (f *LogFilter) MatchFields(line string, fields LogFields) bool {
+	return f.Empty() || f.root.match(line, fields)
+}
+
+// Highlights returns the byte ranges of line that the filter's text
+// matches, regular expressions and quoted strings, for showing why a line
+// matched. Negated terms aren't included. The ranges are in order and
+// don't overlap.
+func (f *LogFilter) Highlights(line string) [][2]int {
+	if f.Empty() {
+		return nil
+	}
+	var spans [][2]int
+	f.root.highlight(line, false, &spans)
+	if len(spans) < 2 {
+		return spans
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+	merged := spans[:1]
+	for _, s := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if s[0] <= last[1] {
+			last[1] = max(last[1], s[1])
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
+
+type logExpr interface {
+	match(line string, f LogFields) bool
+	highlight(line string, negated bool, spans *[][2]int)
+}
+
+type logAnd struct{ l, r logExpr }
+type logOr struct{ l, r logExpr }
+type logNot struct{ e logExpr }
+
+func (e logAnd) match(line string, f LogFields) bool { return e.l.match(line, f) && e.r.match(line, f) }
+func (e logOr) match(line string, f LogFields) bool  { return e.l.match(line, f) || e.r.match(line, f) }
+func (e logNot) match(line string, f LogFields) bool { return !e.e.match(line, f) }
+
+func (e logAnd) highlight(line string, neg bool, spans *[][2]int) {
+	e.l.highlight(line, neg, spans)
+	e.r.highlight(line, neg, spans)
+}
+
+func (e logOr) highlight(line string, neg bool, spans *[][2]int) {
+	e.l.highlight(line, neg, spans)
+	e.r.highlight(line, neg, spans)
+}
+
+func (e logNot) highlight(line string, neg bool, spans *[][2]int) {
+	e.e.highlight(line, !neg, spans)
+}
+
+// logText is a word or string on its own, found anywhere in the line.
+type logText struct {
+	text string
+	fold bool // ignore case
+}
+
+func (e logText) match(line string, _ LogFields) bool {
+	if e.fold {
+		return strings.Contains(strings.ToLower(line), e.text)
+	}
+	return strings.Contains(line, e.text)
+}
+
+func (e logText) highlight(line string, neg bool, spans *[][2]int) {
+	if neg || e.text == "" {
+		return
+	}
+	hay := line
+	if e.fold {
+		hay = strings.ToLower(line)
+		if len(hay) != len(line) {
+			return // lowering changed the length, so offsets wouldn't line up
+		}
+	}
+	for at := 0; ; {
+		i := strings.Index(hay[at:], e.text)
+		if i < 0 {
+			return
+		}
+		*spans = append(*spans, [2]int{at + i, at + i + len(e.text)})
+		at += i + len(e.text)
+	}
+}
+
+// logCompare is field op value.
+type logCompare struct {
+	field string
+	op    string
+	value string
+	re    *regexp.Regexp
+}
+
+func (e logCompare) match(_ string, f LogFields) bool {
+	v, ok := f[e.field]
+	if !ok {
+		return e.op == "!=" || e.op == "!~"
+	}
+	switch e.op {
+	case "~":
+		return e.re.MatchString(v)
+	case "!~":
+		return !e.re.MatchString(v)
+	case "==":
+		return v == e.value
+	case "!=":
+		return v != e.value
+	}
+	if v == "" {
+		return false // an empty value, such as a line's missing level, has no order
+	}
+	c := compareLogValues(v, e.value)
+	switch e.op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	}
+	return c >= 0
+}
+
+func (e logCompare) highlight(line string, neg bool, spans *[][2]int) {
+	if neg {
+		return
+	}
+	switch e.op {
+	case "~":
+		for _, m := range e.re.FindAllStringIndex(line, -1) {
+			if m[1] > m[0] {
+				*spans = append(*spans, [2]int{m[0], m[1]})
+			}
+		}
+	case "==":
+		logText{text: e.value}.highlight(line, false, spans)
+	}
+}
+
+// compareLogValues orders a and b as numbers if both are, as levels if
+// both are, and as text otherwise.
+func compareLogValues(a, b string) int {
+	x, errA := strconv.ParseFloat(a, 64)
+	y, errB := strconv.ParseFloat(b, 64)
+	if errA == nil && errB == nil {
+		switch {
+		case x < y:
+			return -1
+		case x > y:
+			return 1
+		}
+		return 0
+	}
+	if ra, rb := logLevelRank(a), logLevelRank(b); ra >= 0 && rb >= 0 {
+		return ra - rb
+	}
+	return strings.Compare(a, b)
+}
+
+type logTokKind int
+
+const (
+	logTokEOF logTokKind = iota
+	logTokWord
+	logTokString
+	logTokOp    // == != ~ !~ < <= > >=
+	logTokAnd   // &&
+	logTokOr    // ||
+	logTokNot   // !
+	logTokOpen  // (
+	logTokClose // )
+)
+
+type logTok struct {
+	kind logTokKind
+	text string
+	pos  int
+}
+
+type logFilterParser struct {
+	src string
+	pos int
+	tok logTok
+	err error
+}
+
+func (p *logFilterParser) errorf(format string, args ...any) error {
+	return fmt.Errorf("filter: %s at column %d", fmt.Sprintf(format, args...), p.tok.pos+1)
+}
+
+// next reads the next token into p.tok.
+func (p *logFilterParser) next() {
+	s := p.src
+	for p.pos < len(s) && (s[p.pos] == ' ' || s[p.pos] == '\t') {
+		p.pos++
+	}
+	start := p.pos
+	if p.pos >= len(s) {
+		p.tok = logTok{kind: logTokEOF, pos: start}
+		return
+	}
+	two := ""
+	if p.pos+1 < len(s) {
+		two = s[p.pos : p.pos+2]
+	}
+	switch two {
+	case "&&":
+		p.pos += 2
+		p.tok = logTok{logTokAnd, two, start}
+		return
+	case "||":
+		p.pos += 2
+		p.tok = logTok{logTokOr, two, start}
+		return
+	case "==", "!=", "!~", "<=", ">=":
+		p.pos += 2
+		p.tok = logTok{logTokOp, two, start}
+		return
+	}
+	switch c := s[p.pos]; c {
+	case '~', '<', '>':
+		p.pos++
+		p.tok = logTok{logTokOp, string(c), start}
+	case '!':
+		p.pos++
+		p.tok = logTok{logTokNot, "!", start}
+	case '(':
+		p.pos++
+		p.tok = logTok{logTokOpen, "(", start}
+	case ')':
+		p.pos++
+		p.tok = logTok{logTokClose, ")", start}
+	case '"', '\'':
+		end := p.pos + 1
+		for end < len(s) && s[end] != c {
+			if s[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end >= len(s) {
+			p.pos = len(s)
+			p.tok = logTok{logTokString, s[start+1:], start}
+			p.err = fmt.Errorf("filter: unterminated string at column %d", start+1)
+			return
+		}
+		text := s[start+1 : end]
+		if c == '"' {
+			if v, err := strconv.Unquote(s[start : end+1]); err == nil {
+				text = v
+			}
+		} else {
+			text = strings.ReplaceAll(text, `\'`, "'")
+		}
+		p.pos = end + 1
+		p.tok = logTok{logTokString, text, start}
+	default:
+		for p.pos < len(s) && !strings.ContainsRune(" \t&|=!~<>()\"'", rune(s[p.pos])) {
+			p.pos++
+		}
+		p.tok = logTok{logTokWord, s[start:p.pos], start}
+	}
+}
+
+func (p *logFilterParser) or() (logExpr, error) {
+	l, err := p.and()
+	for err == nil && p.tok.kind == logTokOr {
+		p.next()
+		var r logExpr
+		if r, err = p.and(); err == nil {
+			l = logOr{l, r}
+		}
+	}
+	return l, err
+}
+
+func (p *logFilterParser) and() (logExpr, error) {
+	l, err := p.unary()
+	for err == nil && p.tok.kind == logTokAnd {
+		p.next()
+		var r logExpr
+		if r, err = p.unary(); err == nil {
+			l = logAnd{l, r}
+		}
+	}
+	return l, err
+}
+
+func (p *logFilterParser) unary() (logExpr, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	switch tok := p.tok; tok.kind {
+	case logTokNot:
+		p.next()
+		e, err := p.unary()
+		return logNot{e}, err
+	case logTokOpen:
+		p.next()
+		e, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok.kind != logTokClose {
+			return nil, p.errorf("expected )")
+		}
+		p.next()
+		return e, nil
+	case logTokString:
+		p.next()
+		return newLogText(tok.text), nil
+	case logTokWord:
+		p.next()
+		if p.tok.kind != logTokOp {
+			return newLogText(tok.text), nil
+		}
+		op := p.tok.text
+		p.next()
+		if p.err != nil {
+			return nil, p.err
+		}
+		if p.tok.kind != logTokWord && p.tok.kind != logTokString {
+			return nil, p.errorf("expected a value after %s", op)
+		}
+		c := logCompare{field: tok.text, op: op, value: p.tok.text}
+		if op == "~" || op == "!~" {
+			re, err := regexp.Compile(c.value)
+			if err != nil {
+				return nil, p.errorf("%v", err)
+			}
+			c.re = re
+		}
+		p.next()
+		return c, nil
+	case logTokEOF:
+		return nil, p.errorf("expression ends early")
+	}
+	return nil, p.errorf("unexpected %q", p.tok.text)
+}
+
+// newLogText matches text smart-case, as fzf does: ignoring case unless it
+// has capitals.
+func newLogText(text string) logText {
+	for _, r := range text {
+		if unicode.IsUpper(r) {
+			return logText{text: text}
+		}
+	}
+	return logText{text: text, fold: true}
+}
+
+// SavedFilters are named filter expressions, optionally kept in a file
+// between runs:
+//
+//	filters, err := OpenSavedFilters(filepath.Join(configDir, "filters"))
+//	filters.Save("slow network", `source=="network" && msg~"timeout"`)
+//	expr, _ := filters.Get("slow network")
+//
+// The file is plain text, a name and an expression on each line separated
+// by a tab, so it can be edited by hand.
+type SavedFilters struct {
+	path  string
+	names []string // in the order saved
+	exprs map[string]string
+}
+
+// NewSavedFilters creates saved filters kept in memory.
+func NewSavedFilters() *SavedFilters {
+	return &SavedFilters{exprs: map[string]string{}}
+}
+
+// OpenSavedFilters creates saved filters kept in path, reading what's
+// there. A missing file is fine; it's created on the first Save.
+func OpenSavedFilters(path string) (*SavedFilters, error) {
+	s := NewSavedFilters()
+	s.path = path
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		name, expr, ok := strings.Cut(sc.Text(), "\t")
+		if !ok || name == "" {
+			continue
+		}
+		if _, dup := s.exprs[name]; !dup {
+			s.names = append(s.names, name)
+		}
+		s.exprs[name] = expr
+	}
+	return s, sc.Err()
+}
+
+// Save stores expr under name, replacing any filter of that name, and
+// writes the file if there is one. It fails without saving if expr
+// doesn't parse.
+func (s *SavedFilters) Save(name, expr string) error {
+	if name == "" || strings.ContainsAny(name, "\t\r\n") || strings.ContainsAny(expr, "\r\n") {
+		return fmt.Errorf("filter: bad name or expression for %q", name)
+	}
+	if _, err := ParseLogFilter(expr); err != nil {
+		return err
+	}
+	if _, ok := s.exprs[name]; !ok {
+		s.names = append(s.names, name)
+	}
+	s.exprs[name] = expr
+	return s.save()
+}
+
+// Get returns the expression saved as name.
+func (s *SavedFilters) Get(name string) (string, bool) {
+	expr, ok := s.exprs[name]
+	return expr, ok
+}
+
+// Delete removes the filter saved as name.
+func (s *SavedFilters) Delete(name string) error {
+	if _, ok := s.exprs[name]; !ok {
+		return nil
+	}
+	delete(s.exprs, name)
+	for i, n := range s.names {
+		if n == name {
+			s.names = append(s.names[:i], s.names[i+1:]...)
+			break
+		}
+	}
+	return s.save()
+}
+
+// Names returns the names of the saved filters, in the order they were
+// first saved.
+func (s *SavedFilters) Names() []string {
+	return s.names
+}
+
+func (s *SavedFilters) save() error {
+	if s.path == "" {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	var b strings.Builder
+	for _, name := range s.names {
+		b.WriteString(name + "\t" + s.exprs[name] + "\n")
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(b.String()), 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
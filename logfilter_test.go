@@ -0,0 +1,195 @@
+package glyph
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLogLine(t *testing.T) {
+	f := ParseLogLine(`time=12:00 level=warn source=network msg="dial timeout after 5s" retries=3 url=a=b`)
+	for k, want := range map[string]string{
+		"level": "warn", "source": "network", "msg": "dial timeout after 5s", "retries": "3", "url": "a=b",
+	} {
+		if f[k] != want {
+			t.Errorf("%s = %q, want %q", k, f[k], want)
+		}
+	}
+	f = ParseLogLine("2026/01/02 [ERROR] disk full path=/var")
+	if f["level"] != "ERROR" || f["msg"] != "2026/01/02 [ERROR] disk full path=/var" || f["path"] != "/var" {
+		t.Errorf("plain line = %v", f)
+	}
+	if f := ParseLogLine("nothing to see"); f["level"] != "" {
+		t.Errorf("level = %q", f["level"])
+	}
+}
+
+func TestLogFilter(t *testing.T) {
+	lines := []string{
+		`level=info source=network msg="connected"`,
+		`level=warn source=network msg="dial timeout" ms=5000`,
+		`level=error source=disk msg="write failed" ms=12`,
+		`DEBUG cache miss key=users`,
+		`plain line with Timeout`,
+	}
+	for _, c := range []struct {
+		expr string
+		want string // which lines match, as indexes
+	}{
+		{"", "01234"},
+		{`level>=WARN`, "12"},
+		{`level<warn`, "03"},
+		{`level>=WARN && source=="network" && msg~"timeout"`, "1"},
+		{`source!=network`, "234"},
+		{`ms>100`, "1"},
+		{`ms<=12 || key==users`, "23"},
+		{`!(level==info || level==warn)`, "234"},
+		{`timeout`, "14"},
+		{`Timeout`, "4"},
+		{`'dial timeout'`, "1"},
+		{`msg!~"^d"`, "0234"},
+		{`source~net && !"connected"`, "1"},
+	} {
+		f, err := ParseLogFilter(c.expr)
+		if err != nil {
+			t.Errorf("%s: %v", c.expr, err)
+			continue
+		}
+		got := ""
+		for i, line := range lines {
+			if f.Match(line) {
+				got += fmt.Sprint(i)
+			}
+		}
+		if got != c.want {
+			t.Errorf("%s matches %s, want %s", c.expr, got, c.want)
+		}
+	}
+
+	for _, bad := range []string{`level>=`, `(a`, `a &&`, `msg~"("`, `"open`, `a b`, `== x`} {
+		if _, err := ParseLogFilter(bad); err == nil {
+			t.Errorf("%q parsed", bad)
+		}
+	}
+	if _, err := ParseLogFilter(`level>= &&`); err == nil || !strings.Contains(err.Error(), "column 9") {
+		t.Errorf("error = %v", err)
+	}
+}
+
+func TestLogFilterHighlights(t *testing.T) {
+	f, _ := ParseLogFilter(`msg~"time.ut" && (source=="network" || "dial") && !"skip"`)
+	line := `source=network msg="dial timeout"`
+	got := f.Highlights(line)
+	var parts []string
+	for _, s := range got {
+		parts = append(parts, line[s[0]:s[1]])
+	}
+	if strings.Join(parts, ",") != "network,dial,timeout" {
+		t.Errorf("highlights = %q", parts)
+	}
+	// overlapping matches merge
+	f, _ = ParseLogFilter(`"dial t" && msg~"timeout"`)
+	if got := f.Highlights(line); len(got) != 1 || line[got[0][0]:got[0][1]] != "dial timeout" {
+		t.Errorf("merged = %v", got)
+	}
+}
+
+func TestSavedFilters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "glyph", "filters")
+	s, err := OpenSavedFilters(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save("slow network", `source=="network" && msg~"timeout"`); err != nil {
+		t.Fatal(err)
+	}
+	s.Save("errors", "level>=error")
+	s.Save("slow network", "ms>1000")
+	if err := s.Save("broken", "level>="); err == nil {
+		t.Error("saved a filter that doesn't parse")
+	}
+
+	s, err = OpenSavedFilters(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(s.Names(), ","); got != "slow network,errors" {
+		t.Errorf("names = %q", got)
+	}
+	if expr, ok := s.Get("slow network"); !ok || expr != "ms>1000" {
+		t.Errorf("slow network = %q", expr)
+	}
+	s.Delete("errors")
+	data, _ := os.ReadFile(path)
+	if string(data) != "slow network\tms>1000\n" {
+		t.Errorf("file = %q", data)
+	}
+}
+
+func TestLogFilterLive(t *testing.T) {
+	input := "level=info msg=start\nlevel=warn msg=\"slow disk\"\nlevel=info msg=ok\nlevel=error msg=\"disk gone\"\n"
+	expr := ""
+	lv := Log(strings.NewReader(input)).Filter(&expr).Grow(1)
+	tmpl := Build(VBox(lv))
+	time.Sleep(50 * time.Millisecond)
+	frame := func() *Buffer {
+		buf := NewBuffer(40, 4)
+		tmpl.Execute(buf, 40, 4)
+		return buf
+	}
+	if buf := frame(); buf.GetLine(3) != `level=error msg="disk gone"` {
+		t.Fatalf("unfiltered = %q %q %q %q", buf.GetLine(0), buf.GetLine(1), buf.GetLine(2), buf.GetLine(3))
+	}
+
+	expr = `level>=warn && msg~disk`
+	buf := frame()
+	if buf.GetLine(0) != `level=warn msg="slow disk"` || buf.GetLine(1) != `level=error msg="disk gone"` || buf.GetLine(2) != "" {
+		t.Errorf("filtered = %q %q %q", buf.GetLine(0), buf.GetLine(1), buf.GetLine(2))
+	}
+	if c := buf.Get(21, 0); c.Rune != 'd' || c.Style.FG != Yellow {
+		t.Errorf("match cell = %q %+v", c.Rune, c.Style)
+	}
+
+	expr = `level>=`
+	if buf := frame(); lv.FilterErr() == nil || buf.GetLine(1) != `level=error msg="disk gone"` {
+		t.Errorf("bad expression: err %v, line %q", lv.FilterErr(), buf.GetLine(1))
+	}
+}
+
+func TestLogFilterSpilled(t *testing.T) {
+	pr, pw := io.Pipe()
+	expr := `msg~"7$"`
+	lv := Log(pr).MaxLines(64).Spill(t.TempDir()).Filter(&expr).Grow(1)
+	tmpl := Build(VBox(lv))
+	for i := range 500 {
+		fmt.Fprintf(pw, "n=%d msg=\"entry %d\"\n", i, i)
+	}
+	pw.Close()
+	for lv.Scrollback() == nil || lv.Scrollback().Len() < 500 {
+		time.Sleep(time.Millisecond)
+	}
+	defer lv.Scrollback().Close()
+	frame := func() []string {
+		buf := NewBuffer(30, 3)
+		tmpl.Execute(buf, 30, 3)
+		return []string{buf.GetLine(0), buf.GetLine(1), buf.GetLine(2)}
+	}
+	if got := frame(); got[2] != `n=497 msg="entry 497"` || got[1] != `n=487 msg="entry 487"` {
+		t.Errorf("following = %q", got)
+	}
+	lv.scrollToTop()
+	if got := frame(); got[0] != `n=7 msg="entry 7"` {
+		t.Errorf("top = %q", got)
+	}
+	lv.scroll(2)
+	if got := frame(); got[0] != `n=27 msg="entry 27"` {
+		t.Errorf("two matches down = %q", got)
+	}
+	if !lv.Find("entry 1", false) || frame()[0] != `n=107 msg="entry 107"` {
+		t.Errorf("find = %q", frame()[0])
+	}
+}
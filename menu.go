@@ -0,0 +1,300 @@
+package glyph
+
+import (
+	"strings"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// MenuItem is one entry in a menu. An item either runs Action or opens the
+// submenu in Sub.
+type MenuItem struct {
+	Label     string
+	Key       string // accelerator, e.g. "<C-s>"; bound to Action and shown beside the label
+	Action    func()
+	Disabled  bool // shown dimmed and skipped by the cursor
+	Sub       []MenuItem
+	Separator bool // a rule between groups; see MenuSeparator
+}
+
+// MenuSeparator returns a rule to divide groups of items.
+func MenuSeparator() MenuItem {
+	return MenuItem{Separator: true}
+}
+
+func (it MenuItem) selectable() bool {
+	return !it.Separator && !it.Disabled
+}
+
+// menuLevel is one open box in a cascade of menus.
+type menuLevel struct {
+	items []MenuItem
+	sel   int   // selected row, -1 when nothing can be selected
+	x, y  int16 // where the box was last drawn
+	w     int16
+}
+
+// menuPopup is the open state and drawing shared by MenuBar and
+// ContextMenu: a dropdown with any submenus cascading from it.
+type menuPopup struct {
+	levels []menuLevel
+
+	typed   string // type-ahead prefix
+	typedAt time.Time
+
+	style         Style // box body
+	selectedStyle Style
+	disabledStyle Style
+	border        BorderStyle
+}
+
+func newMenuPopup() menuPopup {
+	return menuPopup{
+		selectedStyle: Style{Attr: AttrInverse},
+		disabledStyle: Style{Attr: AttrDim},
+		border:        BorderSingle,
+	}
+}
+
+func (p *menuPopup) open(items []MenuItem) {
+	p.typed = ""
+	p.levels = append(p.levels[:0], menuLevel{items: items, sel: nextSelectable(items, -1, 1)})
+}
+
+func (p *menuPopup) close() { p.levels = p.levels[:0] }
+
+func (p *menuPopup) isOpen() bool { return len(p.levels) > 0 }
+
+func (p *menuPopup) top() *menuLevel { return &p.levels[len(p.levels)-1] }
+
+// selected returns the item under the cursor in the innermost box.
+func (p *menuPopup) selected() (MenuItem, bool) {
+	if !p.isOpen() {
+		return MenuItem{}, false
+	}
+	l := p.top()
+	if l.sel < 0 {
+		return MenuItem{}, false
+	}
+	return l.items[l.sel], true
+}
+
+// move steps the cursor by dir, wrapping and skipping rows that can't be
+// selected.
+func (p *menuPopup) move(dir int) {
+	if !p.isOpen() {
+		return
+	}
+	l := p.top()
+	if i := nextSelectable(l.items, l.sel, dir); i >= 0 {
+		l.sel = i
+	}
+}
+
+// openSub opens the selected item's submenu, reporting whether it had one.
+func (p *menuPopup) openSub() bool {
+	it, ok := p.selected()
+	if !ok || len(it.Sub) == 0 {
+		return false
+	}
+	p.levels = append(p.levels, menuLevel{items: it.Sub, sel: nextSelectable(it.Sub, -1, 1)})
+	p.typed = ""
+	return true
+}
+
+// back closes the innermost submenu, reporting false at the first box.
+func (p *menuPopup) back() bool {
+	if len(p.levels) < 2 {
+		return false
+	}
+	p.levels = p.levels[:len(p.levels)-1]
+	p.typed = ""
+	return true
+}
+
+// menuTypeAheadPause is how long after a keystroke type-ahead starts a new
+// prefix.
+const menuTypeAheadPause = time.Second
+
+// typeAhead moves the cursor to the next item whose label starts with what
+// has been typed. Typing the same letter again steps through the items
+// starting with it.
+func (p *menuPopup) typeAhead(r rune, now time.Time) {
+	if !p.isOpen() {
+		return
+	}
+	if now.Sub(p.typedAt) > menuTypeAheadPause {
+		p.typed = ""
+	}
+	p.typedAt = now
+	l := p.top()
+	if p.typed != "" {
+		// a longer prefix may still match the current item
+		if i := findMenuPrefix(l.items, p.typed+string(r), l.sel); i >= 0 {
+			p.typed += string(r)
+			l.sel = i
+			return
+		}
+	}
+	if i := findMenuPrefix(l.items, string(r), l.sel+1); i >= 0 {
+		p.typed = string(r)
+		l.sel = i
+	}
+}
+
+// findMenuPrefix returns the first selectable item from start on, wrapping,
+// whose label starts with prefix regardless of case, or -1.
+func findMenuPrefix(items []MenuItem, prefix string, start int) int {
+	n := len(items)
+	prefix = strings.ToLower(prefix)
+	for k := range n {
+		i := ((start+k)%n + n) % n
+		if items[i].selectable() && strings.HasPrefix(strings.ToLower(items[i].Label), prefix) {
+			return i
+		}
+	}
+	return -1
+}
+
+// nextSelectable returns the next selectable index after from in direction
+// dir, wrapping, or -1 when there is none.
+func nextSelectable(items []MenuItem, from, dir int) int {
+	n := len(items)
+	i := from
+	for range n {
+		i = (i + dir + n) % n
+		if items[i].selectable() {
+			return i
+		}
+	}
+	return -1
+}
+
+// draw renders the open boxes, the first with its top-left corner at x, y
+// and each submenu beside the row that opened it, kept on screen.
+func (p *menuPopup) draw(buf *Buffer, x, y, screenW, screenH int16) {
+	for i := range p.levels {
+		l := &p.levels[i]
+		w, h := menuSize(l.items)
+		if i > 0 {
+			parent := &p.levels[i-1]
+			x = parent.x + parent.w
+			if x+w > screenW {
+				x = parent.x - w // no room on the right: cascade left
+			}
+			y = parent.y + int16(parent.sel)
+		}
+		x = max(min(x, screenW-w), 0)
+		y = max(min(y, screenH-h), 0)
+		l.x, l.y, l.w = x, y, w
+		p.drawBox(buf, l, h)
+	}
+}
+
+func (p *menuPopup) drawBox(buf *Buffer, l *menuLevel, h int16) {
+	x, y, w := int(l.x), int(l.y), int(l.w)
+	buf.FillRect(x, y, w, int(h), Cell{Rune: ' ', Style: p.style})
+	buf.DrawBorder(x, y, w, int(h), p.border, p.style)
+
+	keyW := menuKeyWidth(l.items)
+	for i, it := range l.items {
+		row := y + 1 + i
+		if it.Separator {
+			buf.SetFast(x, row, Cell{Rune: '├', Style: p.style})
+			for cx := x + 1; cx < x+w-1; cx++ {
+				buf.SetFast(cx, row, Cell{Rune: '─', Style: p.style})
+			}
+			buf.SetFast(x+w-1, row, Cell{Rune: '┤', Style: p.style})
+			continue
+		}
+		style := p.style
+		switch {
+		case it.Disabled:
+			style = p.disabledStyle
+		case i == l.sel:
+			style = p.selectedStyle
+			buf.FillRect(x+1, row, w-2, 1, Cell{Rune: ' ', Style: style})
+		}
+		inner := w - 4 // a space inside each border
+		buf.WriteStringFast(x+2, row, it.Label, style, inner)
+		switch {
+		case len(it.Sub) > 0:
+			buf.WriteStringFast(x+w-3, row, "▸", style, 1)
+		case it.Key != "":
+			label := keyLabel(it.Key)
+			buf.WriteStringFast(x+w-2-keyW, row, label, style, keyW)
+		}
+	}
+}
+
+// menuSize is the box size for items, borders included.
+func menuSize(items []MenuItem) (w, h int16) {
+	labelW, sub := 0, false
+	for _, it := range items {
+		labelW = max(labelW, runewidth.StringWidth(it.Label))
+		sub = sub || len(it.Sub) > 0
+	}
+	right := menuKeyWidth(items)
+	if sub {
+		right = max(right, 1)
+	}
+	if right > 0 {
+		right += 2 // gap after the label
+	}
+	return int16(labelW + right + 4), int16(len(items) + 2)
+}
+
+func menuKeyWidth(items []MenuItem) int {
+	w := 0
+	for _, it := range items {
+		if it.Key != "" && len(it.Sub) == 0 {
+			w = max(w, runewidth.StringWidth(keyLabel(it.Key)))
+		}
+	}
+	return w
+}
+
+// keyLabel turns a key pattern into the form menus show: "<C-s>" reads
+// "Ctrl+S" and "<F5>" reads "F5". Plain sequences such as "gg" are
+// returned as they are.
+func keyLabel(pattern string) string {
+	if len(pattern) < 3 || pattern[0] != '<' || pattern[len(pattern)-1] != '>' || strings.Count(pattern, "<") > 1 {
+		return pattern
+	}
+	parts := strings.Split(pattern[1:len(pattern)-1], "-")
+	if parts[len(parts)-1] == "" {
+		return pattern
+	}
+	var out []string
+	for _, mod := range parts[:len(parts)-1] {
+		switch strings.ToLower(mod) {
+		case "c":
+			out = append(out, "Ctrl")
+		case "a", "m":
+			out = append(out, "Alt")
+		case "s":
+			out = append(out, "Shift")
+		default:
+			return pattern
+		}
+	}
+	key := parts[len(parts)-1]
+	switch strings.ToLower(key) {
+	case "cr", "enter", "return":
+		key = "Enter"
+	case "esc", "escape":
+		key = "Esc"
+	case "bs", "backspace":
+		key = "Backspace"
+	case "del", "delete":
+		key = "Del"
+	case "pageup":
+		key = "PgUp"
+	case "pagedown":
+		key = "PgDn"
+	default:
+		key = strings.ToUpper(key[:1]) + key[1:]
+	}
+	return strings.Join(append(out, key), "+")
+}
@@ -0,0 +1,265 @@
+package glyph
+
+import (
+	"github.com/kungfusheep/riffkey"
+	"github.com/mattn/go-runewidth"
+)
+
+// Menu is one title on a MenuBar and the dropdown it opens.
+type Menu struct {
+	Label string
+	Key   string // opens this menu directly, e.g. "<A-f>"
+	Items []MenuItem
+}
+
+// MenuBarC is a one-line bar of menu titles (File, Edit, View...) whose
+// dropdowns float over the content below. While a menu is open it takes
+// the keyboard: up/down or j/k move, right/l opens a submenu or the next
+// menu, left/h goes back, Enter runs the item and Esc closes.
+//
+//	MenuBar(
+//	    Menu{Label: "File", Key: "<A-f>", Items: []MenuItem{
+//	        {Label: "Open", Key: "<C-o>", Action: open},
+//	        {Label: "Recent", Sub: recent},
+//	        MenuSeparator(),
+//	        {Label: "Quit", Key: "<C-q>", Action: app.Stop},
+//	    }},
+//	    Menu{Label: "Edit", Items: edit},
+//	).BindOpen("<F10>")
+//
+// Item keys are bound as ordinary shortcuts too, so <C-o> opens a file with
+// the menu closed. Disabled items are drawn dimmed and can't be chosen.
+type MenuBarC struct {
+	menus []Menu
+	popup menuPopup
+	open  int     // index of the open menu, -1 when closed
+	xs    []int16 // title positions from the last render
+
+	style       Style
+	activeStyle Style
+	hint        KeyHint
+
+	router           *riffkey.Router
+	push             func(*riffkey.Router)
+	pop              func()
+	declaredBindings []binding
+}
+
+// MenuBar creates a menu bar with the given menus.
+func MenuBar(menus ...Menu) *MenuBarC {
+	return &MenuBarC{
+		menus:       menus,
+		popup:       newMenuPopup(),
+		open:        -1,
+		style:       Style{Attr: AttrInverse},
+		activeStyle: Style{Attr: AttrBold},
+	}
+}
+
+// Style sets the bar style. Inverse by default.
+func (mb *MenuBarC) Style(s Style) *MenuBarC {
+	mb.style = s
+	return mb
+}
+
+// ActiveStyle sets the style of the open menu's title. Bold by default.
+func (mb *MenuBarC) ActiveStyle(s Style) *MenuBarC {
+	mb.activeStyle = s
+	return mb
+}
+
+// Hint shows each menu's Key on its title: HintUnderline underlines the
+// letter of an Alt chord such as <A-f>.
+func (mb *MenuBarC) Hint(h KeyHint) *MenuBarC {
+	mb.hint = h
+	return mb
+}
+
+// MenuStyle sets the dropdown body and border style.
+func (mb *MenuBarC) MenuStyle(s Style) *MenuBarC {
+	mb.popup.style = s
+	return mb
+}
+
+// SelectedStyle sets the style of the item under the cursor. Inverse by
+// default.
+func (mb *MenuBarC) SelectedStyle(s Style) *MenuBarC {
+	mb.popup.selectedStyle = s
+	return mb
+}
+
+// DisabledStyle sets the style of disabled items. Dim by default.
+func (mb *MenuBarC) DisabledStyle(s Style) *MenuBarC {
+	mb.popup.disabledStyle = s
+	return mb
+}
+
+// Border sets the dropdown border. BorderSingle by default.
+func (mb *MenuBarC) Border(b BorderStyle) *MenuBarC {
+	mb.popup.border = b
+	return mb
+}
+
+// BindOpen registers a key that opens the first menu, F10 by convention.
+func (mb *MenuBarC) BindOpen(key string) *MenuBarC {
+	mb.declaredBindings = append(mb.declaredBindings, binding{key, func() { mb.Open(0) }})
+	return mb
+}
+
+// Open opens menu i and gives it the keyboard.
+func (mb *MenuBarC) Open(i int) {
+	if i < 0 || i >= len(mb.menus) {
+		return
+	}
+	if mb.open < 0 && mb.push != nil {
+		mb.push(mb.keys())
+	}
+	mb.open = i
+	mb.popup.open(mb.menus[i].Items)
+}
+
+// Close closes any open menu and hands the keyboard back.
+func (mb *MenuBarC) Close() {
+	if mb.open < 0 {
+		return
+	}
+	mb.open = -1
+	mb.popup.close()
+	if mb.pop != nil {
+		mb.pop()
+	}
+}
+
+// IsOpen reports whether a menu is open.
+func (mb *MenuBarC) IsOpen() bool { return mb.open >= 0 }
+
+// step opens the menu dir places along, wrapping.
+func (mb *MenuBarC) step(dir int) {
+	n := len(mb.menus)
+	mb.Open((mb.open + dir + n) % n)
+}
+
+// activate runs the selected item, or opens its submenu.
+func (mb *MenuBarC) activate() {
+	if mb.popup.openSub() {
+		return
+	}
+	it, ok := mb.popup.selected()
+	if !ok {
+		return
+	}
+	mb.Close()
+	if it.Action != nil {
+		it.Action()
+	}
+}
+
+// keys returns the router pushed while a menu is open. Keys it doesn't know
+// are swallowed so they can't reach the view underneath.
+func (mb *MenuBarC) keys() *riffkey.Router {
+	if mb.router != nil {
+		return mb.router
+	}
+	r := riffkey.NewRouter().NoCounts()
+	down := func(riffkey.Match) { mb.popup.move(1) }
+	up := func(riffkey.Match) { mb.popup.move(-1) }
+	right := func(riffkey.Match) {
+		if !mb.popup.openSub() {
+			mb.step(1)
+		}
+	}
+	left := func(riffkey.Match) {
+		if !mb.popup.back() {
+			mb.step(-1)
+		}
+	}
+	r.Handle("<Down>", down)
+	r.Handle("j", down)
+	r.Handle("<Up>", up)
+	r.Handle("k", up)
+	r.Handle("<Right>", right)
+	r.Handle("l", right)
+	r.Handle("<Left>", left)
+	r.Handle("h", left)
+	r.Handle("<Enter>", func(riffkey.Match) { mb.activate() })
+	r.Handle("<Space>", func(riffkey.Match) { mb.activate() })
+	r.Handle("<Esc>", func(riffkey.Match) {
+		if !mb.popup.back() {
+			mb.Close()
+		}
+	})
+	r.HandleUnmatched(func(riffkey.Key) bool { return true })
+	mb.router = r
+	return r
+}
+
+func (mb *MenuBarC) bindings() []binding {
+	out := append([]binding(nil), mb.declaredBindings...)
+	for i, m := range mb.menus {
+		if m.Key != "" {
+			out = append(out, binding{m.Key, func() { mb.Open(i) }})
+		}
+		out = appendAccelerators(out, m.Items)
+	}
+	return out
+}
+
+// appendAccelerators binds each enabled item's Key to its Action, through
+// any submenus.
+func appendAccelerators(out []binding, items []MenuItem) []binding {
+	for _, it := range items {
+		if it.Key != "" && it.Action != nil && !it.Disabled {
+			out = append(out, binding{it.Key, it.Action})
+		}
+		out = appendAccelerators(out, it.Sub)
+	}
+	return out
+}
+
+func (mb *MenuBarC) setModal(push func(*riffkey.Router), pop func()) {
+	mb.push, mb.pop = push, pop
+}
+
+func (t *Template) compileMenuBarC(mb *MenuBarC, parent int16, depth int) int16 {
+	measure := func(availW int16) (int16, int16) { return -1, 1 }
+	render := func(buf *Buffer, x, y, w, h int16) {
+		mb.renderBar(buf, x, y, w)
+		if mb.open >= 0 {
+			ax, ay := mb.xs[mb.open], y+1
+			t.pendingOverlays = append(t.pendingOverlays, pendingOverlay{draw: func(buf *Buffer, screenW, screenH int16) {
+				mb.popup.draw(buf, ax, ay, screenW, screenH)
+			}})
+		}
+	}
+	return t.compileCustom(Widget(measure, render), parent, depth)
+}
+
+func (mb *MenuBarC) renderBar(buf *Buffer, x, y, w int16) {
+	buf.FillRect(int(x), int(y), int(w), 1, Cell{Rune: ' ', Style: mb.style})
+	mb.xs = mb.xs[:0]
+	cx := x
+	for i, m := range mb.menus {
+		mb.xs = append(mb.xs, cx)
+		style := mb.style
+		if i == mb.open {
+			style = mb.activeStyle
+		}
+		title := " " + m.Label + " "
+		accel := -1
+		if m.Key != "" {
+			switch mb.hint {
+			case HintUnderline:
+				if i := accelIndex(m.Label, accelerator(m.Key)); i >= 0 {
+					accel = i + 1
+				}
+			case HintSuffix:
+				title = " " + m.Label + hintSuffix(m.Key) + " "
+			}
+		}
+		buf.WriteStringFast(int(cx), int(y), title, style, int(max(x+w-cx, 0)))
+		if int(cx)+accel < int(x+w) {
+			underlineCell(buf, int(cx), int(y), accel)
+		}
+		cx += int16(runewidth.StringWidth(title))
+	}
+}
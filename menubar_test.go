@@ -0,0 +1,160 @@
+package glyph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+func TestKeyLabel(t *testing.T) {
+	tests := map[string]string{
+		"<C-s>":   "Ctrl+S",
+		"<A-f>":   "Alt+F",
+		"<S-Tab>": "Shift+Tab",
+		"<F10>":   "F10",
+		"<CR>":    "Enter",
+		"<Esc>":   "Esc",
+		"gg":      "gg",
+		"<C-w>j":  "<C-w>j",
+	}
+	for in, want := range tests {
+		if got := keyLabel(in); got != want {
+			t.Errorf("keyLabel(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func testMenuBar(log *[]string) *MenuBarC {
+	do := func(name string) func() { return func() { *log = append(*log, name) } }
+	return MenuBar(
+		Menu{Label: "File", Items: []MenuItem{
+			{Label: "Open", Key: "<C-o>", Action: do("open")},
+			{Label: "Recent", Sub: []MenuItem{
+				{Label: "a.txt", Action: do("a.txt")},
+				{Label: "b.txt", Action: do("b.txt")},
+			}},
+			MenuSeparator(),
+			{Label: "Quit", Key: "<C-q>", Action: do("quit")},
+		}},
+		Menu{Label: "Edit", Key: "<A-e>", Items: []MenuItem{
+			{Label: "Undo", Disabled: true, Action: do("undo")},
+			{Label: "Copy", Action: do("copy")},
+		}},
+	)
+}
+
+func TestMenuBarRender(t *testing.T) {
+	var log []string
+	mb := testMenuBar(&log)
+	tmpl := Build(VBox(mb, Text("content underneath")))
+
+	got := messageLines(tmpl, 24, 7)
+	if got[0] != " File  Edit" || got[1] != "content underneath" {
+		t.Fatalf("closed bar:\n%s", strings.Join(got, "\n"))
+	}
+
+	mb.Open(0)
+	mb.popup.move(1) // Recent
+	mb.popup.openSub()
+	got = messageLines(tmpl, 30, 7)
+	want := []string{
+		" File  Edit",
+		"┌────────────────┐",
+		"│ Open    Ctrl+O │┌───────┐",
+		"│ Recent       ▸ ││ a.txt │",
+		"├────────────────┤│ b.txt │",
+		"│ Quit    Ctrl+Q │└───────┘",
+		"└────────────────┘",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestMenuBarKeys(t *testing.T) {
+	var log []string
+	mb := testMenuBar(&log)
+	var pushed int
+	mb.setModal(func(*riffkey.Router) { pushed++ }, func() { pushed-- })
+	in := riffkey.NewInput(mb.keys())
+	press := func(keys ...riffkey.Key) {
+		for _, k := range keys {
+			in.Dispatch(k)
+		}
+	}
+	down := riffkey.Key{Special: riffkey.SpecialDown}
+	right := riffkey.Key{Special: riffkey.SpecialRight}
+	enter := riffkey.Key{Special: riffkey.SpecialEnter}
+	esc := riffkey.Key{Special: riffkey.SpecialEscape}
+
+	mb.Open(0)
+	if pushed != 1 {
+		t.Fatalf("router not pushed on open")
+	}
+	// the second down skips the separator
+	press(down, down)
+	if it, _ := mb.popup.selected(); it.Label != "Quit" {
+		t.Errorf("selected %q, want Quit", it.Label)
+	}
+
+	// right from a plain item moves to Edit, whose disabled Undo is skipped
+	press(right)
+	if mb.open != 1 {
+		t.Fatalf("open = %d, want Edit", mb.open)
+	}
+	if it, _ := mb.popup.selected(); it.Label != "Copy" {
+		t.Errorf("selected %q, want Copy", it.Label)
+	}
+	press(enter)
+	if mb.IsOpen() || pushed != 0 || strings.Join(log, ",") != "copy" {
+		t.Errorf("open=%v pushed=%d log=%q", mb.IsOpen(), pushed, log)
+	}
+
+	// into a submenu and back out with Esc
+	mb.Open(0)
+	press(down, right)
+	if len(mb.popup.levels) != 2 {
+		t.Fatalf("submenu not opened")
+	}
+	press(esc)
+	if len(mb.popup.levels) != 1 || !mb.IsOpen() {
+		t.Errorf("esc should close only the submenu")
+	}
+	press(esc)
+	if mb.IsOpen() || pushed != 0 {
+		t.Errorf("esc at the top should close the menu")
+	}
+
+	// unknown keys are swallowed while open
+	mb.Open(0)
+	if !in.Dispatch(riffkey.Key{Rune: 'x'}) {
+		t.Errorf("stray key leaked past the open menu")
+	}
+}
+
+func TestMenuBarBindings(t *testing.T) {
+	var log []string
+	mb := testMenuBar(&log).BindOpen("<F10>")
+	tmpl := Build(VBox(mb))
+	keys := map[string]func(){}
+	for _, b := range tmpl.pendingBindings {
+		keys[b.pattern] = b.handler.(func())
+	}
+	for _, k := range []string{"<F10>", "<A-e>", "<C-o>", "<C-q>"} {
+		if keys[k] == nil {
+			t.Errorf("%s not bound", k)
+		}
+	}
+	keys["<C-o>"]()
+	if strings.Join(log, ",") != "open" {
+		t.Errorf("accelerator ran %q", log)
+	}
+	keys["<A-e>"]()
+	if mb.open != 1 {
+		t.Errorf("menu key opened %d, want 1", mb.open)
+	}
+	if len(tmpl.pendingModals) != 1 {
+		t.Errorf("menu bar not collected for modal wiring")
+	}
+}
@@ -0,0 +1,500 @@
+package glyph
+
+import (
+	"strings"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// Message is one entry in a MessageList.
+type Message struct {
+	Author string
+	Body   string
+	At     time.Time
+	Own    bool // sent by this user: drawn on the right
+}
+
+// MessageListC shows a conversation: authors and times, bodies wrapped to
+// the width, the user's own messages on the right, a separator at each new
+// day and an optional marker above the first unread message. New messages
+// keep it scrolled to the bottom unless the user has scrolled up.
+//
+//	MessageList(&msgs).Unread(&firstUnread).BindVimNav()
+//
+// It redraws when messages are added, the last one changes (e.g. a reply
+// streaming in) or the width changes; call Refresh after editing earlier
+// messages in place.
+type MessageListC struct {
+	msgs   *[]Message
+	unread *int
+	layer  *Layer
+
+	timeFormat     string
+	authorStyle    Style
+	ownStyle       Style
+	bodyStyle      Style
+	timeStyle      Style
+	separatorStyle Style
+	unreadStyle    Style
+
+	grow             float32
+	margin           [4]int16
+	declaredBindings []binding
+
+	// render state
+	lastLen    int
+	lastBody   string
+	lastWidth  int
+	lastHeight int
+	lastUnread int
+	stale      bool
+}
+
+// MessageList creates a message list bound to msgs.
+func MessageList(msgs *[]Message) *MessageListC {
+	ml := &MessageListC{
+		msgs:           msgs,
+		layer:          NewLayer(),
+		timeFormat:     "15:04",
+		authorStyle:    Style{Attr: AttrBold},
+		ownStyle:       Style{FG: Cyan, Attr: AttrBold},
+		timeStyle:      Style{FG: BrightBlack},
+		separatorStyle: Style{FG: BrightBlack},
+		unreadStyle:    Style{FG: Red},
+		stale:          true,
+	}
+	ml.layer.AlwaysRender = true
+	ml.layer.Render = ml.sync
+	return ml
+}
+
+// Unread marks the first unread message with a "new" rule above it. A
+// negative index, or one past the end, shows no marker.
+func (ml *MessageListC) Unread(index *int) *MessageListC {
+	ml.unread = index
+	return ml
+}
+
+// TimeFormat sets the time.Format layout for message times. "15:04" by
+// default; "" hides times.
+func (ml *MessageListC) TimeFormat(layout string) *MessageListC {
+	ml.timeFormat = layout
+	return ml
+}
+
+// AuthorStyle sets the style of other people's names.
+func (ml *MessageListC) AuthorStyle(s Style) *MessageListC {
+	ml.authorStyle = s
+	return ml
+}
+
+// OwnStyle sets the style of the user's own name.
+func (ml *MessageListC) OwnStyle(s Style) *MessageListC {
+	ml.ownStyle = s
+	return ml
+}
+
+// BodyStyle sets the style of message text.
+func (ml *MessageListC) BodyStyle(s Style) *MessageListC {
+	ml.bodyStyle = s
+	return ml
+}
+
+// TimeStyle sets the style of message times.
+func (ml *MessageListC) TimeStyle(s Style) *MessageListC {
+	ml.timeStyle = s
+	return ml
+}
+
+// SeparatorStyle sets the style of day separators.
+func (ml *MessageListC) SeparatorStyle(s Style) *MessageListC {
+	ml.separatorStyle = s
+	return ml
+}
+
+// UnreadStyle sets the style of the unread marker.
+func (ml *MessageListC) UnreadStyle(s Style) *MessageListC {
+	ml.unreadStyle = s
+	return ml
+}
+
+// Grow sets the flex grow factor.
+func (ml *MessageListC) Grow(g float32) *MessageListC {
+	ml.grow = g
+	return ml
+}
+
+// Margin sets equal margin on all sides.
+func (ml *MessageListC) Margin(all int16) *MessageListC {
+	ml.margin = [4]int16{all, all, all, all}
+	return ml
+}
+
+// MarginVH sets vertical and horizontal margins.
+func (ml *MessageListC) MarginVH(v, h int16) *MessageListC {
+	ml.margin = [4]int16{v, h, v, h}
+	return ml
+}
+
+// MarginTRBL sets top, right, bottom, left margins individually.
+func (ml *MessageListC) MarginTRBL(t, r, b, l int16) *MessageListC {
+	ml.margin = [4]int16{t, r, b, l}
+	return ml
+}
+
+// Layer returns the underlying layer for external scroll wiring.
+func (ml *MessageListC) Layer() *Layer { return ml.layer }
+
+// Refresh redraws on the next frame, for edits the list can't see.
+func (ml *MessageListC) Refresh() { ml.stale = true }
+
+// BindNav registers key bindings for scrolling down/up by one line.
+func (ml *MessageListC) BindNav(down, up string) *MessageListC {
+	ml.declaredBindings = append(ml.declaredBindings,
+		binding{down, func() { ml.layer.ScrollDown(1) }},
+		binding{up, func() { ml.layer.ScrollUp(1) }},
+	)
+	return ml
+}
+
+// BindPageNav registers key bindings for half-page scrolling.
+func (ml *MessageListC) BindPageNav(down, up string) *MessageListC {
+	ml.declaredBindings = append(ml.declaredBindings,
+		binding{down, func() { ml.layer.HalfPageDown() }},
+		binding{up, func() { ml.layer.HalfPageUp() }},
+	)
+	return ml
+}
+
+// BindFirstLast registers key bindings for jumping to top/bottom.
+func (ml *MessageListC) BindFirstLast(first, last string) *MessageListC {
+	ml.declaredBindings = append(ml.declaredBindings,
+		binding{first, func() { ml.layer.ScrollToTop() }},
+		binding{last, func() { ml.layer.ScrollToEnd() }},
+	)
+	return ml
+}
+
+// BindVimNav wires standard vim-style scroll keys:
+// j/k: line, Ctrl-d/u: half-page, g/G: top/bottom
+func (ml *MessageListC) BindVimNav() *MessageListC {
+	return ml.BindNav("j", "k").BindPageNav("<C-d>", "<C-u>").BindFirstLast("g", "G")
+}
+
+func (ml *MessageListC) bindings() []binding { return ml.declaredBindings }
+
+func (t *Template) compileMessageListC(ml *MessageListC, parent int16, depth int) int16 {
+	layerView := LayerView(ml.layer).Grow(ml.grow)
+	if ml.margin != [4]int16{} {
+		layerView = layerView.MarginTRBL(ml.margin[0], ml.margin[1], ml.margin[2], ml.margin[3])
+	}
+	return t.compileLayerViewC(layerView, parent, depth)
+}
+
+// messageRow is one drawn line: spans starting at column x.
+type messageRow struct {
+	x     int
+	spans []Span
+}
+
+// messageGroupGap is how close together one author's messages must be to
+// share a header.
+const messageGroupGap = 5 * time.Minute
+
+func (ml *MessageListC) sync() {
+	w := ml.layer.ViewportWidth()
+	h := ml.layer.ViewportHeight()
+	if w <= 0 {
+		return
+	}
+	msgs := *ml.msgs
+	unread := -1
+	if ml.unread != nil {
+		unread = *ml.unread
+	}
+	last := ""
+	if len(msgs) > 0 {
+		last = msgs[len(msgs)-1].Body
+	}
+	if !ml.stale && len(msgs) == ml.lastLen && last == ml.lastBody &&
+		w == ml.lastWidth && h == ml.lastHeight && unread == ml.lastUnread {
+		return
+	}
+	ml.stale = false
+	ml.lastLen, ml.lastBody, ml.lastWidth, ml.lastHeight, ml.lastUnread = len(msgs), last, w, h, unread
+
+	atBottom := ml.layer.ScrollY() >= ml.layer.MaxScroll()
+	rows := ml.rows(msgs, unread, w)
+
+	// few messages sit at the bottom, as chat does
+	top := max(h-len(rows), 0)
+	buf := NewBuffer(w, max(len(rows), h))
+	for i, r := range rows {
+		x := r.x
+		for _, s := range r.spans {
+			buf.WriteStringFast(x, top+i, s.Text, s.Style, w-x)
+			x += runewidth.StringWidth(s.Text)
+		}
+	}
+	y := ml.layer.ScrollY()
+	ml.layer.SetBuffer(buf)
+	if atBottom {
+		ml.layer.ScrollToEnd()
+	} else {
+		ml.layer.ScrollTo(y)
+	}
+}
+
+// rows lays the messages out for width w.
+func (ml *MessageListC) rows(msgs []Message, unread, w int) []messageRow {
+	// bubbles leave a gutter on the far side so the sides read apart
+	bodyW := w
+	if w >= 24 {
+		bodyW = w * 3 / 4
+	}
+	var rows []messageRow
+	var prev *Message // previous message, while its header still applies
+	var day time.Time
+	rule := func(label string, style Style) {
+		if prev != nil {
+			rows = append(rows, messageRow{})
+		}
+		rows = append(rows, messageRow{spans: []Span{{Text: centeredRule(label, w), Style: style}}})
+		prev = nil
+	}
+	for i := range msgs {
+		m := &msgs[i]
+		if !m.At.IsZero() && (day.IsZero() || !sameDay(day, m.At)) {
+			rule(m.At.Format("Mon 2 Jan 2006"), ml.separatorStyle)
+			day = m.At
+		}
+		if i == unread {
+			rule("new", ml.unreadStyle)
+		}
+
+		grouped := prev != nil && prev.Author == m.Author && prev.Own == m.Own &&
+			m.At.Sub(prev.At) < messageGroupGap
+		if !grouped {
+			if prev != nil {
+				rows = append(rows, messageRow{})
+			}
+			rows = append(rows, ml.header(m, w))
+		}
+		for _, line := range wordWrap(m.Body, bodyW) {
+			r := messageRow{spans: []Span{{Text: line, Style: ml.bodyStyle}}}
+			if m.Own {
+				r.x = max(w-runewidth.StringWidth(line), 0)
+			}
+			rows = append(rows, r)
+		}
+		prev = m
+	}
+	return rows
+}
+
+// header is the author and time line, right-aligned for own messages.
+func (ml *MessageListC) header(m *Message, w int) messageRow {
+	name := Span{Text: m.Author, Style: ml.authorStyle}
+	if m.Own {
+		name.Style = ml.ownStyle
+	}
+	var spans []Span
+	if ml.timeFormat == "" || m.At.IsZero() {
+		spans = []Span{name}
+	} else if at := (Span{Text: m.At.Format(ml.timeFormat), Style: ml.timeStyle}); m.Own {
+		spans = []Span{at, {Text: "  "}, name}
+	} else {
+		spans = []Span{name, {Text: "  "}, at}
+	}
+	r := messageRow{spans: spans}
+	if m.Own {
+		width := 0
+		for _, s := range spans {
+			width += runewidth.StringWidth(s.Text)
+		}
+		r.x = max(w-width, 0)
+	}
+	return r
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// centeredRule centres label in a horizontal rule w wide: "──── new ────".
+func centeredRule(label string, w int) string {
+	label = " " + label + " "
+	side := (w - runewidth.StringWidth(label)) / 2
+	if side < 1 {
+		return strings.TrimSpace(label)
+	}
+	return strings.Repeat("─", side) + label + strings.Repeat("─", w-side-runewidth.StringWidth(label))
+}
+
+// wordWrap breaks s into lines at most w wide, on spaces where it can and
+// mid-word where a word alone is too long. Newlines and runs of spaces are
+// kept, except the space a line breaks at.
+func wordWrap(s string, w int) []string {
+	if w <= 0 {
+		return nil
+	}
+	var out []string
+	for _, para := range strings.Split(s, "\n") {
+		line, lineW := "", 0
+		space := "" // spaces since the last word, placed once the next fits
+		start := true
+		for para != "" {
+			if para[0] == ' ' {
+				n := len(para) - len(strings.TrimLeft(para, " "))
+				space, para = space+para[:n], para[n:]
+				continue
+			}
+			n := strings.IndexByte(para, ' ')
+			if n < 0 {
+				n = len(para)
+			}
+			word := para[:n]
+			para = para[n:]
+			ww := runewidth.StringWidth(word)
+			sw := len(space)
+			switch {
+			case (lineW > 0 || start) && lineW+sw+ww <= w:
+				line += space + word
+				lineW += sw + ww
+				start = false
+				space = ""
+				continue
+			case lineW > 0:
+				out = append(out, line)
+			}
+			line, lineW, space, start = "", 0, "", false
+			for ww > w {
+				head := runewidth.Truncate(word, w, "")
+				if head == "" {
+					break // a single rune wider than w
+				}
+				out = append(out, head)
+				word = word[len(head):]
+				ww = runewidth.StringWidth(word)
+			}
+			line, lineW = word, ww
+		}
+		out = append(out, line)
+	}
+	return out
+}
+
+// ChatC pairs a MessageList with a compose box pinned beneath it, the
+// scaffolding of a chat client. Enter sends, Ctrl-J starts a new line, and
+// the box clears after each send:
+//
+//	Chat(&msgs, NewEditBuffer("")).OnSend(func(text string) {
+//	    msgs = append(msgs, Message{Author: "me", Body: text, At: time.Now(), Own: true})
+//	})
+//
+// The list scrolls with Ctrl-N/Ctrl-P and Ctrl-D/Ctrl-U, keys that don't
+// clash with typing.
+type ChatC struct {
+	list   *MessageListC
+	input  *TextAreaC
+	onSend func(string)
+
+	inputHeight int16
+	grow        float32
+	margin      [4]int16
+
+	declaredBindings []binding
+}
+
+// Chat creates a message list over msgs with a compose box editing buf.
+func Chat(msgs *[]Message, buf *EditBuffer) *ChatC {
+	c := &ChatC{
+		list:        MessageList(msgs).BindNav("<C-n>", "<C-p>").BindPageNav("<C-d>", "<C-u>"),
+		input:       TextArea(buf).Bind(),
+		inputHeight: 3,
+	}
+	c.declaredBindings = []binding{
+		{"<Enter>", c.send},
+		{"<C-j>", func() { buf.InsertNewline() }},
+	}
+	return c
+}
+
+// OnSend sets the callback for a sent message, given the compose box text
+// with surrounding blank space trimmed. Blank messages aren't sent.
+func (c *ChatC) OnSend(fn func(text string)) *ChatC {
+	c.onSend = fn
+	return c
+}
+
+// List returns the message list, for styling and unread markers.
+func (c *ChatC) List() *MessageListC { return c.list }
+
+// Input returns the compose box.
+func (c *ChatC) Input() *TextAreaC { return c.input }
+
+// InputHeight sets how many lines the compose box shows. 3 by default.
+func (c *ChatC) InputHeight(h int16) *ChatC {
+	c.inputHeight = h
+	return c
+}
+
+// Grow sets the flex grow factor.
+func (c *ChatC) Grow(g float32) *ChatC {
+	c.grow = g
+	return c
+}
+
+// Margin sets equal margin on all sides.
+func (c *ChatC) Margin(all int16) *ChatC {
+	c.margin = [4]int16{all, all, all, all}
+	return c
+}
+
+// MarginVH sets vertical and horizontal margins.
+func (c *ChatC) MarginVH(v, h int16) *ChatC {
+	c.margin = [4]int16{v, h, v, h}
+	return c
+}
+
+// MarginTRBL sets top, right, bottom, left margins individually.
+func (c *ChatC) MarginTRBL(t, r, b, l int16) *ChatC {
+	c.margin = [4]int16{t, r, b, l}
+	return c
+}
+
+// send hands the composed text to OnSend and clears the box.
+func (c *ChatC) send() {
+	buf := c.input.Buffer()
+	text := strings.TrimSpace(buf.Text())
+	if text == "" {
+		return
+	}
+	buf.SetText("")
+	if c.onSend != nil {
+		c.onSend(text)
+	}
+	// sending always brings the conversation back to the latest
+	c.list.layer.ScrollToEnd()
+}
+
+func (c *ChatC) bindings() []binding { return c.declaredBindings }
+
+// toTemplate returns the template tree for compilation.
+func (c *ChatC) toTemplate() any {
+	box := VBox
+	if c.grow > 0 {
+		box = box.Grow(c.grow)
+	}
+	if c.margin != [4]int16{} {
+		box = box.MarginTRBL(c.margin[0], c.margin[1], c.margin[2], c.margin[3])
+	}
+	return box(
+		c.list.Grow(1),
+		HRule().FG(BrightBlack),
+		c.input.Height(c.inputHeight),
+	)
+}
@@ -0,0 +1,133 @@
+package glyph
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+func TestWordWrap(t *testing.T) {
+	tests := []struct {
+		in   string
+		w    int
+		want []string
+	}{
+		{"the quick brown fox", 9, []string{"the quick", "brown fox"}},
+		{"a\n\nb", 5, []string{"a", "", "b"}},
+		{"abcdefghij", 4, []string{"abcd", "efgh", "ij"}},
+		{"hi  there", 20, []string{"hi  there"}},
+		{"  indented code", 20, []string{"  indented code"}},
+		{"one two", 3, []string{"one", "two"}},
+	}
+	for _, tt := range tests {
+		got := wordWrap(tt.in, tt.w)
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("wordWrap(%q, %d) = %q, want %q", tt.in, tt.w, got, tt.want)
+		}
+	}
+}
+
+func messageLines(tmpl *Template, w, h int) []string {
+	buf := NewBuffer(w, h)
+	tmpl.Execute(buf, int16(w), int16(h))
+	lines := make([]string, h)
+	for y := range lines {
+		lines[y] = buf.GetLine(y)
+	}
+	return lines
+}
+
+func TestMessageList(t *testing.T) {
+	day := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	msgs := []Message{
+		{Author: "ana", Body: "morning", At: day},
+		{Author: "ana", Body: "coffee?", At: day.Add(time.Minute)},
+		{Author: "me", Body: "yes please", At: day.Add(2 * time.Minute), Own: true},
+		{Author: "ana", Body: "done", At: day.Add(24 * time.Hour)},
+	}
+	unread := 3
+	tmpl := Build(VBox(MessageList(&msgs).Unread(&unread).Grow(1)))
+	got := messageLines(tmpl, 30, 12)
+	want := []string{
+		"─────── Mon 2 Mar 2026 ───────",
+		"ana  09:00",
+		"morning",
+		"coffee?",
+		"",
+		"                     09:02  me",
+		"                    yes please",
+		"",
+		"─────── Tue 3 Mar 2026 ───────",
+		"──────────── new ─────────────",
+		"ana  09:00",
+		"done",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestMessageListFollow(t *testing.T) {
+	msgs := []Message{{Author: "a", Body: "1"}}
+	ml := MessageList(&msgs).Grow(1)
+	tmpl := Build(VBox(ml))
+	if got := messageLines(tmpl, 10, 3); got[2] != "1" {
+		t.Fatalf("few messages should sit at the bottom: %q", got)
+	}
+
+	for _, b := range []string{"2", "3", "4"} {
+		msgs = append(msgs, Message{Author: "a", Body: b})
+	}
+	if got := messageLines(tmpl, 10, 3); got[2] != "4" {
+		t.Errorf("not following at the bottom: %q", got)
+	}
+
+	// scrolled up, new messages leave the view where it is
+	ml.Layer().ScrollUp(1)
+	msgs = append(msgs, Message{Author: "a", Body: "5"})
+	if got := messageLines(tmpl, 10, 3); got[2] != "3" {
+		t.Errorf("followed while scrolled up: %q", got)
+	}
+
+	// a reply streaming into the last message counts as a change
+	ml.Layer().ScrollToEnd()
+	msgs[len(msgs)-1].Body = "5 and more"
+	if got := messageLines(tmpl, 10, 3); got[2] != "5 and more" {
+		t.Errorf("last message edit not redrawn: %q", got)
+	}
+}
+
+func TestChatSend(t *testing.T) {
+	var msgs []Message
+	buf := NewEditBuffer("")
+	var sent []string
+	chat := Chat(&msgs, buf).OnSend(func(text string) { sent = append(sent, text) })
+
+	tmpl := Build(chat)
+	var enter, newline func()
+	for _, b := range tmpl.pendingBindings {
+		switch b.pattern {
+		case "<Enter>":
+			enter = b.handler.(func())
+		case "<C-j>":
+			newline = b.handler.(func())
+		}
+	}
+	if enter == nil || newline == nil || tmpl.pendingKeyHandler == nil {
+		t.Fatal("chat bindings not collected")
+	}
+
+	enter()
+	if len(sent) != 0 {
+		t.Errorf("blank message sent")
+	}
+	tmpl.pendingKeyHandler(riffkey.Key{Rune: 'h'})
+	newline()
+	tmpl.pendingKeyHandler(riffkey.Key{Rune: 'i'})
+	enter()
+	if len(sent) != 1 || sent[0] != "h\ni" || buf.Text() != "" {
+		t.Errorf("sent = %q, box = %q", sent, buf.Text())
+	}
+}
@@ -0,0 +1,316 @@
+package glyph
+
+// MillerColumnsC browses a tree as a row of lists, as Finder and ranger do:
+// each column lists the children of the item selected in the column before
+// it, and moving into an item opens its children in the next column.
+//
+//	MillerColumns(root).
+//	    Load(func(n *TreeNode) { n.Children = readDir(n.Data.(string)) }).
+//	    Preview(func(n *TreeNode) any { return Text(head(n.Data.(string))) }).
+//	    OnOpen(func(n *TreeNode) { edit(n.Data.(string)) }).
+//	    BindNav("j", "k", "l", "h")
+//
+// The last column shows the selected item's children, or its preview if it
+// has none. A preview is compiled on its own, so its components can't take
+// keys.
+type MillerColumnsC struct {
+	layer   *Layer
+	root    *TreeNode
+	path    []int // selected index in each column, from the root's
+	offsets []int // first row shown in each column
+	columns int
+	load    func(*TreeNode)
+	preview func(*TreeNode) any
+	onOpen  func(*TreeNode)
+
+	style    Style
+	selected Style // the selection in the focused column
+	trail    Style // the selections leading to it
+	sepStyle Style
+
+	previewOf  *TreeNode
+	previewSub *Template
+
+	grow   float32
+	height int16
+
+	declaredBindings []binding
+}
+
+// MillerColumns creates a browser over root's children.
+func MillerColumns(root *TreeNode) *MillerColumnsC {
+	m := &MillerColumnsC{
+		layer:    NewLayer(),
+		root:     root,
+		path:     []int{0},
+		columns:  3,
+		selected: Style{Attr: AttrInverse},
+		trail:    Style{Attr: AttrBold},
+		sepStyle: Style{Attr: AttrDim},
+	}
+	m.layer.AlwaysRender = true
+	m.layer.Render = m.sync
+	return m
+}
+
+// Ref provides access to the component for external references.
+func (m *MillerColumnsC) Ref(f func(*MillerColumnsC)) *MillerColumnsC { f(m); return m }
+
+// Columns sets how many columns are shown at once, the last of them the
+// children or preview of the selection. 3 by default.
+func (m *MillerColumnsC) Columns(n int) *MillerColumnsC {
+	m.columns = max(n, 1)
+	return m
+}
+
+// Load sets a function to fill in a node's Children the first time it is
+// selected while they're nil, for trees too big to build up front. Left
+// nil or empty, the node is a leaf.
+func (m *MillerColumnsC) Load(fn func(*TreeNode)) *MillerColumnsC {
+	m.load = fn
+	return m
+}
+
+// Preview sets the view shown in the last column for a selected leaf.
+func (m *MillerColumnsC) Preview(fn func(*TreeNode) any) *MillerColumnsC {
+	m.preview = fn
+	return m
+}
+
+// OnOpen sets a callback for moving into a leaf.
+func (m *MillerColumnsC) OnOpen(fn func(*TreeNode)) *MillerColumnsC {
+	m.onOpen = fn
+	return m
+}
+
+// Style sets the style of the items.
+func (m *MillerColumnsC) Style(s Style) *MillerColumnsC {
+	m.style = s
+	return m
+}
+
+// SelectedStyle sets the style of the selection in the focused column.
+// Inverse by default.
+func (m *MillerColumnsC) SelectedStyle(s Style) *MillerColumnsC {
+	m.selected = s
+	return m
+}
+
+// TrailStyle sets the style of the selections in the columns before the
+// focused one. Bold by default.
+func (m *MillerColumnsC) TrailStyle(s Style) *MillerColumnsC {
+	m.trail = s
+	return m
+}
+
+// Grow sets the flex grow factor.
+func (m *MillerColumnsC) Grow(g float32) *MillerColumnsC {
+	m.grow = g
+	return m
+}
+
+// Height sets a fixed viewport height.
+func (m *MillerColumnsC) Height(h int16) *MillerColumnsC {
+	m.height = h
+	return m
+}
+
+// BindNav registers keys to move the selection down and up, into the
+// selected item and back out to its parent.
+func (m *MillerColumnsC) BindNav(down, up, in, out string) *MillerColumnsC {
+	m.declaredBindings = append(m.declaredBindings,
+		binding{pattern: down, handler: func() { m.move(1) }},
+		binding{pattern: up, handler: func() { m.move(-1) }},
+		binding{pattern: in, handler: m.Enter},
+		binding{pattern: out, handler: m.Back},
+	)
+	return m
+}
+
+func (m *MillerColumnsC) bindings() []binding { return m.declaredBindings }
+
+// Selected returns the selected node, or nil in an empty column.
+func (m *MillerColumnsC) Selected() *TreeNode {
+	path := m.Path()
+	if len(path) < len(m.path) {
+		return nil
+	}
+	return path[len(path)-1]
+}
+
+// Path returns the selected node in each column up to the focused one.
+func (m *MillerColumnsC) Path() []*TreeNode {
+	var out []*TreeNode
+	n := m.root
+	for _, i := range m.path {
+		if n == nil || i >= len(n.Children) {
+			break
+		}
+		n = n.Children[i]
+		out = append(out, n)
+	}
+	return out
+}
+
+// Enter moves into the selected item, or opens it if it's a leaf.
+func (m *MillerColumnsC) Enter() {
+	n := m.Selected()
+	if n == nil {
+		return
+	}
+	m.loaded(n)
+	if len(n.Children) == 0 {
+		if m.onOpen != nil {
+			m.onOpen(n)
+		}
+		return
+	}
+	m.path = append(m.path, 0)
+	m.loaded(m.Selected())
+}
+
+// Back moves out to the parent's column.
+func (m *MillerColumnsC) Back() {
+	if len(m.path) > 1 {
+		m.path = m.path[:len(m.path)-1]
+	}
+}
+
+// move moves the focused column's selection by delta, clamped to its items.
+func (m *MillerColumnsC) move(delta int) {
+	items := m.column(len(m.path) - 1)
+	last := len(m.path) - 1
+	m.path[last] = min(max(m.path[last]+delta, 0), max(len(items)-1, 0))
+	m.loaded(m.Selected())
+}
+
+// loaded calls Load for n if its children haven't been filled in.
+func (m *MillerColumnsC) loaded(n *TreeNode) {
+	if n != nil && n.Children == nil && m.load != nil {
+		m.load(n)
+	}
+}
+
+// column returns the items of column i, the children of the selection in
+// the column before; the column after the focused one shows the
+// selection's.
+func (m *MillerColumnsC) column(i int) []*TreeNode {
+	n := m.root
+	for _, sel := range m.path[:i] {
+		if n == nil || sel >= len(n.Children) {
+			return nil
+		}
+		n = n.Children[sel]
+	}
+	if n == nil {
+		return nil
+	}
+	return n.Children
+}
+
+func (t *Template) compileMillerColumnsC(m *MillerColumnsC, parent int16, depth int) int16 {
+	t.collectBindings(m)
+	m.loaded(m.root)
+	m.loaded(m.Selected())
+	layerView := LayerView(m.layer).Grow(m.grow)
+	if m.height > 0 {
+		layerView = layerView.ViewHeight(m.height)
+	}
+	return t.compileLayerViewC(layerView, parent, depth)
+}
+
+// sync draws the columns to fit the viewport.
+func (m *MillerColumnsC) sync() {
+	w, h := m.layer.ViewportWidth(), m.layer.ViewportHeight()
+	if w <= 0 || h <= 0 {
+		return
+	}
+	out := NewBuffer(w, h)
+	m.draw(out, w, h)
+	m.layer.SetBuffer(out)
+}
+
+func (m *MillerColumnsC) draw(buf *Buffer, w, h int) {
+	focus := len(m.path) - 1
+	sel := m.Selected()
+	// the focused column and those before it, then what the selection holds
+	last := focus
+	if sel != nil && (len(sel.Children) > 0 || m.preview != nil) {
+		last++
+	}
+	first := max(0, last-m.columns+1)
+	n := last - first + 1
+	colW := (w - (n - 1)) / n
+	for len(m.offsets) <= last {
+		m.offsets = append(m.offsets, 0)
+	}
+
+	cx := 0
+	for i := first; i <= last; i++ {
+		cw := colW
+		if i == last {
+			cw = w - cx // the last column takes the remainder
+		}
+		if i > first {
+			for row := range h {
+				buf.Set(cx-1, row, Cell{Rune: '│', Style: m.sepStyle})
+			}
+		}
+		switch {
+		case i > focus && len(sel.Children) == 0:
+			m.drawPreview(buf, sel, cx, 0, cw, h)
+		case i > focus:
+			m.drawColumn(buf, sel.Children, -1, i, cx, 0, cw, h)
+		default:
+			m.drawColumn(buf, m.column(i), m.path[i], i, cx, 0, cw, h)
+		}
+		cx += cw + 1
+	}
+}
+
+// drawColumn draws items with sel highlighted, scrolled to keep it in view.
+func (m *MillerColumnsC) drawColumn(buf *Buffer, items []*TreeNode, sel, col, x, y, w, h int) {
+	if w <= 0 || h <= 0 {
+		return
+	}
+	off := m.offsets[col]
+	if sel >= 0 {
+		off = min(max(off, sel-h+1), sel)
+		m.offsets[col] = off
+	} else {
+		off = 0
+	}
+	focused := col == len(m.path)-1
+	for row := 0; row < h && off+row < len(items); row++ {
+		i := off + row
+		st := m.style
+		switch {
+		case i == sel && focused:
+			st = m.selected
+		case i == sel:
+			st = m.trail
+		}
+		buf.FillRect(x, y+row, w, 1, Cell{Rune: ' ', Style: st})
+		buf.WriteStringFast(x+1, y+row, items[i].Label, st, w-3)
+		if len(items[i].Children) > 0 {
+			buf.WriteStringFast(x+w-2, y+row, "›", st, 1)
+		}
+	}
+}
+
+// drawPreview draws the preview of n, compiling it when the selection
+// changes.
+func (m *MillerColumnsC) drawPreview(buf *Buffer, n *TreeNode, x, y, w, h int) {
+	if m.previewOf != n {
+		m.previewOf = n
+		m.previewSub = newSubTemplate(m.preview(n), nil, 0)
+	}
+	if w <= 2 {
+		return
+	}
+	sub := m.previewSub
+	sub.distributeWidths(int16(w-2), nil)
+	sub.layout(0)
+	sub.clipMaxY = int16(y + h)
+	sub.render(buf, int16(x+1), int16(y), int16(w-2))
+}
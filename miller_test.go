@@ -0,0 +1,93 @@
+package glyph
+
+import (
+	"strings"
+	"testing"
+)
+
+func millerTree() *TreeNode {
+	return &TreeNode{Children: []*TreeNode{
+		{Label: "etc", Children: []*TreeNode{
+			{Label: "hosts"},
+			{Label: "ssh", Children: []*TreeNode{{Label: "config"}}},
+		}},
+		{Label: "usr", Children: []*TreeNode{{Label: "bin"}}},
+		{Label: "readme"},
+	}}
+}
+
+func TestMillerColumns(t *testing.T) {
+	var opened []string
+	m := MillerColumns(millerTree()).Height(4).OnOpen(func(n *TreeNode) { opened = append(opened, n.Label) })
+	tmpl := Build(VBox(m))
+	frame := func() *Buffer {
+		buf := NewBuffer(32, 4)
+		tmpl.Execute(buf, 32, 4)
+		return buf
+	}
+
+	buf := frame()
+	if got := buf.GetLine(0); !strings.Contains(got, "etc") || !strings.Contains(got, "hosts") {
+		t.Fatalf("first column and the selection's children not shown:\n%s", buf.String())
+	}
+	if buf.Get(1, 0).Style.Attr&AttrInverse == 0 {
+		t.Error("selection not highlighted")
+	}
+
+	m.Enter()
+	m.move(1)
+	if got := m.Selected().Label; got != "ssh" {
+		t.Fatalf("selected %q, want ssh", got)
+	}
+	buf = frame()
+	if got := buf.GetLine(0); !strings.Contains(got, "etc") || !strings.Contains(got, "config") {
+		t.Errorf("parent, focused and child columns not shown:\n%s", buf.String())
+	}
+	if buf.Get(1, 0).Style.Attr != AttrBold {
+		t.Errorf("trail in the parent column not bold:\n%s", buf.String())
+	}
+
+	m.Enter()
+	m.Enter()
+	if len(opened) != 1 || opened[0] != "config" {
+		t.Errorf("opened = %v", opened)
+	}
+	if path := m.Path(); len(path) != 3 || path[1].Label != "ssh" {
+		t.Errorf("path = %v", path)
+	}
+
+	m.Back()
+	m.Back()
+	m.move(5)
+	if got := m.Selected().Label; got != "readme" {
+		t.Errorf("selected %q after moving past the end, want readme", got)
+	}
+}
+
+func TestMillerColumnsLoadAndPreview(t *testing.T) {
+	loads := 0
+	root := &TreeNode{Children: []*TreeNode{{Label: "dir"}, {Label: "file", Children: []*TreeNode{}}}}
+	m := MillerColumns(root).Height(2).
+		Load(func(n *TreeNode) {
+			loads++
+			n.Children = []*TreeNode{{Label: "child of " + n.Label, Children: []*TreeNode{}}}
+		}).
+		Preview(func(n *TreeNode) any { return Text("about " + n.Label) })
+	tmpl := Build(VBox(m))
+	frame := func() string {
+		buf := NewBuffer(40, 2)
+		tmpl.Execute(buf, 40, 2)
+		return buf.GetLine(0)
+	}
+
+	if got := frame(); !strings.Contains(got, "child of dir") {
+		t.Errorf("selection's children not loaded: %q", got)
+	}
+	m.move(1)
+	if got := frame(); !strings.Contains(got, "about file") {
+		t.Errorf("leaf not previewed: %q", got)
+	}
+	if loads != 1 {
+		t.Errorf("loads = %d, want 1 (root given, file's children empty not nil)", loads)
+	}
+}
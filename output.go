@@ -0,0 +1,183 @@
+package glyph
+
+import "bytes"
+
+// Output tunes how a Screen writes frames to the terminal. The zero value
+// writes each frame in one call, as small as it can be made: styles are
+// sent as changes from the style before, and the cursor is moved by the
+// shortest sequence that gets it there. Over a slow link, such as SSH on a
+// poor connection, fewer bytes per frame is what keeps an app responsive.
+//
+//	app.Screen().SetOutput(Output{BufferSize: 64 << 10, MaxWrite: 4096})
+type Output struct {
+	// BufferSize is how many bytes to reserve for building a frame's
+	// output up front. The buffer grows as needed either way.
+	BufferSize int
+
+	// MaxWrite splits each frame into writes of at most this many bytes,
+	// for links that stall on large writes. 0 writes a frame at once.
+	MaxWrite int
+
+	// AbsoluteMoves positions the cursor by row and column every time, for
+	// terminals that mishandle relative movement.
+	AbsoluteMoves bool
+}
+
+// SetOutput changes how frames are written.
+func (s *Screen) SetOutput(o Output) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.output = o
+	s.buf.Grow(o.BufferSize - s.buf.Len())
+}
+
+// Output returns how frames are written.
+func (s *Screen) Output() Output {
+	return s.output
+}
+
+// write sends p to the terminal in writes no larger than MaxWrite.
+func (s *Screen) write(p []byte) {
+	n := s.output.MaxWrite
+	if n <= 0 {
+		s.writer.Write(p)
+		return
+	}
+	for len(p) > n {
+		s.writer.Write(p[:n])
+		p = p[n:]
+	}
+	if len(p) > 0 {
+		s.writer.Write(p)
+	}
+}
+
+// moveCursor writes the shortest sequence that takes the cursor from
+// fromX, fromY to x, y. fromY is -1 when where the cursor is isn't known,
+// and fromX is past the last column when the cursor waits to wrap there.
+func (s *Screen) moveCursor(fromX, fromY, x, y int) {
+	if fromY >= 0 && !s.output.AbsoluteMoves {
+		switch {
+		case y == fromY && fromX < s.width:
+			if relativeLen(fromX, x) <= absoluteLen(x, y) {
+				s.moveInRow(fromX, x)
+				return
+			}
+		case y == fromY+1:
+			if 2+relativeLen(0, x) <= absoluteLen(x, y) {
+				s.buf.WriteString("\r\n")
+				s.moveInRow(0, x)
+				return
+			}
+		}
+	}
+	s.buf.WriteString("\x1b[")
+	s.writeIntToBuf(y + 1)
+	s.buf.WriteByte(';')
+	s.writeIntToBuf(x + 1)
+	s.buf.WriteByte('H')
+}
+
+// moveInRow writes the shortest sequence that moves the cursor along its
+// row from column fromX to x.
+func (s *Screen) moveInRow(fromX, x int) {
+	switch {
+	case x == fromX:
+	case x == 0:
+		s.buf.WriteByte('\r')
+	case x > fromX:
+		s.writeCSI(x-fromX, 'C')
+	case 1+csiLen(x) < csiLen(fromX-x):
+		s.buf.WriteByte('\r')
+		s.writeCSI(x, 'C')
+	default:
+		s.writeCSI(fromX-x, 'D')
+	}
+}
+
+// writeCSI writes a cursor movement of n, leaving out a count of 1.
+func (s *Screen) writeCSI(n int, final byte) {
+	s.buf.WriteString("\x1b[")
+	if n != 1 {
+		s.writeIntToBuf(n)
+	}
+	s.buf.WriteByte(final)
+}
+
+// relativeLen is how many bytes moveInRow writes.
+func relativeLen(fromX, x int) int {
+	switch {
+	case x == fromX:
+		return 0
+	case x == 0:
+		return 1
+	case x > fromX:
+		return csiLen(x - fromX)
+	}
+	return min(1+csiLen(x), csiLen(fromX-x))
+}
+
+// absoluteLen is how many bytes positioning the cursor at x, y takes.
+func absoluteLen(x, y int) int {
+	return 2 + digits(y+1) + 1 + digits(x+1) + 1
+}
+
+func csiLen(n int) int {
+	if n == 1 {
+		return 3
+	}
+	return 2 + digits(n) + 1
+}
+
+func digits(n int) int {
+	d := 1
+	for n >= 10 {
+		n /= 10
+		d++
+	}
+	return d
+}
+
+// sgrAttrs are the SGR parameters that turn on each attribute.
+var sgrAttrs = [...]struct {
+	attr  Attribute
+	param string
+}{
+	{AttrBold, ";1"},
+	{AttrDim, ";2"},
+	{AttrItalic, ";3"},
+	{AttrUnderline, ";4"},
+	{AttrBlink, ";5"},
+	{AttrInverse, ";7"},
+	{AttrStrikethrough, ";9"},
+}
+
+// writeStyleChange writes what changes the terminal's style from from to
+// to. Attributes being turned off need a reset and the whole style;
+// otherwise only what's new is sent.
+func (s *Screen) writeStyleChange(buf *bytes.Buffer, from, to Style) {
+	if from.Attr&^to.Attr != 0 {
+		s.writeStyle(buf, to)
+		return
+	}
+	if from.Attr == to.Attr && from.FG == to.FG && from.BG == to.BG {
+		return
+	}
+	// parameters are written with a leading separator, and the first
+	// separator becomes the sequence's [
+	start := buf.Len()
+	buf.WriteByte('\x1b')
+	for _, a := range sgrAttrs {
+		if to.Attr&^from.Attr&a.attr != 0 {
+			buf.WriteString(a.param)
+		}
+	}
+	if to.FG != from.FG {
+		s.writeColor(buf, to.FG, true)
+	}
+	if to.BG != from.BG {
+		s.writeColor(buf, to.BG, false)
+	}
+	buf.WriteByte('m')
+	buf.Bytes()[start+1] = '['
+}
@@ -0,0 +1,192 @@
+package glyph
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestMoveCursor(t *testing.T) {
+	tests := []struct {
+		name               string
+		fromX, fromY, x, y int
+		want               string
+	}{
+		{"unknown", -1, -1, 4, 2, "\x1b[3;5H"},
+		{"forward one", 3, 2, 4, 2, "\x1b[C"},
+		{"forward", 3, 2, 40, 2, "\x1b[37C"},
+		{"start of row", 30, 2, 0, 2, "\r"},
+		{"back", 30, 2, 27, 2, "\x1b[3D"},
+		{"back via start", 75, 2, 1, 2, "\r\x1b[C"},
+		{"next row", 79, 2, 0, 3, "\r\n"},
+		{"next row indented", 79, 2, 2, 3, "\r\n\x1b[2C"},
+		{"far", 3, 2, 60, 30, "\x1b[31;61H"},
+		{"waiting to wrap", 80, 2, 70, 2, "\x1b[3;71H"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newTestScreen(80, 40)
+			s.moveCursor(tt.fromX, tt.fromY, tt.x, tt.y)
+			if got := s.buf.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	s, _ := newTestScreen(80, 40)
+	s.SetOutput(Output{AbsoluteMoves: true})
+	s.moveCursor(3, 2, 4, 2)
+	if got := s.buf.String(); got != "\x1b[3;5H" {
+		t.Errorf("AbsoluteMoves: got %q", got)
+	}
+}
+
+func TestWriteStyleChange(t *testing.T) {
+	plain := DefaultStyle()
+	bold := plain
+	bold.Attr = AttrBold
+	boldRed := bold
+	boldRed.FG = Red
+	tests := []struct {
+		name     string
+		from, to Style
+		want     string
+	}{
+		{"attribute added", plain, bold, "\x1b[1m"},
+		{"colour changed", bold, boldRed, "\x1b[31m"},
+		{"attribute removed", boldRed, plain, "\x1b[0;39;49m"},
+		{"nothing visible", plain, Style{FG: plain.FG, BG: plain.BG, Align: AlignRight}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newTestScreen(10, 1)
+			s.writeStyleChange(&s.buf, tt.from, tt.to)
+			if got := s.buf.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// screenText replays what Flush wrote, supporting the sequences it uses,
+// and returns the text on the terminal.
+func screenText(t *testing.T, out string, width, height int) []string {
+	t.Helper()
+	grid := make([][]rune, height)
+	for y := range grid {
+		grid[y] = []rune(strings.Repeat(" ", width))
+	}
+	x, y := 0, 0
+	for i := 0; i < len(out); i++ {
+		switch c := out[i]; c {
+		case '\r':
+			x = 0
+		case '\n':
+			y++
+		case '\x1b':
+			end := i + 2 + strings.IndexAny(out[i+2:], "ABCDHhlmq")
+			params, final := out[i+2:end], out[end]
+			i = end
+			n, err := strconv.Atoi(params)
+			if err != nil {
+				n = 1
+			}
+			switch final {
+			case 'C':
+				x += n
+			case 'D':
+				x -= n
+			case 'H':
+				row, col, _ := strings.Cut(params, ";")
+				y, _ = strconv.Atoi(row)
+				x, _ = strconv.Atoi(col)
+				x, y = x-1, y-1
+			}
+		default:
+			grid[y][x] = rune(c)
+			x++
+		}
+	}
+	lines := make([]string, height)
+	for y := range grid {
+		lines[y] = string(grid[y])
+	}
+	return lines
+}
+
+func TestFlushRelativeMoves(t *testing.T) {
+	s, out := newTestScreen(80, 8)
+	var all strings.Builder
+	for i, frame := range dashboardFrames(80, 8, 6) {
+		s.back.CopyFrom(frame)
+		// text only, so the frame can be checked by replaying its output
+		for j := range s.back.cells {
+			if s.back.cells[j].Rune >= 0x80 {
+				s.back.cells[j].Rune = '#'
+			}
+		}
+		out.Reset()
+		s.Flush()
+		s.BufferCursor(i, 0, false, CursorDefault)
+		s.FlushBuffer()
+		all.WriteString(out.String())
+
+		got := screenText(t, all.String(), 80, 8)
+		for y := range 8 {
+			if want := s.front.GetLine(y); strings.TrimRight(got[y], " ") != want {
+				t.Fatalf("frame %d row %d:\n got %q\nwant %q", i, y, got[y], want)
+			}
+		}
+	}
+	if strings.Count(all.String(), "H") > 30 {
+		t.Errorf("expected mostly relative moves, got %q", all.String())
+	}
+}
+
+func TestBufferCursorUnchanged(t *testing.T) {
+	s, _ := newTestScreen(20, 4)
+	s.BufferCursor(3, 1, true, CursorBar)
+	if s.buf.Len() == 0 {
+		t.Fatal("first cursor not written")
+	}
+	s.buf.Reset()
+	s.BufferCursor(3, 1, true, CursorBar)
+	if s.buf.Len() != 0 {
+		t.Errorf("unchanged cursor written again: %q", s.buf.String())
+	}
+
+	s.back.WriteString(0, 0, "hi", DefaultStyle())
+	s.Flush()
+	s.BufferCursor(3, 1, true, CursorBar)
+	if got := s.buf.String(); !strings.HasSuffix(got, "\x1b[0m\x1b[2;4H") {
+		t.Errorf("cursor not put back after drawing: %q", got)
+	}
+}
+
+func TestMaxWrite(t *testing.T) {
+	w := &countingWriter{}
+	s := NewScreenSize(w, 40, 10)
+	s.SetOutput(Output{MaxWrite: 100})
+	for y := range 10 {
+		s.back.WriteString(0, y, strings.Repeat("x", 40), DefaultStyle())
+	}
+	s.Flush()
+	s.FlushBuffer()
+	if w.writes < 4 || w.max > 100 {
+		t.Errorf("got %d writes of up to %d bytes, want several of at most 100", w.writes, w.max)
+	}
+	if GetFlushStats().Bytes != w.n {
+		t.Errorf("stats report %d bytes, wrote %d", GetFlushStats().Bytes, w.n)
+	}
+}
+
+type countingWriter struct {
+	n, writes, max int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.n += len(p)
+	w.writes++
+	w.max = max(w.max, len(p))
+	return len(p), nil
+}
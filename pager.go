@@ -0,0 +1,166 @@
+package glyph
+
+import (
+	"context"
+	"fmt"
+)
+
+// PagedSource serves a collection too big to load at once a page at a
+// time, such as a database table or a paginated API. Page returns up to
+// limit items starting at offset, and the size of the whole collection,
+// or -1 if it isn't known.
+type PagedSource[T any] interface {
+	Page(ctx context.Context, offset, limit int) (items []T, total int, err error)
+}
+
+// PagedFunc adapts a function to PagedSource:
+//
+//	src := PagedFunc[User](func(ctx context.Context, offset, limit int) ([]User, int, error) {
+//	    return db.Users(ctx, offset, limit)
+//	})
+type PagedFunc[T any] func(ctx context.Context, offset, limit int) ([]T, int, error)
+
+// Page calls f.
+func (f PagedFunc[T]) Page(ctx context.Context, offset, limit int) ([]T, int, error) {
+	return f(ctx, offset, limit)
+}
+
+// SlicePages serves items a page at a time, for data already in memory.
+func SlicePages[T any](items []T) PagedSource[T] {
+	return PagedFunc[T](func(_ context.Context, offset, limit int) ([]T, int, error) {
+		if offset < 0 || limit < 0 {
+			return nil, 0, fmt.Errorf("page: offset %d limit %d", offset, limit)
+		}
+		offset = min(offset, len(items))
+		return items[offset:min(offset+limit, len(items))], len(items), nil
+	})
+}
+
+// Pager browses a PagedSource, holding the page shown in plain fields the
+// view binds to. Pages are fetched in the background by Run:
+//
+//	users := NewPager(SlicePages(all), 50)
+//	go users.Run(app.Context(), app.Post)
+//
+//	VBox(
+//	    AutoTable(&users.Items),
+//	    users.Paginator().BindNav("[", "]"),
+//	)
+//
+// Page moves as soon as Goto is called, and Items follow once the page has
+// loaded; a page that arrives after the pager has moved on is dropped.
+type Pager[T any] struct {
+	Items   []T
+	Page    int // the page shown, from 0
+	Pages   int // pages known of: all of them when Total is known
+	Total   int // items in the source, -1 until known
+	State   SourceState
+	Err     error
+	ErrText string // Err as text, "" when there is none
+
+	src  PagedSource[T]
+	size int
+	want chan int
+}
+
+// NewPager creates a pager over src showing size items a page. It asks for
+// the first page straight away.
+func NewPager[T any](src PagedSource[T], size int) *Pager[T] {
+	p := &Pager[T]{
+		Total: -1,
+		src:   src,
+		size:  max(size, 1),
+		want:  make(chan int, 1),
+	}
+	p.want <- 0
+	return p
+}
+
+// Size returns the number of items a page.
+func (p *Pager[T]) Size() int { return p.size }
+
+// Goto moves to page n, from 0, clamped to the pages known of. Call it on
+// the UI goroutine.
+func (p *Pager[T]) Goto(n int) {
+	n = max(n, 0)
+	if p.Pages > 0 {
+		n = min(n, p.Pages-1)
+	}
+	if n == p.Page && p.State == SourceReady {
+		return
+	}
+	p.Page = n
+	p.request(n)
+}
+
+// Next moves to the following page.
+func (p *Pager[T]) Next() { p.Goto(p.Page + 1) }
+
+// Prev moves to the page before.
+func (p *Pager[T]) Prev() { p.Goto(p.Page - 1) }
+
+// Refresh fetches the page shown again.
+func (p *Pager[T]) Refresh() { p.request(p.Page) }
+
+// request asks Run for page n, replacing any request it hasn't taken yet.
+func (p *Pager[T]) request(n int) {
+	select {
+	case <-p.want:
+	default:
+	}
+	p.want <- n
+}
+
+// Run fetches pages as they're asked for until ctx is done. Each result is
+// handed to post so it lands on the UI goroutine; pass app.Post, or nil to
+// update the fields directly.
+func (p *Pager[T]) Run(ctx context.Context, post func(func())) error {
+	for {
+		var page int
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case page = <-p.want:
+		}
+		items, total, err := p.src.Page(ctx, page*p.size, p.size)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		apply := func() { p.apply(page, items, total, err) }
+		if post == nil {
+			apply()
+		} else {
+			post(apply)
+		}
+	}
+}
+
+func (p *Pager[T]) apply(page int, items []T, total int, err error) {
+	if page != p.Page {
+		return // moved on while it loaded
+	}
+	if err != nil {
+		p.State = SourceError
+		p.Err = err
+		p.ErrText = err.Error()
+		return
+	}
+	p.Items = items
+	p.State = SourceReady
+	p.Err, p.ErrText = nil, ""
+	p.Total = total
+	switch {
+	case total >= 0:
+		p.Pages = max(1, (total+p.size-1)/p.size)
+	case len(items) == p.size:
+		// a full page may have another after it
+		p.Pages = max(p.Pages, page+2)
+	default:
+		p.Pages = page + 1
+	}
+}
+
+// Paginator returns a page indicator that moves the pager.
+func (p *Pager[T]) Paginator() *PaginatorC {
+	return Paginator(&p.Page, &p.Pages).OnChange(p.Goto)
+}
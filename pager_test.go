@@ -0,0 +1,83 @@
+package glyph
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestPager(t *testing.T) {
+	all := []int{1, 2, 3, 4, 5, 6, 7}
+	p := NewPager(SlicePages(all), 3)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	posted := make(chan func())
+	go p.Run(ctx, func(fn func()) { posted <- fn })
+
+	(<-posted)()
+	if !slices.Equal(p.Items, []int{1, 2, 3}) || p.Pages != 3 || p.Total != 7 || p.State != SourceReady {
+		t.Fatalf("first page = %v of %d (%d items)", p.Items, p.Pages, p.Total)
+	}
+
+	p.Goto(1)
+	p.Goto(5) // clamped to the last page, and replaces the request for 1
+	if p.Page != 2 {
+		t.Errorf("Page = %d, want 2", p.Page)
+	}
+	// Run may have taken the request for page 1 first; it is dropped
+	for range 2 {
+		if (<-posted)(); len(p.Items) == 1 {
+			break
+		}
+	}
+	if !slices.Equal(p.Items, []int{7}) {
+		t.Errorf("last page = %v", p.Items)
+	}
+
+	p.Prev()
+	fn := <-posted
+	p.Next() // moved on before page 1 arrived
+	fn()
+	if !slices.Equal(p.Items, []int{7}) {
+		t.Errorf("stale page applied: %v", p.Items)
+	}
+}
+
+func TestPagerUnknownTotal(t *testing.T) {
+	fail := false
+	src := PagedFunc[int](func(_ context.Context, offset, limit int) ([]int, int, error) {
+		if fail {
+			return nil, 0, errors.New("offline")
+		}
+		items, _, err := SlicePages([]int{1, 2, 3, 4}).Page(context.Background(), offset, limit)
+		return items, -1, err
+	})
+	p := NewPager(src, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	posted := make(chan func())
+	go p.Run(ctx, func(fn func()) { posted <- fn })
+
+	(<-posted)()
+	if p.Pages != 2 || p.Total != -1 {
+		t.Errorf("after a full page: Pages = %d Total = %d", p.Pages, p.Total)
+	}
+	p.Next()
+	(<-posted)()
+	if p.Pages != 3 {
+		t.Errorf("after a second full page: Pages = %d", p.Pages)
+	}
+	p.Next()
+	(<-posted)()
+	if p.Pages != 3 || len(p.Items) != 0 {
+		t.Errorf("past the end: Pages = %d Items = %v", p.Pages, p.Items)
+	}
+
+	fail = true
+	p.Refresh()
+	(<-posted)()
+	if p.State != SourceError || p.ErrText != "offline" {
+		t.Errorf("State = %v ErrText = %q", p.State, p.ErrText)
+	}
+}
@@ -0,0 +1,190 @@
+package glyph
+
+import (
+	"strconv"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+// PaginatorC shows which page of how many is on screen, as "‹ 1 … 4 5 6 …
+// 12 ›", and moves between pages with its bindings. It binds to a page
+// index from 0 and a page count; a count of 0 shows the page alone.
+//
+//	Paginator(&page, &pages).
+//	    BindNav("[", "]").
+//	    BindGoto("gp"). // 12gp goes to page 12
+//	    OnChange(load)
+//
+// Without OnChange the bindings set the page themselves. Pager's Paginator
+// comes wired to the pager.
+type PaginatorC struct {
+	page     *int
+	pages    *int
+	onChange func(int)
+	around   int // pages shown either side of the current one
+	style    Style
+	current  Style
+
+	declaredBindings []binding
+}
+
+// Paginator creates a page indicator for *page of *pages.
+func Paginator(page, pages *int) *PaginatorC {
+	return &PaginatorC{
+		page:    page,
+		pages:   pages,
+		around:  1,
+		current: Style{Attr: AttrInverse},
+	}
+}
+
+// OnChange sets what moves to a page, given its index from 0.
+func (c *PaginatorC) OnChange(fn func(page int)) *PaginatorC {
+	c.onChange = fn
+	return c
+}
+
+// Around sets how many page numbers are shown either side of the current
+// one before the rest are elided. 1 by default.
+func (c *PaginatorC) Around(n int) *PaginatorC {
+	c.around = max(n, 0)
+	return c
+}
+
+// Style sets the style of the indicator.
+func (c *PaginatorC) Style(s Style) *PaginatorC {
+	c.style = s
+	return c
+}
+
+// CurrentStyle sets the style of the current page's number. Inverse by
+// default.
+func (c *PaginatorC) CurrentStyle(s Style) *PaginatorC {
+	c.current = s
+	return c
+}
+
+// BindNav registers keys for the previous and next page. A count moves
+// that many pages.
+func (c *PaginatorC) BindNav(prev, next string) *PaginatorC {
+	c.declaredBindings = append(c.declaredBindings,
+		binding{pattern: prev, handler: func(m riffkey.Match) { c.move(*c.page - m.Count) }},
+		binding{pattern: next, handler: func(m riffkey.Match) { c.move(*c.page + m.Count) }},
+	)
+	return c
+}
+
+// BindEnds registers keys for the first and last page.
+func (c *PaginatorC) BindEnds(first, last string) *PaginatorC {
+	c.declaredBindings = append(c.declaredBindings,
+		binding{pattern: first, handler: func() { c.move(0) }},
+		binding{pattern: last, handler: func() { c.move(*c.pages - 1) }},
+	)
+	return c
+}
+
+// BindGoto registers a key that jumps to the page numbered by its count,
+// from 1, so "12gp" goes to page 12. Without a count it goes to the first.
+func (c *PaginatorC) BindGoto(pattern string) *PaginatorC {
+	c.declaredBindings = append(c.declaredBindings,
+		binding{pattern: pattern, handler: func(m riffkey.Match) { c.move(m.Count - 1) }},
+	)
+	return c
+}
+
+func (c *PaginatorC) bindings() []binding { return c.declaredBindings }
+
+// move goes to page n, clamped to the pages there are.
+func (c *PaginatorC) move(n int) {
+	if *c.pages > 0 {
+		n = min(n, *c.pages-1)
+	}
+	n = max(n, 0)
+	if n == *c.page {
+		return
+	}
+	if c.onChange != nil {
+		c.onChange(n)
+		return
+	}
+	*c.page = n
+}
+
+func (t *Template) compilePaginatorC(c *PaginatorC, parent int16, depth int) int16 {
+	t.collectBindings(c)
+	return t.compileCustom(Widget(c.measure, c.render), parent, depth)
+}
+
+// shown returns the page indexes to number, with -1 for each gap.
+func (c *PaginatorC) shown() []int {
+	page, pages := *c.page, *c.pages
+	if pages <= 0 {
+		return []int{page}
+	}
+	page = min(max(page, 0), pages-1)
+	out := []int{0}
+	add := func(i int) {
+		last := out[len(out)-1]
+		if i <= last {
+			return
+		}
+		if i > last+1 {
+			out = append(out, -1)
+		}
+		out = append(out, i)
+	}
+	for i := page - c.around; i <= page+c.around; i++ {
+		add(min(max(i, 0), pages-1))
+	}
+	add(pages - 1)
+	return out
+}
+
+func (c *PaginatorC) measure(availW int16) (w, h int16) {
+	w = 4 // "‹ " and " ›"
+	for i, p := range c.shown() {
+		if i > 0 {
+			w++
+		}
+		if p < 0 {
+			w++
+		} else {
+			w += int16(len(strconv.Itoa(p + 1)))
+		}
+	}
+	return w, 1
+}
+
+func (c *PaginatorC) render(buf *Buffer, x, y, w, h int16) {
+	end := int(x + w)
+	at := int(x)
+	write := func(s string, st Style) {
+		buf.WriteStringFast(at, int(y), s, st, end-at)
+		at += StringWidth(s)
+	}
+	dim := c.style
+	dim.Attr |= AttrDim
+	arrow := func(s string, enabled bool) {
+		if enabled {
+			write(s, c.style)
+		} else {
+			write(s, dim)
+		}
+	}
+	page, pages := *c.page, *c.pages
+	arrow("‹ ", page > 0)
+	for i, p := range c.shown() {
+		if i > 0 {
+			write(" ", c.style)
+		}
+		switch {
+		case p < 0:
+			write("…", dim)
+		case p == page:
+			write(strconv.Itoa(p+1), c.current)
+		default:
+			write(strconv.Itoa(p+1), c.style)
+		}
+	}
+	arrow(" ›", pages <= 0 || page < pages-1)
+}
@@ -0,0 +1,73 @@
+package glyph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+func TestPaginator(t *testing.T) {
+	page, pages := 0, 12
+	pg := Paginator(&page, &pages).BindNav("[", "]").BindEnds("^", "$").BindGoto("gp")
+	tmpl := Build(pg)
+	frame := func() *Buffer {
+		buf := NewBuffer(30, 1)
+		tmpl.Execute(buf, 30, 1)
+		return buf
+	}
+	press := func(pattern string, count int) {
+		for _, b := range pg.bindings() {
+			if b.pattern != pattern {
+				continue
+			}
+			switch h := b.handler.(type) {
+			case func():
+				h()
+			case func(riffkey.Match):
+				h(riffkey.Match{Count: count})
+			}
+		}
+	}
+
+	if got := strings.TrimSpace(frame().GetLine(0)); got != "‹ 1 2 … 12 ›" {
+		t.Errorf("first page = %q", got)
+	}
+	if frame().Get(0, 0).Style.Attr&AttrDim == 0 {
+		t.Error("previous arrow not dimmed on the first page")
+	}
+
+	press("]", 4)
+	if page != 4 {
+		t.Fatalf("page = %d after 4], want 4", page)
+	}
+	buf := frame()
+	if got := strings.TrimSpace(buf.GetLine(0)); got != "‹ 1 … 4 5 6 … 12 ›" {
+		t.Errorf("middle page = %q", got)
+	}
+	if buf.Get(8, 0).Rune != '5' || buf.Get(8, 0).Style.Attr&AttrInverse == 0 {
+		t.Errorf("current page not highlighted:\n%s", buf.String())
+	}
+
+	press("$", 1)
+	press("]", 1)
+	if page != 11 {
+		t.Errorf("page = %d past the end, want 11", page)
+	}
+	press("gp", 3)
+	if page != 2 {
+		t.Errorf("3gp went to %d, want 2", page)
+	}
+	press("^", 1)
+	press("[", 1)
+	if page != 0 {
+		t.Errorf("page = %d before the start, want 0", page)
+	}
+
+	var asked []int
+	pg.OnChange(func(n int) { asked = append(asked, n) })
+	press("]", 1)
+	if page != 0 || len(asked) != 1 || asked[0] != 1 {
+		t.Errorf("OnChange: page = %d asked = %v", page, asked)
+	}
+}
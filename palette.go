@@ -0,0 +1,341 @@
+package glyph
+
+import (
+	"github.com/kungfusheep/riffkey"
+	"github.com/mattn/go-runewidth"
+)
+
+// CommandPaletteC is a searchable list of a Commands registry that floats
+// at the top of the screen while open. It takes no space in the layout, so
+// put it anywhere in the view:
+//
+//	app.Command("write", "Write the buffer", save).Key("<C-s>")
+//	app.Command("goto", "Go to a line", gotoLine)
+//
+//	VBox(
+//	    editor,
+//	    CommandPalette(app.Commands()).BindOpen("<C-p>"),
+//	)
+//
+// Typing narrows the list with an fzf query over names and descriptions;
+// up/down or Ctrl-p/Ctrl-n move, Enter runs the selected command and Esc
+// closes. A command that takes arguments is filled in for them to be typed
+// after it, and a line that already starts with a command and has
+// arguments runs as it is. Errors go to the registry's OnError.
+//
+// Tab completes: on a name it fills in the selected command, and on an
+// argument it cycles through what the command's Completer offers, which
+// is listed as it's typed. Shift-Tab cycles back. With a History, Ctrl-R
+// searches it; see CommandLine.
+type CommandPaletteC struct {
+	prompt  cmdPrompt
+	matches []*Command
+	sel     int
+	open    bool
+
+	width     int16
+	rows      int
+	style     Style
+	selStyle  Style
+	descStyle Style
+	keyStyle  Style
+	border    BorderStyle
+
+	router           *riffkey.Router
+	push             func(*riffkey.Router)
+	pop              func()
+	declaredBindings []binding
+}
+
+// CommandPalette creates a palette over cmds.
+func CommandPalette(cmds *Commands) *CommandPaletteC {
+	p := &CommandPaletteC{
+		width:     60,
+		rows:      10,
+		selStyle:  Style{Attr: AttrInverse},
+		descStyle: Style{Attr: AttrDim},
+		keyStyle:  Style{FG: Cyan},
+		border:    BorderRounded,
+	}
+	p.prompt = cmdPrompt{cmds: cmds, changed: p.refilter}
+	return p
+}
+
+// History records the lines run from the palette in h, and lets Ctrl-R
+// search them.
+func (p *CommandPaletteC) History(h *History) *CommandPaletteC {
+	p.prompt.history = h
+	return p
+}
+
+// Width sets the widest the palette gets. 60 by default; it shrinks to fit
+// the screen.
+func (p *CommandPaletteC) Width(w int16) *CommandPaletteC {
+	p.width = w
+	return p
+}
+
+// Rows sets how many commands are listed at once. 10 by default.
+func (p *CommandPaletteC) Rows(n int) *CommandPaletteC {
+	p.rows = max(n, 1)
+	return p
+}
+
+// Style sets the palette body and border style.
+func (p *CommandPaletteC) Style(s Style) *CommandPaletteC {
+	p.style = s
+	return p
+}
+
+// SelectedStyle sets the style of the command under the cursor. Inverse by
+// default.
+func (p *CommandPaletteC) SelectedStyle(s Style) *CommandPaletteC {
+	p.selStyle = s
+	return p
+}
+
+// DescStyle sets the style of command descriptions. Dim by default.
+func (p *CommandPaletteC) DescStyle(s Style) *CommandPaletteC {
+	p.descStyle = s
+	return p
+}
+
+// KeyStyle sets the style of the keys commands are bound to. Cyan by
+// default.
+func (p *CommandPaletteC) KeyStyle(s Style) *CommandPaletteC {
+	p.keyStyle = s
+	return p
+}
+
+// Border sets the palette border. BorderRounded by default.
+func (p *CommandPaletteC) Border(b BorderStyle) *CommandPaletteC {
+	p.border = b
+	return p
+}
+
+// BindOpen registers a key that opens the palette.
+func (p *CommandPaletteC) BindOpen(key string) *CommandPaletteC {
+	p.declaredBindings = append(p.declaredBindings, binding{key, p.Open})
+	return p
+}
+
+// Open opens the palette with an empty query.
+func (p *CommandPaletteC) Open() {
+	if !p.open && p.push != nil {
+		p.push(p.keys())
+	}
+	p.open = true
+	p.prompt.recalling = false
+	p.setQuery("")
+}
+
+// Close closes the palette and hands the keyboard back.
+func (p *CommandPaletteC) Close() {
+	if !p.open {
+		return
+	}
+	p.open = false
+	if p.pop != nil {
+		p.pop()
+	}
+}
+
+// IsOpen reports whether the palette is open.
+func (p *CommandPaletteC) IsOpen() bool { return p.open }
+
+// Query returns what's been typed.
+func (p *CommandPaletteC) Query() string { return p.prompt.line }
+
+// Selected returns the command under the cursor, or nil if none match.
+func (p *CommandPaletteC) Selected() *Command {
+	if p.sel < len(p.matches) {
+		return p.matches[p.sel]
+	}
+	return nil
+}
+
+func (p *CommandPaletteC) setQuery(q string) {
+	p.prompt.set(q)
+}
+
+// refilter matches commands against the query until it names one and
+// goes on to its arguments.
+func (p *CommandPaletteC) refilter() {
+	p.sel = 0
+	if cmd := p.prompt.cmd; cmd != nil {
+		p.matches = []*Command{cmd}
+		return
+	}
+	p.matches = p.prompt.cmds.Find(p.prompt.nameQuery())
+}
+
+func (p *CommandPaletteC) move(dir int) {
+	if n := len(p.matches); n > 0 {
+		p.sel = (p.sel + dir + n) % n
+	}
+}
+
+// complete fills in the selected command's name, or steps through the
+// completions for the argument being typed.
+func (p *CommandPaletteC) complete(dir int) {
+	if p.prompt.cmd != nil {
+		p.prompt.complete(dir)
+		return
+	}
+	if cmd := p.Selected(); cmd != nil && dir > 0 {
+		p.setQuery(cmd.Name + " ")
+	}
+}
+
+// activate runs the line if it's a command with arguments, otherwise the
+// selected command, asking for its arguments first if it has any.
+func (p *CommandPaletteC) activate() {
+	line := p.prompt.line
+	words, err := splitCommandLine(line)
+	if err == nil && len(words) > 1 {
+		if _, err := p.prompt.cmds.Get(words[0]); err == nil {
+			p.Close()
+			p.prompt.cmds.report(p.prompt.run(line))
+			return
+		}
+	}
+	cmd := p.Selected()
+	if cmd == nil {
+		return
+	}
+	if cmd.Args() > 0 {
+		if line != cmd.Name+" " {
+			p.setQuery(cmd.Name + " ")
+		}
+		return
+	}
+	p.Close()
+	p.prompt.cmds.report(p.prompt.run(cmd.Name))
+}
+
+// keys returns the router pushed while the palette is open. Anything it
+// doesn't bind edits the query.
+func (p *CommandPaletteC) keys() *riffkey.Router {
+	if p.router != nil {
+		return p.router
+	}
+	r := riffkey.NewRouter().NoCounts()
+	p.prompt.bind(r, promptKeys{
+		enter:  p.activate,
+		escape: p.Close,
+		up:     func() { p.move(-1) },
+		down:   func() { p.move(1) },
+		tab:    p.complete,
+	})
+	p.router = r
+	return r
+}
+func (p *CommandPaletteC) bindings() []binding {
+	return p.declaredBindings
+}
+
+func (p *CommandPaletteC) setModal(push func(*riffkey.Router), pop func()) {
+	p.push, p.pop = push, pop
+}
+
+func (t *Template) compileCommandPaletteC(p *CommandPaletteC, parent int16, depth int) int16 {
+	measure := func(int16) (w, h int16) { return 0, 0 }
+	render := func(buf *Buffer, x, y, w, h int16) {
+		if p.open {
+			t.pendingOverlays = append(t.pendingOverlays, pendingOverlay{draw: p.draw})
+		}
+	}
+	return t.compileCustom(Widget(measure, render), parent, depth)
+}
+
+func (p *CommandPaletteC) draw(buf *Buffer, screenW, screenH int16) {
+	pr := &p.prompt
+	n := len(p.matches)
+	switch {
+	case pr.recalling:
+		n = len(pr.recall.matches)
+	case pr.cmd != nil && len(pr.comps) > 0:
+		n = len(pr.comps)
+	}
+	w := min(p.width, screenW-2)
+	rows := min(p.rows, n, max(int(screenH)-5, 0))
+	h := int16(rows + 3) // the query, the list and the border
+	if n == 0 {
+		h++ // room to say so
+	}
+	if w < 10 || h > screenH {
+		return
+	}
+	x, y := (screenW-w)/2, min(screenH/6, screenH-h)
+	bx, by, bw := int(x), int(y), int(w)
+	buf.FillRect(bx, by, bw, int(h), Cell{Rune: ' ', Style: p.style})
+	buf.DrawBorder(bx, by, bw, int(h), p.border, p.style)
+
+	inner := bw - 4
+	switch {
+	case pr.recalling:
+		buf.WriteStringFast(bx+2, by+1, "history: ", p.descStyle, inner)
+		drawLine(buf, bx+11, by+1, inner-9, pr.recall.query, pr.recall.cursor, p.style)
+	default:
+		buf.WriteStringFast(bx+2, by+1, "> ", p.style, inner)
+		drawLine(buf, bx+4, by+1, inner-2, pr.line, pr.cursor, p.style)
+	}
+
+	switch {
+	case n == 0 && pr.recalling:
+		buf.WriteStringFast(bx+2, by+2, "no matching history", p.descStyle, inner)
+	case n == 0:
+		buf.WriteStringFast(bx+2, by+2, "no matching commands", p.descStyle, inner)
+	case pr.recalling:
+		pr.drawRecall(buf, bx+1, by+2, bw-2, rows, p.style, p.selStyle)
+	case pr.cmd != nil && len(pr.comps) > 0:
+		p.drawCompletions(buf, bx, by+2, bw, rows)
+	default:
+		p.drawCommands(buf, bx, by+2, bw, rows)
+	}
+}
+
+func (p *CommandPaletteC) drawCommands(buf *Buffer, bx, by, bw, rows int) {
+	inner := bw - 4
+	top := max(p.sel-rows+1, 0)
+	nameW := 0
+	for _, cmd := range p.matches {
+		nameW = max(nameW, runewidth.StringWidth(cmd.Name))
+	}
+	nameW = min(nameW, inner/2)
+	for i := 0; i < rows; i++ {
+		cmd := p.matches[top+i]
+		row := by + i
+		style, desc, key := p.style, p.descStyle, p.keyStyle
+		if top+i == p.sel {
+			style = p.selStyle
+			desc, key = style.Dim(), style
+			buf.FillRect(bx+1, row, bw-2, 1, Cell{Rune: ' ', Style: style})
+		}
+		buf.WriteStringFast(bx+2, row, cmd.Name, style, nameW)
+		right := 0
+		if k := cmd.KeyPattern(); k != "" {
+			label := keyLabel(k)
+			right = runewidth.StringWidth(label)
+			buf.WriteStringFast(bx+bw-2-right, row, label, key, right)
+			right++
+		}
+		if descW := inner - nameW - 2 - right; descW > 0 {
+			buf.WriteStringFast(bx+2+nameW+2, row, cmd.Desc, desc, descW)
+		}
+	}
+}
+
+func (p *CommandPaletteC) drawCompletions(buf *Buffer, bx, by, bw, rows int) {
+	pr := &p.prompt
+	top := max(pr.compSel-rows+1, 0)
+	for i := 0; i < rows; i++ {
+		row := by + i
+		style := p.style
+		if top+i == pr.compSel {
+			style = p.selStyle
+			buf.FillRect(bx+1, row, bw-2, 1, Cell{Rune: ' ', Style: style})
+		}
+		buf.WriteStringFast(bx+2, row, pr.comps[top+i], style, bw-4)
+	}
+}
@@ -0,0 +1,112 @@
+package glyph
+
+// Pattern is a texture for a box's background, drawn over its Fill and
+// under its border and children: a tile of cells repeated from the box's
+// top-left corner, or a ramp of runes stretched across it.
+//
+//	VBox.Pattern(ShadeLight)(...)                         // a disabled panel
+//	VBox.Pattern(Checkerboard(BrightBlack, Black))(...)   // a placeholder
+//	HBox.Pattern(Ramp("░▒▓█").Style(Style{FG: Blue}))(...)
+//	VBox.Pattern(Tile("╱ ", " ╱"))(...)                     // hatching
+type Pattern struct {
+	tile      [][]Cell
+	ramp      []rune
+	rampStyle Style
+	vertical  bool
+}
+
+// The shades of the block elements, for dimmed or disabled areas.
+var (
+	ShadeLight  = Tile("░")
+	ShadeMedium = Tile("▒")
+	ShadeDark   = Tile("▓")
+)
+
+// Tile makes a pattern that repeats rows, one string per row. Rows can
+// differ in length; each repeats on its own.
+func Tile(rows ...string) Pattern {
+	var p Pattern
+	for _, row := range rows {
+		var cells []Cell
+		for _, r := range row {
+			cells = append(cells, Cell{Rune: r})
+		}
+		if len(cells) > 0 {
+			p.tile = append(p.tile, cells)
+		}
+	}
+	return p
+}
+
+// Checkerboard makes a pattern of squares in two background colours.
+// Each square is two cells wide, which a terminal draws about square.
+func Checkerboard(a, b Color) Pattern {
+	ca := Cell{Rune: ' ', Style: Style{BG: a}}
+	cb := Cell{Rune: ' ', Style: Style{BG: b}}
+	return Pattern{tile: [][]Cell{
+		{ca, ca, cb, cb},
+		{cb, cb, ca, ca},
+	}}
+}
+
+// Ramp makes a pattern that steps through runes from the box's left edge
+// to its right, such as "░▒▓█" for a shading ramp, however wide the box.
+func Ramp(runes string) Pattern {
+	return Pattern{ramp: []rune(runes)}
+}
+
+// Vertical runs a Ramp from the top of the box to the bottom instead.
+func (p Pattern) Vertical() Pattern {
+	p.vertical = true
+	return p
+}
+
+// Style sets the style of a pattern's runes. A Checkerboard keeps its
+// background colours.
+func (p Pattern) Style(s Style) Pattern {
+	tile := make([][]Cell, len(p.tile))
+	for i, row := range p.tile {
+		tile[i] = make([]Cell, len(row))
+		for j, c := range row {
+			bg := c.Style.BG
+			c.Style = s
+			if bg.Mode != ColorDefault {
+				c.Style.BG = bg
+			}
+			tile[i][j] = c
+		}
+	}
+	p.tile = tile
+	p.rampStyle = s
+	return p
+}
+
+// draw paints the pattern over a box. Cells without a background of
+// their own take bg, the box's fill.
+func (p *Pattern) draw(buf *Buffer, x, y, w, h int, bg Color) {
+	if w <= 0 || h <= 0 {
+		return
+	}
+	for row := range h {
+		for col := range w {
+			var c Cell
+			switch {
+			case len(p.ramp) > 0:
+				n, i := w, col
+				if p.vertical {
+					n, i = h, row
+				}
+				c = Cell{Rune: p.ramp[i*len(p.ramp)/n], Style: p.rampStyle}
+			case len(p.tile) > 0:
+				tr := p.tile[row%len(p.tile)]
+				c = tr[col%len(tr)]
+			default:
+				return
+			}
+			if c.Style.BG.Mode == ColorDefault {
+				c.Style.BG = bg
+			}
+			buf.SetFast(x+col, y+row, c)
+		}
+	}
+}
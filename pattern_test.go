@@ -0,0 +1,61 @@
+package glyph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPatternFill(t *testing.T) {
+	tests := []struct {
+		name string
+		view any
+		want []string
+	}{
+		{"tile", VBox.Grow(1).Pattern(Tile("╱ ", " ╱"))(Text("hi")), []string{
+			"hi╱ ╱",
+			" ╱ ╱ ╱",
+			"╱ ╱ ╱",
+		}},
+		{"shade under a border", VBox.Border(BorderSingle).Pattern(ShadeLight)(Text("x")), []string{
+			"┌────┐",
+			"│x░░░│",
+			"└────┘",
+		}},
+		{"ramp", HBox.Grow(1).Pattern(Ramp("░▒▓"))(Space()), []string{
+			"░░▒▒▓▓",
+			"░░▒▒▓▓",
+			"░░▒▒▓▓",
+		}},
+		{"vertical ramp", HBox.Grow(1).Pattern(Ramp("·:").Vertical())(Space()), []string{
+			"······",
+			"······",
+			"::::::",
+		}},
+	}
+	for _, tt := range tests {
+		got := messageLines(Build(VBox.Height(3)(tt.view)), 6, 3)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s:\n%q\nwant\n%q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCheckerboard(t *testing.T) {
+	buf := NewBuffer(6, 2)
+	Build(VBox.Height(2).Pattern(Checkerboard(Red, Blue).Style(Style{FG: White}))(Space())).Execute(buf, 6, 2)
+	for _, c := range []struct {
+		x, y int
+		bg   Color
+	}{{0, 0, Red}, {1, 0, Red}, {2, 0, Blue}, {4, 0, Red}, {0, 1, Blue}, {2, 1, Red}} {
+		if got := buf.Get(c.x, c.y).Style; got.BG != c.bg || got.FG != White {
+			t.Errorf("%d,%d: %+v, want BG %v", c.x, c.y, got, c.bg)
+		}
+	}
+
+	// a tile's cells take the box's fill
+	buf = NewBuffer(4, 1)
+	Build(HBox.Fill(Green).Pattern(ShadeDark)(Space())).Execute(buf, 4, 1)
+	if c := buf.Get(3, 0); c.Rune != '▓' || c.Style.BG != Green {
+		t.Errorf("filled shade %+v", c)
+	}
+}
@@ -0,0 +1,66 @@
+package glyph
+
+// layerPin is a live view anchored to a spot in a LayerView's content.
+type layerPin struct {
+	x, y int
+	view any
+	tmpl *Template
+}
+
+// Pin renders view live at column x, row y of the layer's content. When
+// that spot scrolls out of the viewport the view docks in the top or
+// bottom right corner, on the side it left by, like a video mini-player,
+// so a status summary stays visible all the way down a long page. Leave
+// blank rows in the content for the view to sit on.
+//
+//	LayerView(page).Grow(1).Pin(0, 12, VBox.Border(BorderRounded)(Text(&nowPlaying)))
+func (l LayerViewC) Pin(x, y int, view any) LayerViewC {
+	l.pins = append(l.pins, layerPin{x: x, y: y, view: view})
+	return l
+}
+
+// compilePins builds each pinned view as its own template.
+func (t *Template) compilePins(pins []layerPin) []layerPin {
+	out := make([]layerPin, len(pins))
+	for i, p := range pins {
+		sub := newSubTemplate(p.view, nil, 0)
+		t.pendingBindings = append(t.pendingBindings, sub.pendingBindings...)
+		t.adoptLive(sub)
+		p.tmpl = sub
+		out[i] = p
+	}
+	return out
+}
+
+// renderPins draws a layer's pinned views over its blitted viewport at
+// x,y, docking any whose spot is out of view.
+func (t *Template) renderPins(buf *Buffer, op *Op, x, y, w, h int) {
+	l := op.LayerPtr
+	for i := range op.LayerPins {
+		p := &op.LayerPins[i]
+		sub := p.tmpl
+		if len(sub.ops) == 0 || w-p.x <= 0 {
+			continue
+		}
+		sub.app = t.app
+		sub.distributeWidths(int16(w-p.x), nil)
+		sub.layout(int16(h))
+		pw, ph := int(sub.geom[0].W), int(sub.geom[0].H)
+		if pw <= 0 || ph <= 0 || ph > h {
+			continue
+		}
+		sub.distributeFlexGrow(int16(ph))
+
+		px, py := p.x-l.ScrollX(), p.y-l.ScrollY()
+		switch {
+		case py < 0:
+			px, py = w-pw, 0
+		case py+ph > h:
+			px, py = w-pw, h-ph
+		default:
+			px = max(min(px, w-pw), 0)
+		}
+		buf.FillRect(x+px, y+py, pw, ph, Cell{Rune: ' '})
+		sub.render(buf, int16(x+px), int16(y+py), int16(pw))
+	}
+}
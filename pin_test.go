@@ -0,0 +1,44 @@
+package glyph
+
+import "testing"
+
+func TestLayerPin(t *testing.T) {
+	layer := NewLayer()
+	buf := NewBuffer(10, 20)
+	for y := 0; y < 20; y++ {
+		buf.WriteStringFast(0, y, "row", Style{}, 10)
+	}
+	layer.SetBuffer(buf)
+
+	status := "live"
+	tmpl := Build(VBox(LayerView(layer).ViewWidth(10).ViewHeight(4).Pin(2, 5, Text(&status))))
+	frame := func() *Buffer {
+		screen := NewBuffer(10, 4)
+		tmpl.Execute(screen, 10, 4)
+		return screen
+	}
+
+	// spot below the viewport: docked bottom right
+	screen := frame()
+	if got := screen.GetLine(3); got != "row   live" {
+		t.Errorf("docked bottom = %q", got)
+	}
+
+	// spot in view: drawn in place
+	layer.ScrollTo(3)
+	status = "seen"
+	screen = frame()
+	if got := screen.GetLine(2); got != "roseen" {
+		t.Errorf("in place = %q", got)
+	}
+
+	// spot scrolled past: docked top right
+	layer.ScrollTo(10)
+	screen = frame()
+	if got := screen.GetLine(0); got != "row   seen" {
+		t.Errorf("docked top = %q", got)
+	}
+	if got := screen.GetLine(1); got != "row" {
+		t.Errorf("line 1 = %q", got)
+	}
+}
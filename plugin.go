@@ -0,0 +1,166 @@
+package glyph
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Plugin extends an app with commands, components and hooks, so that
+// editors and dashboards built on glyph can take add-ons written
+// separately. Every plugin has a name and is initialised with the app it
+// joins; it adds to the app by also implementing any of CommandPlugin,
+// ComponentPlugin and HookPlugin:
+//
+//	type gitStatus struct{ branch string }
+//
+//	func (g *gitStatus) Name() string            { return "git-status" }
+//	func (g *gitStatus) Init(app *glyph.App) error { g.branch = currentBranch(); return nil }
+//	func (g *gitStatus) Commands() []glyph.PluginCommand {
+//	    return []glyph.PluginCommand{{Name: "branch", Desc: "Show the branch", Fn: g.show}}
+//	}
+//
+//	func init() { glyph.RegisterPlugin("git-status", func() glyph.Plugin { return &gitStatus{} }) }
+//
+// An app picks plugins up with Use, or with UsePlugins for those registered
+// by name. The glyph/plugins package loads them from Go plugin files and
+// from other processes.
+type Plugin interface {
+	Name() string
+	Init(app *App) error
+}
+
+// CommandPlugin is a Plugin that adds commands to the app's Commands.
+type CommandPlugin interface {
+	Plugin
+	Commands() []PluginCommand
+}
+
+// ComponentPlugin is a Plugin that adds component types to the app's
+// ViewLoader, for views loaded from descriptions.
+type ComponentPlugin interface {
+	Plugin
+	Components() map[string]ComponentFunc
+}
+
+// HookPlugin is a Plugin that runs code at points in the app's life.
+type HookPlugin interface {
+	Plugin
+	Hooks() Hooks
+}
+
+// PluginCommand is a command a plugin adds. Fn is a function as
+// Commands.Add takes, and Key, if set, binds it to a key.
+type PluginCommand struct {
+	Name string
+	Desc string
+	Fn   any
+	Key  string
+}
+
+// Hooks are functions a plugin has run at points in the app's life. They
+// run after the app's own OnBeforeRender, OnAfterRender and OnResize, and
+// with its OnShutdown hooks. Any may be nil.
+type Hooks struct {
+	BeforeRender func()
+	AfterRender  func()
+	Resize       func(width, height int)
+	Shutdown     func(reason ShutdownReason)
+}
+
+var pluginRegistry struct {
+	mu   sync.Mutex
+	news map[string]func() Plugin
+}
+
+// RegisterPlugin makes a plugin available to UsePlugins under name,
+// usually from the init function of the package that provides it, so that
+// importing the package is enough to offer it. It panics if name is taken.
+func RegisterPlugin(name string, newPlugin func() Plugin) {
+	pluginRegistry.mu.Lock()
+	defer pluginRegistry.mu.Unlock()
+	if pluginRegistry.news == nil {
+		pluginRegistry.news = map[string]func() Plugin{}
+	}
+	if _, dup := pluginRegistry.news[name]; dup {
+		panic(fmt.Sprintf("RegisterPlugin: %q registered twice", name))
+	}
+	pluginRegistry.news[name] = newPlugin
+}
+
+// RegisteredPlugins returns the names plugins are registered under, sorted.
+func RegisteredPlugins() []string {
+	pluginRegistry.mu.Lock()
+	defer pluginRegistry.mu.Unlock()
+	names := make([]string, 0, len(pluginRegistry.news))
+	for name := range pluginRegistry.news {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// UsePlugins uses the plugins registered under names, or every registered
+// plugin if no names are given.
+func (a *App) UsePlugins(names ...string) error {
+	if len(names) == 0 {
+		names = RegisteredPlugins()
+	}
+	plugins := make([]Plugin, 0, len(names))
+	for _, name := range names {
+		pluginRegistry.mu.Lock()
+		newPlugin, ok := pluginRegistry.news[name]
+		pluginRegistry.mu.Unlock()
+		if !ok {
+			return fmt.Errorf("plugin %q is not registered", name)
+		}
+		plugins = append(plugins, newPlugin())
+	}
+	return a.Use(plugins...)
+}
+
+// Use initialises plugins and adds their commands, components and hooks
+// to the app, in order. It stops at the first plugin whose Init fails.
+func (a *App) Use(plugins ...Plugin) error {
+	for _, p := range plugins {
+		if err := p.Init(a); err != nil {
+			return fmt.Errorf("plugin %s: %w", p.Name(), err)
+		}
+		if cp, ok := p.(CommandPlugin); ok {
+			for _, c := range cp.Commands() {
+				cmd := a.Command(c.Name, c.Desc, c.Fn)
+				if c.Key != "" {
+					cmd.Key(c.Key)
+				}
+			}
+		}
+		if cp, ok := p.(ComponentPlugin); ok {
+			for typ, fn := range cp.Components() {
+				a.ViewLoader().Register(typ, fn)
+			}
+		}
+		if hp, ok := p.(HookPlugin); ok {
+			h := hp.Hooks()
+			a.hooks = append(a.hooks, h)
+			if h.Shutdown != nil {
+				a.OnShutdown(h.Shutdown)
+			}
+		}
+		a.plugins = append(a.plugins, p)
+	}
+	return nil
+}
+
+// Plugins returns the plugins the app uses, in the order they were added.
+func (a *App) Plugins() []Plugin {
+	return a.plugins
+}
+
+// ViewLoader returns the app's view loader, which has the component types
+// of the plugins the app uses registered.
+func (a *App) ViewLoader() *ViewLoader {
+	if a.viewLoader == nil {
+		a.viewLoader = NewViewLoader()
+	}
+	return a.viewLoader
+}
@@ -0,0 +1,87 @@
+package glyph
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+type testPlugin struct {
+	name    string
+	app     *App
+	renders int
+	failing bool
+}
+
+func (p *testPlugin) Name() string { return p.name }
+
+func (p *testPlugin) Init(app *App) error {
+	if p.failing {
+		return errors.New("no config")
+	}
+	p.app = app
+	return nil
+}
+
+func (p *testPlugin) Commands() []PluginCommand {
+	return []PluginCommand{{Name: p.name + "-hello", Desc: "Say hello", Key: "H", Fn: func() {}}}
+}
+
+func (p *testPlugin) Components() map[string]ComponentFunc {
+	return map[string]ComponentFunc{"shout": func(n ViewNode, _ []any) (any, error) {
+		return Text(n.String("text") + "!"), nil
+	}}
+}
+
+func (p *testPlugin) Hooks() Hooks {
+	return Hooks{BeforeRender: func() { p.renders++ }}
+}
+
+func TestPlugins(t *testing.T) {
+	RegisterPlugin("test-a", func() Plugin { return &testPlugin{name: "a"} })
+	RegisterPlugin("test-b", func() Plugin { return &testPlugin{name: "b", failing: true} })
+	defer func() {
+		pluginRegistry.mu.Lock()
+		delete(pluginRegistry.news, "test-a")
+		delete(pluginRegistry.news, "test-b")
+		pluginRegistry.mu.Unlock()
+	}()
+
+	app, _ := NewApp()
+	be := NewBufferBackend(20, 2)
+	app.SetBackend(be)
+	if err := app.UsePlugins("test-a"); err != nil {
+		t.Fatal(err)
+	}
+	p := app.Plugins()[0].(*testPlugin)
+	if p.app != app {
+		t.Error("Init not called with the app")
+	}
+	if _, err := app.Commands().Get("a-hello"); err != nil {
+		t.Error(err)
+	}
+
+	view, err := app.ViewLoader().LoadJSON([]byte(`{"type": "shout", "text": "hi"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.SetView(view)
+	app.RenderNow()
+	app.Input().Dispatch(riffkey.Key{Rune: 'H'})
+	app.RenderNow()
+	if got := be.Frame().GetLine(0); got != "hi!" || p.renders != 2 {
+		t.Errorf("line %q after %d renders", got, p.renders)
+	}
+
+	if err := app.UsePlugins("test-b"); err == nil || err.Error() != "plugin b: no config" {
+		t.Errorf("failing init: %v", err)
+	}
+	if err := app.UsePlugins("nope"); err == nil {
+		t.Error("used an unregistered plugin")
+	}
+	if got := RegisteredPlugins(); !reflect.DeepEqual(got, []string{"test-a", "test-b"}) {
+		t.Errorf("registered %q", got)
+	}
+}
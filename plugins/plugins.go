@@ -0,0 +1,50 @@
+// Package plugins loads glyph plugins from outside the program: from Go
+// plugin files built with -buildmode=plugin, and from other processes that
+// serve commands over their standard input and output.
+//
+//	p, err := plugins.Open("~/.config/myapp/plugins/git.so")
+//	...
+//	p, err := plugins.Exec("myapp-jira", "--project", "OPS")
+//	...
+//	err = app.Use(p)
+//
+// Plugins compiled into the program need neither; they register with
+// glyph.RegisterPlugin and the app uses them with UsePlugins.
+package plugins
+
+import (
+	"fmt"
+	goplugin "plugin"
+
+	"github.com/kungfusheep/glyph"
+)
+
+// Open loads a plugin from a Go plugin file. The file's main package must
+// declare a function NewPlugin returning a glyph.Plugin, or a variable
+// Plugin of type glyph.Plugin, and be built with the same version of glyph
+// as the program:
+//
+//	func NewPlugin() glyph.Plugin { return &gitStatus{} }
+//
+// Go plugin files work on Linux, FreeBSD and macOS only.
+func Open(path string) (glyph.Plugin, error) {
+	lib, err := goplugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if sym, err := lib.Lookup("NewPlugin"); err == nil {
+		if newPlugin, ok := sym.(func() glyph.Plugin); ok {
+			return newPlugin(), nil
+		}
+		return nil, fmt.Errorf("plugins: %s: NewPlugin is %T, not func() glyph.Plugin", path, sym)
+	}
+	sym, err := lib.Lookup("Plugin")
+	if err != nil {
+		return nil, fmt.Errorf("plugins: %s exports neither Plugin nor NewPlugin", path)
+	}
+	// a variable is looked up as a pointer to it
+	if p, ok := sym.(*glyph.Plugin); ok && *p != nil {
+		return *p, nil
+	}
+	return nil, fmt.Errorf("plugins: %s: Plugin is %T, not glyph.Plugin", path, sym)
+}
@@ -0,0 +1,67 @@
+package plugins
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/kungfusheep/glyph"
+)
+
+// TestHelperProcess is the plugin process TestExec runs.
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("GLYPH_TEST_PLUGIN") != "1" {
+		t.Skip("run by TestExec")
+	}
+	Serve("echo",
+		glyph.PluginCommand{Name: "greet", Desc: "Write a greeting", Key: "g", Fn: func(name string) error {
+			if name == "nobody" {
+				return errors.New("who?")
+			}
+			return os.WriteFile(os.Getenv("GLYPH_TEST_OUT"), []byte("hello "+name), 0o644)
+		}},
+	)
+	os.Exit(0)
+}
+
+func TestExec(t *testing.T) {
+	out := t.TempDir() + "/out"
+	t.Setenv("GLYPH_TEST_PLUGIN", "1")
+	t.Setenv("GLYPH_TEST_OUT", out)
+	p, err := Exec(os.Args[0], "-test.run=^TestHelperProcess$")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+	if p.Name() != "echo" {
+		t.Errorf("name %q", p.Name())
+	}
+
+	app, _ := glyph.NewApp()
+	if err := app.Use(p); err != nil {
+		t.Fatal(err)
+	}
+	cmd, err := app.Commands().Get("greet")
+	if err != nil || cmd.Desc != "Write a greeting" || cmd.KeyPattern() != "g" {
+		t.Fatalf("command %+v, %v", cmd, err)
+	}
+	if err := app.Commands().Run("greet world"); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(out); string(data) != "hello world" {
+		t.Errorf("wrote %q", data)
+	}
+	if err := app.Commands().Run("greet nobody"); err == nil || err.Error() != "who?" {
+		t.Errorf("error %v", err)
+	}
+	if err := app.Commands().Run("greet"); err == nil || !strings.Contains(err.Error(), "usage") {
+		t.Errorf("missing argument: %v", err)
+	}
+}
+
+func TestOpenMissing(t *testing.T) {
+	if _, err := Open(t.TempDir() + "/missing.so"); err == nil {
+		t.Error("opened a missing file")
+	}
+}
@@ -0,0 +1,174 @@
+package plugins
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"os/exec"
+
+	"github.com/kungfusheep/glyph"
+)
+
+// The protocol between an app and a plugin process is JSON-RPC 1.0 over
+// the process's standard input and output, one request at a time, with
+// two methods:
+//
+//	Plugin.Describe(0) → Description
+//	Plugin.Run(Call)   → 0, or an error
+//
+// Serve implements it for plugins written in Go; a plugin in another
+// language answers the same requests.
+
+// Description is what a plugin process tells the app about itself.
+type Description struct {
+	Name     string
+	Commands []CommandInfo
+}
+
+// CommandInfo describes a command a plugin process offers. Its arguments
+// are passed as strings.
+type CommandInfo struct {
+	Name string
+	Desc string
+	Key  string // a key to bind it to, or ""
+}
+
+// Call asks a plugin process to run one of its commands.
+type Call struct {
+	Command string
+	Args    []string
+}
+
+// Process is a plugin running in another process, started by Exec. Its
+// commands run in the process, and it's stopped when the app shuts down.
+type Process struct {
+	desc   Description
+	cmd    *exec.Cmd
+	client *rpc.Client
+}
+
+// Exec starts a plugin process, running name with args, and asks it for
+// its commands. The process's standard error is discarded, as the app
+// owns the terminal.
+func Exec(name string, args ...string) (*Process, error) {
+	cmd := exec.Command(name, args...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	p := &Process{cmd: cmd, client: jsonrpc.NewClient(pipe{stdout, stdin})}
+	if err := p.client.Call("Plugin.Describe", 0, &p.desc); err != nil {
+		p.Close()
+		return nil, fmt.Errorf("plugins: %s: %w", name, err)
+	}
+	if p.desc.Name == "" {
+		p.desc.Name = name
+	}
+	return p, nil
+}
+
+// Name returns the name the process gave.
+func (p *Process) Name() string { return p.desc.Name }
+
+// Init does nothing; the process set itself up when it started.
+func (p *Process) Init(*glyph.App) error { return nil }
+
+// Commands returns the process's commands, which run in the process.
+func (p *Process) Commands() []glyph.PluginCommand {
+	cmds := make([]glyph.PluginCommand, len(p.desc.Commands))
+	for i, c := range p.desc.Commands {
+		cmds[i] = glyph.PluginCommand{Name: c.Name, Desc: c.Desc, Key: c.Key, Fn: func(args ...string) error {
+			return p.Run(c.Name, args...)
+		}}
+	}
+	return cmds
+}
+
+// Hooks stops the process when the app shuts down.
+func (p *Process) Hooks() glyph.Hooks {
+	return glyph.Hooks{Shutdown: func(glyph.ShutdownReason) { p.Close() }}
+}
+
+// Run runs one of the process's commands.
+func (p *Process) Run(command string, args ...string) error {
+	var ok int
+	err := p.client.Call("Plugin.Run", Call{Command: command, Args: args}, &ok)
+	var serverErr rpc.ServerError
+	if errors.As(err, &serverErr) {
+		return errors.New(string(serverErr))
+	}
+	return err
+}
+
+// Close closes the process's input, which tells it to exit, and waits for
+// it to.
+func (p *Process) Close() error {
+	p.client.Close()
+	return p.cmd.Wait()
+}
+
+// Serve answers an app's requests on standard input and output, for a Go
+// program run as a plugin with Exec. It returns once the app closes its
+// input.
+//
+//	func main() {
+//	    plugins.Serve("jira", glyph.PluginCommand{Name: "ticket", Desc: "Open a ticket", Fn: openTicket})
+//	}
+func Serve(name string, cmds ...glyph.PluginCommand) error {
+	return serve(pipe{os.Stdin, os.Stdout}, name, cmds)
+}
+
+func serve(conn io.ReadWriteCloser, name string, cmds []glyph.PluginCommand) error {
+	s := &service{name: name, cmds: glyph.NewCommands()}
+	for _, c := range cmds {
+		s.cmds.Add(c.Name, c.Desc, c.Fn)
+		s.info = append(s.info, CommandInfo{Name: c.Name, Desc: c.Desc, Key: c.Key})
+	}
+	srv := rpc.NewServer()
+	if err := srv.RegisterName("Plugin", s); err != nil {
+		return err
+	}
+	srv.ServeCodec(jsonrpc.NewServerCodec(conn))
+	return nil
+}
+
+// service is the RPC receiver Serve publishes.
+type service struct {
+	name string
+	cmds *glyph.Commands
+	info []CommandInfo
+}
+
+func (s *service) Describe(_ int, d *Description) error {
+	*d = Description{Name: s.name, Commands: s.info}
+	return nil
+}
+
+func (s *service) Run(c Call, _ *int) error {
+	cmd, err := s.cmds.Get(c.Command)
+	if err != nil {
+		return err
+	}
+	return cmd.Call(c.Args...)
+}
+
+// pipe joins a reader and a writer into the connection RPC wants.
+type pipe struct {
+	io.ReadCloser
+	io.WriteCloser
+}
+
+func (p pipe) Close() error {
+	p.WriteCloser.Close()
+	return p.ReadCloser.Close()
+}
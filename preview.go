@@ -0,0 +1,243 @@
+package glyph
+
+import (
+	"context"
+	"sync"
+)
+
+// A Previewer draws the items it handles, such as files of one kind, for a
+// Preview pane. Both methods run in the background, off the UI goroutine.
+type Previewer interface {
+	// Handles reports whether the previewer can show item.
+	Handles(item string) bool
+	// Preview draws item to fit w by h cells. It should give up when ctx
+	// is done, as it is once the selection moves on.
+	Preview(ctx context.Context, item string, w, h int) (*Buffer, error)
+}
+
+// PreviewerFunc makes a Previewer from a pair of functions.
+func PreviewerFunc(handles func(item string) bool, preview func(ctx context.Context, item string, w, h int) (*Buffer, error)) Previewer {
+	return previewerFunc{handles, preview}
+}
+
+type previewerFunc struct {
+	handles func(string) bool
+	preview func(context.Context, string, int, int) (*Buffer, error)
+}
+
+func (p previewerFunc) Handles(item string) bool { return p.handles(item) }
+
+func (p previewerFunc) Preview(ctx context.Context, item string, w, h int) (*Buffer, error) {
+	return p.preview(ctx, item, w, h)
+}
+
+// DefaultPreviewers returns the previewers a Preview uses unless told
+// otherwise, for paths: directory listings, images, text with syntax
+// highlighting, and a hex dump of anything else.
+func DefaultPreviewers() []Previewer {
+	return []Previewer{DirPreviewer(), ImagePreviewer(), TextPreviewer(), HexPreviewer()}
+}
+
+// PreviewC shows the item *item names, drawn by the first of its
+// previewers that handles it, as a file manager previews the file under
+// the cursor:
+//
+//	Preview(&selectedPath).Grow(1)
+//	Preview(&selectedKey).Previewers(PreviewerFunc(isRecord, drawRecord))
+//
+// Previews are made in the background, so a slow one doesn't hold up
+// frames, and one still going when the item changes is cancelled. The
+// pane keeps the last preview until the next is ready. On terminals with
+// the kitty graphics protocol, images are shown at full resolution.
+type PreviewC struct {
+	layer      *Layer
+	item       *string
+	previewers []Previewer
+	grow       float32
+	height     int16
+	tmpl       *Template // for the app that images are placed through
+
+	shown  previewKey // the preview on show or being made
+	cancel context.CancelFunc
+	mu     sync.Mutex
+	ready  *previewResult // made in the background, not yet shown
+	update func()         // set to app.RequestRender during wiring
+	image  *placedImage   // kitty image on show, if any
+}
+
+// previewKey is what a preview was made for.
+type previewKey struct {
+	item  string
+	w, h  int
+	image bool // as a kitty image rather than cells
+}
+
+type previewResult struct {
+	key previewKey
+	buf *Buffer
+	png []byte // for a kitty image
+	err error
+}
+
+// Preview creates a pane previewing *item with DefaultPreviewers.
+func Preview(item *string) *PreviewC {
+	p := &PreviewC{
+		layer:      NewLayer(),
+		item:       item,
+		previewers: DefaultPreviewers(),
+	}
+	p.layer.AlwaysRender = true
+	p.layer.Render = p.sync
+	return p
+}
+
+// Previewers replaces the previewers, tried in order.
+func (p *PreviewC) Previewers(ps ...Previewer) *PreviewC {
+	p.previewers = ps
+	return p
+}
+
+// With puts ps ahead of the previewers already set.
+func (p *PreviewC) With(ps ...Previewer) *PreviewC {
+	p.previewers = append(append([]Previewer(nil), ps...), p.previewers...)
+	return p
+}
+
+// Grow sets the flex grow factor.
+func (p *PreviewC) Grow(g float32) *PreviewC {
+	p.grow = g
+	return p
+}
+
+// Height sets a fixed viewport height.
+func (p *PreviewC) Height(h int16) *PreviewC {
+	p.height = h
+	return p
+}
+
+func (p *PreviewC) updateHook() *func() { return &p.update }
+
+func (t *Template) compilePreviewC(p *PreviewC, parent int16, depth int) int16 {
+	t.collectUpdateHook(p)
+	p.tmpl = t
+	layerView := LayerView(p.layer).Grow(p.grow)
+	if p.height > 0 {
+		layerView = layerView.ViewHeight(p.height)
+	}
+	return t.compileLayerViewC(layerView, parent, depth)
+}
+
+// sync starts a preview when the item or size has changed, and shows one
+// that has finished.
+func (p *PreviewC) sync() {
+	w, h := p.layer.ViewportWidth(), p.layer.ViewportHeight()
+	if w <= 0 || h <= 0 {
+		return
+	}
+	screen := p.graphics()
+	key := previewKey{item: *p.item, w: w, h: h, image: screen != nil}
+	if key != p.shown {
+		p.start(key)
+	}
+	p.mu.Lock()
+	r := p.ready
+	p.ready = nil
+	p.mu.Unlock()
+	if r != nil && r.key == p.shown {
+		p.show(r, screen)
+	}
+	if p.image != nil && screen != nil {
+		p.image.place(screen, p.layer.screenX, p.layer.screenY)
+	}
+}
+
+// start cancels the preview being made and makes one for key.
+func (p *PreviewC) start(key previewKey) {
+	if p.cancel != nil {
+		p.cancel()
+		p.cancel = nil
+	}
+	p.shown = key
+	if key.item == "" {
+		p.show(&previewResult{key: key, buf: NewBuffer(key.w, key.h)}, p.graphics())
+		return
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	previewers := p.previewers
+	go func() {
+		r := makePreview(ctx, previewers, key)
+		if ctx.Err() != nil {
+			return
+		}
+		p.mu.Lock()
+		p.ready = r
+		p.mu.Unlock()
+		if p.update != nil {
+			p.update()
+		}
+	}()
+}
+
+// makePreview runs the first previewer that handles key's item.
+func makePreview(ctx context.Context, previewers []Previewer, key previewKey) *previewResult {
+	r := &previewResult{key: key}
+	for _, pv := range previewers {
+		if !pv.Handles(key.item) {
+			continue
+		}
+		if img, ok := pv.(imagePreviewer); ok && key.image {
+			r.png, r.err = img.png(ctx, key.item)
+		} else {
+			r.buf, r.err = pv.Preview(ctx, key.item, key.w, key.h)
+		}
+		return r
+	}
+	r.buf = NewBuffer(key.w, key.h)
+	r.buf.WriteStringFast(0, 0, "no preview", Style{Attr: AttrDim}, key.w)
+	return r
+}
+
+// show puts a finished preview in the pane.
+func (p *PreviewC) show(r *previewResult, screen *Screen) {
+	if p.image != nil && screen != nil {
+		p.image.remove(screen)
+	}
+	p.image = nil
+	out := r.buf
+	switch {
+	case r.err != nil:
+		out = NewBuffer(r.key.w, r.key.h)
+		out.WriteStringFast(0, 0, r.err.Error(), Style{FG: Red}, r.key.w)
+	case r.png != nil:
+		out = NewBuffer(r.key.w, r.key.h)
+		p.image = newPlacedImage(r.png, r.key.w, r.key.h, p.cellAspect())
+	case out == nil:
+		out = NewBuffer(r.key.w, r.key.h)
+	}
+	p.layer.SetBuffer(out)
+}
+
+// graphics returns the screen to place kitty images on, or nil when the
+// app's terminal doesn't take them.
+func (p *PreviewC) graphics() *Screen {
+	if p.tmpl == nil || p.tmpl.app == nil {
+		return nil
+	}
+	a := p.tmpl.app
+	t, ok := a.backend.(*terminalBackend)
+	if !ok || !a.Capabilities().KittyGraphics {
+		return nil
+	}
+	return t.screen
+}
+
+// cellAspect returns a cell's height over its width, 2 if unknown.
+func (p *PreviewC) cellAspect() float64 {
+	if p.tmpl != nil && p.tmpl.app != nil {
+		if c := p.tmpl.app.Capabilities(); c.CellWidth > 0 && c.CellHeight > 0 {
+			return float64(c.CellHeight) / float64(c.CellWidth)
+		}
+	}
+	return 2
+}
@@ -0,0 +1,112 @@
+package glyph
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// previewFrames renders tmpl until done reports true or a second passes.
+func previewFrames(t *testing.T, tmpl *Template, w, h int, done func(*Buffer) bool) *Buffer {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		buf := NewBuffer(w, h)
+		tmpl.Execute(buf, int16(w), int16(h))
+		if done(buf) || time.Now().After(deadline) {
+			return buf
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPreviewCancelsStale(t *testing.T) {
+	release := make(chan struct{})
+	cancelled := make(chan string, 1)
+	slow := PreviewerFunc(
+		func(item string) bool { return true },
+		func(ctx context.Context, item string, w, h int) (*Buffer, error) {
+			if item == "slow" {
+				select {
+				case <-ctx.Done():
+					cancelled <- item
+					return nil, ctx.Err()
+				case <-release:
+				}
+			}
+			out := NewBuffer(w, h)
+			out.WriteStringFast(0, 0, "showing "+item, Style{}, w)
+			return out, nil
+		})
+
+	item := "slow"
+	tmpl := Build(VBox(Preview(&item).Previewers(slow).Height(2)))
+	previewFrames(t, tmpl, 20, 2, func(*Buffer) bool { return true })
+
+	item = "fast"
+	buf := previewFrames(t, tmpl, 20, 2, func(b *Buffer) bool { return strings.Contains(b.GetLine(0), "fast") })
+	if got := strings.TrimSpace(buf.GetLine(0)); got != "showing fast" {
+		t.Errorf("line = %q", got)
+	}
+	select {
+	case got := <-cancelled:
+		if got != "slow" {
+			t.Errorf("cancelled %q", got)
+		}
+	case <-time.After(time.Second):
+		t.Error("stale preview not cancelled")
+	}
+	close(release)
+}
+
+func TestDefaultPreviewers(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	code := write("main.go", []byte("package main\n\n// hello\nfunc main() {}\n"))
+	bin := write("blob.bin", []byte{0x7f, 'E', 'L', 'F', 0, 1, 2})
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	for i := range 16 {
+		img.Set(i%4, i/4, color.RGBA{R: 255, A: 255})
+	}
+	f, _ := os.Create(filepath.Join(dir, "red.png"))
+	png.Encode(f, img)
+	f.Close()
+	os.Mkdir(filepath.Join(dir, "sub"), 0o755)
+
+	item := dir
+	tmpl := Build(VBox(Preview(&item).Height(4)))
+	show := func(path string, want string) *Buffer {
+		t.Helper()
+		item = path
+		buf := previewFrames(t, tmpl, 80, 4, func(b *Buffer) bool { return strings.Contains(b.String(), want) })
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("preview of %s lacks %q:\n%s", filepath.Base(path), want, buf.String())
+		}
+		return buf
+	}
+
+	if buf := show(dir, "sub/"); buf.GetLine(0) != "sub/" {
+		t.Errorf("directories not listed first:\n%s", buf.String())
+	}
+	if buf := show(code, "package main"); buf.Get(0, 0).Style.FG != Magenta || buf.Get(0, 2).Style.FG != BrightBlack {
+		t.Errorf("Go not highlighted:\n%s", buf.String())
+	}
+	show(bin, "7f 45 4c 46 00")
+	item = filepath.Join(dir, "red.png")
+	buf := previewFrames(t, tmpl, 80, 4, func(b *Buffer) bool { return b.Get(0, 0).Rune == '▀' })
+	if c := buf.Get(0, 0); c.Rune != '▀' || c.Style.FG != RGB(255, 0, 0) {
+		t.Errorf("image not drawn in half blocks: %q %+v", c.Rune, c.Style)
+	}
+}
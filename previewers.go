@@ -0,0 +1,249 @@
+package glyph
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/gif" // decoders for ImagePreviewer
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// DirPreviewer lists the entries of a directory, directories first.
+func DirPreviewer() Previewer {
+	return PreviewerFunc(isDir, previewDir)
+}
+
+func isDir(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && fi.IsDir()
+}
+
+func previewDir(ctx context.Context, path string, w, h int) (*Buffer, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(entries, func(a, b os.DirEntry) int {
+		switch {
+		case a.IsDir() == b.IsDir():
+			return strings.Compare(a.Name(), b.Name())
+		case a.IsDir():
+			return -1
+		}
+		return 1
+	})
+	out := NewBuffer(w, h)
+	if len(entries) == 0 {
+		out.WriteStringFast(0, 0, "empty", Style{Attr: AttrDim}, w)
+		return out, nil
+	}
+	for y, e := range entries {
+		if y == h-1 && len(entries) > h {
+			out.WriteStringFast(0, y, fmt.Sprintf("… %d more", len(entries)-y), Style{Attr: AttrDim}, w)
+			break
+		}
+		name, st := e.Name(), Style{}
+		if e.IsDir() {
+			name += "/"
+			st = Style{FG: Blue, Attr: AttrBold}
+		}
+		if strings.HasPrefix(e.Name(), ".") {
+			st.Attr |= AttrDim
+		}
+		out.WriteStringFast(0, y, name, st, w)
+	}
+	return out, ctx.Err()
+}
+
+// previewSniff is how much of a file is read to tell text from binary.
+const previewSniff = 8 << 10
+
+// TextPreviewer shows the start of a text file, highlighting code in
+// languages it knows by the file's extension.
+func TextPreviewer() Previewer {
+	return PreviewerFunc(isTextFile, previewText)
+}
+
+func isTextFile(path string) bool {
+	head, err := readHead(path, previewSniff)
+	return err == nil && looksLikeText(head)
+}
+
+// looksLikeText reports whether data is UTF-8 without NULs, allowing for a
+// character cut off at the end.
+func looksLikeText(data []byte) bool {
+	if bytes.IndexByte(data, 0) >= 0 {
+		return false
+	}
+	for len(data) > 0 && !utf8.Valid(data) {
+		r, size := utf8.DecodeLastRune(data)
+		if r != utf8.RuneError || size > 1 || len(data) < 4 {
+			return false
+		}
+		data = data[:len(data)-1]
+	}
+	return true
+}
+
+func previewText(ctx context.Context, path string, w, h int) (*Buffer, error) {
+	// enough for h lines of w, and no more
+	head, err := readHead(path, int64(h*(w+1)*4))
+	if err != nil {
+		return nil, err
+	}
+	text := string(head)
+	if lines := strings.SplitAfterN(text, "\n", h+1); len(lines) > h {
+		text = strings.Join(lines[:h], "")
+	}
+	out := NewBuffer(w, h)
+	for y, line := range HighlightCode(path, text, DefaultSyntaxStyles) {
+		if y >= h {
+			break
+		}
+		out.WriteSpans(0, y, line, w)
+	}
+	return out, ctx.Err()
+}
+
+// HexPreviewer shows a hex dump of the start of a file.
+func HexPreviewer() Previewer {
+	return PreviewerFunc(isFile, previewHex)
+}
+
+func isFile(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && fi.Mode().IsRegular()
+}
+
+func previewHex(ctx context.Context, path string, w, h int) (*Buffer, error) {
+	// 16 bytes a row takes 78 cells: offset, hex and text
+	perRow := 16
+	if w < 78 {
+		perRow = 8
+	}
+	data, err := readHead(path, int64(perRow*h))
+	if err != nil {
+		return nil, err
+	}
+	out := NewBuffer(w, h)
+	dim := Style{Attr: AttrDim}
+	for y := 0; y*perRow < len(data) && y < h; y++ {
+		row := data[y*perRow : min((y+1)*perRow, len(data))]
+		var hex, text strings.Builder
+		for i := range perRow {
+			if i == perRow/2 {
+				hex.WriteByte(' ')
+			}
+			if i >= len(row) {
+				hex.WriteString("   ")
+				continue
+			}
+			fmt.Fprintf(&hex, "%02x ", row[i])
+			if c := row[i]; c >= 0x20 && c < 0x7f {
+				text.WriteByte(c)
+			} else {
+				text.WriteByte('.')
+			}
+		}
+		out.WriteSpans(0, y, []Span{
+			{Text: fmt.Sprintf("%08x  ", y*perRow), Style: dim},
+			{Text: hex.String()},
+			{Text: " |", Style: dim},
+			{Text: text.String()},
+			{Text: "|", Style: dim},
+		}, w)
+	}
+	return out, ctx.Err()
+}
+
+func readHead(path string, n int64) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(io.LimitReader(f, n))
+}
+
+// imagePreviewer is a previewer that can hand over its image as PNG, for
+// terminals that draw images themselves.
+type imagePreviewer interface {
+	png(ctx context.Context, item string) ([]byte, error)
+}
+
+// ImagePreviewer shows PNG, JPEG and GIF images. Where the terminal can't
+// draw images, they are drawn in half-block cells, two pixels to a cell.
+func ImagePreviewer() Previewer {
+	return imageFiles{}
+}
+
+type imageFiles struct{}
+
+func (imageFiles) Handles(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png", ".jpg", ".jpeg", ".gif":
+		return isFile(path)
+	}
+	return false
+}
+
+func (imageFiles) png(ctx context.Context, path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil || strings.EqualFold(filepath.Ext(path), ".png") {
+		return data, err
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	var b bytes.Buffer
+	err = png.Encode(&b, img)
+	return b.Bytes(), err
+}
+
+func (imageFiles) Preview(ctx context.Context, path string, w, h int) (*Buffer, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, err
+	}
+	out := NewBuffer(w, h)
+	drawHalfBlocks(out, img, w, h)
+	return out, ctx.Err()
+}
+
+// drawHalfBlocks draws img scaled to fit w by h cells, each cell showing a
+// pixel above another as the colours of '▀'.
+func drawHalfBlocks(out *Buffer, img image.Image, w, h int) {
+	b := img.Bounds()
+	if b.Empty() {
+		return
+	}
+	// half-block pixels are about square, so fit 2h rows in cells of aspect 1
+	cols, rows := fitCells(b.Dx(), b.Dy(), w, h*2, 1)
+	pixel := func(x, y int) Color {
+		r, g, bl, _ := img.At(b.Min.X+x*b.Dx()/cols, b.Min.Y+y*b.Dy()/rows).RGBA()
+		return RGB(uint8(r>>8), uint8(g>>8), uint8(bl>>8))
+	}
+	for y := 0; y < rows; y += 2 {
+		for x := range cols {
+			c := Cell{Rune: '▀', Style: Style{FG: pixel(x, y)}}
+			if y+1 < rows {
+				c.Style.BG = pixel(x, y+1)
+			}
+			out.Set(x, y/2, c)
+		}
+	}
+}
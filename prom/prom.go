@@ -0,0 +1,228 @@
+// Package prom reads metrics from Prometheus, by querying a server's HTTP
+// API or scraping an exporter's /metrics page, into plain fields that glyph
+// components bind to:
+//
+//	c := prom.New("http://prometheus:9090")
+//	rps := c.Watch(`sum(rate(http_requests_total[1m]))`)
+//	go rps.Run(app.Context(), 5*time.Second, app.Post)
+//
+//	Text(&rps.Text)
+//	Sparkline(&rps.History)
+//	AutoTable(&rps.Samples).Columns("Series", "Value")
+package prom
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sample is one series' value at a point in time.
+type Sample struct {
+	Series string // name and labels, e.g. up{job="api"}
+	Value  float64
+	Labels map[string]string // including __name__ when there is one
+	Time   time.Time
+}
+
+// Series is one series' values over a range.
+type Series struct {
+	Series string
+	Labels map[string]string
+	Values []float64
+	Times  []time.Time
+}
+
+// Client queries a Prometheus server.
+type Client struct {
+	URL  string       // base URL, e.g. http://localhost:9090
+	HTTP *http.Client // http.DefaultClient if nil
+}
+
+// New creates a client for the server at baseURL.
+func New(baseURL string) *Client {
+	return &Client{URL: strings.TrimSuffix(baseURL, "/")}
+}
+
+func (c *Client) client() *http.Client {
+	if c.HTTP == nil {
+		return http.DefaultClient
+	}
+	return c.HTTP
+}
+
+// Query runs an instant query. A scalar result comes back as one sample
+// with no labels.
+func (c *Client) Query(ctx context.Context, expr string) ([]Sample, error) {
+	q := url.Values{"query": {expr}}
+	var data struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	}
+	if err := c.get(ctx, "/api/v1/query", q, &data); err != nil {
+		return nil, err
+	}
+	switch data.ResultType {
+	case "vector":
+		var result []struct {
+			Metric map[string]string `json:"metric"`
+			Value  point             `json:"value"`
+		}
+		if err := json.Unmarshal(data.Result, &result); err != nil {
+			return nil, err
+		}
+		out := make([]Sample, len(result))
+		for i, r := range result {
+			out[i] = Sample{Series: seriesName(r.Metric), Labels: r.Metric, Value: r.Value.v, Time: r.Value.t}
+		}
+		return out, nil
+	case "scalar":
+		var p point
+		if err := json.Unmarshal(data.Result, &p); err != nil {
+			return nil, err
+		}
+		return []Sample{{Value: p.v, Time: p.t}}, nil
+	}
+	return nil, fmt.Errorf("prom: unexpected %s result", data.ResultType)
+}
+
+// QueryRange runs a range query from start to end at step resolution.
+func (c *Client) QueryRange(ctx context.Context, expr string, start, end time.Time, step time.Duration) ([]Series, error) {
+	q := url.Values{
+		"query": {expr},
+		"start": {formatTime(start)},
+		"end":   {formatTime(end)},
+		"step":  {strconv.FormatFloat(step.Seconds(), 'f', -1, 64)},
+	}
+	var data struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Metric map[string]string `json:"metric"`
+			Values []point           `json:"values"`
+		} `json:"result"`
+	}
+	if err := c.get(ctx, "/api/v1/query_range", q, &data); err != nil {
+		return nil, err
+	}
+	if data.ResultType != "matrix" {
+		return nil, fmt.Errorf("prom: unexpected %s result", data.ResultType)
+	}
+	out := make([]Series, len(data.Result))
+	for i, r := range data.Result {
+		s := Series{Series: seriesName(r.Metric), Labels: r.Metric}
+		for _, p := range r.Values {
+			s.Values = append(s.Values, p.v)
+			s.Times = append(s.Times, p.t)
+		}
+		out[i] = s
+	}
+	return out, nil
+}
+
+// get calls an API endpoint and decodes the data of a success response.
+func (c *Client) get(ctx context.Context, path string, q url.Values, data any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.URL+path+"?"+q.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	var body struct {
+		Status string          `json:"status"`
+		Data   json.RawMessage `json:"data"`
+		Error  string          `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("prom: %s", resp.Status)
+		}
+		return err
+	}
+	if body.Status != "success" {
+		if body.Error == "" {
+			body.Error = resp.Status
+		}
+		return errors.New("prom: " + body.Error)
+	}
+	return json.Unmarshal(body.Data, data)
+}
+
+// point is a [timestamp, "value"] pair from the API.
+type point struct {
+	t time.Time
+	v float64
+}
+
+func (p *point) UnmarshalJSON(b []byte) error {
+	var raw [2]any
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	ts, ok := raw[0].(float64)
+	s, ok2 := raw[1].(string)
+	if !ok || !ok2 {
+		return fmt.Errorf("prom: bad sample %s", b)
+	}
+	v, err := parseValue(s)
+	if err != nil {
+		return err
+	}
+	sec, frac := math.Modf(ts)
+	p.t = time.Unix(int64(sec), int64(frac*1e9))
+	p.v = v
+	return nil
+}
+
+func formatTime(t time.Time) string {
+	return strconv.FormatFloat(float64(t.UnixNano())/1e9, 'f', 3, 64)
+}
+
+// parseValue reads a sample value, including Prometheus' spellings of
+// infinity and NaN.
+func parseValue(s string) (float64, error) {
+	switch s {
+	case "+Inf", "Inf":
+		return math.Inf(1), nil
+	case "-Inf":
+		return math.Inf(-1), nil
+	case "NaN":
+		return math.NaN(), nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+// seriesName renders labels in the usual name{k="v",...} form, keys sorted.
+func seriesName(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		if k != "__name__" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	var b strings.Builder
+	b.WriteString(labels["__name__"])
+	if len(keys) == 0 {
+		return b.String()
+	}
+	b.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(k + "=" + strconv.Quote(labels[k]))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
@@ -0,0 +1,150 @@
+package prom
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func promServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query().Get("query")
+		switch {
+		case r.URL.Path == "/api/v1/query" && q == "up":
+			w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[
+				{"metric":{"__name__":"up","job":"api","instance":"a:80"},"value":[1700000000.5,"1"]},
+				{"metric":{"__name__":"up","job":"db"},"value":[1700000000.5,"0"]}]}}`))
+		case r.URL.Path == "/api/v1/query" && q == "scalar(1)":
+			w.Write([]byte(`{"status":"success","data":{"resultType":"scalar","result":[1700000000,"+Inf"]}}`))
+		case r.URL.Path == "/api/v1/query_range":
+			if r.URL.Query().Get("step") != "15" {
+				t.Errorf("step = %q", r.URL.Query().Get("step"))
+			}
+			w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[
+				{"metric":{"job":"api"},"values":[[1700000000,"1"],[1700000015,"2.5"],[1700000030,"4"]]}]}}`))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"status":"error","errorType":"bad_data","error":"parse error"}`))
+		}
+	}))
+}
+
+func TestQuery(t *testing.T) {
+	srv := promServer(t)
+	defer srv.Close()
+	c := New(srv.URL + "/")
+	ctx := context.Background()
+
+	got, err := c.Query(ctx, "up")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Series != `up{instance="a:80",job="api"}` || got[0].Value != 1 || got[1].Value != 0 {
+		t.Errorf("samples = %+v", got)
+	}
+	if want := time.Unix(1700000000, 5e8); !got[0].Time.Equal(want) {
+		t.Errorf("time = %v, want %v", got[0].Time, want)
+	}
+
+	got, err = c.Query(ctx, "scalar(1)")
+	if err != nil || len(got) != 1 || !math.IsInf(got[0].Value, 1) {
+		t.Errorf("scalar = %+v, %v", got, err)
+	}
+
+	if _, err := c.Query(ctx, "bad{"); err == nil || !strings.Contains(err.Error(), "parse error") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestQueryRange(t *testing.T) {
+	srv := promServer(t)
+	defer srv.Close()
+	end := time.Unix(1700000030, 0)
+	got, err := New(srv.URL).QueryRange(context.Background(), "rate(x[1m])", end.Add(-30*time.Second), end, 15*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Series != `{job="api"}` || len(got[0].Values) != 3 || got[0].Values[1] != 2.5 {
+		t.Errorf("series = %+v", got)
+	}
+}
+
+func TestParseText(t *testing.T) {
+	text := `# HELP http_requests_total Requests.
+# TYPE http_requests_total counter
+http_requests_total{method="GET",path="/a \"b\""} 1027 1700000000000
+http_requests_total{method="POST"} 3
+process_open_fds 12
+go_gc_duration_seconds{quantile="0.5",} NaN
+`
+	got, err := ParseText(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 4 {
+		t.Fatalf("got %d samples: %+v", len(got), got)
+	}
+	if got[0].Labels["path"] != `/a "b"` || got[0].Value != 1027 || got[0].Time.UnixMilli() != 1700000000000 {
+		t.Errorf("first = %+v", got[0])
+	}
+	if got[2].Series != "process_open_fds" || got[2].Value != 12 {
+		t.Errorf("third = %+v", got[2])
+	}
+	if !math.IsNaN(got[3].Value) || got[3].Labels["quantile"] != "0.5" {
+		t.Errorf("fourth = %+v", got[3])
+	}
+
+	if _, err := ParseText(strings.NewReader(`bad{x="1" 2`)); err == nil {
+		t.Error("expected an error for unterminated labels")
+	}
+}
+
+func TestWatch(t *testing.T) {
+	srv := promServer(t)
+	defer srv.Close()
+	c := New(srv.URL)
+
+	w := c.Watch("up")
+	w.Keep = 2
+	for range 3 {
+		apply, err := w.fetch(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		apply(w)
+	}
+	if w.Value != 1 || w.Text != "1" || len(w.Samples) != 2 || len(w.History) != 2 {
+		t.Errorf("watch = %+v", w)
+	}
+
+	r := c.WatchRange("rate(x[1m])", 30*time.Second, 15*time.Second)
+	apply, err := r.fetch(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	apply(r)
+	if r.Value != 4 || len(r.History) != 3 || r.History[1] != 2.5 {
+		t.Errorf("range watch = %+v", r)
+	}
+}
+
+func TestWatchScrape(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("node_load1 0.42\nprocess_open_fds 12\n"))
+	}))
+	defer srv.Close()
+
+	w := WatchScrape(srv.URL, "node_")
+	ctx, cancel := context.WithCancel(context.Background())
+	w.Run(ctx, time.Hour, func(fn func()) {
+		fn()
+		cancel()
+	})
+	if len(w.Samples) != 1 || w.Text != "0.42" || w.Err != nil {
+		t.Errorf("watch = %+v", w)
+	}
+}
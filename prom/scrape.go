@@ -0,0 +1,129 @@
+package prom
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Scrape fetches an exporter's metrics page, such as
+// http://localhost:9100/metrics, and parses it.
+func Scrape(ctx context.Context, url string) ([]Sample, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/plain")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("prom: scrape %s: %s", url, resp.Status)
+	}
+	return ParseText(resp.Body)
+}
+
+// ParseText parses the Prometheus text exposition format. Comments and
+// type hints are skipped; histogram and summary parts come through as the
+// plain series they're written as (_bucket, _sum, _count).
+func ParseText(r io.Reader) ([]Sample, error) {
+	var out []Sample
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+	for n := 1; sc.Scan(); n++ {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		s, err := parseLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("prom: line %d: %w", n, err)
+		}
+		out = append(out, s)
+	}
+	return out, sc.Err()
+}
+
+// parseLine reads `name{label="value",...} value [timestamp]`.
+func parseLine(line string) (Sample, error) {
+	labels := map[string]string{}
+	end := strings.IndexAny(line, "{ \t")
+	if end < 0 {
+		return Sample{}, fmt.Errorf("no value in %q", line)
+	}
+	labels["__name__"] = line[:end]
+	rest := line[end:]
+	if rest[0] == '{' {
+		var err error
+		if rest, err = parseLabels(rest[1:], labels); err != nil {
+			return Sample{}, err
+		}
+	}
+	f := strings.Fields(rest)
+	if len(f) == 0 {
+		return Sample{}, fmt.Errorf("no value in %q", line)
+	}
+	v, err := parseValue(f[0])
+	if err != nil {
+		return Sample{}, err
+	}
+	s := Sample{Series: seriesName(labels), Labels: labels, Value: v}
+	if len(f) > 1 {
+		var ms int64
+		if _, err := fmt.Sscan(f[1], &ms); err == nil {
+			s.Time = time.UnixMilli(ms)
+		}
+	}
+	return s, nil
+}
+
+// parseLabels reads label pairs up to the closing brace into labels and
+// returns what follows it.
+func parseLabels(s string, labels map[string]string) (string, error) {
+	for {
+		s = strings.TrimLeft(s, " ,")
+		if s == "" {
+			return "", fmt.Errorf("unterminated labels")
+		}
+		if s[0] == '}' {
+			return s[1:], nil
+		}
+		eq := strings.IndexByte(s, '=')
+		if eq < 0 || eq+1 >= len(s) || s[eq+1] != '"' {
+			return "", fmt.Errorf("bad label in %q", s)
+		}
+		name := strings.TrimSpace(s[:eq])
+		s = s[eq+2:]
+		var val strings.Builder
+		closed := false
+		for i := 0; i < len(s); i++ {
+			c := s[i]
+			if c == '\\' && i+1 < len(s) {
+				i++
+				switch s[i] {
+				case 'n':
+					val.WriteByte('\n')
+				default:
+					val.WriteByte(s[i])
+				}
+				continue
+			}
+			if c == '"' {
+				s = s[i+1:]
+				closed = true
+				break
+			}
+			val.WriteByte(c)
+		}
+		if !closed {
+			return "", fmt.Errorf("unterminated label value")
+		}
+		labels[name] = val.String()
+	}
+}
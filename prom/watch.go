@@ -0,0 +1,150 @@
+package prom
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kungfusheep/glyph/format"
+)
+
+// Watch keeps the result of a query or scrape current, in fields to bind
+// to. Value and History follow the first sample, which suits single-series
+// queries like sum(...).
+type Watch struct {
+	Samples []Sample  // latest result, for AutoTable
+	Value   float64   // first sample's value
+	Text    string    // Value formatted, e.g. "1.2k"
+	History []float64 // Value over time, or the range, for Sparkline
+	Err     error
+	ErrText string // Err as text, "" when there is none
+
+	Keep   int                    // History length for instant queries; 60 by default
+	Format func(v float64) string // how Text is written; SI at one decimal by default
+	fetch  func(context.Context) (apply func(*Watch), err error)
+}
+
+// Watch follows an instant query, adding each result's first value to
+// History.
+func (c *Client) Watch(expr string) *Watch {
+	return newWatch(func(ctx context.Context) (func(*Watch), error) {
+		samples, err := c.Query(ctx, expr)
+		if err != nil {
+			return nil, err
+		}
+		return func(w *Watch) { w.setSamples(samples, true) }, nil
+	})
+}
+
+// WatchRange follows a range query over the last window at step
+// resolution. History is the first series' values across the window.
+func (c *Client) WatchRange(expr string, window, step time.Duration) *Watch {
+	return newWatch(func(ctx context.Context) (func(*Watch), error) {
+		end := time.Now()
+		series, err := c.QueryRange(ctx, expr, end.Add(-window), end, step)
+		if err != nil {
+			return nil, err
+		}
+		return func(w *Watch) {
+			samples := make([]Sample, len(series))
+			for i, s := range series {
+				samples[i] = Sample{Series: s.Series, Labels: s.Labels}
+				if n := len(s.Values); n > 0 {
+					samples[i].Value, samples[i].Time = s.Values[n-1], s.Times[n-1]
+				}
+			}
+			w.setSamples(samples, false)
+			w.History = w.History[:0]
+			if len(series) > 0 {
+				w.History = append(w.History, series[0].Values...)
+			}
+		}, nil
+	})
+}
+
+// WatchScrape follows an exporter's metrics page. Only series whose name
+// starts with prefix are kept; pass "" for all of them.
+func WatchScrape(url, prefix string) *Watch {
+	return newWatch(func(ctx context.Context) (func(*Watch), error) {
+		samples, err := Scrape(ctx, url)
+		if err != nil {
+			return nil, err
+		}
+		kept := samples[:0]
+		for _, s := range samples {
+			if strings.HasPrefix(s.Labels["__name__"], prefix) {
+				kept = append(kept, s)
+			}
+		}
+		return func(w *Watch) { w.setSamples(kept, true) }, nil
+	})
+}
+
+func newWatch(fetch func(context.Context) (func(*Watch), error)) *Watch {
+	return &Watch{Keep: 60, fetch: fetch}
+}
+
+// Run fetches immediately and then every interval until ctx is done,
+// handing each result to post so it lands on the UI goroutine; pass
+// app.Post, or nil to update the fields directly.
+func (w *Watch) Run(ctx context.Context, interval time.Duration, post func(func())) error {
+	if post == nil {
+		post = func(fn func()) { fn() }
+	}
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		fctx, cancel := context.WithTimeout(ctx, interval)
+		apply, err := w.fetch(fctx)
+		cancel()
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			post(func() { w.Err, w.ErrText = err, err.Error() })
+		} else {
+			post(func() {
+				w.Err, w.ErrText = nil, ""
+				apply(w)
+			})
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+func (w *Watch) setSamples(samples []Sample, record bool) {
+	w.Samples = samples
+	if len(samples) == 0 {
+		w.Value, w.Text = 0, "-"
+		return
+	}
+	w.Value = samples[0].Value
+	if w.Format != nil {
+		w.Text = w.Format(w.Value)
+	} else {
+		w.Text = formatValue(w.Value)
+	}
+	if record {
+		keep := w.Keep
+		if keep <= 0 {
+			keep = 60
+		}
+		w.History = append(w.History, w.Value)
+		if len(w.History) > keep {
+			w.History = append(w.History[:0], w.History[len(w.History)-keep:]...)
+		}
+	}
+}
+
+// formatValue is SI at one decimal, keeping small fractions readable.
+func formatValue(v float64) string {
+	if v != 0 && v > -1 && v < 1 {
+		return strconv.FormatFloat(v, 'g', 3, 64)
+	}
+	return format.SI(v, 1)
+}
@@ -0,0 +1,215 @@
+package glyph
+
+import (
+	"github.com/kungfusheep/riffkey"
+	"github.com/mattn/go-runewidth"
+)
+
+// cmdPrompt is the line a command is typed into, shared by CommandPalette
+// and CommandLine: editing, argument completion and Ctrl-R history recall.
+type cmdPrompt struct {
+	cmds    *Commands
+	history *History
+
+	line   string
+	cursor int
+
+	// cmd is the command the line names once it goes on to arguments;
+	// comps complete the word being typed, from compStart
+	cmd       *Command
+	comps     []string
+	compStart int
+	compSel   int
+
+	recalling bool
+	recall    historyRecall
+
+	changed func() // called when the line has been read again
+}
+
+// historyRecall is a Ctrl-R search through the history.
+type historyRecall struct {
+	query   string
+	cursor  int
+	matches []string
+	sel     int
+	saved   string // the line before the search, put back on Esc
+}
+
+func (pr *cmdPrompt) set(line string) {
+	pr.line = line
+	pr.cursor = len([]rune(line))
+	pr.update()
+}
+
+// update reads the line again after it's changed.
+func (pr *cmdPrompt) update() {
+	pr.cmd, pr.compSel = nil, -1
+	pr.compStart, pr.comps = pr.cmds.Complete(pr.line)
+	words, err := splitCommandLine(pr.line)
+	if err == nil && len(words) > 0 && (len(words) > 1 || lastWordStart(pr.line) == len(pr.line)) {
+		pr.cmd, _ = pr.cmds.Get(words[0])
+	}
+	if pr.changed != nil {
+		pr.changed()
+	}
+}
+
+// nameQuery is the part of the line to match command names against.
+func (pr *cmdPrompt) nameQuery() string {
+	if words, err := splitCommandLine(pr.line); err == nil && len(words) > 1 {
+		return words[0]
+	}
+	return pr.line
+}
+
+// complete steps through the completions for the word being typed.
+func (pr *cmdPrompt) complete(dir int) {
+	n := len(pr.comps)
+	if n == 0 {
+		return
+	}
+	if pr.compSel < 0 && dir < 0 {
+		pr.compSel = n - 1
+	} else {
+		pr.compSel = (pr.compSel + dir + n) % n
+	}
+	pr.line = pr.line[:pr.compStart] + QuoteWord(pr.comps[pr.compSel])
+	pr.cursor = len([]rune(pr.line))
+	if n == 1 {
+		pr.update() // a lone directory completes into it next
+	}
+}
+
+// run records the line in the history and runs it.
+func (pr *cmdPrompt) run(line string) error {
+	if pr.history != nil {
+		pr.history.Add(line)
+	}
+	return pr.cmds.Run(line)
+}
+
+// startRecall begins a Ctrl-R search, or steps to the next older match if
+// one is under way.
+func (pr *cmdPrompt) startRecall() {
+	if pr.history == nil {
+		return
+	}
+	if pr.recalling {
+		pr.moveRecall(1)
+		return
+	}
+	pr.recalling = true
+	pr.recall = historyRecall{saved: pr.line}
+	pr.searchRecall(pr.recall.query)
+}
+
+func (pr *cmdPrompt) searchRecall(q string) {
+	pr.recall.matches = pr.history.Search(q)
+	pr.recall.sel = 0
+}
+
+func (pr *cmdPrompt) moveRecall(dir int) {
+	if n := len(pr.recall.matches); n > 0 {
+		pr.recall.sel = min(max(pr.recall.sel+dir, 0), n-1)
+	}
+}
+
+// endRecall finishes a search, taking the selected line if accept is set
+// and putting the line back as it was otherwise.
+func (pr *cmdPrompt) endRecall(accept bool) {
+	pr.recalling = false
+	if accept && pr.recall.sel < len(pr.recall.matches) {
+		pr.set(pr.recall.matches[pr.recall.sel])
+		return
+	}
+	pr.set(pr.recall.saved)
+}
+
+// promptKeys are what a prompt's host does with the keys they share. tab
+// defaults to stepping through the completions.
+type promptKeys struct {
+	enter, escape, up, down func()
+	tab                     func(dir int)
+}
+
+// bind registers the prompt's keys on r. While recalling, Enter takes the
+// match, Esc or Ctrl-G cancels and up/down move through the matches;
+// otherwise they go to the host. Keys r doesn't bind edit the line, or the
+// search.
+func (pr *cmdPrompt) bind(r *riffkey.Router, k promptKeys) {
+	either := func(recalling, otherwise func()) func(riffkey.Match) {
+		return func(riffkey.Match) {
+			if pr.recalling {
+				recalling()
+			} else if otherwise != nil {
+				otherwise()
+			}
+		}
+	}
+	older := func() { pr.moveRecall(1) }
+	newer := func() { pr.moveRecall(-1) }
+	r.Handle("<Up>", either(older, k.up))
+	r.Handle("<C-p>", either(older, k.up))
+	r.Handle("<Down>", either(newer, k.down))
+	r.Handle("<C-n>", either(newer, k.down))
+	r.Handle("<Enter>", either(func() { pr.endRecall(true) }, k.enter))
+	r.Handle("<Esc>", either(func() { pr.endRecall(false) }, k.escape))
+	r.Handle("<C-g>", either(func() { pr.endRecall(false) }, nil))
+	r.Handle("<C-r>", func(riffkey.Match) { pr.startRecall() })
+	tab := k.tab
+	if tab == nil {
+		tab = pr.complete
+	}
+	r.Handle("<Tab>", either(func() { pr.endRecall(true) }, func() { tab(1) }))
+	r.Handle("<S-Tab>", either(func() {}, func() { tab(-1) }))
+
+	lineText := riffkey.NewTextHandler(&pr.line, &pr.cursor)
+	lineText.OnChange = func(string) { pr.update() }
+	searchText := riffkey.NewTextHandler(&pr.recall.query, &pr.recall.cursor)
+	searchText.OnChange = pr.searchRecall
+	r.HandleUnmatched(func(key riffkey.Key) bool {
+		if pr.recalling {
+			searchText.HandleKey(key)
+		} else {
+			lineText.HandleKey(key)
+		}
+		return true
+	})
+}
+
+// drawLine draws text with a cursor at rune cur, scrolled so the cursor
+// stays within w columns.
+func drawLine(buf *Buffer, x, y, w int, text string, cur int, style Style) {
+	runes := []rune(text)
+	cur = min(max(cur, 0), len(runes))
+	start := 0
+	for runewidth.StringWidth(string(runes[start:cur])) >= w && start < cur {
+		start++
+	}
+	buf.WriteStringFast(x, y, string(runes[start:]), style, w)
+	cx := x + runewidth.StringWidth(string(runes[start:cur]))
+	if cx < x+w {
+		c := buf.Get(cx, y)
+		if c.Rune == 0 {
+			c.Rune = ' '
+		}
+		c.Style = style.Inverse()
+		buf.SetFast(cx, y, c)
+	}
+}
+
+// drawRecall draws the history matches in rows rows from y, the selected
+// one in sel.
+func (pr *cmdPrompt) drawRecall(buf *Buffer, x, y, w, rows int, style, sel Style) {
+	r := &pr.recall
+	top := max(r.sel-rows+1, 0)
+	for i := 0; i < rows && top+i < len(r.matches); i++ {
+		s := style
+		if top+i == r.sel {
+			s = sel
+			buf.FillRect(x, y+i, w, 1, Cell{Rune: ' ', Style: s})
+		}
+		buf.WriteStringFast(x+1, y+i, r.matches[top+i], s, w-2)
+	}
+}
@@ -0,0 +1,489 @@
+package glyph
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/kungfusheep/riffkey"
+	"golang.org/x/sys/unix"
+)
+
+// ErrCancelled is returned by a Prompter when the question is dismissed
+// with Esc or Ctrl-C, or input ends before it's answered.
+var ErrCancelled = errors.New("prompt cancelled")
+
+// Prompter asks one-off questions from an ordinary command-line program:
+//
+//	p := glyph.Prompt()
+//	env, err := p.Select("Deploy to", "production", "staging", "local")
+//	if err != nil {
+//	    return err // ErrCancelled if the user backed out
+//	}
+//	ok, err := p.Confirm("Deploy to "+env+"?", false)
+//
+// On a terminal each question is drawn inline, below the cursor, and
+// answered with the keyboard; once answered it's replaced by a one-line
+// summary. When stdin or stdout isn't a terminal, as in pipes and CI, the
+// same questions are asked as plain lines of text and answered by typing
+// a line, so the program works either way.
+type Prompter struct {
+	in          io.Reader
+	out         io.Writer
+	lines       *bufio.Reader
+	interactive bool
+	backend     Backend
+
+	questionStyle Style
+	selectedStyle Style
+	dimStyle      Style
+	maxRows       int
+}
+
+// Prompt returns a Prompter on stdin and stdout, drawing its questions if
+// both are terminals.
+func Prompt() *Prompter {
+	return &Prompter{
+		in:            os.Stdin,
+		out:           os.Stdout,
+		interactive:   isTerminal(os.Stdin.Fd()) && isTerminal(os.Stdout.Fd()),
+		questionStyle: Style{Attr: AttrBold},
+		selectedStyle: Style{FG: Cyan},
+		dimStyle:      Style{Attr: AttrDim},
+		maxRows:       10,
+	}
+}
+
+func isTerminal(fd uintptr) bool {
+	_, err := unix.IoctlGetTermios(int(fd), ioctlGetTermios)
+	return err == nil
+}
+
+// Plain makes the Prompter read answers as lines from in and write
+// questions to out, as it does when there's no terminal.
+func (p *Prompter) Plain(in io.Reader, out io.Writer) *Prompter {
+	p.in, p.out, p.lines = in, out, nil
+	p.interactive = false
+	return p
+}
+
+// Backend makes the Prompter draw its questions on b and read their keys
+// from it, writing answered questions to out; for testing prompts with a
+// BufferBackend. A BufferBackend's input ends with the app that uses it,
+// so give each question a new one.
+func (p *Prompter) Backend(b Backend, out io.Writer) *Prompter {
+	p.backend, p.out = b, out
+	p.interactive = true
+	return p
+}
+
+// Styles sets the style of the question and of the option under the
+// cursor. Bold and cyan by default.
+func (p *Prompter) Styles(question, selected Style) *Prompter {
+	p.questionStyle, p.selectedStyle = question, selected
+	return p
+}
+
+// MaxRows sets how many options are listed at once. 10 by default.
+func (p *Prompter) MaxRows(n int) *Prompter {
+	p.maxRows = max(n, 1)
+	return p
+}
+
+// Select asks for one of options and returns it.
+func (p *Prompter) Select(question string, options ...string) (string, error) {
+	if len(options) == 0 {
+		return "", errors.New("Select: no options")
+	}
+	if !p.interactive {
+		return p.plainSelect(question, options)
+	}
+	sel := 0
+	err := p.run(question, func(lines *[]promptLine) {
+		p.listLines(lines, options, sel, func(i int) string { return "" })
+	}, func(r *riffkey.Router, done func(error)) {
+		p.bindList(r, len(options), &sel)
+		r.Handle("<Enter>", func(riffkey.Match) { done(nil) })
+	})
+	if err != nil {
+		return "", err
+	}
+	p.summary(question, options[sel])
+	return options[sel], nil
+}
+
+// MultiSelect asks for any number of options, toggled with Space, and
+// returns those chosen in the order given. The options in chosen start
+// out ticked.
+func (p *Prompter) MultiSelect(question string, options []string, chosen ...string) ([]string, error) {
+	picked := make([]bool, len(options))
+	for i, o := range options {
+		for _, c := range chosen {
+			picked[i] = picked[i] || o == c
+		}
+	}
+	if !p.interactive {
+		return p.plainMultiSelect(question, options, picked)
+	}
+	sel := 0
+	err := p.run(question, func(lines *[]promptLine) {
+		p.listLines(lines, options, sel, func(i int) string {
+			if picked[i] {
+				return "[x] "
+			}
+			return "[ ] "
+		})
+		*lines = append(*lines, promptLine{text: "space to tick, a for all, enter to finish", style: p.dimStyle})
+	}, func(r *riffkey.Router, done func(error)) {
+		p.bindList(r, len(options), &sel)
+		r.Handle("<Space>", func(riffkey.Match) { picked[sel] = !picked[sel] })
+		r.Handle("a", func(riffkey.Match) {
+			all := true
+			for _, ok := range picked {
+				all = all && ok
+			}
+			for i := range picked {
+				picked[i] = !all
+			}
+		})
+		r.Handle("<Enter>", func(riffkey.Match) { done(nil) })
+	})
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	for i, ok := range picked {
+		if ok {
+			out = append(out, options[i])
+		}
+	}
+	p.summary(question, strings.Join(out, ", "))
+	return out, nil
+}
+
+// Confirm asks a yes or no question. Enter alone answers def.
+func (p *Prompter) Confirm(question string, def bool) (bool, error) {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	if !p.interactive {
+		for {
+			line, err := p.ask(fmt.Sprintf("%s [%s] ", question, hint))
+			if err != nil {
+				return false, err
+			}
+			if v, ok := parseYesNo(line, def); ok {
+				return v, nil
+			}
+			fmt.Fprintln(p.out, "Please answer y or n.")
+		}
+	}
+	answer := def
+	err := p.run(question, func(lines *[]promptLine) {
+		(*lines)[0].after = " (" + hint + ")"
+	}, func(r *riffkey.Router, done func(error)) {
+		for _, k := range []string{"y", "Y"} {
+			r.Handle(k, func(riffkey.Match) { answer = true; done(nil) })
+		}
+		for _, k := range []string{"n", "N"} {
+			r.Handle(k, func(riffkey.Match) { answer = false; done(nil) })
+		}
+		r.Handle("<Enter>", func(riffkey.Match) { done(nil) })
+	})
+	if err != nil {
+		return false, err
+	}
+	p.summary(question, map[bool]string{true: "yes", false: "no"}[answer])
+	return answer, nil
+}
+
+func parseYesNo(s string, def bool) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "":
+		return def, true
+	case "y", "yes":
+		return true, true
+	case "n", "no":
+		return false, true
+	}
+	return false, false
+}
+
+// Text asks for a line of text. An empty answer is def.
+func (p *Prompter) Text(question, def string) (string, error) {
+	return p.text(question, def, false, nil)
+}
+
+// Password asks for a line of text without showing it. Without a terminal
+// it's read as a plain line, and shown if input is being typed.
+func (p *Prompter) Password(question string) (string, error) {
+	return p.text(question, "", true, nil)
+}
+
+// Autocomplete asks for a line of text, listing complete's suggestions for
+// what's typed under it. Up and down choose one and Tab takes it.
+func (p *Prompter) Autocomplete(question string, complete Completer) (string, error) {
+	return p.text(question, "", false, complete)
+}
+
+func (p *Prompter) text(question, def string, secret bool, complete Completer) (string, error) {
+	if !p.interactive {
+		prompt := question + ": "
+		if def != "" {
+			prompt = fmt.Sprintf("%s [%s]: ", question, def)
+		}
+		line, err := p.ask(prompt)
+		if err != nil {
+			return "", err
+		}
+		if line == "" {
+			line = def
+		}
+		return line, nil
+	}
+
+	var value string
+	var cursor int
+	var suggestions []string
+	sel := -1
+	suggest := func(v string) {
+		sel = -1
+		if complete != nil {
+			suggestions = complete(v)
+		}
+	}
+	suggest("")
+	err := p.run(question, func(lines *[]promptLine) {
+		first := &(*lines)[0]
+		switch {
+		case secret:
+			first.input, first.cursor = strings.Repeat("*", len([]rune(value))), cursor
+		case value == "" && def != "":
+			first.input, first.cursor, first.after = "", 0, def
+		default:
+			first.input, first.cursor = value, cursor
+		}
+		first.editing = true
+		for i, s := range suggestions[:min(len(suggestions), p.maxRows)] {
+			line := promptLine{text: "  " + s, style: p.dimStyle}
+			if i == sel {
+				line = promptLine{text: "> " + s, style: p.selectedStyle}
+			}
+			*lines = append(*lines, line)
+		}
+	}, func(r *riffkey.Router, done func(error)) {
+		r.Handle("<Enter>", func(riffkey.Match) { done(nil) })
+		if complete != nil {
+			r.Handle("<Down>", func(riffkey.Match) { sel = min(sel+1, min(len(suggestions), p.maxRows)-1) })
+			r.Handle("<Up>", func(riffkey.Match) { sel = max(sel-1, -1) })
+			r.Handle("<Tab>", func(riffkey.Match) {
+				if len(suggestions) > 0 {
+					value = suggestions[max(sel, 0)]
+					cursor = len([]rune(value))
+					suggest(value)
+				}
+			})
+		}
+		th := riffkey.NewTextHandler(&value, &cursor)
+		th.OnChange = suggest
+		r.HandleUnmatched(func(k riffkey.Key) bool {
+			th.HandleKey(k)
+			return true
+		})
+	})
+	if err != nil {
+		return "", err
+	}
+	if value == "" {
+		value = def
+	}
+	shown := value
+	if secret {
+		shown = strings.Repeat("*", len([]rune(value)))
+	}
+	p.summary(question, shown)
+	return value, nil
+}
+
+// promptLine is a row of an interactive question.
+type promptLine struct {
+	text    string
+	style   Style
+	input   string // typed text, drawn with a cursor when editing
+	cursor  int
+	editing bool
+	after   string // dim text after the input, such as a hint or default
+}
+
+// run draws a question inline until done is called, rebuilding its lines
+// with draw after each key.
+func (p *Prompter) run(question string, draw func(*[]promptLine), bind func(*riffkey.Router, func(error))) error {
+	app, err := NewInlineApp()
+	if err != nil {
+		return err
+	}
+	if p.backend != nil {
+		app.SetBackend(p.backend)
+	}
+	app.ClearOnExit(true)
+
+	var result error
+	done := func(err error) {
+		result = err
+		app.Stop()
+	}
+	var lines []promptLine
+	app.OnBeforeRender(func() {
+		lines = append(lines[:0], promptLine{text: "? " + question, style: p.questionStyle})
+		draw(&lines)
+	})
+	bind(app.Router(), done)
+	app.Handle("<Esc>", func() { done(ErrCancelled) })
+	app.Handle("<C-c>", func() { done(ErrCancelled) })
+
+	app.SetView(Widget(
+		func(availW int16) (w, h int16) { return availW, int16(len(lines)) },
+		func(buf *Buffer, x, y, w, h int16) {
+			for i, l := range lines[:min(len(lines), int(h))] {
+				row, col, right := int(y)+i, int(x), int(x)+int(w)
+				buf.WriteStringFast(col, row, l.text, l.style, right-col)
+				col += StringWidth(l.text)
+				if l.editing {
+					col++
+					drawLine(buf, col, row, right-col, l.input, l.cursor, Style{})
+					col += StringWidth(l.input) + 1
+				}
+				if l.after != "" && col < right {
+					buf.WriteStringFast(col, row, l.after, p.dimStyle, right-col)
+				}
+			}
+		},
+	))
+	if err := app.Run(); err != nil {
+		return err
+	}
+	return result
+}
+
+// listLines adds a scrolled window of options, marking the selected one.
+func (p *Prompter) listLines(lines *[]promptLine, options []string, sel int, mark func(int) string) {
+	rows := min(len(options), p.maxRows)
+	top := min(max(sel-rows+1, 0), len(options)-rows)
+	for i := top; i < top+rows; i++ {
+		line := promptLine{text: "  " + mark(i) + options[i]}
+		if i == sel {
+			line = promptLine{text: "> " + mark(i) + options[i], style: p.selectedStyle}
+		}
+		*lines = append(*lines, line)
+	}
+}
+
+// bindList binds moving through n options.
+func (p *Prompter) bindList(r *riffkey.Router, n int, sel *int) {
+	down := func(riffkey.Match) { *sel = min(*sel+1, n-1) }
+	up := func(riffkey.Match) { *sel = max(*sel-1, 0) }
+	for _, k := range []string{"<Down>", "j", "<C-n>", "<Tab>"} {
+		r.Handle(k, down)
+	}
+	for _, k := range []string{"<Up>", "k", "<C-p>", "<S-Tab>"} {
+		r.Handle(k, up)
+	}
+	r.Handle("g", func(riffkey.Match) { *sel = 0 })
+	r.Handle("G", func(riffkey.Match) { *sel = n - 1 })
+}
+
+// summary prints the answered question in place of the prompt.
+func (p *Prompter) summary(question, answer string) {
+	fmt.Fprintf(p.out, "%s %s\n", question, answer)
+}
+
+// ask writes prompt and reads a line of input without its newline.
+func (p *Prompter) ask(prompt string) (string, error) {
+	if p.lines == nil {
+		p.lines = bufio.NewReader(p.in)
+	}
+	fmt.Fprint(p.out, prompt)
+	line, err := p.lines.ReadString('\n')
+	if err != nil && (line == "" || err != io.EOF) {
+		fmt.Fprintln(p.out)
+		return "", ErrCancelled
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (p *Prompter) plainSelect(question string, options []string) (string, error) {
+	fmt.Fprintln(p.out, question)
+	for i, o := range options {
+		fmt.Fprintf(p.out, "  %d) %s\n", i+1, o)
+	}
+	for {
+		line, err := p.ask(fmt.Sprintf("Choose 1-%d: ", len(options)))
+		if err != nil {
+			return "", err
+		}
+		if i, ok := pickOption(line, options); ok {
+			return options[i], nil
+		}
+		fmt.Fprintf(p.out, "%q is not one of the choices.\n", line)
+	}
+}
+
+func (p *Prompter) plainMultiSelect(question string, options []string, picked []bool) ([]string, error) {
+	fmt.Fprintln(p.out, question)
+	var def []string
+	for i, o := range options {
+		fmt.Fprintf(p.out, "  %d) %s\n", i+1, o)
+		if picked[i] {
+			def = append(def, strconv.Itoa(i+1))
+		}
+	}
+	prompt := "Choose any, separated by spaces or commas: "
+	if len(def) > 0 {
+		prompt = fmt.Sprintf("Choose any, separated by spaces or commas [%s]: ", strings.Join(def, " "))
+	}
+next:
+	for {
+		line, err := p.ask(prompt)
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(line) == "" {
+			line = strings.Join(def, " ")
+		}
+		chosen := make([]bool, len(options))
+		for _, word := range strings.FieldsFunc(line, func(r rune) bool { return r == ',' || r == ' ' }) {
+			i, ok := pickOption(word, options)
+			if !ok {
+				fmt.Fprintf(p.out, "%q is not one of the choices.\n", word)
+				continue next
+			}
+			chosen[i] = true
+		}
+		var out []string
+		for i, ok := range chosen {
+			if ok {
+				out = append(out, options[i])
+			}
+		}
+		return out, nil
+	}
+}
+
+// pickOption reads an answer as an option's number or its text.
+func pickOption(s string, options []string) (int, bool) {
+	s = strings.TrimSpace(s)
+	if n, err := strconv.Atoi(s); err == nil && n >= 1 && n <= len(options) {
+		return n - 1, true
+	}
+	for i, o := range options {
+		if strings.EqualFold(o, s) {
+			return i, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,142 @@
+package glyph
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPromptPlain(t *testing.T) {
+	ask := func(in string) (*Prompter, *bytes.Buffer) {
+		var out bytes.Buffer
+		return Prompt().Plain(strings.NewReader(in), &out), &out
+	}
+
+	p, out := ask("4\nstaging\n")
+	got, err := p.Select("Deploy to", "production", "staging")
+	if err != nil || got != "staging" {
+		t.Fatalf("Select = %q, %v", got, err)
+	}
+	if !strings.Contains(out.String(), "  2) staging\n") || !strings.Contains(out.String(), `"4" is not one of the choices`) {
+		t.Errorf("Select wrote %q", out.String())
+	}
+
+	p, _ = ask("1, 3\n")
+	many, err := p.MultiSelect("Regions", []string{"eu", "us", "ap"})
+	if err != nil || !reflect.DeepEqual(many, []string{"eu", "ap"}) {
+		t.Errorf("MultiSelect = %q, %v", many, err)
+	}
+	p, _ = ask("\n")
+	many, _ = p.MultiSelect("Regions", []string{"eu", "us", "ap"}, "us")
+	if !reflect.DeepEqual(many, []string{"us"}) {
+		t.Errorf("MultiSelect default = %q", many)
+	}
+
+	p, _ = ask("maybe\n\nn\n")
+	if ok, _ := p.Confirm("Sure?", true); !ok {
+		t.Error("Confirm: empty answer should take the default")
+	}
+	if ok, _ := p.Confirm("Sure?", true); ok {
+		t.Error("Confirm: n answered yes")
+	}
+
+	p, out = ask("\nhunter2")
+	if name, _ := p.Text("Name", "anon"); name != "anon" {
+		t.Errorf("Text default = %q", name)
+	}
+	if pw, err := p.Password("Password"); pw != "hunter2" || err != nil {
+		t.Errorf("Password without a newline = %q, %v", pw, err)
+	}
+	if !strings.HasPrefix(out.String(), "Name [anon]: ") {
+		t.Errorf("Text wrote %q", out.String())
+	}
+
+	p, _ = ask("")
+	if _, err := p.Text("Name", ""); !errors.Is(err, ErrCancelled) {
+		t.Errorf("Text at EOF = %v, want ErrCancelled", err)
+	}
+}
+
+// answer runs ask against a BufferBackend, typing keys once the question
+// shows want.
+func answer[T any](t *testing.T, want, keys string, ask func(*Prompter) (T, error)) (T, error, string) {
+	t.Helper()
+	be := NewBufferBackend(40, 10)
+	var out bytes.Buffer
+	p := Prompt().Backend(be, &out)
+	type result struct {
+		v   T
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		v, err := ask(p)
+		done <- result{v, err}
+	}()
+	if !be.WaitFor(func(b *Buffer) bool { return strings.Contains(b.String(), want) }, time.Second) {
+		t.Fatalf("never showed %q:\n%s", want, be.Frame().String())
+	}
+	be.Type(keys)
+	select {
+	case r := <-done:
+		return r.v, r.err, out.String()
+	case <-time.After(time.Second):
+		t.Fatalf("still asking after %q:\n%s", keys, be.Frame().String())
+	}
+	panic("unreachable")
+}
+
+func TestPromptInteractive(t *testing.T) {
+	got, err, out := answer(t, "> production", "jj\r", func(p *Prompter) (string, error) {
+		return p.Select("Deploy to", "production", "staging", "local")
+	})
+	if got != "local" || err != nil || out != "Deploy to local\n" {
+		t.Errorf("Select = %q, %v, wrote %q", got, err, out)
+	}
+
+	many, _, _ := answer(t, "[x] us", " j j \r", func(p *Prompter) ([]string, error) {
+		return p.MultiSelect("Regions", []string{"eu", "us", "ap"}, "us")
+	})
+	if !reflect.DeepEqual(many, []string{"eu", "ap"}) {
+		t.Errorf("MultiSelect = %q", many)
+	}
+
+	ok, _, _ := answer(t, "(y/N)", "y", func(p *Prompter) (bool, error) {
+		return p.Confirm("Sure?", false)
+	})
+	if !ok {
+		t.Error("Confirm: y answered no")
+	}
+
+	name, _, _ := answer(t, "? Name", "bob\x7fx\r", func(p *Prompter) (string, error) {
+		return p.Text("Name", "")
+	})
+	if name != "box" {
+		t.Errorf("Text = %q", name)
+	}
+
+	pw, _, out := answer(t, "? Password", "abc\r", func(p *Prompter) (string, error) {
+		return p.Password("Password")
+	})
+	if pw != "abc" || out != "Password ***\n" {
+		t.Errorf("Password = %q, wrote %q", pw, out)
+	}
+
+	words := []string{"checkout", "cherry-pick", "commit"}
+	cmd, _, _ := answer(t, "? git", "comm\t\r", func(p *Prompter) (string, error) {
+		return p.Autocomplete("git", CompleteWords(words...))
+	})
+	if cmd != "commit" {
+		t.Errorf("Autocomplete = %q", cmd)
+	}
+
+	_, err, _ = answer(t, "? Name", "\x03", func(p *Prompter) (string, error) {
+		return p.Text("Name", "")
+	})
+	if !errors.Is(err, ErrCancelled) {
+		t.Errorf("Ctrl-C = %v, want ErrCancelled", err)
+	}
+}
@@ -0,0 +1,96 @@
+package glyph
+
+import "unsafe"
+
+// ProtectC keeps overlays off its child: modals, menus and other floating
+// components drawn over the frame leave its cells as the child drew them.
+// Use it for what must stay visible whatever is open, such as a recording
+// indicator:
+//
+//	HBox(
+//	    Text(&title).Grow(1),
+//	    Protect(Text("● REC").FG(Red)),
+//	)
+//
+// A modal's backdrop doesn't dim it either.
+type ProtectC struct {
+	child any
+}
+
+// Protect wraps child so overlays don't draw over it.
+func Protect(child any) ProtectC {
+	return ProtectC{child: child}
+}
+
+func (t *Template) compileProtectC(v ProtectC, parent int16, depth int, elemBase unsafe.Pointer, elemSize uintptr) int16 {
+	idx := t.compile(v.child, parent, depth, elemBase, elemSize)
+	if idx >= 0 && int(idx) < len(t.ops) {
+		t.ops[idx].protect = true
+	}
+	return idx
+}
+
+// Protect keeps a region of the buffer as it is now until Unprotect: what's
+// drawn over it meanwhile is put back when protection ends. Templates
+// protect the regions of Protect components between drawing the frame and
+// drawing its overlays.
+func (b *Buffer) Protect(x, y, width, height int) {
+	if x < 0 {
+		width += x
+		x = 0
+	}
+	if y < 0 {
+		height += y
+		y = 0
+	}
+	width = min(width, b.width-x)
+	height = min(height, b.height-y)
+	if width <= 0 || height <= 0 {
+		return
+	}
+	b.protected = append(b.protected, Rect{X: x, Y: y, W: width, H: height})
+	for row := y; row < y+height; row++ {
+		b.protectedCells = append(b.protectedCells, b.cells[row*b.width+x:][:width]...)
+	}
+}
+
+// Protected reports whether the cell at x, y is in a protected region.
+func (b *Buffer) Protected(x, y int) bool {
+	for _, r := range b.protected {
+		if x >= r.X && x < r.X+r.W && y >= r.Y && y < r.Y+r.H {
+			return true
+		}
+	}
+	return false
+}
+
+// Unprotect puts back the cells of the protected regions as they were
+// when protected, and ends their protection.
+func (b *Buffer) Unprotect() {
+	saved := b.protectedCells
+	for _, r := range b.protected {
+		for row := r.Y; row < r.Y+r.H; row++ {
+			saved = saved[copy(b.cells[row*b.width+r.X:][:r.W], saved[:r.W]):]
+			b.dirtyRows[row] = true
+		}
+	}
+	b.protected = b.protected[:0]
+	b.protectedCells = b.protectedCells[:0]
+}
+
+// addProtect records where a Protect component was drawn, less its margin.
+func (t *Template) addProtect(op *Op, geom *Geom, absX, absY int16) {
+	if t.protects == nil {
+		return
+	}
+	r := Rect{
+		X: int(absX + op.Margin[3]),
+		Y: int(absY + op.Margin[0]),
+		W: int(geom.W - op.marginH()),
+		H: int(geom.H - op.marginV()),
+	}
+	if t.clipMaxY > 0 {
+		r.H = min(r.H, int(t.clipMaxY)-r.Y)
+	}
+	*t.protects = append(*t.protects, r)
+}
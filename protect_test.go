@@ -0,0 +1,44 @@
+package glyph
+
+import "testing"
+
+func TestProtect(t *testing.T) {
+	items := []string{"a", "b"}
+	tmpl := Build(VBox(
+		HBox(Text("title"), Space(), Protect(Text("REC").FG(Red))),
+		ForEach(&items, func(s *string) any { return Protect(Text(s)) }),
+		Overlay.Size(30, 3).BG(Blue).Backdrop()(Text("modal over everything")),
+	))
+	buf := NewBuffer(30, 5)
+	tmpl.Execute(buf, 30, 5)
+
+	if c := buf.Get(27, 0); c.Rune != 'R' || c.Style.FG != Red || c.Style.Attr.Has(AttrDim) {
+		t.Errorf("protected cell = %q %+v, want it kept from the backdrop", c.Rune, c.Style)
+	}
+	if c := buf.Get(0, 0); !c.Style.Attr.Has(AttrDim) {
+		t.Error("backdrop didn't dim unprotected cells")
+	}
+	if got := buf.GetLine(1); got != "aodal over everything" {
+		t.Errorf("line 1 = %q, want the modal under the protected item", got)
+	}
+	if c := buf.Get(0, 1); c.Style.BG == Blue {
+		t.Errorf("modal filled the protected item: %+v", c.Style)
+	}
+	if buf.Protected(27, 0) {
+		t.Error("protection outlived the frame")
+	}
+}
+
+func TestBufferProtect(t *testing.T) {
+	buf := NewBuffer(5, 1)
+	buf.WriteStringFast(0, 0, "keep!", Style{}, 5)
+	buf.Protect(1, 0, 2, 1)
+	buf.WriteStringFast(0, 0, "-----", Style{}, 5)
+	if !buf.Protected(2, 0) || buf.Protected(3, 0) {
+		t.Error("Protected reports the wrong cells")
+	}
+	buf.Unprotect()
+	if got := buf.GetLine(0); got != "-ee--" {
+		t.Errorf("after Unprotect = %q, want %q", got, "-ee--")
+	}
+}
@@ -0,0 +1,575 @@
+package glyph
+
+import (
+	"errors"
+)
+
+// QRLevel is how much of a QR code can be damaged and still read: more
+// recovery makes a larger code.
+type QRLevel int
+
+const (
+	QRLow      QRLevel = iota // about 7% recoverable
+	QRMedium                  // 15%
+	QRQuartile                // 25%
+	QRHigh                    // 30%
+)
+
+// ErrQRTooLong is returned for text that won't fit in the largest QR code
+// at the level asked for.
+var ErrQRTooLong = errors.New("qr: text too long")
+
+// QRCodeC shows text as a QR code, for sharing URLs and pairing codes from
+// the terminal:
+//
+//	QRCode("https://example.com/device?code=" + code)
+//	QRCode(&url).Level(QRHigh)
+//
+// Each cell holds two modules, one above the other, as half blocks, so the
+// modules come out square. They are drawn in black and white whatever the
+// theme, with a quiet margin around them, for cameras to find. Text too
+// long for a QR code shows an error instead.
+type QRCodeC struct {
+	content any // string or *string
+	level   QRLevel
+	quiet   int
+	dark    Color
+	light   Color
+
+	// the last encoding, redone when the text or level changes
+	encoded    string
+	encodedAt  QRLevel
+	modules    [][]bool
+	err        error
+	hasEncoded bool
+}
+
+// QRCode creates a QR code of content, a string or *string.
+func QRCode(content any) *QRCodeC {
+	return &QRCodeC{content: content, level: QRMedium, quiet: 2, dark: Black, light: BrightWhite}
+}
+
+// Level sets the error correction level, QRMedium by default.
+func (q *QRCodeC) Level(l QRLevel) *QRCodeC {
+	q.level = l
+	return q
+}
+
+// QuietZone sets the light margin around the code, in modules. 2 by
+// default; the standard asks for 4, which some readers need.
+func (q *QRCodeC) QuietZone(n int) *QRCodeC {
+	q.quiet = max(n, 0)
+	return q
+}
+
+// Colors sets the colors of the dark and light modules.
+func (q *QRCodeC) Colors(dark, light Color) *QRCodeC {
+	q.dark, q.light = dark, light
+	return q
+}
+
+func (q *QRCodeC) text() string {
+	switch c := q.content.(type) {
+	case string:
+		return c
+	case *string:
+		return *c
+	}
+	return ""
+}
+
+// encode returns the modules for the current text, encoding it again only
+// if it changed.
+func (q *QRCodeC) encode() ([][]bool, error) {
+	text := q.text()
+	if !q.hasEncoded || text != q.encoded || q.level != q.encodedAt {
+		q.modules, q.err = EncodeQR(text, q.level)
+		q.encoded, q.encodedAt, q.hasEncoded = text, q.level, true
+	}
+	return q.modules, q.err
+}
+
+const qrTooLongText = "too long for a QR code"
+
+func (q *QRCodeC) measure(availW int16) (int16, int16) {
+	modules, err := q.encode()
+	if err != nil {
+		return int16(len(qrTooLongText)), 1
+	}
+	n := len(modules) + 2*q.quiet
+	return int16(n), int16((n + 1) / 2)
+}
+
+func (q *QRCodeC) render(buf *Buffer, x, y, w, h int16) {
+	modules, err := q.encode()
+	if err != nil {
+		buf.WriteStringFast(int(x), int(y), qrTooLongText, Style{FG: Red}, int(w))
+		return
+	}
+	size := len(modules)
+	n := size + 2*q.quiet
+	dark := func(mx, my int) bool {
+		mx, my = mx-q.quiet, my-q.quiet
+		return mx >= 0 && my >= 0 && mx < size && my < size && modules[my][mx]
+	}
+	color := func(d bool) Color {
+		if d {
+			return q.dark
+		}
+		return q.light
+	}
+	for row := 0; row < (n+1)/2 && row < int(h); row++ {
+		for col := 0; col < n && col < int(w); col++ {
+			top, bottom := dark(col, row*2), dark(col, row*2+1)
+			if row*2+1 >= n {
+				bottom = top // odd height: the last row's lower half is off the code
+			}
+			buf.Set(int(x)+col, int(y)+row, Cell{Rune: '▀', Style: Style{FG: color(top), BG: color(bottom)}})
+		}
+	}
+}
+
+func (t *Template) compileQRCodeC(v *QRCodeC, parent int16, depth int) int16 {
+	return t.compileCustom(Custom{Measure: v.measure, Render: v.render}, parent, depth)
+}
+
+// ============================================================================
+// Encoding
+// ============================================================================
+
+// EncodeQR encodes text as a QR code in byte mode, in the smallest version
+// that holds it at level, and returns its modules by row, true for dark.
+func EncodeQR(text string, level QRLevel) ([][]bool, error) {
+	data := []byte(text)
+	ver := 1
+	for ; ver <= 40; ver++ {
+		if 4+qrCountBits(ver)+8*len(data) <= qrDataCodewords(ver, level)*8 {
+			break
+		}
+	}
+	if ver > 40 {
+		return nil, ErrQRTooLong
+	}
+
+	// mode, count and data, then a terminator and padding to capacity
+	var bb qrBits
+	bb.add(0b0100, 4)
+	bb.add(len(data), qrCountBits(ver))
+	for _, b := range data {
+		bb.add(int(b), 8)
+	}
+	capacity := qrDataCodewords(ver, level) * 8
+	bb.add(0, min(4, capacity-len(bb)))
+	bb.add(0, (8-len(bb)%8)%8)
+	for pad := 0xEC; len(bb) < capacity; pad ^= 0xEC ^ 0x11 {
+		bb.add(pad, 8)
+	}
+	codewords := make([]byte, len(bb)/8)
+	for i, bit := range bb {
+		if bit {
+			codewords[i>>3] |= 1 << (7 - i&7)
+		}
+	}
+
+	m := newQRMatrix(ver)
+	m.drawFunctionPatterns(level)
+	m.drawCodewords(qrAddECC(codewords, ver, level))
+
+	// the mask scoring lowest on the standard's penalties
+	best, bestPenalty := 0, -1
+	for mask := range 8 {
+		m.applyMask(mask)
+		m.drawFormat(level, mask)
+		if p := m.penalty(); bestPenalty < 0 || p < bestPenalty {
+			best, bestPenalty = mask, p
+		}
+		m.applyMask(mask) // masking twice undoes it
+	}
+	m.applyMask(best)
+	m.drawFormat(level, best)
+	return m.modules, nil
+}
+
+// qrBits is a bit string built up most significant bit first.
+type qrBits []bool
+
+func (b *qrBits) add(v, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*b = append(*b, v>>i&1 == 1)
+	}
+}
+
+func qrCountBits(ver int) int {
+	if ver <= 9 {
+		return 8
+	}
+	return 16
+}
+
+// qrECCPerBlock and qrBlocks are the error correction codewords in each
+// block and the number of blocks, by level then version.
+var (
+	qrECCPerBlock = [4][41]int{
+		{0, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+		{0, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+		{0, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+		{0, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	}
+	qrBlocks = [4][41]int{
+		{0, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+		{0, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+		{0, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+		{0, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+	}
+)
+
+// qrRawModules returns how many modules of a version hold codewords: all
+// but the function patterns and format and version information.
+func qrRawModules(ver int) int {
+	n := (16*ver+128)*ver + 64
+	if ver >= 2 {
+		align := ver/7 + 2
+		n -= (25*align-10)*align - 55
+		if ver >= 7 {
+			n -= 36
+		}
+	}
+	return n
+}
+
+func qrDataCodewords(ver int, level QRLevel) int {
+	return qrRawModules(ver)/8 - qrECCPerBlock[level][ver]*qrBlocks[level][ver]
+}
+
+// qrAddECC splits data into the version's blocks, appends each block's
+// Reed-Solomon codewords, and interleaves the blocks.
+func qrAddECC(data []byte, ver int, level QRLevel) []byte {
+	numBlocks := qrBlocks[level][ver]
+	eccLen := qrECCPerBlock[level][ver]
+	raw := qrRawModules(ver) / 8
+	numShort := numBlocks - raw%numBlocks
+	shortLen := raw / numBlocks
+
+	divisor := qrRSDivisor(eccLen)
+	blocks := make([][]byte, numBlocks)
+	k := 0
+	for i := range blocks {
+		n := shortLen - eccLen
+		if i >= numShort {
+			n++
+		}
+		dat := data[k : k+n]
+		k += n
+		block := append([]byte(nil), dat...)
+		if i < numShort {
+			block = append(block, 0) // kept aligned with the long blocks; skipped below
+		}
+		blocks[i] = append(block, qrRSRemainder(dat, divisor)...)
+	}
+	var out []byte
+	for i := range blocks[0] {
+		for j, b := range blocks {
+			if i != shortLen-eccLen || j >= numShort {
+				out = append(out, b[i])
+			}
+		}
+	}
+	return out
+}
+
+// qrRSDivisor returns the Reed-Solomon generator polynomial of a degree,
+// highest coefficient first, the leading 1 left out.
+func qrRSDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for range degree {
+		for j := range result {
+			result[j] = qrGFMul(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = qrGFMul(root, 2)
+	}
+	return result
+}
+
+// qrRSRemainder returns the error correction codewords of data.
+func qrRSRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, d := range divisor {
+			result[i] ^= qrGFMul(d, factor)
+		}
+	}
+	return result
+}
+
+// qrGFMul multiplies in GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1.
+func qrGFMul(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = z<<1 ^ (z>>7)*0x11D
+		z ^= int(y>>i&1) * int(x)
+	}
+	return byte(z)
+}
+
+// qrMatrix is a code being built: its modules and which of them belong to
+// function patterns, which data and masks leave alone.
+type qrMatrix struct {
+	ver        int
+	size       int
+	modules    [][]bool
+	isFunction [][]bool
+}
+
+func newQRMatrix(ver int) *qrMatrix {
+	size := ver*4 + 17
+	m := &qrMatrix{ver: ver, size: size}
+	m.modules = make([][]bool, size)
+	m.isFunction = make([][]bool, size)
+	for i := range size {
+		m.modules[i] = make([]bool, size)
+		m.isFunction[i] = make([]bool, size)
+	}
+	return m
+}
+
+func (m *qrMatrix) setFunction(x, y int, dark bool) {
+	m.modules[y][x] = dark
+	m.isFunction[y][x] = true
+}
+
+func (m *qrMatrix) drawFunctionPatterns(level QRLevel) {
+	for i := range m.size {
+		m.setFunction(6, i, i%2 == 0)
+		m.setFunction(i, 6, i%2 == 0)
+	}
+	m.drawFinder(3, 3)
+	m.drawFinder(m.size-4, 3)
+	m.drawFinder(3, m.size-4)
+
+	pos := m.alignmentPositions()
+	last := len(pos) - 1
+	for i := range pos {
+		for j := range pos {
+			if i == 0 && j == 0 || i == 0 && j == last || i == last && j == 0 {
+				continue // the finders are here
+			}
+			for dy := -2; dy <= 2; dy++ {
+				for dx := -2; dx <= 2; dx++ {
+					m.setFunction(pos[i]+dx, pos[j]+dy, max(abs(dx), abs(dy)) != 1)
+				}
+			}
+		}
+	}
+
+	m.drawFormat(level, 0) // reserved now, drawn for real once masked
+	m.drawVersion()
+}
+
+// drawFinder draws a finder pattern and its separator around (x, y).
+func (m *qrMatrix) drawFinder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx >= 0 && xx < m.size && yy >= 0 && yy < m.size {
+				d := max(abs(dx), abs(dy))
+				m.setFunction(xx, yy, d != 2 && d != 4)
+			}
+		}
+	}
+}
+
+// alignmentPositions returns the centers of the version's alignment
+// patterns along each axis.
+func (m *qrMatrix) alignmentPositions() []int {
+	if m.ver == 1 {
+		return nil
+	}
+	n := m.ver/7 + 2
+	step := (m.ver*8 + n*3 + 5) / (n*4 - 4) * 2
+	pos := make([]int, n)
+	pos[0] = 6
+	for i, p := n-1, m.size-7; i >= 1; i, p = i-1, p-step {
+		pos[i] = p
+	}
+	return pos
+}
+
+// qrFormatLevel is each level's two format bits.
+var qrFormatLevel = [4]int{QRLow: 1, QRMedium: 0, QRQuartile: 3, QRHigh: 2}
+
+// qrFormatBits returns the 15 format bits of a level and mask, BCH coded
+// and masked.
+func qrFormatBits(level QRLevel, mask int) int {
+	data := qrFormatLevel[level]<<3 | mask
+	rem := data
+	for range 10 {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+func (m *qrMatrix) drawFormat(level QRLevel, mask int) {
+	bits := qrFormatBits(level, mask)
+	bit := func(i int) bool { return bits>>i&1 == 1 }
+	for i := range 6 {
+		m.setFunction(8, i, bit(i))
+	}
+	m.setFunction(8, 7, bit(6))
+	m.setFunction(8, 8, bit(7))
+	m.setFunction(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		m.setFunction(14-i, 8, bit(i))
+	}
+	for i := range 8 {
+		m.setFunction(m.size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		m.setFunction(8, m.size-15+i, bit(i))
+	}
+	m.setFunction(8, m.size-8, true) // always dark
+}
+
+// qrVersionBits returns the 18 version bits of versions 7 and up.
+func qrVersionBits(ver int) int {
+	rem := ver
+	for range 12 {
+		rem = rem<<1 ^ (rem>>11)*0x1F25
+	}
+	return ver<<12 | rem
+}
+
+func (m *qrMatrix) drawVersion() {
+	if m.ver < 7 {
+		return
+	}
+	bits := qrVersionBits(m.ver)
+	for i := range 18 {
+		dark := bits>>i&1 == 1
+		a, b := m.size-11+i%3, i/3
+		m.setFunction(a, b, dark)
+		m.setFunction(b, a, dark)
+	}
+}
+
+// drawCodewords places data in the modules left free, in two-module
+// columns zigzagging up and down from the bottom right.
+func (m *qrMatrix) drawCodewords(data []byte) {
+	i := 0
+	for right := m.size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // skip the vertical timing pattern
+		}
+		upward := (right+1)&2 == 0
+		for vert := range m.size {
+			for j := range 2 {
+				x, y := right-j, vert
+				if upward {
+					y = m.size - 1 - vert
+				}
+				if !m.isFunction[y][x] && i < len(data)*8 {
+					m.modules[y][x] = data[i>>3]>>(7-i&7)&1 == 1
+					i++
+				}
+			}
+		}
+	}
+}
+
+// qrMasks are the eight data masks; a module is flipped where its mask
+// holds.
+var qrMasks = [8]func(x, y int) bool{
+	func(x, y int) bool { return (x+y)%2 == 0 },
+	func(x, y int) bool { return y%2 == 0 },
+	func(x, y int) bool { return x%3 == 0 },
+	func(x, y int) bool { return (x+y)%3 == 0 },
+	func(x, y int) bool { return (x/3+y/2)%2 == 0 },
+	func(x, y int) bool { return x*y%2+x*y%3 == 0 },
+	func(x, y int) bool { return (x*y%2+x*y%3)%2 == 0 },
+	func(x, y int) bool { return ((x+y)%2+x*y%3)%2 == 0 },
+}
+
+func (m *qrMatrix) applyMask(mask int) {
+	for y := range m.size {
+		for x := range m.size {
+			if !m.isFunction[y][x] && qrMasks[mask](x, y) {
+				m.modules[y][x] = !m.modules[y][x]
+			}
+		}
+	}
+}
+
+// penalty scores the modules as the standard does to pick a mask: long
+// runs, 2x2 blocks, finder-like patterns and imbalance of dark and light
+// all make a code harder to read.
+func (m *qrMatrix) penalty() int {
+	n := m.size
+	at := func(x, y int, transpose bool) bool {
+		if transpose {
+			return m.modules[x][y]
+		}
+		return m.modules[y][x]
+	}
+	p := 0
+	for _, transpose := range []bool{false, true} {
+		for y := range n {
+			run := 1
+			for x := 1; x <= n; x++ {
+				if x < n && at(x, y, transpose) == at(x-1, y, transpose) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					p += 3 + run - 5
+				}
+				run = 1
+			}
+			// 1:1:3:1:1 with four light modules on one side
+			for x := 0; x+11 <= n; x++ {
+				var pattern [11]bool
+				for i := range pattern {
+					pattern[i] = at(x+i, y, transpose)
+				}
+				if pattern == qrFinderBefore || pattern == qrFinderAfter {
+					p += 40
+				}
+			}
+		}
+	}
+	dark := 0
+	for y := range n {
+		for x := range n {
+			if m.modules[y][x] {
+				dark++
+			}
+			if x+1 < n && y+1 < n {
+				c := m.modules[y][x]
+				if c == m.modules[y][x+1] && c == m.modules[y+1][x] && c == m.modules[y+1][x+1] {
+					p += 3
+				}
+			}
+		}
+	}
+	total := n * n
+	k := (abs(dark*20-total*10)+total-1)/total - 1
+	return p + k*10
+}
+
+var (
+	qrFinderBefore = [11]bool{false, false, false, false, true, false, true, true, true, false, true}
+	qrFinderAfter  = [11]bool{true, false, true, true, true, false, true, false, false, false, false}
+)
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
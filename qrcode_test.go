@@ -0,0 +1,216 @@
+package glyph
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestQRReedSolomon(t *testing.T) {
+	// the 1-M "HELLO WORLD" example worked through in most QR tutorials
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	want := []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23}
+	if got := qrRSRemainder(data, qrRSDivisor(10)); string(got) != string(want) {
+		t.Errorf("ecc = %v, want %v", got, want)
+	}
+}
+
+func TestQRTables(t *testing.T) {
+	if got := qrFormatBits(QRLow, 0); got != 0b111011111000100 {
+		t.Errorf("L/0 format = %015b", got)
+	}
+	if got := qrFormatBits(QRHigh, 7); got != 0b000100000111011 {
+		t.Errorf("H/7 format = %015b", got)
+	}
+	if got := qrVersionBits(7); got != 0b000111110010010100 {
+		t.Errorf("v7 version = %018b", got)
+	}
+	// data codewords from the standard's capacity table
+	for _, c := range []struct {
+		ver   int
+		level QRLevel
+		want  int
+	}{{1, QRLow, 19}, {1, QRHigh, 9}, {5, QRQuartile, 62}, {10, QRHigh, 122}, {40, QRLow, 2956}, {40, QRMedium, 2334}} {
+		if got := qrDataCodewords(c.ver, c.level); got != c.want {
+			t.Errorf("v%d level %d holds %d codewords, want %d", c.ver, c.level, got, c.want)
+		}
+	}
+	m := newQRMatrix(32)
+	if got := m.alignmentPositions(); len(got) != 6 || got[1] != 34 || got[5] != 138 {
+		t.Errorf("v32 alignment = %v", got)
+	}
+}
+
+// readQR reads the text back out of a code: the reverse of EncodeQR's
+// masking, placement and interleaving, written separately from it.
+func readQR(t *testing.T, modules [][]bool, level QRLevel) string {
+	t.Helper()
+	size := len(modules)
+	ver := (size - 17) / 4
+	format := 0
+	for i := 0; i < 15; i++ {
+		x, y := 8, i
+		switch {
+		case i == 6:
+			y = 7
+		case i == 7:
+			y = 8
+		case i >= 8:
+			x, y = 14-i, 8
+			if i == 8 {
+				x = 7
+			}
+		}
+		if modules[y][x] {
+			format |= 1 << i
+		}
+	}
+	mask := -1
+	for k := range 8 {
+		if qrFormatBits(level, k) == format {
+			mask = k
+		}
+	}
+	if mask < 0 {
+		t.Fatalf("format bits %015b match no mask at level %d", format, level)
+	}
+
+	fn := newQRMatrix(ver)
+	fn.drawFunctionPatterns(level)
+	var raw []byte
+	var cur byte
+	n := 0
+	for right := size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := range size {
+			for j := range 2 {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = size - 1 - vert
+				}
+				if fn.isFunction[y][x] {
+					continue
+				}
+				bit := modules[y][x] != qrMasks[mask](x, y)
+				cur <<= 1
+				if bit {
+					cur |= 1
+				}
+				if n++; n%8 == 0 {
+					raw = append(raw, cur)
+					cur = 0
+				}
+			}
+		}
+	}
+
+	blocks := qrBlocks[level][ver]
+	eccLen := qrECCPerBlock[level][ver]
+	total := qrRawModules(ver) / 8
+	raw = raw[:total]
+	dataLen := make([]int, blocks)
+	for i := range blocks {
+		dataLen[i] = total/blocks - eccLen
+		if i >= blocks-total%blocks {
+			dataLen[i]++
+		}
+	}
+	data := make([][]byte, blocks)
+	k := 0
+	for i := 0; k < total-eccLen*blocks; i++ {
+		for b := range blocks {
+			if i < dataLen[b] {
+				data[b] = append(data[b], raw[k])
+				k++
+			}
+		}
+	}
+	for i := range eccLen {
+		for b := range blocks {
+			if want := qrRSRemainder(data[b], qrRSDivisor(eccLen))[i]; raw[k] != want {
+				t.Fatalf("block %d ecc %d = %d, want %d", b, i, raw[k], want)
+			}
+			k++
+		}
+	}
+
+	var stream []byte
+	for _, d := range data {
+		stream = append(stream, d...)
+	}
+	bit := func(i int) int { return int(stream[i/8]>>(7-i%8)) & 1 }
+	read := func(at, bits int) int {
+		v := 0
+		for i := range bits {
+			v = v<<1 | bit(at+i)
+		}
+		return v
+	}
+	if mode := read(0, 4); mode != 0b0100 {
+		t.Fatalf("mode = %04b", mode)
+	}
+	count := qrCountBits(ver)
+	length := read(4, count)
+	out := make([]byte, length)
+	for i := range out {
+		out[i] = byte(read(4+count+8*i, 8))
+	}
+	return string(out)
+}
+
+func TestEncodeQR(t *testing.T) {
+	for _, c := range []struct {
+		text  string
+		level QRLevel
+		ver   int
+	}{
+		{"", QRMedium, 1},
+		{"https://example.com", QRMedium, 2},
+		{"https://example.com", QRHigh, 3},
+		{strings.Repeat("glyph ", 40), QRQuartile, 13},
+		{strings.Repeat("é", 400), QRLow, 20},
+	} {
+		modules, err := EncodeQR(c.text, c.level)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := c.ver*4 + 17; len(modules) != want {
+			t.Errorf("%q at %d: size %d, want %d", c.text[:min(len(c.text), 20)], c.level, len(modules), want)
+		}
+		for _, corner := range [][2]int{{0, 0}, {len(modules) - 7, 0}, {0, len(modules) - 7}} {
+			if !modules[corner[1]][corner[0]] || modules[corner[1]+1][corner[0]+1] || !modules[corner[1]+3][corner[0]+3] {
+				t.Errorf("no finder at %v", corner)
+			}
+		}
+		if got := readQR(t, modules, c.level); got != c.text {
+			t.Errorf("read back %q, want %q", got, c.text)
+		}
+	}
+	if _, err := EncodeQR(strings.Repeat("x", 3000), QRLow); err != ErrQRTooLong {
+		t.Errorf("3000 bytes: err = %v", err)
+	}
+}
+
+func TestQRCode(t *testing.T) {
+	url := "hi"
+	q := QRCode(&url).QuietZone(1)
+	tmpl := Build(VBox(q))
+	buf := NewBuffer(30, 12)
+	tmpl.Execute(buf, 30, 12)
+
+	// 21 modules and a module of margin each side: 23 across, 12 rows of two
+	if got := buf.GetLine(11); got != strings.Repeat("▀", 23) {
+		t.Errorf("last row = %q", got)
+	}
+	if c := buf.Get(1, 0); c.Style.FG != BrightWhite || c.Style.BG != Black {
+		t.Errorf("finder's top corner = %+v, want light over dark", c.Style)
+	}
+
+	url = strings.Repeat("x", 3000)
+	buf = NewBuffer(30, 12)
+	tmpl.Execute(buf, 30, 12)
+	if got := buf.GetLine(0); got != qrTooLongText {
+		t.Errorf("too long shows %q", got)
+	}
+}
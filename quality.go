@@ -0,0 +1,188 @@
+package glyph
+
+import "time"
+
+// Quality is how much detail an app draws. Apps start at QualityFull and,
+// with a FrameBudget, drop a level when frames keep taking too long and
+// come back up once they're comfortably fast again.
+type Quality uint8
+
+const (
+	// QualityFull draws everything as designed.
+	QualityFull Quality = iota
+	// QualityReduced skips animations, so transitions and flashes jump
+	// straight to their end, draws sparklines at half resolution, and
+	// runs Every tickers at half rate.
+	QualityReduced
+	// QualityMinimal also runs Every tickers at a quarter rate.
+	QualityMinimal
+)
+
+func (q Quality) String() string {
+	switch q {
+	case QualityFull:
+		return "full"
+	case QualityReduced:
+		return "reduced"
+	case QualityMinimal:
+		return "minimal"
+	}
+	return "unknown"
+}
+
+const (
+	// degradeAfter is how many frames in a row over budget lower quality.
+	degradeAfter = 5
+	// recoverAfter is how long frames must stay within half the budget
+	// before quality goes back up.
+	recoverAfter = 2 * time.Second
+)
+
+// FrameBudget turns on adaptive quality: when degradeAfter frames in a row
+// take longer than d, the app lowers its Quality a level, and when frames
+// have taken under half of d for recoverAfter, it raises it again. A
+// frame's time runs from the start of the render to the frame having been
+// presented. 0 turns it off, leaving quality where it is.
+//
+//	app.FrameBudget(16 * time.Millisecond)
+func (a *App) FrameBudget(d time.Duration) *App {
+	a.frameBudget = d
+	return a
+}
+
+// Quality returns the app's current quality level.
+func (a *App) Quality() Quality {
+	return Quality(a.quality.Load())
+}
+
+// SetQuality sets the quality level, as FrameBudget would. With a budget
+// set, the app keeps adjusting it from there.
+func (a *App) SetQuality(q Quality) *App {
+	if Quality(a.quality.Swap(uint32(q))) == q {
+		return a
+	}
+	if a.onQuality != nil {
+		a.onQuality(q)
+	}
+	a.RequestRender()
+	return a
+}
+
+// OnQualityChange sets a callback for when the quality level changes, for
+// an app to scale back work of its own under load. Changes FrameBudget
+// makes call it on the render loop.
+func (a *App) OnQualityChange(fn func(Quality)) *App {
+	a.onQuality = fn
+	return a
+}
+
+// frameTook adjusts quality for a frame that took took, finishing at now.
+func (a *App) frameTook(took time.Duration, now time.Time) {
+	if a.frameBudget <= 0 {
+		return
+	}
+	q := a.Quality()
+	switch {
+	case took > a.frameBudget:
+		a.overBudget++
+		a.calmSince = now
+		if a.overBudget >= degradeAfter && q < QualityMinimal {
+			a.overBudget = 0
+			a.SetQuality(q + 1)
+		}
+	case took > a.frameBudget/2:
+		a.overBudget = 0
+		a.calmSince = now
+	default:
+		a.overBudget = 0
+		if a.calmSince.IsZero() {
+			a.calmSince = now
+		}
+		if q > QualityFull && now.Sub(a.calmSince) >= recoverAfter {
+			a.calmSince = now
+			a.SetQuality(q - 1)
+		}
+	}
+}
+
+// tickEvery is how often an Every ticker asked to run every d runs at the
+// current quality.
+func (a *App) tickEvery(d time.Duration) time.Duration {
+	return d << a.Quality()
+}
+
+// qualityUser is a compiled node that draws differently by quality.
+type qualityUser interface {
+	setQuality(Quality)
+}
+
+func (t *Template) collectQualityUser(node any) {
+	if q, ok := node.(qualityUser); ok {
+		t.qualityUsers = append(t.qualityUsers, q)
+	}
+}
+
+// SetQuality switches the template and the nodes in it to quality q. App
+// does this for you; call it when executing a template directly.
+func (t *Template) SetQuality(q Quality) {
+	t.quality = q
+	for _, u := range t.qualityUsers {
+		u.setQuality(q)
+	}
+}
+
+// AdaptiveC shows one of several views of the same thing by the app's
+// quality level, so a component can register cheaper views of itself to
+// use under load:
+//
+//	Adaptive(HBox(Sparkline(&history).Width(60), Text(&rate))).
+//	    Reduced(Text(&rate)).
+//	    Minimal(Space())
+//
+// A level without a view of its own uses the next richer one registered.
+type AdaptiveC struct {
+	full, reduced, minimal any
+	quality                Quality
+}
+
+// Adaptive wraps the view drawn at QualityFull.
+func Adaptive(full any) *AdaptiveC {
+	return &AdaptiveC{full: full}
+}
+
+// Reduced sets the view drawn at QualityReduced.
+func (c *AdaptiveC) Reduced(view any) *AdaptiveC {
+	c.reduced = view
+	return c
+}
+
+// Minimal sets the view drawn at QualityMinimal.
+func (c *AdaptiveC) Minimal(view any) *AdaptiveC {
+	c.minimal = view
+	return c
+}
+
+func (c *AdaptiveC) setQuality(q Quality) { c.quality = q }
+
+// AdaptiveC compiles as a Switch over its quality, with the full view as
+// the default and a case for each of the others.
+
+func (c *AdaptiveC) evaluateSwitch() any {
+	if i := c.getMatchIndex(); i >= 0 {
+		return c.getCaseNodes()[i]
+	}
+	return c.full
+}
+
+func (c *AdaptiveC) getCaseNodes() []any { return []any{c.reduced, c.minimal} }
+func (c *AdaptiveC) getDefaultNode() any { return c.full }
+
+func (c *AdaptiveC) getMatchIndex() int {
+	switch {
+	case c.quality >= QualityMinimal && c.minimal != nil:
+		return 1
+	case c.quality >= QualityReduced && c.reduced != nil:
+		return 0
+	}
+	return -1
+}
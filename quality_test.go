@@ -0,0 +1,154 @@
+package glyph
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFrameBudget(t *testing.T) {
+	a := &App{}
+	a.FrameBudget(10 * time.Millisecond)
+	var changes []Quality
+	a.OnQualityChange(func(q Quality) { changes = append(changes, q) })
+
+	now := time.Now()
+	frame := func(took time.Duration) {
+		now = now.Add(took + time.Millisecond)
+		a.frameTook(took, now)
+	}
+
+	// a slow frame now and then is tolerated
+	for range 3 {
+		frame(20 * time.Millisecond)
+		frame(2 * time.Millisecond)
+	}
+	if a.Quality() != QualityFull {
+		t.Fatalf("quality %v after occasional slow frames", a.Quality())
+	}
+
+	for range degradeAfter {
+		frame(20 * time.Millisecond)
+	}
+	if a.Quality() != QualityReduced {
+		t.Fatalf("quality %v after %d slow frames, want reduced", a.Quality(), degradeAfter)
+	}
+	for range 3 * degradeAfter {
+		frame(20 * time.Millisecond)
+	}
+	if a.Quality() != QualityMinimal {
+		t.Fatalf("quality %v under sustained load, want minimal", a.Quality())
+	}
+
+	// frames near the budget don't count as calm
+	for range 100 {
+		frame(8 * time.Millisecond)
+	}
+	if a.Quality() != QualityMinimal {
+		t.Fatalf("recovered to %v while frames were near budget", a.Quality())
+	}
+
+	// fast frames for recoverAfter raise it a level at a time
+	for range 2 {
+		for elapsed := time.Duration(0); elapsed <= recoverAfter; elapsed += 100 * time.Millisecond {
+			frame(time.Millisecond)
+			now = now.Add(100 * time.Millisecond)
+		}
+	}
+	if a.Quality() != QualityFull {
+		t.Errorf("quality %v after load dropped, want full", a.Quality())
+	}
+	want := []Quality{QualityReduced, QualityMinimal, QualityReduced, QualityFull}
+	if len(changes) != len(want) {
+		t.Fatalf("changes %v, want %v", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("changes %v, want %v", changes, want)
+		}
+	}
+}
+
+func TestAdaptive(t *testing.T) {
+	tmpl := Build(VBox(
+		Adaptive(Text("full")).Minimal(Text("minimal")),
+		Adaptive(Text("rich")).Reduced(Text("plain")),
+	))
+	lines := func(q Quality) string {
+		tmpl.SetQuality(q)
+		buf := NewBuffer(10, 2)
+		tmpl.Execute(buf, 10, 2)
+		return strings.TrimSpace(buf.GetLine(0)) + "," + strings.TrimSpace(buf.GetLine(1))
+	}
+	for q, want := range map[Quality]string{
+		QualityFull:    "full,rich",
+		QualityReduced: "full,plain",
+		QualityMinimal: "minimal,plain",
+	} {
+		if got := lines(q); got != want {
+			t.Errorf("%v: got %q, want %q", q, got, want)
+		}
+	}
+}
+
+func TestQualitySkipsAnimation(t *testing.T) {
+	clock := NewFakeClock(time.Time{})
+	show := false
+	price := "1"
+	tmpl := Build(VBox(
+		If(&show).Then(Text("panel")).Transition(Slide),
+		FlashOnChange(Text(&price)).Style(Style{BG: Red}),
+	))
+	tmpl.SetClock(clock)
+	tmpl.SetQuality(QualityReduced)
+	frame := func() *Buffer {
+		buf := NewBuffer(10, 2)
+		tmpl.Execute(buf, 10, 2)
+		return buf
+	}
+	frame()
+
+	// the panel appears in full at once
+	show = true
+	price = "2"
+	buf := frame()
+	if got := strings.TrimSpace(buf.GetLine(0)); got != "panel" {
+		t.Errorf("panel mid-transition = %q", got)
+	}
+	// the flash holds at full strength, then ends
+	if bg := buf.Get(0, 1).Style.BG; bg != Red {
+		t.Errorf("flash bg = %+v", bg)
+	}
+	clock.Advance(700 * time.Millisecond)
+	if bg := frame().Get(0, 1).Style.BG; bg.Mode != ColorDefault {
+		t.Errorf("after flash bg = %+v", bg)
+	}
+}
+
+func TestCoarseSparkline(t *testing.T) {
+	values := []float64{0, 7, 1, 6, 2, 5, 3, 4}
+	fine, coarse := NewBuffer(8, 1), NewBuffer(8, 1)
+	fine.WriteSparkline(0, 0, values, 8, 0, 7, Style{})
+	coarse.writeSparkline(0, 0, values, 8, 0, 7, Style{}, true)
+	if got := fine.GetLine(0); got != "▁█▂▇▃▆▄▅" {
+		t.Errorf("fine = %q", got)
+	}
+	if got := coarse.GetLine(0); got != "▂▂▂▂▄▄▄▄" {
+		t.Errorf("coarse = %q", got)
+	}
+}
+
+func TestEveryAtReducedQuality(t *testing.T) {
+	app, _ := NewApp()
+	clock := NewFakeClock(time.Time{})
+	app.SetBackend(NewBufferBackend(10, 1)).SetClock(clock).SetView(Text("x"))
+	app.SetQuality(QualityReduced)
+
+	ticks := 0
+	app.Every(100*time.Millisecond, func() { ticks++ })
+	clock.Advance(450 * time.Millisecond)
+	app.RenderNow()
+	if ticks != 2 {
+		t.Errorf("%d ticks in 450ms at half rate, want 2", ticks)
+	}
+}
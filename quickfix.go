@@ -0,0 +1,460 @@
+package glyph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// QuickfixEntry is a location in a file with a message, as produced by
+// grep, compilers, linters or diagnostics.
+type QuickfixEntry struct {
+	File    string
+	Line    int // 1-based
+	Col     int // 1-based, 0 when unknown
+	Message string
+	Kind    string // optional tag such as "error" or "warning"
+}
+
+// QuickfixGroup is a run of entries for the same file.
+type QuickfixGroup struct {
+	File    string
+	Start   int // index of the first entry in the list
+	Entries []QuickfixEntry
+}
+
+// QuickfixList is a shared, thread-safe list of locations with a current
+// entry. It holds no view state, so one list can back several views and
+// survives view switches. Entries are kept grouped by file in the order
+// files first appear.
+//
+//	qf := NewQuickfixList().OnJump(func(e QuickfixEntry) { open(e.File, e.Line) })
+//	qf.Append(QuickfixEntry{File: "main.go", Line: 12, Message: "unused variable"})
+//	app.Handle("]q", func() { qf.Next() })
+type QuickfixList struct {
+	mu       sync.Mutex
+	entries  []QuickfixEntry
+	current  int
+	onUpdate func() // called when entries change (for RequestRender)
+	onJump   func(QuickfixEntry)
+}
+
+// NewQuickfixList creates an empty list.
+func NewQuickfixList() *QuickfixList {
+	return &QuickfixList{}
+}
+
+// OnUpdate sets a callback fired whenever entries are added or cleared.
+// Quickfix views wire this to app.RequestRender automatically.
+func (q *QuickfixList) OnUpdate(fn func()) *QuickfixList {
+	q.onUpdate = fn
+	return q
+}
+
+// OnJump sets the callback fired by Next, Prev, NextFile, PrevFile and Jump.
+func (q *QuickfixList) OnJump(fn func(QuickfixEntry)) *QuickfixList {
+	q.onJump = fn
+	return q
+}
+
+// Append adds entries, placing each after the existing entries for its file.
+// Safe to call from any goroutine.
+func (q *QuickfixList) Append(entries ...QuickfixEntry) {
+	q.mu.Lock()
+	for _, e := range entries {
+		at := len(q.entries)
+		for i := len(q.entries) - 1; i >= 0; i-- {
+			if q.entries[i].File == e.File {
+				at = i + 1
+				break
+			}
+		}
+		q.entries = append(q.entries, QuickfixEntry{})
+		copy(q.entries[at+1:], q.entries[at:])
+		q.entries[at] = e
+		if at <= q.current && len(q.entries) > 1 {
+			q.current++
+		}
+	}
+	q.mu.Unlock()
+	q.notify()
+}
+
+// Clear removes every entry.
+func (q *QuickfixList) Clear() {
+	q.mu.Lock()
+	q.entries = nil
+	q.current = 0
+	q.mu.Unlock()
+	q.notify()
+}
+
+// Len returns the number of entries.
+func (q *QuickfixList) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return len(q.entries)
+}
+
+// Entries returns a copy of all entries.
+func (q *QuickfixList) Entries() []QuickfixEntry {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	out := make([]QuickfixEntry, len(q.entries))
+	copy(out, q.entries)
+	return out
+}
+
+// Groups returns the entries grouped by file.
+func (q *QuickfixList) Groups() []QuickfixGroup {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var groups []QuickfixGroup
+	for i, e := range q.entries {
+		if n := len(groups); n > 0 && groups[n-1].File == e.File {
+			groups[n-1].Entries = append(groups[n-1].Entries, e)
+			continue
+		}
+		groups = append(groups, QuickfixGroup{File: e.File, Start: i, Entries: []QuickfixEntry{e}})
+	}
+	return groups
+}
+
+// Index returns the index of the current entry.
+func (q *QuickfixList) Index() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.current
+}
+
+// Current returns the current entry.
+func (q *QuickfixList) Current() (QuickfixEntry, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.current >= len(q.entries) {
+		return QuickfixEntry{}, false
+	}
+	return q.entries[q.current], true
+}
+
+// Select makes entry i current without jumping.
+func (q *QuickfixList) Select(i int) {
+	q.mu.Lock()
+	q.current = max(min(i, len(q.entries)-1), 0)
+	q.mu.Unlock()
+}
+
+// Jump fires OnJump for the current entry.
+func (q *QuickfixList) Jump() {
+	if e, ok := q.Current(); ok && q.onJump != nil {
+		q.onJump(e)
+	}
+}
+
+// Next moves to the next entry and jumps to it. Returns false at the end.
+func (q *QuickfixList) Next() bool {
+	return q.move(func(entries []QuickfixEntry, cur int) int { return cur + 1 })
+}
+
+// Prev moves to the previous entry and jumps to it. Returns false at the start.
+func (q *QuickfixList) Prev() bool {
+	return q.move(func(entries []QuickfixEntry, cur int) int { return cur - 1 })
+}
+
+// NextFile moves to the first entry of the next file.
+func (q *QuickfixList) NextFile() bool {
+	return q.move(func(entries []QuickfixEntry, cur int) int {
+		i := cur
+		for i < len(entries) && entries[i].File == entries[cur].File {
+			i++
+		}
+		return i
+	})
+}
+
+// PrevFile moves to the first entry of the previous file.
+func (q *QuickfixList) PrevFile() bool {
+	return q.move(func(entries []QuickfixEntry, cur int) int {
+		i := cur
+		for i > 0 && entries[i-1].File == entries[cur].File {
+			i--
+		}
+		if i == 0 {
+			return -1
+		}
+		i--
+		for i > 0 && entries[i-1].File == entries[i].File {
+			i--
+		}
+		return i
+	})
+}
+
+func (q *QuickfixList) move(fn func(entries []QuickfixEntry, cur int) int) bool {
+	q.mu.Lock()
+	if len(q.entries) == 0 {
+		q.mu.Unlock()
+		return false
+	}
+	i := fn(q.entries, q.current)
+	if i < 0 || i >= len(q.entries) {
+		q.mu.Unlock()
+		return false
+	}
+	q.current = i
+	q.mu.Unlock()
+	q.Jump()
+	return true
+}
+
+func (q *QuickfixList) notify() {
+	if q.onUpdate != nil {
+		q.onUpdate()
+	}
+}
+
+// quickfixLine matches "file:line:col: message" and "file:line: message".
+var quickfixLine = regexp.MustCompile(`^(.+?):(\d+)(?::(\d+))?:\s*(.*)$`)
+
+// ParseQuickfix parses a compiler-style line such as
+// "main.go:12:5: undefined: x". Returns false if the line doesn't match.
+func ParseQuickfix(line string) (QuickfixEntry, bool) {
+	m := quickfixLine.FindStringSubmatch(strings.TrimRight(line, "\r"))
+	if m == nil {
+		return QuickfixEntry{}, false
+	}
+	e := QuickfixEntry{File: m[1], Message: m[4]}
+	e.Line, _ = strconv.Atoi(m[2])
+	e.Col, _ = strconv.Atoi(m[3])
+	for _, kind := range []string{"error", "warning", "note"} {
+		if rest, ok := strings.CutPrefix(e.Message, kind+":"); ok {
+			e.Kind, e.Message = kind, strings.TrimSpace(rest)
+			break
+		}
+	}
+	return e, true
+}
+
+// ReadFrom appends every parseable line from r, e.g. the output of a build
+// or linter. Unparseable lines are skipped. Blocks until r is exhausted, so
+// run it in a goroutine for long-running producers.
+func (q *QuickfixList) ReadFrom(r io.Reader) (int64, error) {
+	scanner := bufio.NewScanner(r)
+	var n int64
+	for scanner.Scan() {
+		n += int64(len(scanner.Bytes())) + 1
+		if e, ok := ParseQuickfix(scanner.Text()); ok {
+			q.Append(e)
+		}
+	}
+	return n, scanner.Err()
+}
+
+// Entry converts a grep match into a quickfix entry.
+func (m GrepMatch) Entry() QuickfixEntry {
+	return QuickfixEntry{File: m.File, Line: m.Line, Col: m.Col + 1, Message: strings.TrimSpace(m.Text)}
+}
+
+// ============================================================================
+// Quickfix component
+// ============================================================================
+
+// QuickfixC displays a QuickfixList grouped by file, highlighting the
+// current entry.
+//
+//	Quickfix(qf).BindNav("j", "k").BindJump("<Enter>").BindFileNav("]f", "[f")
+type QuickfixC struct {
+	list  *QuickfixList
+	layer *Layer
+	top   int
+
+	fileStyle     Style
+	posStyle      Style
+	errorStyle    Style
+	warningStyle  Style
+	selectedStyle Style
+
+	grow   float32
+	height int16
+	margin [4]int16
+
+	declaredBindings []binding
+}
+
+// Quickfix creates a view of list.
+func Quickfix(list *QuickfixList) *QuickfixC {
+	q := &QuickfixC{
+		list:          list,
+		layer:         NewLayer(),
+		fileStyle:     Style{FG: Magenta, Attr: AttrBold},
+		posStyle:      Style{FG: Green},
+		errorStyle:    Style{FG: Red},
+		warningStyle:  Style{FG: Yellow},
+		selectedStyle: Style{BG: PaletteColor(237)},
+	}
+	q.layer.AlwaysRender = true
+	q.layer.Render = q.sync
+	return q
+}
+
+// Ref provides access to the component for external references.
+func (q *QuickfixC) Ref(f func(*QuickfixC)) *QuickfixC { f(q); return q }
+
+// List returns the underlying list.
+func (q *QuickfixC) List() *QuickfixList { return q.list }
+
+// FileStyle sets the style of file group headers.
+func (q *QuickfixC) FileStyle(s Style) *QuickfixC {
+	q.fileStyle = s
+	return q
+}
+
+// SelectedStyle sets the style of the current entry.
+func (q *QuickfixC) SelectedStyle(s Style) *QuickfixC {
+	q.selectedStyle = s
+	return q
+}
+
+// Grow sets the flex grow factor.
+func (q *QuickfixC) Grow(g float32) *QuickfixC {
+	q.grow = g
+	return q
+}
+
+// Height sets a fixed viewport height.
+func (q *QuickfixC) Height(h int16) *QuickfixC {
+	q.height = h
+	return q
+}
+
+// Margin sets uniform margin on all sides.
+func (q *QuickfixC) Margin(all int16) *QuickfixC {
+	q.margin = [4]int16{all, all, all, all}
+	return q
+}
+
+// MarginVH sets vertical and horizontal margin.
+func (q *QuickfixC) MarginVH(v, h int16) *QuickfixC {
+	q.margin = [4]int16{v, h, v, h}
+	return q
+}
+
+// MarginTRBL sets individual margins for top, right, bottom, left.
+func (q *QuickfixC) MarginTRBL(t, r, b, l int16) *QuickfixC {
+	q.margin = [4]int16{t, r, b, l}
+	return q
+}
+
+// BindNav registers keys that move the current entry without jumping.
+func (q *QuickfixC) BindNav(down, up string) *QuickfixC {
+	q.declaredBindings = append(q.declaredBindings,
+		binding{pattern: down, handler: func() { q.list.Select(q.list.Index() + 1) }},
+		binding{pattern: up, handler: func() { q.list.Select(q.list.Index() - 1) }},
+	)
+	return q
+}
+
+// BindJump registers a key that jumps to the current entry.
+func (q *QuickfixC) BindJump(key string) *QuickfixC {
+	q.declaredBindings = append(q.declaredBindings, binding{pattern: key, handler: q.list.Jump})
+	return q
+}
+
+// BindNextPrev registers keys that move to the next or previous entry and jump.
+func (q *QuickfixC) BindNextPrev(next, prev string) *QuickfixC {
+	q.declaredBindings = append(q.declaredBindings,
+		binding{pattern: next, handler: func() { q.list.Next() }},
+		binding{pattern: prev, handler: func() { q.list.Prev() }},
+	)
+	return q
+}
+
+// BindFileNav registers keys that jump to the next or previous file.
+func (q *QuickfixC) BindFileNav(next, prev string) *QuickfixC {
+	q.declaredBindings = append(q.declaredBindings,
+		binding{pattern: next, handler: func() { q.list.NextFile() }},
+		binding{pattern: prev, handler: func() { q.list.PrevFile() }},
+	)
+	return q
+}
+
+func (q *QuickfixC) bindings() []binding { return q.declaredBindings }
+
+func (q *QuickfixC) updateHook() *func() { return &q.list.onUpdate }
+
+func (q *QuickfixC) sync() {
+	w, h := q.layer.ViewportWidth(), q.layer.ViewportHeight()
+	if w <= 0 || h <= 0 {
+		return
+	}
+	current := q.list.Index()
+
+	// flatten groups into rows: a header per file, then its entries
+	type row struct {
+		header string
+		entry  *QuickfixEntry
+		index  int
+	}
+	var rows []row
+	selRow := 0
+	for _, g := range q.list.Groups() {
+		rows = append(rows, row{header: fmt.Sprintf("%s (%d)", g.File, len(g.Entries))})
+		for i := range g.Entries {
+			if g.Start+i == current {
+				selRow = len(rows)
+			}
+			rows = append(rows, row{entry: &g.Entries[i], index: g.Start + i})
+		}
+	}
+
+	if selRow-1 < q.top {
+		q.top = max(selRow-1, 0) // keep the file header visible too
+	} else if selRow >= q.top+h {
+		q.top = selRow - h + 1
+	}
+
+	out := NewBuffer(w, h)
+	for y := 0; y < h && q.top+y < len(rows); y++ {
+		r := rows[q.top+y]
+		if r.entry == nil {
+			out.WriteStringFast(0, y, r.header, q.fileStyle, w)
+			continue
+		}
+		e := r.entry
+		pos := fmt.Sprintf("  %d", e.Line)
+		if e.Col > 0 {
+			pos += fmt.Sprintf(":%d", e.Col)
+		}
+		spans := []Span{{Text: pos, Style: q.posStyle}, {Text: " "}}
+		switch e.Kind {
+		case "error":
+			spans = append(spans, Span{Text: "error ", Style: q.errorStyle})
+		case "warning":
+			spans = append(spans, Span{Text: "warning ", Style: q.warningStyle})
+		case "":
+		default:
+			spans = append(spans, Span{Text: e.Kind + " ", Style: Style{Attr: AttrDim}})
+		}
+		spans = append(spans, Span{Text: expandTabs(e.Message)})
+		out.WriteSpans(0, y, spans, w)
+		if r.index == current {
+			highlightRow(out, y, w, q.selectedStyle)
+		}
+	}
+	q.layer.SetBuffer(out)
+}
+
+func (t *Template) compileQuickfixC(v *QuickfixC, parent int16, depth int) int16 {
+	layerView := LayerView(v.layer).Grow(v.grow)
+	if v.height > 0 {
+		layerView = layerView.ViewHeight(v.height)
+	}
+	if v.margin != [4]int16{} {
+		layerView = layerView.MarginTRBL(v.margin[0], v.margin[1], v.margin[2], v.margin[3])
+	}
+	return t.compileLayerViewC(layerView, parent, depth)
+}
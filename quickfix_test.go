@@ -0,0 +1,132 @@
+package glyph
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestQuickfixAppendGroupsByFile(t *testing.T) {
+	q := NewQuickfixList()
+	q.Append(
+		QuickfixEntry{File: "a.go", Line: 1},
+		QuickfixEntry{File: "b.go", Line: 5},
+		QuickfixEntry{File: "a.go", Line: 9},
+	)
+	var got []string
+	for _, e := range q.Entries() {
+		got = append(got, e.File)
+	}
+	if strings.Join(got, ",") != "a.go,a.go,b.go" {
+		t.Errorf("expected entries grouped by file, got %v", got)
+	}
+	groups := q.Groups()
+	if len(groups) != 2 || groups[1].Start != 2 || len(groups[0].Entries) != 2 {
+		t.Errorf("unexpected groups %+v", groups)
+	}
+}
+
+func TestQuickfixNavigation(t *testing.T) {
+	var jumps []int
+	q := NewQuickfixList().OnJump(func(e QuickfixEntry) { jumps = append(jumps, e.Line) })
+	q.Append(
+		QuickfixEntry{File: "a.go", Line: 1},
+		QuickfixEntry{File: "a.go", Line: 2},
+		QuickfixEntry{File: "b.go", Line: 3},
+		QuickfixEntry{File: "c.go", Line: 4},
+	)
+
+	q.Next()
+	q.NextFile()
+	q.NextFile()
+	if q.NextFile() {
+		t.Error("expected no file after the last")
+	}
+	q.PrevFile()
+	q.Prev()
+	if q.Prev(); q.Index() != 0 {
+		t.Errorf("expected to be back at the start, got %d", q.Index())
+	}
+	if q.Prev() {
+		t.Error("expected no entry before the first")
+	}
+	want := []int{2, 3, 4, 3, 2, 1}
+	if len(jumps) != len(want) {
+		t.Fatalf("jumps %v, want %v", jumps, want)
+	}
+	for i := range want {
+		if jumps[i] != want[i] {
+			t.Fatalf("jumps %v, want %v", jumps, want)
+		}
+	}
+}
+
+func TestQuickfixAppendKeepsCurrent(t *testing.T) {
+	q := NewQuickfixList()
+	q.Append(QuickfixEntry{File: "a.go", Line: 1}, QuickfixEntry{File: "b.go", Line: 2})
+	q.Select(1)
+	q.Append(QuickfixEntry{File: "a.go", Line: 7}) // inserted before the current entry
+	if e, _ := q.Current(); e.File != "b.go" {
+		t.Errorf("current entry should not change, got %+v", e)
+	}
+}
+
+func TestParseQuickfix(t *testing.T) {
+	tests := []struct {
+		line string
+		want QuickfixEntry
+		ok   bool
+	}{
+		{"main.go:12:5: undefined: x", QuickfixEntry{File: "main.go", Line: 12, Col: 5, Message: "undefined: x"}, true},
+		{"src/x.c:3: warning: unused", QuickfixEntry{File: "src/x.c", Line: 3, Message: "unused", Kind: "warning"}, true},
+		{"# github.com/foo/bar", QuickfixEntry{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseQuickfix(tt.line)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseQuickfix(%q) = %+v, %v; want %+v, %v", tt.line, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	q := NewQuickfixList()
+	q.ReadFrom(strings.NewReader("# pkg\na.go:1:2: bad\nb.go:3:4: worse\n"))
+	if q.Len() != 2 {
+		t.Errorf("expected 2 entries, got %d", q.Len())
+	}
+}
+
+func TestQuickfixComponentRenders(t *testing.T) {
+	q := NewQuickfixList()
+	q.Append(
+		QuickfixEntry{File: "a.go", Line: 3, Col: 1, Message: "boom", Kind: "error"},
+		QuickfixEntry{File: "b.go", Line: 7, Message: "hmm"},
+	)
+	q.Select(1)
+
+	tmpl := Build(VBox(Quickfix(q).Height(4)))
+	buf := NewBuffer(30, 4)
+	tmpl.Execute(buf, 30, 4)
+
+	want := []string{"a.go (1)", "  3:1 error boom", "b.go (1)", "  7 hmm"}
+	for y, w := range want {
+		if got := strings.TrimRight(buf.GetLine(y), " "); got != w {
+			t.Errorf("line %d: got %q, want %q", y, got, w)
+		}
+	}
+	if c := buf.Get(0, 3); c.Style.BG != PaletteColor(237) {
+		t.Error("expected current entry highlighted")
+	}
+}
+
+func TestGrepRunnerIntoQuickfix(t *testing.T) {
+	dir := writeGrepTree(t)
+	q := NewQuickfixList()
+	r := NewGrepRunner(nil).Into(q)
+	r.Run("TODO", dir)
+	r.Wait()
+	if q.Len() != 2 {
+		t.Fatalf("expected matches in quickfix list, got %d", q.Len())
+	}
+	if e := q.Entries()[1]; e.Message != "// TODO: two" || e.Col != 5 {
+		t.Errorf("unexpected entry %+v", e)
+	}
+}
@@ -0,0 +1,163 @@
+package glyph
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Multiplexer identifies a terminal multiplexer or remote shell sitting
+// between the app and the real terminal.
+type Multiplexer uint8
+
+const (
+	MuxNone Multiplexer = iota
+	MuxTmux
+	MuxScreen
+	MuxMosh
+)
+
+func (m Multiplexer) String() string {
+	switch m {
+	case MuxTmux:
+		return "tmux"
+	case MuxScreen:
+		return "screen"
+	case MuxMosh:
+		return "mosh"
+	}
+	return "none"
+}
+
+// Quirks describes what gets lost on the way to the real terminal. Screen
+// adjusts the sequences it emits to match: RGB colours fall back to the
+// nearest 256-colour index, unsupported cursor sequences are dropped, and
+// sequences a multiplexer would swallow are wrapped for passthrough. The
+// zero value is a plain terminal with no quirks.
+type Quirks struct {
+	Mux           Multiplexer
+	NoTrueColor   bool // 24-bit colour doesn't reach the terminal
+	NoCursorShape bool // DECSCUSR cursor shapes are dropped
+	NoCursorColor bool // OSC 12 cursor colour is dropped
+	Passthrough   bool // cursor sequences need DCS passthrough (GNU screen)
+}
+
+// DetectQuirks inspects the environment and process ancestry.
+func DetectQuirks() Quirks {
+	return detectQuirks(os.Getenv, ancestorNames(8))
+}
+
+func detectQuirks(getenv func(string) string, ancestors []string) Quirks {
+	colorterm := strings.ToLower(getenv("COLORTERM"))
+	truecolor := colorterm == "truecolor" || colorterm == "24bit"
+	term := getenv("TERM")
+
+	switch {
+	case getenv("TMUX") != "":
+		// tmux only forwards RGB when its outer terminal has Tc/RGB, which
+		// users signal by exporting COLORTERM inside it
+		return Quirks{Mux: MuxTmux, NoTrueColor: !truecolor}
+	case getenv("STY") != "" || strings.HasPrefix(term, "screen"):
+		return Quirks{Mux: MuxScreen, NoTrueColor: true, Passthrough: true}
+	}
+	for _, name := range ancestors {
+		if name == "mosh-server" {
+			return Quirks{Mux: MuxMosh, NoTrueColor: !truecolor, NoCursorShape: true, NoCursorColor: true}
+		}
+	}
+	return Quirks{}
+}
+
+// Wrap prepares an escape sequence (OSC, DCS or CSI) for the multiplexer
+// in use so it reaches the outer terminal: tmux and screen need DCS
+// passthrough, with tmux doubling every ESC inside it. Sequences the
+// multiplexer handles itself should be written unwrapped.
+func (q Quirks) Wrap(seq string) string {
+	switch q.Mux {
+	case MuxTmux:
+		return "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+	case MuxScreen:
+		return "\x1bP" + seq + "\x1b\\"
+	}
+	return seq
+}
+
+// Warnings explains degraded features and how to restore them.
+func (q Quirks) Warnings() []string {
+	var w []string
+	switch q.Mux {
+	case MuxTmux:
+		if q.NoTrueColor {
+			w = append(w, "tmux: 24-bit colour downgraded to 256; add `set -as terminal-features ',*:RGB'` to tmux.conf and export COLORTERM=truecolor")
+		}
+		w = append(w, "tmux: wrapped sequences need `set -g allow-passthrough on` (tmux 3.3+)")
+	case MuxScreen:
+		w = append(w, "screen: 24-bit colour downgraded to 256")
+	case MuxMosh:
+		if q.NoTrueColor {
+			w = append(w, "mosh: 24-bit colour downgraded to 256")
+		}
+		w = append(w, "mosh: cursor shape and colour are not forwarded")
+	}
+	return w
+}
+
+// ancestorNames returns the command names of up to n parent processes.
+// It relies on /proc and returns nil where that isn't available.
+func ancestorNames(n int) []string {
+	var names []string
+	pid := os.Getppid()
+	for i := 0; i < n && pid > 1; i++ {
+		stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+		if err != nil {
+			break
+		}
+		// pid (comm) state ppid ...
+		s := string(stat)
+		open, close := strings.IndexByte(s, '('), strings.LastIndexByte(s, ')')
+		if open < 0 || close < open {
+			break
+		}
+		names = append(names, s[open+1:close])
+		var ppid int
+		if _, err := fmt.Sscanf(s[close+1:], " %c %d", new(byte), &ppid); err != nil {
+			break
+		}
+		pid = ppid
+	}
+	return names
+}
+
+// rgbTo256 returns the closest xterm 256-colour index to an RGB colour,
+// choosing between the 6x6x6 cube and the grey ramp.
+func rgbTo256(r, g, b uint8) uint8 {
+	cube := func(v uint8) uint8 {
+		switch {
+		case v < 48:
+			return 0
+		case v < 115:
+			return 1
+		}
+		return (v - 35) / 40
+	}
+	cr, cg, cb := cube(r), cube(g), cube(b)
+	cubeIdx := 16 + 36*cr + 6*cg + cb
+
+	avg := (int(r) + int(g) + int(b)) / 3
+	grayIdx := uint8(232)
+	if avg > 238 {
+		grayIdx = 255
+	} else if avg > 8 {
+		grayIdx = 232 + uint8((avg-8)/10)
+	}
+
+	dist := func(idx uint8) int {
+		pr, pg, pb := palette256(idx)
+		dr, dg, db := int(r)-int(pr), int(g)-int(pg), int(b)-int(pb)
+		return dr*dr + dg*dg + db*db
+	}
+	if dist(grayIdx) < dist(cubeIdx) {
+		return grayIdx
+	}
+	return cubeIdx
+}
@@ -0,0 +1,88 @@
+package glyph
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectQuirks(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       map[string]string
+		ancestors []string
+		want      Quirks
+	}{
+		{"plain", map[string]string{"TERM": "xterm-256color"}, nil, Quirks{}},
+		{"tmux", map[string]string{"TMUX": "/tmp/tmux-0/default,1,0", "TERM": "tmux-256color"}, nil,
+			Quirks{Mux: MuxTmux, NoTrueColor: true}},
+		{"tmux truecolor", map[string]string{"TMUX": "x", "COLORTERM": "truecolor"}, nil,
+			Quirks{Mux: MuxTmux}},
+		{"screen", map[string]string{"STY": "1234.pts-0", "TERM": "screen"}, nil,
+			Quirks{Mux: MuxScreen, NoTrueColor: true, Passthrough: true}},
+		{"mosh", map[string]string{"TERM": "xterm-256color"}, []string{"bash", "mosh-server"},
+			Quirks{Mux: MuxMosh, NoTrueColor: true, NoCursorShape: true, NoCursorColor: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := detectQuirks(func(k string) string { return tt.env[k] }, tt.ancestors)
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuirksWrap(t *testing.T) {
+	seq := "\x1b]12;#ff0000\x07"
+	if got := (Quirks{}).Wrap(seq); got != seq {
+		t.Errorf("plain wrap = %q", got)
+	}
+	if got, want := (Quirks{Mux: MuxTmux}).Wrap(seq), "\x1bPtmux;\x1b\x1b]12;#ff0000\x07\x1b\\"; got != want {
+		t.Errorf("tmux wrap = %q, want %q", got, want)
+	}
+	if got, want := (Quirks{Mux: MuxScreen}).Wrap(seq), "\x1bP"+seq+"\x1b\\"; got != want {
+		t.Errorf("screen wrap = %q, want %q", got, want)
+	}
+}
+
+func TestRGBTo256(t *testing.T) {
+	tests := []struct {
+		r, g, b uint8
+		want    uint8
+	}{
+		{255, 0, 0, 196},
+		{0, 0, 0, 16},
+		{128, 128, 128, 244},
+		{95, 135, 175, 67},
+	}
+	for _, tt := range tests {
+		if got := rgbTo256(tt.r, tt.g, tt.b); got != tt.want {
+			t.Errorf("rgbTo256(%d,%d,%d) = %d, want %d", tt.r, tt.g, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestScreenQuirks(t *testing.T) {
+	s, _ := newTestScreen(10, 2)
+	s.SetQuirks(Quirks{Mux: MuxScreen, NoTrueColor: true, Passthrough: true})
+
+	s.buf.Reset()
+	s.writeColor(&s.buf, RGB(255, 0, 0), true)
+	if got := s.buf.String(); got != ";38;5;196" {
+		t.Errorf("downgraded colour = %q", got)
+	}
+
+	s.buf.Reset()
+	s.BufferCursor(0, 0, true, CursorBar)
+	if !strings.HasPrefix(s.buf.String(), "\x1bP\x1b[6 q\x1b\\") {
+		t.Errorf("cursor shape not wrapped: %q", s.buf.String())
+	}
+
+	s.SetQuirks(Quirks{Mux: MuxMosh, NoCursorShape: true, NoCursorColor: true})
+	s.buf.Reset()
+	s.BufferCursorColor(RGB(1, 2, 3))
+	s.BufferCursor(0, 0, true, CursorBar)
+	if strings.Contains(s.buf.String(), " q") || strings.Contains(s.buf.String(), "]12;") {
+		t.Errorf("unsupported sequences emitted: %q", s.buf.String())
+	}
+}
@@ -0,0 +1,81 @@
+package glyph
+
+import "strings"
+
+// RenderString lays out and renders view at width columns, as tall as its
+// content, and returns it as text with ANSI styling, for printing
+// components such as tables and trees from ordinary commands without an
+// App or a terminal:
+//
+//	fmt.Println(glyph.RenderString(VBox.Border(BorderRounded).Title("Summary")(
+//	    Leader("files", &files),
+//	    Leader("errors", &errs),
+//	), 40))
+//
+// Lines end at their last visible cell and are separated by newlines, with
+// none at the end. Components that grow have nothing to grow into.
+func RenderString(view any, width int) string {
+	return renderOnce(view, width, true)
+}
+
+// RenderText is RenderString without the styling, for output to files,
+// pipes or terminals that shouldn't get colour.
+func RenderText(view any, width int) string {
+	return renderOnce(view, width, false)
+}
+
+// renderOnce renders view into a buffer just tall enough for it and
+// returns its lines.
+func renderOnce(view any, width int, styled bool) string {
+	if width <= 0 {
+		return ""
+	}
+	tmpl := Build(view)
+	h := tmpl.naturalHeight(int16(width))
+	if h <= 0 {
+		return ""
+	}
+	buf := NewBuffer(width, int(h))
+	tmpl.Execute(buf, int16(width), h)
+	tmpl.unmountAll()
+	lines := make([]string, h)
+	for y := range lines {
+		lines[y] = buf.printedLine(y, styled)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// printedLine returns row y up to its last cell that shows: one with a
+// character, a background or an attribute visible on a space. Wide runes'
+// spacer cells are left out, and styled lines carry ANSI styling.
+func (b *Buffer) printedLine(y int, styled bool) string {
+	end := 0
+	for x := 0; x < b.width; x++ {
+		c := b.Get(x, y)
+		if c.Rune != ' ' && c.Rune != 0 || c.Style.BG.Mode != ColorDefault ||
+			c.Style.Attr.Has(AttrUnderline) || c.Style.Attr.Has(AttrInverse) {
+			end = x + 1
+		}
+	}
+	var sb strings.Builder
+	var last Style
+	for x := 0; x < end; x++ {
+		c := b.Get(x, y)
+		if c.Rune == 0 && x > 0 {
+			continue // the spacer after a wide rune
+		}
+		if styled && !c.Style.Equal(last) {
+			sb.WriteString(b.styleToANSI(c.Style))
+			last = c.Style
+		}
+		r := c.Rune
+		if r == 0 {
+			r = ' '
+		}
+		sb.WriteRune(r)
+	}
+	if !last.Equal(Style{}) {
+		sb.WriteString("\x1b[0m")
+	}
+	return sb.String()
+}
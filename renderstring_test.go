@@ -0,0 +1,33 @@
+package glyph
+
+import "testing"
+
+func TestRenderString(t *testing.T) {
+	files, errs := "12", "0"
+	view := VBox.Border(BorderRounded).Title("Summary")(
+		Leader("files", &files),
+		Leader("errors", &errs),
+		Space(), // nothing to grow into
+	)
+	want := "╭─ Summary ─────╮\n" +
+		"│files........12│\n" +
+		"│errors........0│\n" +
+		"╰───────────────╯"
+	if got := RenderText(view, 17); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	got := RenderString(HBox(Text("ok").FG(Green).Bold(), Text(" 🚀 done")), 20)
+	if want := "\x1b[0;1;32;49mok\x1b[0;39;49m 🚀 done"; got != want {
+		t.Errorf("styled %q, want %q", got, want)
+	}
+	if got := RenderText(Text("x").BG(Blue), 5); got != "x" {
+		t.Errorf("trailing spaces %q", got)
+	}
+	if got := RenderString(Text("hi").Inverse(), 10); got != "\x1b[0;7;39;49mhi\x1b[0m" {
+		t.Errorf("reset %q", got)
+	}
+	if RenderString(VBox(), 10) != "" || RenderString(Text("x"), 0) != "" {
+		t.Error("empty view rendered something")
+	}
+}
@@ -0,0 +1,397 @@
+package glyph
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Replacement is one pending match and the text that would replace it.
+type Replacement struct {
+	Start, End TextPos
+	Old, New   string
+}
+
+// Replacer finds and rewrites matches in an EditBuffer, one line at a time.
+// Literal replacers match text exactly; regex replacers expand capture
+// groups ($1, ${name}) in the replacement.
+//
+//	r, _ := NewRegexReplacer(`(\w+)\.Get\(\)`, "${1}.Value()")
+//	r.Lines(10, 40).ReplaceAll(buf)
+type Replacer struct {
+	pattern string
+	repl    string
+	literal bool
+	re      *regexp.Regexp
+
+	from, to int // inclusive line range; to < 0 means the last line
+}
+
+// NewReplacer creates a literal replacer.
+func NewReplacer(find, replace string) *Replacer {
+	r := &Replacer{pattern: find, repl: replace, literal: true, to: -1}
+	r.re = regexp.MustCompile(regexp.QuoteMeta(find))
+	return r
+}
+
+// NewRegexReplacer creates a regex replacer. The replacement may reference
+// capture groups as in regexp.Expand.
+func NewRegexReplacer(pattern, replace string) (*Replacer, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return &Replacer{pattern: pattern, repl: replace, re: re, to: -1}, nil
+}
+
+// IgnoreCase makes matching case-insensitive.
+func (r *Replacer) IgnoreCase() *Replacer {
+	p := r.pattern
+	if r.literal {
+		p = regexp.QuoteMeta(p)
+	}
+	r.re = regexp.MustCompile("(?i)" + p)
+	return r
+}
+
+// Lines limits matching to lines from..to inclusive.
+func (r *Replacer) Lines(from, to int) *Replacer {
+	r.from, r.to = from, to
+	return r
+}
+
+// Find returns every pending replacement in document order.
+func (r *Replacer) Find(b *EditBuffer) []Replacement {
+	if r.literal && r.pattern == "" {
+		return nil
+	}
+	from, to := max(r.from, 0), r.to
+	if to < 0 || to >= b.LineCount() {
+		to = b.LineCount() - 1
+	}
+	var out []Replacement
+	for i := from; i <= to; i++ {
+		line := b.Line(i)
+		for _, m := range r.re.FindAllStringSubmatchIndex(line, -1) {
+			rep := Replacement{
+				Start: TextPos{Line: i, Col: m[0]},
+				End:   TextPos{Line: i, Col: m[1]},
+				Old:   line[m[0]:m[1]],
+				New:   r.repl,
+			}
+			if !r.literal {
+				rep.New = string(r.re.ExpandString(nil, r.repl, line, m))
+			}
+			out = append(out, rep)
+		}
+	}
+	return out
+}
+
+// ReplaceAll replaces every match as a single undo step and returns the
+// number of replacements made.
+func (r *Replacer) ReplaceAll(b *EditBuffer) int {
+	reps := r.Find(b)
+	b.ApplyReplacements(reps)
+	return len(reps)
+}
+
+// Confirm starts a confirm-each session over the current matches.
+func (r *Replacer) Confirm(b *EditBuffer) *ReplaceSession {
+	s := &ReplaceSession{buf: b, pending: r.Find(b)}
+	s.focus()
+	return s
+}
+
+// ApplyReplacements performs the given replacements as one undo step.
+// reps must be in document order and must not overlap; cursors are moved
+// so they stay on the same text.
+func (b *EditBuffer) ApplyReplacements(reps []Replacement) {
+	if len(reps) == 0 {
+		return
+	}
+	b.checkpoint(editOther)
+	for i := len(reps) - 1; i >= 0; i-- {
+		rep := reps[i]
+		newEnd := b.replace(rep.Start, rep.End, rep.New)
+		for j := range b.sels {
+			b.sels[j].Anchor = shiftThrough(b.sels[j].Anchor, rep.Start, rep.End, newEnd)
+			b.sels[j].Head = shiftThrough(b.sels[j].Head, rep.Start, rep.End, newEnd)
+		}
+	}
+	b.normalize(b.sels[b.primary])
+	b.changed()
+}
+
+// shiftThrough maps p across a replacement of [start, oldEnd) that now ends
+// at newEnd. Positions inside the replaced text move to its end.
+func shiftThrough(p, start, oldEnd, newEnd TextPos) TextPos {
+	if !start.Less(p) {
+		return p
+	}
+	if p.Less(oldEnd) {
+		return newEnd
+	}
+	return shiftPos(p, oldEnd, newEnd)
+}
+
+// ReplaceSession steps through matches one at a time, for :s///c style
+// interaction. The current match is selected in the buffer so an editor
+// scrolls to it. Accepted replacements undo as a single step.
+type ReplaceSession struct {
+	buf      *EditBuffer
+	pending  []Replacement
+	idx      int
+	replaced int
+	grouped  bool
+}
+
+// Current returns the match awaiting a decision.
+func (s *ReplaceSession) Current() (Replacement, bool) {
+	if s.Done() {
+		return Replacement{}, false
+	}
+	return s.pending[s.idx], true
+}
+
+// Pending returns the matches not yet decided, starting with the current one.
+func (s *ReplaceSession) Pending() []Replacement {
+	if s.Done() {
+		return nil
+	}
+	return s.pending[s.idx:]
+}
+
+// Accept replaces the current match and moves to the next.
+func (s *ReplaceSession) Accept() {
+	if s.Done() {
+		return
+	}
+	if !s.grouped {
+		s.buf.BeginUndoGroup()
+		s.grouped = true
+	}
+	rep := s.pending[s.idx]
+	s.buf.ApplyReplacements([]Replacement{rep})
+	newEnd := s.buf.advance(rep.Start, rep.New)
+	for i := s.idx + 1; i < len(s.pending); i++ {
+		s.pending[i].Start = shiftPos(s.pending[i].Start, rep.End, newEnd)
+		s.pending[i].End = shiftPos(s.pending[i].End, rep.End, newEnd)
+	}
+	s.replaced++
+	s.next()
+}
+
+// Skip leaves the current match alone and moves to the next.
+func (s *ReplaceSession) Skip() {
+	if !s.Done() {
+		s.next()
+	}
+}
+
+// AcceptAll replaces the current match and every remaining one.
+func (s *ReplaceSession) AcceptAll() {
+	for !s.Done() {
+		s.Accept()
+	}
+}
+
+// Stop ends the session, leaving remaining matches untouched.
+func (s *ReplaceSession) Stop() {
+	s.idx = len(s.pending)
+	s.finish()
+}
+
+// Done reports whether every match has been decided.
+func (s *ReplaceSession) Done() bool {
+	return s.idx >= len(s.pending)
+}
+
+// Replaced returns the number of accepted replacements.
+func (s *ReplaceSession) Replaced() int {
+	return s.replaced
+}
+
+func (s *ReplaceSession) next() {
+	s.idx++
+	s.focus()
+}
+
+func (s *ReplaceSession) focus() {
+	if s.Done() {
+		s.finish()
+		return
+	}
+	rep := s.pending[s.idx]
+	s.buf.SetSelections([]Selection{{Anchor: rep.Start, Head: rep.End}})
+}
+
+func (s *ReplaceSession) finish() {
+	if s.grouped {
+		s.buf.EndUndoGroup()
+		s.grouped = false
+	}
+}
+
+// ============================================================================
+// Preview
+// ============================================================================
+
+// ReplacePreviewC lists pending replacements with the old text struck
+// through next to its replacement. When a session is attached, only its
+// remaining matches are shown and the current one is marked.
+//
+//	ReplacePreview(buf, replacer).Grow(1)
+type ReplacePreviewC struct {
+	buf      *EditBuffer
+	replacer *Replacer
+	session  *ReplaceSession
+	layer    *Layer
+
+	style        Style
+	lineNoStyle  Style
+	oldStyle     Style
+	newStyle     Style
+	currentStyle Style
+
+	grow   float32
+	height int16
+	margin [4]int16
+}
+
+// ReplacePreview creates a preview of r applied to buf.
+func ReplacePreview(buf *EditBuffer, r *Replacer) *ReplacePreviewC {
+	p := &ReplacePreviewC{
+		buf:          buf,
+		replacer:     r,
+		layer:        NewLayer(),
+		lineNoStyle:  Style{Attr: AttrDim},
+		oldStyle:     Style{FG: Red, Attr: AttrStrikethrough},
+		newStyle:     Style{FG: Green},
+		currentStyle: Style{FG: Yellow, Attr: AttrBold},
+	}
+	p.layer.AlwaysRender = true
+	p.layer.Render = p.sync
+	return p
+}
+
+// Ref provides access to the component for external references.
+func (p *ReplacePreviewC) Ref(f func(*ReplacePreviewC)) *ReplacePreviewC { f(p); return p }
+
+// Layer returns the underlying layer for external scroll wiring.
+func (p *ReplacePreviewC) Layer() *Layer { return p.layer }
+
+// SetReplacer swaps the replacer, e.g. as the user edits the pattern.
+func (p *ReplacePreviewC) SetReplacer(r *Replacer) {
+	p.replacer = r
+}
+
+// Session shows the remaining matches of s instead of the replacer's.
+func (p *ReplacePreviewC) Session(s *ReplaceSession) *ReplacePreviewC {
+	p.session = s
+	return p
+}
+
+// Style sets the style for unchanged text.
+func (p *ReplacePreviewC) Style(s Style) *ReplacePreviewC {
+	p.style = s
+	return p
+}
+
+// OldStyle sets the style for text being replaced.
+func (p *ReplacePreviewC) OldStyle(s Style) *ReplacePreviewC {
+	p.oldStyle = s
+	return p
+}
+
+// CurrentStyle sets the line number style of the session's current match.
+func (p *ReplacePreviewC) CurrentStyle(s Style) *ReplacePreviewC {
+	p.currentStyle = s
+	return p
+}
+
+// NewStyle sets the style for replacement text.
+func (p *ReplacePreviewC) NewStyle(s Style) *ReplacePreviewC {
+	p.newStyle = s
+	return p
+}
+
+// Grow sets the flex grow factor.
+func (p *ReplacePreviewC) Grow(g float32) *ReplacePreviewC {
+	p.grow = g
+	return p
+}
+
+// Height sets a fixed viewport height.
+func (p *ReplacePreviewC) Height(h int16) *ReplacePreviewC {
+	p.height = h
+	return p
+}
+
+// Margin sets uniform margin on all sides.
+func (p *ReplacePreviewC) Margin(all int16) *ReplacePreviewC {
+	p.margin = [4]int16{all, all, all, all}
+	return p
+}
+
+// MarginVH sets vertical and horizontal margin.
+func (p *ReplacePreviewC) MarginVH(v, h int16) *ReplacePreviewC {
+	p.margin = [4]int16{v, h, v, h}
+	return p
+}
+
+// MarginTRBL sets individual margins for top, right, bottom, left.
+func (p *ReplacePreviewC) MarginTRBL(t, r, b, l int16) *ReplacePreviewC {
+	p.margin = [4]int16{t, r, b, l}
+	return p
+}
+
+// Replacements returns what the preview currently shows.
+func (p *ReplacePreviewC) Replacements() []Replacement {
+	switch {
+	case p.session != nil:
+		return p.session.Pending()
+	case p.replacer != nil:
+		return p.replacer.Find(p.buf)
+	}
+	return nil
+}
+
+func (p *ReplacePreviewC) sync() {
+	w := p.layer.ViewportWidth()
+	h := p.layer.ViewportHeight()
+	if w <= 0 {
+		return
+	}
+	reps := p.Replacements()
+	out := NewBuffer(w, max(len(reps), h, 1))
+	for y, rep := range reps {
+		line := p.buf.Line(rep.Start.Line)
+		lineNo := p.lineNoStyle
+		if p.session != nil && y == 0 {
+			lineNo = p.currentStyle
+		}
+		spans := []Span{
+			{Text: fmt.Sprintf("%4d: ", rep.Start.Line+1), Style: lineNo},
+			{Text: line[:rep.Start.Col], Style: p.style},
+			{Text: rep.Old, Style: p.oldStyle},
+			{Text: strings.ReplaceAll(rep.New, "\n", "↵"), Style: p.newStyle},
+			{Text: line[rep.End.Col:], Style: p.style},
+		}
+		out.WriteSpans(0, y, spans, w)
+	}
+	scrollY := p.layer.ScrollY()
+	p.layer.SetBuffer(out)
+	p.layer.ScrollTo(scrollY)
+}
+
+func (t *Template) compileReplacePreviewC(v *ReplacePreviewC, parent int16, depth int) int16 {
+	layerView := LayerView(v.layer).Grow(v.grow)
+	if v.height > 0 {
+		layerView = layerView.ViewHeight(v.height)
+	}
+	if v.margin != [4]int16{} {
+		layerView = layerView.MarginTRBL(v.margin[0], v.margin[1], v.margin[2], v.margin[3])
+	}
+	return t.compileLayerViewC(layerView, parent, depth)
+}
@@ -0,0 +1,95 @@
+package glyph
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReplacerLiteral(t *testing.T) {
+	b := NewEditBuffer("a.b a.b\naxb")
+	n := NewReplacer("a.b", "c").ReplaceAll(b)
+	if n != 2 {
+		t.Errorf("expected 2 replacements, got %d", n)
+	}
+	if got := b.Text(); got != "c c\naxb" {
+		t.Errorf("literal pattern should not match as regex, got %q", got)
+	}
+	b.Undo()
+	if got := b.Text(); got != "a.b a.b\naxb" {
+		t.Errorf("expected single undo step, got %q", got)
+	}
+}
+
+func TestReplacerRegexCaptures(t *testing.T) {
+	b := NewEditBuffer("x.Get()\ny.Get()")
+	r, err := NewRegexReplacer(`(\w+)\.Get\(\)`, "${1}.Value()")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.ReplaceAll(b)
+	if got := b.Text(); got != "x.Value()\ny.Value()" {
+		t.Errorf("unexpected text %q", got)
+	}
+
+	if _, err := NewRegexReplacer("(", ""); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+}
+
+func TestReplacerLinesAndIgnoreCase(t *testing.T) {
+	b := NewEditBuffer("Foo\nfoo\nFOO")
+	NewReplacer("foo", "bar").IgnoreCase().Lines(1, 2).ReplaceAll(b)
+	if got := b.Text(); got != "Foo\nbar\nbar" {
+		t.Errorf("unexpected text %q", got)
+	}
+}
+
+func TestReplacerKeepsCursorOnText(t *testing.T) {
+	b := NewEditBuffer("aa tail")
+	b.SetCursor(TextPos{Col: 3})
+	NewReplacer("a", "bbb").ReplaceAll(b)
+	if b.Cursor() != (TextPos{Col: 7}) {
+		t.Errorf("cursor should stay before 'tail', got %+v", b.Cursor())
+	}
+}
+
+func TestReplaceSessionConfirmEach(t *testing.T) {
+	b := NewEditBuffer("x x x")
+	s := NewReplacer("x", "yy").Confirm(b)
+
+	if cur, ok := s.Current(); !ok || cur.Start.Col != 0 {
+		t.Fatalf("expected first match current, got %+v", cur)
+	}
+	if got := b.SelectedText(); got[0] != "x" {
+		t.Errorf("current match should be selected, got %v", got)
+	}
+
+	s.Accept()
+	s.Skip()
+	s.Accept()
+	if !s.Done() || s.Replaced() != 2 {
+		t.Errorf("expected done with 2 replacements, got done=%v n=%d", s.Done(), s.Replaced())
+	}
+	if got := b.Text(); got != "yy x yy" {
+		t.Errorf("unexpected text %q", got)
+	}
+
+	b.Undo()
+	if got := b.Text(); got != "x x x" {
+		t.Errorf("accepted replacements should undo together, got %q", got)
+	}
+}
+
+func TestReplacePreviewRenders(t *testing.T) {
+	b := NewEditBuffer("keep\nold value")
+	tmpl := Build(VBox(ReplacePreview(b, NewReplacer("old", "new")).Height(2)))
+	buf := NewBuffer(30, 2)
+	tmpl.Execute(buf, 30, 2)
+
+	if got := buf.GetLine(0); !strings.HasPrefix(got, "   2: oldnew value") {
+		t.Errorf("unexpected preview line %q", got)
+	}
+	if c := buf.Get(6, 0); !c.Style.Attr.Has(AttrStrikethrough) {
+		t.Error("old text should be struck through")
+	}
+}
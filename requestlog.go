@@ -0,0 +1,705 @@
+package glyph
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+// RequestEntry is one recorded request and its response.
+type RequestEntry struct {
+	ID             int
+	Method         string
+	URL            string
+	Start          time.Time
+	Duration       time.Duration // until the response was read; 0 while pending
+	Status         int           // 0 until the response arrives
+	RequestHeader  http.Header
+	ResponseHeader http.Header
+	RequestBody    []byte // up to the log's body limit
+	ResponseBody   []byte
+	Truncated      bool  // a body was longer than the limit
+	Err            error // the request failed
+	Done           bool
+}
+
+// RequestLog is a shared, thread-safe record of HTTP requests with a
+// current entry, for inspecting traffic while building against an API.
+// Like QuickfixList it holds no view state, so a RequestList and a
+// RequestDetail can share one.
+//
+//	reqs := NewRequestLog()
+//	client := &http.Client{Transport: reqs.Transport(nil)}
+//	HBox(RequestList(reqs).BindNav("j", "k").Grow(1), RequestDetail(reqs).Grow(2))
+//
+// Requests come in through Transport for a client, Middleware for a
+// server, or Begin and End for anything else. The newest entries are kept,
+// 500 by default.
+type RequestLog struct {
+	mu       sync.Mutex
+	entries  []RequestEntry
+	current  int
+	lastID   int
+	limit    int
+	maxBody  int
+	onUpdate func() // called when entries change (for RequestRender)
+}
+
+// NewRequestLog creates an empty log keeping 500 entries and 64KiB of
+// each body.
+func NewRequestLog() *RequestLog {
+	return &RequestLog{limit: 500, maxBody: 64 << 10}
+}
+
+// Limit sets how many entries are kept; older ones are dropped.
+func (l *RequestLog) Limit(n int) *RequestLog {
+	l.limit = max(n, 1)
+	return l
+}
+
+// MaxBody sets how many bytes of each body are kept.
+func (l *RequestLog) MaxBody(n int) *RequestLog {
+	l.maxBody = n
+	return l
+}
+
+// OnUpdate sets a callback fired whenever entries change. Request views
+// wire this to app.RequestRender automatically.
+func (l *RequestLog) OnUpdate(fn func()) *RequestLog {
+	l.onUpdate = fn
+	return l
+}
+
+// Begin records the start of a request and returns its ID for End. Safe
+// to call from any goroutine.
+func (l *RequestLog) Begin(method, url string, header http.Header, body []byte) int {
+	l.mu.Lock()
+	l.lastID++
+	e := RequestEntry{
+		ID:            l.lastID,
+		Method:        method,
+		URL:           url,
+		Start:         time.Now(),
+		RequestHeader: header.Clone(),
+	}
+	e.RequestBody, e.Truncated = l.clip(body)
+	l.add(e)
+	l.mu.Unlock()
+	l.notify()
+	return e.ID
+}
+
+// End records the response to the request Begin returned id for, or the
+// error it failed with. Safe to call from any goroutine.
+func (l *RequestLog) End(id, status int, header http.Header, body []byte, err error) {
+	l.mu.Lock()
+	i := l.find(id)
+	if i < 0 {
+		l.mu.Unlock()
+		return
+	}
+	e := &l.entries[i]
+	e.Duration = time.Since(e.Start)
+	e.Status, e.ResponseHeader, e.Err, e.Done = status, header.Clone(), err, true
+	var cut bool
+	e.ResponseBody, cut = l.clip(body)
+	e.Truncated = e.Truncated || cut
+	l.mu.Unlock()
+	l.notify()
+}
+
+// Add records a finished request, as from a log or another transport.
+// Its ID is assigned.
+func (l *RequestLog) Add(e RequestEntry) {
+	l.mu.Lock()
+	l.lastID++
+	e.ID, e.Done = l.lastID, true
+	l.add(e)
+	l.mu.Unlock()
+	l.notify()
+}
+
+// add appends e, dropping the oldest entry past the limit. The current
+// entry follows new ones while it's the newest.
+func (l *RequestLog) add(e RequestEntry) {
+	follow := l.current >= len(l.entries)-1
+	l.entries = append(l.entries, e)
+	if over := len(l.entries) - l.limit; over > 0 {
+		l.entries = slices.Delete(l.entries, 0, over)
+		l.current = max(l.current-over, 0)
+	}
+	if follow {
+		l.current = len(l.entries) - 1
+	}
+}
+
+func (l *RequestLog) find(id int) int {
+	for i := len(l.entries) - 1; i >= 0; i-- {
+		if l.entries[i].ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// clip copies up to maxBody bytes of body.
+func (l *RequestLog) clip(body []byte) ([]byte, bool) {
+	if len(body) > l.maxBody {
+		return slices.Clone(body[:l.maxBody]), true
+	}
+	return slices.Clone(body), false
+}
+
+// Clear removes every entry.
+func (l *RequestLog) Clear() {
+	l.mu.Lock()
+	l.entries = nil
+	l.current = 0
+	l.mu.Unlock()
+	l.notify()
+}
+
+// Len returns the number of entries.
+func (l *RequestLog) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.entries)
+}
+
+// Entries returns a copy of all entries, oldest first.
+func (l *RequestLog) Entries() []RequestEntry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return slices.Clone(l.entries)
+}
+
+// Index returns the index of the current entry.
+func (l *RequestLog) Index() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.current
+}
+
+// Current returns the current entry.
+func (l *RequestLog) Current() (RequestEntry, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.current >= len(l.entries) {
+		return RequestEntry{}, false
+	}
+	return l.entries[l.current], true
+}
+
+// Select makes entry i current. Selecting the newest entry follows new
+// requests as they arrive.
+func (l *RequestLog) Select(i int) {
+	l.mu.Lock()
+	l.current = max(min(i, len(l.entries)-1), 0)
+	l.mu.Unlock()
+}
+
+func (l *RequestLog) notify() {
+	if l.onUpdate != nil {
+		l.onUpdate()
+	}
+}
+
+// Transport returns a RoundTripper that records each request through next,
+// or http.DefaultTransport if next is nil. Request bodies are recorded when
+// the request can replay them (see http.Request.GetBody), as requests made
+// from bytes or strings can; response bodies as they are read, the entry
+// ending when the body is read to the end or closed.
+func (l *RequestLog) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return recordingTransport{log: l, next: next}
+}
+
+type recordingTransport struct {
+	log  *RequestLog
+	next http.RoundTripper
+}
+
+func (t recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.GetBody != nil {
+		if rc, err := req.GetBody(); err == nil {
+			body, _ = io.ReadAll(io.LimitReader(rc, int64(t.log.maxBody)+1))
+			rc.Close()
+		}
+	}
+	id := t.log.Begin(req.Method, req.URL.String(), req.Header, body)
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		t.log.End(id, 0, nil, nil, err)
+		return nil, err
+	}
+	resp.Body = &recordingBody{ReadCloser: resp.Body, max: t.log.maxBody, end: func(b []byte, err error) {
+		t.log.End(id, resp.StatusCode, resp.Header, b, err)
+	}}
+	return resp, nil
+}
+
+// recordingBody keeps what is read of a body, up to max bytes, and ends
+// its entry at EOF, an error or Close.
+type recordingBody struct {
+	io.ReadCloser
+	buf   bytes.Buffer
+	max   int
+	end   func([]byte, error)
+	ended bool
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if room := b.max + 1 - b.buf.Len(); room > 0 {
+		b.buf.Write(p[:min(n, room)])
+	}
+	if err == io.EOF {
+		b.finish(nil)
+	} else if err != nil {
+		b.finish(err)
+	}
+	return n, err
+}
+
+func (b *recordingBody) Close() error {
+	b.finish(nil)
+	return b.ReadCloser.Close()
+}
+
+func (b *recordingBody) finish(err error) {
+	if !b.ended {
+		b.ended = true
+		b.end(b.buf.Bytes(), err)
+	}
+}
+
+// Middleware returns a handler that records each request next serves,
+// with the request body as the handler read it.
+func (l *RequestLog) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		in := &recordingBody{ReadCloser: r.Body, max: l.maxBody, end: func([]byte, error) {}}
+		r.Body = in
+		start := time.Now()
+		out := &recordingWriter{ResponseWriter: w, max: l.maxBody}
+		next.ServeHTTP(out, r)
+		if out.status == 0 {
+			out.status = http.StatusOK
+		}
+		e := RequestEntry{
+			Method:         r.Method,
+			URL:            r.URL.String(),
+			Start:          start,
+			Duration:       time.Since(start),
+			Status:         out.status,
+			RequestHeader:  r.Header.Clone(),
+			ResponseHeader: w.Header().Clone(),
+		}
+		var cutIn, cutOut bool
+		e.RequestBody, cutIn = l.clip(in.buf.Bytes())
+		e.ResponseBody, cutOut = l.clip(out.buf.Bytes())
+		e.Truncated = cutIn || cutOut
+		l.Add(e)
+	})
+}
+
+// recordingWriter keeps the status and up to max bytes of a response.
+type recordingWriter struct {
+	http.ResponseWriter
+	status int
+	buf    bytes.Buffer
+	max    int
+}
+
+func (w *recordingWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *recordingWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if room := w.max + 1 - w.buf.Len(); room > 0 {
+		w.buf.Write(p[:min(len(p), room)])
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Unwrap lets http.ResponseController reach the writer beneath.
+func (w *recordingWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
+// ============================================================================
+// Request views
+// ============================================================================
+
+// requestStatusStyle returns the style a status is shown in: green for
+// success, cyan for redirects, yellow for client errors and red for server
+// errors and failures.
+func requestStatusStyle(e *RequestEntry) Style {
+	switch {
+	case e.Err != nil || e.Status >= 500:
+		return Style{FG: Red}
+	case e.Status >= 400:
+		return Style{FG: Yellow}
+	case e.Status >= 300:
+		return Style{FG: Cyan}
+	case e.Status >= 200:
+		return Style{FG: Green}
+	}
+	return Style{Attr: AttrDim}
+}
+
+// requestStatus returns the status column of an entry.
+func requestStatus(e *RequestEntry) string {
+	switch {
+	case e.Err != nil:
+		return "ERR"
+	case !e.Done:
+		return "…"
+	}
+	return fmt.Sprint(e.Status)
+}
+
+// requestDuration formats a duration to a few significant figures.
+func requestDuration(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return fmt.Sprintf("%.2fs", d.Seconds())
+	case d >= time.Millisecond:
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	}
+	return fmt.Sprintf("%dµs", d.Microseconds())
+}
+
+// RequestListC lists a RequestLog's entries with method, status, duration
+// and URL, highlighting the current one.
+//
+//	RequestList(reqs).BindNav("j", "k").Grow(1)
+type RequestListC struct {
+	log   *RequestLog
+	layer *Layer
+	top   int
+
+	selectedStyle Style
+
+	grow   float32
+	height int16
+
+	declaredBindings []binding
+}
+
+// RequestList creates a list of log's entries.
+func RequestList(log *RequestLog) *RequestListC {
+	r := &RequestListC{
+		log:           log,
+		layer:         NewLayer(),
+		selectedStyle: Style{BG: PaletteColor(237)},
+	}
+	r.layer.AlwaysRender = true
+	r.layer.Render = r.sync
+	return r
+}
+
+// Ref provides access to the component for external references.
+func (r *RequestListC) Ref(f func(*RequestListC)) *RequestListC { f(r); return r }
+
+// Log returns the underlying log.
+func (r *RequestListC) Log() *RequestLog { return r.log }
+
+// SelectedStyle sets the style of the current entry.
+func (r *RequestListC) SelectedStyle(s Style) *RequestListC {
+	r.selectedStyle = s
+	return r
+}
+
+// Grow sets the flex grow factor.
+func (r *RequestListC) Grow(g float32) *RequestListC {
+	r.grow = g
+	return r
+}
+
+// Height sets a fixed viewport height.
+func (r *RequestListC) Height(h int16) *RequestListC {
+	r.height = h
+	return r
+}
+
+// BindNav registers keys that move the current entry. A count moves that
+// many.
+func (r *RequestListC) BindNav(down, up string) *RequestListC {
+	r.declaredBindings = append(r.declaredBindings,
+		binding{pattern: down, handler: func(m riffkey.Match) { r.log.Select(r.log.Index() + m.Count) }},
+		binding{pattern: up, handler: func(m riffkey.Match) { r.log.Select(r.log.Index() - m.Count) }},
+	)
+	return r
+}
+
+// BindEnds registers keys that move to the oldest and newest entries.
+func (r *RequestListC) BindEnds(first, last string) *RequestListC {
+	r.declaredBindings = append(r.declaredBindings,
+		binding{pattern: first, handler: func() { r.log.Select(0) }},
+		binding{pattern: last, handler: func() { r.log.Select(r.log.Len() - 1) }},
+	)
+	return r
+}
+
+// BindClear registers a key that clears the log.
+func (r *RequestListC) BindClear(key string) *RequestListC {
+	r.declaredBindings = append(r.declaredBindings, binding{pattern: key, handler: r.log.Clear})
+	return r
+}
+
+func (r *RequestListC) bindings() []binding { return r.declaredBindings }
+
+func (r *RequestListC) updateHook() *func() { return &r.log.onUpdate }
+
+func (r *RequestListC) sync() {
+	w, h := r.layer.ViewportWidth(), r.layer.ViewportHeight()
+	if w <= 0 || h <= 0 {
+		return
+	}
+	entries := r.log.Entries()
+	current := r.log.Index()
+	if current < r.top {
+		r.top = current
+	} else if current >= r.top+h {
+		r.top = current - h + 1
+	}
+	r.top = max(min(r.top, len(entries)-h), 0)
+
+	methodW := 4
+	for _, e := range entries {
+		methodW = max(methodW, len(e.Method))
+	}
+	out := NewBuffer(w, h)
+	if len(entries) == 0 {
+		out.WriteStringFast(0, 0, "no requests yet", Style{Attr: AttrDim}, w)
+	}
+	for y := 0; y < h && r.top+y < len(entries); y++ {
+		e := &entries[r.top+y]
+		dur := ""
+		if e.Done {
+			dur = requestDuration(e.Duration)
+		}
+		out.WriteSpans(0, y, []Span{
+			{Text: fmt.Sprintf("%-*s ", methodW, e.Method), Style: Style{Attr: AttrBold}},
+			{Text: fmt.Sprintf("%3s ", requestStatus(e)), Style: requestStatusStyle(e)},
+			{Text: fmt.Sprintf("%7s ", dur), Style: Style{Attr: AttrDim}},
+			{Text: e.URL},
+		}, w)
+		if r.top+y == current {
+			highlightRow(out, y, w, r.selectedStyle)
+		}
+	}
+	r.layer.SetBuffer(out)
+}
+
+func (t *Template) compileRequestListC(v *RequestListC, parent int16, depth int) int16 {
+	t.collectBindings(v)
+	t.collectUpdateHook(v)
+	layerView := LayerView(v.layer).Grow(v.grow)
+	if v.height > 0 {
+		layerView = layerView.ViewHeight(v.height)
+	}
+	return t.compileLayerViewC(layerView, parent, depth)
+}
+
+// RequestDetailC shows a RequestLog's current entry in full: the request
+// line, status and timing, then each side's headers and body. JSON bodies
+// are indented and highlighted. It scrolls, back to the top whenever the
+// current entry changes.
+//
+//	RequestDetail(reqs).BindScroll("<C-e>", "<C-y>").Grow(2)
+type RequestDetailC struct {
+	log   *RequestLog
+	layer *Layer
+	shown int // ID of the entry on show
+
+	headingStyle Style
+	nameStyle    Style
+
+	grow   float32
+	height int16
+
+	declaredBindings []binding
+}
+
+// RequestDetail creates a view of log's current entry.
+func RequestDetail(log *RequestLog) *RequestDetailC {
+	d := &RequestDetailC{
+		log:          log,
+		layer:        NewLayer(),
+		shown:        -1,
+		headingStyle: Style{Attr: AttrBold | AttrUnderline},
+		nameStyle:    Style{FG: Cyan},
+	}
+	d.layer.AlwaysRender = true
+	d.layer.Render = d.sync
+	return d
+}
+
+// Ref provides access to the component for external references.
+func (d *RequestDetailC) Ref(f func(*RequestDetailC)) *RequestDetailC { f(d); return d }
+
+// HeadingStyle sets the style of the section headings.
+func (d *RequestDetailC) HeadingStyle(s Style) *RequestDetailC {
+	d.headingStyle = s
+	return d
+}
+
+// Grow sets the flex grow factor.
+func (d *RequestDetailC) Grow(g float32) *RequestDetailC {
+	d.grow = g
+	return d
+}
+
+// Height sets a fixed viewport height.
+func (d *RequestDetailC) Height(h int16) *RequestDetailC {
+	d.height = h
+	return d
+}
+
+// BindScroll registers keys that scroll the detail down and up a line.
+func (d *RequestDetailC) BindScroll(down, up string) *RequestDetailC {
+	d.declaredBindings = append(d.declaredBindings,
+		binding{pattern: down, handler: func(m riffkey.Match) { d.layer.ScrollDown(m.Count) }},
+		binding{pattern: up, handler: func(m riffkey.Match) { d.layer.ScrollUp(m.Count) }},
+	)
+	return d
+}
+
+// BindPageScroll registers keys that scroll the detail by half a page.
+func (d *RequestDetailC) BindPageScroll(down, up string) *RequestDetailC {
+	d.declaredBindings = append(d.declaredBindings,
+		binding{pattern: down, handler: d.layer.HalfPageDown},
+		binding{pattern: up, handler: d.layer.HalfPageUp},
+	)
+	return d
+}
+
+func (d *RequestDetailC) bindings() []binding { return d.declaredBindings }
+
+func (d *RequestDetailC) updateHook() *func() { return &d.log.onUpdate }
+
+func (d *RequestDetailC) sync() {
+	w, h := d.layer.ViewportWidth(), d.layer.ViewportHeight()
+	if w <= 0 || h <= 0 {
+		return
+	}
+	e, ok := d.log.Current()
+	if !ok {
+		out := NewBuffer(w, h)
+		out.WriteStringFast(0, 0, "no request selected", Style{Attr: AttrDim}, w)
+		d.layer.SetBuffer(out)
+		d.shown = -1
+		return
+	}
+	lines := d.lines(&e, w)
+	out := NewBuffer(w, max(len(lines), h))
+	for y, line := range lines {
+		out.WriteSpans(0, y, line, w)
+	}
+	d.layer.SetBuffer(out)
+	if e.ID != d.shown {
+		d.shown = e.ID
+		d.layer.ScrollToTop()
+	}
+}
+
+// lines lays out an entry as lines of spans, width wide.
+func (d *RequestDetailC) lines(e *RequestEntry, width int) [][]Span {
+	dim := Style{Attr: AttrDim}
+	lines := [][]Span{
+		{{Text: e.Method + " ", Style: Style{Attr: AttrBold}}, {Text: e.URL}},
+	}
+	status := []Span{{Text: requestStatus(e), Style: requestStatusStyle(e)}}
+	switch {
+	case e.Err != nil:
+		status = append(status, Span{Text: " " + e.Err.Error(), Style: Style{FG: Red}})
+	case e.Done:
+		status = append(status, Span{Text: " " + http.StatusText(e.Status), Style: requestStatusStyle(e)})
+	}
+	if e.Done {
+		status = append(status, Span{Text: " · " + requestDuration(e.Duration), Style: dim})
+	}
+	if !e.Start.IsZero() {
+		status = append(status, Span{Text: " · " + e.Start.Format("15:04:05.000"), Style: dim})
+	}
+	lines = append(lines, status)
+
+	section := func(title string, header http.Header, body []byte) {
+		lines = append(lines, nil, []Span{{Text: title, Style: d.headingStyle}})
+		names := make([]string, 0, len(header))
+		for name := range header {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			for _, v := range header[name] {
+				lines = append(lines, []Span{{Text: "  " + name + ": ", Style: d.nameStyle}, {Text: v}})
+			}
+		}
+		if len(body) == 0 {
+			return
+		}
+		lines = append(lines, nil)
+		for _, line := range d.bodyLines(header, body, width-2) {
+			lines = append(lines, append([]Span{{Text: "  "}}, line...))
+		}
+	}
+	section("Request", e.RequestHeader, e.RequestBody)
+	if e.Done && e.Err == nil {
+		section("Response", e.ResponseHeader, e.ResponseBody)
+	}
+	if e.Truncated {
+		lines = append(lines, nil, []Span{{Text: "bodies cut short at the log's limit", Style: dim}})
+	}
+	return lines
+}
+
+// bodyLines formats a body for reading: JSON as a StructuredView shows
+// it, other text as it is, and binary as its size.
+func (d *RequestDetailC) bodyLines(header http.Header, body []byte, width int) [][]Span {
+	if trimmed := bytes.TrimSpace(body); json.Valid(trimmed) {
+		f := structJSON(trimmed, "")
+		if f.nested {
+			return structLines(f.fields, width, d.nameStyle, Style{})
+		}
+		return [][]Span{{{Text: f.value}}}
+	}
+	if !looksLikeText(body) {
+		kind := header.Get("Content-Type")
+		if kind == "" {
+			kind = "binary"
+		}
+		return [][]Span{{{Text: fmt.Sprintf("%d bytes of %s", len(body), kind), Style: Style{Attr: AttrDim}}}}
+	}
+	var out [][]Span
+	for _, line := range strings.Split(strings.TrimSuffix(string(body), "\n"), "\n") {
+		out = append(out, []Span{{Text: expandTabs(strings.TrimSuffix(line, "\r"))}})
+	}
+	return out
+}
+
+func (t *Template) compileRequestDetailC(v *RequestDetailC, parent int16, depth int) int16 {
+	t.collectBindings(v)
+	t.collectUpdateHook(v)
+	layerView := LayerView(v.layer).Grow(v.grow)
+	if v.height > 0 {
+		layerView = layerView.ViewHeight(v.height)
+	}
+	return t.compileLayerViewC(layerView, parent, depth)
+}
@@ -0,0 +1,141 @@
+package glyph
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestLog(t *testing.T) {
+	l := NewRequestLog().Limit(3).MaxBody(4)
+	updates := 0
+	l.OnUpdate(func() { updates++ })
+
+	id := l.Begin("POST", "/a", http.Header{"X-A": {"1"}}, []byte("hello"))
+	if e, _ := l.Current(); e.ID != id || e.Done || string(e.RequestBody) != "hell" || !e.Truncated {
+		t.Fatalf("pending entry = %+v", e)
+	}
+	l.End(id, 201, nil, []byte("ok"), nil)
+	if e, _ := l.Current(); !e.Done || e.Status != 201 || string(e.ResponseBody) != "ok" {
+		t.Errorf("ended entry = %+v", e)
+	}
+
+	l.Add(RequestEntry{Method: "GET", URL: "/b"})
+	if e, _ := l.Current(); e.URL != "/b" {
+		t.Errorf("current didn't follow the newest: %q", e.URL)
+	}
+	l.Select(0)
+	l.Add(RequestEntry{Method: "GET", URL: "/c"})
+	if e, _ := l.Current(); e.URL != "/a" {
+		t.Errorf("current moved off a selected entry to %q", e.URL)
+	}
+	l.Add(RequestEntry{Method: "GET", URL: "/d"})
+	if l.Len() != 3 || l.Entries()[0].URL != "/b" || l.Index() != 0 {
+		t.Errorf("after limit: len %d, first %q, index %d", l.Len(), l.Entries()[0].URL, l.Index())
+	}
+	l.End(id, 500, nil, nil, nil) // dropped; ignored
+	if updates != 5 {
+		t.Errorf("updates = %d, want 5", updates)
+	}
+}
+
+func TestRequestLogTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusTeapot)
+		io.WriteString(w, `{"ok":true}`)
+	}))
+	defer srv.Close()
+
+	l := NewRequestLog()
+	client := &http.Client{Transport: l.Transport(nil)}
+	resp, err := client.Post(srv.URL+"/brew", "text/plain", strings.NewReader("earl grey"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e, _ := l.Current(); e.Done {
+		t.Error("entry done before the body was read")
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	e, _ := l.Current()
+	if !e.Done || e.Status != http.StatusTeapot || e.Method != "POST" || !strings.HasSuffix(e.URL, "/brew") {
+		t.Errorf("entry = %+v", e)
+	}
+	if string(e.RequestBody) != "earl grey" || string(e.ResponseBody) != string(body) {
+		t.Errorf("bodies = %q, %q", e.RequestBody, e.ResponseBody)
+	}
+	if e.ResponseHeader.Get("Content-Type") != "application/json" {
+		t.Errorf("response header = %v", e.ResponseHeader)
+	}
+
+	failing := &http.Client{Transport: l.Transport(roundTripFunc(func(*http.Request) (*http.Response, error) {
+		return nil, errors.New("refused")
+	}))}
+	failing.Get("http://example.invalid/")
+	if e, _ := l.Current(); e.Err == nil || !e.Done {
+		t.Errorf("failed entry = %+v", e)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestRequestLogMiddleware(t *testing.T) {
+	l := NewRequestLog()
+	h := l.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		in, _ := io.ReadAll(r.Body)
+		w.Write(append([]byte("echo "), in...))
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("PUT", "/echo", strings.NewReader("hi")))
+
+	e, ok := l.Current()
+	if !ok || e.Status != 200 || e.Method != "PUT" || string(e.RequestBody) != "hi" || string(e.ResponseBody) != "echo hi" {
+		t.Errorf("entry = %+v", e)
+	}
+}
+
+func TestRequestViews(t *testing.T) {
+	l := NewRequestLog()
+	l.Add(RequestEntry{Method: "GET", URL: "/users", Status: 200,
+		ResponseHeader: http.Header{"Content-Type": {"application/json"}},
+		ResponseBody:   []byte(`{"name":"ada","team":{"id":7}}`)})
+	l.Add(RequestEntry{Method: "DELETE", URL: "/users/1", Status: 404})
+
+	tmpl := Build(VBox(RequestList(l).Height(3), RequestDetail(l).Height(12)))
+	buf := NewBuffer(60, 15)
+	tmpl.Execute(buf, 60, 15)
+
+	if got := buf.GetLine(0); !strings.HasPrefix(got, "GET    200") || !strings.Contains(got, "/users") {
+		t.Errorf("list row 0 = %q", got)
+	}
+	if got := buf.GetLine(1); !strings.HasPrefix(got, "DELETE 404") || buf.Get(7, 1).Style.FG != Yellow {
+		t.Errorf("list row 1 = %q", got)
+	}
+	if got := buf.GetLine(3); got != "DELETE /users/1" {
+		t.Errorf("detail heading = %q", got)
+	}
+
+	l.Select(0)
+	buf = NewBuffer(60, 15)
+	tmpl.Execute(buf, 60, 15)
+	out := buf.String()
+	for _, want := range []string{"200 OK", "Content-Type: application/json"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("detail lacks %q:\n%s", want, out)
+		}
+	}
+	for y, want := range []string{"  name  ada", "  team", "    id  7"} {
+		if got := buf.GetLine(11 + y); got != want {
+			t.Errorf("body line %d = %q, want %q", y, got, want)
+		}
+	}
+	if c := buf.Get(2, 11); c.Style.FG != Cyan {
+		t.Errorf("body key style = %+v", c.Style)
+	}
+}
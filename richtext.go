@@ -0,0 +1,257 @@
+package glyph
+
+import (
+	"strconv"
+	"strings"
+)
+
+// RichText builds a []Span one piece at a time. Style methods apply to
+// the most recently added text.
+//
+//	rt := NewRichText().
+//	    Add("ERROR").FG(Red).Bold().
+//	    Add(" disk full")
+//	VBox(rt, ...)
+type RichText struct {
+	spans []Span
+}
+
+// NewRichText creates an empty builder.
+func NewRichText() *RichText {
+	return &RichText{}
+}
+
+// Add appends text with the default style.
+func (r *RichText) Add(text string) *RichText {
+	r.spans = append(r.spans, Span{Text: text})
+	return r
+}
+
+// Markup appends spans parsed from s; see ParseMarkup.
+func (r *RichText) Markup(s string) *RichText {
+	r.spans = append(r.spans, ParseMarkup(s)...)
+	return r
+}
+
+// Span appends a ready-made span.
+func (r *RichText) Span(s Span) *RichText {
+	r.spans = append(r.spans, s)
+	return r
+}
+
+func (r *RichText) last() *Span {
+	if len(r.spans) == 0 {
+		return &Span{}
+	}
+	return &r.spans[len(r.spans)-1]
+}
+
+// Style replaces the style of the last text.
+func (r *RichText) Style(s Style) *RichText { r.last().Style = s; return r }
+
+// FG sets the foreground colour of the last text.
+func (r *RichText) FG(c Color) *RichText { r.last().Style.FG = c; return r }
+
+// BG sets the background colour of the last text.
+func (r *RichText) BG(c Color) *RichText { r.last().Style.BG = c; return r }
+
+// Bold makes the last text bold.
+func (r *RichText) Bold() *RichText { return r.attr(AttrBold) }
+
+// Dim makes the last text dim.
+func (r *RichText) Dim() *RichText { return r.attr(AttrDim) }
+
+// Italic makes the last text italic.
+func (r *RichText) Italic() *RichText { return r.attr(AttrItalic) }
+
+// Underline underlines the last text.
+func (r *RichText) Underline() *RichText { return r.attr(AttrUnderline) }
+
+// Inverse swaps the colours of the last text.
+func (r *RichText) Inverse() *RichText { return r.attr(AttrInverse) }
+
+// Strikethrough strikes through the last text.
+func (r *RichText) Strikethrough() *RichText { return r.attr(AttrStrikethrough) }
+
+// OnSelect makes the last text a jump target that runs fn.
+func (r *RichText) OnSelect(fn func()) *RichText {
+	r.last().Jump = &SpanJump{OnSelect: fn}
+	return r
+}
+
+func (r *RichText) attr(a Attribute) *RichText {
+	s := r.last()
+	s.Style.Attr = s.Style.Attr.With(a)
+	return r
+}
+
+// Spans returns the spans built so far.
+func (r *RichText) Spans() []Span {
+	return r.spans
+}
+
+// Node returns a RichTextNode showing the spans built so far.
+func (r *RichText) Node() RichTextNode {
+	return RichTextNode{Spans: r.spans}
+}
+
+// Markup creates a RichText from inline markup; see ParseMarkup.
+//
+//	Markup("[red::b]ERROR[-] disk full")
+func Markup(s string) RichTextNode {
+	return RichTextNode{Spans: ParseMarkup(s)}
+}
+
+// ParseMarkup converts text with inline style tags into spans. A tag is
+// [fg:bg:attrs], where any field may be left empty to keep its current
+// value or set to "-" to reset it:
+//
+//	[red]            red text
+//	[yellow:blue]    yellow on blue
+//	[::bu]           bold and underlined
+//	[#ff8800::i]     RGB colour, italic
+//	[-]              back to the default style
+//
+// Colours are names (red, brightcyan, grey), 0-255 palette indexes or
+// #rrggbb. Attribute letters are b bold, d dim, i italic, u underline,
+// l blink, r reverse and s strikethrough. Write [[ for a literal [.
+// Bracketed text that isn't a valid tag is shown as is.
+func ParseMarkup(s string) []Span {
+	var spans []Span
+	var cur Style
+	var text strings.Builder
+
+	flush := func() {
+		if text.Len() > 0 {
+			spans = append(spans, Span{Text: text.String(), Style: cur})
+			text.Reset()
+		}
+	}
+
+	for i := 0; i < len(s); {
+		if s[i] != '[' {
+			j := strings.IndexByte(s[i:], '[')
+			if j < 0 {
+				j = len(s) - i
+			}
+			text.WriteString(s[i : i+j])
+			i += j
+			continue
+		}
+		if strings.HasPrefix(s[i:], "[[") {
+			text.WriteByte('[')
+			i += 2
+			continue
+		}
+		end := strings.IndexByte(s[i:], ']')
+		if end < 0 {
+			text.WriteString(s[i:])
+			break
+		}
+		next, ok := applyTag(cur, s[i+1:i+end])
+		if !ok {
+			text.WriteString(s[i : i+end+1])
+			i += end + 1
+			continue
+		}
+		flush()
+		cur = next
+		i += end + 1
+	}
+	flush()
+	return spans
+}
+
+// applyTag returns style with the tag's fields applied, or false if tag
+// isn't markup.
+func applyTag(style Style, tag string) (Style, bool) {
+	if tag == "-" {
+		return Style{}, true
+	}
+	fields := strings.Split(tag, ":")
+	if tag == "" || len(fields) > 3 {
+		return style, false
+	}
+	for i, f := range fields {
+		if f == "" {
+			continue
+		}
+		switch i {
+		case 0, 1:
+			var c Color
+			if f != "-" {
+				var ok bool
+				if c, ok = parseMarkupColor(f); !ok {
+					return style, false
+				}
+			}
+			if i == 0 {
+				style.FG = c
+			} else {
+				style.BG = c
+			}
+		case 2:
+			if f == "-" {
+				style.Attr = AttrNone
+				continue
+			}
+			for _, ch := range f {
+				a, ok := markupAttrs[ch]
+				if !ok {
+					return style, false
+				}
+				style.Attr = style.Attr.With(a)
+			}
+		}
+	}
+	return style, true
+}
+
+var markupAttrs = map[rune]Attribute{
+	'b': AttrBold,
+	'd': AttrDim,
+	'i': AttrItalic,
+	'u': AttrUnderline,
+	'l': AttrBlink,
+	'r': AttrInverse,
+	's': AttrStrikethrough,
+}
+
+var markupColors = map[string]Color{
+	"black":         Black,
+	"red":           Red,
+	"green":         Green,
+	"yellow":        Yellow,
+	"blue":          Blue,
+	"magenta":       Magenta,
+	"cyan":          Cyan,
+	"white":         White,
+	"gray":          BrightBlack,
+	"grey":          BrightBlack,
+	"brightblack":   BrightBlack,
+	"brightred":     BrightRed,
+	"brightgreen":   BrightGreen,
+	"brightyellow":  BrightYellow,
+	"brightblue":    BrightBlue,
+	"brightmagenta": BrightMagenta,
+	"brightcyan":    BrightCyan,
+	"brightwhite":   BrightWhite,
+	"default":       DefaultColor(),
+}
+
+func parseMarkupColor(s string) (Color, bool) {
+	if c, ok := markupColors[strings.ToLower(s)]; ok {
+		return c, true
+	}
+	if len(s) == 7 && s[0] == '#' {
+		v, err := strconv.ParseUint(s[1:], 16, 32)
+		if err != nil {
+			return Color{}, false
+		}
+		return Hex(uint32(v)), true
+	}
+	if n, err := strconv.Atoi(s); err == nil && n >= 0 && n <= 255 {
+		return PaletteColor(uint8(n)), true
+	}
+	return Color{}, false
+}
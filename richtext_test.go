@@ -0,0 +1,80 @@
+package glyph
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseMarkup(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []Span
+	}{
+		{"plain", []Span{{Text: "plain"}}},
+		{"[red::b]ERROR[-] message", []Span{
+			{Text: "ERROR", Style: Style{FG: Red, Attr: AttrBold}},
+			{Text: " message"},
+		}},
+		{"[yellow:blue]a[:-]b[::u]c", []Span{
+			{Text: "a", Style: Style{FG: Yellow, BG: Blue}},
+			{Text: "b", Style: Style{FG: Yellow}},
+			{Text: "c", Style: Style{FG: Yellow, Attr: AttrUnderline}},
+		}},
+		{"[#ff8800]hex[196]pal", []Span{
+			{Text: "hex", Style: Style{FG: Hex(0xff8800)}},
+			{Text: "pal", Style: Style{FG: PaletteColor(196)}},
+		}},
+		{"[[red] and [not a tag] and [", []Span{{Text: "[red] and [not a tag] and ["}}},
+		{"[::bi]x[::-]y", []Span{
+			{Text: "x", Style: Style{Attr: AttrBold | AttrItalic}},
+			{Text: "y"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got := ParseMarkup(tt.in)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("span %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRichTextBuilder(t *testing.T) {
+	rt := NewRichText().
+		Add("ERROR").FG(Red).Bold().
+		Add(" disk ").
+		Markup("[green]full")
+	spans := rt.Spans()
+	if len(spans) != 3 {
+		t.Fatalf("spans = %+v", spans)
+	}
+	if spans[0].Style != (Style{FG: Red, Attr: AttrBold}) || spans[1].Style != (Style{}) || spans[2].Style.FG != Green {
+		t.Errorf("styles = %+v", spans)
+	}
+
+	buf := NewBuffer(20, 1)
+	Build(VBox(rt)).Execute(buf, 20, 1)
+	if got := strings.TrimSpace(buf.GetLine(0)); got != "ERROR disk full" {
+		t.Errorf("line = %q", got)
+	}
+	if c := buf.Get(0, 0); c.Style.FG != Red || !c.Style.Attr.Has(AttrBold) {
+		t.Errorf("first cell style = %+v", c.Style)
+	}
+
+	// styling before any Add is ignored rather than panicking
+	NewRichText().Bold().FG(Red)
+}
+
+func TestMarkupNode(t *testing.T) {
+	buf := NewBuffer(20, 1)
+	Build(VBox(Markup("[red]a[-]b"))).Execute(buf, 20, 1)
+	if buf.Get(0, 0).Style.FG != Red || buf.Get(1, 0).Style.FG == Red {
+		t.Errorf("styles = %+v %+v", buf.Get(0, 0).Style, buf.Get(1, 0).Style)
+	}
+}
@@ -35,6 +35,25 @@ type Screen struct {
 	// Rendering state
 	lastStyle Style        // Last style we emitted (for optimization)
 	buf       bytes.Buffer // Reusable buffer for building output
+	quirks    Quirks       // What the terminal path supports
+	output    Output       // How frames are written
+
+	// The cursor as BufferCursor last sent it, so what hasn't changed
+	// isn't sent again. cursorSent is false once anything else may have
+	// changed it, and cursorMoved once only its position may have.
+	cursor      Cursor
+	cursorSent  bool
+	cursorMoved bool
+
+	// Whether frames are wrapped in synchronized updates, and whether the
+	// output buffer holds the start of one that hasn't been ended
+	sync    bool
+	syncing bool
+
+	// Title and notification sequences waiting for the next frame, and
+	// whether the terminal's own title was saved before setting one
+	pending    []byte
+	titleSaved bool
 
 	// Synchronization - protects buffer access during resize
 	mu sync.Mutex
@@ -70,11 +89,30 @@ func NewScreen(w io.Writer) (*Screen, error) {
 		resizeChan: make(chan Size, 1),
 		sigChan:    make(chan os.Signal, 1),
 		lastStyle:  DefaultStyle(),
+		quirks:     DetectQuirks(),
 	}
+	s.sync = detectCapabilities(os.Getenv, s.quirks).SyncOutput
 
 	return s, nil
 }
 
+// NewScreenSize creates a screen of a fixed size writing to w, without
+// querying or configuring a terminal. Use it as a reproducible fake
+// terminal in tests and benchmarks.
+func NewScreenSize(w io.Writer, width, height int) *Screen {
+	return &Screen{
+		front:      NewBuffer(width, height),
+		back:       NewBuffer(width, height),
+		writer:     w,
+		fd:         -1,
+		width:      width,
+		height:     height,
+		resizeChan: make(chan Size, 1),
+		sigChan:    make(chan os.Signal, 1),
+		lastStyle:  DefaultStyle(),
+	}
+}
+
 // getTerminalSize returns the current terminal dimensions.
 func getTerminalSize(fd int) (int, int, error) {
 	ws, err := unix.IoctlGetWinsize(fd, unix.TIOCGWINSZ)
@@ -139,6 +177,8 @@ func (s *Screen) EnterRawMode() error {
 	}
 
 	s.inRawMode = true
+	s.cursorSent = false
+	s.lastStyle = DefaultStyle()
 
 	// Start listening for resize signals
 	signal.Notify(s.sigChan, syscall.SIGWINCH)
@@ -146,6 +186,7 @@ func (s *Screen) EnterRawMode() error {
 
 	// Enter alternate screen, hide cursor, enable bracketed paste
 	s.writeString("\x1b[?1049h") // Enter alternate screen
+	s.writeString("\x1b[0m")     // Reset style (ensures lastStyle matches actual style)
 	s.writeString("\x1b[2J")     // Clear screen (ensures front buffer matches actual screen)
 	s.writeString("\x1b[H")      // Move cursor to home position
 	s.writeString("\x1b[?25l")   // Hide cursor
@@ -164,6 +205,7 @@ func (s *Screen) ExitRawMode() error {
 	s.writeString("\x1b[?2004l") // Disable bracketed paste mode
 	s.writeString("\x1b[?25h")   // Show cursor
 	s.writeString("\x1b[?1049l") // Exit alternate screen
+	s.restoreTitle()
 
 	signal.Stop(s.sigChan)
 
@@ -210,6 +252,7 @@ func (s *Screen) EnterInlineMode() error {
 
 	s.inRawMode = true
 	s.inlineMode = true
+	s.cursorSent = false
 
 	// Start listening for resize signals
 	signal.Notify(s.sigChan, syscall.SIGWINCH)
@@ -259,6 +302,7 @@ func (s *Screen) ExitInlineMode(linesUsed int, clear bool) error {
 		// Reset style
 		s.writeString("\x1b[0m")
 	}
+	s.restoreTitle()
 
 	signal.Stop(s.sigChan)
 
@@ -296,6 +340,7 @@ func (s *Screen) handleSignals() {
 			s.back.Clear()
 			// Clear the actual terminal screen
 			s.writeString("\x1b[2J")
+			s.cursorSent = false
 			s.mu.Unlock()
 			// Non-blocking send (outside lock to avoid potential deadlock)
 			select {
@@ -310,6 +355,7 @@ func (s *Screen) handleSignals() {
 type FlushStats struct {
 	DirtyRows   int
 	ChangedRows int
+	Bytes       int // written by FlushBuffer
 }
 
 // lastFlushStats holds stats from the most recent flush.
@@ -331,10 +377,17 @@ func (s *Screen) Flush() {
 	defer s.mu.Unlock()
 
 	s.buf.Reset()
+	s.beginFrame()
 
 	dirtyCount := 0
 	changedCount := 0
 	cursorX, cursorY := -1, -1
+	relative := false
+	if s.cursorSent && !s.cursorMoved {
+		// the cursor is still where the last frame left it
+		cursorX, cursorY = s.cursor.X, s.cursor.Y
+		relative = true
+	}
 	positionCount := 0
 
 	for y := 0; y < s.height; y++ {
@@ -344,15 +397,23 @@ func (s *Screen) Flush() {
 		}
 		dirtyCount++
 
+		backRow := s.back.cells[y*s.back.width:][:s.width]
+		frontRow := s.front.cells[y*s.front.width:][:s.width]
+		// most rows of most frames are unchanged, and comparing them whole
+		// is far cheaper than cell by cell
+		if sameCells(backRow, frontRow) {
+			continue
+		}
+
 		rowChanged := false
-		backBase := y * s.back.width
 		frontBase := y * s.front.width
 		for x := 0; x < s.width; x++ {
-			idx := backBase + x
-			backCell := s.back.cells[idx]
-			if backCell == s.front.cells[frontBase+x] {
-				continue
+			// skip runs of unchanged cells a block at a time
+			x += firstDiff(backRow[x:], frontRow[x:])
+			if x >= s.width {
+				break
 			}
+			backCell := backRow[x]
 
 			// skip placeholder cells (second half of double-width chars)
 			if backCell.Rune == 0 {
@@ -374,15 +435,18 @@ func (s *Screen) Flush() {
 						x, y, cursorX, cursorY, backCell.Rune, backCell.Rune, rw)
 				}
 				positionCount++
-				s.buf.WriteString("\x1b[")
-				s.writeIntToBuf(y + 1)
-				s.buf.WriteByte(';')
-				s.writeIntToBuf(x + 1)
-				s.buf.WriteByte('H')
+				s.cursorMoved = true
+				if relative {
+					s.moveCursor(cursorX, cursorY, x, y)
+				} else {
+					s.moveCursor(-1, -1, x, y)
+				}
+				relative = true
 			}
 
 			s.writeCell(&s.buf, backCell)
 			s.front.cells[frontBase+x] = backCell
+			s.cursorMoved = true
 			// cursor advances by the display width of the character
 			// fast path: ASCII runes are always width 1
 			rw := 1
@@ -394,6 +458,9 @@ func (s *Screen) Flush() {
 			}
 			cursorX = x + rw
 			cursorY = y
+			// terminals differ on the width of wide characters and emoji,
+			// so don't move relative to where one ends
+			relative = backCell.Rune < 0x1100
 		}
 	}
 
@@ -406,6 +473,10 @@ func (s *Screen) Flush() {
 	if changedCount > 0 {
 		s.buf.WriteString("\x1b[0m")
 		s.lastStyle = DefaultStyle()
+	} else if s.syncing {
+		// nothing to hold back
+		s.buf.Reset()
+		s.syncing = false
 	}
 	// Note: Don't write here - let FlushBuffer() do it so we can batch cursor ops
 
@@ -444,6 +515,7 @@ func (s *Screen) FlushFull() {
 	defer s.mu.Unlock()
 
 	s.buf.Reset()
+	s.beginFrame()
 
 	// Clear screen and move to home
 	s.buf.WriteString("\x1b[2J\x1b[H")
@@ -462,8 +534,10 @@ func (s *Screen) FlushFull() {
 	// Reset style at end
 	s.buf.WriteString("\x1b[0m")
 	s.lastStyle = DefaultStyle()
+	s.cursorSent = false
+	s.endFrame()
 
-	s.writer.Write(s.buf.Bytes())
+	s.write(s.buf.Bytes())
 }
 
 // FlushInline renders the buffer for inline mode (no alternate screen).
@@ -477,6 +551,7 @@ func (s *Screen) FlushInline(height, prevLines int) int {
 	defer s.mu.Unlock()
 
 	s.buf.Reset()
+	s.beginFrame()
 
 	linesRendered := 0
 	for y := 0; y < height && y < s.height; y++ {
@@ -520,8 +595,11 @@ func (s *Screen) FlushInline(height, prevLines int) int {
 		s.buf.WriteString(fmt.Sprintf("\x1b[%dA", totalLines-1))
 	}
 	s.buf.WriteString("\r")
+	s.cursorSent = false
+	s.endFrame()
+	s.writePending()
 
-	s.writer.Write(s.buf.Bytes())
+	s.write(s.buf.Bytes())
 	s.back.ClearDirtyFlags()
 
 	return linesRendered
@@ -531,7 +609,7 @@ func (s *Screen) FlushInline(height, prevLines int) int {
 func (s *Screen) writeCell(buf *bytes.Buffer, cell Cell) {
 	// Only emit style changes
 	if !cell.Style.Equal(s.lastStyle) {
-		s.writeStyle(buf, cell.Style)
+		s.writeStyleChange(buf, s.lastStyle, cell.Style)
 		s.lastStyle = cell.Style
 	}
 	buf.WriteRune(cell.Rune)
@@ -543,26 +621,10 @@ func (s *Screen) writeStyle(buf *bytes.Buffer, style Style) {
 	buf.WriteString("\x1b[0")
 
 	// Attributes
-	if style.Attr.Has(AttrBold) {
-		buf.WriteString(";1")
-	}
-	if style.Attr.Has(AttrDim) {
-		buf.WriteString(";2")
-	}
-	if style.Attr.Has(AttrItalic) {
-		buf.WriteString(";3")
-	}
-	if style.Attr.Has(AttrUnderline) {
-		buf.WriteString(";4")
-	}
-	if style.Attr.Has(AttrBlink) {
-		buf.WriteString(";5")
-	}
-	if style.Attr.Has(AttrInverse) {
-		buf.WriteString(";7")
-	}
-	if style.Attr.Has(AttrStrikethrough) {
-		buf.WriteString(";9")
+	for _, a := range sgrAttrs {
+		if style.Attr.Has(a.attr) {
+			buf.WriteString(a.param)
+		}
 	}
 
 	// Foreground color
@@ -576,6 +638,9 @@ func (s *Screen) writeStyle(buf *bytes.Buffer, style Style) {
 
 // writeColor writes the ANSI escape code for a color (allocation-free).
 func (s *Screen) writeColor(buf *bytes.Buffer, c Color, fg bool) {
+	if c.Mode == ColorRGB && s.quirks.NoTrueColor {
+		c = Color{Mode: Color256, Index: rgbTo256(c.R, c.G, c.B)}
+	}
 	switch c.Mode {
 	case ColorDefault:
 		// Use default color (39 for fg, 49 for bg)
@@ -635,11 +700,13 @@ func (s *Screen) Clear() {
 // ShowCursor makes the cursor visible.
 func (s *Screen) ShowCursor() {
 	s.writeString("\x1b[?25h")
+	s.cursorSent = false
 }
 
 // HideCursor hides the cursor.
 func (s *Screen) HideCursor() {
 	s.writeString("\x1b[?25l")
+	s.cursorSent = false
 }
 
 // MoveCursor moves the cursor to the given position (0-indexed).
@@ -653,24 +720,39 @@ func (s *Screen) MoveCursor(x, y int) {
 	b = appendInt(b, x+1)
 	b = append(b, 'H')
 	s.writer.Write(b)
+	s.cursorMoved = true
 }
 
 // BufferCursor writes cursor positioning and visibility to the internal buffer.
 // Call this before FlushBuffer() to batch cursor ops with content in one syscall.
+// Only what changed since the last call is written.
 func (s *Screen) BufferCursor(x, y int, visible bool, shape CursorShape) {
+	sent, moved, prev := s.cursorSent, s.cursorMoved, s.cursor
+	s.cursor = Cursor{X: x, Y: y, Style: shape, Visible: visible}
+	s.cursorSent, s.cursorMoved = true, false
+
 	// Cursor shape: \x1b[N q
-	s.buf.WriteString("\x1b[")
-	s.writeIntToBuf(int(shape))
-	s.buf.WriteString(" q")
+	if !s.quirks.NoCursorShape && (!sent || shape != prev.Style) {
+		var scratch [16]byte
+		b := append(scratch[:0], "\x1b["...)
+		b = appendInt(b, int(shape))
+		b = append(b, " q"...)
+		s.writeQuirked(&s.buf, b)
+	}
 
 	// Cursor position: \x1b[row;colH
-	s.buf.WriteString("\x1b[")
-	s.writeIntToBuf(y + 1)
-	s.buf.WriteByte(';')
-	s.writeIntToBuf(x + 1)
-	s.buf.WriteByte('H')
+	if !sent || moved || x != prev.X || y != prev.Y {
+		s.buf.WriteString("\x1b[")
+		s.writeIntToBuf(y + 1)
+		s.buf.WriteByte(';')
+		s.writeIntToBuf(x + 1)
+		s.buf.WriteByte('H')
+	}
 
 	// Cursor visibility
+	if sent && visible == prev.Visible {
+		return
+	}
 	if visible {
 		s.buf.WriteString("\x1b[?25h")
 	} else {
@@ -681,16 +763,37 @@ func (s *Screen) BufferCursor(x, y int, visible bool, shape CursorShape) {
 // BufferCursorColor sets cursor color using OSC 12 escape sequence.
 // Format: OSC 12 ; #RRGGBB BEL
 func (s *Screen) BufferCursorColor(c Color) {
-	if c.Mode == ColorRGB {
-		s.buf.WriteString("\x1b]12;#")
-		s.buf.WriteByte(hexDigit(c.R >> 4))
-		s.buf.WriteByte(hexDigit(c.R & 0xF))
-		s.buf.WriteByte(hexDigit(c.G >> 4))
-		s.buf.WriteByte(hexDigit(c.G & 0xF))
-		s.buf.WriteByte(hexDigit(c.B >> 4))
-		s.buf.WriteByte(hexDigit(c.B & 0xF))
-		s.buf.WriteByte('\x07') // BEL terminator
+	if c.Mode == ColorRGB && !s.quirks.NoCursorColor {
+		var scratch [16]byte
+		b := append(scratch[:0], "\x1b]12;#"...)
+		b = append(b,
+			hexDigit(c.R>>4), hexDigit(c.R&0xF),
+			hexDigit(c.G>>4), hexDigit(c.G&0xF),
+			hexDigit(c.B>>4), hexDigit(c.B&0xF),
+			'\x07') // BEL terminator
+		s.writeQuirked(&s.buf, b)
+	}
+}
+
+// writeQuirked writes a cursor sequence, wrapping it for passthrough when
+// the multiplexer would otherwise swallow it.
+func (s *Screen) writeQuirked(w io.Writer, seq []byte) {
+	if s.quirks.Passthrough {
+		io.WriteString(w, s.quirks.Wrap(string(seq)))
+		return
 	}
+	w.Write(seq)
+}
+
+// SetQuirks overrides the detected terminal quirks.
+func (s *Screen) SetQuirks(q Quirks) {
+	s.quirks = q
+	s.cursorSent = false
+}
+
+// Quirks returns the terminal quirks in effect.
+func (s *Screen) Quirks() Quirks {
+	return s.quirks
 }
 
 func hexDigit(n uint8) byte {
@@ -700,10 +803,17 @@ func hexDigit(n uint8) byte {
 	return 'a' + n - 10
 }
 
-// FlushBuffer writes the accumulated buffer to the terminal in one syscall.
+// FlushBuffer writes the accumulated buffer to the terminal in one syscall,
+// or in writes of at most Output.MaxWrite bytes, ending the frame's
+// synchronized update.
 func (s *Screen) FlushBuffer() {
+	s.endFrame()
+	s.mu.Lock()
+	s.writePending()
+	s.mu.Unlock()
+	lastFlushStats.Bytes = s.buf.Len()
 	if s.buf.Len() > 0 {
-		s.writer.Write(s.buf.Bytes())
+		s.write(s.buf.Bytes())
 	}
 }
 
@@ -722,13 +832,17 @@ const (
 
 // SetCursorShape changes the cursor shape.
 func (s *Screen) SetCursorShape(shape CursorShape) {
+	if s.quirks.NoCursorShape {
+		return
+	}
 	// Build escape sequence without allocation: \x1b[N q
 	var scratch [16]byte
 	b := scratch[:0]
 	b = append(b, "\x1b["...)
 	b = appendInt(b, int(shape))
 	b = append(b, " q"...)
-	s.writer.Write(b)
+	s.writeQuirked(s.writer, b)
+	s.cursorSent = false
 }
 
 // appendInt appends an integer to a byte slice without allocation.
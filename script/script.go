@@ -0,0 +1,231 @@
+// Package script embeds a Lua interpreter in a glyph app, so its users can
+// add commands, key bindings and small panels from a script without a Go
+// toolchain:
+//
+//	s := script.New(app)
+//	defer s.Close()
+//	if err := s.DoFile(filepath.Join(configDir, "init.lua")); err != nil {
+//	    log.Print(err)
+//	}
+//	app.SetView(VBox(editor.Grow(1), s.Widget("status")))
+//
+// Scripts see a table named glyph:
+//
+//	glyph.command("wc", "Count words", function(...) ... end)
+//	glyph.map("<C-w>", function() glyph.run("wc") end)
+//	glyph.widget("status", function(width)
+//	    return { "[green::b]ok[-] " .. os.date("%H:%M") }
+//	end)
+//
+// command adds to the app's Commands, so Lua commands can be run from a
+// CommandLine or CommandPalette and take their arguments as strings; an
+// error raised in one is the error the command returns. map binds a key on
+// the app's router. run runs a command line and returns nil or the error
+// message, and commands lists the registered command names. widget names
+// a function that returns the lines of a panel, in glyph markup, which
+// Widget places in a view; it's called each frame with the width
+// available.
+//
+// Only one goroutine runs Lua at a time, so scripts need no locking of
+// their own.
+package script
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/kungfusheep/glyph"
+	lua "github.com/yuin/gopher-lua"
+)
+
+// Engine is a Lua interpreter wired to an app.
+type Engine struct {
+	app     *glyph.App
+	mu      sync.Mutex
+	l       *lua.LState
+	widgets map[string]*lua.LFunction
+	onError func(error)
+}
+
+// New creates an interpreter with Lua's standard libraries and the glyph
+// table, bound to app.
+func New(app *glyph.App) *Engine {
+	e := &Engine{app: app, l: lua.NewState(), widgets: map[string]*lua.LFunction{}}
+	mod := e.l.NewTable()
+	e.l.SetFuncs(mod, map[string]lua.LGFunction{
+		"command":  e.luaCommand,
+		"map":      e.luaMap,
+		"run":      e.luaRun,
+		"commands": e.luaCommands,
+		"widget":   e.luaWidget,
+	})
+	e.l.SetGlobal("glyph", mod)
+	return e
+}
+
+// OnError sets a function called with errors raised by the functions
+// scripts bind to keys, which have nowhere else to go.
+func (e *Engine) OnError(fn func(error)) *Engine {
+	e.onError = fn
+	return e
+}
+
+// State returns the interpreter, for adding Go functions of the app's own.
+// Use it only from the goroutine that calls DoString or DoFile, before the
+// app runs.
+func (e *Engine) State() *lua.LState {
+	return e.l
+}
+
+// Close frees the interpreter. Commands and keys the scripts added stay
+// registered but do nothing.
+func (e *Engine) Close() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.l != nil {
+		e.l.Close()
+		e.l = nil
+	}
+}
+
+// DoString runs Lua source.
+func (e *Engine) DoString(src string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.l == nil {
+		return errClosed
+	}
+	return e.l.DoString(src)
+}
+
+// DoFile runs a Lua file.
+func (e *Engine) DoFile(path string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.l == nil {
+		return errClosed
+	}
+	return e.l.DoFile(path)
+}
+
+var errClosed = errors.New("script: engine closed")
+
+// call runs fn with args, holding the lock, and returns what it returned.
+func (e *Engine) call(fn *lua.LFunction, args ...lua.LValue) (lua.LValue, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.l == nil {
+		return lua.LNil, errClosed
+	}
+	if err := e.l.CallByParam(lua.P{Fn: fn, NRet: 1, Protect: true}, args...); err != nil {
+		return lua.LNil, err
+	}
+	ret := e.l.Get(-1)
+	e.l.Pop(1)
+	return ret, nil
+}
+
+// glyph.command(name, desc, fn)
+func (e *Engine) luaCommand(L *lua.LState) int {
+	name, desc, fn := L.CheckString(1), L.OptString(2, ""), L.CheckFunction(3)
+	e.app.Command(name, desc, func(args ...string) error {
+		in := make([]lua.LValue, len(args))
+		for i, a := range args {
+			in[i] = lua.LString(a)
+		}
+		_, err := e.call(fn, in...)
+		return err
+	})
+	return 0
+}
+
+// glyph.map(pattern, fn)
+func (e *Engine) luaMap(L *lua.LState) int {
+	pattern, fn := L.CheckString(1), L.CheckFunction(2)
+	e.app.Handle(pattern, func() {
+		if _, err := e.call(fn); err != nil && e.onError != nil {
+			e.onError(err)
+		}
+	})
+	return 0
+}
+
+// glyph.run(line) returns nil, or the error as a string.
+func (e *Engine) luaRun(L *lua.LState) int {
+	line := L.CheckString(1)
+	// the command may be a Lua one, which takes the lock this call is
+	// running under; let it go while the line runs, as a nested call
+	e.mu.Unlock()
+	err := e.app.Commands().Run(line)
+	e.mu.Lock()
+	if err != nil {
+		L.Push(lua.LString(err.Error()))
+		return 1
+	}
+	L.Push(lua.LNil)
+	return 1
+}
+
+// glyph.commands() returns the names of the registered commands.
+func (e *Engine) luaCommands(L *lua.LState) int {
+	t := L.NewTable()
+	for _, cmd := range e.app.Commands().All() {
+		t.Append(lua.LString(cmd.Name))
+	}
+	L.Push(t)
+	return 1
+}
+
+// glyph.widget(name, fn)
+func (e *Engine) luaWidget(L *lua.LState) int {
+	e.widgets[L.CheckString(1)] = L.CheckFunction(2)
+	return 0
+}
+
+// Widget returns a panel drawn by the script function registered under
+// name, as tall as the lines it returns. It's empty until a script
+// registers the name, and shows the error in red if the function fails.
+func (e *Engine) Widget(name string) glyph.Custom {
+	var lines []string
+	var failed bool
+	return glyph.Widget(
+		func(availW int16) (w, h int16) {
+			lines, failed = e.widgetLines(name, availW)
+			return availW, int16(len(lines))
+		},
+		func(buf *glyph.Buffer, x, y, w, h int16) {
+			for i, line := range lines[:min(len(lines), int(h))] {
+				if failed {
+					buf.WriteStringFast(int(x), int(y)+i, line, glyph.Style{FG: glyph.Red}, int(w))
+					continue
+				}
+				buf.WriteSpans(int(x), int(y)+i, glyph.ParseMarkup(line), int(w))
+			}
+		},
+	)
+}
+
+// widgetLines calls a widget function for its lines, or describes why it
+// couldn't.
+func (e *Engine) widgetLines(name string, width int16) (lines []string, failed bool) {
+	e.mu.Lock()
+	fn := e.widgets[name]
+	e.mu.Unlock()
+	if fn == nil {
+		return nil, false
+	}
+	ret, err := e.call(fn, lua.LNumber(width))
+	if err != nil {
+		return []string{fmt.Sprintf("%s: %v", name, err)}, true
+	}
+	switch v := ret.(type) {
+	case lua.LString:
+		return []string{string(v)}, false
+	case *lua.LTable:
+		for i := 1; i <= v.Len(); i++ {
+			lines = append(lines, lua.LVAsString(v.RawGetInt(i)))
+		}
+	}
+	return lines, false
+}
@@ -0,0 +1,71 @@
+package script
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kungfusheep/glyph"
+	"github.com/kungfusheep/riffkey"
+)
+
+func TestScript(t *testing.T) {
+	app, _ := glyph.NewApp()
+	be := glyph.NewBufferBackend(30, 3)
+	app.SetBackend(be)
+	s := New(app)
+	defer s.Close()
+
+	err := s.DoString(`
+		count = 0
+		glyph.command("add", "Add to the count", function(n)
+			if n == nil then error("add what?") end
+			count = count + tonumber(n)
+		end)
+		glyph.command("double", "", function()
+			return glyph.run("add " .. count)
+		end)
+		glyph.map("+", function() glyph.run("add 1") end)
+		glyph.widget("count", function(width)
+			return { "[green::b]count[-] " .. count, "width " .. width }
+		end)
+		glyph.widget("broken", function() error("oops") end)
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.SetView(glyph.VBox(s.Widget("count"), s.Widget("broken"), s.Widget("missing")))
+
+	cmds := app.Commands()
+	if err := cmds.Run("add 2"); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmds.Run("double"); err != nil { // a Lua command running another
+		t.Fatal(err)
+	}
+	if err := cmds.Run("add"); err == nil || !strings.Contains(err.Error(), "add what?") {
+		t.Errorf("error %v", err)
+	}
+	app.Input().Dispatch(riffkey.Key{Rune: '+'})
+
+	app.RenderNow()
+	frame := be.Frame()
+	if got := frame.GetLine(0); got != "count 5" {
+		t.Errorf("line 0 %q", got)
+	}
+	if got := frame.GetLine(1); got != "width 30" {
+		t.Errorf("line 1 %q", got)
+	}
+	if got := frame.GetLine(2); !strings.HasPrefix(got, "broken: ") || !strings.Contains(got, "oops") {
+		t.Errorf("error line %q", got)
+	}
+	if c := frame.Get(0, 0); c.Style.FG != glyph.Green || c.Style.Attr&glyph.AttrBold == 0 {
+		t.Errorf("markup style %+v", c.Style)
+	}
+
+	if err := s.DoString(`names = table.concat(glyph.commands(), ",")`); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.State().GetGlobal("names").String(); got != "add,double" {
+		t.Errorf("commands %q", got)
+	}
+}
@@ -0,0 +1,213 @@
+package glyph
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"os"
+	"sync"
+)
+
+// scrollbackStride is how many spilled lines share an index entry. Reading
+// a line reads its whole block, so the index stays small, 8 bytes for every
+// 64 lines, however much is on disk.
+const scrollbackStride = 64
+
+// Scrollback is an append-only store of lines that keeps the latest in
+// memory and spills older ones to a temporary file, so a long-running app
+// can hold far more history than it could in memory and still read or
+// search any of it:
+//
+//	sb := NewScrollback(10000, "")   // 10000 lines in memory, the rest in os.TempDir()
+//	sb.Append(line)
+//	page := sb.Lines(top, height)
+//
+// The file is unlinked as soon as it's made, so it goes when the process
+// does, or on Close. If it can't be written, the oldest lines are dropped
+// instead and Err says why. A Scrollback is safe for concurrent use.
+type Scrollback struct {
+	mu      sync.Mutex
+	keep    int
+	dir     string
+	recent  []string // in memory, from line spilled on
+	spilled int      // lines on disk, or dropped if spilling failed
+	err     error
+
+	file  *os.File
+	w     *bufio.Writer
+	size  int64   // bytes written to file
+	index []int64 // offset of each block of scrollbackStride lines
+
+	cached      int // block number of cachedLines, -1 for none
+	cachedLines []string
+}
+
+// NewScrollback creates a store keeping at least keep lines in memory and
+// spilling the rest to a file in dir, os.TempDir() if empty.
+func NewScrollback(keep int, dir string) *Scrollback {
+	return &Scrollback{keep: max(keep, scrollbackStride), dir: dir, cached: -1}
+}
+
+// Append adds a line to the end.
+func (s *Scrollback) Append(line string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.recent = append(s.recent, line)
+	if len(s.recent) >= s.keep+max(s.keep/4, scrollbackStride) {
+		s.spill(len(s.recent) - s.keep)
+	}
+}
+
+// spill moves the oldest n lines out of memory, whole blocks at a time so
+// each block starts on an index entry.
+func (s *Scrollback) spill(n int) {
+	n -= n % scrollbackStride
+	if n == 0 {
+		return
+	}
+	if s.file == nil && s.err == nil {
+		s.file, s.err = os.CreateTemp(s.dir, "glyph-scrollback-*")
+		if s.err == nil {
+			os.Remove(s.file.Name())
+			s.w = bufio.NewWriterSize(s.file, 64*1024)
+		}
+	}
+	var length [binary.MaxVarintLen64]byte
+	for i, line := range s.recent[:n] {
+		if s.err != nil {
+			break
+		}
+		if (s.spilled+i)%scrollbackStride == 0 {
+			s.index = append(s.index, s.size)
+		}
+		k := binary.PutUvarint(length[:], uint64(len(line)))
+		s.w.Write(length[:k])
+		_, s.err = s.w.WriteString(line)
+		s.size += int64(k + len(line))
+	}
+	s.spilled += n
+	s.recent = append([]string(nil), s.recent[n:]...)
+}
+
+// Len returns the number of lines.
+func (s *Scrollback) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.spilled + len(s.recent)
+}
+
+// Spilled returns how many lines are on disk, and how many bytes they
+// take.
+func (s *Scrollback) Spilled() (lines int, bytes int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return min(len(s.index)*scrollbackStride, s.spilled), s.size
+}
+
+// Err returns the error that stopped lines spilling, if any. From then on
+// the oldest lines are dropped: Line returns "" for them.
+func (s *Scrollback) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Line returns line i, counting from 0, or "" if there's no such line.
+func (s *Scrollback) Line(i int) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.line(i)
+}
+
+// Lines returns up to n lines from line from on.
+func (s *Scrollback) Lines(from, n int) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	from = max(from, 0)
+	n = min(n, s.spilled+len(s.recent)-from)
+	if n <= 0 {
+		return nil
+	}
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = s.line(from + i)
+	}
+	return lines
+}
+
+// Find returns the first line at or after from that match accepts, or the
+// last at or before it if backward, or -1 if none does. Lines on disk are
+// read a block at a time, so this reads everything in the way.
+func (s *Scrollback) Find(from int, backward bool, match func(string) bool) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := s.spilled + len(s.recent)
+	step := 1
+	if backward {
+		step = -1
+	}
+	for i := from; i >= 0 && i < n; i += step {
+		if match(s.line(i)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Close releases the file. The lines on disk are gone; the ones in memory
+// stay readable.
+func (s *Scrollback) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file, s.w, s.index, s.cached = nil, nil, nil, -1
+	if s.err == nil {
+		s.err = os.ErrClosed
+	}
+	return err
+}
+
+func (s *Scrollback) line(i int) string {
+	if i < 0 || i >= s.spilled+len(s.recent) {
+		return ""
+	}
+	if i >= s.spilled {
+		return s.recent[i-s.spilled]
+	}
+	block := i / scrollbackStride
+	if block >= len(s.index) {
+		return "" // dropped after a failed spill
+	}
+	if block != s.cached {
+		lines, err := s.readBlock(block)
+		if err != nil {
+			return ""
+		}
+		s.cached, s.cachedLines = block, lines
+	}
+	return s.cachedLines[i%scrollbackStride]
+}
+
+func (s *Scrollback) readBlock(block int) ([]string, error) {
+	if err := s.w.Flush(); err != nil {
+		return nil, err
+	}
+	start := s.index[block]
+	r := bufio.NewReader(io.NewSectionReader(s.file, start, s.size-start))
+	lines := make([]string, scrollbackStride)
+	for i := range lines {
+		n, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, n)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		lines[i] = string(buf)
+	}
+	return lines, nil
+}
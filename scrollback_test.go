@@ -0,0 +1,131 @@
+package glyph
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScrollback(t *testing.T) {
+	sb := NewScrollback(100, t.TempDir())
+	defer sb.Close()
+	for i := range 1000 {
+		sb.Append(fmt.Sprintf("line %d %s", i, strings.Repeat("x", i%7)))
+	}
+	if sb.Len() != 1000 {
+		t.Fatalf("len = %d", sb.Len())
+	}
+	lines, bytes := sb.Spilled()
+	if lines < 800 || lines%scrollbackStride != 0 || bytes == 0 {
+		t.Errorf("spilled %d lines, %d bytes", lines, bytes)
+	}
+	if len(sb.recent) >= 100+scrollbackStride {
+		t.Errorf("%d lines in memory", len(sb.recent))
+	}
+	for _, i := range []int{0, 63, 64, 500, lines - 1, lines, 999} {
+		if got, want := sb.Line(i), fmt.Sprintf("line %d %s", i, strings.Repeat("x", i%7)); got != want {
+			t.Errorf("line %d = %q", i, got)
+		}
+	}
+	if got := sb.Lines(995, 10); len(got) != 5 || got[4] != "line 999 xxxxx" {
+		t.Errorf("tail = %q", got)
+	}
+	if got := sb.Line(1000); got != "" {
+		t.Errorf("past the end = %q", got)
+	}
+
+	match := func(line string) bool { return strings.HasPrefix(line, "line 12") }
+	if got := sb.Find(0, false, match); got != 12 {
+		t.Errorf("first match = %d", got)
+	}
+	if got := sb.Find(13, false, match); got != 120 {
+		t.Errorf("next match = %d", got)
+	}
+	if got := sb.Find(999, true, match); got != 129 {
+		t.Errorf("last match = %d", got)
+	}
+	if got := sb.Find(0, false, func(string) bool { return false }); got != -1 {
+		t.Errorf("no match = %d", got)
+	}
+
+	sb.Close()
+	if sb.Line(0) != "" || sb.Line(999) != "line 999 xxxxx" || sb.Err() == nil {
+		t.Errorf("after close: %q %q %v", sb.Line(0), sb.Line(999), sb.Err())
+	}
+}
+
+func TestScrollbackUnwritable(t *testing.T) {
+	sb := NewScrollback(64, t.TempDir()+"/missing")
+	for i := range 300 {
+		sb.Append(fmt.Sprint(i))
+	}
+	if sb.Err() == nil || sb.Len() != 300 || sb.Line(0) != "" || sb.Line(299) != "299" {
+		t.Errorf("err %v len %d first %q last %q", sb.Err(), sb.Len(), sb.Line(0), sb.Line(299))
+	}
+}
+
+func TestLogSpill(t *testing.T) {
+	pr, pw := io.Pipe()
+	lv := Log(pr).MaxLines(64).Spill(t.TempDir()).BindVimNav().Grow(1)
+	tmpl := Build(VBox(lv))
+	for i := range 500 {
+		fmt.Fprintf(pw, "entry %d\n", i)
+	}
+	pw.Close()
+	deadline := time.Now().Add(time.Second)
+	for lv.Scrollback().Len() < 500 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	defer lv.Scrollback().Close()
+
+	frame := func() []string {
+		buf := NewBuffer(20, 4)
+		tmpl.Execute(buf, 20, 4)
+		return []string{buf.GetLine(0), buf.GetLine(3)}
+	}
+	if got := frame(); got[0] != "entry 496" || got[1] != "entry 499" {
+		t.Fatalf("following = %q", got)
+	}
+
+	lv.scrollToTop()
+	if got := frame(); got[0] != "entry 0" {
+		t.Errorf("top = %q", got)
+	}
+	lv.scroll(2)
+	if got := frame(); got[0] != "entry 2" {
+		t.Errorf("scrolled = %q", got)
+	}
+	if !lv.Find("entry 42", false) {
+		t.Fatal("entry 42 not found")
+	}
+	if got := frame(); got[0] != "entry 42" {
+		t.Errorf("found = %q", got)
+	}
+	if lv.Find("entry 1", true); frame()[0] != "entry 19" {
+		t.Errorf("found backward = %q", frame()[0])
+	}
+	if lv.Find("nowhere", false) {
+		t.Error("found a missing line")
+	}
+	lv.resume()
+	if got := frame(); got[1] != "entry 499" {
+		t.Errorf("resumed = %q", got)
+	}
+}
+
+func TestLogFind(t *testing.T) {
+	lv := Log(strings.NewReader("alpha\nbeta\ngamma\nbeta again\ndelta\n")).MaxLines(100)
+	tmpl := Build(VBox(lv))
+	time.Sleep(50 * time.Millisecond)
+	buf := NewBuffer(20, 2)
+	tmpl.Execute(buf, 20, 2)
+	lv.scrollToTop()
+	if !lv.Find("beta", false) || lv.Layer().ScrollY() != 1 {
+		t.Errorf("beta at %d", lv.Layer().ScrollY())
+	}
+	if !lv.Find("beta", false) || lv.Layer().ScrollY() != 3 {
+		t.Errorf("next beta at %d", lv.Layer().ScrollY())
+	}
+}
@@ -0,0 +1,70 @@
+package glyph
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// ShutdownReason says why an app stopped.
+type ShutdownReason struct {
+	Signal os.Signal // SIGINT, SIGTERM or SIGHUP; nil when Stop was called
+}
+
+func (r ShutdownReason) String() string {
+	if r.Signal != nil {
+		return r.Signal.String()
+	}
+	return "stop"
+}
+
+// OnShutdown registers fn to run once the app stops, whether through Stop
+// or SIGINT, SIGTERM or SIGHUP. Hooks run in registration order after the
+// terminal has been restored and the app's context cancelled, so they can
+// print, save session state, or wait for background work to wind down.
+func (a *App) OnShutdown(fn func(reason ShutdownReason)) *App {
+	a.onShutdown = append(a.onShutdown, fn)
+	return a
+}
+
+// watchSignals turns termination signals into a graceful Stop for as long
+// as the app runs. Only the first is caught: after it the signals get their
+// default action back, so a second Ctrl-C still ends an app whose shutdown
+// hangs. The returned func stops watching.
+func (a *App) watchSignals() func() {
+	if a.signals == nil {
+		a.signals = make(chan os.Signal, 1)
+	}
+	signal.Notify(a.signals, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case sig := <-a.signals:
+			signal.Stop(a.signals)
+			a.stop(ShutdownReason{Signal: sig})
+		case <-done:
+		}
+	}()
+	return func() {
+		signal.Stop(a.signals)
+		close(done)
+	}
+}
+
+// shutdown runs the OnShutdown hooks. It's deferred first in run so it
+// happens after everything else has been torn down.
+func (a *App) shutdown() {
+	a.Context()
+	a.cancel()
+
+	a.postMu.Lock()
+	reason := a.stopReason
+	a.stopReason = nil
+	a.postMu.Unlock()
+	if reason == nil {
+		reason = &ShutdownReason{}
+	}
+	for _, fn := range a.onShutdown {
+		fn(*reason)
+	}
+}
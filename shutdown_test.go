@@ -0,0 +1,168 @@
+package glyph
+
+import (
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func runWithBackend(t *testing.T, app *App, be *BufferBackend) <-chan error {
+	t.Helper()
+	done := make(chan error, 1)
+	go func() { done <- app.Run() }()
+	if !be.WaitFor(func(b *Buffer) bool { return strings.TrimSpace(b.GetLine(0)) != "" }, time.Second) {
+		t.Fatal("app never rendered")
+	}
+	return done
+}
+
+func TestOnShutdownStop(t *testing.T) {
+	app, err := NewApp()
+	if err != nil {
+		t.Fatal(err)
+	}
+	be := NewBufferBackend(10, 2)
+	app.SetBackend(be).SetView(Text("hi"))
+
+	var reasons []ShutdownReason
+	ctxDone := false
+	app.OnShutdown(func(r ShutdownReason) {
+		reasons = append(reasons, r)
+		ctxDone = app.Context().Err() != nil
+	})
+
+	done := runWithBackend(t, app, be)
+	app.Stop()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return")
+	}
+	if len(reasons) != 1 || reasons[0].Signal != nil || reasons[0].String() != "stop" {
+		t.Errorf("reasons = %v, want one stop", reasons)
+	}
+	if !ctxDone {
+		t.Error("context should be cancelled before hooks run")
+	}
+}
+
+func TestOnShutdownSignal(t *testing.T) {
+	app, err := NewApp()
+	if err != nil {
+		t.Fatal(err)
+	}
+	be := NewBufferBackend(10, 2)
+	app.SetBackend(be).SetView(Text("hi"))
+
+	got := make(chan ShutdownReason, 2)
+	app.OnShutdown(func(r ShutdownReason) { got <- r })
+
+	done := runWithBackend(t, app, be)
+	app.signals <- syscall.SIGTERM
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after SIGTERM")
+	}
+	if r := <-got; r.Signal != syscall.SIGTERM {
+		t.Errorf("reason = %v, want terminated", r)
+	}
+	if len(got) != 0 {
+		t.Error("hooks ran more than once")
+	}
+}
+
+func TestSecondSignalNotCaught(t *testing.T) {
+	app, err := NewApp()
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.SetBackend(NewBufferBackend(10, 2))
+
+	// keep the process alive when the app lets go of SIGHUP
+	other := make(chan os.Signal, 1)
+	signal.Notify(other, syscall.SIGHUP)
+	defer signal.Stop(other)
+
+	unwatch := app.watchSignals()
+	defer unwatch()
+	app.signals <- syscall.SIGTERM
+	deadline := time.Now().Add(time.Second)
+	for {
+		app.postMu.Lock()
+		stopped := app.stopReason != nil
+		app.postMu.Unlock()
+		if stopped {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("first signal not handled")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	// shutdown is under way but Run hasn't returned: a second signal must
+	// get its default action rather than vanish into the app's channel
+	syscall.Kill(os.Getpid(), syscall.SIGHUP)
+	select {
+	case <-other:
+	case <-time.After(time.Second):
+		t.Fatal("SIGHUP not delivered")
+	}
+	if len(app.signals) != 0 {
+		t.Error("second signal was swallowed by the app")
+	}
+}
+
+func TestRunAgain(t *testing.T) {
+	app, err := NewApp()
+	if err != nil {
+		t.Fatal(err)
+	}
+	resized := make(chan Size, 4)
+	app.OnResize(func(w, h int) { resized <- Size{Width: w, Height: h} })
+	app.SetView(Text("hi"))
+
+	first := NewBufferBackend(10, 2)
+	app.SetBackend(first)
+	done := runWithBackend(t, app, first)
+	ctx := app.Context()
+	app.Stop()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if ctx.Err() == nil {
+		t.Fatal("first run's context not cancelled")
+	}
+
+	// the stopped run no longer drains its backend's resizes
+	first.Resize(20, 4)
+	select {
+	case s := <-resized:
+		t.Errorf("stopped run handled a resize to %v", s)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	second := NewBufferBackend(10, 2)
+	app.SetBackend(second)
+	done = runWithBackend(t, app, second)
+	if app.Context().Err() != nil {
+		t.Error("second run's context already cancelled")
+	}
+	second.Resize(30, 5)
+	select {
+	case s := <-resized:
+		if s != (Size{Width: 30, Height: 5}) {
+			t.Errorf("resize = %v", s)
+		}
+	case <-time.After(time.Second):
+		t.Error("second run didn't handle a resize")
+	}
+	app.Stop()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,104 @@
+package glyph
+
+import "time"
+
+// SkeletonC stands in for content that hasn't loaded yet: grey bars the
+// size of what's coming, with a lighter band sweeping across them.
+//
+//	Skeleton(24, 40, 16) // three lines of text, 24, 40 and 16 cells wide
+//	Skeleton(0, 0)       // two lines as wide as there is room
+//
+// Below QualityFull the bars are drawn without the shimmer.
+type SkeletonC struct {
+	ticking
+	widths []int16
+	base   Color
+	shine  Color
+	period time.Duration
+	still  bool
+}
+
+// skeletonBand is how many cells the shimmer band spans.
+const skeletonBand = 8
+
+// Skeleton creates one bar per width, a row each; 0 fills the row. With no
+// widths it is a single full row.
+func Skeleton(widths ...int16) *SkeletonC {
+	if len(widths) == 0 {
+		widths = []int16{0}
+	}
+	return &SkeletonC{
+		widths: widths,
+		base:   RGB(58, 58, 58),
+		shine:  RGB(96, 96, 96),
+		period: 1200 * time.Millisecond,
+	}
+}
+
+// Colors sets the bars' colour and the shimmer's. Dark greys by default.
+func (s *SkeletonC) Colors(base, shine Color) *SkeletonC {
+	s.base, s.shine = base, shine
+	return s
+}
+
+// Period sets how long the shimmer takes to cross. 1.2s by default; 0
+// turns it off.
+func (s *SkeletonC) Period(d time.Duration) *SkeletonC {
+	s.period = d
+	return s
+}
+
+func (s *SkeletonC) setQuality(q Quality) { s.still = q > QualityFull }
+
+func (t *Template) compileSkeletonC(s *SkeletonC, parent int16, depth int) int16 {
+	t.collectUpdateHook(s)
+	t.collectClockUser(s)
+	t.collectQualityUser(s)
+	return t.compileCustom(Widget(s.measure, s.render), parent, depth)
+}
+
+func (s *SkeletonC) measure(availW int16) (w, h int16) {
+	for _, bw := range s.widths {
+		if bw == 0 {
+			if availW < 0 {
+				return -1, int16(len(s.widths)) // fill
+			}
+			w = availW
+		}
+		w = max(w, bw)
+	}
+	if availW >= 0 {
+		w = min(w, availW)
+	}
+	return w, int16(len(s.widths))
+}
+
+func (s *SkeletonC) render(buf *Buffer, x, y, w, h int16) {
+	// the band's centre starts off the left edge and leaves off the right
+	shimmer := !s.still && s.period > 0
+	centre := 0
+	if shimmer {
+		span := int64(w) + skeletonBand
+		at := s.now().UnixNano() % int64(s.period)
+		centre = int(at*span/int64(s.period)) - skeletonBand/2
+		s.after(s.period / time.Duration(span))
+	}
+	base := rgbColor(s.base, false)
+	shine := rgbColor(s.shine, false)
+	for row, bw := range s.widths[:min(len(s.widths), int(h))] {
+		if bw == 0 || bw > w {
+			bw = w
+		}
+		for i := range int(bw) {
+			bg := s.base
+			d := i - centre
+			if d < 0 {
+				d = -d
+			}
+			if shimmer && d < skeletonBand/2 {
+				bg = LerpColor(base, shine, 1-float64(d)/(skeletonBand/2))
+			}
+			buf.Set(int(x)+i, int(y)+row, Cell{Rune: ' ', Style: Style{BG: bg}})
+		}
+	}
+}
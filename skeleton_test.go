@@ -0,0 +1,53 @@
+package glyph
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSkeleton(t *testing.T) {
+	start := time.Unix(0, 0)
+	clock := NewFakeClock(start)
+	renders := 0
+
+	tmpl := Build(VBox(Skeleton(6, 0), Text("end")))
+	tmpl.SetClock(clock)
+	for _, u := range tmpl.pendingUpdates {
+		*u = func() { renders++ }
+	}
+	frame := func() *Buffer {
+		buf := NewBuffer(12, 3)
+		tmpl.Execute(buf, 12, 3)
+		return buf
+	}
+
+	buf := frame()
+	if buf.GetLine(2) != "end" {
+		t.Fatalf("skeleton not two rows:\n%s", buf.String())
+	}
+	for x := range 12 {
+		want := x < 6
+		if got := buf.Get(x, 0).Style.BG.Mode != ColorDefault; got != want {
+			t.Errorf("row 0 cell %d barred = %v", x, got)
+		}
+		if buf.Get(x, 1).Style.BG.Mode == ColorDefault {
+			t.Errorf("row 1 cell %d not barred", x)
+		}
+	}
+
+	// mid-period the band is in the middle of the row, lighter than the ends
+	clock.Advance(600 * time.Millisecond)
+	if renders == 0 {
+		t.Fatal("shimmer didn't ask for a frame")
+	}
+	buf = frame()
+	if mid, end := buf.Get(6, 1).Style.BG, buf.Get(0, 1).Style.BG; mid.R <= end.R {
+		t.Errorf("band not mid-row: middle %v, end %v", mid, end)
+	}
+
+	tmpl.SetQuality(QualityReduced)
+	buf = frame()
+	if buf.Get(6, 1).Style.BG != buf.Get(0, 1).Style.BG {
+		t.Error("shimmer drawn below full quality")
+	}
+}
@@ -0,0 +1,114 @@
+package glyph
+
+import (
+	"strconv"
+	"unsafe"
+)
+
+// SpanWriter builds a line of styled spans by appending, reusing its
+// memory from one frame to the next, so a line rebuilt every frame costs no
+// allocations once it has reached its longest:
+//
+//	var line SpanWriter
+//	app.OnBeforeRender(func() {
+//	    line.Reset()
+//	    line.Text("cpu ", Style{Attr: AttrDim}).Float(cpu, 1, Style{FG: Green}).Text("%", Style{})
+//	    line.Text("  conns ", Style{Attr: AttrDim}).Int(conns, Style{})
+//	})
+//	app.SetView(line.Rich())
+//
+// Numbers are formatted straight into the writer, without fmt. Spans
+// returned by Spans, and the text of their numbers, are valid until the
+// next Reset.
+type SpanWriter struct {
+	spans []Span
+	text  []byte // formatted numbers, which spans point into
+}
+
+// Reset empties the writer, keeping its memory.
+func (w *SpanWriter) Reset() *SpanWriter {
+	w.spans = w.spans[:0]
+	w.text = w.text[:0]
+	return w
+}
+
+// Text appends s in style.
+func (w *SpanWriter) Text(s string, style Style) *SpanWriter {
+	w.spans = append(w.spans, Span{Text: s, Style: style})
+	return w
+}
+
+// Span appends s.
+func (w *SpanWriter) Span(s Span) *SpanWriter {
+	w.spans = append(w.spans, s)
+	return w
+}
+
+// Int appends n in decimal.
+func (w *SpanWriter) Int(n int, style Style) *SpanWriter {
+	return w.number(strconv.AppendInt(w.text, int64(n), 10), style)
+}
+
+// Float appends f with prec digits after the point.
+func (w *SpanWriter) Float(f float64, prec int, style Style) *SpanWriter {
+	return w.number(strconv.AppendFloat(w.text, f, 'f', prec, 64), style)
+}
+
+// number appends the digits added to the end of w.text as a span. If
+// appending them moved w.text to a bigger array, earlier spans still point
+// into the old one, which stays alive while they do.
+func (w *SpanWriter) number(text []byte, style Style) *SpanWriter {
+	start := len(w.text)
+	w.text = text
+	digits := text[start:]
+	return w.Text(unsafe.String(unsafe.SliceData(digits), len(digits)), style)
+}
+
+// Spans returns the spans written since the last Reset.
+func (w *SpanWriter) Spans() []Span {
+	return w.spans
+}
+
+// Len returns the number of spans written since the last Reset.
+func (w *SpanWriter) Len() int {
+	return len(w.spans)
+}
+
+// Rich returns a RichText showing the writer's spans as they are each
+// frame.
+func (w *SpanWriter) Rich() RichTextNode {
+	return RichTextNode{Spans: &w.spans}
+}
+
+// WriteInt writes n in decimal at x, y, without allocating, and returns the
+// number of cells written.
+func (b *Buffer) WriteInt(x, y, n int, style Style, maxWidth int) int {
+	var scratch [20]byte
+	digits := strconv.AppendInt(scratch[:0], int64(n), 10)
+	return b.writeASCII(x, y, digits, style, maxWidth)
+}
+
+// WriteFloat writes f with prec digits after the point at x, y, without
+// allocating, and returns the number of cells written.
+func (b *Buffer) WriteFloat(x, y int, f float64, prec int, style Style, maxWidth int) int {
+	var scratch [32]byte
+	digits := strconv.AppendFloat(scratch[:0], f, 'f', prec, 64)
+	return b.writeASCII(x, y, digits, style, maxWidth)
+}
+
+// writeASCII writes single-byte characters into a run of cells.
+func (b *Buffer) writeASCII(x, y int, s []byte, style Style, maxWidth int) int {
+	if y < 0 || y >= b.height {
+		return 0
+	}
+	if y > b.dirtyMaxY {
+		b.dirtyMaxY = y
+	}
+	b.dirtyRows[y] = true
+	n := min(len(s), maxWidth, b.width-x)
+	row := b.cells[y*b.width:][:b.width]
+	for i := max(0, -x); i < n; i++ {
+		row[x+i] = Cell{Rune: rune(s[i]), Style: style}
+	}
+	return max(n, 0)
+}
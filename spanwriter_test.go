@@ -0,0 +1,90 @@
+package glyph
+
+import "testing"
+
+func TestSpanWriter(t *testing.T) {
+	var w SpanWriter
+	app, _ := NewApp()
+	be := NewBufferBackend(30, 1)
+	app.SetBackend(be)
+	app.SetView(w.Rich())
+
+	for i, want := range []string{"cpu 12.5% conns -3", "cpu 100.0% conns 1234567"} {
+		cpu, conns := []float64{12.5, 100}[i], []int{-3, 1234567}[i]
+		w.Reset()
+		w.Text("cpu ", Style{Attr: AttrDim}).Float(cpu, 1, Style{FG: Green}).Text("%", Style{})
+		w.Text(" conns ", Style{}).Int(conns, Style{FG: Red})
+		app.RenderNow()
+		if got := be.Frame().GetLine(0); got != want {
+			t.Errorf("frame %d = %q, want %q", i, got, want)
+		}
+	}
+	if c := be.Frame().Get(4, 0); c.Style.FG != Green {
+		t.Errorf("number style = %+v", c.Style)
+	}
+}
+
+func TestWriteNumbers(t *testing.T) {
+	buf := NewBuffer(10, 2)
+	if n := buf.WriteInt(1, 0, -42, Style{}, 10); n != 3 {
+		t.Errorf("WriteInt wrote %d cells", n)
+	}
+	buf.WriteFloat(-2, 1, 3.14159, 2, Style{}, 10)
+	if got := buf.GetLine(0); got != " -42" {
+		t.Errorf("WriteInt = %q", got)
+	}
+	if got := buf.GetLine(1); got != "14" {
+		t.Errorf("WriteFloat clipped at the left = %q", got)
+	}
+	if n := buf.WriteInt(8, 0, 12345, Style{}, 10); n != 2 {
+		t.Errorf("WriteInt past the edge wrote %d cells", n)
+	}
+}
+
+// The render path for text bound to strings, and for a SpanWriter rebuilt
+// each frame, allocates nothing once warm.
+func TestTextRenderAllocs(t *testing.T) {
+	title, status := "dashboard", "ok"
+	var w SpanWriter
+	n := 0
+	tmpl := Build(VBox(
+		Text(&title),
+		Textf("status: ", &status, "!"),
+		w.Rich(),
+	))
+	buf := NewBuffer(40, 3)
+	frame := func() {
+		n++
+		w.Reset()
+		w.Text("req ", Style{}).Int(n, Style{FG: Cyan}).Text(" p99 ", Style{}).Float(float64(n)/7, 2, Style{})
+		buf.Clear()
+		tmpl.Execute(buf, 40, 3)
+	}
+	frame()
+	if allocs := testing.AllocsPerRun(100, frame); allocs != 0 {
+		t.Errorf("text render allocated %v times a frame", allocs)
+	}
+}
+
+func BenchmarkSpanWriterRender(b *testing.B) {
+	var w SpanWriter
+	tmpl := Build(w.Rich())
+	buf := NewBuffer(80, 1)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		w.Reset()
+		w.Text("cpu ", Style{Attr: AttrDim}).Float(float64(i%1000)/10, 1, Style{FG: Green}).Text("%", Style{})
+		w.Text("  conns ", Style{Attr: AttrDim}).Int(i, Style{})
+		tmpl.Execute(buf, 80, 1)
+	}
+}
+
+func BenchmarkTextfRender(b *testing.B) {
+	status := "ok"
+	tmpl := Build(Textf("status: ", &status, " (", Bold(&status), ")"))
+	buf := NewBuffer(80, 1)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		tmpl.Execute(buf, 80, 1)
+	}
+}
@@ -0,0 +1,664 @@
+package glyph
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/kungfusheep/riffkey"
+	"github.com/mattn/go-runewidth"
+)
+
+// PaneC is one pane of a SplitPane: a titled, bordered area with its own
+// keys. A pane whose content is another SplitPane nests that split in its
+// place, with no border of its own.
+type PaneC struct {
+	title   string
+	content any
+	grow    float32
+
+	declaredBindings []binding
+	sub              *Template   // compiled content, nil for a nested split
+	split            *SplitPaneC // nested split, if the content is one
+	input            *riffkey.Input
+	x, y, w, h       int16 // area from the last render, in the split's own coordinates
+
+	floating       bool
+	fx, fy, fw, fh int16 // floating position and size; zero size until placed
+	z              int   // stacking order among floating panes
+}
+
+// Pane creates a pane showing content under title.
+func Pane(title string, content any) *PaneC {
+	return &PaneC{title: title, content: content, grow: 1}
+}
+
+// Grow sets the pane's share of the split. 1 by default.
+func (p *PaneC) Grow(g float32) *PaneC {
+	p.grow = g
+	return p
+}
+
+// Floating starts the pane detached from the split, as a window at x, y
+// of w by h cells over the other panes.
+func (p *PaneC) Floating(x, y, w, h int16) *PaneC {
+	p.floating = true
+	p.fx, p.fy, p.fw, p.fh = x, y, w, h
+	return p
+}
+
+// IsFloating reports whether the pane is detached from the split.
+func (p *PaneC) IsFloating() bool {
+	return p.floating
+}
+
+// Handle registers a key that only works while this pane has focus. The
+// handler is a func(riffkey.Match), func(any) or func(), as for App.Handle.
+func (p *PaneC) Handle(pattern string, handler any) *PaneC {
+	p.declaredBindings = append(p.declaredBindings, binding{pattern, handler})
+	return p
+}
+
+// SplitPaneC lays panes out side by side, or stacked with Vertical, and
+// routes keys to the focused one. Keys bound inside a pane's content, such
+// as a TextView's BindVimNav, and keys from Pane.Handle are consulted
+// first; whatever the focused pane doesn't use falls through to the view's
+// own bindings. So an editor pane and a log pane can both use j and k.
+//
+//	SplitPane(
+//	    Pane("files", fileTree).Grow(1),
+//	    Pane("", SplitPane(
+//	        Pane("editor", editor).Grow(3),
+//	        Pane("log", TextView(&logLines).BindVimNav()),
+//	    ).Vertical()).Grow(3),
+//	)
+//
+// Focus moves vim-style: <C-w> then h/j/k/l or the arrows for the pane in
+// that direction, w and W for the next and previous pane, and z to zoom the
+// focused pane to fill the split until z is pressed again.
+//
+// f detaches the focused pane into a floating window over the others, or
+// docks it back. A floating pane keeps its place in the focus order, and
+// focusing one raises it above the rest; <C-w> then H/J/K/L moves it and
+// > < + - resize it. Nested splits take their borders and styles from the
+// outermost one.
+type SplitPaneC struct {
+	panes    []*PaneC
+	vertical bool
+	focus    int
+	parent   *SplitPaneC
+
+	layer  *Layer
+	tmpl   *Template // the template the split was compiled into
+	grow   float32
+	margin [4]int16
+
+	border     BorderStyle
+	style      Style
+	focusStyle Style
+	navKey     string
+	zoomMark   string
+	zoomed     *PaneC
+	zTop       int // highest floating z so far
+
+	router *riffkey.Router
+	nav    *riffkey.Input // <C-w> keys
+	base   *riffkey.Input // the view's router, for keys no pane used
+}
+
+// SplitPane creates a split of panes side by side.
+func SplitPane(panes ...*PaneC) *SplitPaneC {
+	sp := &SplitPaneC{
+		panes:      panes,
+		layer:      NewLayer(),
+		grow:       1,
+		border:     BorderSingle,
+		style:      Style{FG: BrightBlack},
+		focusStyle: Style{FG: Cyan},
+		navKey:     "<C-w>",
+		zoomMark:   "[Z]",
+	}
+	sp.layer.AlwaysRender = true
+	sp.layer.Render = sp.sync
+	return sp
+}
+
+// Vertical stacks the panes top to bottom.
+func (sp *SplitPaneC) Vertical() *SplitPaneC {
+	sp.vertical = true
+	return sp
+}
+
+// Border sets the pane border. BorderSingle by default.
+func (sp *SplitPaneC) Border(b BorderStyle) *SplitPaneC {
+	sp.border = b
+	return sp
+}
+
+// Style sets the border and title style of panes without focus.
+func (sp *SplitPaneC) Style(s Style) *SplitPaneC {
+	sp.style = s
+	return sp
+}
+
+// FocusStyle sets the border and title style of the focused pane.
+func (sp *SplitPaneC) FocusStyle(s Style) *SplitPaneC {
+	sp.focusStyle = s
+	return sp
+}
+
+// NavKey sets the prefix for pane navigation. "<C-w>" by default; "" turns
+// it off.
+func (sp *SplitPaneC) NavKey(prefix string) *SplitPaneC {
+	sp.navKey = prefix
+	return sp
+}
+
+// ZoomMark sets what follows a zoomed pane's title. "[Z]" by default.
+func (sp *SplitPaneC) ZoomMark(mark string) *SplitPaneC {
+	sp.zoomMark = mark
+	return sp
+}
+
+// Grow sets the flex grow factor. 1 by default.
+func (sp *SplitPaneC) Grow(g float32) *SplitPaneC {
+	sp.grow = g
+	return sp
+}
+
+// Margin sets equal margin on all sides.
+func (sp *SplitPaneC) Margin(all int16) *SplitPaneC {
+	sp.margin = [4]int16{all, all, all, all}
+	return sp
+}
+
+// MarginVH sets vertical and horizontal margins.
+func (sp *SplitPaneC) MarginVH(v, h int16) *SplitPaneC {
+	sp.margin = [4]int16{v, h, v, h}
+	return sp
+}
+
+// MarginTRBL sets top, right, bottom, left margins individually.
+func (sp *SplitPaneC) MarginTRBL(t, r, b, l int16) *SplitPaneC {
+	sp.margin = [4]int16{t, r, b, l}
+	return sp
+}
+
+// root returns the outermost split, which holds the styles and routing.
+func (sp *SplitPaneC) root() *SplitPaneC {
+	for sp.parent != nil {
+		sp = sp.parent
+	}
+	return sp
+}
+
+// Focused returns the pane with focus, looking through nested splits.
+func (sp *SplitPaneC) Focused() *PaneC {
+	path := sp.focusPath()
+	if len(path) == 0 {
+		return nil
+	}
+	return path[len(path)-1]
+}
+
+// focusPath is the chain of focused panes from this split down to a leaf.
+func (sp *SplitPaneC) focusPath() []*PaneC {
+	var path []*PaneC
+	for s := sp; s != nil && len(s.panes) > 0; {
+		p := s.panes[min(s.focus, len(s.panes)-1)]
+		path = append(path, p)
+		s = p.split
+	}
+	return path
+}
+
+// Focus gives focus to p, which may sit in a nested split. Focusing
+// another pane ends a zoom, and focusing a floating pane raises it.
+func (sp *SplitPaneC) Focus(p *PaneC) {
+	r := sp.root()
+	if r.zoomed != nil && r.zoomed != p {
+		r.zoomed = nil
+	}
+	if p.floating && (p.z == 0 || p.z < r.zTop) {
+		r.raise(p)
+	}
+	for i, q := range sp.panes {
+		if q == p || (q.split != nil && q.split.contains(p)) {
+			sp.focus = i
+			if q.split != nil {
+				q.split.Focus(p)
+			}
+			return
+		}
+	}
+}
+
+func (sp *SplitPaneC) contains(p *PaneC) bool {
+	for _, q := range sp.panes {
+		if q == p || (q.split != nil && q.split.contains(p)) {
+			return true
+		}
+	}
+	return false
+}
+
+// leaves returns the panes that hold content, in order.
+func (sp *SplitPaneC) leaves() []*PaneC {
+	var out []*PaneC
+	for _, p := range sp.panes {
+		if p.split != nil {
+			out = append(out, p.split.leaves()...)
+		} else {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// FocusNext moves focus to the next pane, wrapping.
+func (sp *SplitPaneC) FocusNext() { sp.focusStep(1) }
+
+// FocusPrev moves focus to the previous pane, wrapping.
+func (sp *SplitPaneC) FocusPrev() { sp.focusStep(-1) }
+
+func (sp *SplitPaneC) focusStep(dir int) {
+	leaves := sp.leaves()
+	cur := sp.Focused()
+	for i, p := range leaves {
+		if p == cur {
+			sp.Focus(leaves[(i+dir+len(leaves))%len(leaves)])
+			return
+		}
+	}
+}
+
+// FocusDir moves focus to the nearest pane in a direction: dx or dy is -1
+// or 1. It does nothing at the edge.
+func (sp *SplitPaneC) FocusDir(dx, dy int) {
+	cur := sp.Focused()
+	if cur == nil {
+		return
+	}
+	var best *PaneC
+	bestScore := int(^uint(0) >> 1)
+	for _, p := range sp.leaves() {
+		if p == cur {
+			continue
+		}
+		// gap along the direction, and how far off the line the pane sits
+		var gap, off int
+		switch {
+		case dx > 0:
+			gap, off = int(p.x-(cur.x+cur.w)), centreOffset(p.y, p.h, cur.y, cur.h)
+		case dx < 0:
+			gap, off = int(cur.x-(p.x+p.w)), centreOffset(p.y, p.h, cur.y, cur.h)
+		case dy > 0:
+			gap, off = int(p.y-(cur.y+cur.h)), centreOffset(p.x, p.w, cur.x, cur.w)
+		case dy < 0:
+			gap, off = int(cur.y-(p.y+p.h)), centreOffset(p.x, p.w, cur.x, cur.w)
+		}
+		if gap < 0 {
+			continue
+		}
+		if score := gap*1000 + off; score < bestScore {
+			best, bestScore = p, score
+		}
+	}
+	if best != nil {
+		sp.Focus(best)
+	}
+}
+
+// ToggleZoom zooms the focused pane to fill the split, or restores the
+// layout if a pane is zoomed.
+func (sp *SplitPaneC) ToggleZoom() {
+	r := sp.root()
+	if r.zoomed != nil {
+		r.zoomed = nil
+		return
+	}
+	r.zoomed = r.Focused()
+}
+
+// Zoomed returns the zoomed pane, or nil.
+func (sp *SplitPaneC) Zoomed() *PaneC {
+	return sp.root().zoomed
+}
+
+// ToggleFloat detaches the focused pane into a floating window, or docks
+// it back into the split.
+func (sp *SplitPaneC) ToggleFloat() {
+	p := sp.Focused()
+	if p == nil {
+		return
+	}
+	if p.floating {
+		sp.Dock(p)
+	} else {
+		sp.Float(p)
+	}
+}
+
+// Float detaches p into a floating window. It reopens where it was last,
+// or centred at half the split's size the first time.
+func (sp *SplitPaneC) Float(p *PaneC) {
+	r := sp.root()
+	if r.zoomed == p {
+		r.zoomed = nil
+	}
+	p.floating = true
+	r.raise(p)
+}
+
+// Dock puts a floating pane back in its place in the split.
+func (sp *SplitPaneC) Dock(p *PaneC) {
+	p.floating = false
+}
+
+// MoveFloat moves the focused pane, if it's floating, by dx and dy cells.
+// It stays within the split.
+func (sp *SplitPaneC) MoveFloat(dx, dy int16) {
+	if p := sp.Focused(); p != nil && p.floating {
+		p.fx += dx
+		p.fy += dy
+	}
+}
+
+// ResizeFloat grows the focused pane, if it's floating, by dw and dh
+// cells, or shrinks it for negative values.
+func (sp *SplitPaneC) ResizeFloat(dw, dh int16) {
+	if p := sp.Focused(); p != nil && p.floating && p.fw > 0 {
+		p.fw = max(p.fw+dw, 3)
+		p.fh = max(p.fh+dh, 3)
+	}
+}
+
+func (sp *SplitPaneC) raise(p *PaneC) {
+	sp.zTop++
+	p.z = sp.zTop
+}
+
+// floats returns the floating panes from bottom to top.
+func (sp *SplitPaneC) floats() []*PaneC {
+	var out []*PaneC
+	for _, p := range sp.leaves() {
+		if p.floating {
+			out = append(out, p)
+		}
+	}
+	slices.SortStableFunc(out, func(a, b *PaneC) int { return a.z - b.z })
+	return out
+}
+
+// placeFloats gives new floating panes a size and keeps them all within
+// the w by h area.
+func (sp *SplitPaneC) placeFloats(w, h int16) {
+	for _, p := range sp.floats() {
+		if p.fw <= 0 || p.fh <= 0 {
+			p.fw, p.fh = max(w/2, 3), max(h/2, 3)
+			p.fx, p.fy = (w-p.fw)/2, (h-p.fh)/2
+		}
+		p.fw, p.fh = min(p.fw, w), min(p.fh, h)
+		p.fx = max(min(p.fx, w-p.fw), 0)
+		p.fy = max(min(p.fy, h-p.fh), 0)
+		p.x, p.y, p.w, p.h = p.fx, p.fy, p.fw, p.fh
+	}
+}
+
+// centreOffset is the distance between the midpoints of two spans.
+func centreOffset(a, aw, b, bw int16) int {
+	d := int(2*a+aw) - int(2*b+bw)
+	if d < 0 {
+		d = -d
+	}
+	return d / 2
+}
+
+func (t *Template) compileSplitPaneC(sp *SplitPaneC, parent int16, depth int) int16 {
+	sp.tmpl = t
+	sp.compilePanes(t)
+	layerView := LayerView(sp.layer).Grow(sp.grow)
+	if sp.margin != [4]int16{} {
+		layerView = layerView.MarginTRBL(sp.margin[0], sp.margin[1], sp.margin[2], sp.margin[3])
+	}
+	return t.compileLayerViewC(layerView, parent, depth)
+}
+
+// compilePanes compiles each pane's content on its own, so its key
+// bindings stay with the pane rather than joining the view's.
+func (sp *SplitPaneC) compilePanes(t *Template) {
+	for _, p := range sp.panes {
+		if inner, ok := p.content.(*SplitPaneC); ok {
+			inner.parent = sp
+			p.split = inner
+			inner.compilePanes(t)
+			continue
+		}
+		p.sub = newSubTemplate(VBox(p.content), nil, 0)
+		t.adoptLive(p.sub)
+	}
+}
+
+func (sp *SplitPaneC) sync() {
+	w := sp.layer.ViewportWidth()
+	h := sp.layer.ViewportHeight()
+	if w <= 0 || h <= 0 {
+		return
+	}
+	buf := sp.layer.Buffer()
+	if buf == nil || buf.Width() != w || buf.Height() != h {
+		buf = NewBuffer(w, h)
+		sp.layer.SetBuffer(buf)
+	} else {
+		buf.Clear()
+	}
+	sp.layout(0, 0, int16(w), int16(h))
+	sp.placeFloats(int16(w), int16(h))
+
+	// docked panes first, then floating ones over them in stacking order.
+	// A zoomed pane takes the whole area; the layout is kept for FocusDir
+	var drawn []*PaneC
+	for _, p := range sp.leaves() {
+		if !p.floating {
+			drawn = append(drawn, p)
+		}
+	}
+	drawn = append(drawn, sp.floats()...)
+	if sp.zoomed != nil {
+		drawn = []*PaneC{sp.zoomed}
+	}
+	focused := sp.Focused()
+	for _, p := range drawn {
+		style := sp.style
+		if p == focused {
+			style = sp.focusStyle
+		}
+		title := p.title
+		x, y, pw, ph := p.x, p.y, p.w, p.h
+		if p == sp.zoomed {
+			x, y, pw, ph = 0, 0, int16(w), int16(h)
+			if sp.zoomMark != "" {
+				title = strings.TrimSpace(title + " " + sp.zoomMark)
+			}
+		}
+		if p.floating {
+			buf.FillRect(int(x), int(y), int(pw), int(ph), Cell{Rune: ' '})
+		}
+		sp.drawPane(buf, p, title, x, y, pw, ph, style)
+	}
+
+	// menus and other floating content opened inside a pane
+	for _, p := range drawn {
+		p.sub.renderOverlays(buf, int16(w), int16(h))
+	}
+}
+
+// layout divides x, y, w, h between the panes, through nested splits.
+func (sp *SplitPaneC) layout(x, y, w, h int16) {
+	total := w
+	if sp.vertical {
+		total = h
+	}
+	sizes := splitSizes(total, sp.panes)
+	at := int16(0)
+	for i, p := range sp.panes {
+		if p.floating {
+			continue
+		}
+		p.x, p.y, p.w, p.h = x+at, y, sizes[i], h
+		if sp.vertical {
+			p.x, p.y, p.w, p.h = x, y+at, w, sizes[i]
+		}
+		at += sizes[i]
+		if p.split != nil {
+			p.split.layout(p.x, p.y, p.w, p.h)
+		}
+	}
+}
+
+// splitSizes divides total between the docked panes by their grow
+// weights, the rounding remainder going to the last.
+func splitSizes(total int16, panes []*PaneC) []int16 {
+	sizes := make([]int16, len(panes))
+	var weight float32
+	last := -1
+	for i, p := range panes {
+		if !p.floating {
+			weight += p.grow
+			last = i
+		}
+	}
+	if weight <= 0 {
+		return sizes
+	}
+	used := int16(0)
+	for i, p := range panes {
+		if p.floating {
+			continue
+		}
+		if i == last {
+			sizes[i] = total - used
+			break
+		}
+		sizes[i] = int16(float32(total) * p.grow / weight)
+		used += sizes[i]
+	}
+	return sizes
+}
+
+func (sp *SplitPaneC) drawPane(buf *Buffer, p *PaneC, title string, px, py, pw, ph int16, style Style) {
+	x, y, w, h := int(px), int(py), int(pw), int(ph)
+	if w < 2 || h < 2 {
+		return
+	}
+	buf.DrawBorder(x, y, w, h, sp.border, style)
+	if title != "" && w > 5 {
+		title = " " + title + " "
+		buf.WriteStringFast(x+2, y, title, style, min(runewidth.StringWidth(title), w-3))
+	}
+
+	iw, ih := pw-2, ph-2
+	if iw <= 0 || ih <= 0 {
+		return
+	}
+	if sp.tmpl != nil {
+		p.sub.app = sp.tmpl.app
+	}
+	p.sub.distributeWidths(iw, nil)
+	p.sub.layout(ih)
+	p.sub.distributeFlexGrow(ih)
+	p.sub.clipMaxY = py + 1 + ih
+	p.sub.render(buf, px+1, py+1, iw)
+}
+
+// attachRouter pushes a router that offers each key to the pane
+// navigation, then the focused pane and any split around it, and last to
+// base, the view's own router.
+func (sp *SplitPaneC) attachRouter(base *riffkey.Router, push func(*riffkey.Router)) {
+	sp.base = riffkey.NewInput(base)
+
+	nav := riffkey.NewRouter().NoCounts()
+	if sp.navKey != "" {
+		dirs := []struct {
+			keys   []string
+			dx, dy int
+		}{
+			{[]string{"h", "<Left>"}, -1, 0},
+			{[]string{"j", "<Down>"}, 0, 1},
+			{[]string{"k", "<Up>"}, 0, -1},
+			{[]string{"l", "<Right>"}, 1, 0},
+		}
+		for _, d := range dirs {
+			for _, k := range d.keys {
+				nav.Handle(sp.navKey+k, func(riffkey.Match) { sp.FocusDir(d.dx, d.dy) })
+			}
+		}
+		nav.Handle(sp.navKey+"w", func(riffkey.Match) { sp.FocusNext() })
+		nav.Handle(sp.navKey+"W", func(riffkey.Match) { sp.FocusPrev() })
+		nav.Handle(sp.navKey+"z", func(riffkey.Match) { sp.ToggleZoom() })
+		nav.Handle(sp.navKey+"f", func(riffkey.Match) { sp.ToggleFloat() })
+		moves := []struct {
+			key            string
+			dx, dy, dw, dh int16
+		}{
+			{"H", -1, 0, 0, 0}, {"J", 0, 1, 0, 0}, {"K", 0, -1, 0, 0}, {"L", 1, 0, 0, 0},
+			{">", 0, 0, 1, 0}, {"<", 0, 0, -1, 0}, {"+", 0, 0, 0, 1}, {"-", 0, 0, 0, -1},
+		}
+		for _, m := range moves {
+			nav.Handle(sp.navKey+m.key, func(riffkey.Match) {
+				sp.MoveFloat(m.dx, m.dy)
+				sp.ResizeFloat(m.dw, m.dh)
+			})
+		}
+	}
+	sp.nav = riffkey.NewInput(nav)
+
+	sp.wirePanes()
+	if sp.router == nil {
+		sp.router = riffkey.NewRouter().NoCounts()
+		sp.router.HandleUnmatched(sp.dispatch)
+		if push != nil {
+			push(sp.router)
+		}
+	}
+}
+
+// wirePanes gives every pane, nested splits included, a router of its own
+// keys.
+func (sp *SplitPaneC) wirePanes() {
+	for _, p := range sp.panes {
+		r := riffkey.NewRouter().NoCounts()
+		for _, b := range p.declaredBindings {
+			routeBinding(r, b, func() {})
+		}
+		if p.split != nil {
+			p.split.wirePanes()
+		} else {
+			for _, b := range p.sub.pendingBindings {
+				routeBinding(r, b, func() {})
+			}
+			if tib := p.sub.pendingTIB; tib != nil {
+				th := riffkey.NewTextHandler(tib.value, tib.cursor)
+				th.OnChange = tib.onChange
+				r.HandleUnmatched(th.HandleKey)
+			} else if p.sub.pendingKeyHandler != nil {
+				r.HandleUnmatched(p.sub.pendingKeyHandler)
+			}
+		}
+		p.input = riffkey.NewInput(r)
+	}
+}
+
+// dispatch routes one key: navigation first, then the focused pane out
+// through the splits that hold it, then the view.
+func (sp *SplitPaneC) dispatch(k riffkey.Key) bool {
+	if sp.nav != nil && sp.nav.Dispatch(k) {
+		return true
+	}
+	path := sp.focusPath()
+	for i := len(path) - 1; i >= 0; i-- {
+		if in := path[i].input; in != nil && in.Dispatch(k) {
+			return true
+		}
+	}
+	return sp.base != nil && sp.base.Dispatch(k)
+}
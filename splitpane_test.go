@@ -0,0 +1,214 @@
+package glyph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+func TestSplitPaneRender(t *testing.T) {
+	left := Pane("files", Text("a.go"))
+	right := Pane("", SplitPane(
+		Pane("edit", Text("package a")),
+		Pane("log", Text("ok")),
+	).Vertical()).Grow(2)
+	sp := SplitPane(left, right)
+	tmpl := Build(VBox(sp))
+
+	got := messageLines(tmpl, 30, 8)
+	want := []string{
+		"┌─ files ┐┌─ edit ───────────┐",
+		"│a.go    ││package a         │",
+		"│        ││                  │",
+		"│        │└──────────────────┘",
+		"│        │┌─ log ────────────┐",
+		"│        ││ok                │",
+		"│        ││                  │",
+		"└────────┘└──────────────────┘",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if sp.Focused() != left {
+		t.Errorf("first pane should start with focus")
+	}
+}
+
+func TestSplitPaneFocusDir(t *testing.T) {
+	files := Pane("files", Text(""))
+	edit := Pane("edit", Text(""))
+	log := Pane("log", Text(""))
+	sp := SplitPane(files, Pane("", SplitPane(edit, log).Vertical()).Grow(2))
+	messageLines(Build(VBox(sp)), 30, 8)
+
+	steps := []struct {
+		dx, dy int
+		want   *PaneC
+	}{
+		{1, 0, edit}, // both sit to the right; the first wins the tie
+		{0, 1, log},
+		{-1, 0, files},
+		{-1, 0, files}, // nothing further left
+		{1, 0, edit},
+		{0, -1, edit},
+	}
+	for i, s := range steps {
+		sp.FocusDir(s.dx, s.dy)
+		if got := sp.Focused(); got != s.want {
+			t.Fatalf("step %d: focused %q, want %q", i, got.title, s.want.title)
+		}
+	}
+	sp.FocusNext()
+	if sp.Focused() != log {
+		t.Errorf("FocusNext from edit = %q", sp.Focused().title)
+	}
+	sp.FocusNext()
+	if sp.Focused() != files {
+		t.Errorf("FocusNext should wrap to files")
+	}
+}
+
+func TestSplitPaneRouting(t *testing.T) {
+	var got []string
+	hit := func(s string) func() { return func() { got = append(got, s) } }
+
+	editor := Pane("editor", Text("")).Handle("j", hit("editor j"))
+	logs := Pane("log", StreamText().BindNav("j", "k"))
+	sp := SplitPane(editor, logs)
+	tmpl := Build(VBox(sp))
+	messageLines(tmpl, 20, 5)
+
+	if len(tmpl.pendingScopes) != 1 {
+		t.Fatalf("split not collected for router wiring")
+	}
+	for _, b := range tmpl.pendingBindings {
+		if b.pattern == "j" {
+			t.Errorf("pane binding leaked into the view's bindings")
+		}
+	}
+
+	base := riffkey.NewRouter()
+	base.Handle("j", func(riffkey.Match) { got = append(got, "view j") })
+	base.Handle("q", func(riffkey.Match) { got = append(got, "view q") })
+	var pushed *riffkey.Router
+	sp.attachRouter(base, func(r *riffkey.Router) { pushed = r })
+	in := riffkey.NewInput(pushed)
+	press := func(keys ...riffkey.Key) {
+		for _, k := range keys {
+			in.Dispatch(k)
+		}
+	}
+	j := riffkey.Key{Rune: 'j'}
+	q := riffkey.Key{Rune: 'q'}
+	ctrlW := riffkey.Key{Rune: 'w', Mod: riffkey.ModCtrl}
+
+	press(j, q)
+	if strings.Join(got, ",") != "editor j,view q" {
+		t.Errorf("editor focused: %q", got)
+	}
+
+	got = nil
+	press(ctrlW, riffkey.Key{Rune: 'l'})
+	if sp.Focused() != logs {
+		t.Fatalf("<C-w>l didn't move focus")
+	}
+	press(j, q)
+	if strings.Join(got, ",") != "view q" {
+		t.Errorf("log focused: %q", got) // the log's own j scrolls it
+	}
+}
+
+func TestSplitPaneZoom(t *testing.T) {
+	files := Pane("files", Text("a.go"))
+	edit := Pane("edit", Text("package a"))
+	sp := SplitPane(files, edit)
+	tmpl := Build(VBox(sp))
+	messageLines(tmpl, 24, 4)
+
+	sp.Focus(edit)
+	sp.ToggleZoom()
+	if sp.Zoomed() != edit {
+		t.Fatalf("focused pane not zoomed")
+	}
+	got := messageLines(tmpl, 24, 4)
+	want := []string{
+		"┌─ edit [Z] ───────────┐",
+		"│package a             │",
+		"│                      │",
+		"└──────────────────────┘",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("zoomed:\ngot:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// moving focus off the zoomed pane restores the split
+	sp.FocusDir(-1, 0)
+	if sp.Focused() != files || sp.Zoomed() != nil {
+		t.Fatalf("focus %q, zoomed %v", sp.Focused().title, sp.Zoomed())
+	}
+	got = messageLines(tmpl, 24, 4)
+	if !strings.HasPrefix(got[0], "┌─ files") || !strings.Contains(got[0], "┐┌─ edit") {
+		t.Errorf("restored: %q", got[0])
+	}
+
+	sp.ToggleZoom()
+	sp.ToggleZoom()
+	if sp.Zoomed() != nil {
+		t.Errorf("second toggle didn't restore")
+	}
+}
+
+func TestSplitPaneFloat(t *testing.T) {
+	files := Pane("files", Text("a.go"))
+	edit := Pane("edit", Text("package a"))
+	term := Pane("term", Text("$")).Floating(4, 2, 12, 3)
+	sp := SplitPane(files, edit, term)
+	tmpl := Build(VBox(sp))
+
+	// the floating pane takes no share of the split and sits on top
+	got := messageLines(tmpl, 24, 6)
+	want := []string{
+		"┌─ files ──┐┌─ edit ───┐",
+		"│a.go      ││package a │",
+		"│   ┌─ term ───┐       │",
+		"│   │$         │       │",
+		"│   └──────────┘       │",
+		"└──────────┘└──────────┘",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("floating:\ngot:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// moves are kept inside the split
+	sp.Focus(term)
+	sp.MoveFloat(100, -1)
+	sp.ResizeFloat(-2, 0)
+	got = messageLines(tmpl, 24, 6)
+	if got[1] != "│a.go      ││p┌─ term ─┐" {
+		t.Errorf("moved: %q", got[1])
+	}
+
+	// docking gives it back a share; floating again reopens it where it was
+	sp.ToggleFloat()
+	got = messageLines(tmpl, 24, 6)
+	if got[0] != "┌─ file┐┌─ edit┐┌─ term┐" {
+		t.Errorf("docked: %q", got[0])
+	}
+	sp.ToggleFloat()
+	messageLines(tmpl, 24, 6)
+	if term.x != 14 || term.y != 1 || term.w != 10 {
+		t.Errorf("refloated at %d,%d w%d", term.x, term.y, term.w)
+	}
+
+	// focusing a floating pane raises it above the others
+	a := Pane("a", Text("")).Floating(0, 0, 8, 3)
+	b := Pane("b", Text("")).Floating(2, 1, 8, 3)
+	sp2 := SplitPane(Pane("main", Text("")), a, b)
+	messageLines(Build(VBox(sp2)), 24, 6)
+	sp2.Focus(b)
+	sp2.Focus(a)
+	if f := sp2.floats(); f[len(f)-1] != a {
+		t.Errorf("focused float not on top")
+	}
+}
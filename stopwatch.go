@@ -0,0 +1,233 @@
+package glyph
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// StopwatchC times something with start, stop and laps, showing the time
+// so far as "1:02.34" and the latest laps beneath it:
+//
+//	Stopwatch().BindToggle("<Space>").BindLap("l").BindReset("r").ShowLaps(5)
+//
+// It re-renders itself from the app's clock while running, so nothing
+// needs a goroutine, and reads the clock rather than counting frames, so
+// a slow frame never loses time.
+type StopwatchC struct {
+	ticking
+	running   bool
+	started   time.Time     // when the current run began
+	banked    time.Duration // time from earlier runs
+	laps      []time.Duration
+	precision time.Duration
+	format    func(time.Duration) string
+	showLaps  int
+	style     Style
+	lapStyle  Style
+
+	declaredBindings []binding
+}
+
+// Stopwatch creates a stopped stopwatch reading zero.
+func Stopwatch() *StopwatchC {
+	return &StopwatchC{precision: 10 * time.Millisecond, lapStyle: Style{Attr: AttrDim}}
+}
+
+// Precision sets how finely the time is shown and so how often it redraws:
+// time.Second, 100ms, 10ms (the default) or time.Millisecond.
+func (s *StopwatchC) Precision(d time.Duration) *StopwatchC {
+	s.precision = max(d, time.Millisecond)
+	return s
+}
+
+// Format sets how times are shown, in place of the default m:ss.cc. It is
+// given times truncated to the precision.
+func (s *StopwatchC) Format(fn func(time.Duration) string) *StopwatchC {
+	s.format = fn
+	return s
+}
+
+// ShowLaps shows the last n laps under the time, newest first, each with
+// its own time and the total when it was taken.
+func (s *StopwatchC) ShowLaps(n int) *StopwatchC {
+	s.showLaps = max(n, 0)
+	return s
+}
+
+// Style sets the style of the time.
+func (s *StopwatchC) Style(st Style) *StopwatchC {
+	s.style = st
+	return s
+}
+
+// FG sets the foreground colour of the time.
+func (s *StopwatchC) FG(col Color) *StopwatchC {
+	s.style.FG = col
+	return s
+}
+
+// LapStyle sets the style of the lap rows, dim by default.
+func (s *StopwatchC) LapStyle(st Style) *StopwatchC {
+	s.lapStyle = st
+	return s
+}
+
+// BindToggle registers a key that starts and stops the stopwatch.
+func (s *StopwatchC) BindToggle(key string) *StopwatchC {
+	s.declaredBindings = append(s.declaredBindings, binding{pattern: key, handler: s.Toggle})
+	return s
+}
+
+// BindLap registers a key that takes a lap.
+func (s *StopwatchC) BindLap(key string) *StopwatchC {
+	s.declaredBindings = append(s.declaredBindings, binding{pattern: key, handler: s.Lap})
+	return s
+}
+
+// BindReset registers a key that resets the stopwatch.
+func (s *StopwatchC) BindReset(key string) *StopwatchC {
+	s.declaredBindings = append(s.declaredBindings, binding{pattern: key, handler: s.Reset})
+	return s
+}
+
+func (s *StopwatchC) bindings() []binding { return s.declaredBindings }
+
+// Start starts the stopwatch, or carries on from where it was stopped.
+func (s *StopwatchC) Start() {
+	if !s.running {
+		s.running, s.started = true, s.now()
+	}
+}
+
+// Stop stops the stopwatch, keeping its time.
+func (s *StopwatchC) Stop() {
+	if s.running {
+		s.banked += s.now().Sub(s.started)
+		s.running = false
+	}
+}
+
+// Toggle starts the stopwatch if it's stopped and stops it if not.
+func (s *StopwatchC) Toggle() {
+	if s.running {
+		s.Stop()
+	} else {
+		s.Start()
+	}
+}
+
+// Lap records the time so far as the end of a lap. It does nothing while
+// stopped.
+func (s *StopwatchC) Lap() {
+	if s.running {
+		s.laps = append(s.laps, s.Elapsed())
+	}
+}
+
+// Reset sets the time back to zero and clears the laps. A running
+// stopwatch keeps running.
+func (s *StopwatchC) Reset() {
+	s.banked, s.laps = 0, nil
+	if s.running {
+		s.started = s.now()
+	}
+}
+
+// Running reports whether the stopwatch is running.
+func (s *StopwatchC) Running() bool { return s.running }
+
+// Elapsed returns the time so far.
+func (s *StopwatchC) Elapsed() time.Duration {
+	if s.running {
+		return s.banked + s.now().Sub(s.started)
+	}
+	return s.banked
+}
+
+// Laps returns the total time at the end of each lap, oldest first.
+func (s *StopwatchC) Laps() []time.Duration { return s.laps }
+
+func (t *Template) compileStopwatchC(s *StopwatchC, parent int16, depth int) int16 {
+	t.collectUpdateHook(s)
+	t.collectClockUser(s)
+	t.collectBindings(s)
+	return t.compileCustom(Widget(s.measure, s.render), parent, depth)
+}
+
+func (s *StopwatchC) text(d time.Duration) string {
+	d = d.Truncate(s.precision)
+	if s.format != nil {
+		return s.format(d)
+	}
+	return formatStopwatch(d, s.precision)
+}
+
+// lines returns the time and the lap rows under it.
+func (s *StopwatchC) lines(elapsed time.Duration) []string {
+	lines := []string{s.text(elapsed)}
+	n := min(s.showLaps, len(s.laps))
+	if n == 0 {
+		return lines
+	}
+	label := len(fmt.Sprint(len(s.laps)))
+	var laps, splits []string
+	width := 0
+	for i := len(s.laps) - 1; i >= len(s.laps)-n; i-- {
+		lap := s.laps[i]
+		if i > 0 {
+			lap -= s.laps[i-1]
+		}
+		laps = append(laps, s.text(lap))
+		splits = append(splits, s.text(s.laps[i]))
+		width = max(width, runewidth.StringWidth(laps[len(laps)-1]))
+	}
+	for j, lap := range laps {
+		num := len(s.laps) - j
+		pad := strings.Repeat(" ", width-runewidth.StringWidth(lap))
+		lines = append(lines, fmt.Sprintf("lap %*d  %s%s  %s", label, num, pad, lap, splits[j]))
+	}
+	return lines
+}
+
+func (s *StopwatchC) measure(availW int16) (w, h int16) {
+	lines := s.lines(s.Elapsed())
+	for _, l := range lines {
+		w = max(w, int16(runewidth.StringWidth(l)))
+	}
+	return w, int16(len(lines))
+}
+
+func (s *StopwatchC) render(buf *Buffer, x, y, w, h int16) {
+	elapsed := s.Elapsed()
+	for i, l := range s.lines(elapsed) {
+		if i >= int(h) {
+			break
+		}
+		style := s.style
+		if i > 0 {
+			style = s.lapStyle
+		}
+		buf.WriteStringFast(int(x), int(y)+i, l, style, int(w))
+	}
+	if s.running {
+		s.after(s.precision - elapsed%s.precision)
+	}
+}
+
+// formatStopwatch shows d as m:ss or h:mm:ss, with as many decimal places
+// as precision calls for.
+func formatStopwatch(d, precision time.Duration) string {
+	text := formatCountdown(d.Truncate(time.Second))
+	places := 0
+	for p := precision; p < time.Second && places < 9; p *= 10 {
+		places++
+	}
+	if places == 0 {
+		return text
+	}
+	frac := fmt.Sprintf("%09d", d%time.Second)
+	return text + "." + frac[:places]
+}
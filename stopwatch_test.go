@@ -0,0 +1,104 @@
+package glyph
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStopwatch(t *testing.T) {
+	clock := NewFakeClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	renders := 0
+	sw := Stopwatch().ShowLaps(2)
+	tmpl := Build(VBox(sw))
+	tmpl.SetClock(clock)
+	for _, u := range tmpl.pendingUpdates {
+		*u = func() { renders++ }
+	}
+	frame := func() []string {
+		buf := NewBuffer(30, 4)
+		tmpl.Execute(buf, 30, 4)
+		return []string{buf.GetLine(0), buf.GetLine(1), buf.GetLine(2), buf.GetLine(3)}
+	}
+
+	if got := frame()[0]; got != "0:00.00" {
+		t.Fatalf("stopped = %q", got)
+	}
+	clock.Advance(time.Second)
+	if renders != 0 {
+		t.Errorf("stopped stopwatch rendered %d times", renders)
+	}
+
+	sw.Start()
+	frame()
+	clock.Advance(25 * time.Millisecond)
+	if renders != 1 { // hundredths come faster than frames
+		t.Errorf("renders = %d, want one a frame", renders)
+	}
+	if got := frame()[0]; got != "0:00.02" {
+		t.Errorf("running = %q", got)
+	}
+
+	clock.Advance(4*time.Second + 75*time.Millisecond) // 4.10
+	sw.Lap()
+	sw.Lap() // an empty lap
+	sw.Stop()
+	clock.Advance(time.Hour)
+	sw.Lap() // ignored while stopped
+	sw.Toggle()
+	clock.Advance(61 * time.Second)
+	sw.Lap()
+	sw.Toggle()
+	got := frame()
+	want := []string{
+		"1:05.10",
+		"lap 3  1:01.00  1:05.10",
+		"lap 2  0:00.00  0:04.10",
+		"",
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("row %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+	if sw.Running() || len(sw.Laps()) != 3 || sw.Laps()[0] != 4100*time.Millisecond {
+		t.Errorf("running %v laps %v", sw.Running(), sw.Laps())
+	}
+
+	sw.Reset()
+	if got := frame(); got[0] != "0:00.00" || got[1] != "" {
+		t.Errorf("after reset = %q", got)
+	}
+}
+
+func TestStopwatchBindings(t *testing.T) {
+	sw := Stopwatch().BindToggle("s").BindLap("l").BindReset("r")
+	if got := len(sw.bindings()); got != 3 {
+		t.Fatalf("bindings = %d", got)
+	}
+	clock := NewFakeClock(time.Time{})
+	sw.setClock(clock)
+	sw.bindings()[0].handler.(func())()
+	clock.Advance(time.Second)
+	sw.bindings()[1].handler.(func())()
+	if sw.Elapsed() != time.Second || len(sw.Laps()) != 1 {
+		t.Errorf("elapsed %v laps %v", sw.Elapsed(), sw.Laps())
+	}
+	sw.bindings()[2].handler.(func())()
+	if sw.Elapsed() != 0 || !sw.Running() {
+		t.Errorf("after reset: elapsed %v running %v", sw.Elapsed(), sw.Running())
+	}
+}
+
+func TestFormatStopwatch(t *testing.T) {
+	d := time.Hour + 2*time.Minute + 3*time.Second + 456789*time.Microsecond
+	for precision, want := range map[time.Duration]string{
+		time.Second:            "1:02:03",
+		100 * time.Millisecond: "1:02:03.4",
+		10 * time.Millisecond:  "1:02:03.45",
+		time.Millisecond:       "1:02:03.456",
+	} {
+		if got := formatStopwatch(d, precision); got != want {
+			t.Errorf("at %v = %q, want %q", precision, got, want)
+		}
+	}
+}
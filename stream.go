@@ -0,0 +1,220 @@
+package glyph
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Stream is a live feed pushed by a server, over Server-Sent Events or a
+// WebSocket. Messages reach the UI goroutine through OnMessage, and Lines
+// turns the feed into a reader for Log and FilterLog:
+//
+//	feed := SSE("https://ci.example.com/builds/42/log")
+//	go feed.Run(app.Context(), app.Post)
+//	Log(feed.Lines())
+//
+//	ticks := WebSocket("wss://example.com/ticker").OnMessage(func(m string) {
+//	    quotes.Append(strings.Split(m, ",")...)
+//	})
+//
+// A dropped connection is retried with backoff. State is SourceLoading
+// while connecting, SourceReady while connected and SourceError between a
+// failure and the next attempt.
+type Stream struct {
+	State   SourceState
+	Err     error
+	ErrText string // Err as text, "" when there is none
+	Last    string // most recent message
+
+	connect   func(ctx context.Context, s *Stream, ready func(), msg func(string)) error
+	onMessage func(string)
+	lines     *io.PipeWriter
+	lastID    string // SSE event id, sent back on reconnect
+	retry     time.Duration
+}
+
+// streamMinRetry and streamMaxRetry bound the wait between reconnects.
+const (
+	streamMinRetry = time.Second
+	streamMaxRetry = 30 * time.Second
+)
+
+// OnMessage sets a callback run on the UI goroutine for each message.
+func (s *Stream) OnMessage(fn func(msg string)) *Stream {
+	s.onMessage = fn
+	return s
+}
+
+// Lines returns a reader yielding one line per message, for Log or
+// FilterLog. Call it before Run; messages block until the reader keeps up.
+func (s *Stream) Lines() io.Reader {
+	r, w := io.Pipe()
+	s.lines = w
+	return r
+}
+
+// Run connects and delivers messages until ctx is done, reconnecting when
+// the connection drops. Updates are handed to post so they run on the UI
+// goroutine; pass app.Post, or nil to apply them directly.
+func (s *Stream) Run(ctx context.Context, post func(func())) error {
+	if post == nil {
+		post = func(fn func()) { fn() }
+	}
+	if s.lines != nil {
+		// closing also frees a write blocked on a reader that went away
+		stop := context.AfterFunc(ctx, func() { s.lines.Close() })
+		defer stop()
+		defer s.lines.Close()
+	}
+	wait := streamMinRetry
+	for {
+		ready := func() {
+			wait = streamMinRetry
+			post(func() {
+				s.State = SourceReady
+				s.Err, s.ErrText = nil, ""
+			})
+		}
+		err := s.connect(ctx, s, ready, func(msg string) {
+			if s.lines != nil {
+				io.WriteString(s.lines, msg+"\n")
+			}
+			post(func() {
+				s.Last = msg
+				if s.onMessage != nil {
+					s.onMessage(msg)
+				}
+			})
+		})
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err == nil {
+			err = io.ErrUnexpectedEOF // the server hung up
+		}
+		post(func() {
+			s.State = SourceError
+			s.Err = err
+			s.ErrText = err.Error()
+		})
+		if s.retry > 0 {
+			wait = s.retry // the server asked for this
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+		wait = min(wait*2, streamMaxRetry)
+		post(func() { s.State = SourceLoading })
+	}
+}
+
+// SSE creates a stream reading Server-Sent Events from url. Each event's
+// data, multi-line data joined by newlines, is one message. The last event
+// ID is sent back on reconnect so the server can resume.
+func SSE(url string) *Stream {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return &Stream{connect: func(context.Context, *Stream, func(), func(string)) error { return err }}
+	}
+	return SSERequest(req)
+}
+
+// SSERequest is SSE for a request of your own, with headers or auth set.
+func SSERequest(req *http.Request) *Stream {
+	return &Stream{connect: func(ctx context.Context, s *Stream, ready func(), msg func(string)) error {
+		r := req.Clone(ctx)
+		r.Header.Set("Accept", "text/event-stream")
+		r.Header.Set("Cache-Control", "no-cache")
+		if s.lastID != "" {
+			r.Header.Set("Last-Event-ID", s.lastID)
+		}
+		resp, err := http.DefaultClient.Do(r)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("GET %s: %s", r.URL, resp.Status)
+		}
+		ready()
+		return readSSE(resp.Body, s, msg)
+	}}
+}
+
+// readSSE parses an event stream, calling msg for each event with data.
+func readSSE(r io.Reader, s *Stream, msg func(string)) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<20)
+	var data []string
+	for sc.Scan() {
+		line := sc.Text()
+		if line == "" {
+			// a blank line dispatches the event
+			if data != nil {
+				msg(strings.Join(data, "\n"))
+				data = nil
+			}
+			continue
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "": // comment, often a keepalive
+		case "data":
+			data = append(data, value)
+		case "id":
+			s.lastID = value
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil {
+				s.retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+	if err := sc.Err(); err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
+
+// WebSocket creates a stream reading text messages from a ws:// or wss://
+// url. Binary messages are passed on as they are.
+func WebSocket(url string) *Stream {
+	return WebSocketRequest(url, nil)
+}
+
+// WebSocketRequest is WebSocket with extra handshake headers, such as
+// Authorization.
+func WebSocketRequest(url string, header http.Header) *Stream {
+	return &Stream{connect: func(ctx context.Context, s *Stream, ready func(), msg func(string)) error {
+		conn, err := dialWebSocket(ctx, url, header)
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		ready()
+		stop := context.AfterFunc(ctx, func() { conn.Close() })
+		defer stop()
+		for {
+			m, err := conn.ReadMessage()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				return err
+			}
+			msg(string(m))
+		}
+	}}
+}
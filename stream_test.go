@@ -0,0 +1,159 @@
+package glyph
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadSSE(t *testing.T) {
+	in := `: keepalive
+
+id: 7
+data: first
+
+data: two
+data: lines
+retry: 2500
+
+event: ping
+
+`
+	s := &Stream{}
+	var got []string
+	if err := readSSE(strings.NewReader(in), s, func(m string) { got = append(got, m) }); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0] != "first" || got[1] != "two\nlines" {
+		t.Errorf("messages = %q", got)
+	}
+	if s.lastID != "7" || s.retry != 2500*time.Millisecond {
+		t.Errorf("lastID = %q retry = %v", s.lastID, s.retry)
+	}
+}
+
+// collect runs s until n messages arrive, applying posts on this
+// goroutine, and returns the messages.
+func collect(t *testing.T, s *Stream, n int) []string {
+	t.Helper()
+	var got []string
+	s.OnMessage(func(m string) { got = append(got, m) })
+	posted := make(chan func(), 16)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.Run(ctx, func(fn func()) { posted <- fn })
+	timeout := time.After(5 * time.Second)
+	for len(got) < n {
+		select {
+		case fn := <-posted:
+			fn()
+		case <-timeout:
+			t.Fatalf("got %q, waiting for %d messages (state %v, err %v)", got, n, s.State, s.Err)
+		}
+	}
+	return got
+}
+
+func TestSSE(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: hello\n\ndata: world\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	s := SSE(srv.URL)
+	got := collect(t, s, 2)
+	if got[0] != "hello" || got[1] != "world" {
+		t.Errorf("messages = %q", got)
+	}
+	if s.State != SourceReady || s.Last != "world" {
+		t.Errorf("state = %v last = %q", s.State, s.Last)
+	}
+}
+
+func TestStreamLines(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "data: one\n\ndata: two\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	s := SSE(srv.URL)
+	lines := bufio.NewScanner(s.Lines())
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx, func(func()) {})
+		close(done)
+	}()
+	for _, want := range []string{"one", "two"} {
+		if !lines.Scan() || lines.Text() != want {
+			t.Fatalf("line = %q, want %q", lines.Text(), want)
+		}
+	}
+	cancel()
+	<-done
+}
+
+// wsServer accepts one WebSocket, sends msgs as text frames, pings once and
+// waits for the pong, then closes.
+func wsServer(t *testing.T, msgs ...string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("Sec-WebSocket-Key")
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		fmt.Fprintf(rw, "HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\nSec-WebSocket-Accept: %s\r\n\r\n", wsAccept(key))
+		frame := func(op byte, fin bool, p string) {
+			b := op
+			if fin {
+				b |= 0x80
+			}
+			rw.Write([]byte{b, byte(len(p))})
+			rw.WriteString(p)
+			rw.Flush()
+		}
+		frame(wsPing, true, "hi")
+		c := &wsConn{conn: conn, r: rw.Reader}
+		if _, op, p, err := c.readFrame(); err != nil || op != wsPong || string(p) != "hi" {
+			t.Errorf("pong = %x %q %v", op, p, err)
+		}
+		for _, m := range msgs {
+			// split each message in two to exercise continuation frames
+			half := len(m) / 2
+			frame(wsText, false, m[:half])
+			frame(wsContinuation, true, m[half:])
+		}
+		frame(wsClose, true, "")
+		io.Copy(io.Discard, rw)
+	}))
+}
+
+func TestWebSocket(t *testing.T) {
+	srv := wsServer(t, "tick 1", "tick 2")
+	defer srv.Close()
+
+	s := WebSocket("ws" + strings.TrimPrefix(srv.URL, "http"))
+	got := collect(t, s, 2)
+	if got[0] != "tick 1" || got[1] != "tick 2" {
+		t.Errorf("messages = %q", got)
+	}
+}
+
+func TestWebSocketBadScheme(t *testing.T) {
+	if _, err := dialWebSocket(context.Background(), "http://example.com", nil); err == nil {
+		t.Error("expected an error for http://")
+	}
+}
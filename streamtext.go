@@ -0,0 +1,257 @@
+package glyph
+
+import (
+	"strings"
+	"sync"
+)
+
+// StreamTextC shows text that arrives a piece at a time, such as tokens from
+// a language model. Chunks can be appended from any goroutine; the view
+// follows the end while the user is at the bottom, holds still once they
+// scroll up, and re-wraps on resize.
+//
+//	out := StreamText().OnCancel(cancel).BindCancel("<C-c>").BindVimNav()
+//	go func() {
+//	    for tok := range tokens {
+//	        out.Append(tok)
+//	    }
+//	    out.Done()
+//	}()
+//
+// It is also an io.Writer, so io.Copy(out, resp.Body) works.
+type StreamTextC struct {
+	mu       sync.Mutex
+	text     strings.Builder
+	version  uint64
+	done     bool
+	onCancel func()
+	update   func() // set to app.RequestRender during wiring
+
+	layer  *Layer
+	style  Style
+	cursor string
+
+	grow             float32
+	margin           [4]int16
+	declaredBindings []binding
+
+	// render state
+	lastVersion uint64
+	lastWidth   int
+	lastHeight  int
+	lastDone    bool
+}
+
+// StreamText creates an empty streaming text view.
+func StreamText() *StreamTextC {
+	st := &StreamTextC{
+		layer:  NewLayer(),
+		cursor: "▍",
+	}
+	st.layer.AlwaysRender = true
+	st.layer.Render = st.sync
+	return st
+}
+
+// Append adds a chunk to the end. Safe to call from any goroutine.
+func (st *StreamTextC) Append(chunk string) {
+	st.mu.Lock()
+	st.text.WriteString(chunk)
+	st.version++
+	update := st.update
+	st.mu.Unlock()
+	if update != nil {
+		update()
+	}
+}
+
+// Write implements io.Writer by appending p.
+func (st *StreamTextC) Write(p []byte) (int, error) {
+	st.Append(string(p))
+	return len(p), nil
+}
+
+// Text returns everything received so far.
+func (st *StreamTextC) Text() string {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return st.text.String()
+}
+
+// Reset clears the text and starts a new stream. Call it on the UI
+// goroutine, e.g. from a key handler or app.Post.
+func (st *StreamTextC) Reset() {
+	st.mu.Lock()
+	st.text.Reset()
+	st.version++
+	st.done = false
+	st.mu.Unlock()
+	st.layer.ScrollToTop()
+}
+
+// Done marks the stream finished, hiding the cursor. Safe to call from any
+// goroutine.
+func (st *StreamTextC) Done() {
+	st.mu.Lock()
+	st.done = true
+	update := st.update
+	st.mu.Unlock()
+	if update != nil {
+		update()
+	}
+}
+
+// Streaming reports whether text is still expected.
+func (st *StreamTextC) Streaming() bool {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	return !st.done
+}
+
+// OnCancel sets what Cancel calls to stop the producer, typically a
+// context.CancelFunc.
+func (st *StreamTextC) OnCancel(fn func()) *StreamTextC {
+	st.onCancel = fn
+	return st
+}
+
+// Cancel stops a stream under way: it calls the OnCancel hook and marks the
+// stream done. It does nothing once the stream is done.
+func (st *StreamTextC) Cancel() {
+	st.mu.Lock()
+	if st.done {
+		st.mu.Unlock()
+		return
+	}
+	st.done = true
+	st.mu.Unlock()
+	if st.onCancel != nil {
+		st.onCancel()
+	}
+}
+
+// BindCancel registers a key that cancels the stream.
+func (st *StreamTextC) BindCancel(key string) *StreamTextC {
+	st.declaredBindings = append(st.declaredBindings, binding{key, st.Cancel})
+	return st
+}
+
+// Style sets the text style.
+func (st *StreamTextC) Style(s Style) *StreamTextC {
+	st.style = s
+	return st
+}
+
+// Cursor sets the marker drawn after the text while streaming. "▍" by
+// default; "" hides it.
+func (st *StreamTextC) Cursor(s string) *StreamTextC {
+	st.cursor = s
+	return st
+}
+
+// Grow sets the flex grow factor.
+func (st *StreamTextC) Grow(g float32) *StreamTextC {
+	st.grow = g
+	return st
+}
+
+// Margin sets equal margin on all sides.
+func (st *StreamTextC) Margin(all int16) *StreamTextC {
+	st.margin = [4]int16{all, all, all, all}
+	return st
+}
+
+// MarginVH sets vertical and horizontal margins.
+func (st *StreamTextC) MarginVH(v, h int16) *StreamTextC {
+	st.margin = [4]int16{v, h, v, h}
+	return st
+}
+
+// MarginTRBL sets top, right, bottom, left margins individually.
+func (st *StreamTextC) MarginTRBL(t, r, b, l int16) *StreamTextC {
+	st.margin = [4]int16{t, r, b, l}
+	return st
+}
+
+// Layer returns the underlying layer for external scroll wiring.
+func (st *StreamTextC) Layer() *Layer { return st.layer }
+
+// BindNav registers key bindings for scrolling down/up by one line.
+func (st *StreamTextC) BindNav(down, up string) *StreamTextC {
+	st.declaredBindings = append(st.declaredBindings,
+		binding{down, func() { st.layer.ScrollDown(1) }},
+		binding{up, func() { st.layer.ScrollUp(1) }},
+	)
+	return st
+}
+
+// BindPageNav registers key bindings for half-page scrolling.
+func (st *StreamTextC) BindPageNav(down, up string) *StreamTextC {
+	st.declaredBindings = append(st.declaredBindings,
+		binding{down, func() { st.layer.HalfPageDown() }},
+		binding{up, func() { st.layer.HalfPageUp() }},
+	)
+	return st
+}
+
+// BindFirstLast registers key bindings for jumping to top/bottom.
+func (st *StreamTextC) BindFirstLast(first, last string) *StreamTextC {
+	st.declaredBindings = append(st.declaredBindings,
+		binding{first, func() { st.layer.ScrollToTop() }},
+		binding{last, func() { st.layer.ScrollToEnd() }},
+	)
+	return st
+}
+
+// BindVimNav wires standard vim-style scroll keys:
+// j/k: line, Ctrl-d/u: half-page, g/G: top/bottom
+func (st *StreamTextC) BindVimNav() *StreamTextC {
+	return st.BindNav("j", "k").BindPageNav("<C-d>", "<C-u>").BindFirstLast("g", "G")
+}
+
+func (st *StreamTextC) bindings() []binding { return st.declaredBindings }
+
+func (st *StreamTextC) updateHook() *func() { return &st.update }
+
+func (t *Template) compileStreamTextC(st *StreamTextC, parent int16, depth int) int16 {
+	layerView := LayerView(st.layer).Grow(st.grow)
+	if st.margin != [4]int16{} {
+		layerView = layerView.MarginTRBL(st.margin[0], st.margin[1], st.margin[2], st.margin[3])
+	}
+	return t.compileLayerViewC(layerView, parent, depth)
+}
+
+func (st *StreamTextC) sync() {
+	w := st.layer.ViewportWidth()
+	h := st.layer.ViewportHeight()
+	if w <= 0 {
+		return
+	}
+	st.mu.Lock()
+	version, done := st.version, st.done
+	if version == st.lastVersion && w == st.lastWidth && h == st.lastHeight && done == st.lastDone && st.layer.Buffer() != nil {
+		st.mu.Unlock()
+		return
+	}
+	text := st.text.String()
+	st.mu.Unlock()
+	st.lastVersion, st.lastWidth, st.lastHeight, st.lastDone = version, w, h, done
+
+	// follow the end only while the reader is already there
+	atBottom := st.layer.ScrollY() >= st.layer.MaxScroll()
+	if !done {
+		text += st.cursor
+	}
+	lines := wordWrap(text, w)
+	buf := NewBuffer(w, max(len(lines), h))
+	for i, line := range lines {
+		buf.WriteStringFast(0, i, line, st.style, w)
+	}
+	y := st.layer.ScrollY()
+	st.layer.SetBuffer(buf)
+	if atBottom {
+		st.layer.ScrollToEnd()
+	} else {
+		st.layer.ScrollTo(y)
+	}
+}
@@ -0,0 +1,85 @@
+package glyph
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestStreamText(t *testing.T) {
+	st := StreamText().Grow(1)
+	tmpl := Build(VBox(st))
+	renders := 0
+	for _, u := range tmpl.pendingUpdates {
+		*u = func() { renders++ }
+	}
+
+	st.Append("hello ")
+	st.Append("world")
+	if renders != 2 {
+		t.Errorf("renders = %d, want 2", renders)
+	}
+	if got := messageLines(tmpl, 8, 3); got[0] != "hello" || got[1] != "world▍" {
+		t.Errorf("streaming = %q", got)
+	}
+
+	// narrower re-wraps
+	if got := messageLines(tmpl, 5, 3); got[0] != "hello" || got[1] != "world" || got[2] != "▍" {
+		t.Errorf("rewrapped = %q", got)
+	}
+
+	st.Done()
+	if got := messageLines(tmpl, 8, 3); got[1] != "world" || st.Streaming() {
+		t.Errorf("done = %q", got)
+	}
+}
+
+func TestStreamTextFollow(t *testing.T) {
+	st := StreamText().Cursor("").Grow(1)
+	tmpl := Build(VBox(st))
+	for i := range 5 {
+		st.Append(strings.Repeat(string(rune('a'+i)), 4) + "\n")
+	}
+	if got := messageLines(tmpl, 4, 2); got[0] != "eeee" {
+		t.Errorf("not following: %q", got)
+	}
+
+	st.Layer().ScrollUp(2)
+	st.Append("ffff\n")
+	if got := messageLines(tmpl, 4, 2); got[0] != "cccc" {
+		t.Errorf("followed while scrolled up: %q", got)
+	}
+
+	st.Layer().ScrollToEnd()
+	st.Append("gggg")
+	if got := messageLines(tmpl, 4, 2); got[1] != "gggg" {
+		t.Errorf("didn't resume following: %q", got)
+	}
+}
+
+func TestStreamTextCancel(t *testing.T) {
+	cancelled := 0
+	st := StreamText().OnCancel(func() { cancelled++ })
+	st.Cancel()
+	st.Cancel()
+	if cancelled != 1 || st.Streaming() {
+		t.Errorf("cancelled = %d streaming = %v", cancelled, st.Streaming())
+	}
+}
+
+func TestStreamTextWriter(t *testing.T) {
+	st := StreamText()
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			io.Copy(st, strings.NewReader("abc"))
+		}()
+	}
+	wg.Wait()
+	if got := st.Text(); len(got) != 12 {
+		t.Errorf("text = %q", got)
+	}
+}
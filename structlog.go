@@ -0,0 +1,492 @@
+package glyph
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// ============================================================================
+// Structured log lines
+// ============================================================================
+
+// jsonLogFields flattens a line holding a JSON object into fields, nested
+// objects as dotted keys such as "http.status", with the keys in the order
+// they appear. Arrays are kept as JSON text.
+func jsonLogFields(line string) ([]string, LogFields, bool) {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "{") || !json.Valid([]byte(trimmed)) {
+		return nil, nil, false
+	}
+	var keys []string
+	f := LogFields{}
+	if !flattenJSON([]byte(trimmed), "", &keys, f) {
+		return nil, nil, false
+	}
+	return keys, f, true
+}
+
+func flattenJSON(raw []byte, prefix string, keys *[]string, f LogFields) bool {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return false
+	}
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return false
+		}
+		key := prefix + t.(string)
+		var v json.RawMessage
+		if dec.Decode(&v) != nil {
+			return false
+		}
+		if v[0] == '{' {
+			if !flattenJSON(v, key+".", keys, f) {
+				return false
+			}
+			continue
+		}
+		if _, dup := f[key]; !dup {
+			*keys = append(*keys, key)
+		}
+		f[key] = jsonText(v)
+	}
+	return true
+}
+
+// jsonText is a JSON value as shown: strings unquoted, anything else as
+// written, compacted.
+func jsonText(v json.RawMessage) string {
+	var s string
+	if v[0] == '"' && json.Unmarshal(v, &s) == nil {
+		return s
+	}
+	var b bytes.Buffer
+	if json.Compact(&b, v) == nil {
+		return b.String()
+	}
+	return string(v)
+}
+
+// ============================================================================
+// Log columns
+// ============================================================================
+
+// logColumnMax caps how wide a column other than the last grows.
+const logColumnMax = 24
+
+// logRow is a line as a log showing columns draws it.
+type logRow struct {
+	text  string
+	level int // level's rank, -1 for none
+}
+
+// Columns shows lines as aligned columns of the named fields, for JSON or
+// logfmt logs, instead of as they are:
+//
+//	Log(r).Columns("time", "level", "http.status", "msg")
+//
+// Fields are found as a LogFilter finds them, nested JSON keys dotted, and
+// a line without one leaves its column blank. Columns widen to fit what
+// they've shown, up to a limit, except the last, which takes the rest of
+// the row. Levels are coloured by severity. With no names the columns are
+// picked from the fields seen: a time, the level and the message.
+func (lv *LogC) Columns(fields ...string) *LogC {
+	lv.columns = append([]string{}, fields...)
+	lv.colWidths = map[string]int{}
+	lv.fieldSeen = map[string]bool{}
+	lv.rows = map[string]logRow{}
+	return lv
+}
+
+// Fields returns the fields seen in the log's lines so far, in the order
+// they first appeared, when showing columns.
+func (lv *LogC) Fields() []string {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	return append([]string(nil), lv.fields...)
+}
+
+// BindDetail registers keys that open a pane under the log showing the
+// selected line's whole record, and close it again. The nav keys then
+// move a selection through the lines rather than scrolling them; it
+// starts on the newest and moving up stops following.
+func (lv *LogC) BindDetail(open, close string) *LogC {
+	lv.selecting = true
+	if lv.detail == nil {
+		lv.detail = StructuredView(&lv.selected).Grow(1)
+	}
+	lv.declaredBindings = append(lv.declaredBindings,
+		binding{open, func() { lv.detailOpen = true }},
+		binding{close, func() { lv.detailOpen = false }},
+	)
+	return lv
+}
+
+// Detail returns the pane BindDetail opens, to style it or bind its keys.
+func (lv *LogC) Detail() *StructuredViewC {
+	if lv.detail == nil {
+		lv.detail = StructuredView(&lv.selected).Grow(1)
+	}
+	return lv.detail
+}
+
+// DetailOpen reports whether the detail pane is showing.
+func (lv *LogC) DetailOpen() bool { return lv.detailOpen }
+
+// Selected returns the selected line, when BindDetail is in use.
+func (lv *LogC) Selected() string {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	return lv.selected
+}
+
+// SelectedStyle sets the style of the selected line, inverse by default.
+func (lv *LogC) SelectedStyle(s Style) *LogC {
+	lv.selectedStyle = s
+	return lv
+}
+
+// activeColumns returns the columns shown.
+func (lv *LogC) activeColumns() []string {
+	if len(lv.columns) > 0 {
+		return lv.columns
+	}
+	var cols []string
+	for _, k := range []string{"time", "ts", "timestamp", "@timestamp", "t"} {
+		if lv.fieldSeen[k] {
+			cols = append(cols, k)
+			break
+		}
+	}
+	if lv.sawLevel {
+		cols = append(cols, "level")
+	}
+	return append(cols, "msg")
+}
+
+// noteFields records a new line's fields and widens the columns to fit
+// it. Called with lv.mu held.
+func (lv *LogC) noteFields(line string) {
+	keys, own := logLineFields(line)
+	for _, k := range keys {
+		if !lv.fieldSeen[k] {
+			lv.fieldSeen[k] = true
+			lv.fields = append(lv.fields, k)
+		}
+	}
+	f := withLogAliases(line, own)
+	if f["level"] != "" && !lv.sawLevel {
+		lv.sawLevel = true
+		clear(lv.rows)
+	}
+	cols := lv.activeColumns()
+	for _, c := range cols[:len(cols)-1] {
+		if w := min(StringWidth(f[c]), logColumnMax); w > lv.colWidths[c] {
+			lv.colWidths[c] = w
+			clear(lv.rows) // drawn too narrow
+		}
+	}
+}
+
+// row returns how a line is drawn: as it is, or in columns.
+func (lv *LogC) row(line string) logRow {
+	if lv.columns == nil {
+		return logRow{text: line, level: -1}
+	}
+	if r, ok := lv.rows[line]; ok {
+		return r
+	}
+	if len(lv.rows) > 2*max(lv.maxLines, 1000) {
+		clear(lv.rows)
+	}
+	f := ParseLogLine(line)
+	cols := lv.activeColumns()
+	var b strings.Builder
+	for i, c := range cols {
+		v := strings.ReplaceAll(f[c], "\n", " ")
+		if i == len(cols)-1 {
+			b.WriteString(v)
+			break
+		}
+		w := lv.colWidths[c]
+		v = Truncate(v, w)
+		b.WriteString(v + strings.Repeat(" ", w-StringWidth(v)+2))
+	}
+	r := logRow{text: b.String(), level: logLevelRank(f["level"])}
+	lv.rows[line] = r
+	return r
+}
+
+// levelColumn returns where the level column is drawn, if shown.
+func (lv *LogC) levelColumn() (x, w int, ok bool) {
+	cols := lv.activeColumns()
+	for _, c := range cols[:len(cols)-1] {
+		if c == "level" {
+			return x, lv.colWidths[c], true
+		}
+		x += lv.colWidths[c] + 2
+	}
+	return 0, 0, false
+}
+
+func logLevelStyle(rank int) Style {
+	switch {
+	case rank >= 4:
+		return Style{FG: Red, Attr: AttrBold}
+	case rank == 3:
+		return Style{FG: Yellow}
+	case rank >= 0 && rank <= 1:
+		return Style{Attr: AttrDim}
+	}
+	return Style{}
+}
+
+// moveCursor moves the selection n lines, scrolling to keep it on screen.
+// Called with lv.mu held.
+func (lv *LogC) moveCursor(n int) {
+	h := max(lv.layer.ViewportHeight(), 1)
+	rows := h
+	if lv.scrollback == nil {
+		rows = min(h, len(lv.shown())-lv.layer.ScrollY())
+	} else if lv.page.lines > 0 {
+		rows = min(h, len(lv.pageLines))
+	}
+	if lv.following {
+		lv.cursor = rows - 1
+	}
+	if n < 0 {
+		lv.following = false
+	}
+	lv.cursor += n
+	if lv.cursor < 0 {
+		lv.scrollLocked(lv.cursor)
+		lv.cursor = 0
+	}
+	if lv.cursor >= rows {
+		lv.scrollLocked(lv.cursor - rows + 1)
+		lv.cursor = max(rows-1, 0)
+	}
+}
+
+// ============================================================================
+// StructuredView
+// ============================================================================
+
+// StructuredViewC shows a record's fields as aligned keys and values,
+// nested objects indented under their key, for the detail of a log line
+// or any JSON:
+//
+//	StructuredView(&selectedLine).BindScroll("<C-e>", "<C-y>").Grow(1)
+//
+// Content is a string or *string, read each frame: a JSON object or
+// array, or a logfmt line, whose key=value pairs are shown, or else plain
+// text. Long values wrap under themselves.
+type StructuredViewC struct {
+	layer    *Layer
+	content  any // string or *string
+	keyStyle Style
+	style    Style
+
+	drawn      string // the content and width last drawn
+	drawnWidth int
+
+	grow   float32
+	height int16
+
+	declaredBindings []binding
+}
+
+// structField is a key and either a value or fields of its own.
+type structField struct {
+	key    string
+	value  string
+	fields []structField
+	nested bool
+}
+
+// StructuredView creates a view of content, a string or *string.
+func StructuredView(content any) *StructuredViewC {
+	v := &StructuredViewC{layer: NewLayer(), content: content, keyStyle: Style{FG: Cyan}}
+	v.layer.AlwaysRender = true
+	v.layer.Render = v.sync
+	return v
+}
+
+// Ref provides access to the component for external references.
+func (v *StructuredViewC) Ref(f func(*StructuredViewC)) *StructuredViewC { f(v); return v }
+
+// KeyStyle sets the style of the keys, cyan by default.
+func (v *StructuredViewC) KeyStyle(s Style) *StructuredViewC {
+	v.keyStyle = s
+	v.drawn, v.drawnWidth = "", 0
+	return v
+}
+
+// Style sets the style of the values.
+func (v *StructuredViewC) Style(s Style) *StructuredViewC {
+	v.style = s
+	v.drawn, v.drawnWidth = "", 0
+	return v
+}
+
+// Grow sets the flex grow factor.
+func (v *StructuredViewC) Grow(g float32) *StructuredViewC {
+	v.grow = g
+	return v
+}
+
+// Height sets a fixed viewport height.
+func (v *StructuredViewC) Height(h int16) *StructuredViewC {
+	v.height = h
+	return v
+}
+
+// BindScroll registers keys that scroll a line down and up.
+func (v *StructuredViewC) BindScroll(down, up string) *StructuredViewC {
+	v.declaredBindings = append(v.declaredBindings,
+		binding{pattern: down, handler: func() { v.layer.ScrollDown(1) }},
+		binding{pattern: up, handler: func() { v.layer.ScrollUp(1) }},
+	)
+	return v
+}
+
+func (v *StructuredViewC) bindings() []binding { return v.declaredBindings }
+
+func (v *StructuredViewC) text() string {
+	switch c := v.content.(type) {
+	case string:
+		return c
+	case *string:
+		return *c
+	}
+	return ""
+}
+
+func (v *StructuredViewC) sync() {
+	text, w := v.text(), max(v.layer.ViewportWidth(), 1)
+	if text == v.drawn && w == v.drawnWidth && v.layer.Buffer() != nil {
+		return
+	}
+	scroll := v.layer.ScrollY()
+	if text != v.drawn {
+		scroll = 0
+	}
+	v.drawn, v.drawnWidth = text, w
+
+	var lines [][]Span
+	if fields := structFields(text); fields != nil {
+		lines = structLines(fields, w, v.keyStyle, v.style)
+	} else {
+		for _, part := range wordWrap(text, w) {
+			lines = append(lines, []Span{{Text: part, Style: v.style}})
+		}
+	}
+
+	buf := NewBuffer(w, max(len(lines), 1))
+	for y, line := range lines {
+		buf.WriteSpans(0, y, line, w)
+	}
+	v.layer.SetBuffer(buf)
+	v.layer.ScrollTo(scroll)
+}
+
+// structLines lays out fields in width columns: keys padded to the widest
+// among their siblings, nested fields indented under their key, and values
+// wrapped under themselves.
+func structLines(fields []structField, width int, keyStyle, style Style) [][]Span {
+	var lines [][]Span
+	var walk func(fields []structField, indent int)
+	walk = func(fields []structField, indent int) {
+		keyW := 0
+		for _, f := range fields {
+			keyW = max(keyW, StringWidth(f.key))
+		}
+		pad := strings.Repeat(" ", indent)
+		for _, f := range fields {
+			if f.nested {
+				lines = append(lines, []Span{{Text: pad}, {Text: f.key, Style: keyStyle}})
+				walk(f.fields, indent+2)
+				continue
+			}
+			key := f.key + strings.Repeat(" ", keyW-StringWidth(f.key)+2)
+			for i, part := range wordWrap(f.value, max(width-indent-keyW-2, 1)) {
+				if i > 0 {
+					key = strings.Repeat(" ", keyW+2)
+				}
+				lines = append(lines, []Span{{Text: pad}, {Text: key, Style: keyStyle}, {Text: part, Style: style}})
+			}
+		}
+	}
+	walk(fields, 0)
+	return lines
+}
+
+// structFields parses text as JSON or logfmt, nil for plain text.
+func structFields(text string) []structField {
+	trimmed := strings.TrimSpace(text)
+	if (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) && json.Valid([]byte(trimmed)) {
+		f := structJSON(json.RawMessage(trimmed), "")
+		if f.nested {
+			return f.fields
+		}
+	}
+	keys, f := logLineFields(text)
+	if len(keys) == 0 {
+		return nil
+	}
+	fields := make([]structField, len(keys))
+	for i, k := range keys {
+		fields[i] = structField{key: k, value: f[k]}
+	}
+	return fields
+}
+
+// structJSON turns a JSON value into a field: objects keep their key
+// order, arrays of objects or arrays list their elements as [0], [1], and
+// anything else is a value.
+func structJSON(raw json.RawMessage, key string) structField {
+	f := structField{key: key}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	switch raw[0] {
+	case '{':
+		dec.Token()
+		for dec.More() {
+			t, err := dec.Token()
+			if err != nil {
+				break
+			}
+			var v json.RawMessage
+			if dec.Decode(&v) != nil {
+				break
+			}
+			f.fields = append(f.fields, structJSON(v, t.(string)))
+		}
+		f.nested = len(f.fields) > 0
+	case '[':
+		var elems []json.RawMessage
+		json.Unmarshal(raw, &elems)
+		for i, e := range elems {
+			if e[0] == '{' || e[0] == '[' {
+				f.nested = true
+			}
+			f.fields = append(f.fields, structJSON(e, "["+strconv.Itoa(i)+"]"))
+		}
+	}
+	if !f.nested {
+		f.fields = nil
+		f.value = jsonText(raw)
+	}
+	return f
+}
+
+func (t *Template) compileStructuredViewC(v *StructuredViewC, parent int16, depth int) int16 {
+	t.collectBindings(v)
+	layerView := LayerView(v.layer).Grow(v.grow)
+	if v.height > 0 {
+		layerView = layerView.ViewHeight(v.height)
+	}
+	return t.compileLayerViewC(layerView, parent, depth)
+}
@@ -0,0 +1,168 @@
+package glyph
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseLogLineJSON(t *testing.T) {
+	line := `{"ts":"12:00:01","level":"warn","http":{"status":503,"path":"/api"},"tags":["a","b"],"ok":false}`
+	keys, _ := logLineFields(line)
+	if want := []string{"ts", "level", "http.status", "http.path", "tags", "ok"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("keys = %q, want %q", keys, want)
+	}
+	f := ParseLogLine(line)
+	want := LogFields{
+		"ts": "12:00:01", "level": "warn", "http.status": "503", "http.path": "/api",
+		"tags": `["a","b"]`, "ok": "false", "msg": line,
+	}
+	if !reflect.DeepEqual(f, want) {
+		t.Errorf("fields = %q", f)
+	}
+
+	filter, err := ParseLogFilter(`http.status >= 500 && level == warn`)
+	if err != nil || !filter.Match(line) {
+		t.Errorf("filter on nested field: match %v, err %v", filter.Match(line), err)
+	}
+
+	// a broken object is an ordinary line
+	if f := ParseLogLine(`{"level":"error" x=1`); f["x"] != "1" {
+		t.Errorf("broken JSON = %q", f)
+	}
+}
+
+func TestLogColumns(t *testing.T) {
+	input := `{"time":"10:00","level":"info","status":200,"msg":"ok"}
+{"time":"10:01","level":"error","status":500,"msg":"boom","extra":1}
+{"time":"10:02","msg":"no level"}
+`
+	lv := Log(strings.NewReader(input)).Columns("time", "level", "status", "msg").Grow(1)
+	tmpl := Build(VBox(lv))
+	time.Sleep(50 * time.Millisecond)
+	buf := NewBuffer(40, 3)
+	tmpl.Execute(buf, 40, 3)
+
+	want := []string{
+		"10:00  info   200  ok",
+		"10:01  error  500  boom",
+		"10:02              no level",
+	}
+	for i, w := range want {
+		if got := buf.GetLine(i); got != w {
+			t.Errorf("row %d = %q, want %q", i, got, w)
+		}
+	}
+	if c := buf.Get(7, 1); c.Style.FG != Red {
+		t.Errorf("error level style = %+v", c.Style)
+	}
+	if got, want := lv.Fields(), []string{"time", "level", "status", "msg", "extra"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Fields = %q, want %q", got, want)
+	}
+}
+
+func TestLogColumnsAuto(t *testing.T) {
+	input := "ts=1 level=debug msg=\"first\" id=7\nts=22 level=warn msg=second\n"
+	lv := Log(strings.NewReader(input)).Columns().Grow(1)
+	tmpl := Build(VBox(lv))
+	time.Sleep(50 * time.Millisecond)
+	buf := NewBuffer(30, 2)
+	tmpl.Execute(buf, 30, 2)
+	if buf.GetLine(0) != "1   debug  first" || buf.GetLine(1) != "22  warn   second" {
+		t.Errorf("auto columns = %q %q", buf.GetLine(0), buf.GetLine(1))
+	}
+}
+
+func TestLogDetail(t *testing.T) {
+	input := `{"level":"info","msg":"one"}
+{"level":"info","msg":"two","req":{"id":"r1","ms":12}}
+{"level":"error","msg":"three"}
+`
+	lv := Log(strings.NewReader(input)).Columns("level", "msg").BindDetail("<Enter>", "<Esc>").Grow(1)
+	tmpl := Build(VBox(lv))
+	time.Sleep(50 * time.Millisecond)
+	frame := func() *Buffer {
+		buf := NewBuffer(30, 15)
+		tmpl.Execute(buf, 30, 15)
+		return buf
+	}
+
+	// following, the newest line is selected
+	buf := frame()
+	if c := buf.Get(0, 2); c.Style.Attr&AttrInverse == 0 {
+		t.Errorf("newest line not selected: %+v", c.Style)
+	}
+	lv.nav(-1)
+	buf = frame()
+	if lv.Selected() != `{"level":"info","msg":"two","req":{"id":"r1","ms":12}}` {
+		t.Fatalf("selected = %q", lv.Selected())
+	}
+	if buf.Get(0, 2).Style.Attr&AttrInverse != 0 || buf.Get(0, 1).Style.Attr&AttrInverse == 0 {
+		t.Error("selection didn't move up a row")
+	}
+
+	lv.declaredBindings[0].handler.(func())() // open
+	buf = frame()
+	var pane []string
+	for y := range 15 {
+		if line := buf.GetLine(y); strings.HasPrefix(line, " ") || strings.Contains(line, "req") {
+			pane = append(pane, line)
+		}
+	}
+	found := false
+	for y := range 15 {
+		if buf.GetLine(y) == "msg    two" {
+			found = true
+			if want := []string{"req", "  id  r1", "  ms  12"}; !reflect.DeepEqual(pane, want) {
+				t.Errorf("nested = %q, want %q", pane, want)
+			}
+		}
+	}
+	if !found || !lv.DetailOpen() {
+		t.Errorf("detail pane not shown: open %v", lv.DetailOpen())
+	}
+
+	lv.declaredBindings[1].handler.(func())() // close
+	if buf = frame(); lv.DetailOpen() || buf.GetLine(5) != "" {
+		t.Errorf("detail pane still shown: %q", buf.GetLine(5))
+	}
+}
+
+func TestStructuredView(t *testing.T) {
+	content := `{"id":1,"name":"a long name that wraps","user":{"first":"Ada","roles":["admin","dev"]},"items":[{"n":1},{"n":2}]}`
+	v := StructuredView(&content).Grow(1)
+	tmpl := Build(VBox(v))
+	buf := NewBuffer(20, 12)
+	tmpl.Execute(buf, 20, 12)
+
+	want := []string{
+		"id     1",
+		"name   a long name",
+		"       that wraps",
+		"user",
+		"  first  Ada",
+		`  roles  ["admin","d`,
+		`         ev"]`,
+		"items",
+		"  [0]",
+		"    n  1",
+		"  [1]",
+		"    n  2",
+	}
+	for i, w := range want {
+		if got := buf.GetLine(i); got != w {
+			t.Errorf("row %d = %q, want %q", i, got, w)
+		}
+	}
+	if buf.Get(0, 0).Style.FG != Cyan {
+		t.Errorf("key style = %+v", buf.Get(0, 0).Style)
+	}
+
+	content = "plain text"
+	buf = NewBuffer(20, 2)
+	tmpl.Execute(buf, 20, 2)
+	if buf.GetLine(0) != "plain text" {
+		t.Errorf("plain = %q", buf.GetLine(0))
+	}
+}
@@ -0,0 +1,42 @@
+package glyph
+
+// Synchronized output (DEC mode 2026) has the terminal hold what it shows
+// until a frame has been written in full, rather than drawing it as it
+// arrives, so a large update such as a full-screen scroll appears at once
+// instead of tearing part way down. Screens turn it on for terminals known
+// to support it.
+
+const (
+	beginSync = "\x1b[?2026h"
+	endSync   = "\x1b[?2026l"
+)
+
+// SetSyncOutput turns synchronized output on or off. Terminals that don't
+// support it ignore it, but each frame costs the extra bytes either way.
+func (s *Screen) SetSyncOutput(on bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sync = on
+}
+
+// SyncOutput reports whether frames are wrapped in synchronized updates.
+func (s *Screen) SyncOutput() bool {
+	return s.sync
+}
+
+// beginFrame starts a synchronized update in the output buffer, if they're
+// on.
+func (s *Screen) beginFrame() {
+	if s.sync {
+		s.buf.WriteString(beginSync)
+		s.syncing = true
+	}
+}
+
+// endFrame ends the synchronized update begun by beginFrame.
+func (s *Screen) endFrame() {
+	if s.syncing {
+		s.buf.WriteString(endSync)
+		s.syncing = false
+	}
+}
@@ -0,0 +1,49 @@
+package glyph
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestSyncOutput(t *testing.T) {
+	var out bytes.Buffer
+	s := NewScreenSize(&out, 20, 4)
+	s.SetSyncOutput(true)
+
+	s.back.WriteString(0, 0, "hello", DefaultStyle())
+	s.Flush()
+	s.BufferCursor(5, 0, true, CursorBar)
+	s.FlushBuffer()
+	got := out.String()
+	if !strings.HasPrefix(got, beginSync) || !strings.HasSuffix(got, endSync) {
+		t.Errorf("frame not wrapped: %q", got)
+	}
+	if strings.Count(got, beginSync) != 1 || strings.Count(got, endSync) != 1 {
+		t.Errorf("frame wrapped more than once: %q", got)
+	}
+
+	// nothing changed, so there's nothing to hold back
+	out.Reset()
+	s.Flush()
+	s.BufferCursor(6, 0, true, CursorBar)
+	s.FlushBuffer()
+	if got := out.String(); strings.Contains(got, beginSync) || strings.Contains(got, endSync) {
+		t.Errorf("cursor-only frame wrapped: %q", got)
+	}
+
+	out.Reset()
+	s.FlushFull()
+	if got := out.String(); !strings.HasPrefix(got, beginSync) || !strings.HasSuffix(got, endSync) {
+		t.Errorf("full redraw not wrapped: %q", got)
+	}
+
+	out.Reset()
+	s.SetSyncOutput(false)
+	s.back.WriteString(0, 1, "world", DefaultStyle())
+	s.Flush()
+	s.FlushBuffer()
+	if got := out.String(); strings.Contains(got, beginSync) {
+		t.Errorf("wrapped with sync off: %q", got)
+	}
+}
@@ -0,0 +1,18 @@
+//go:build linux || darwin
+
+package sysmetrics
+
+import "golang.org/x/sys/unix"
+
+// diskUsage returns the used and total bytes of the filesystem at path.
+func diskUsage(path string) (used, total uint64, err error) {
+	var fs unix.Statfs_t
+	if err := unix.Statfs(path, &fs); err != nil {
+		return 0, 0, err
+	}
+	bsize := uint64(fs.Bsize)
+	// like df, usage is out of what's usable: blocks reserved for root
+	// count as neither used nor free
+	used = (fs.Blocks - fs.Bfree) * bsize
+	return used, used + fs.Bavail*bsize, nil
+}
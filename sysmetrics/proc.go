@@ -0,0 +1,106 @@
+package sysmetrics
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// The parsers below read the Linux /proc formats. They live outside the
+// Linux build so they can be tested anywhere.
+
+var errFormat = errors.New("sysmetrics: unexpected /proc format")
+
+// parseStat reads the aggregate cpu line of /proc/stat, returning busy and
+// total jiffies.
+func parseStat(r io.Reader) (busy, total uint64, err error) {
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		f := strings.Fields(sc.Text())
+		if len(f) < 5 || f[0] != "cpu" {
+			continue
+		}
+		// user nice system idle iowait irq softirq steal; guest time is
+		// already counted in user
+		var idle uint64
+		for i, s := range f[1:min(len(f), 9)] {
+			v, err := strconv.ParseUint(s, 10, 64)
+			if err != nil {
+				return 0, 0, err
+			}
+			total += v
+			if i == 3 || i == 4 {
+				idle += v
+			}
+		}
+		return total - idle, total, nil
+	}
+	return 0, 0, errFormat
+}
+
+// parseMeminfo reads /proc/meminfo, returning used and total bytes. Used
+// memory is what isn't available, so page cache counts as free.
+func parseMeminfo(r io.Reader) (used, total uint64, err error) {
+	var avail uint64
+	var haveTotal, haveAvail bool
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		f := strings.Fields(sc.Text())
+		if len(f) < 2 {
+			continue
+		}
+		v, err := strconv.ParseUint(f[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch f[0] {
+		case "MemTotal:":
+			total, haveTotal = v*1024, true
+		case "MemAvailable:":
+			avail, haveAvail = v*1024, true
+		}
+	}
+	if !haveTotal || !haveAvail {
+		return 0, 0, errFormat
+	}
+	return total - min(avail, total), total, nil
+}
+
+// parseNetDev reads /proc/net/dev, returning bytes received and sent
+// across every interface but loopback.
+func parseNetDev(r io.Reader) (rx, tx uint64, err error) {
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		name, rest, ok := strings.Cut(sc.Text(), ":")
+		if !ok || strings.TrimSpace(name) == "lo" {
+			continue
+		}
+		f := strings.Fields(rest)
+		if len(f) < 9 {
+			return 0, 0, errFormat
+		}
+		r, err1 := strconv.ParseUint(f[0], 10, 64)
+		t, err2 := strconv.ParseUint(f[8], 10, 64)
+		if err := errors.Join(err1, err2); err != nil {
+			return 0, 0, err
+		}
+		rx += r
+		tx += t
+	}
+	return rx, tx, sc.Err()
+}
+
+// parseLoadavg reads the one minute figure from /proc/loadavg.
+func parseLoadavg(r io.Reader) (float64, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return 0, err
+	}
+	f := strings.Fields(string(b))
+	if len(f) == 0 {
+		return 0, errFormat
+	}
+	return strconv.ParseFloat(f[0], 64)
+}
@@ -0,0 +1,215 @@
+package sysmetrics
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Process is one running process as of the last sample.
+type Process struct {
+	PID, PPID int
+	User      string
+	Name      string // executable name
+	Command   string // command line, or Name in brackets for kernel threads
+	State     string // e.g. "R" running, "S" sleeping, "Z" zombie
+	Nice      int
+	Threads   int     // 0 where the platform doesn't say
+	RSS       uint64  // resident memory in bytes
+	CPU       float64 // percent of one core since the last sample
+	Mem       float64 // percent of total memory
+
+	cpuTime time.Duration // cumulative, for CPU
+}
+
+// Processes holds the process list from the latest sample:
+//
+//	ps := sysmetrics.NewProcesses()
+//	go ps.Run(app.Context(), 2*time.Second, app.Post)
+//
+// CPU needs two samples, so it reads zero until the second.
+type Processes struct {
+	List []Process
+	Err  error // error from the last sample, if any
+
+	prev   map[int]time.Duration
+	prevAt time.Time
+}
+
+// NewProcesses creates an empty process list.
+func NewProcesses() *Processes {
+	return &Processes{}
+}
+
+// Sample lists the processes and updates List.
+func (p *Processes) Sample() error {
+	at := time.Now()
+	list, memTotal, err := readProcs()
+	p.apply(list, memTotal, at, err)
+	return err
+}
+
+// Run samples every interval until ctx is done, like Metrics.Run: the
+// list is read on the calling goroutine and handed to post to apply.
+func (p *Processes) Run(ctx context.Context, interval time.Duration, post func(func())) error {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		at := time.Now()
+		list, memTotal, err := readProcs()
+		if post == nil {
+			p.apply(list, memTotal, at, err)
+		} else {
+			post(func() { p.apply(list, memTotal, at, err) })
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// Find returns the process with the given pid, or nil.
+func (p *Processes) Find(pid int) *Process {
+	for i := range p.List {
+		if p.List[i].PID == pid {
+			return &p.List[i]
+		}
+	}
+	return nil
+}
+
+func (p *Processes) apply(list []Process, memTotal uint64, at time.Time, err error) {
+	p.Err = err
+	if err != nil {
+		return
+	}
+	secs := at.Sub(p.prevAt).Seconds()
+	prev := make(map[int]time.Duration, len(list))
+	for i := range list {
+		pr := &list[i]
+		if before, ok := p.prev[pr.PID]; ok && secs > 0 && pr.cpuTime >= before {
+			pr.CPU = (pr.cpuTime - before).Seconds() / secs * 100
+		}
+		if memTotal > 0 {
+			pr.Mem = float64(pr.RSS) / float64(memTotal) * 100
+		}
+		prev[pr.PID] = pr.cpuTime
+	}
+	p.List, p.prev, p.prevAt = list, prev, at
+}
+
+// Kill sends sig to the process pid.
+func Kill(pid int, sig os.Signal) error {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return proc.Signal(sig)
+}
+
+// clockTicks is USER_HZ, the unit of /proc CPU times. It is 100 on every
+// Linux architecture in use.
+const clockTicks = 100
+
+// parseProcStat reads /proc/<pid>/stat. The command name is in
+// parentheses and may itself hold spaces and parentheses, so fields are
+// counted from the last ')'. RSS is in pages.
+func parseProcStat(stat string) (p Process, rssPages uint64, err error) {
+	open, end := strings.IndexByte(stat, '('), strings.LastIndexByte(stat, ')')
+	if open < 0 || end < open {
+		return p, 0, errFormat
+	}
+	if p.PID, err = strconv.Atoi(strings.TrimSpace(stat[:open])); err != nil {
+		return p, 0, err
+	}
+	p.Name = stat[open+1 : end]
+	// state ppid pgrp session tty tpgid flags minflt cminflt majflt cmajflt
+	// utime stime cutime cstime priority nice threads itreal start vsize rss
+	f := strings.Fields(stat[end+1:])
+	if len(f) < 22 {
+		return p, 0, errFormat
+	}
+	p.State = f[0]
+	num := func(i int) int64 {
+		if err != nil {
+			return 0
+		}
+		var v int64
+		v, err = strconv.ParseInt(f[i], 10, 64)
+		return v
+	}
+	p.PPID = int(num(1))
+	utime, stime := num(11), num(12)
+	p.Nice, p.Threads = int(num(16)), int(num(17))
+	rssPages = uint64(num(21))
+	if err != nil {
+		return p, 0, err
+	}
+	p.cpuTime = time.Duration(utime+stime) * time.Second / clockTicks
+	return p, rssPages, nil
+}
+
+// parseCmdline reads /proc/<pid>/cmdline, whose arguments are separated
+// and ended by NULs.
+func parseCmdline(b []byte) string {
+	return strings.Join(strings.Split(strings.TrimRight(string(b), "\x00"), "\x00"), " ")
+}
+
+// psColumns are the ps fields parsePS reads, for systems without /proc.
+var psColumns = []string{"-axo", "pid=,ppid=,user=,nice=,state=,rss=,time=,comm="}
+
+// parsePS reads a line of ps output in psColumns' order. The command comes
+// last as it may hold spaces.
+func parsePS(line string) (p Process, err error) {
+	f := strings.Fields(line)
+	if len(f) < 8 {
+		return p, errFormat
+	}
+	var rss uint64
+	p.PID, err = strconv.Atoi(f[0])
+	if err == nil {
+		p.PPID, err = strconv.Atoi(f[1])
+	}
+	if err == nil {
+		p.Nice, err = strconv.Atoi(f[3])
+	}
+	if err == nil {
+		rss, err = strconv.ParseUint(f[5], 10, 64)
+	}
+	if err == nil {
+		p.cpuTime, err = parsePSTime(f[6])
+	}
+	if err != nil {
+		return p, err
+	}
+	p.User, p.State, p.RSS = f[2], f[4][:1], rss*1024
+	p.Command = strings.Join(f[7:], " ")
+	p.Name = filepath.Base(p.Command)
+	return p, nil
+}
+
+// parsePSTime reads ps's cumulative CPU time, [[dd-]hh:]mm:ss[.ss].
+func parsePSTime(s string) (time.Duration, error) {
+	var days int64
+	if d, rest, ok := strings.Cut(s, "-"); ok {
+		n, err := strconv.ParseInt(d, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		days, s = n, rest
+	}
+	var total float64
+	for part := range strings.SplitSeq(s, ":") {
+		v, err := strconv.ParseFloat(part, 64)
+		if err != nil {
+			return 0, err
+		}
+		total = total*60 + v
+	}
+	return time.Duration(days)*24*time.Hour + time.Duration(total*float64(time.Second)), nil
+}
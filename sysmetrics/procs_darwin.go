@@ -0,0 +1,31 @@
+package sysmetrics
+
+import (
+	"bufio"
+	"bytes"
+	"os/exec"
+
+	"golang.org/x/sys/unix"
+)
+
+// readProcs lists the processes through ps, as reading other processes'
+// details directly takes cgo on macOS, and returns them with the total
+// memory.
+func readProcs() ([]Process, uint64, error) {
+	memTotal, err := unix.SysctlUint64("hw.memsize")
+	if err != nil {
+		return nil, 0, err
+	}
+	out, err := exec.Command("ps", psColumns...).Output()
+	if err != nil {
+		return nil, 0, err
+	}
+	var list []Process
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		if p, err := parsePS(sc.Text()); err == nil {
+			list = append(list, p)
+		}
+	}
+	return list, memTotal, sc.Err()
+}
@@ -0,0 +1,72 @@
+package sysmetrics
+
+import (
+	"io"
+	"os"
+	"os/user"
+	"strconv"
+	"sync"
+	"syscall"
+)
+
+// readProcs lists the processes in /proc, skipping any that exit while
+// being read, and returns them with the total memory.
+func readProcs() ([]Process, uint64, error) {
+	var memTotal uint64
+	if err := readProc("/proc/meminfo", func(r io.Reader) (err error) {
+		_, memTotal, err = parseMeminfo(r)
+		return err
+	}); err != nil {
+		return nil, 0, err
+	}
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, 0, err
+	}
+	pageSize := uint64(os.Getpagesize())
+	list := make([]Process, 0, len(entries))
+	for _, e := range entries {
+		if _, err := strconv.Atoi(e.Name()); err != nil {
+			continue
+		}
+		dir := "/proc/" + e.Name()
+		stat, err := os.ReadFile(dir + "/stat")
+		if err != nil {
+			continue
+		}
+		p, rss, err := parseProcStat(string(stat))
+		if err != nil {
+			continue
+		}
+		p.RSS = rss * pageSize
+		if cmd, err := os.ReadFile(dir + "/cmdline"); err == nil && len(cmd) > 0 {
+			p.Command = parseCmdline(cmd)
+		} else {
+			p.Command = "[" + p.Name + "]"
+		}
+		if fi, err := os.Stat(dir); err == nil {
+			if st, ok := fi.Sys().(*syscall.Stat_t); ok {
+				p.User = userName(st.Uid)
+			}
+		}
+		list = append(list, p)
+	}
+	return list, memTotal, nil
+}
+
+// userNames caches user lookups by uid, as there are far fewer users than
+// processes.
+var userNames sync.Map
+
+func userName(uid uint32) string {
+	if name, ok := userNames.Load(uid); ok {
+		return name.(string)
+	}
+	id := strconv.FormatUint(uint64(uid), 10)
+	name := id
+	if u, err := user.LookupId(id); err == nil {
+		name = u.Username
+	}
+	userNames.Store(uid, name)
+	return name
+}
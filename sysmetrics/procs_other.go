@@ -0,0 +1,9 @@
+//go:build !linux && !darwin
+
+package sysmetrics
+
+import "errors"
+
+func readProcs() ([]Process, uint64, error) {
+	return nil, 0, errors.ErrUnsupported
+}
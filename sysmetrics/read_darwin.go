@@ -0,0 +1,48 @@
+package sysmetrics
+
+import (
+	"errors"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// read samples memory, disk, network and load through sysctl. macOS only
+// reports CPU ticks through Mach calls that need cgo, so CPU stays zero.
+func read(path string) (sample, error) {
+	s := sample{at: time.Now()}
+
+	total, err := unix.SysctlUint64("hw.memsize")
+	if err != nil {
+		return s, err
+	}
+	pageSize, err1 := unix.SysctlUint32("vm.pagesize")
+	free, err2 := unix.SysctlUint32("vm.page_free_count")
+	spec, err3 := unix.SysctlUint32("vm.page_speculative_count")
+	cache, err4 := unix.SysctlUint32("vm.page_pageable_external_count")
+	if err := errors.Join(err1, err2, err3, err4); err != nil {
+		return s, err
+	}
+	// as on Linux, file cache the kernel can drop counts as free
+	avail := (uint64(free) + uint64(spec) + uint64(cache)) * uint64(pageSize)
+	s.memUsed, s.memTotal = total-min(avail, total), total
+
+	ifs, err := unix.SysctlRaw("net.route", 0, 0, unix.NET_RT_IFLIST2, 0)
+	if err != nil {
+		return s, err
+	}
+	if s.rx, s.tx, err = parseIfList2(ifs); err != nil {
+		return s, err
+	}
+
+	load, err := unix.SysctlRaw("vm.loadavg")
+	if err != nil {
+		return s, err
+	}
+	if s.load, err = parseLoadavgRaw(load); err != nil {
+		return s, err
+	}
+
+	s.diskUsed, s.diskTotal, err = diskUsage(path)
+	return s, err
+}
@@ -0,0 +1,48 @@
+package sysmetrics
+
+import (
+	"io"
+	"os"
+	"time"
+)
+
+func read(path string) (sample, error) {
+	s := sample{at: time.Now()}
+	var err error
+	if err = readProc("/proc/stat", func(r io.Reader) error {
+		s.cpuBusy, s.cpuTotal, err = parseStat(r)
+		return err
+	}); err != nil {
+		return s, err
+	}
+	if err = readProc("/proc/meminfo", func(r io.Reader) error {
+		s.memUsed, s.memTotal, err = parseMeminfo(r)
+		return err
+	}); err != nil {
+		return s, err
+	}
+	if err = readProc("/proc/net/dev", func(r io.Reader) error {
+		s.rx, s.tx, err = parseNetDev(r)
+		return err
+	}); err != nil {
+		return s, err
+	}
+	if err = readProc("/proc/loadavg", func(r io.Reader) error {
+		s.load, err = parseLoadavg(r)
+		return err
+	}); err != nil {
+		return s, err
+	}
+
+	s.diskUsed, s.diskTotal, err = diskUsage(path)
+	return s, err
+}
+
+func readProc(name string, parse func(io.Reader) error) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return parse(f)
+}
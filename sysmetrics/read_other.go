@@ -0,0 +1,9 @@
+//go:build !linux && !darwin
+
+package sysmetrics
+
+import "errors"
+
+func read(path string) (sample, error) {
+	return sample{}, errors.ErrUnsupported
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+
+package sysmetrics
+
+import "errors"
+
+// Renice sets the nice value of the process pid.
+func Renice(pid, nice int) error {
+	return errors.ErrUnsupported
+}
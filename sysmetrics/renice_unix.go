@@ -0,0 +1,11 @@
+//go:build linux || darwin
+
+package sysmetrics
+
+import "golang.org/x/sys/unix"
+
+// Renice sets the nice value of the process pid, from -20 to 19. Lowering
+// it, to raise the process's priority, usually takes root.
+func Renice(pid, nice int) error {
+	return unix.Setpriority(unix.PRIO_PROCESS, pid, nice)
+}
@@ -0,0 +1,56 @@
+package sysmetrics
+
+import "encoding/binary"
+
+// The parsers below read the raw sysctl records macOS returns. Like the
+// /proc parsers they build everywhere so they can be tested anywhere.
+
+// Offsets into struct if_msghdr2, whose if_data64 starts at byte 32.
+const (
+	ifmType     = 3
+	ifmFlags    = 8
+	ifmIbytes   = 32 + 64
+	ifmObytes   = 32 + 72
+	ifmMinLen   = ifmObytes + 8
+	rtmIfinfo2  = 0x12
+	iffLoopback = 0x8
+)
+
+// parseIfList2 walks the RTM_IFINFO2 messages of a NET_RT_IFLIST2 route
+// dump, returning bytes received and sent across every interface but
+// loopback.
+func parseIfList2(b []byte) (rx, tx uint64, err error) {
+	for len(b) >= 4 {
+		n := int(binary.LittleEndian.Uint16(b))
+		if n < 4 || n > len(b) {
+			return 0, 0, errFormat
+		}
+		msg := b[:n]
+		b = b[n:]
+		if msg[ifmType] != rtmIfinfo2 {
+			continue
+		}
+		if n < ifmMinLen {
+			return 0, 0, errFormat
+		}
+		if binary.LittleEndian.Uint32(msg[ifmFlags:])&iffLoopback != 0 {
+			continue
+		}
+		rx += binary.LittleEndian.Uint64(msg[ifmIbytes:])
+		tx += binary.LittleEndian.Uint64(msg[ifmObytes:])
+	}
+	return rx, tx, nil
+}
+
+// parseLoadavgRaw reads the one minute figure from a struct loadavg: three
+// fixed-point uint32 averages, then the long scale they're out of.
+func parseLoadavgRaw(b []byte) (float64, error) {
+	if len(b) < 24 {
+		return 0, errFormat
+	}
+	scale := binary.LittleEndian.Uint64(b[16:])
+	if scale == 0 {
+		return 0, errFormat
+	}
+	return float64(binary.LittleEndian.Uint32(b)) / float64(scale), nil
+}
@@ -0,0 +1,159 @@
+// Package sysmetrics samples CPU, memory, disk and network usage into plain
+// fields that glyph components bind to directly:
+//
+//	m := sysmetrics.New()
+//	app.SetView(VBox(
+//	    Progress(&m.CPU),
+//	    Sparkline(&m.CPUHistory),
+//	    Text(&m.NetText),
+//	))
+//	go m.Run(app.Context(), time.Second, app.Post)
+//
+// Readings come from /proc on Linux and sysctl on macOS, where CPU reads
+// zero: its tick counters are only reachable through cgo. Elsewhere Sample
+// returns errors.ErrUnsupported.
+package sysmetrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/kungfusheep/glyph/format"
+)
+
+// Metrics holds the latest readings. Rates and CPU need two samples, so
+// they read zero until the second.
+type Metrics struct {
+	CPU  int // busy across all cores, 0-100
+	Mem  int // memory in use, 0-100
+	Disk int // space used on Path's filesystem, 0-100
+
+	MemUsed, MemTotal   uint64  // bytes
+	DiskUsed, DiskTotal uint64  // bytes
+	NetRx, NetTx        float64 // bytes per second, loopback excluded
+	Load                float64 // one minute load average
+
+	// Histories of the readings above, oldest first, for Sparkline.
+	CPUHistory, MemHistory, NetRxHistory, NetTxHistory []float64
+
+	// Short labels for Text, e.g. "42%" and "↓1.2Mi/s ↑340Ki/s".
+	CPUText, MemText, DiskText, NetText string
+
+	Path    string // filesystem Disk reports on; "/" by default
+	History int    // samples kept in each history; 60 by default
+	Err     error  // error from the last sample, if any
+
+	prev *sample
+}
+
+// New creates metrics for the root filesystem keeping 60 samples.
+func New() *Metrics {
+	return &Metrics{Path: "/", History: 60}
+}
+
+// sample is one raw reading. The counters are cumulative.
+type sample struct {
+	at                  time.Time
+	cpuBusy, cpuTotal   uint64
+	memUsed, memTotal   uint64
+	diskUsed, diskTotal uint64
+	rx, tx              uint64
+	load                float64
+}
+
+// Sample takes a reading and updates the fields.
+func (m *Metrics) Sample() error {
+	s, err := read(m.path())
+	m.apply(s, err)
+	return err
+}
+
+// Run samples every interval until ctx is done. Readings are taken on the
+// calling goroutine and handed to post to update the fields, so pass
+// app.Post to keep them on the UI goroutine, or nil to update directly.
+func (m *Metrics) Run(ctx context.Context, interval time.Duration, post func(func())) error {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	path := m.path()
+	for {
+		s, err := read(path)
+		if post == nil {
+			m.apply(s, err)
+		} else {
+			post(func() { m.apply(s, err) })
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+func (m *Metrics) path() string {
+	if m.Path == "" {
+		return "/"
+	}
+	return m.Path
+}
+
+func (m *Metrics) apply(s sample, err error) {
+	m.Err = err
+	if err != nil {
+		return
+	}
+	if p := m.prev; p != nil {
+		if dt := counterDelta(s.cpuTotal, p.cpuTotal); dt > 0 {
+			m.CPU = percent(float64(counterDelta(s.cpuBusy, p.cpuBusy)), float64(dt))
+		}
+		if secs := s.at.Sub(p.at).Seconds(); secs > 0 {
+			m.NetRx = float64(counterDelta(s.rx, p.rx)) / secs
+			m.NetTx = float64(counterDelta(s.tx, p.tx)) / secs
+		}
+	}
+	m.prev = &s
+
+	m.MemUsed, m.MemTotal = s.memUsed, s.memTotal
+	m.Mem = percent(float64(s.memUsed), float64(s.memTotal))
+	m.DiskUsed, m.DiskTotal = s.diskUsed, s.diskTotal
+	m.Disk = percent(float64(s.diskUsed), float64(s.diskTotal))
+	m.Load = s.load
+
+	n := m.History
+	if n <= 0 {
+		n = 60
+	}
+	m.CPUHistory = push(m.CPUHistory, float64(m.CPU), n)
+	m.MemHistory = push(m.MemHistory, float64(m.Mem), n)
+	m.NetRxHistory = push(m.NetRxHistory, m.NetRx, n)
+	m.NetTxHistory = push(m.NetTxHistory, m.NetTx, n)
+
+	m.CPUText = format.Percent(float64(m.CPU)/100, 0)
+	m.MemText = format.IEC(float64(m.MemUsed), 1) + "/" + format.IEC(float64(m.MemTotal), 1)
+	m.DiskText = format.Percent(float64(m.Disk)/100, 0)
+	m.NetText = "↓" + format.IEC(m.NetRx, 1) + "/s ↑" + format.IEC(m.NetTx, 1) + "/s"
+}
+
+// counterDelta is how far a counter moved, treating a reset as zero.
+func counterDelta(cur, prev uint64) uint64 {
+	if cur < prev {
+		return 0
+	}
+	return cur - prev
+}
+
+func percent(part, whole float64) int {
+	if whole <= 0 {
+		return 0
+	}
+	return min(max(int(part/whole*100+0.5), 0), 100)
+}
+
+// push appends v, dropping the oldest values beyond n.
+func push(h []float64, v float64, n int) []float64 {
+	h = append(h, v)
+	if len(h) > n {
+		h = append(h[:0], h[len(h)-n:]...)
+	}
+	return h
+}
@@ -0,0 +1,198 @@
+package sysmetrics
+
+import (
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseStat(t *testing.T) {
+	stat := `cpu  100 5 50 800 40 3 2 0 10 0
+cpu0 50 2 25 400 20 1 1 0 5 0
+intr 12345
+`
+	busy, total, err := parseStat(strings.NewReader(stat))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if busy != 160 || total != 1000 {
+		t.Errorf("busy, total = %d, %d; want 160, 1000", busy, total)
+	}
+}
+
+func TestParseMeminfo(t *testing.T) {
+	info := `MemTotal:       16000000 kB
+MemFree:         2000000 kB
+MemAvailable:   12000000 kB
+Buffers:          300000 kB
+`
+	used, total, err := parseMeminfo(strings.NewReader(info))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if used != 4000000*1024 || total != 16000000*1024 {
+		t.Errorf("used, total = %d, %d", used, total)
+	}
+	if _, _, err := parseMeminfo(strings.NewReader("MemTotal: 1 kB\n")); err == nil {
+		t.Error("missing MemAvailable should be an error")
+	}
+}
+
+func TestParseNetDev(t *testing.T) {
+	dev := `Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo: 9999       10    0    0    0     0          0         0     9999      10    0    0    0     0       0          0
+  eth0: 1000       10    0    0    0     0          0         0      400       5    0    0    0     0       0          0
+ wlan0:  500        4    0    0    0     0          0         0      100       2    0    0    0     0       0          0
+`
+	rx, tx, err := parseNetDev(strings.NewReader(dev))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rx != 1500 || tx != 500 {
+		t.Errorf("rx, tx = %d, %d; want 1500, 500", rx, tx)
+	}
+}
+
+func TestParseIfList2(t *testing.T) {
+	msg := func(typ byte, flags uint32, rx, tx uint64) []byte {
+		b := make([]byte, 160)
+		binary.LittleEndian.PutUint16(b, 160)
+		b[ifmType] = typ
+		binary.LittleEndian.PutUint32(b[ifmFlags:], flags)
+		binary.LittleEndian.PutUint64(b[ifmIbytes:], rx)
+		binary.LittleEndian.PutUint64(b[ifmObytes:], tx)
+		return b
+	}
+	var dump []byte
+	dump = append(dump, msg(rtmIfinfo2, iffLoopback, 9999, 9999)...)
+	dump = append(dump, msg(rtmIfinfo2, 0, 1000, 400)...)
+	dump = append(dump, msg(0x0c, 0, 7777, 7777)...) // an address message
+	dump = append(dump, msg(rtmIfinfo2, 0, 500, 100)...)
+	rx, tx, err := parseIfList2(dump)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rx != 1500 || tx != 500 {
+		t.Errorf("rx, tx = %d, %d; want 1500, 500", rx, tx)
+	}
+	if _, _, err := parseIfList2(dump[:170]); err == nil {
+		t.Error("expected an error for a truncated dump")
+	}
+}
+
+func TestParseLoadavgRaw(t *testing.T) {
+	b := make([]byte, 24)
+	binary.LittleEndian.PutUint32(b, 1536)
+	binary.LittleEndian.PutUint64(b[16:], 2048)
+	if got, err := parseLoadavgRaw(b); err != nil || got != 0.75 {
+		t.Errorf("load = %v, %v; want 0.75", got, err)
+	}
+	if _, err := parseLoadavgRaw(b[:12]); err == nil {
+		t.Error("expected an error for a short record")
+	}
+}
+
+func TestApply(t *testing.T) {
+	m := New()
+	m.History = 2
+	at := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	m.apply(sample{at: at, cpuBusy: 100, cpuTotal: 1000, memUsed: 1 << 30, memTotal: 4 << 30, rx: 1000, tx: 0}, nil)
+	if m.CPU != 0 || m.NetRx != 0 {
+		t.Errorf("first sample has no rates: CPU %d NetRx %v", m.CPU, m.NetRx)
+	}
+	if m.Mem != 25 || m.MemText != "1.0Gi/4.0Gi" {
+		t.Errorf("Mem = %d %q", m.Mem, m.MemText)
+	}
+
+	m.apply(sample{at: at.Add(2 * time.Second), cpuBusy: 400, cpuTotal: 1500, memTotal: 4 << 30, rx: 5096, tx: 2048}, nil)
+	if m.CPU != 60 || m.CPUText != "60%" {
+		t.Errorf("CPU = %d %q, want 60", m.CPU, m.CPUText)
+	}
+	if m.NetRx != 2048 || m.NetTx != 1024 || m.NetText != "↓2.0Ki/s ↑1.0Ki/s" {
+		t.Errorf("net = %v %v %q", m.NetRx, m.NetTx, m.NetText)
+	}
+
+	m.apply(sample{at: at.Add(3 * time.Second), cpuBusy: 400, cpuTotal: 2000, memTotal: 4 << 30, rx: 5096, tx: 2048}, nil)
+	if len(m.CPUHistory) != 2 || m.CPUHistory[0] != 60 || m.CPUHistory[1] != 0 {
+		t.Errorf("CPUHistory = %v", m.CPUHistory)
+	}
+
+	errBoom := errors.New("boom")
+	m.apply(sample{}, errBoom)
+	if m.Err != errBoom || len(m.CPUHistory) != 2 {
+		t.Errorf("error sample changed readings: Err %v history %v", m.Err, m.CPUHistory)
+	}
+}
+
+func TestSample(t *testing.T) {
+	m := New()
+	err := m.Sample()
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip("not supported on this platform")
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.MemTotal == 0 || m.Mem <= 0 || m.DiskTotal == 0 {
+		t.Errorf("implausible readings: %+v", m)
+	}
+}
+
+func TestParseProcStat(t *testing.T) {
+	stat := "4242 (tmux: server (1)) S 1 4242 4242 0 -1 4194624 900 0 0 0 250 50 0 0 20 5 3 0 12345 9000000 512 18446744073709551615\n"
+	p, rss, err := parseProcStat(stat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.PID != 4242 || p.PPID != 1 || p.Name != "tmux: server (1)" || p.State != "S" {
+		t.Errorf("process = %+v", p)
+	}
+	if p.Nice != 5 || p.Threads != 3 || rss != 512 || p.cpuTime != 3*time.Second {
+		t.Errorf("nice %d, threads %d, rss %d, cpu %v", p.Nice, p.Threads, rss, p.cpuTime)
+	}
+	if _, _, err := parseProcStat("12 (short) S 1"); err == nil {
+		t.Error("truncated stat should be an error")
+	}
+	if got := parseCmdline([]byte("vim\x00-R\x00notes.md\x00")); got != "vim -R notes.md" {
+		t.Errorf("cmdline = %q", got)
+	}
+}
+
+func TestParsePS(t *testing.T) {
+	p, err := parsePS("  501   1 alice      0 Ss    20480 1-02:03:04.50 /Applications/Some App.app/Contents/MacOS/Some App")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.PID != 501 || p.User != "alice" || p.State != "S" || p.RSS != 20480*1024 || p.Name != "Some App" {
+		t.Errorf("process = %+v", p)
+	}
+	if want := 26*time.Hour + 3*time.Minute + 4500*time.Millisecond; p.cpuTime != want {
+		t.Errorf("cpu time = %v, want %v", p.cpuTime, want)
+	}
+	if d, err := parsePSTime("0:01.25"); err != nil || d != 1250*time.Millisecond {
+		t.Errorf("parsePSTime = %v, %v", d, err)
+	}
+}
+
+func TestProcessesApply(t *testing.T) {
+	var ps Processes
+	at := time.Unix(100, 0)
+	ps.apply([]Process{{PID: 1, RSS: 1 << 20, cpuTime: time.Second}}, 4<<20, at, nil)
+	if ps.List[0].CPU != 0 || ps.List[0].Mem != 25 {
+		t.Errorf("first sample = %+v", ps.List[0])
+	}
+	ps.apply([]Process{{PID: 1, cpuTime: 2 * time.Second}, {PID: 2, cpuTime: time.Second}}, 4<<20, at.Add(2*time.Second), nil)
+	if ps.List[0].CPU != 50 || ps.List[1].CPU != 0 {
+		t.Errorf("CPU = %v, %v; want 50, 0 for the new process", ps.List[0].CPU, ps.List[1].CPU)
+	}
+	if ps.Find(2) == nil || ps.Find(3) != nil {
+		t.Error("Find")
+	}
+	ps.apply(nil, 0, at.Add(3*time.Second), errors.ErrUnsupported)
+	if len(ps.List) != 2 || ps.Err == nil {
+		t.Error("a failed sample should keep the list and set Err")
+	}
+}
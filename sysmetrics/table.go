@@ -0,0 +1,297 @@
+package sysmetrics
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/kungfusheep/glyph"
+	"github.com/kungfusheep/glyph/format"
+)
+
+// ProcessSort is a column the process table is sorted by.
+type ProcessSort int
+
+const (
+	SortCPU ProcessSort = iota // busiest first
+	SortMem                    // largest first
+	SortPID
+	SortUser
+	SortName
+)
+
+var sortNames = [...]string{SortCPU: "CPU", SortMem: "memory", SortPID: "PID", SortUser: "user", SortName: "name"}
+
+func (s ProcessSort) String() string { return sortNames[s] }
+
+// ProcessTable is an htop-style process list made of stock glyph
+// components, as a starting point for a process manager:
+//
+//	pt := sysmetrics.NewProcessTable(sysmetrics.NewProcesses())
+//	app.SetView(pt.View())
+//	go pt.Run(app.Context(), 2*time.Second, app.Post)
+//
+// Typing searches. Up and down move, F5 shows the processes as a tree,
+// F6 sorts by the next column, F7 and F8 lower and raise the selected
+// process's nice value, and F9 picks a signal to send it. Nothing is sent
+// or reniced until confirmed in a menu.
+type ProcessTable struct {
+	Procs   *Processes
+	Summary string // e.g. "214 processes · sorted by CPU"
+	Message string // the outcome of the last action, or the keys
+
+	sort ProcessSort
+	tree bool
+
+	rows    []processRow
+	header  string
+	list    *glyph.FilterListC[processRow]
+	signals *glyph.ContextMenuC
+	confirm *glyph.ContextMenuC
+	asking  []glyph.MenuItem // confirm's items; the first is the question
+	target  Process          // the process the open menus act on
+}
+
+// processRow is a process and its line in the table.
+type processRow struct {
+	Process
+	line string
+	pad  string // tree indent
+}
+
+// processKeys is the Message shown until an action reports.
+const processKeys = "F5 tree  F6 sort  F7 nice-  F8 nice+  F9 signal  Esc clear search"
+
+// NewProcessTable creates a table of p's processes, sorted by CPU.
+func NewProcessTable(p *Processes) *ProcessTable {
+	t := &ProcessTable{Procs: p, Message: processKeys}
+	t.asking = []glyph.MenuItem{{}, {Label: "Cancel"}}
+	t.confirm = glyph.ContextMenu(glyph.Text(&t.header).Bold(), t.asking...)
+	t.list = glyph.FilterList(&t.rows, func(r *processRow) string {
+		return fmt.Sprintf("%d %s %s", r.PID, r.User, r.Command)
+	}).
+		Placeholder("search").
+		Header(t.confirm).
+		Render(func(r *processRow) any { return glyph.Text(&r.line) }).
+		BindNav("<Down>", "<Up>").
+		HandleClear("<Esc>", nil).
+		Handle("<F5>", func(*processRow) { t.SetTree(!t.tree) }).
+		Handle("<F6>", func(*processRow) { t.SortBy((t.sort + 1) % ProcessSort(len(sortNames))) }).
+		Handle("<F7>", func(r *processRow) { t.askRenice(r.Process, r.Nice-1) }).
+		Handle("<F8>", func(r *processRow) { t.askRenice(r.Process, r.Nice+1) }).
+		Handle("<F9>", func(r *processRow) {
+			t.target = r.Process
+			t.signals.Open()
+		})
+
+	var items []glyph.MenuItem
+	for _, s := range []struct {
+		label, name string
+		sig         syscall.Signal
+	}{
+		{"Terminate", "SIGTERM", syscall.SIGTERM},
+		{"Kill", "SIGKILL", syscall.SIGKILL},
+		{"Interrupt", "SIGINT", syscall.SIGINT},
+		{"Hang up", "SIGHUP", syscall.SIGHUP},
+	} {
+		items = append(items, glyph.MenuItem{
+			Label:  fmt.Sprintf("%-10s %s", s.label, s.name),
+			Action: func() { t.askSignal(s.name, s.sig) },
+		})
+	}
+	t.signals = glyph.ContextMenu(glyph.Text(&t.Summary), items...)
+	t.Refresh()
+	return t
+}
+
+// View returns the view to show: the summary, search, column titles and
+// process list, and the last action's outcome.
+func (t *ProcessTable) View() any {
+	return glyph.VBox(
+		t.signals,
+		t.list,
+		glyph.Text(&t.Message).Dim(),
+	)
+}
+
+// MaxVisible sets how many processes are listed at once.
+func (t *ProcessTable) MaxVisible(n int) *ProcessTable {
+	t.list.MaxVisible(n)
+	return t
+}
+
+// Run samples the processes every interval until ctx is done, like
+// Processes.Run, refreshing the table after each sample.
+func (t *ProcessTable) Run(ctx context.Context, interval time.Duration, post func(func())) error {
+	return t.Procs.Run(ctx, interval, func(apply func()) {
+		update := func() {
+			apply()
+			t.Refresh()
+		}
+		if post == nil {
+			update()
+		} else {
+			post(update)
+		}
+	})
+}
+
+// SortBy sorts the table by a column.
+func (t *ProcessTable) SortBy(s ProcessSort) {
+	t.sort = s
+	t.Refresh()
+}
+
+// SetTree shows the processes under their parents, each set of children
+// sorted, or as one sorted list.
+func (t *ProcessTable) SetTree(on bool) {
+	t.tree = on
+	t.Refresh()
+}
+
+// Selected returns the selected process, or nil if none is listed.
+func (t *ProcessTable) Selected() *Process {
+	if r := t.list.Selected(); r != nil {
+		return &r.Process
+	}
+	return nil
+}
+
+// Refresh rebuilds the table from Procs, keeping the selection on the same
+// process.
+func (t *ProcessTable) Refresh() {
+	selected := -1
+	if p := t.Selected(); p != nil {
+		selected = p.PID
+	}
+	t.rows = orderProcesses(t.rows[:0], t.Procs.List, t.sort, t.tree)
+	for i := range t.rows {
+		t.rows[i].line = processLine(&t.rows[i])
+	}
+	t.list.Refresh()
+	for i, r := range t.list.Filter().Items {
+		if r.PID == selected {
+			t.list.SetIndex(i)
+			break
+		}
+	}
+
+	t.header = processHeader(t.sort)
+	t.Summary = fmt.Sprintf("%d processes · sorted by %s", len(t.rows), t.sort)
+	if t.tree {
+		t.Summary += " · tree"
+	}
+	if t.Procs.Err != nil {
+		t.Summary += " · " + t.Procs.Err.Error()
+	}
+}
+
+func (t *ProcessTable) askSignal(name string, sig syscall.Signal) {
+	p := t.target
+	t.ask(fmt.Sprintf("Send %s to %d %s", name, p.PID, p.Name), func() error {
+		return Kill(p.PID, sig)
+	})
+}
+
+func (t *ProcessTable) askRenice(p Process, nice int) {
+	nice = min(max(nice, -20), 19)
+	t.ask(fmt.Sprintf("Renice %d %s from %d to %d", p.PID, p.Name, p.Nice, nice), func() error {
+		return Renice(p.PID, nice)
+	})
+}
+
+// ask opens the confirm menu, running act and reporting its outcome if the
+// question is chosen.
+func (t *ProcessTable) ask(question string, act func() error) {
+	t.asking[0] = glyph.MenuItem{Label: question, Action: func() {
+		if err := act(); err != nil {
+			t.Message = question + ": " + err.Error()
+			return
+		}
+		t.Message = "Done: " + question
+		t.Procs.Sample()
+		t.Refresh()
+	}}
+	t.confirm.Open()
+}
+
+// orderProcesses appends procs to rows sorted by s, or as a tree with
+// each parent's children sorted by s beneath it.
+func orderProcesses(rows []processRow, procs []Process, s ProcessSort, tree bool) []processRow {
+	sorted := slices.Clone(procs)
+	slices.SortStableFunc(sorted, func(a, b Process) int { return compareProcesses(&a, &b, s) })
+	if !tree {
+		for _, p := range sorted {
+			rows = append(rows, processRow{Process: p})
+		}
+		return rows
+	}
+
+	children := make(map[int][]Process)
+	pids := make(map[int]bool, len(sorted))
+	for _, p := range sorted {
+		pids[p.PID] = true
+	}
+	var roots []Process
+	for _, p := range sorted {
+		if p.PPID == p.PID || !pids[p.PPID] {
+			roots = append(roots, p)
+		} else {
+			children[p.PPID] = append(children[p.PPID], p)
+		}
+	}
+	var walk func(p Process, pad, indent string)
+	walk = func(p Process, pad, indent string) {
+		rows = append(rows, processRow{Process: p, pad: pad})
+		kids := children[p.PID]
+		for i, c := range kids {
+			if i == len(kids)-1 {
+				walk(c, indent+"└─ ", indent+"   ")
+			} else {
+				walk(c, indent+"├─ ", indent+"│  ")
+			}
+		}
+	}
+	for _, r := range roots {
+		walk(r, "", "")
+	}
+	return rows
+}
+
+func compareProcesses(a, b *Process, s ProcessSort) int {
+	var c int
+	switch s {
+	case SortCPU:
+		c = cmp.Compare(b.CPU, a.CPU)
+	case SortMem:
+		c = cmp.Compare(b.RSS, a.RSS)
+	case SortUser:
+		c = strings.Compare(a.User, b.User)
+	case SortName:
+		c = strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	}
+	if c == 0 {
+		c = cmp.Compare(a.PID, b.PID)
+	}
+	return c
+}
+
+// processColumns lays out a line of the table.
+const processColumns = "%7s %-9.9s %3s %6s %5s %7s  %s"
+
+func processHeader(s ProcessSort) string {
+	titles := map[ProcessSort]string{SortPID: "PID", SortUser: "USER", SortCPU: "CPU%", SortMem: "MEM%", SortName: "COMMAND"}
+	titles[s] += "▾"
+	return "  " + fmt.Sprintf(processColumns, titles[SortPID], titles[SortUser], "NI", titles[SortCPU], titles[SortMem], "RES", titles[SortName])
+}
+
+func processLine(r *processRow) string {
+	return fmt.Sprintf(processColumns,
+		fmt.Sprint(r.PID), r.User, fmt.Sprint(r.Nice),
+		fmt.Sprintf("%.1f", r.CPU), fmt.Sprintf("%.1f", r.Mem),
+		format.IEC(float64(r.RSS), 0), r.pad+r.Command)
+}
@@ -0,0 +1,78 @@
+package sysmetrics
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/kungfusheep/glyph"
+)
+
+func testProcesses() *Processes {
+	return &Processes{List: []Process{
+		{PID: 1, User: "root", Name: "init", Command: "/sbin/init", CPU: 0.5, RSS: 8 << 20},
+		{PID: 20, PPID: 1, User: "alice", Name: "sshd", Command: "sshd", CPU: 2},
+		{PID: 30, PPID: 20, User: "alice", Name: "bash", Command: "-bash", CPU: 40, RSS: 1 << 20},
+		{PID: 40, PPID: 1, User: "bob", Name: "cron", Command: "cron", CPU: 1},
+	}}
+}
+
+func TestOrderProcesses(t *testing.T) {
+	pids := func(rows []processRow) (out []int) {
+		for _, r := range rows {
+			out = append(out, r.PID)
+		}
+		return out
+	}
+	procs := testProcesses().List
+	if got := pids(orderProcesses(nil, procs, SortCPU, false)); !slices.Equal(got, []int{30, 20, 40, 1}) {
+		t.Errorf("by CPU = %v", got)
+	}
+	if got := pids(orderProcesses(nil, procs, SortUser, false)); !slices.Equal(got, []int{20, 30, 40, 1}) {
+		t.Errorf("by user = %v", got)
+	}
+
+	tree := orderProcesses(nil, procs, SortCPU, true)
+	if got := pids(tree); !slices.Equal(got, []int{1, 20, 30, 40}) {
+		t.Errorf("tree = %v", got)
+	}
+	if tree[1].pad != "├─ " || tree[2].pad != "│  └─ " || tree[3].pad != "└─ " {
+		t.Errorf("tree pads = %q %q %q", tree[1].pad, tree[2].pad, tree[3].pad)
+	}
+}
+
+func TestProcessTable(t *testing.T) {
+	pt := NewProcessTable(testProcesses())
+	tmpl := glyph.Build(pt.View())
+	frame := func() *glyph.Buffer {
+		buf := glyph.NewBuffer(70, 8)
+		tmpl.Execute(buf, 70, 8)
+		return buf
+	}
+
+	buf := frame()
+	if got := buf.GetLine(0); !strings.Contains(got, "4 processes · sorted by CPU") {
+		t.Errorf("summary = %q", got)
+	}
+	if got := buf.GetLine(2); !strings.Contains(got, "CPU%▾") || !strings.Contains(got, "COMMAND") {
+		t.Errorf("header = %q", got)
+	}
+	if got := buf.GetLine(3); !strings.Contains(got, "30 alice") || !strings.Contains(got, "40.0") || !strings.Contains(got, "-bash") {
+		t.Errorf("busiest process not first: %q", got)
+	}
+
+	pt.list.SetIndex(2) // cron
+	pt.SetTree(true)
+	if p := pt.Selected(); p == nil || p.PID != 40 {
+		t.Fatalf("selection didn't follow the process into the tree: %+v", p)
+	}
+	buf = frame()
+	if got := buf.GetLine(5); !strings.Contains(got, "│  └─ -bash") {
+		t.Errorf("tree line = %q\n%s", got, buf.String())
+	}
+
+	pt.askRenice(*pt.Selected(), 25)
+	if got := pt.asking[0].Label; got != "Renice 40 cron from 0 to 19" {
+		t.Errorf("question = %q", got)
+	}
+}
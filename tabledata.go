@@ -0,0 +1,278 @@
+package glyph
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// TableData is tabular data loaded from CSV, TSV or JSON. Columns are sized
+// to fit their contents and aligned by the type of value they hold: numbers
+// right, booleans centred, everything else left.
+//
+//	data, err := ReadCSV(f)
+//	app.SetView(VBox(data.Table()))
+type TableData struct {
+	Columns []TableColumn
+	Rows    [][]string
+
+	kinds []cellKind // inferred per column, parallel to Columns
+	src   *csv.Reader
+}
+
+// cellKind is the inferred type of a column's values.
+type cellKind uint8
+
+const (
+	kindUnknown cellKind = iota // no non-empty values yet
+	kindNumber
+	kindBool
+	kindText
+)
+
+// ReadCSV reads comma-separated records from r. The first record is the header.
+func ReadCSV(r io.Reader) (*TableData, error) { return readDelimited(r, ',') }
+
+// ReadTSV reads tab-separated records from r. The first record is the header.
+func ReadTSV(r io.Reader) (*TableData, error) { return readDelimited(r, '\t') }
+
+func readDelimited(r io.Reader, comma rune) (*TableData, error) {
+	d, err := StreamCSV(r, comma)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		rec, err := d.src.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		d.Append(rec...)
+	}
+	d.src = nil
+	return d, nil
+}
+
+// StreamCSV reads only the header from r, leaving the records to Follow.
+// Use it for sources that keep producing rows, like a pipe or a socket.
+//
+//	data, err := StreamCSV(conn, ',')
+//	go data.Follow(app.Post)
+func StreamCSV(r io.Reader, comma rune) (*TableData, error) {
+	cr := csv.NewReader(r)
+	cr.Comma = comma
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, errors.New("table data: missing header")
+	}
+	if err != nil {
+		return nil, err
+	}
+	d := newTableData(header)
+	d.src = cr
+	return d, nil
+}
+
+// Follow appends records from a StreamCSV source until it ends, returning
+// nil at EOF. Each append is handed to post so it runs on the UI goroutine;
+// pass app.Post, or nil to append directly.
+func (d *TableData) Follow(post func(func())) error {
+	if d.src == nil {
+		return errors.New("table data: not a stream")
+	}
+	for {
+		rec, err := d.src.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if post == nil {
+			d.Append(rec...)
+			continue
+		}
+		post(func() { d.Append(rec...) })
+	}
+}
+
+// ReadJSON reads a JSON array of objects from r. Columns follow the order
+// keys first appear in.
+func ReadJSON(r io.Reader) (*TableData, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	if tok, err := dec.Token(); err != nil {
+		return nil, err
+	} else if tok != json.Delim('[') {
+		return nil, errors.New("table data: expected a JSON array")
+	}
+
+	var keys []string
+	var records []map[string]any
+	for dec.More() {
+		if tok, err := dec.Token(); err != nil {
+			return nil, err
+		} else if tok != json.Delim('{') {
+			return nil, errors.New("table data: expected a JSON object")
+		}
+		rec := make(map[string]any)
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key := tok.(string)
+			var v any
+			if err := dec.Decode(&v); err != nil {
+				return nil, err
+			}
+			if !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+			rec[key] = v
+		}
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	return fromRecords(keys, records), nil
+}
+
+// FromMaps builds table data from records keyed by column name. Columns are
+// sorted by name since maps have no order.
+func FromMaps(records []map[string]any) *TableData {
+	var keys []string
+	for _, rec := range records {
+		for k := range rec {
+			if !slices.Contains(keys, k) {
+				keys = append(keys, k)
+			}
+		}
+	}
+	slices.Sort(keys)
+	return fromRecords(keys, records)
+}
+
+func fromRecords(keys []string, records []map[string]any) *TableData {
+	d := newTableData(keys)
+	row := make([]string, len(keys))
+	for _, rec := range records {
+		for i, k := range keys {
+			row[i] = formatCellValue(rec[k])
+		}
+		d.Append(row...)
+	}
+	return d
+}
+
+func newTableData(header []string) *TableData {
+	d := &TableData{
+		Columns: make([]TableColumn, len(header)),
+		kinds:   make([]cellKind, len(header)),
+	}
+	for i, h := range header {
+		d.Columns[i] = TableColumn{Header: h}
+		d.fit(i, h)
+	}
+	return d
+}
+
+// Append adds a row, widening and re-aligning columns to fit. Cells beyond
+// the header are dropped; missing cells are left blank.
+func (d *TableData) Append(cells ...string) {
+	row := make([]string, len(d.Columns))
+	copy(row, cells)
+	for i, c := range row {
+		d.fit(i, c)
+		if c == "" {
+			continue
+		}
+		k := inferCellKind(c)
+		switch d.kinds[i] {
+		case k, kindText:
+			continue
+		case kindUnknown:
+			d.kinds[i] = k
+		default:
+			d.kinds[i] = kindText
+		}
+		d.Columns[i].Align = d.kinds[i].align()
+	}
+	d.Rows = append(d.Rows, row)
+}
+
+// fit widens column i to hold s plus a one-cell gap before the next column.
+func (d *TableData) fit(i int, s string) {
+	w := utf8.RuneCountInString(s)
+	if i < len(d.Columns)-1 {
+		w++
+	}
+	if w > d.Columns[i].Width {
+		d.Columns[i].Width = w
+	}
+}
+
+// Table returns a Table bound to the data with a bold header row. Rows
+// appended later show up on the next render.
+func (d *TableData) Table() Table {
+	return Table{
+		Columns:     d.Columns,
+		Rows:        &d.Rows,
+		ShowHeader:  true,
+		HeaderStyle: Style{Attr: AttrBold},
+	}
+}
+
+func (k cellKind) align() Align {
+	switch k {
+	case kindNumber:
+		return AlignRight
+	case kindBool:
+		return AlignCenter
+	default:
+		return AlignLeft
+	}
+}
+
+func inferCellKind(s string) cellKind {
+	s = strings.TrimSpace(s)
+	if _, err := strconv.ParseFloat(strings.ReplaceAll(s, ",", ""), 64); err == nil {
+		return kindNumber
+	}
+	if strings.EqualFold(s, "true") || strings.EqualFold(s, "false") {
+		return kindBool
+	}
+	return kindText
+}
+
+// formatCellValue renders a decoded value as cell text.
+func formatCellValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case map[string]any, []any:
+		b, _ := json.Marshal(v)
+		return string(b)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,115 @@
+package glyph
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestReadCSV(t *testing.T) {
+	d, err := ReadCSV(strings.NewReader("name,price,live\nAAPL,189.5,true\nMSFT,\"1,200.25\",false\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Rows) != 2 || d.Rows[1][1] != "1,200.25" {
+		t.Fatalf("rows = %q", d.Rows)
+	}
+	want := []TableColumn{
+		{Header: "name", Width: 5, Align: AlignLeft},
+		{Header: "price", Width: 9, Align: AlignRight},
+		{Header: "live", Width: 5, Align: AlignCenter},
+	}
+	for i, c := range d.Columns {
+		if c != want[i] {
+			t.Errorf("column %d = %+v, want %+v", i, c, want[i])
+		}
+	}
+
+	tmpl := Build(VBox(d.Table()))
+	buf := NewBuffer(19, 3)
+	tmpl.Execute(buf, 19, 3)
+	if got := buf.GetLine(1); got != "AAPL     189.5true" {
+		t.Errorf("line 1 = %q", got)
+	}
+}
+
+func TestReadTSVInference(t *testing.T) {
+	d, err := ReadTSV(strings.NewReader("a\tb\tc\n1,024\tyes\ttrue\n-3\t\tFALSE\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	aligns := []Align{AlignRight, AlignLeft, AlignCenter}
+	for i, c := range d.Columns {
+		if c.Align != aligns[i] {
+			t.Errorf("column %s align = %v, want %v", c.Header, c.Align, aligns[i])
+		}
+	}
+}
+
+func TestReadJSON(t *testing.T) {
+	d, err := ReadJSON(strings.NewReader(`[{"sym":"BTC","px":64000.5},{"sym":"ETH","tags":["l1"],"px":3100}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var headers []string
+	for _, c := range d.Columns {
+		headers = append(headers, c.Header)
+	}
+	if got := strings.Join(headers, ","); got != "sym,px,tags" {
+		t.Errorf("headers = %s", got)
+	}
+	if got := strings.Join(d.Rows[1], "|"); got != `ETH|3100|["l1"]` {
+		t.Errorf("row 1 = %s", got)
+	}
+	if d.Columns[1].Align != AlignRight {
+		t.Error("px should be right aligned")
+	}
+
+	if _, err := ReadJSON(strings.NewReader(`{"a":1}`)); err == nil {
+		t.Error("expected error for non-array")
+	}
+}
+
+func TestFromMaps(t *testing.T) {
+	d := FromMaps([]map[string]any{
+		{"b": 2, "a": "x"},
+		{"a": "y", "c": nil},
+	})
+	if len(d.Columns) != 3 || d.Columns[0].Header != "a" || d.Columns[2].Header != "c" {
+		t.Fatalf("columns = %+v", d.Columns)
+	}
+	if got := strings.Join(d.Rows[1], "|"); got != "y||" {
+		t.Errorf("row 1 = %q", got)
+	}
+}
+
+func TestStreamCSVFollow(t *testing.T) {
+	pr, pw := io.Pipe()
+	go func() {
+		io.WriteString(pw, "host,load\n")
+		io.WriteString(pw, "web1,0.5\nweb2,12.25\n")
+		pw.Close()
+	}()
+
+	d, err := StreamCSV(pr, ',')
+	if err != nil {
+		t.Fatal(err)
+	}
+	var posted []func()
+	if err := d.Follow(func(fn func()) { posted = append(posted, fn) }); err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Rows) != 0 || len(posted) != 2 {
+		t.Fatalf("rows = %d posted = %d, want 0 and 2", len(d.Rows), len(posted))
+	}
+	for _, fn := range posted {
+		fn()
+	}
+	if len(d.Rows) != 2 || d.Columns[1].Width != 5 || d.Columns[1].Align != AlignRight {
+		t.Errorf("after posts rows = %q columns = %+v", d.Rows, d.Columns)
+	}
+
+	if _, err := StreamCSV(strings.NewReader(""), ','); err == nil {
+		t.Error("expected error for missing header")
+	}
+}
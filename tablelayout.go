@@ -0,0 +1,180 @@
+package glyph
+
+import "slices"
+
+// TableLayout is a user's arrangement of an AutoTable's columns: their
+// order, which are hidden, and any widths set by resizing. Its fields are
+// plain data so it can be saved between runs (e.g. as JSON) and handed back
+// to Layout on the next start.
+type TableLayout struct {
+	Columns []LayoutColumn `json:"columns"`
+
+	active   int            // selected column, as an index into the visible columns
+	rendered map[string]int // widths from the last frame, for resizing from natural
+}
+
+// LayoutColumn is one column in a TableLayout.
+type LayoutColumn struct {
+	Field   string `json:"field"`
+	Visible bool   `json:"visible"`
+	Width   int    `json:"width,omitempty"` // 0 = natural width
+}
+
+// sync makes the layout hold exactly fields: unknown entries are dropped and
+// new fields are appended visible, so a layout saved against an older struct
+// still applies.
+func (l *TableLayout) sync(fields []string) {
+	kept := l.Columns[:0]
+	for _, c := range l.Columns {
+		if slices.Index(fields, c.Field) >= 0 && layoutIndex(kept, c.Field) < 0 {
+			kept = append(kept, c)
+		}
+	}
+	for _, f := range fields {
+		if layoutIndex(kept, f) < 0 {
+			kept = append(kept, LayoutColumn{Field: f, Visible: true})
+		}
+	}
+	l.Columns = kept
+	l.clampActive()
+}
+
+func layoutIndex(cols []LayoutColumn, field string) int {
+	for i, c := range cols {
+		if c.Field == field {
+			return i
+		}
+	}
+	return -1
+}
+
+// visible returns the index into fields of each visible column, in order.
+func (l *TableLayout) visible(fields []string) []int {
+	out := make([]int, 0, len(l.Columns))
+	for _, c := range l.Columns {
+		if !c.Visible {
+			continue
+		}
+		if i := slices.Index(fields, c.Field); i >= 0 {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+// visibleAt returns the index into Columns of the n-th visible column.
+func (l *TableLayout) visibleAt(n int) int {
+	for i, c := range l.Columns {
+		if !c.Visible {
+			continue
+		}
+		if n == 0 {
+			return i
+		}
+		n--
+	}
+	return -1
+}
+
+func (l *TableLayout) visibleCount() int {
+	n := 0
+	for _, c := range l.Columns {
+		if c.Visible {
+			n++
+		}
+	}
+	return n
+}
+
+func (l *TableLayout) clampActive() {
+	l.active = max(min(l.active, l.visibleCount()-1), 0)
+}
+
+// Active returns the field name of the selected column, or "" if every
+// column is hidden.
+func (l *TableLayout) Active() string {
+	if i := l.visibleAt(l.active); i >= 0 {
+		return l.Columns[i].Field
+	}
+	return ""
+}
+
+// Select moves the selection delta visible columns right (negative for left).
+func (l *TableLayout) Select(delta int) {
+	l.active += delta
+	l.clampActive()
+}
+
+// Resize widens the selected column by delta cells, or narrows it for a
+// negative delta. A column never shrinks below one cell.
+func (l *TableLayout) Resize(delta int) {
+	i := l.visibleAt(l.active)
+	if i < 0 {
+		return
+	}
+	c := &l.Columns[i]
+	w := c.Width
+	if w == 0 {
+		w = l.rendered[c.Field]
+	}
+	c.Width = max(w+delta, 1)
+}
+
+// ResetWidth returns the selected column to its natural width.
+func (l *TableLayout) ResetWidth() {
+	if i := l.visibleAt(l.active); i >= 0 {
+		l.Columns[i].Width = 0
+	}
+}
+
+// Move shifts the selected column delta visible places right (negative for
+// left), keeping it selected.
+func (l *TableLayout) Move(delta int) {
+	target := max(min(l.active+delta, l.visibleCount()-1), 0)
+	for l.active != target {
+		step := 1
+		if target < l.active {
+			step = -1
+		}
+		a, b := l.visibleAt(l.active), l.visibleAt(l.active+step)
+		l.Columns[a], l.Columns[b] = l.Columns[b], l.Columns[a]
+		l.active += step
+	}
+}
+
+// Hide hides the selected column. Hidden columns come back through Show,
+// ShowAll or a ColumnChooser.
+func (l *TableLayout) Hide() {
+	if i := l.visibleAt(l.active); i >= 0 {
+		l.Columns[i].Visible = false
+		l.clampActive()
+	}
+}
+
+// Show makes the named column visible again.
+func (l *TableLayout) Show(field string) {
+	if i := layoutIndex(l.Columns, field); i >= 0 {
+		l.Columns[i].Visible = true
+	}
+}
+
+// ShowAll makes every column visible.
+func (l *TableLayout) ShowAll() {
+	for i := range l.Columns {
+		l.Columns[i].Visible = true
+	}
+}
+
+// ColumnChooser returns a checklist of the layout's columns for showing and
+// hiding them, meant to sit in an Overlay:
+//
+//	If(&choosing).Then(Overlay.Centered()(
+//	    VBox.Border(BorderRounded).Title("Columns")(
+//	        ColumnChooser(&layout).BindNav("j", "k").BindToggle("<Space>"),
+//	    ),
+//	))
+func ColumnChooser(l *TableLayout) *CheckListC[LayoutColumn] {
+	return CheckList(&l.Columns).
+		Checked(func(c *LayoutColumn) *bool { return &c.Visible }).
+		Render(func(c *LayoutColumn) any { return Text(&c.Field) })
+}
@@ -0,0 +1,108 @@
+package glyph
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+type layoutRow struct {
+	Name string
+	CPU  int
+	Mem  int
+}
+
+func TestTableLayout(t *testing.T) {
+	rows := []layoutRow{{"web", 12, 300}, {"db", 7, 900}}
+	var layout TableLayout
+	table := AutoTable(&rows).Gap(1).Layout(&layout).
+		BindColumnSelect("h", "l").
+		BindColumnMove("H", "L").
+		BindColumnResize("<", ">").
+		BindColumnHide("x")
+	tmpl := Build(VBox(table))
+	frame := func() *Buffer {
+		buf := NewBuffer(14, 3)
+		tmpl.Execute(buf, 14, 3)
+		return buf
+	}
+	press := func(key string) {
+		for _, b := range tmpl.pendingBindings {
+			if b.pattern == key {
+				b.handler.(func())()
+				return
+			}
+		}
+		t.Fatalf("no binding for %q", key)
+	}
+
+	buf := frame()
+	if len(layout.Columns) != 3 || layout.Active() != "Name" {
+		t.Fatalf("layout = %+v active %q", layout.Columns, layout.Active())
+	}
+	if !buf.Get(0, 0).Style.Attr.Has(AttrInverse) {
+		t.Error("selected header should be inverse")
+	}
+
+	// move Mem to the front, then hide CPU
+	press("l")
+	press("l")
+	press("H")
+	press("H")
+	if layout.Active() != "Mem" || layout.Columns[0].Field != "Mem" {
+		t.Fatalf("after move active %q columns %+v", layout.Active(), layout.Columns)
+	}
+	press("l")
+	press("l")
+	press("x")
+	buf = frame()
+	if got := strings.Fields(buf.GetLine(0)); strings.Join(got, " ") != "Mem Name" {
+		t.Errorf("header = %q, want Mem Name", buf.GetLine(0))
+	}
+	if layout.Active() != "Name" {
+		t.Errorf("active after hide = %q", layout.Active())
+	}
+
+	// resizing fixes the width from what was last drawn
+	natural := layout.rendered["Name"]
+	press(">")
+	if layout.Columns[1].Width != natural+1 {
+		t.Errorf("width = %d, want %d", layout.Columns[1].Width, natural+1)
+	}
+	layout.ResetWidth()
+
+	// a saved layout survives a round trip and tolerates new fields
+	saved, _ := json.Marshal(layout)
+	var restored TableLayout
+	if err := json.Unmarshal(saved, &restored); err != nil {
+		t.Fatal(err)
+	}
+	restored.Columns = append(restored.Columns[:1], LayoutColumn{Field: "Gone", Visible: true})
+	restored.sync([]string{"Name", "CPU", "Mem"})
+	var fields []string
+	for _, c := range restored.Columns {
+		fields = append(fields, c.Field)
+	}
+	if got := strings.Join(fields, ","); got != "Mem,Name,CPU" {
+		t.Errorf("restored order = %s", got)
+	}
+	restored.ShowAll()
+	if restored.visibleCount() != 3 {
+		t.Error("ShowAll should show every column")
+	}
+}
+
+func TestColumnChooser(t *testing.T) {
+	layout := TableLayout{Columns: []LayoutColumn{{Field: "A", Visible: true}, {Field: "B"}}}
+	chooser := ColumnChooser(&layout).BindToggle("<Space>").BindNav("j", "k")
+	buf := NewBuffer(10, 2)
+	Build(VBox(chooser)).Execute(buf, 10, 2)
+	if got := buf.GetLine(1); !strings.Contains(got, "☐") || !strings.Contains(got, "B") {
+		t.Errorf("line 1 = %q", got)
+	}
+	chooser.Down(nil)
+	chooser.declaredBindings[0].handler.(func())()
+	if !layout.Columns[1].Visible {
+		t.Error("toggle should show B")
+	}
+}
@@ -0,0 +1,234 @@
+package glyph
+
+import (
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// fileWatcher wakes a Tail when the file it follows, or the directory it's
+// in, changes.
+type fileWatcher interface {
+	watch(f *os.File) // follow a newly opened file
+	wait(timeout time.Duration)
+	wake() // end a wait early
+	close()
+}
+
+// Tail follows a file as it grows, like tail -F, as an io.Reader for Log:
+//
+//	Log(TailFile("/var/log/app.log")).BindVimNav().Grow(1)
+//
+// Reads block until there's more to read, woken by inotify on Linux and
+// kqueue on macOS, or by checking every Poll interval elsewhere or when
+// those can't be used. A file that's rotated, renamed away with a new one
+// made in its place, is read to its end and then the new one from its
+// start; one truncated in place is read again from the start. A file that
+// doesn't exist yet, or is gone for a while, is waited for.
+type Tail struct {
+	path  string
+	lines int
+	poll  time.Duration
+
+	mu      sync.Mutex
+	started bool
+	waiting bool
+	closed  chan struct{}
+	err     error
+
+	watcher fileWatcher
+	file    *os.File
+	info    os.FileInfo
+	offset  int64
+	last    byte // last byte read, to end a rotated file's unfinished line
+}
+
+// TailFile creates a Tail of the file at path. It's opened on the first
+// Read, starting with its last 1000 lines.
+func TailFile(path string) *Tail {
+	return &Tail{path: path, lines: 1000, poll: 250 * time.Millisecond, closed: make(chan struct{})}
+}
+
+// Lines sets how many of the lines already in the file to start with: 0
+// for only lines written from now on, -1 for the whole file.
+func (t *Tail) Lines(n int) *Tail {
+	t.lines = n
+	return t
+}
+
+// Poll sets how often the file is checked when nothing wakes the Tail
+// sooner, 250ms by default.
+func (t *Tail) Poll(d time.Duration) *Tail {
+	t.poll = max(d, time.Millisecond)
+	return t
+}
+
+// Err returns why the file couldn't be opened, while it can't.
+func (t *Tail) Err() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err
+}
+
+// Read reads what's been written to the file, waiting for more if there's
+// nothing new. After Close it returns io.EOF.
+func (t *Tail) Read(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.started {
+		t.started = true
+		t.watcher, _ = newFileWatcher(t.path) // nil: poll instead
+		t.open(t.lines)
+	}
+	rotated := false
+	for {
+		select {
+		case <-t.closed:
+			t.release()
+			return 0, io.EOF
+		default:
+		}
+		if t.file == nil {
+			t.open(-1)
+		}
+		if t.file != nil && len(p) > 0 {
+			n, err := t.file.Read(p)
+			if n > 0 {
+				t.offset += int64(n)
+				t.last = p[n-1]
+				return n, nil
+			}
+			if err != nil && err != io.EOF {
+				return 0, err
+			}
+			info, err := os.Stat(t.path)
+			switch {
+			case err != nil:
+				// gone, wait for it to come back
+			case !os.SameFile(info, t.info) && !rotated:
+				rotated = true // read what was written before the rename
+				continue
+			case !os.SameFile(info, t.info):
+				t.file.Close()
+				t.file = nil
+				t.open(-1)
+				if t.last != 0 && t.last != '\n' {
+					t.last = '\n'
+					p[0] = '\n' // don't run into the new file's first line
+					return 1, nil
+				}
+				continue
+			case info.Size() < t.offset:
+				t.file.Seek(0, io.SeekStart)
+				t.offset = 0
+				continue
+			}
+		}
+		rotated = false
+		t.wait()
+	}
+}
+
+// Close stops the Tail. A Read waiting for more returns io.EOF, so a Log
+// reading it stops.
+func (t *Tail) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	select {
+	case <-t.closed:
+		return nil
+	default:
+	}
+	close(t.closed)
+	if t.waiting {
+		if t.watcher != nil {
+			t.watcher.wake()
+		}
+		return nil // the Read releases everything
+	}
+	t.release()
+	return nil
+}
+
+// open opens the file, starting lines from its end, or at its start for
+// -1. Called with t.mu held.
+func (t *Tail) open(lines int) {
+	f, err := os.Open(t.path)
+	if err != nil {
+		t.err = err
+		return
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		t.err = err
+		return
+	}
+	start := tailOffset(f, info.Size(), lines)
+	if _, err := f.Seek(start, io.SeekStart); err != nil {
+		f.Close()
+		t.err = err
+		return
+	}
+	t.file, t.info, t.offset, t.err = f, info, start, nil
+	if t.watcher != nil {
+		t.watcher.watch(f)
+	}
+}
+
+// wait waits for the file to change, or the poll interval. Called with
+// t.mu held, which it lets go of meanwhile.
+func (t *Tail) wait() {
+	t.waiting = true
+	t.mu.Unlock()
+	if t.watcher != nil {
+		t.watcher.wait(t.poll)
+	} else {
+		select {
+		case <-t.closed:
+		case <-time.After(t.poll):
+		}
+	}
+	t.mu.Lock()
+	t.waiting = false
+}
+
+func (t *Tail) release() {
+	if t.file != nil {
+		t.file.Close()
+		t.file = nil
+	}
+	if t.watcher != nil {
+		t.watcher.close()
+		t.watcher = nil
+	}
+}
+
+// tailOffset returns where the last n lines of f start, 0 for the whole
+// file if n is -1 or there are fewer lines.
+func tailOffset(f *os.File, size int64, n int) int64 {
+	if n < 0 {
+		return 0
+	}
+	if n == 0 {
+		return size
+	}
+	buf := make([]byte, 64*1024)
+	for pos := size; pos > 0; {
+		k := min(int64(len(buf)), pos)
+		pos -= k
+		if _, err := f.ReadAt(buf[:k], pos); err != nil {
+			return 0
+		}
+		for i := k - 1; i >= 0; i-- {
+			// a final newline ends the last line rather than starting one
+			if buf[i] == '\n' && pos+i != size-1 {
+				if n--; n == 0 {
+					return pos + i + 1
+				}
+			}
+		}
+	}
+	return 0
+}
@@ -0,0 +1,71 @@
+package glyph
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// kqueueWatcher watches a tailed file for writes, and the directory it's
+// in for a file made in its place.
+type kqueueWatcher struct {
+	kq   int
+	dir  int
+	pipe [2]int // written to end a wait
+}
+
+func newFileWatcher(path string) (fileWatcher, error) {
+	kq, err := unix.Kqueue()
+	if err != nil {
+		return nil, err
+	}
+	w := &kqueueWatcher{kq: kq, dir: -1}
+	if err := unix.Pipe(w.pipe[:]); err != nil {
+		unix.Close(kq)
+		return nil, err
+	}
+	unix.CloseOnExec(w.pipe[0])
+	unix.CloseOnExec(w.pipe[1])
+	changes := []unix.Kevent_t{{Ident: uint64(w.pipe[0]), Filter: unix.EVFILT_READ, Flags: unix.EV_ADD}}
+	if dir, err := unix.Open(filepath.Dir(path), unix.O_EVTONLY|unix.O_CLOEXEC, 0); err == nil {
+		w.dir = dir
+		changes = append(changes, unix.Kevent_t{
+			Ident: uint64(dir), Filter: unix.EVFILT_VNODE, Flags: unix.EV_ADD | unix.EV_CLEAR,
+			Fflags: unix.NOTE_WRITE,
+		})
+	}
+	if _, err := unix.Kevent(kq, changes, nil, nil); err != nil {
+		w.close()
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *kqueueWatcher) watch(f *os.File) {
+	// dropped by the kernel when the file is closed
+	unix.Kevent(w.kq, []unix.Kevent_t{{
+		Ident: uint64(f.Fd()), Filter: unix.EVFILT_VNODE, Flags: unix.EV_ADD | unix.EV_CLEAR,
+		Fflags: unix.NOTE_WRITE | unix.NOTE_EXTEND | unix.NOTE_ATTRIB | unix.NOTE_DELETE | unix.NOTE_RENAME,
+	}}, nil, nil)
+}
+
+func (w *kqueueWatcher) wait(timeout time.Duration) {
+	var events [8]unix.Kevent_t
+	ts := unix.NsecToTimespec(timeout.Nanoseconds())
+	unix.Kevent(w.kq, nil, events[:], &ts)
+}
+
+func (w *kqueueWatcher) wake() {
+	unix.Write(w.pipe[1], []byte{0})
+}
+
+func (w *kqueueWatcher) close() {
+	unix.Close(w.kq)
+	if w.dir >= 0 {
+		unix.Close(w.dir)
+	}
+	unix.Close(w.pipe[0])
+	unix.Close(w.pipe[1])
+}
@@ -0,0 +1,61 @@
+package glyph
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+// inotifyWatcher watches the directory a tailed file is in, which sees
+// both writes to the file and files renamed or made in its place.
+type inotifyWatcher struct {
+	fd   int
+	pipe [2]int // written to end a wait
+}
+
+func newFileWatcher(path string) (fileWatcher, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+	const events = unix.IN_MODIFY | unix.IN_ATTRIB | unix.IN_CREATE | unix.IN_DELETE | unix.IN_MOVED_FROM | unix.IN_MOVED_TO
+	if _, err := unix.InotifyAddWatch(fd, filepath.Dir(path), events); err != nil {
+		unix.Close(fd)
+		return nil, err
+	}
+	w := &inotifyWatcher{fd: fd}
+	if err := unix.Pipe2(w.pipe[:], unix.O_CLOEXEC|unix.O_NONBLOCK); err != nil {
+		unix.Close(fd)
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *inotifyWatcher) watch(*os.File) {}
+
+func (w *inotifyWatcher) wait(timeout time.Duration) {
+	fds := []unix.PollFd{
+		{Fd: int32(w.fd), Events: unix.POLLIN},
+		{Fd: int32(w.pipe[0]), Events: unix.POLLIN},
+	}
+	unix.Poll(fds, int(timeout/time.Millisecond))
+	// what changed doesn't matter, the file is checked either way
+	var buf [4096]byte
+	for {
+		if n, _ := unix.Read(w.fd, buf[:]); n <= 0 {
+			break
+		}
+	}
+}
+
+func (w *inotifyWatcher) wake() {
+	unix.Write(w.pipe[1], []byte{0})
+}
+
+func (w *inotifyWatcher) close() {
+	unix.Close(w.fd)
+	unix.Close(w.pipe[0])
+	unix.Close(w.pipe[1])
+}
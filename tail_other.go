@@ -0,0 +1,10 @@
+//go:build !linux && !darwin
+
+package glyph
+
+import "errors"
+
+// newFileWatcher has nothing to watch with here, so Tail polls.
+func newFileWatcher(path string) (fileWatcher, error) {
+	return nil, errors.ErrUnsupported
+}
@@ -0,0 +1,118 @@
+package glyph
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTailOffset(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "tail")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	f.WriteString("one\ntwo\nthree\n")
+	for n, want := range map[int]int64{-1: 0, 0: 14, 1: 8, 2: 4, 3: 0, 10: 0} {
+		if got := tailOffset(f, 14, n); got != want {
+			t.Errorf("last %d lines start at %d, want %d", n, got, want)
+		}
+	}
+	if got := tailOffset(f, 13, 1); got != 8 {
+		t.Errorf("without a final newline, last line starts at %d, want 8", got)
+	}
+}
+
+func TestTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	os.WriteFile(path, []byte("old 1\nold 2\nold 3\n"), 0o644)
+
+	tail := TailFile(path).Lines(2).Poll(20 * time.Millisecond)
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(tail)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+	expect := func(want ...string) {
+		t.Helper()
+		for _, w := range want {
+			select {
+			case got := <-lines:
+				if got != w {
+					t.Fatalf("line = %q, want %q", got, w)
+				}
+			case <-time.After(2 * time.Second):
+				t.Fatalf("timed out waiting for %q", w)
+			}
+		}
+	}
+	appendLine := func(p, s string) {
+		f, err := os.OpenFile(p, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.WriteString(s)
+		f.Close()
+	}
+
+	expect("old 2", "old 3")
+	appendLine(path, "new 1\n")
+	expect("new 1")
+
+	// rotated, mid-line: renamed away and replaced
+	appendLine(path, "last of old")
+	os.Rename(path, path+".1")
+	appendLine(path, "rotated 1\n")
+	expect("last of old", "rotated 1")
+
+	// truncated in place
+	os.Truncate(path, 0)
+	time.Sleep(50 * time.Millisecond)
+	appendLine(path, "after truncate\n")
+	expect("after truncate")
+
+	tail.Close()
+	select {
+	case _, ok := <-lines:
+		if ok {
+			t.Error("line after Close")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Read didn't end on Close")
+	}
+	if n, err := tail.Read(make([]byte, 8)); n != 0 || err != io.EOF {
+		t.Errorf("Read after Close = %d, %v", n, err)
+	}
+}
+
+func TestTailWaitsForFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "later.log")
+	tail := TailFile(path).Poll(10 * time.Millisecond)
+	defer tail.Close()
+	got := make(chan string, 1)
+	go func() {
+		scanner := bufio.NewScanner(tail)
+		if scanner.Scan() {
+			got <- scanner.Text()
+		}
+	}()
+	time.Sleep(30 * time.Millisecond)
+	if tail.Err() == nil {
+		t.Error("no error while the file is missing")
+	}
+	os.WriteFile(path, []byte("hello\n"), 0o644)
+	select {
+	case line := <-got:
+		if line != "hello" {
+			t.Errorf("line = %q", line)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("file never read")
+	}
+}
@@ -4,11 +4,14 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 	"unicode"
 	"unicode/utf8"
 	"unsafe"
+
+	"github.com/kungfusheep/riffkey"
 )
 
 // Component is the extension interface for custom components.
@@ -51,6 +54,26 @@ type textInputBindable interface {
 	textBinding() *textInputBinding
 }
 
+// keyHandlerBindable is implemented by components that consume raw key
+// presses, such as TextAreaC.
+type keyHandlerBindable interface {
+	keyHandler() func(riffkey.Key) bool
+}
+
+// modalBindable is implemented by components that take over the keyboard
+// while open, such as menus. They're given the app's Push and Pop during
+// wiring.
+type modalBindable interface {
+	setModal(push func(*riffkey.Router), pop func())
+}
+
+// routerScoped is implemented by containers that give keys to their focused
+// child before the view's own router, such as SplitPane. During wiring they
+// get the view's router to fall back on and the app's Push.
+type routerScoped interface {
+	attachRouter(base *riffkey.Router, push func(*riffkey.Router))
+}
+
 // templateTree is implemented by compound components that compose existing
 // building blocks into a template subtree.
 type templateTree interface {
@@ -111,17 +134,46 @@ type Template struct {
 	flexScratchGrow []float32 // flex grow values (shared by VBox + HBox phases)
 	flexScratchImpl []int16   // implicit flex children (HBox only)
 	treeScratchPfx  []bool    // tree node line prefix
+	spanScratch     []Span    // spans with their bound strings read (Textf)
 
 	// Declarative bindings collected during compile, wired during setup
 	pendingBindings     []binding
 	pendingTIB          *textInputBinding
-	pendingLogs         []*LogC       // Logs that need app.RequestRender wiring
-	pendingFocusManager *FocusManager // Focus manager for multi-input routing
+	pendingKeyHandler   func(riffkey.Key) bool // raw key consumer (TextArea)
+	pendingLogs         []*LogC                // Logs that need app.RequestRender wiring
+	pendingUpdates      []*func()              // async sources' update hooks needing app.RequestRender
+	pendingFocusManager *FocusManager          // Focus manager for multi-input routing
+	pendingModals       []modalBindable        // components that push their own router while open
+	pendingScopes       []routerScoped         // containers routing keys to their focused child
+
+	// Channel and atomic sources copied into template-owned values each frame
+	live []liveSource
+
+	// Lifecycle hooks settled after each frame
+	mounts []mounter
+
+	// Time-based nodes and the clock they were last given
+	clockUsers []clockUser
+	clock      Clock
+
+	// Nodes that draw by quality, and the quality they were last given;
+	// shared with sub-templates like app
+	qualityUsers []qualityUser
+	quality      Quality
+
+	// Where each op was drawn in the last frame, while a dev tool asks;
+	// shared with sub-templates
+	trace *renderTrace
+
+	// Where Protect components were drawn this frame; shared with
+	// sub-templates
+	protects *[]Rect
 }
 
 // pendingOverlay stores info needed to render an overlay after main content
 type pendingOverlay struct {
-	op *Op // pointer to the overlay op
+	op   *Op                                       // pointer to the overlay op
+	draw func(buf *Buffer, screenW, screenH int16) // or a component's own floating drawing
 }
 
 // SetApp links this template to an App for jump mode support.
@@ -129,18 +181,50 @@ func (t *Template) SetApp(a *App) {
 	t.app = a
 }
 
+// updateHookable is implemented by components fed from background goroutines.
+// The hook is set to app.RequestRender during wiring if the user left it nil.
+type updateHookable interface {
+	updateHook() *func()
+}
+
+func (t *Template) collectUpdateHook(node any) {
+	if u, ok := node.(updateHookable); ok {
+		t.pendingUpdates = append(t.pendingUpdates, u.updateHook())
+	}
+}
+
 func (t *Template) collectBindings(node any) {
 	if b, ok := node.(bindable); ok {
 		t.pendingBindings = append(t.pendingBindings, b.bindings()...)
 	}
 }
 
+func (t *Template) collectModal(node any) {
+	if m, ok := node.(modalBindable); ok {
+		t.pendingModals = append(t.pendingModals, m)
+	}
+}
+
+func (t *Template) collectRouterScope(node any) {
+	if s, ok := node.(routerScoped); ok {
+		t.pendingScopes = append(t.pendingScopes, s)
+	}
+}
+
 func (t *Template) collectTextInputBinding(node any) {
 	if tib, ok := node.(textInputBindable); ok {
 		t.pendingTIB = tib.textBinding()
 	}
 }
 
+func (t *Template) collectKeyHandler(node any) {
+	if kh, ok := node.(keyHandlerBindable); ok {
+		if h := kh.keyHandler(); h != nil {
+			t.pendingKeyHandler = h
+		}
+	}
+}
+
 func (t *Template) collectFocusManager(node any) {
 	// check if InputC or FilterLogC has a manager
 	switch v := node.(type) {
@@ -189,20 +273,27 @@ type Op struct {
 	FitContent   bool    // size to content instead of filling available space
 
 	// Container
-	IsRow        bool        // true=HBox, false=VBox
-	Border       BorderStyle // border style
-	BorderFG     *Color      // border foreground color
-	BorderBG     *Color      // border background color
-	Title        string      // border title
-	ChildStart   int16       // first child op index
-	ChildEnd     int16       // last child op index (exclusive)
-	CascadeStyle *Style      // style inherited by children (pointer for dynamic themes)
-	Fill         Color       // container fill color (fills entire area)
-	Margin       [4]int16    // outer margin: top, right, bottom, left
+	IsRow        bool         // true=HBox, false=VBox
+	Border       BorderStyle  // border style
+	BorderFG     *Color       // border foreground color
+	BorderBG     *Color       // border background color
+	Title        string       // border title
+	ChildStart   int16        // first child op index
+	ChildEnd     int16        // last child op index (exclusive)
+	CascadeStyle *Style       // style inherited by children (pointer for dynamic themes)
+	Fill         Color        // container fill color (fills entire area)
+	Pattern      *Pattern     // container background texture, over Fill
+	Margin       [4]int16     // outer margin: top, right, bottom, left
+	Collapse     *collapsible // set by Collapsible
 
 	// Control flow
 	CondPtr  *bool         // for If (simple bool pointer)
 	CondNode conditionNode // for If (builder-style conditions)
+	trans    *transition   // for If with Transition
+	life     *lifecycle    // mount/unmount hooks (Lifecycle)
+	protect  bool          // kept clear of overlays (Protect)
+	name     string        // the component compiled into this op, for dev tools
+	keys     []string      // and the keys it binds
 	ThenTmpl *Template     // for If
 	ElseTmpl *Template     // for If/Else
 	IterTmpl *Template     // for ForEach
@@ -210,7 +301,8 @@ type Op struct {
 	ElemSize uintptr
 
 	// ForEach runtime - reused across frames
-	iterGeoms []Geom // per-item geometry
+	iterGeoms []Geom      // per-item geometry
+	keyed     *keyedItems // per-key templates (ForEach with Key)
 
 	// Switch
 	SwitchNode  switchNodeInterface
@@ -224,9 +316,11 @@ type Op struct {
 	CustomLayout LayoutFunc
 
 	// Layer
-	LayerPtr    *Layer // pointer to Layer
-	LayerWidth  int16  // viewport width (0 = fill available)
-	LayerHeight int16  // viewport height (0 = fill available)
+	LayerPtr        *Layer     // pointer to Layer
+	LayerWidth      int16      // viewport width (0 = fill available)
+	LayerHeight     int16      // viewport height (0 = fill available)
+	LayerHScrollbar bool       // reserve the bottom row for a horizontal scrollbar
+	LayerPins       []layerPin // live views pinned to spots in the content
 
 	// RichText
 	StaticSpans []Span    // for static spans
@@ -270,6 +364,12 @@ type Op struct {
 	AutoTableColCfgs  []*ColumnConfig     // per-column config (parallel to Fields, nil = no config)
 	AutoTableSort     *autoTableSortState // nil unless sorting enabled
 	AutoTableScroll   *autoTableScroll    // nil unless scrolling enabled
+	AutoTableHScroll  *autoTableHScroll   // nil unless columns scroll sideways
+	AutoTableNames    []string            // field names (parallel to Fields)
+	AutoTableLayout   *TableLayout        // nil unless Layout called
+	AutoTableGroups   *autoTableGroups    // nil unless grouped
+	AutoTableGroupBy  int                 // struct field index to group by (-1 = none)
+	AutoTableAggs     []Aggregator        // footer aggregates (parallel to Fields, nil = no footer)
 
 	// Sparkline
 	SparkValues    []float64  // static values
@@ -304,6 +404,7 @@ type Op struct {
 	TabsGap           int       // gap between tabs
 	TabsActiveStyle   Style     // style for active tab
 	TabsInactiveStyle Style     // style for inactive tabs
+	TabsAccel         []int     // per tab, the rune of its label to underline, or -1
 
 	// TreeView
 	TreeRoot          *TreeNode // root node
@@ -318,11 +419,14 @@ type Op struct {
 	// Jump (jump target wrapper) - just marks a position, child is inline
 	JumpOnSelect func() // callback when target is selected
 	JumpStyle    Style  // label style override (zero = use app default)
+	JumpAccel    rune   // letter to underline as the target's key hint
+	JumpDisabled *bool  // while true, not a target
 
 	// TextInput
 	TextInputFieldPtr       *InputState // Field-based API (bundles Value+Cursor)
 	TextInputFocusGroupPtr  *FocusGroup // shared focus tracker
 	TextInputFocusIndex     int         // this field's index in focus group
+	TextInputDisabledPtr    *bool       // grayed, without a cursor, while true
 	TextInputValuePtr       *string     // bound text value (legacy)
 	TextInputCursorPtr      *int        // bound cursor position (legacy)
 	TextInputFocusedPtr     *bool       // show cursor only when true (legacy)
@@ -422,6 +526,25 @@ func Build(ui any) *Template {
 	return t
 }
 
+// newSubTemplate compiles node into a template of its own, for branches,
+// iterations and containers that lay out and render their content
+// separately. elemBase and elemSize are as for compile.
+func newSubTemplate(node any, elemBase unsafe.Pointer, elemSize uintptr) *Template {
+	sub := &Template{
+		ops:     make([]Op, 0, 16),
+		byDepth: make([][]int16, 8),
+	}
+	for i := range sub.byDepth {
+		sub.byDepth[i] = make([]int16, 0, 4)
+	}
+	sub.compile(node, -1, 0, elemBase, elemSize)
+	if sub.maxDepth >= 0 {
+		sub.byDepth = sub.byDepth[:sub.maxDepth+1]
+	}
+	sub.geom = make([]Geom, len(sub.ops))
+	return sub
+}
+
 func (t *Template) addOp(op Op, depth int) int16 {
 	idx := int16(len(t.ops))
 	op.Depth = int8(depth)
@@ -444,6 +567,16 @@ func (t *Template) addOp(op Op, depth int) int16 {
 }
 
 func (t *Template) compile(node any, parent int16, depth int, elemBase unsafe.Pointer, elemSize uintptr) int16 {
+	idx := t.compileNode(node, parent, depth, elemBase, elemSize)
+	if idx >= 0 && int(idx) < len(t.ops) {
+		// the outermost node compiled to an op names it
+		t.ops[idx].name = componentName(node)
+		t.ops[idx].keys = boundKeys(node)
+	}
+	return idx
+}
+
+func (t *Template) compileNode(node any, parent int16, depth int, elemBase unsafe.Pointer, elemSize uintptr) int16 {
 	if node == nil {
 		return -1
 	}
@@ -461,6 +594,8 @@ func (t *Template) compile(node any, parent int16, depth int, elemBase unsafe.Po
 		return t.compileIf(v, parent, depth, elemBase, elemSize)
 	case ForEachNode:
 		return t.compileForEach(v, parent, depth)
+	case *LevelMeterC:
+		return t.compileLevelMeterC(v, parent, depth)
 	case Renderer:
 		return t.compileRenderer(v, parent, depth)
 	case Box:
@@ -471,6 +606,8 @@ func (t *Template) compile(node any, parent int16, depth int, elemBase unsafe.Po
 		return t.compileLayer(v, parent, depth)
 	case RichTextNode:
 		return t.compileRichText(v, parent, depth, elemBase, elemSize)
+	case *RichText:
+		return t.compileRichText(v.Node(), parent, depth, elemBase, elemSize)
 	case SelectionList:
 		return t.compileSelectionList(&v, parent, depth, elemBase, elemSize)
 	case *SelectionList:
@@ -503,11 +640,17 @@ func (t *Template) compile(node any, parent int16, depth int, elemBase unsafe.Po
 		return t.compileOverlay(v, parent, depth)
 	case Component:
 		return t.compile(v.Build(), parent, depth, elemBase, elemSize)
+	case LifecycleC:
+		return t.compileLifecycleC(v, parent, depth, elemBase, elemSize)
+	case ProtectC:
+		return t.compileProtectC(v, parent, depth, elemBase, elemSize)
 
 	// New functional API types
 	case VBoxC:
+		t.collectBindings(v)
 		return t.compileVBoxC(v, parent, depth, elemBase, elemSize)
 	case HBoxC:
+		t.collectBindings(v)
 		return t.compileHBoxC(v, parent, depth, elemBase, elemSize)
 	case TextC:
 		return t.compileTextC(v, parent, depth, elemBase, elemSize)
@@ -526,12 +669,15 @@ func (t *Template) compile(node any, parent int16, depth int, elemBase unsafe.Po
 	case SparklineC:
 		return t.compileSparklineC(v, parent, depth)
 	case JumpC:
+		t.collectBindings(v)
 		return t.compileJumpC(v, parent, depth, elemBase, elemSize)
 	case LayerViewC:
+		t.collectBindings(v)
 		return t.compileLayerViewC(v, parent, depth)
 	case OverlayC:
 		return t.compileOverlayC(v, parent, depth)
 	case TabsC:
+		t.collectBindings(v)
 		return t.compileTabsC(v, parent, depth)
 	case ScrollbarC:
 		return t.compileScrollbarC(v, parent, depth)
@@ -551,12 +697,105 @@ func (t *Template) compile(node any, parent int16, depth int, elemBase unsafe.Po
 	case *LogC:
 		t.collectBindings(v)
 		return t.compileLogC(v, parent, depth)
+	case *StructuredViewC:
+		return t.compileStructuredViewC(v, parent, depth)
 	case *TextViewC:
 		t.collectBindings(v)
 		return t.compileTextViewC(v, parent, depth)
+	case *MessageListC:
+		t.collectBindings(v)
+		return t.compileMessageListC(v, parent, depth)
+	case *StreamTextC:
+		t.collectBindings(v)
+		t.collectUpdateHook(v)
+		return t.compileStreamTextC(v, parent, depth)
 	case *FilterLogC:
 		t.collectFocusManager(v)
 		return t.compileFilterLogC(v, parent, depth)
+	case *TextAreaC:
+		t.collectBindings(v)
+		t.collectKeyHandler(v)
+		return t.compileTextAreaC(v, parent, depth)
+	case *ReplacePreviewC:
+		return t.compileReplacePreviewC(v, parent, depth)
+	case *QuickfixC:
+		t.collectBindings(v)
+		t.collectUpdateHook(v)
+		return t.compileQuickfixC(v, parent, depth)
+	case *ErrorBoundaryC:
+		return t.compileErrorBoundaryC(v, parent, depth)
+	case *BadgeC:
+		return t.compileBadgeC(v, parent, depth)
+	case *ValidatedC:
+		return t.compileValidatedC(v, parent, depth, elemBase, elemSize)
+	case *MenuBarC:
+		t.collectBindings(v)
+		t.collectModal(v)
+		return t.compileMenuBarC(v, parent, depth)
+	case *SplitPaneC:
+		t.collectRouterScope(v)
+		return t.compileSplitPaneC(v, parent, depth)
+	case *WorkspacesC:
+		t.collectRouterScope(v)
+		return t.compileWorkspacesC(v, parent, depth)
+	case *WorkspaceBarC:
+		return t.compileWorkspaceBarC(v, parent, depth)
+	case *CheatsheetC:
+		return t.compileCheatsheetC(v, parent, depth)
+	case *ContextMenuC:
+		t.collectBindings(v)
+		t.collectModal(v)
+		return t.compileContextMenuC(v, parent, depth)
+	case *CommandPaletteC:
+		t.collectBindings(v)
+		t.collectModal(v)
+		return t.compileCommandPaletteC(v, parent, depth)
+	case *CommandLineC:
+		t.collectBindings(v)
+		t.collectModal(v)
+		return t.compileCommandLineC(v, parent, depth)
+	case *CharPickerC:
+		t.collectBindings(v)
+		t.collectModal(v)
+		return t.compileCharPickerC(v, parent, depth)
+	case *FlashC:
+		return t.compileFlashC(v, parent, depth)
+	case *TimeAgoC:
+		return t.compileTimeAgoC(v, parent, depth)
+	case *WallClockC:
+		return t.compileWallClockC(v, parent, depth)
+	case *SkeletonC:
+		return t.compileSkeletonC(v, parent, depth)
+	case *AsyncBoundaryC:
+		return t.compileAsyncBoundaryC(v, parent, depth, elemBase, elemSize)
+	case *PaginatorC:
+		return t.compilePaginatorC(v, parent, depth)
+	case *MillerColumnsC:
+		return t.compileMillerColumnsC(v, parent, depth)
+	case *PreviewC:
+		return t.compilePreviewC(v, parent, depth)
+	case *HexViewC:
+		return t.compileHexViewC(v, parent, depth)
+	case *RequestListC:
+		return t.compileRequestListC(v, parent, depth)
+	case *RequestDetailC:
+		return t.compileRequestDetailC(v, parent, depth)
+	case *FlameGraphC:
+		return t.compileFlameGraphC(v, parent, depth)
+	case *WorldMapC:
+		return t.compileWorldMapC(v, parent, depth)
+	case *QRCodeC:
+		return t.compileQRCodeC(v, parent, depth)
+	case *FigletC:
+		return t.compileFigletC(v, parent, depth)
+	case *CountdownC:
+		return t.compileCountdownC(v, parent, depth)
+	case *AlertListC:
+		return t.compileAlertListC(v, parent, depth)
+	case *WatchC:
+		return t.compileWatchC(v, parent, depth)
+	case *StopwatchC:
+		return t.compileStopwatchC(v, parent, depth)
 	case Custom:
 		return t.compileCustom(v, parent, depth)
 	}
@@ -570,6 +809,8 @@ func (t *Template) compile(node any, parent int16, depth int, elemBase unsafe.Po
 	if tc, ok := node.(templateTree); ok {
 		t.collectBindings(node)
 		t.collectTextInputBinding(node)
+		t.collectUpdateHook(node)
+		t.collectModal(node)
 		return t.compile(tc.toTemplate(), parent, depth, elemBase, elemSize)
 	}
 
@@ -735,10 +976,11 @@ func (t *Template) compileRichText(v RichTextNode, parent int16, depth int, elem
 // When elemBase is nil, offs[i] stores the raw uintptr of the *string.
 // When elemBase is non-nil, offs[i] stores the offset from elemBase.
 // ^uintptr(0) sentinel means that span's text is static.
-func resolveSpanStrs(spans []Span, offs []uintptr, elemBase unsafe.Pointer) []Span {
+// The copy is t's scratch slice, valid until the next call.
+func (t *Template) resolveSpanStrs(spans []Span, offs []uintptr, elemBase unsafe.Pointer) []Span {
 	noOffset := ^uintptr(0)
-	resolved := make([]Span, len(spans))
-	copy(resolved, spans)
+	resolved := append(t.spanScratch[:0], spans...)
+	t.spanScratch = resolved
 	for i, off := range offs {
 		if off == noOffset {
 			continue
@@ -795,18 +1037,7 @@ func (t *Template) compileSelectionList(v *SelectionList, parent int16, depth in
 		templateResult := renderRV.Call([]reflect.Value{dummyElem})[0].Interface()
 
 		// Compile iteration template
-		iterTmpl = &Template{
-			ops:     make([]Op, 0, 16),
-			byDepth: make([][]int16, 8),
-		}
-		for i := range iterTmpl.byDepth {
-			iterTmpl.byDepth[i] = make([]int16, 0, 4)
-		}
-		iterTmpl.compile(templateResult, -1, 0, dummyBase, sliceElemSize)
-		if iterTmpl.maxDepth >= 0 {
-			iterTmpl.byDepth = iterTmpl.byDepth[:iterTmpl.maxDepth+1]
-		}
-		iterTmpl.geom = make([]Geom, len(iterTmpl.ops))
+		iterTmpl = newSubTemplate(templateResult, dummyBase, sliceElemSize)
 	}
 
 	op := Op{
@@ -1086,6 +1317,7 @@ func (t *Template) compileTextInput(v TextInput, parent int16, depth int) int16
 		TextInputStyle:          v.Style,
 		TextInputPlaceholderSty: v.PlaceholderStyle,
 		TextInputCursorStyle:    v.CursorStyle,
+		TextInputDisabledPtr:    v.Disabled,
 	}
 
 	// Set defaults for styles
@@ -1150,6 +1382,12 @@ func (t *Template) compileText(v TextNode, parent int16, depth int, elemBase uns
 			op.Kind = OpTextPtr
 			op.StrPtr = val
 		}
+	default:
+		if ptr, src, ok := liveValue[string](val); ok {
+			t.addLive(src)
+			op.Kind = OpTextPtr
+			op.StrPtr = ptr
+		}
 	}
 
 	return t.addOp(op, depth)
@@ -1178,6 +1416,12 @@ func (t *Template) compileProgress(v ProgressNode, parent int16, depth int, elem
 			op.Kind = OpProgressPtr
 			op.IntPtr = val
 		}
+	default:
+		if ptr, src, ok := liveInt(val); ok {
+			t.addLive(src)
+			op.Kind = OpProgressPtr
+			op.IntPtr = ptr
+		}
 	}
 
 	return t.addOp(op, depth)
@@ -1246,21 +1490,11 @@ func (t *Template) compileIf(v IfNode, parent int16, depth int, elemBase unsafe.
 
 	// Compile then branch as sub-template
 	if v.Then != nil {
-		thenTmpl := &Template{
-			ops:     make([]Op, 0, 16),
-			byDepth: make([][]int16, 8),
-		}
-		for i := range thenTmpl.byDepth {
-			thenTmpl.byDepth[i] = make([]int16, 0, 4)
-		}
-		thenTmpl.compile(v.Then, -1, 0, elemBase, elemSize)
-		if thenTmpl.maxDepth >= 0 {
-			thenTmpl.byDepth = thenTmpl.byDepth[:thenTmpl.maxDepth+1]
-		}
-		thenTmpl.geom = make([]Geom, len(thenTmpl.ops))
+		thenTmpl := newSubTemplate(v.Then, elemBase, elemSize)
 		op.ThenTmpl = thenTmpl
 		// bubble up declarative bindings from sub-template
 		t.pendingBindings = append(t.pendingBindings, thenTmpl.pendingBindings...)
+		t.adoptLive(thenTmpl)
 	}
 
 	return t.addOp(op, depth)
@@ -1281,42 +1515,28 @@ func (t *Template) compileCondition(cond conditionNode, parent int16, depth int,
 		Kind:     OpIf,
 		Parent:   parent,
 		CondNode: cond,
+		trans:    cond.getTransition(),
+	}
+	if op.trans != nil {
+		t.collectUpdateHook(op.trans)
+		t.collectClockUser(op.trans)
+		t.collectQualityUser(op.trans)
 	}
 
 	// Compile then branch as sub-template
 	if cond.getThen() != nil {
-		thenTmpl := &Template{
-			ops:     make([]Op, 0, 16),
-			byDepth: make([][]int16, 8),
-		}
-		for i := range thenTmpl.byDepth {
-			thenTmpl.byDepth[i] = make([]int16, 0, 4)
-		}
-		thenTmpl.compile(cond.getThen(), -1, 0, elemBase, elemSize)
-		if thenTmpl.maxDepth >= 0 {
-			thenTmpl.byDepth = thenTmpl.byDepth[:thenTmpl.maxDepth+1]
-		}
-		thenTmpl.geom = make([]Geom, len(thenTmpl.ops))
+		thenTmpl := newSubTemplate(cond.getThen(), elemBase, elemSize)
 		op.ThenTmpl = thenTmpl
 		t.pendingBindings = append(t.pendingBindings, thenTmpl.pendingBindings...)
+		t.adoptLive(thenTmpl)
 	}
 
 	// Compile else branch if present
 	if cond.getElse() != nil {
-		elseTmpl := &Template{
-			ops:     make([]Op, 0, 16),
-			byDepth: make([][]int16, 8),
-		}
-		for i := range elseTmpl.byDepth {
-			elseTmpl.byDepth[i] = make([]int16, 0, 4)
-		}
-		elseTmpl.compile(cond.getElse(), -1, 0, elemBase, elemSize)
-		if elseTmpl.maxDepth >= 0 {
-			elseTmpl.byDepth = elseTmpl.byDepth[:elseTmpl.maxDepth+1]
-		}
-		elseTmpl.geom = make([]Geom, len(elseTmpl.ops))
+		elseTmpl := newSubTemplate(cond.getElse(), elemBase, elemSize)
 		op.ElseTmpl = elseTmpl
 		t.pendingBindings = append(t.pendingBindings, elseTmpl.pendingBindings...)
+		t.adoptLive(elseTmpl)
 	}
 
 	return t.addOp(op, depth)
@@ -1334,40 +1554,21 @@ func (t *Template) compileSwitch(sw switchNodeInterface, parent int16, depth int
 	op.SwitchCases = make([]*Template, len(caseNodes))
 	for i, caseNode := range caseNodes {
 		if caseNode != nil {
-			caseTmpl := &Template{
-				ops:     make([]Op, 0, 16),
-				byDepth: make([][]int16, 8),
-			}
-			for j := range caseTmpl.byDepth {
-				caseTmpl.byDepth[j] = make([]int16, 0, 4)
-			}
-			caseTmpl.compile(caseNode, -1, 0, elemBase, elemSize)
-			if caseTmpl.maxDepth >= 0 {
-				caseTmpl.byDepth = caseTmpl.byDepth[:caseTmpl.maxDepth+1]
-			}
-			caseTmpl.geom = make([]Geom, len(caseTmpl.ops))
+			caseTmpl := newSubTemplate(caseNode, elemBase, elemSize)
 			op.SwitchCases[i] = caseTmpl
 			t.pendingBindings = append(t.pendingBindings, caseTmpl.pendingBindings...)
+			t.adoptLive(caseTmpl)
 		}
 	}
 
 	// Compile default branch
 	if defNode := sw.getDefaultNode(); defNode != nil {
-		defTmpl := &Template{
-			ops:     make([]Op, 0, 16),
-			byDepth: make([][]int16, 8),
-		}
-		for i := range defTmpl.byDepth {
-			defTmpl.byDepth[i] = make([]int16, 0, 4)
-		}
-		defTmpl.compile(defNode, -1, 0, elemBase, elemSize)
-		if defTmpl.maxDepth >= 0 {
-			defTmpl.byDepth = defTmpl.byDepth[:defTmpl.maxDepth+1]
-		}
-		defTmpl.geom = make([]Geom, len(defTmpl.ops))
+		defTmpl := newSubTemplate(defNode, elemBase, elemSize)
 		op.SwitchDef = defTmpl
 		t.pendingBindings = append(t.pendingBindings, defTmpl.pendingBindings...)
+		t.adoptLive(defTmpl)
 	}
+	t.collectQualityUser(sw)
 
 	return t.addOp(op, depth)
 }
@@ -1404,18 +1605,8 @@ func (t *Template) compileForEach(v ForEachNode, parent int16, depth int) int16
 	templateResult := renderRV.Call([]reflect.Value{dummyElem})[0].Interface()
 
 	// Compile iteration template
-	iterTmpl := &Template{
-		ops:     make([]Op, 0, 16),
-		byDepth: make([][]int16, 8),
-	}
-	for i := range iterTmpl.byDepth {
-		iterTmpl.byDepth[i] = make([]int16, 0, 4)
-	}
-	iterTmpl.compile(templateResult, -1, 0, dummyBase, elemSize)
-	if iterTmpl.maxDepth >= 0 {
-		iterTmpl.byDepth = iterTmpl.byDepth[:iterTmpl.maxDepth+1]
-	}
-	iterTmpl.geom = make([]Geom, len(iterTmpl.ops))
+	iterTmpl := newSubTemplate(templateResult, dummyBase, elemSize)
+	t.adoptLive(iterTmpl)
 
 	op := Op{
 		Kind:     OpForEach,
@@ -1433,8 +1624,12 @@ func (t *Template) compileForEach(v ForEachNode, parent int16, depth int) int16
 // ============================================================================
 
 func (t *Template) compileVBoxC(v VBoxC, parent int16, depth int, elemBase unsafe.Pointer, elemSize uintptr) int16 {
-	return t.compileContainer(
-		v.children,
+	children := v.children
+	if v.collapse != nil {
+		children = []any{collapseBody(v.collapse, VBox.Grow(v.flexGrow)(v.children...))}
+	}
+	idx := t.compileContainer(
+		children,
 		v.gap,
 		false, // isRow
 		flex{percentWidth: v.percentWidth, width: v.width, height: v.height, flexGrow: v.flexGrow, fitContent: v.fitContent},
@@ -1450,11 +1645,20 @@ func (t *Template) compileVBoxC(v VBoxC, parent int16, depth int, elemBase unsaf
 		elemBase,
 		elemSize,
 	)
+	if v.collapse != nil {
+		t.markCollapsible(idx, v.collapse)
+	}
+	t.ops[idx].Pattern = v.pattern
+	return idx
 }
 
 func (t *Template) compileHBoxC(v HBoxC, parent int16, depth int, elemBase unsafe.Pointer, elemSize uintptr) int16 {
-	return t.compileContainer(
-		v.children,
+	children := v.children
+	if v.collapse != nil {
+		children = []any{collapseBody(v.collapse, HBox.Grow(v.flexGrow)(v.children...))}
+	}
+	idx := t.compileContainer(
+		children,
 		v.gap,
 		true, // isRow
 		flex{percentWidth: v.percentWidth, width: v.width, height: v.height, flexGrow: v.flexGrow, fitContent: v.fitContent},
@@ -1470,6 +1674,11 @@ func (t *Template) compileHBoxC(v HBoxC, parent int16, depth int, elemBase unsaf
 		elemBase,
 		elemSize,
 	)
+	if v.collapse != nil {
+		t.markCollapsible(idx, v.collapse)
+	}
+	t.ops[idx].Pattern = v.pattern
+	return idx
 }
 
 func (t *Template) compileTextC(v TextC, parent int16, depth int, elemBase unsafe.Pointer, elemSize uintptr) int16 {
@@ -1493,6 +1702,12 @@ func (t *Template) compileTextC(v TextC, parent int16, depth int, elemBase unsaf
 			op.Kind = OpTextPtr
 			op.StrPtr = val
 		}
+	default:
+		if ptr, src, ok := liveValue[string](val); ok {
+			t.addLive(src)
+			op.Kind = OpTextPtr
+			op.StrPtr = ptr
+		}
 	}
 
 	return t.addOp(op, depth)
@@ -1571,6 +1786,12 @@ func (t *Template) compileProgressC(v ProgressC, parent int16, depth int, elemBa
 			op.Kind = OpProgressPtr
 			op.IntPtr = val
 		}
+	default:
+		if ptr, src, ok := liveInt(val); ok {
+			t.addLive(src)
+			op.Kind = OpProgressPtr
+			op.IntPtr = ptr
+		}
 	}
 
 	return t.addOp(op, depth)
@@ -1668,17 +1889,28 @@ func (t *Template) compileSparklineC(v SparklineC, parent int16, depth int) int1
 }
 
 func (t *Template) compileJumpC(v JumpC, parent int16, depth int, elemBase unsafe.Pointer, elemSize uintptr) int16 {
+	var accel rune
+	if v.key != "" {
+		switch v.hint {
+		case HintUnderline:
+			accel = accelerator(v.key)
+		case HintSuffix:
+			v.child = HBox(v.child, Text(hintSuffix(v.key)).Dim())
+		}
+	}
 	idx := t.addOp(Op{
 		Kind:         OpJump,
 		Parent:       parent,
 		JumpOnSelect: v.onSelect,
 		JumpStyle:    v.style,
+		JumpAccel:    accel,
+		JumpDisabled: v.disabled,
 		ChildStart:   int16(len(t.ops)),
 		Margin:       v.margin,
 	}, depth)
 
 	if v.child != nil {
-		t.compile(v.child, idx, depth+1, elemBase, elemSize)
+		t.compile(grayWhen(v.disabled, v.child), idx, depth+1, elemBase, elemSize)
 	}
 
 	t.ops[idx].ChildEnd = int16(len(t.ops))
@@ -1694,6 +1926,11 @@ func (t *Template) compileLayerViewC(v LayerViewC, parent int16, depth int) int1
 		LayerHeight: v.viewHeight,
 		FlexGrow:    v.flexGrow,
 		Margin:      v.margin,
+
+		LayerHScrollbar:  v.hScrollbar,
+		LayerPins:        t.compilePins(v.pins),
+		ScrollTrackStyle: v.scrollbarStyle,
+		ScrollThumbStyle: v.scrollbarStyle,
 	}, depth)
 }
 
@@ -1733,10 +1970,31 @@ func (t *Template) compileOverlayC(v OverlayC, parent int16, depth int) int16 {
 }
 
 func (t *Template) compileTabsC(v TabsC, parent int16, depth int) int16 {
+	labels := v.labels
+	var accels []int
+	if v.hint != HintNone && len(v.keys) > 0 {
+		labels = append([]string(nil), v.labels...)
+		for i, key := range v.keys[:min(len(v.keys), len(labels))] {
+			switch {
+			case key == "":
+			case v.hint == HintSuffix:
+				labels[i] += hintSuffix(key)
+			case v.hint == HintUnderline:
+				if accels == nil {
+					accels = make([]int, len(labels))
+					for j := range accels {
+						accels[j] = -1
+					}
+				}
+				accels[i] = accelIndex(labels[i], accelerator(key))
+			}
+		}
+	}
 	return t.addOp(Op{
 		Kind:              OpTabs,
 		Parent:            parent,
-		TabsLabels:        v.labels,
+		TabsLabels:        labels,
+		TabsAccel:         accels,
 		TabsSelectedPtr:   v.selected,
 		TabsStyleType:     v.tabStyle,
 		TabsGap:           int(v.gap),
@@ -1853,6 +2111,24 @@ func (t *Template) compileAutoTableReactive(v AutoTableC, rv reflect.Value, pare
 		ss.initialDone = true
 	}
 
+	if v.layout != nil {
+		v.layout.sync(columns)
+	}
+
+	groupField := -1
+	if v.groups != nil {
+		if f, ok := elemType.FieldByName(v.groups.field); ok {
+			groupField = f.Index[0]
+		}
+	}
+	var aggs []Aggregator
+	if len(v.aggregates) > 0 {
+		aggs = make([]Aggregator, len(columns))
+		for i, name := range columns {
+			aggs[i] = v.aggregates[name]
+		}
+	}
+
 	op := Op{
 		Kind:              OpAutoTable,
 		Parent:            parent,
@@ -1867,6 +2143,12 @@ func (t *Template) compileAutoTableReactive(v AutoTableC, rv reflect.Value, pare
 		AutoTableColCfgs:  colCfgs,
 		AutoTableSort:     v.sortState,
 		AutoTableScroll:   v.scroll,
+		AutoTableHScroll:  v.hscroll,
+		AutoTableNames:    columns,
+		AutoTableLayout:   v.layout,
+		AutoTableGroups:   v.groups,
+		AutoTableGroupBy:  groupField,
+		AutoTableAggs:     aggs,
 		Margin:            v.margin,
 	}
 
@@ -2036,9 +2318,7 @@ func (t *Template) compileAutoTableStatic(v AutoTableC, rv reflect.Value, parent
 				} else {
 					str = fmt.Sprintf("%v", val)
 				}
-				if cfg != nil && cfg.style != nil {
-					cellStyle = cfg.style(val)
-				}
+				cellStyle = cfg.cellStyle(rowStyle, val, elem)
 			}
 			if cfg := colCfgs[j]; cfg != nil {
 				cellStyle.Align = cfg.align
@@ -2079,6 +2359,9 @@ func (t *Template) compileCheckboxC(v *CheckboxC, parent int16, depth int, elemB
 
 	box := HBox.Gap(1)(mark, labelNode)
 	box.margin = v.style.margin
+	if v.disabled != nil {
+		return t.compile(grayWhen(v.disabled, box), parent, depth, elemBase, 0)
+	}
 	return t.compileHBoxC(box, parent, depth, elemBase, 0)
 }
 
@@ -2097,26 +2380,40 @@ func (t *Template) compileRadioC(v *RadioC, parent int16, depth int) int16 {
 		items = append(items, item)
 	}
 
+	var group any
 	if v.horizontal {
 		hbox := HBox.Gap(v.gap)(items...)
 		hbox.margin = v.style.margin
-		return t.compileHBoxC(hbox, parent, depth, nil, 0)
+		group = hbox
+	} else {
+		vbox := VBox.Gap(v.gap)(items...)
+		vbox.margin = v.style.margin
+		group = vbox
 	}
-	vbox := VBox.Gap(v.gap)(items...)
-	vbox.margin = v.style.margin
-	return t.compileVBoxC(vbox, parent, depth, nil, 0)
+	return t.compile(grayWhen(v.disabled, group), parent, depth, nil, 0)
 }
 
 func (t *Template) compileInputC(v *InputC, parent int16, depth int) int16 {
 	// Convert to TextInput and compile
 	ti := v.toTextInput()
+	ti.Disabled = v.disabled
 	return t.compile(ti, parent, depth, nil, 0)
 }
 
 // Execute runs all three phases and renders to the buffer.
 func (t *Template) Execute(buf *Buffer, screenW, screenH int16) {
+	// Snapshot channel and atomic bindings before anything reads them
+	t.drainLive()
+
 	// Clear pending overlays from previous frame
 	t.pendingOverlays = t.pendingOverlays[:0]
+	if t.trace != nil {
+		t.trace.boxes = t.trace.boxes[:0]
+	}
+	if t.protects == nil {
+		t.protects = new([]Rect)
+	}
+	*t.protects = (*t.protects)[:0]
 
 	// Phase 1: Width distribution (top → down)
 	t.distributeWidths(screenW, nil)
@@ -2130,8 +2427,16 @@ func (t *Template) Execute(buf *Buffer, screenW, screenH int16) {
 	// Phase 3: Render (top → down)
 	t.render(buf, 0, 0, screenW)
 
-	// Phase 4: Render overlays (after main content so they appear on top)
+	// Phase 4: Render overlays (after main content so they appear on top),
+	// keeping them off protected regions
+	for _, r := range *t.protects {
+		buf.Protect(r.X, r.Y, r.W, r.H)
+	}
 	t.renderOverlays(buf, screenW, screenH)
+	buf.Unprotect()
+
+	// Phase 5: Mount what appeared, unmount what disappeared
+	t.settleMounts()
 }
 
 // distributeWidths assigns W to all ops, top-down.
@@ -2205,7 +2510,7 @@ func (t *Template) computeIntrinsicWidth(idx int16) int16 {
 		}
 
 		// Add gaps for HBox
-		if op.IsRow && childCount > 1 && op.Gap > 0 {
+		if op.IsRow && childCount > 1 && op.Gap != 0 {
 			intrinsicW += int16(op.Gap) * (childCount - 1)
 		}
 
@@ -2393,6 +2698,7 @@ func (t *Template) setOpWidth(op *Op, geom *Geom, availW int16, elemBase unsafe.
 		// Calculate width from the active branch content
 		condTrue := (op.CondPtr != nil && *op.CondPtr) ||
 			(op.CondNode != nil && op.CondNode.evaluateWithBase(elemBase))
+		condTrue = op.ifShown(condTrue)
 		if condTrue && op.ThenTmpl != nil {
 			op.ThenTmpl.elemBase = elemBase
 			// Check if content has fixed-width children (ContentSized)
@@ -2483,6 +2789,7 @@ func (t *Template) distributeVBoxChildWidths(idx int16, op *Op, availW int16, el
 func (t *Template) getIfContentOp(childOp *Op, elemBase unsafe.Pointer) *Op {
 	condTrue := (childOp.CondPtr != nil && *childOp.CondPtr) ||
 		(childOp.CondNode != nil && childOp.CondNode.evaluateWithBase(elemBase))
+	condTrue = childOp.ifShown(condTrue)
 
 	if condTrue && childOp.ThenTmpl != nil && len(childOp.ThenTmpl.ops) > 0 {
 		return &childOp.ThenTmpl.ops[0]
@@ -2550,7 +2857,7 @@ func (t *Template) distributeHBoxChildWidths(idx int16, op *Op, availW int16, el
 	// Note: we track fixedWidthCount during the loop above to avoid double-counting
 	// flex children that might have non-zero W from a previous render
 	childCount := fixedWidthCount + int16(len(flexChildren)) + int16(len(implicitFlexChildren))
-	if childCount > 1 && op.Gap > 0 {
+	if childCount > 1 && op.Gap != 0 {
 		usedW += int16(op.Gap) * (childCount - 1)
 	}
 
@@ -2579,6 +2886,7 @@ func (t *Template) distributeHBoxChildWidths(idx int16, op *Op, availW int16, el
 			if childOp.Kind == OpIf {
 				condTrue := (childOp.CondPtr != nil && *childOp.CondPtr) ||
 					(childOp.CondNode != nil && childOp.CondNode.evaluateWithBase(elemBase))
+				condTrue = childOp.ifShown(condTrue)
 				if condTrue && childOp.ThenTmpl != nil {
 					childOp.ThenTmpl.elemBase = elemBase
 					childOp.ThenTmpl.distributeWidths(flexW, elemBase)
@@ -2608,6 +2916,7 @@ func (t *Template) distributeHBoxChildWidths(idx int16, op *Op, availW int16, el
 			if childOp.Kind == OpIf {
 				condTrue := (childOp.CondPtr != nil && *childOp.CondPtr) ||
 					(childOp.CondNode != nil && childOp.CondNode.evaluateWithBase(elemBase))
+				condTrue = childOp.ifShown(condTrue)
 				if condTrue && childOp.ThenTmpl != nil {
 					childOp.ThenTmpl.elemBase = elemBase
 					childOp.ThenTmpl.distributeWidths(w, elemBase)
@@ -2644,7 +2953,8 @@ func (t *Template) layout(_ int16) {
 			case OpAutoTable:
 				dataRows := 0
 				if op.AutoTableSlicePtr != nil {
-					dataRows = reflect.ValueOf(op.AutoTableSlicePtr).Elem().Len()
+					lines, _ := autoTableLines(op, reflect.ValueOf(op.AutoTableSlicePtr).Elem())
+					dataRows = len(lines)
 				}
 				visibleRows := dataRows
 				if sc := op.AutoTableScroll; sc != nil && sc.maxVisible < visibleRows {
@@ -2835,6 +3145,7 @@ func (t *Template) layoutContainer(idx int16, op *Op, geom *Geom) {
 				// Use evaluateWithBase for conditions in ForEach context
 				condTrue := (childOp.CondPtr != nil && *childOp.CondPtr) ||
 					(childOp.CondNode != nil && childOp.CondNode.evaluateWithBase(t.elemBase))
+				condTrue = childOp.ifShown(condTrue)
 				// Use pre-calculated width if set (from flex distribution), otherwise use availW
 				ifWidth := t.geom[i].W
 				if ifWidth == 0 {
@@ -2842,7 +3153,7 @@ func (t *Template) layoutContainer(idx int16, op *Op, geom *Geom) {
 				}
 				if childOp.ThenTmpl != nil && condTrue {
 					// Add gap before this child if needed
-					if needGap && op.Gap > 0 {
+					if needGap && op.Gap != 0 {
 						cursor += int16(op.Gap)
 					}
 					childOp.ThenTmpl.elemBase = t.elemBase
@@ -2863,7 +3174,7 @@ func (t *Template) layoutContainer(idx int16, op *Op, geom *Geom) {
 					needGap = true // Next visible child needs gap
 				} else if childOp.ElseTmpl != nil && !condTrue {
 					// Add gap before this child if needed
-					if needGap && op.Gap > 0 {
+					if needGap && op.Gap != 0 {
 						cursor += int16(op.Gap)
 					}
 					childOp.ElseTmpl.elemBase = t.elemBase
@@ -2886,7 +3197,7 @@ func (t *Template) layoutContainer(idx int16, op *Op, geom *Geom) {
 
 			case OpForEach:
 				// Add gap before this child if needed
-				if needGap && op.Gap > 0 {
+				if needGap && op.Gap != 0 {
 					cursor += int16(op.Gap)
 				}
 				h, w := t.layoutForEach(i, childOp, availW)
@@ -2913,7 +3224,7 @@ func (t *Template) layoutContainer(idx int16, op *Op, geom *Geom) {
 				}
 				if tmpl != nil {
 					// Add gap before this child if needed
-					if needGap && op.Gap > 0 {
+					if needGap && op.Gap != 0 {
 						cursor += int16(op.Gap)
 					}
 					tmpl.elemBase = t.elemBase
@@ -2936,7 +3247,7 @@ func (t *Template) layoutContainer(idx int16, op *Op, geom *Geom) {
 			default:
 				childGeom := &t.geom[i]
 				// Add gap before this child if needed
-				if needGap && op.Gap > 0 && childGeom.W > 0 {
+				if needGap && op.Gap != 0 && childGeom.W > 0 {
 					cursor += int16(op.Gap)
 				}
 				childGeom.LocalX = contentOffX + cursor
@@ -2968,7 +3279,7 @@ func (t *Template) layoutContainer(idx int16, op *Op, geom *Geom) {
 			}
 
 			// Handle gap
-			if !firstChild && op.Gap > 0 {
+			if !firstChild && op.Gap != 0 {
 				cursor += int16(op.Gap)
 			}
 			firstChild = false
@@ -2979,11 +3290,12 @@ func (t *Template) layoutContainer(idx int16, op *Op, geom *Geom) {
 				// Use evaluateWithBase for conditions in ForEach context
 				condTrue := (childOp.CondPtr != nil && *childOp.CondPtr) ||
 					(childOp.CondNode != nil && childOp.CondNode.evaluateWithBase(t.elemBase))
+				condTrue = childOp.ifShown(condTrue)
 				if childOp.ThenTmpl != nil && condTrue {
 					childOp.ThenTmpl.elemBase = t.elemBase
 					childOp.ThenTmpl.distributeWidths(availW, t.elemBase)
 					childOp.ThenTmpl.layout(0)
-					h := childOp.ThenTmpl.Height()
+					h := childOp.ifHeight(childOp.ThenTmpl.Height())
 					t.geom[i].LocalX = contentOffX
 					t.geom[i].LocalY = contentOffY + cursor
 					t.geom[i].H = h
@@ -3137,6 +3449,7 @@ func (t *Template) stretchRowChildren(idx int16, op *Op) {
 func (t *Template) stretchIfContent(op *Op, newH int16) {
 	condTrue := (op.CondPtr != nil && *op.CondPtr) ||
 		(op.CondNode != nil && op.CondNode.evaluateWithBase(t.elemBase))
+	condTrue = op.ifShown(condTrue)
 
 	var tmpl *Template
 	if condTrue && op.ThenTmpl != nil {
@@ -3211,10 +3524,10 @@ func (t *Template) distributeFlexInCol(idx int16, op *Op, rootH int16) {
 		childGeom := &t.geom[i]
 
 		// Check for direct flex child (container, layer or spacer)
-		if (childOp.Kind == OpContainer || childOp.Kind == OpLayer || childOp.Kind == OpSpacer) && childOp.FlexGrow > 0 {
-			totalFlex += childOp.FlexGrow
+		if grow := childOp.flexGrow(); (childOp.Kind == OpContainer || childOp.Kind == OpLayer || childOp.Kind == OpSpacer) && grow > 0 {
+			totalFlex += grow
 			flexChildren = append(flexChildren, i)
-			flexGrowValues = append(flexGrowValues, childOp.FlexGrow)
+			flexGrowValues = append(flexGrowValues, grow)
 			usedH += childGeom.ContentH // Use content height for flex children
 			continue
 		}
@@ -3237,7 +3550,7 @@ func (t *Template) distributeFlexInCol(idx int16, op *Op, rootH int16) {
 	t.flexScratchGrow = flexGrowValues
 
 	// Add gaps to used height
-	if childCount > 1 && op.Gap > 0 {
+	if childCount > 1 && op.Gap != 0 {
 		usedH += int16(op.Gap) * (childCount - 1)
 	}
 
@@ -3276,7 +3589,7 @@ func (t *Template) distributeFlexInCol(idx int16, op *Op, rootH int16) {
 				continue
 			}
 
-			if !firstChild && op.Gap > 0 {
+			if !firstChild && op.Gap != 0 {
 				cursor += int16(op.Gap)
 			}
 			firstChild = false
@@ -3307,6 +3620,7 @@ func (t *Template) distributeFlexInCol(idx int16, op *Op, rootH int16) {
 func (t *Template) propagateFlexToIf(op *Op, newH int16) {
 	condTrue := (op.CondPtr != nil && *op.CondPtr) ||
 		(op.CondNode != nil && op.CondNode.evaluateWithBase(t.elemBase))
+	condTrue = op.ifShown(condTrue)
 
 	var tmpl *Template
 	if condTrue && op.ThenTmpl != nil {
@@ -3333,6 +3647,7 @@ func (t *Template) getIfFlexGrow(op *Op) float32 {
 	// Determine which branch is active
 	condTrue := (op.CondPtr != nil && *op.CondPtr) ||
 		(op.CondNode != nil && op.CondNode.evaluateWithBase(t.elemBase))
+	condTrue = op.ifShown(condTrue)
 
 	var tmpl *Template
 	if condTrue && op.ThenTmpl != nil {
@@ -3410,6 +3725,9 @@ func (t *Template) layoutForEach(_ int16, op *Op, availW int16) (totalH, maxW in
 
 	sliceHdr := *(*sliceHeader)(op.SlicePtr)
 	if sliceHdr.Len == 0 {
+		if op.keyed != nil {
+			op.keyed.reconcile(nil, 0)
+		}
 		return 0, 0
 	}
 
@@ -3419,16 +3737,21 @@ func (t *Template) layoutForEach(_ int16, op *Op, availW int16) (totalH, maxW in
 	}
 	op.iterGeoms = op.iterGeoms[:sliceHdr.Len]
 
+	if op.keyed != nil {
+		op.keyed.reconcile(sliceHdr.Data, sliceHdr.Len)
+	}
+
 	cursor := int16(0)
 	for i := 0; i < sliceHdr.Len; i++ {
 		// Get element pointer for this item
 		elemPtr := unsafe.Pointer(uintptr(sliceHdr.Data) + uintptr(i)*op.ElemSize)
 
 		// Layout sub-template for this item with element base
-		op.IterTmpl.elemBase = elemPtr // Set element base for condition evaluation
-		op.IterTmpl.distributeWidths(availW, elemPtr)
-		op.IterTmpl.layout(0)
-		itemH := op.IterTmpl.Height()
+		tmpl := op.itemTmpl(i)
+		tmpl.elemBase = elemPtr // Set element base for condition evaluation
+		tmpl.distributeWidths(availW, elemPtr)
+		tmpl.layout(0)
+		itemH := tmpl.Height()
 
 		op.iterGeoms[i].LocalX = 0
 		op.iterGeoms[i].LocalY = cursor
@@ -3527,10 +3850,20 @@ func (t *Template) renderOp(buf *Buffer, idx int16, globalX, globalY, maxW int16
 
 	op := &t.ops[idx]
 	geom := &t.geom[idx]
+	if op.life != nil {
+		op.life.seen = true
+	}
 
 	// Compute absolute position
 	absX := globalX + geom.LocalX
 	absY := globalY + geom.LocalY
+	if t.trace != nil {
+		t.trace.add(op, absX, absY, geom)
+		defer t.trace.enter()()
+	}
+	if op.protect {
+		t.addProtect(op, geom, absX, absY)
+	}
 
 	// generic margin offset for non-container ops (containers handle margin themselves)
 	if op.Kind != OpContainer && op.marginH()+op.marginV() > 0 {
@@ -3579,16 +3912,18 @@ func (t *Template) renderOp(buf *Buffer, idx int16, globalX, globalY, maxW int16
 	case OpRichText:
 		spans := op.StaticSpans
 		if op.SpanStrOffs != nil {
-			spans = resolveSpanStrs(spans, op.SpanStrOffs, nil)
+			spans = t.resolveSpanStrs(spans, op.SpanStrOffs, nil)
 		}
 		buf.WriteSpans(int(absX), int(absY), spans, int(maxW))
+		t.spanJumps(buf, spans, int(absX), int(absY), int(maxW))
 
 	case OpRichTextPtr:
 		spans := *op.SpansPtr
 		if op.SpanStrOffs != nil {
-			spans = resolveSpanStrs(spans, op.SpanStrOffs, nil)
+			spans = t.resolveSpanStrs(spans, op.SpanStrOffs, nil)
 		}
 		buf.WriteSpans(int(absX), int(absY), spans, int(maxW))
+		t.spanJumps(buf, spans, int(absX), int(absY), int(maxW))
 
 	case OpRichTextOff:
 		// top-level render has no elemBase; skip
@@ -3646,12 +3981,12 @@ func (t *Template) renderOp(buf *Buffer, idx int16, globalX, globalY, maxW int16
 
 	case OpSparkline:
 		style := t.effectiveStyle(op.SparkStyle)
-		buf.WriteSparkline(int(absX), int(absY), op.SparkValues, int(contentW), op.SparkMin, op.SparkMax, style)
+		buf.writeSparkline(int(absX), int(absY), op.SparkValues, int(contentW), op.SparkMin, op.SparkMax, style, t.quality > QualityFull)
 
 	case OpSparklinePtr:
 		if op.SparkValuesPtr != nil {
 			style := t.effectiveStyle(op.SparkStyle)
-			buf.WriteSparkline(int(absX), int(absY), *op.SparkValuesPtr, int(contentW), op.SparkMin, op.SparkMax, style)
+			buf.writeSparkline(int(absX), int(absY), *op.SparkValuesPtr, int(contentW), op.SparkMin, op.SparkMax, style, t.quality > QualityFull)
 		}
 
 	case OpHRule:
@@ -3726,23 +4061,7 @@ func (t *Template) renderOp(buf *Buffer, idx int16, globalX, globalY, maxW int16
 		}
 
 	case OpLayer:
-		// Blit the layer's visible portion to the buffer
-		if op.LayerPtr != nil {
-			layerW := int(contentW)
-			if op.LayerWidth > 0 {
-				layerW = int(op.LayerWidth)
-			}
-			op.LayerPtr.SetViewport(layerW, int(contentH))
-			op.LayerPtr.screenX = int(absX) // set screen offset for cursor translation
-			op.LayerPtr.screenY = int(absY)
-			op.LayerPtr.prepare() // re-render if dimensions changed
-			op.LayerPtr.blit(buf, int(absX), int(absY), layerW, int(contentH))
-
-			// track layer with visible cursor for automatic cursor positioning
-			if op.LayerPtr.cursor.Visible && t.app != nil {
-				t.app.activeLayer = op.LayerPtr
-			}
-		}
+		t.renderLayer(buf, op, absX, absY, contentW, contentH)
 
 	case OpContainer:
 		// Margin inset: visible box starts inside the margin
@@ -3772,6 +4091,9 @@ func (t *Template) renderOp(buf *Buffer, idx int16, globalX, globalY, maxW int16
 			fillCell := Cell{Rune: ' ', Style: Style{BG: fillColor}}
 			buf.FillRect(int(boxX), int(boxY), int(boxW), int(boxH), fillCell)
 		}
+		if op.Pattern != nil {
+			op.Pattern.draw(buf, int(boxX), int(boxY), int(boxW), int(boxH), fillColor)
+		}
 
 		// Draw border if present
 		if op.Border.Horizontal != 0 {
@@ -3784,7 +4106,11 @@ func (t *Template) renderOp(buf *Buffer, idx int16, globalX, globalY, maxW int16
 			} else if fillColor.Mode != ColorDefault {
 				style.BG = fillColor
 			}
-			buf.DrawBorder(int(boxX), int(boxY), int(boxW), int(boxH), op.Border, style)
+			if op.Parent >= 0 && t.ops[op.Parent].Gap < 0 {
+				buf.DrawBox(int(boxX), int(boxY), int(boxW), int(boxH), op.Border, style)
+			} else {
+				buf.DrawBorder(int(boxX), int(boxY), int(boxW), int(boxH), op.Border, style)
+			}
 
 			if op.Title != "" {
 				titleTransform := TransformNone
@@ -3798,7 +4124,7 @@ func (t *Template) renderOp(buf *Buffer, idx int16, globalX, globalY, maxW int16
 					titleX++
 					buf.SetFast(titleX, int(boxY), Cell{Rune: ' ', Style: style})
 					titleX++
-					title := applyTransform(op.Title, titleTransform)
+					title := applyTransform(op.title(), titleTransform)
 					titleW := utf8.RuneCountInString(title)
 					availTitleW := titleMaxW - 3 // border char + space before + space after
 					if availTitleW > 0 {
@@ -3811,6 +4137,7 @@ func (t *Template) renderOp(buf *Buffer, idx int16, globalX, globalY, maxW int16
 					}
 				}
 			}
+			t.collapseJump(buf, op, boxX+3, boxY)
 		}
 
 		// Calculate content width (accounting for margin + border)
@@ -3847,20 +4174,31 @@ func (t *Template) renderOp(buf *Buffer, idx int16, globalX, globalY, maxW int16
 	case OpIf:
 		// Render active branch if condition is true
 		condTrue := (op.CondPtr != nil && *op.CondPtr) || (op.CondNode != nil && op.CondNode.evaluate())
+		condTrue = op.ifShown(condTrue)
 		if op.ThenTmpl != nil && condTrue {
 			op.ThenTmpl.app = t.app
+			op.ThenTmpl.quality = t.quality
 			op.ThenTmpl.inheritedStyle = t.inheritedStyle // propagate inherited style
 			op.ThenTmpl.inheritedFill = t.inheritedFill   // propagate inherited fill
 			op.ThenTmpl.clipMaxY = t.clipMaxY             // propagate vertical clip
+			op.ThenTmpl.trace = t.trace
+			if op.trans != nil {
+				op.ThenTmpl.clipMaxY = clipTo(t.clipMaxY, absY+geom.H)
+			}
 			op.ThenTmpl.pendingOverlays = op.ThenTmpl.pendingOverlays[:0]
 			op.ThenTmpl.render(buf, absX, absY, geom.W)
+			if op.trans != nil {
+				op.trans.dim(buf, absX, absY, geom.W, geom.H)
+			}
 			// Propagate overlays from sub-template to main template
 			t.pendingOverlays = append(t.pendingOverlays, op.ThenTmpl.pendingOverlays...)
 		} else if op.ElseTmpl != nil && !condTrue {
 			op.ElseTmpl.app = t.app
+			op.ElseTmpl.quality = t.quality
 			op.ElseTmpl.inheritedStyle = t.inheritedStyle // propagate inherited style
 			op.ElseTmpl.inheritedFill = t.inheritedFill   // propagate inherited fill
 			op.ElseTmpl.clipMaxY = t.clipMaxY             // propagate vertical clip
+			op.ElseTmpl.trace = t.trace
 			op.ElseTmpl.pendingOverlays = op.ElseTmpl.pendingOverlays[:0]
 			op.ElseTmpl.render(buf, absX, absY, geom.W)
 			t.pendingOverlays = append(t.pendingOverlays, op.ElseTmpl.pendingOverlays...)
@@ -3883,7 +4221,7 @@ func (t *Template) renderOp(buf *Buffer, idx int16, globalX, globalY, maxW int16
 
 			// Rebind template ops to this element's data
 			elemPtr := unsafe.Pointer(uintptr(sliceHdr.Data) + uintptr(i)*op.ElemSize)
-			t.renderSubTemplate(buf, op.IterTmpl, itemAbsX, itemAbsY, itemGeom.W, elemPtr)
+			t.renderSubTemplate(buf, op.itemTmpl(i), itemAbsX, itemAbsY, itemGeom.W, elemPtr)
 		}
 
 	case OpSwitch:
@@ -3897,6 +4235,9 @@ func (t *Template) renderOp(buf *Buffer, idx int16, globalX, globalY, maxW int16
 		}
 		if tmpl != nil {
 			tmpl.clipMaxY = t.clipMaxY // propagate vertical clip
+			tmpl.app = t.app
+			tmpl.quality = t.quality
+			tmpl.trace = t.trace
 			tmpl.render(buf, absX, absY, geom.W)
 		}
 	}
@@ -3905,6 +4246,10 @@ func (t *Template) renderOp(buf *Buffer, idx int16, globalX, globalY, maxW int16
 // renderSubTemplate renders a sub-template (for ForEach) with element-bound data.
 func (t *Template) renderSubTemplate(buf *Buffer, sub *Template, globalX, globalY, maxW int16, elemBase unsafe.Pointer) {
 	sub.clipMaxY = t.clipMaxY // propagate vertical clip
+	sub.app = t.app
+	sub.quality = t.quality
+	sub.trace = t.trace
+	sub.protects = t.protects
 	// Render root-level ops in sub-template
 	for i := range sub.ops {
 		if sub.ops[i].Parent == -1 {
@@ -3917,9 +4262,19 @@ func (t *Template) renderSubTemplate(buf *Buffer, sub *Template, globalX, global
 func (sub *Template) renderSubOp(buf *Buffer, idx int16, globalX, globalY, maxW int16, elemBase unsafe.Pointer) {
 	op := &sub.ops[idx]
 	geom := &sub.geom[idx]
+	if op.life != nil {
+		op.life.seen = true
+	}
 
 	absX := globalX + geom.LocalX
 	absY := globalY + geom.LocalY
+	if sub.trace != nil {
+		sub.trace.add(op, absX, absY, geom)
+		defer sub.trace.enter()()
+	}
+	if op.protect {
+		sub.addProtect(op, geom, absX, absY)
+	}
 
 	// generic margin offset for non-container ops
 	if op.Kind != OpContainer && op.marginH()+op.marginV() > 0 {
@@ -3978,24 +4333,27 @@ func (sub *Template) renderSubOp(buf *Buffer, idx int16, globalX, globalY, maxW
 	case OpRichText:
 		spans := op.StaticSpans
 		if op.SpanStrOffs != nil {
-			spans = resolveSpanStrs(spans, op.SpanStrOffs, elemBase)
+			spans = sub.resolveSpanStrs(spans, op.SpanStrOffs, elemBase)
 		}
 		buf.WriteSpans(int(absX), int(absY), spans, int(maxW))
+		sub.spanJumps(buf, spans, int(absX), int(absY), int(maxW))
 
 	case OpRichTextPtr:
 		spans := *op.SpansPtr
 		if op.SpanStrOffs != nil {
-			spans = resolveSpanStrs(spans, op.SpanStrOffs, elemBase)
+			spans = sub.resolveSpanStrs(spans, op.SpanStrOffs, elemBase)
 		}
 		buf.WriteSpans(int(absX), int(absY), spans, int(maxW))
+		sub.spanJumps(buf, spans, int(absX), int(absY), int(maxW))
 
 	case OpRichTextOff:
 		spansPtr := (*[]Span)(unsafe.Pointer(uintptr(elemBase) + op.SpansOff))
 		spans := *spansPtr
 		if op.SpanStrOffs != nil {
-			spans = resolveSpanStrs(spans, op.SpanStrOffs, elemBase)
+			spans = sub.resolveSpanStrs(spans, op.SpanStrOffs, elemBase)
 		}
 		buf.WriteSpans(int(absX), int(absY), spans, int(maxW))
+		sub.spanJumps(buf, spans, int(absX), int(absY), int(maxW))
 
 	case OpLeader:
 		width := int(op.Width)
@@ -4046,12 +4404,12 @@ func (sub *Template) renderSubOp(buf *Buffer, idx int16, globalX, globalY, maxW
 
 	case OpSparkline:
 		style := sub.effectiveStyle(op.SparkStyle)
-		buf.WriteSparkline(int(absX), int(absY), op.SparkValues, int(contentW), op.SparkMin, op.SparkMax, style)
+		buf.writeSparkline(int(absX), int(absY), op.SparkValues, int(contentW), op.SparkMin, op.SparkMax, style, sub.quality > QualityFull)
 
 	case OpSparklinePtr:
 		if op.SparkValuesPtr != nil {
 			style := sub.effectiveStyle(op.SparkStyle)
-			buf.WriteSparkline(int(absX), int(absY), *op.SparkValuesPtr, int(contentW), op.SparkMin, op.SparkMax, style)
+			buf.writeSparkline(int(absX), int(absY), *op.SparkValuesPtr, int(contentW), op.SparkMin, op.SparkMax, style, sub.quality > QualityFull)
 		}
 
 	case OpHRule:
@@ -4132,23 +4490,7 @@ func (sub *Template) renderSubOp(buf *Buffer, idx int16, globalX, globalY, maxW
 		}
 
 	case OpLayer:
-		// Blit the layer's visible portion to the buffer
-		if op.LayerPtr != nil {
-			layerW := int(contentW)
-			if op.LayerWidth > 0 {
-				layerW = int(op.LayerWidth)
-			}
-			op.LayerPtr.SetViewport(layerW, int(contentH))
-			op.LayerPtr.screenX = int(absX) // set screen offset for cursor translation
-			op.LayerPtr.screenY = int(absY)
-			op.LayerPtr.prepare() // re-render if dimensions changed
-			op.LayerPtr.blit(buf, int(absX), int(absY), layerW, int(contentH))
-
-			// track layer with visible cursor for automatic cursor positioning
-			if op.LayerPtr.cursor.Visible && sub.app != nil {
-				sub.app.activeLayer = op.LayerPtr
-			}
-		}
+		sub.renderLayer(buf, op, absX, absY, contentW, contentH)
 
 	case OpContainer:
 		// Margin inset: visible box starts inside the margin
@@ -4178,6 +4520,9 @@ func (sub *Template) renderSubOp(buf *Buffer, idx int16, globalX, globalY, maxW
 			fillCell := Cell{Rune: ' ', Style: Style{BG: fillColor}}
 			buf.FillRect(int(boxX), int(boxY), int(boxW), int(boxH), fillCell)
 		}
+		if op.Pattern != nil {
+			op.Pattern.draw(buf, int(boxX), int(boxY), int(boxW), int(boxH), fillColor)
+		}
 
 		// Draw border if present
 		if op.Border.Horizontal != 0 {
@@ -4190,7 +4535,11 @@ func (sub *Template) renderSubOp(buf *Buffer, idx int16, globalX, globalY, maxW
 			} else if fillColor.Mode != ColorDefault {
 				style.BG = fillColor
 			}
-			buf.DrawBorder(int(boxX), int(boxY), int(boxW), int(boxH), op.Border, style)
+			if op.Parent >= 0 && sub.ops[op.Parent].Gap < 0 {
+				buf.DrawBox(int(boxX), int(boxY), int(boxW), int(boxH), op.Border, style)
+			} else {
+				buf.DrawBorder(int(boxX), int(boxY), int(boxW), int(boxH), op.Border, style)
+			}
 
 			if op.Title != "" {
 				titleTransform := TransformNone
@@ -4204,7 +4553,7 @@ func (sub *Template) renderSubOp(buf *Buffer, idx int16, globalX, globalY, maxW
 					titleX++
 					buf.SetFast(titleX, int(boxY), Cell{Rune: ' ', Style: style})
 					titleX++
-					title := applyTransform(op.Title, titleTransform)
+					title := applyTransform(op.title(), titleTransform)
 					titleW := utf8.RuneCountInString(title)
 					availTitleW := titleMaxW - 3 // border char + space before + space after
 					if availTitleW > 0 {
@@ -4217,6 +4566,7 @@ func (sub *Template) renderSubOp(buf *Buffer, idx int16, globalX, globalY, maxW
 					}
 				}
 			}
+			sub.collapseJump(buf, op, boxX+3, boxY)
 		}
 
 		// Calculate content width (accounting for margin + border)
@@ -4242,10 +4592,19 @@ func (sub *Template) renderSubOp(buf *Buffer, idx int16, globalX, globalY, maxW
 	case OpIf:
 		// Use evaluateWithBase for conditions inside ForEach
 		condTrue := (op.CondPtr != nil && *op.CondPtr) || (op.CondNode != nil && op.CondNode.evaluateWithBase(elemBase))
+		condTrue = op.ifShown(condTrue)
 		if op.ThenTmpl != nil && condTrue {
 			op.ThenTmpl.inheritedStyle = sub.inheritedStyle // propagate inherited style
 			op.ThenTmpl.inheritedFill = sub.inheritedFill   // propagate inherited fill
-			sub.renderSubTemplate(buf, op.ThenTmpl, absX, absY, geom.W, elemBase)
+			if op.trans != nil {
+				oldClip := sub.clipMaxY
+				sub.clipMaxY = clipTo(oldClip, absY+geom.H)
+				sub.renderSubTemplate(buf, op.ThenTmpl, absX, absY, geom.W, elemBase)
+				sub.clipMaxY = oldClip
+				op.trans.dim(buf, absX, absY, geom.W, geom.H)
+			} else {
+				sub.renderSubTemplate(buf, op.ThenTmpl, absX, absY, geom.W, elemBase)
+			}
 		} else if op.ElseTmpl != nil && !condTrue {
 			op.ElseTmpl.inheritedStyle = sub.inheritedStyle // propagate inherited style
 			op.ElseTmpl.inheritedFill = sub.inheritedFill   // propagate inherited fill
@@ -4261,7 +4620,7 @@ func (sub *Template) renderSubOp(buf *Buffer, idx int16, globalX, globalY, maxW
 				itemAbsX := absX + itemGeom.LocalX
 				itemAbsY := absY + itemGeom.LocalY
 				nestedElemPtr := unsafe.Pointer(uintptr(sliceHdr.Data) + uintptr(j)*op.ElemSize)
-				sub.renderSubTemplate(buf, op.IterTmpl, itemAbsX, itemAbsY, itemGeom.W, nestedElemPtr)
+				sub.renderSubTemplate(buf, op.itemTmpl(j), itemAbsX, itemAbsY, itemGeom.W, nestedElemPtr)
 			}
 		}
 
@@ -4284,6 +4643,9 @@ func (sub *Template) renderSubOp(buf *Buffer, idx int16, globalX, globalY, maxW
 func (t *Template) renderSelectionList(buf *Buffer, op *Op, geom *Geom, absX, absY, maxW int16) {
 	sliceHdr := *(*sliceHeader)(op.SlicePtr)
 	if sliceHdr.Len == 0 {
+		if op.SelectionListPtr != nil {
+			op.SelectionListPtr.observeWindow(0, 0)
+		}
 		return
 	}
 
@@ -4308,6 +4670,9 @@ func (t *Template) renderSelectionList(buf *Buffer, op *Op, geom *Geom, absX, ab
 	if t.clipMaxY > 0 {
 		availableRows := int(t.clipMaxY - absY)
 		if availableRows <= 0 {
+			if op.SelectionListPtr != nil {
+				op.SelectionListPtr.observeWindow(0, 0)
+			}
 			return
 		}
 		if endIdx-startIdx > availableRows {
@@ -4337,6 +4702,10 @@ func (t *Template) renderSelectionList(buf *Buffer, op *Op, geom *Geom, absX, ab
 		}
 	}
 
+	if op.SelectionListPtr != nil {
+		op.SelectionListPtr.observeWindow(startIdx, endIdx)
+	}
+
 	// Pre-computed spaces for non-selected items (same width as marker)
 	spaces := op.MarkerSpaces
 
@@ -4447,22 +4816,25 @@ func (t *Template) renderSelectionList(buf *Buffer, op *Op, geom *Geom, absX, ab
 				case OpRichText:
 					spans := iterOp.StaticSpans
 					if iterOp.SpanStrOffs != nil {
-						spans = resolveSpanStrs(spans, iterOp.SpanStrOffs, elemPtr)
+						spans = t.resolveSpanStrs(spans, iterOp.SpanStrOffs, elemPtr)
 					}
 					buf.WriteSpans(int(contentX), y, spans, int(contentW))
+					t.spanJumps(buf, spans, int(contentX), y, int(contentW))
 				case OpRichTextPtr:
 					spans := *iterOp.SpansPtr
 					if iterOp.SpanStrOffs != nil {
-						spans = resolveSpanStrs(spans, iterOp.SpanStrOffs, elemPtr)
+						spans = t.resolveSpanStrs(spans, iterOp.SpanStrOffs, elemPtr)
 					}
 					buf.WriteSpans(int(contentX), y, spans, int(contentW))
+					t.spanJumps(buf, spans, int(contentX), y, int(contentW))
 				case OpRichTextOff:
 					spansPtr := (*[]Span)(unsafe.Pointer(uintptr(elemPtr) + iterOp.SpansOff))
 					spans := *spansPtr
 					if iterOp.SpanStrOffs != nil {
-						spans = resolveSpanStrs(spans, iterOp.SpanStrOffs, elemPtr)
+						spans = t.resolveSpanStrs(spans, iterOp.SpanStrOffs, elemPtr)
 					}
 					buf.WriteSpans(int(contentX), y, spans, int(contentW))
+					t.spanJumps(buf, spans, int(contentX), y, int(contentW))
 				}
 			}
 		}
@@ -4587,25 +4959,13 @@ func (t *Template) renderJump(buf *Buffer, op *Op, geom *Geom, absX, absY, maxW
 		}
 	}
 
+	if op.JumpAccel != 0 {
+		underlineAccel(buf, int(absX), int(absY), int(geom.W), op.JumpAccel)
+	}
+
 	// If jump mode is active, register this target and draw label
-	if t.app != nil && t.app.JumpModeActive() {
-		t.app.AddJumpTarget(absX, absY, op.JumpOnSelect, op.JumpStyle)
-
-		// Draw label if assigned
-		jm := t.app.JumpMode()
-		for i := len(jm.Targets) - 1; i >= 0; i-- {
-			target := &jm.Targets[i]
-			if target.X == absX && target.Y == absY && target.Label != "" {
-				style := t.app.JumpStyle().LabelStyle
-				if !target.Style.Equal(Style{}) {
-					style = target.Style
-				}
-				for j, r := range target.Label {
-					buf.Set(int(absX)+j, int(absY), Cell{Rune: r, Style: style})
-				}
-				break
-			}
-		}
+	if t.app != nil && t.app.JumpModeActive() && !isSet(op.JumpDisabled) {
+		t.jumpTarget(buf, absX, absY, op.JumpOnSelect, op.JumpStyle)
 	}
 }
 
@@ -4650,11 +5010,17 @@ func (t *Template) renderTextInput(buf *Buffer, op *Op, geom *Geom, absX, absY i
 		// Default: show cursor if we have cursor tracking
 		showCursor = op.TextInputFieldPtr != nil || op.TextInputCursorPtr != nil
 	}
+	textStyle, placeholderStyle := op.TextInputStyle, op.TextInputPlaceholderSty
+	if isSet(op.TextInputDisabledPtr) {
+		showCursor = false
+		textStyle = disabledStyle(textStyle)
+		placeholderStyle = disabledStyle(placeholderStyle)
+	}
 
 	// Handle empty state with placeholder
 	if value == "" {
 		if op.TextInputPlaceholder != "" {
-			buf.WriteStringFast(int(absX), int(absY), op.TextInputPlaceholder, op.TextInputPlaceholderSty, width)
+			buf.WriteStringFast(int(absX), int(absY), op.TextInputPlaceholder, placeholderStyle, width)
 		}
 		// Draw cursor at start if focused
 		if showCursor {
@@ -4694,7 +5060,7 @@ func (t *Template) renderTextInput(buf *Buffer, op *Op, geom *Geom, absX, absY i
 
 	x := int(absX)
 	for i := scrollOffset; i < visibleEnd; i++ {
-		style := op.TextInputStyle
+		style := textStyle
 		// Highlight cursor position if focused
 		if showCursor && i == cursorRune {
 			style = op.TextInputCursorStyle
@@ -4712,6 +5078,10 @@ func (t *Template) renderTextInput(buf *Buffer, op *Op, geom *Geom, absX, absY i
 // renderOverlays renders all collected overlays after main content.
 func (t *Template) renderOverlays(buf *Buffer, screenW, screenH int16) {
 	for _, po := range t.pendingOverlays {
+		if po.draw != nil {
+			po.draw(buf, screenW, screenH)
+			continue
+		}
 		t.renderOverlay(buf, po.op, screenW, screenH)
 	}
 }
@@ -4724,6 +5094,7 @@ func (t *Template) renderOverlay(buf *Buffer, op *Op, screenW, screenH int16) {
 
 	// Link app to child template for jump mode support
 	op.OverlayChildTmpl.app = t.app
+	op.OverlayChildTmpl.quality = t.quality
 
 	// Calculate content size by doing a dry-run layout
 	childTmpl := op.OverlayChildTmpl
@@ -4795,6 +5166,7 @@ func (t *Template) renderOverlay(buf *Buffer, op *Op, screenW, screenH int16) {
 	childTmpl.distributeWidths(overlayW, nil)
 	childTmpl.layout(overlayH)
 	childTmpl.distributeFlexGrow(overlayH)
+	childTmpl.trace = t.trace
 	childTmpl.render(buf, posX, posY, overlayW)
 }
 
@@ -4817,6 +5189,10 @@ func (t *Template) renderTabs(buf *Buffer, op *Op, geom *Geom, absX, absY int16)
 		// apply transform to label text
 		label = applyTransform(label, style.Transform)
 		labelLen := utf8.RuneCountInString(label)
+		accel := -1
+		if i < len(op.TabsAccel) {
+			accel = op.TabsAccel[i]
+		}
 
 		switch op.TabsStyleType {
 		case TabsStyleBox:
@@ -4839,12 +5215,14 @@ func (t *Template) renderTabs(buf *Buffer, op *Op, geom *Geom, absX, absY int16)
 				buf.Set(x+1+j, y+2, Cell{Rune: '─', Style: style})
 			}
 			buf.Set(x+labelLen+3, y+2, Cell{Rune: '┘', Style: style})
+			underlineCell(buf, x+2, y+1, accel)
 			x += labelLen + 4 + op.TabsGap
 
 		case TabsStyleBracket:
 			buf.Set(x, y, Cell{Rune: '[', Style: style})
 			buf.WriteStringFast(x+1, y, label, style, labelLen)
 			buf.Set(x+1+labelLen, y, Cell{Rune: ']', Style: style})
+			underlineCell(buf, x+1, y, accel)
 			x += labelLen + 2 + op.TabsGap
 
 		default: // TabsStyleUnderline
@@ -4855,12 +5233,51 @@ func (t *Template) renderTabs(buf *Buffer, op *Op, geom *Geom, absX, absY int16)
 				buf.WriteStringFast(x, y, label, underlineStyle, labelLen)
 			} else {
 				buf.WriteStringFast(x, y, label, style, labelLen)
+				underlineCell(buf, x, y, accel)
 			}
 			x += labelLen + op.TabsGap
 		}
 	}
 }
 
+// renderLayer blits the visible portion of a LayerView.
+func (t *Template) renderLayer(buf *Buffer, op *Op, absX, absY, contentW, contentH int16) {
+	if op.LayerPtr == nil {
+		return
+	}
+	layerW := int(contentW)
+	if op.LayerWidth > 0 {
+		layerW = int(op.LayerWidth)
+	}
+	layerH := int(contentH)
+	if op.LayerHScrollbar && layerH > 1 {
+		layerH-- // bottom row holds the scrollbar
+	}
+	op.LayerPtr.SetViewport(layerW, layerH)
+	op.LayerPtr.screenX = int(absX) // set screen offset for cursor translation
+	op.LayerPtr.screenY = int(absY)
+	op.LayerPtr.prepare() // re-render if dimensions changed
+	op.LayerPtr.blit(buf, int(absX), int(absY), layerW, layerH)
+	t.renderPins(buf, op, int(absX), int(absY), layerW, layerH)
+
+	if layerH < int(contentH) {
+		l := op.LayerPtr
+		thumbPos, thumbSize := scrollThumb(layerW, l.ContentWidth(), layerW, l.ScrollX())
+		for i := 0; i < layerW; i++ {
+			c := Cell{Rune: '─', Style: op.ScrollTrackStyle}
+			if i >= thumbPos && i < thumbPos+thumbSize && l.MaxScrollX() > 0 {
+				c = Cell{Rune: '━', Style: op.ScrollThumbStyle}
+			}
+			buf.Set(int(absX)+i, int(absY)+layerH, c)
+		}
+	}
+
+	// track layer with visible cursor for automatic cursor positioning
+	if op.LayerPtr.cursor.Visible && t.app != nil {
+		t.app.activeLayer = op.LayerPtr
+	}
+}
+
 func (t *Template) renderScrollbar(buf *Buffer, op *Op, geom *Geom, absX, absY int16) {
 	// Calculate scrollbar dimensions
 	length := int(geom.H)
@@ -4879,40 +5296,7 @@ func (t *Template) renderScrollbar(buf *Buffer, op *Op, geom *Geom, absX, absY i
 	}
 
 	// Calculate thumb size and position
-	contentSize := op.ScrollContentSize
-	viewSize := op.ScrollViewSize
-	if contentSize <= 0 {
-		contentSize = 1
-	}
-	if viewSize <= 0 {
-		viewSize = 1
-	}
-
-	// Thumb size proportional to view/content ratio (minimum 1)
-	thumbSize := (viewSize * length) / contentSize
-	if thumbSize < 1 {
-		thumbSize = 1
-	}
-	if thumbSize > length {
-		thumbSize = length
-	}
-
-	// Thumb position
-	scrollRange := contentSize - viewSize
-	if scrollRange <= 0 {
-		scrollRange = 1
-	}
-	trackSpace := length - thumbSize
-	thumbPos := 0
-	if trackSpace > 0 {
-		thumbPos = (pos * trackSpace) / scrollRange
-		if thumbPos < 0 {
-			thumbPos = 0
-		}
-		if thumbPos > trackSpace {
-			thumbPos = trackSpace
-		}
-	}
+	thumbPos, thumbSize := scrollThumb(length, op.ScrollContentSize, op.ScrollViewSize, pos)
 
 	// Draw the scrollbar
 	if op.ScrollHorizontal {
@@ -4946,6 +5330,43 @@ func (t *Template) renderScrollbar(buf *Buffer, op *Op, geom *Geom, absX, absY i
 	}
 }
 
+// scrollThumb returns the offset and size of a scrollbar thumb on a track
+// of length cells.
+func scrollThumb(length, contentSize, viewSize, pos int) (thumbPos, thumbSize int) {
+	if contentSize <= 0 {
+		contentSize = 1
+	}
+	if viewSize <= 0 {
+		viewSize = 1
+	}
+
+	// Thumb size proportional to view/content ratio (minimum 1)
+	thumbSize = (viewSize * length) / contentSize
+	if thumbSize < 1 {
+		thumbSize = 1
+	}
+	if thumbSize > length {
+		thumbSize = length
+	}
+
+	// Thumb position
+	scrollRange := contentSize - viewSize
+	if scrollRange <= 0 {
+		scrollRange = 1
+	}
+	trackSpace := length - thumbSize
+	if trackSpace > 0 {
+		thumbPos = (pos * trackSpace) / scrollRange
+		if thumbPos < 0 {
+			thumbPos = 0
+		}
+		if thumbPos > trackSpace {
+			thumbPos = trackSpace
+		}
+	}
+	return thumbPos, thumbSize
+}
+
 func (t *Template) renderTable(buf *Buffer, op *Op, absX, absY, maxW int16) {
 	if op.TableRowsPtr == nil {
 		return
@@ -5040,6 +5461,17 @@ func (t *Template) renderAutoTable(buf *Buffer, op *Op, absX, absY, maxW int16)
 	nCols := len(op.AutoTableFields)
 	gap := int(op.AutoTableGap)
 
+	// display order -- every column, unless a layout reorders or hides some
+	cols := make([]int, nCols)
+	for i := range cols {
+		cols[i] = i
+	}
+	layout := op.AutoTableLayout
+	if layout != nil {
+		layout.clampActive()
+		cols = layout.visible(op.AutoTableNames)
+	}
+
 	// re-apply sort if active (keeps data consistent after mutations)
 	if ss := op.AutoTableSort; ss != nil && ss.col >= 0 && ss.col < nCols {
 		autoTableSort(op.AutoTableSlicePtr, op.AutoTableFields[ss.col], ss.asc)
@@ -5063,44 +5495,100 @@ func (t *Template) renderAutoTable(buf *Buffer, op *Op, absX, absY, maxW int16)
 			elem = elem.Elem()
 		}
 		for j, fi := range op.AutoTableFields {
-			val := elem.Field(fi).Interface()
-			var str string
-			if cfg := op.AutoTableColCfgs[j]; cfg != nil && cfg.format != nil {
-				str = cfg.format(val)
-			} else {
-				str = fmt.Sprintf("%v", val)
+			str := autoTableCellText(op.AutoTableColCfgs[j], elem.Field(fi).Interface())
+			if len(str) > widths[j] {
+				widths[j] = len(str)
 			}
+		}
+	}
+
+	// group headers and aggregate footers interleave with the data rows
+	lines, groups := autoTableLines(op, rv)
+	for _, g := range groups {
+		for j, str := range g.footer {
 			if len(str) > widths[j] {
 				widths[j] = len(str)
 			}
 		}
 	}
 
+	// columns resized by the user keep their width; the rest share what's left
+	fixed := make([]bool, nCols)
+	if layout != nil {
+		for _, c := range layout.Columns {
+			if i := slices.Index(op.AutoTableNames, c.Field); i >= 0 && c.Width > 0 {
+				widths[i] = c.Width
+				fixed[i] = true
+			}
+		}
+	}
+
 	// distribute remaining width proportionally to natural column widths
 	availW := int(maxW) - int(absX)
-	totalNatural := 0
-	for _, w := range widths {
-		totalNatural += w
+	totalNatural, totalFixed := 0, 0
+	for _, i := range cols {
+		if fixed[i] {
+			totalFixed += widths[i]
+		} else {
+			totalNatural += widths[i]
+		}
 	}
-	totalGaps := gap * (nCols - 1)
-	remaining := availW - totalNatural - totalGaps
+	totalGaps := gap * (len(cols) - 1)
+	remaining := availW - totalNatural - totalFixed - totalGaps
 
 	if remaining > 0 && totalNatural > 0 {
-		for i, w := range widths {
-			extra := remaining * w / totalNatural
-			widths[i] += extra
+		for _, i := range cols {
+			if !fixed[i] {
+				widths[i] += remaining * widths[i] / totalNatural
+			}
 		}
 	}
 
+	if layout != nil {
+		if layout.rendered == nil {
+			layout.rendered = make(map[string]int, nCols)
+		}
+		for _, i := range cols {
+			layout.rendered[op.AutoTableNames[i]] = widths[i]
+		}
+	}
+
+	// place columns -- frozen ones stay put, the rest scroll sideways,
+	// keeping the selected column in view
+	frozen, off := 0, 0
+	if hs := op.AutoTableHScroll; hs != nil {
+		frozen = min(hs.frozen, len(cols))
+		hs.clamp(len(cols) - frozen)
+		if layout != nil && layout.active >= frozen {
+			hs.offset = min(hs.offset, layout.active-frozen)
+			for hs.offset < layout.active-frozen {
+				xs, _ := autoTablePlace(cols, widths, frozen, hs.offset, gap, availW)
+				if xs[layout.active] >= 0 && xs[layout.active]+widths[cols[layout.active]] <= availW {
+					break
+				}
+				hs.offset++
+			}
+		}
+		off = hs.offset
+	}
+	xs, sepX := autoTablePlace(cols, widths, frozen, off, gap, availW)
+
 	hdrStyle := t.effectiveStyle(op.AutoTableHdrStyle)
 	y := int(absY)
+	if sepX >= 0 {
+		buf.Set(int(absX)+sepX, y, Cell{Rune: '│', Style: hdrStyle})
+	}
 
 	// header row
 	x := int(absX)
 	jumpActive := op.AutoTableSort != nil && t.app != nil && t.app.JumpModeActive()
 
-	for i, h := range op.AutoTableHeaders {
-		text := applyTransform(h, hdrStyle.Transform)
+	for pos, i := range cols {
+		if xs[pos] < 0 {
+			continue
+		}
+		x = int(absX) + xs[pos]
+		text := applyTransform(op.AutoTableHeaders[i], hdrStyle.Transform)
 		if op.AutoTableSort != nil && op.AutoTableSort.col == i {
 			if op.AutoTableSort.asc {
 				text += " ▲"
@@ -5112,7 +5600,11 @@ func (t *Template) renderAutoTable(buf *Buffer, op *Op, absX, absY, maxW int16)
 		if cfg := op.AutoTableColCfgs[i]; cfg != nil {
 			hdrAlign = cfg.align
 		}
-		t.writeTableCell(buf, x, y, text, widths[i], hdrAlign, hdrStyle)
+		colStyle := hdrStyle
+		if layout != nil && pos == layout.active {
+			colStyle.Attr |= AttrInverse
+		}
+		t.writeTableCell(buf, x, y, text, min(widths[i], availW-xs[pos]), hdrAlign, colStyle)
 
 		// register column header as a jump target for sorting
 		if jumpActive {
@@ -5129,140 +5621,164 @@ func (t *Template) renderAutoTable(buf *Buffer, op *Op, absX, absY, maxW int16)
 				}
 				autoTableSort(slicePtr, fieldIdx, ss.asc)
 			}, Style{})
+			t.drawJumpLabel(buf, x, y)
+		}
+	}
+	y++
 
-			// draw jump label if assigned (second render pass)
-			jm := t.app.JumpMode()
-			for j := len(jm.Targets) - 1; j >= 0; j-- {
-				target := &jm.Targets[j]
-				if target.X == int16(x) && target.Y == int16(y) && target.Label != "" {
-					style := t.app.JumpStyle().LabelStyle
-					for k, r := range target.Label {
-						buf.Set(x+k, y, Cell{Rune: r, Style: style})
-					}
-					break
-				}
+	// drawLine draws display line n -- a data row, group header or footer
+	drawLine := func(dst *Buffer, x0, y, n int) {
+		line := lines[n]
+		rowStyle := t.effectiveStyle(op.AutoTableRowStyle)
+
+		switch line.kind {
+		case autoTableGroupLine:
+			g := groups[line.group]
+			mark := "▼ "
+			if op.AutoTableGroups.isCollapsed(g.key) {
+				mark = "▶ "
 			}
+			t.writeTableCell(dst, x0, y, fmt.Sprintf("%s%s (%d)", mark, g.key, len(g.rows)), availW, AlignLeft, hdrStyle)
+			return
+		case autoTableFooterLine:
+			rowStyle = hdrStyle
 		}
 
-		x += widths[i] + gap
-	}
-	y++
+		isAlt := line.kind == autoTableDataLine && op.AutoTableAltStyle != nil && line.alt
+		if isAlt {
+			rowStyle = t.effectiveStyle(*op.AutoTableAltStyle)
+		}
 
-	// data rows -- when scrolling is enabled, render all rows to an internal
-	// buffer and blit only the visible viewport to the screen buffer.
-	sc := op.AutoTableScroll
-	if sc != nil {
-		sc.clamp(nRows)
+		// fill entire row background for alt rows
+		if isAlt && op.AutoTableFill.Mode != ColorDefault {
+			for fx := x0; fx < x0+availW; fx++ {
+				dst.Set(fx, y, Cell{Rune: ' ', Style: Style{BG: op.AutoTableFill}})
+			}
+		}
 
-		// allocate or resize internal buffer (width = availW, height = nRows)
-		if sc.buf == nil || sc.bufW != availW || sc.buf.Height() < nRows {
-			sc.buf = NewBuffer(availW, nRows)
-			sc.bufW = availW
-		} else {
-			sc.buf.Clear()
+		if sepX >= 0 {
+			dst.Set(x0+sepX, y, Cell{Rune: '│', Style: rowStyle})
 		}
 
-		// render all data rows into internal buffer at y=0..nRows-1
-		for i := 0; i < nRows; i++ {
-			elem := rv.Index(i)
+		var elem reflect.Value
+		if line.kind == autoTableDataLine {
+			elem = rv.Index(line.row)
 			if elem.Kind() == reflect.Ptr {
 				elem = elem.Elem()
 			}
-
-			rowStyle := t.effectiveStyle(op.AutoTableRowStyle)
-			isAlt := op.AutoTableAltStyle != nil && i%2 == 1
-			if isAlt {
-				rowStyle = t.effectiveStyle(*op.AutoTableAltStyle)
+		}
+		for pos, j := range cols {
+			if xs[pos] < 0 {
+				continue
 			}
-
-			if isAlt && op.AutoTableFill.Mode != ColorDefault {
-				for fx := 0; fx < availW; fx++ {
-					sc.buf.Set(fx, i, Cell{Rune: ' ', Style: Style{BG: op.AutoTableFill}})
-				}
+			cfg := op.AutoTableColCfgs[j]
+			cellStyle := rowStyle
+			var str string
+			if line.kind == autoTableFooterLine {
+				str = groups[line.group].footer[j]
+			} else {
+				val := elem.Field(op.AutoTableFields[j]).Interface()
+				str = autoTableCellText(cfg, val)
+				cellStyle = cfg.cellStyle(rowStyle, val, elem)
 			}
 
-			bx := 0
-			for j, fi := range op.AutoTableFields {
-				val := elem.Field(fi).Interface()
-				cfg := op.AutoTableColCfgs[j]
+			cellAlign := AlignLeft
+			if cfg != nil {
+				cellAlign = cfg.align
+			}
 
-				var str string
-				if cfg != nil && cfg.format != nil {
-					str = cfg.format(val)
-				} else {
-					str = fmt.Sprintf("%v", val)
-				}
+			t.writeTableCell(dst, x0+xs[pos], y, str, min(widths[j], availW-xs[pos]), cellAlign, cellStyle)
+		}
+	}
 
-				cellStyle := rowStyle
-				if cfg != nil && cfg.style != nil {
-					cellStyle = cfg.style(val)
-				}
+	// data rows -- when scrolling is enabled, render all rows to an internal
+	// buffer and blit only the visible viewport to the screen buffer.
+	nLines := len(lines)
+	first, visH := 0, nLines
+	sc := op.AutoTableScroll
+	if sc != nil {
+		sc.lines = nLines
+		sc.clamp(nLines)
 
-				cellAlign := AlignLeft
-				if cfg != nil {
-					cellAlign = cfg.align
-				}
+		// allocate or resize internal buffer (width = availW, height = nLines)
+		if sc.buf == nil || sc.bufW != availW || sc.buf.Height() < nLines {
+			sc.buf = NewBuffer(availW, nLines)
+			sc.bufW = availW
+		} else {
+			sc.buf.Clear()
+		}
 
-				t.writeTableCell(sc.buf, bx, i, str, widths[j], cellAlign, cellStyle)
-				bx += widths[j] + gap
-			}
+		// render all lines into internal buffer at y=0..nLines-1
+		for n := range lines {
+			drawLine(sc.buf, 0, n, n)
 		}
 
 		// blit visible window from internal buffer to screen
-		visH := sc.maxVisible
-		if visH > nRows {
-			visH = nRows
-		}
+		first, visH = sc.offset, min(sc.maxVisible, nLines)
 		buf.Blit(sc.buf, 0, sc.offset, int(absX), y, availW, visH)
 	} else {
 		// no scroll -- render directly (backwards compatible)
-		for i := 0; i < nRows; i++ {
-			elem := rv.Index(i)
-			if elem.Kind() == reflect.Ptr {
-				elem = elem.Elem()
-			}
+		for n := range lines {
+			drawLine(buf, int(absX), y+n, n)
+		}
+	}
 
-			rowStyle := t.effectiveStyle(op.AutoTableRowStyle)
-			isAlt := op.AutoTableAltStyle != nil && i%2 == 1
-			if isAlt {
-				rowStyle = t.effectiveStyle(*op.AutoTableAltStyle)
+	// group headers collapse and expand through jump labels
+	if gs := op.AutoTableGroups; gs != nil && t.app != nil && t.app.JumpModeActive() {
+		for n := first; n < first+visH && n < nLines; n++ {
+			if lines[n].kind != autoTableGroupLine {
+				continue
 			}
+			key := groups[lines[n].group].key
+			t.app.AddJumpTarget(int16(absX), int16(y+n-first), func() {
+				gs.collapsed[key] = !gs.collapsed[key]
+			}, Style{})
+			t.drawJumpLabel(buf, int(absX), y+n-first)
+		}
+	}
+}
 
-			// fill entire row background for alt rows
-			if isAlt && op.AutoTableFill.Mode != ColorDefault {
-				for fx := int(absX); fx < int(maxW); fx++ {
-					buf.Set(fx, y, Cell{Rune: ' ', Style: Style{BG: op.AutoTableFill}})
-				}
+// drawJumpLabel draws the label of the jump target at x,y, if one has been
+// assigned yet (second render pass).
+func (t *Template) drawJumpLabel(buf *Buffer, x, y int) {
+	jm := t.app.JumpMode()
+	for j := len(jm.Targets) - 1; j >= 0; j-- {
+		target := &jm.Targets[j]
+		if target.X == int16(x) && target.Y == int16(y) && target.Label != "" {
+			style := t.app.JumpStyle().LabelStyle
+			for k, r := range target.Label {
+				buf.Set(x+k, y, Cell{Rune: r, Style: style})
 			}
+			return
+		}
+	}
+}
 
-			x = int(absX)
-			for j, fi := range op.AutoTableFields {
-				val := elem.Field(fi).Interface()
-				cfg := op.AutoTableColCfgs[j]
-
-				var str string
-				if cfg != nil && cfg.format != nil {
-					str = cfg.format(val)
-				} else {
-					str = fmt.Sprintf("%v", val)
-				}
-
-				cellStyle := rowStyle
-				if cfg != nil && cfg.style != nil {
-					cellStyle = cfg.style(val)
-				}
-
-				cellAlign := AlignLeft
-				if cfg != nil {
-					cellAlign = cfg.align
-				}
-
-				t.writeTableCell(buf, x, y, str, widths[j], cellAlign, cellStyle)
-				x += widths[j] + gap
-			}
-			y++
+// autoTablePlace returns the x offset of each displayed column (-1 when
+// scrolled off or past the right edge) and of the rule after the frozen
+// columns (-1 when nothing is frozen). The first off scrollable columns are
+// skipped.
+func autoTablePlace(cols, widths []int, frozen, off, gap, availW int) (xs []int, sepX int) {
+	xs = make([]int, len(cols))
+	sepX = -1
+	x := 0
+	for pos, i := range cols {
+		if pos >= frozen && pos < frozen+off {
+			xs[pos] = -1
+			continue
+		}
+		if frozen > 0 && pos == frozen+off && x < availW {
+			sepX = x
+			x += 1 + gap
+		}
+		if x >= availW {
+			xs[pos] = -1
+			continue
 		}
+		xs[pos] = x
+		x += widths[i] + gap
 	}
+	return xs, sepX
 }
 
 // Height returns the computed height after layout.
@@ -5281,6 +5797,15 @@ func (t *Template) Height() int16 {
 	return totalH
 }
 
+// naturalHeight lays the template out at width screenW with nothing to
+// grow into, and returns the height its content takes.
+func (t *Template) naturalHeight(screenW int16) int16 {
+	t.drainLive()
+	t.distributeWidths(screenW, nil)
+	t.layout(0)
+	return t.Height()
+}
+
 // DebugDump prints the template's op tree for debugging layout issues.
 func (t *Template) DebugDump(prefix string) {
 	fmt.Fprintf(os.Stderr, "%s=== Template Debug (%d ops) ===\n", prefix, len(t.ops))
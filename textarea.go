@@ -0,0 +1,378 @@
+package glyph
+
+import "github.com/kungfusheep/riffkey"
+
+// TextAreaC is a multi-line text editor backed by an EditBuffer.
+// It supports multiple cursors: every keystroke applies at each cursor,
+// secondary cursors are drawn with CursorStyle and the primary cursor is
+// shown as the terminal cursor.
+//
+//	buf := NewEditBuffer("")
+//	TextArea(buf).Bind().BindAddCursor("<C-Up>", "<C-Down>").BindNextMatch("<C-d>")
+type TextAreaC struct {
+	buf   *EditBuffer
+	layer *Layer
+
+	tabWidth       int
+	indentUnit     string
+	style          Style
+	cursorStyle    Style
+	selectionStyle Style
+
+	grow   float32
+	height int16
+	margin [4]int16
+
+	declaredBindings []binding
+	declaredKeys     func(riffkey.Key) bool
+	digraphs         bool
+
+	// render state
+	scrollX     int
+	lastVersion uint64
+	lastWidth   int
+	lastHeight  int
+}
+
+// TextArea creates a multi-line editor for buf.
+func TextArea(buf *EditBuffer) *TextAreaC {
+	ta := &TextAreaC{
+		buf:            buf,
+		layer:          NewLayer(),
+		tabWidth:       4,
+		indentUnit:     "\t",
+		cursorStyle:    Style{Attr: AttrInverse},
+		selectionStyle: Style{Attr: AttrInverse},
+	}
+	ta.layer.AlwaysRender = true
+	ta.layer.Render = ta.sync
+	return ta
+}
+
+// Ref provides access to the component for external references.
+func (ta *TextAreaC) Ref(f func(*TextAreaC)) *TextAreaC { f(ta); return ta }
+
+// Buffer returns the underlying edit buffer.
+func (ta *TextAreaC) Buffer() *EditBuffer { return ta.buf }
+
+// Layer returns the underlying layer for external scroll wiring.
+func (ta *TextAreaC) Layer() *Layer { return ta.layer }
+
+// Style sets the text style.
+func (ta *TextAreaC) Style(s Style) *TextAreaC {
+	ta.style = s
+	return ta
+}
+
+// CursorStyle sets the style used to draw secondary cursors.
+func (ta *TextAreaC) CursorStyle(s Style) *TextAreaC {
+	ta.cursorStyle = s
+	return ta
+}
+
+// SelectionStyle sets the style used to highlight selected text.
+func (ta *TextAreaC) SelectionStyle(s Style) *TextAreaC {
+	ta.selectionStyle = s
+	return ta
+}
+
+// TabWidth sets the display width of a tab stop. Default is 4.
+func (ta *TextAreaC) TabWidth(w int) *TextAreaC {
+	ta.tabWidth = max(w, 1)
+	return ta
+}
+
+// IndentUnit sets the text added by one indent step. Default is a tab.
+func (ta *TextAreaC) IndentUnit(s string) *TextAreaC {
+	ta.indentUnit = s
+	return ta
+}
+
+// AutoIndent sets the indentation provider for new lines and pasted blocks.
+//
+//	TextArea(buf).AutoIndent(BlockIndent("\t", "{[(", "}])"))
+func (ta *TextAreaC) AutoIndent(fn IndentFunc) *TextAreaC {
+	ta.buf.SetIndent(fn)
+	return ta
+}
+
+// AutoPair enables auto-closing brackets and quotes. With no arguments
+// DefaultPairs are used.
+func (ta *TextAreaC) AutoPair(pairs ...string) *TextAreaC {
+	if len(pairs) == 0 {
+		pairs = DefaultPairs
+	}
+	ta.buf.SetPairs(pairs...)
+	return ta
+}
+
+// Grow sets the flex grow factor.
+func (ta *TextAreaC) Grow(g float32) *TextAreaC {
+	ta.grow = g
+	return ta
+}
+
+// Height sets a fixed viewport height.
+func (ta *TextAreaC) Height(h int16) *TextAreaC {
+	ta.height = h
+	return ta
+}
+
+// Margin sets uniform margin on all sides.
+func (ta *TextAreaC) Margin(all int16) *TextAreaC {
+	ta.margin = [4]int16{all, all, all, all}
+	return ta
+}
+
+// MarginVH sets vertical and horizontal margin.
+func (ta *TextAreaC) MarginVH(v, h int16) *TextAreaC {
+	ta.margin = [4]int16{v, h, v, h}
+	return ta
+}
+
+// MarginTRBL sets individual margins for top, right, bottom, left.
+func (ta *TextAreaC) MarginTRBL(t, r, b, l int16) *TextAreaC {
+	ta.margin = [4]int16{t, r, b, l}
+	return ta
+}
+
+// Bind routes unmatched keys (typing, arrows, backspace...) to this text area.
+func (ta *TextAreaC) Bind() *TextAreaC {
+	ta.declaredKeys = ta.HandleKey
+	return ta
+}
+
+// Digraphs lets Ctrl-K and two characters type a symbol, such as "->"
+// for → or "a*" for α; see Digraph.
+func (ta *TextAreaC) Digraphs() *TextAreaC {
+	ta.digraphs = true
+	return ta
+}
+
+// BindAddCursor registers keys that add a cursor on the line above or below.
+func (ta *TextAreaC) BindAddCursor(above, below string) *TextAreaC {
+	ta.declaredBindings = append(ta.declaredBindings,
+		binding{pattern: above, handler: func() { ta.buf.AddCursorAbove() }},
+		binding{pattern: below, handler: func() { ta.buf.AddCursorBelow() }},
+	)
+	return ta
+}
+
+// BindNextMatch registers a key that selects the word under the cursor, then
+// adds a cursor at each next occurrence on repeated presses.
+func (ta *TextAreaC) BindNextMatch(key string) *TextAreaC {
+	ta.declaredBindings = append(ta.declaredBindings,
+		binding{pattern: key, handler: func() { ta.buf.AddCursorAtNextMatch() }},
+	)
+	return ta
+}
+
+// BindUndo registers keys for undo and redo.
+func (ta *TextAreaC) BindUndo(undo, redo string) *TextAreaC {
+	ta.declaredBindings = append(ta.declaredBindings,
+		binding{pattern: undo, handler: func() { ta.buf.Undo() }},
+		binding{pattern: redo, handler: func() { ta.buf.Redo() }},
+	)
+	return ta
+}
+
+// BindIndent registers keys that indent or dedent every selected line.
+func (ta *TextAreaC) BindIndent(indent, dedent string) *TextAreaC {
+	ta.declaredBindings = append(ta.declaredBindings,
+		binding{pattern: indent, handler: func() { ta.eachSelectedLines(func(r LineRange) { ta.buf.IndentLines(r.From, r.To, ta.indentUnit) }) }},
+		binding{pattern: dedent, handler: func() { ta.eachSelectedLines(func(r LineRange) { ta.buf.DedentLines(r.From, r.To, ta.indentUnit) }) }},
+	)
+	return ta
+}
+
+// BindComment registers a key that toggles line comments on every selected
+// line using prefix, e.g. "// " or "# ".
+func (ta *TextAreaC) BindComment(key, prefix string) *TextAreaC {
+	ta.declaredBindings = append(ta.declaredBindings,
+		binding{pattern: key, handler: func() { ta.eachSelectedLines(func(r LineRange) { ta.buf.ToggleComment(r.From, r.To, prefix) }) }},
+	)
+	return ta
+}
+
+// eachSelectedLines runs fn over each selected line range as one undo step.
+func (ta *TextAreaC) eachSelectedLines(fn func(LineRange)) {
+	ta.buf.BeginUndoGroup()
+	for _, r := range ta.buf.SelectedLines() {
+		fn(r)
+	}
+	ta.buf.EndUndoGroup()
+}
+
+func (ta *TextAreaC) bindings() []binding { return ta.declaredBindings }
+
+func (ta *TextAreaC) keyHandler() func(riffkey.Key) bool {
+	if ta.digraphs && ta.declaredKeys != nil {
+		return digraphKeys(ta.declaredKeys)
+	}
+	return ta.declaredKeys
+}
+
+// HandleKey applies a key press to the buffer. Returns true if handled.
+// Escape collapses multiple cursors and is only consumed when it did so.
+func (ta *TextAreaC) HandleKey(k riffkey.Key) bool {
+	b := ta.buf
+	shift := k.Mod&riffkey.ModShift != 0
+	switch {
+	case k.IsPaste():
+		b.Paste(k.Paste)
+	case k.Rune != 0 && (k.Mod == riffkey.ModNone || k.Mod == riffkey.ModShift):
+		b.TypeRune(k.Rune)
+	case k.Special == riffkey.SpecialSpace:
+		b.Insert(" ")
+	case k.Special == riffkey.SpecialTab && k.Mod == riffkey.ModNone:
+		b.Insert("\t")
+	case k.Special == riffkey.SpecialEnter:
+		b.InsertNewline()
+	case k.Special == riffkey.SpecialBackspace:
+		b.Backspace()
+	case k.Special == riffkey.SpecialDelete:
+		b.Delete()
+	case k.Special == riffkey.SpecialLeft:
+		b.MoveLeft(shift)
+	case k.Special == riffkey.SpecialRight:
+		b.MoveRight(shift)
+	case k.Special == riffkey.SpecialUp:
+		b.MoveUp(shift)
+	case k.Special == riffkey.SpecialDown:
+		b.MoveDown(shift)
+	case k.Special == riffkey.SpecialHome:
+		b.MoveLineStart(shift)
+	case k.Special == riffkey.SpecialEnd:
+		b.MoveLineEnd(shift)
+	case k.Special == riffkey.SpecialEscape:
+		return b.ClearSecondaryCursors()
+	default:
+		return false
+	}
+	return true
+}
+
+// displayCol returns the screen column of byte offset col in line.
+func (ta *TextAreaC) displayCol(line string, col int) int {
+	x := 0
+	for i, r := range line {
+		if i >= col {
+			break
+		}
+		if r == '\t' {
+			x += ta.tabWidth - x%ta.tabWidth
+		} else {
+			x++
+		}
+	}
+	return x
+}
+
+func (ta *TextAreaC) sync() {
+	w := ta.layer.ViewportWidth()
+	h := ta.layer.ViewportHeight()
+	if w <= 0 {
+		return
+	}
+	if ta.buf.Version() == ta.lastVersion && w == ta.lastWidth && h == ta.lastHeight && ta.layer.Buffer() != nil {
+		return
+	}
+	ta.lastVersion = ta.buf.Version()
+	ta.lastWidth = w
+	ta.lastHeight = h
+
+	lines := ta.buf.Lines()
+	cur := ta.buf.Cursor()
+	curX := ta.displayCol(lines[cur.Line], cur.Col)
+
+	// keep the primary cursor in view horizontally
+	if curX < ta.scrollX {
+		ta.scrollX = curX
+	} else if curX >= ta.scrollX+w {
+		ta.scrollX = curX - w + 1
+	}
+
+	out := NewBuffer(w, max(len(lines), h))
+	for y, line := range lines {
+		ta.writeLine(out, y, line, w)
+	}
+
+	// selections and secondary cursors
+	primary := ta.buf.PrimaryIndex()
+	for i, s := range ta.buf.Selections() {
+		if !s.Empty() {
+			start, end := s.Range()
+			for y := start.Line; y <= end.Line; y++ {
+				from, to := 0, len(lines[y])+1 // +1 highlights the newline
+				if y == start.Line {
+					from = start.Col
+				}
+				if y == end.Line {
+					to = end.Col
+				}
+				x0 := ta.displayCol(lines[y], from) - ta.scrollX
+				x1 := ta.displayCol(lines[y], min(to, len(lines[y])))
+				if to > len(lines[y]) {
+					x1++
+				}
+				x1 -= ta.scrollX
+				for x := max(x0, 0); x < min(x1, w); x++ {
+					c := out.Get(x, y)
+					out.SetFast(x, y, Cell{Rune: c.Rune, Style: ta.selectionStyle})
+				}
+			}
+		}
+		if i != primary {
+			x := ta.displayCol(lines[s.Head.Line], s.Head.Col) - ta.scrollX
+			c := out.Get(x, s.Head.Line)
+			out.SetFast(x, s.Head.Line, Cell{Rune: c.Rune, Style: ta.cursorStyle})
+		}
+	}
+
+	// SetBuffer resets scroll, so carry it across and keep the cursor visible
+	scrollY := ta.layer.ScrollY()
+	ta.layer.SetBuffer(out)
+	if cur.Line < scrollY {
+		scrollY = cur.Line
+	} else if h > 0 && cur.Line >= scrollY+h {
+		scrollY = cur.Line - h + 1
+	}
+	ta.layer.ScrollTo(scrollY)
+	ta.layer.SetCursor(curX-ta.scrollX, cur.Line)
+	ta.layer.ShowCursor()
+}
+
+// writeLine draws one buffer line with tab expansion and horizontal scroll.
+func (ta *TextAreaC) writeLine(out *Buffer, y int, line string, w int) {
+	x := 0
+	for _, r := range line {
+		n := 1
+		if r == '\t' {
+			n = ta.tabWidth - x%ta.tabWidth
+			r = ' '
+		}
+		for ; n > 0; n-- {
+			if sx := x - ta.scrollX; sx >= w {
+				return
+			} else if sx >= 0 {
+				out.SetFast(sx, y, Cell{Rune: r, Style: ta.style})
+			}
+			x++
+		}
+	}
+	// pad the rest of the line so the style covers the full width
+	for sx := max(x-ta.scrollX, 0); sx < w; sx++ {
+		out.SetFast(sx, y, Cell{Rune: ' ', Style: ta.style})
+	}
+}
+
+func (t *Template) compileTextAreaC(v *TextAreaC, parent int16, depth int) int16 {
+	layerView := LayerView(v.layer).Grow(v.grow)
+	if v.height > 0 {
+		layerView = layerView.ViewHeight(v.height)
+	}
+	if v.margin != [4]int16{} {
+		layerView = layerView.MarginTRBL(v.margin[0], v.margin[1], v.margin[2], v.margin[3])
+	}
+	return t.compileLayerViewC(layerView, parent, depth)
+}
@@ -0,0 +1,200 @@
+package glyph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+func TestEditBufferInsertAtMultipleCursors(t *testing.T) {
+	b := NewEditBuffer("one\ntwo\nthree")
+	b.AddCursorBelow()
+	b.AddCursorBelow()
+	if b.CursorCount() != 3 {
+		t.Fatalf("expected 3 cursors, got %d", b.CursorCount())
+	}
+
+	b.Insert("> ")
+	if got := b.Text(); got != "> one\n> two\n> three" {
+		t.Errorf("unexpected text %q", got)
+	}
+	for i, s := range b.Selections() {
+		if s.Head != (TextPos{Line: i, Col: 2}) {
+			t.Errorf("cursor %d at %+v, want line %d col 2", i, s.Head, i)
+		}
+	}
+}
+
+func TestEditBufferSameLineCursorsShift(t *testing.T) {
+	b := NewEditBuffer("abc")
+	b.SetSelections([]Selection{
+		{Anchor: TextPos{Col: 1}, Head: TextPos{Col: 1}},
+		{Anchor: TextPos{Col: 2}, Head: TextPos{Col: 2}},
+	})
+	b.Insert("XY")
+	if got := b.Text(); got != "aXYbXYc" {
+		t.Errorf("expected aXYbXYc, got %q", got)
+	}
+
+	b.Backspace()
+	if got := b.Text(); got != "aXbXc" {
+		t.Errorf("expected aXbXc after backspace, got %q", got)
+	}
+}
+
+func TestEditBufferBackspaceJoinsLines(t *testing.T) {
+	b := NewEditBuffer("ab\ncd")
+	b.SetCursor(TextPos{Line: 1})
+	b.Backspace()
+	if got := b.Text(); got != "abcd" {
+		t.Errorf("expected abcd, got %q", got)
+	}
+	if b.Cursor() != (TextPos{Col: 2}) {
+		t.Errorf("expected cursor at col 2, got %+v", b.Cursor())
+	}
+}
+
+func TestEditBufferCursorsMerge(t *testing.T) {
+	b := NewEditBuffer("ab")
+	b.SetCursor(TextPos{Col: 2})
+	b.AddCursor(TextPos{Col: 1})
+	b.Backspace()
+	b.Backspace()
+	if b.CursorCount() != 1 {
+		t.Errorf("expected cursors to merge at line start, got %d", b.CursorCount())
+	}
+	if b.Text() != "" {
+		t.Errorf("expected empty text, got %q", b.Text())
+	}
+}
+
+func TestEditBufferNextMatch(t *testing.T) {
+	b := NewEditBuffer("foo bar foo\nfoo")
+	b.SetCursor(TextPos{Col: 1})
+
+	// first press selects the word under the cursor
+	if !b.AddCursorAtNextMatch() {
+		t.Fatal("expected word selection")
+	}
+	if got := b.SelectedText(); len(got) != 1 || got[0] != "foo" {
+		t.Fatalf("expected [foo], got %v", got)
+	}
+
+	b.AddCursorAtNextMatch()
+	b.AddCursorAtNextMatch()
+	if b.CursorCount() != 3 {
+		t.Fatalf("expected 3 selections, got %d", b.CursorCount())
+	}
+	if b.AddCursorAtNextMatch() {
+		t.Error("expected no further matches")
+	}
+
+	b.Insert("baz")
+	if got := b.Text(); got != "baz bar baz\nbaz" {
+		t.Errorf("unexpected text %q", got)
+	}
+}
+
+func TestEditBufferUndoCoalescesTyping(t *testing.T) {
+	b := NewEditBuffer("")
+	for _, r := range "hello" {
+		b.Insert(string(r))
+	}
+	b.InsertNewline()
+	b.Insert("x")
+
+	b.Undo()
+	if got := b.Text(); got != "hello\n" {
+		t.Errorf("after first undo got %q", got)
+	}
+	b.Undo()
+	b.Undo()
+	if got := b.Text(); got != "" {
+		t.Errorf("after undoing everything got %q", got)
+	}
+	b.Redo()
+	if got := b.Text(); got != "hello" {
+		t.Errorf("after redo got %q", got)
+	}
+}
+
+func TestEditBufferUndoGroup(t *testing.T) {
+	b := NewEditBuffer("a\nb")
+	b.BeginUndoGroup()
+	b.SetCursor(TextPos{})
+	b.InsertNewline()
+	b.SetCursor(TextPos{Line: 2})
+	b.InsertNewline()
+	b.EndUndoGroup()
+
+	b.Undo()
+	if got := b.Text(); got != "a\nb" {
+		t.Errorf("expected group undone in one step, got %q", got)
+	}
+}
+
+func TestEditBufferMoveUpDownKeepsColumn(t *testing.T) {
+	b := NewEditBuffer("héllo\nab\nworld")
+	b.SetCursor(TextPos{Col: len("hél")})
+	b.MoveDown(false)
+	if b.Cursor() != (TextPos{Line: 1, Col: 2}) {
+		t.Errorf("expected clamp to end of short line, got %+v", b.Cursor())
+	}
+	b.MoveUp(false)
+	if b.Cursor() != (TextPos{Col: len("hé")}) {
+		t.Errorf("expected rune column 2 on line 0, got %+v", b.Cursor())
+	}
+}
+
+func TestTextAreaRendersSecondaryCursors(t *testing.T) {
+	b := NewEditBuffer("abc\ndef")
+	b.AddCursorBelow()
+
+	ta := TextArea(b).Height(2)
+	tmpl := Build(VBox(ta))
+	buf := NewBuffer(10, 2)
+	tmpl.Execute(buf, 10, 2)
+
+	if got := buf.GetLine(0); !strings.HasPrefix(got, "abc") {
+		t.Errorf("expected line 0 to start with abc, got %q", got)
+	}
+	// the newest cursor is primary and shown as the terminal cursor,
+	// the older one is drawn inverse
+	if c := buf.Get(0, 1); c.Style.Attr.Has(AttrInverse) {
+		t.Error("primary cursor cell should not be drawn inverse")
+	}
+	if c := buf.Get(0, 0); !c.Style.Attr.Has(AttrInverse) {
+		t.Error("secondary cursor cell should be drawn inverse")
+	}
+	if cur := ta.Layer().Cursor(); !cur.Visible || cur.X != 0 || cur.Y != 1 {
+		t.Errorf("expected primary layer cursor at 0,1, got %+v", cur)
+	}
+}
+
+func TestTextAreaHandleKey(t *testing.T) {
+	b := NewEditBuffer("")
+	ta := TextArea(b)
+	for _, r := range "hi" {
+		ta.HandleKey(riffkey.Key{Rune: r})
+	}
+	ta.HandleKey(riffkey.Key{Special: riffkey.SpecialEnter})
+	ta.HandleKey(riffkey.Key{Rune: '!'})
+	if got := b.Text(); got != "hi\n!" {
+		t.Errorf("unexpected text %q", got)
+	}
+	if ta.HandleKey(riffkey.Key{Special: riffkey.SpecialEscape}) {
+		t.Error("escape with a single cursor should not be consumed")
+	}
+}
+
+func TestTextAreaBindWiresKeyHandler(t *testing.T) {
+	ta := TextArea(NewEditBuffer("")).Bind().BindAddCursor("<C-k>", "<C-j>")
+	tmpl := Build(VBox(ta))
+	if tmpl.pendingKeyHandler == nil {
+		t.Error("expected key handler to be collected")
+	}
+	if len(tmpl.pendingBindings) != 2 {
+		t.Errorf("expected 2 bindings, got %d", len(tmpl.pendingBindings))
+	}
+}
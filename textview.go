@@ -1,6 +1,9 @@
 package glyph
 
-import "unicode/utf8"
+import (
+	"strings"
+	"unicode/utf8"
+)
 
 // TextViewC displays multi-line text with word wrapping in a layer-backed viewport.
 // Content is re-wrapped only when the text or viewport width changes.
@@ -11,6 +14,8 @@ type TextViewC struct {
 	margin           [4]int16
 	lastContent      string
 	lastWidth        int
+	noWrap           bool
+	hScrollbar       bool
 	declaredBindings []binding
 }
 
@@ -45,6 +50,36 @@ func (tv *TextViewC) MarginTRBL(t, r, b, l int16) *TextViewC {
 	return tv
 }
 
+// NoWrap keeps long lines whole, scrolling sideways instead of wrapping,
+// for code and wide tables.
+func (tv *TextViewC) NoWrap() *TextViewC {
+	tv.noWrap = true
+	return tv
+}
+
+// HScrollbar reserves the bottom row for a horizontal scrollbar. Only
+// useful with NoWrap.
+func (tv *TextViewC) HScrollbar() *TextViewC {
+	tv.hScrollbar = true
+	return tv
+}
+
+// ScrollX binds the horizontal scroll offset to p, as Layer.BindScrollX.
+func (tv *TextViewC) ScrollX(p *int) *TextViewC {
+	tv.layer.BindScrollX(p)
+	return tv
+}
+
+// BindHScroll registers keys for column-by-column horizontal scrolling,
+// such as "zh" and "zl". Only useful with NoWrap.
+func (tv *TextViewC) BindHScroll(left, right string) *TextViewC {
+	tv.declaredBindings = append(tv.declaredBindings,
+		binding{pattern: left, handler: func() { tv.layer.ScrollLeft(1) }},
+		binding{pattern: right, handler: func() { tv.layer.ScrollRight(1) }},
+	)
+	return tv
+}
+
 // Layer returns the underlying layer for external scroll wiring.
 func (tv *TextViewC) Layer() *Layer { return tv.layer }
 
@@ -80,20 +115,32 @@ func (tv *TextViewC) sync() {
 	tv.lastContent = c
 	tv.lastWidth = w
 
-	lines := wrapText(c, w)
+	var lines []string
+	bw := w
+	if tv.noWrap {
+		lines = strings.Split(ExpandTabs(c, 4), "\n")
+		for _, line := range lines {
+			bw = max(bw, StringWidth(line))
+		}
+	} else {
+		lines = wrapText(c, w)
+	}
 	if len(lines) == 0 {
 		lines = []string{""}
 	}
 	h := max(len(lines), tv.layer.ViewportHeight())
-	buf := NewBuffer(w, h)
+	buf := NewBuffer(bw, h)
 	for i, line := range lines {
-		buf.WriteStringFast(0, i, line, Style{}, w)
+		buf.WriteStringFast(0, i, line, Style{}, bw)
 	}
 	tv.layer.SetBuffer(buf)
 }
 
 func (t *Template) compileTextViewC(v *TextViewC, parent int16, depth int) int16 {
 	layerView := LayerView(v.layer).Grow(v.grow)
+	if v.hScrollbar {
+		layerView = layerView.HScrollbar()
+	}
 	if v.margin != [4]int16{} {
 		layerView = layerView.MarginTRBL(v.margin[0], v.margin[1], v.margin[2], v.margin[3])
 	}
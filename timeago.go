@@ -0,0 +1,166 @@
+package glyph
+
+import (
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// ticking re-renders a time-based node when its text next changes, so it
+// stays current without the app running a goroutine of its own.
+type ticking struct {
+	update  func() // set to app.RequestRender during wiring
+	pending atomic.Bool
+	timer   Timer
+	due     time.Time
+	clock   Clock
+}
+
+func (k *ticking) updateHook() *func() { return &k.update }
+func (k *ticking) setClock(c Clock)    { k.clock = c }
+
+func (k *ticking) now() time.Time {
+	if k.clock == nil {
+		return time.Now()
+	}
+	return k.clock.Now()
+}
+
+// after asks for a render in d, unless one is already due sooner. It is
+// only called from render.
+func (k *ticking) after(d time.Duration) {
+	if k.update == nil {
+		return
+	}
+	d = max(d, transitionFrame)
+	due := k.now().Add(d)
+	if k.pending.Load() {
+		if !due.Before(k.due) {
+			return
+		}
+		k.timer.Stop()
+	}
+	clock := k.clock
+	if clock == nil {
+		clock = SystemClock
+	}
+	update := k.update
+	k.pending.Store(true)
+	k.due = due
+	k.timer = clock.AfterFunc(d, func() {
+		k.pending.Store(false)
+		update()
+	})
+}
+
+// TimeAgoC shows how long ago a time was, "2m ago" or "just now", and
+// re-renders itself each time the text would change. Times in the future
+// read "in 5m".
+//
+//	TimeAgo(&event.At).Granularity(time.Minute).FG(BrightBlack)
+type TimeAgoC struct {
+	ticking
+	at          *time.Time
+	granularity time.Duration
+	style       Style
+}
+
+// TimeAgo creates a relative time display bound to t.
+func TimeAgo(t *time.Time) *TimeAgoC {
+	return &TimeAgoC{at: t, granularity: time.Second}
+}
+
+// Granularity sets the smallest unit shown; anything closer than that is
+// "just now". One second by default.
+func (c *TimeAgoC) Granularity(d time.Duration) *TimeAgoC {
+	c.granularity = d
+	return c
+}
+
+// Style sets the text style.
+func (c *TimeAgoC) Style(s Style) *TimeAgoC {
+	c.style = s
+	return c
+}
+
+// FG sets the foreground colour.
+func (c *TimeAgoC) FG(col Color) *TimeAgoC {
+	c.style.FG = col
+	return c
+}
+
+func (t *Template) compileTimeAgoC(c *TimeAgoC, parent int16, depth int) int16 {
+	t.collectUpdateHook(c)
+	t.collectClockUser(c)
+	return t.compileCustom(Widget(c.measure, c.render), parent, depth)
+}
+
+func (c *TimeAgoC) text() (string, time.Duration) {
+	if c.at == nil || c.at.IsZero() {
+		return "", 0
+	}
+	return relativeTime(c.now().Sub(*c.at), c.granularity)
+}
+
+func (c *TimeAgoC) measure(availW int16) (w, h int16) {
+	s, _ := c.text()
+	return int16(runewidth.StringWidth(s)), 1
+}
+
+func (c *TimeAgoC) render(buf *Buffer, x, y, w, h int16) {
+	s, next := c.text()
+	buf.WriteStringFast(int(x), int(y), s, c.style, int(w))
+	if next > 0 {
+		c.after(next)
+	}
+}
+
+// relativeUnits are the units of relative time, largest first.
+var relativeUnits = []struct {
+	d    time.Duration
+	name string
+}{
+	{365 * 24 * time.Hour, "y"},
+	{30 * 24 * time.Hour, "mo"},
+	{7 * 24 * time.Hour, "w"},
+	{24 * time.Hour, "d"},
+	{time.Hour, "h"},
+	{time.Minute, "m"},
+	{time.Second, "s"},
+}
+
+// relativeTime describes an elapsed duration (negative for the future) in
+// its largest whole unit, or "just now" within gran, along with how long
+// until the description changes.
+func relativeTime(d, gran time.Duration) (string, time.Duration) {
+	future := d < 0
+	if future {
+		d = -d
+	}
+	gran = max(gran, time.Second)
+	if d < gran {
+		if future {
+			return "just now", d + gran
+		}
+		return "just now", gran - d
+	}
+	for i, u := range relativeUnits {
+		if d < u.d {
+			continue
+		}
+		n := d / u.d
+		s := strconv.FormatInt(int64(n), 10) + u.name
+		if future {
+			// counts down: changes once we drop below n whole units
+			return "in " + s, d - n*u.d + 1
+		}
+		next := (n+1)*u.d - d
+		if i > 0 {
+			next = min(next, relativeUnits[i-1].d-d)
+		}
+		return s + " ago", next
+	}
+	return "just now", 0
+}
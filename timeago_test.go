@@ -0,0 +1,70 @@
+package glyph
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRelativeTime(t *testing.T) {
+	tests := []struct {
+		d, gran time.Duration
+		want    string
+		next    time.Duration
+	}{
+		{0, time.Second, "just now", time.Second},
+		{45 * time.Second, time.Second, "45s ago", time.Second},
+		{45 * time.Second, time.Minute, "just now", 15 * time.Second},
+		{150 * time.Second, time.Minute, "2m ago", 30 * time.Second},
+		{59*time.Minute + 30*time.Second, time.Minute, "59m ago", 30 * time.Second},
+		{26 * time.Hour, time.Second, "1d ago", 22 * time.Hour},
+		{40 * 24 * time.Hour, time.Second, "1mo ago", 20 * 24 * time.Hour},
+		{-5*time.Minute - 10*time.Second, time.Second, "in 5m", 10*time.Second + 1},
+	}
+	for _, tt := range tests {
+		got, next := relativeTime(tt.d, tt.gran)
+		if got != tt.want || next != tt.next {
+			t.Errorf("relativeTime(%v, %v) = %q, %v; want %q, %v", tt.d, tt.gran, got, next, tt.want, tt.next)
+		}
+	}
+}
+
+func TestTimeAgo(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+	at := start.Add(-90 * time.Second)
+	renders := 0
+
+	tmpl := Build(HBox(TimeAgo(&at).Granularity(time.Minute), Text("|")))
+	tmpl.SetClock(clock)
+	for _, u := range tmpl.pendingUpdates {
+		*u = func() { renders++ }
+	}
+	frame := func() string {
+		buf := NewBuffer(20, 1)
+		tmpl.Execute(buf, 20, 1)
+		return buf.GetLine(0)
+	}
+
+	if got := frame(); got != "1m ago|" {
+		t.Fatalf("line = %q", got)
+	}
+	// next change is at the two minute mark, 30s away
+	clock.Advance(29 * time.Second)
+	if renders != 0 {
+		t.Fatalf("rendered early")
+	}
+	clock.Advance(time.Second)
+	if renders != 1 || frame() != "2m ago|" {
+		t.Errorf("renders = %d line = %q", renders, frame())
+	}
+
+	// a fresh time brings the next render forward
+	at = clock.Now()
+	if got := frame(); got != "just now|" {
+		t.Errorf("fresh = %q", got)
+	}
+	clock.Advance(time.Minute)
+	if renders != 2 {
+		t.Errorf("renders = %d, want 2", renders)
+	}
+}
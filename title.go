@@ -0,0 +1,134 @@
+package glyph
+
+import "strings"
+
+// The terminal's title, icon name and desktop notifications are set with
+// OSC sequences. They're queued on the screen and sent with the next frame,
+// so they can be set from any goroutine without landing in the middle of
+// one.
+//
+//	app.SetTitle("build: running")
+//	go func() {
+//	    err := build()
+//	    app.SetTitle("build: done")
+//	    app.Notify("Build finished", summary(err))
+//	}()
+//
+// The title the terminal had is saved the first time one is set, and put
+// back when the app exits, on terminals that keep a stack of titles.
+
+// SetTitle sets the terminal's window or tab title.
+func (a *App) SetTitle(title string) *App {
+	if t, ok := a.backend.(*terminalBackend); ok {
+		t.screen.SetTitle(title)
+		a.RequestRender()
+	}
+	return a
+}
+
+// SetIconName sets the name the terminal shows for its minimised window or
+// tab, where that differs from the title.
+func (a *App) SetIconName(name string) *App {
+	if t, ok := a.backend.(*terminalBackend); ok {
+		t.screen.SetIconName(name)
+		a.RequestRender()
+	}
+	return a
+}
+
+// Notify shows a desktop notification, with OSC 9 or OSC 777 as the
+// terminal understands. It reports false, sending nothing, when the
+// terminal isn't known to show notifications (see Capabilities).
+func (a *App) Notify(title, body string) bool {
+	t, ok := a.backend.(*terminalBackend)
+	if !ok || !a.Capabilities().Notifications {
+		return false
+	}
+	t.screen.Notify(title, body, a.Capabilities().Terminal)
+	a.RequestRender()
+	return true
+}
+
+// osc777 are the terminals that take notifications as OSC 777, with a
+// title. Others take OSC 9, which has only a message.
+var osc777 = map[string]bool{
+	"foot":    true,
+	"ghostty": true,
+	"contour": true,
+	"vte":     true,
+}
+
+// SetTitle queues the window title, sent with the next frame.
+func (s *Screen) SetTitle(title string) {
+	s.queueTitle("\x1b]2;" + oscText(title) + "\x07")
+}
+
+// SetIconName queues the icon name, sent with the next frame.
+func (s *Screen) SetIconName(name string) {
+	s.queueTitle("\x1b]1;" + oscText(name) + "\x07")
+}
+
+// Notify queues a desktop notification, sent with the next frame, in the
+// form the named terminal takes (see Capabilities.Terminal).
+func (s *Screen) Notify(title, body, terminal string) {
+	var seq string
+	if osc777[terminal] {
+		seq = "\x1b]777;notify;" + strings.ReplaceAll(oscText(title), ";", ",") + ";" + oscText(body) + "\x07"
+	} else {
+		msg := oscText(body)
+		if title != "" {
+			msg = oscText(title) + ": " + msg
+		}
+		seq = "\x1b]9;" + msg + "\x07"
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending = append(s.pending, s.quirks.Wrap(seq)...)
+}
+
+// queueTitle queues a title sequence, first saving the terminal's own title
+// so it can be put back.
+func (s *Screen) queueTitle(seq string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.titleSaved {
+		s.pending = append(s.pending, "\x1b[22;0t"...)
+		s.titleSaved = true
+	}
+	s.pending = append(s.pending, seq...)
+}
+
+// writePending adds queued sequences to the output buffer. Called with mu
+// held.
+func (s *Screen) writePending() {
+	if len(s.pending) > 0 {
+		s.buf.Write(s.pending)
+		s.pending = s.pending[:0]
+	}
+}
+
+// restoreTitle puts back the title saved when the app first set one.
+func (s *Screen) restoreTitle() {
+	if s.titleSaved {
+		s.writeString("\x1b[23;0t")
+		s.titleSaved = false
+	}
+}
+
+// oscText removes control characters, which would end an OSC sequence
+// early or smuggle in another.
+func oscText(s string) string {
+	if strings.IndexFunc(s, isControl) < 0 {
+		return s
+	}
+	return strings.Map(func(r rune) rune {
+		if isControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+func isControl(r rune) bool {
+	return r < 0x20 || r == 0x7f || (r >= 0x80 && r < 0xa0)
+}
@@ -0,0 +1,76 @@
+package glyph
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTitleSentWithFrame(t *testing.T) {
+	var out bytes.Buffer
+	s := NewScreenSize(&out, 20, 4)
+	s.SetTitle("build: \x1b]2;evil\x07running")
+	s.SetIconName("build")
+	if out.Len() != 0 {
+		t.Fatalf("title written before a frame: %q", out.String())
+	}
+
+	s.Flush()
+	s.FlushBuffer()
+	want := "\x1b[22;0t\x1b]2;build: ]2;evilrunning\x07\x1b]1;build\x07"
+	if got := out.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	// the saved title is pushed only once
+	out.Reset()
+	s.SetTitle("build: done")
+	s.Flush()
+	s.FlushBuffer()
+	if got := out.String(); got != "\x1b]2;build: done\x07" {
+		t.Errorf("second title: %q", got)
+	}
+
+	out.Reset()
+	s.restoreTitle()
+	if got := out.String(); got != "\x1b[23;0t" {
+		t.Errorf("restore: %q", got)
+	}
+}
+
+func TestNotify(t *testing.T) {
+	tests := []struct {
+		terminal string
+		quirks   Quirks
+		want     string
+	}{
+		{"iterm2", Quirks{}, "\x1b]9;Build: 3 failed\x07"},
+		{"foot", Quirks{}, "\x1b]777;notify;Build;3 failed\x07"},
+		{"kitty", Quirks{Mux: MuxTmux}, "\x1bPtmux;\x1b\x1b]9;Build: 3 failed\x07\x1b\\"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.terminal, func(t *testing.T) {
+			var out bytes.Buffer
+			s := NewScreenSize(&out, 20, 4)
+			s.SetQuirks(tt.quirks)
+			s.Notify("Build", "3 failed", tt.terminal)
+			s.FlushBuffer()
+			if got := out.String(); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppNotifyUnsupported(t *testing.T) {
+	app, err := NewApp()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if app.SetCapabilities(Capabilities{Terminal: "apple"}).Notify("done", "") {
+		t.Error("notified a terminal without notifications")
+	}
+	if strings.Contains(string(app.Screen().pending), "]9;") {
+		t.Error("notification queued anyway")
+	}
+}
@@ -0,0 +1,97 @@
+package glyph
+
+import (
+	"reflect"
+	"strings"
+)
+
+// renderTrace records where each op was drawn in a frame, for the dev
+// tools that explain a screen: the width audit and the layout overlay.
+type renderTrace struct {
+	boxes []traceBox
+	depth int // how many ops are being drawn around the next one
+}
+
+// traceBox is one op as drawn: its outer rectangle, margin included, and
+// how deeply it's nested. Boxes are traced parents first.
+type traceBox struct {
+	op         *Op
+	x, y, w, h int16
+	depth      int
+}
+
+func (rt *renderTrace) add(op *Op, x, y int16, geom *Geom) {
+	rt.boxes = append(rt.boxes, traceBox{op: op, x: x, y: y, w: geom.W, h: geom.H, depth: rt.depth})
+}
+
+// enter nests the ops drawn until the returned func is called inside the
+// last one added.
+func (rt *renderTrace) enter() func() {
+	rt.depth++
+	return func() { rt.depth-- }
+}
+
+func (b traceBox) contains(x, y int) bool {
+	return x >= int(b.x) && x < int(b.x)+int(b.w) && y >= int(b.y) && y < int(b.y)+int(b.h)
+}
+
+// at returns the boxes drawn over a cell, outermost first.
+func (rt *renderTrace) at(x, y int) []traceBox {
+	var out []traceBox
+	for _, b := range rt.boxes {
+		if b.contains(x, y) {
+			out = append(out, b)
+		}
+	}
+	return out
+}
+
+// path names the components drawn over a cell, outermost first, as in
+// "VBox > HBox > Text".
+func (rt *renderTrace) path(x, y int) string {
+	var names []string
+	for _, b := range rt.at(x, y) {
+		if b.op.name != "" {
+			names = append(names, b.op.name)
+		}
+	}
+	return strings.Join(names, " > ")
+}
+
+// componentName is the name a dev tool shows for a node: the type's
+// name, without the package and the C or Node glyph's builders end in
+// when it's one of ours.
+func componentName(node any) string {
+	t := reflect.TypeOf(node)
+	if t == nil {
+		return ""
+	}
+	name := strings.TrimPrefix(t.String(), "*")
+	if short, ok := strings.CutPrefix(name, "glyph."); ok {
+		if s := strings.TrimSuffix(short, "Node"); s != "" && s != short {
+			return s
+		}
+		if s := strings.TrimSuffix(short, "C"); s != "" {
+			return s
+		}
+		return short
+	}
+	return name
+}
+
+// boundKeys lists the keys a node binds, for the inspector. A node that
+// takes typing lists "typing".
+func boundKeys(node any) []string {
+	var keys []string
+	if b, ok := node.(bindable); ok {
+		for _, bd := range b.bindings() {
+			keys = append(keys, bd.pattern)
+		}
+	}
+	if tib, ok := node.(textInputBindable); ok && tib.textBinding() != nil {
+		keys = append(keys, "typing")
+	} else if kh, ok := node.(keyHandlerBindable); ok && kh.keyHandler() != nil {
+		keys = append(keys, "typing")
+	}
+	return keys
+}
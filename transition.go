@@ -0,0 +1,179 @@
+package glyph
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Transition animates the Then branch of an If as it appears and
+// disappears, instead of toggling it in a single frame.
+//
+//	If(&showHelp).Then(helpBar).Transition(SlideFade)
+//
+// While leaving, the Then branch keeps rendering until the animation ends;
+// an Else branch takes over afterwards. Inside ForEach the animation state
+// is shared by every item.
+type Transition struct {
+	Duration time.Duration
+	Height   bool // grow from and shrink to zero rows
+	Dim      bool // draw dimmed while in motion
+}
+
+// Common transitions.
+var (
+	Slide     = Transition{Duration: 150 * time.Millisecond, Height: true}
+	Fade      = Transition{Duration: 150 * time.Millisecond, Dim: true}
+	SlideFade = Transition{Duration: 150 * time.Millisecond, Height: true, Dim: true}
+)
+
+// transitionFrame is the redraw interval while a transition is running.
+const transitionFrame = time.Second / 60
+
+// transition tracks one If's enter/exit progress.
+type transition struct {
+	spec Transition
+
+	started bool
+	target  bool      // last observed condition
+	from    float64   // progress when the current motion began
+	since   time.Time // when the current motion began
+
+	update  func() // set to app.RequestRender during wiring
+	pending atomic.Bool
+	clock   Clock
+	instant bool // below QualityFull, motion jumps to its end
+}
+
+func (tr *transition) updateHook() *func()  { return &tr.update }
+func (tr *transition) setClock(c Clock)     { tr.clock = c }
+func (tr *transition) setQuality(q Quality) { tr.instant = q > QualityFull }
+
+func (tr *transition) now() time.Time {
+	if tr.clock == nil {
+		return time.Now()
+	}
+	return tr.clock.Now()
+}
+
+// observe records the current condition and reports whether the Then
+// branch should be laid out and drawn.
+func (tr *transition) observe(cond bool) bool {
+	now := tr.now()
+	if !tr.started {
+		// whatever is showing at first render appears without animating
+		tr.started = true
+		tr.target = cond
+		tr.from = b2f(cond)
+		tr.since = now
+		return cond
+	}
+	if cond != tr.target {
+		tr.from = tr.progressAt(now)
+		tr.target = cond
+		tr.since = now
+	}
+	p := tr.progressAt(now)
+	if p > 0 && p < 1 {
+		tr.schedule()
+	}
+	return p > 0
+}
+
+// progress is 0 when fully hidden and 1 when fully shown.
+func (tr *transition) progress() float64 {
+	return tr.progressAt(tr.now())
+}
+
+func (tr *transition) progressAt(now time.Time) float64 {
+	to := b2f(tr.target)
+	if tr.spec.Duration <= 0 || tr.instant {
+		return to
+	}
+	step := float64(now.Sub(tr.since)) / float64(tr.spec.Duration)
+	if to > tr.from {
+		return min(tr.from+step, 1)
+	}
+	return max(tr.from-step, 0)
+}
+
+// moving reports whether an enter or exit is still under way.
+func (tr *transition) moving() bool {
+	p := tr.progress()
+	return p > 0 && p < 1
+}
+
+// height scales the full content height by the current progress.
+func (tr *transition) height(h int16) int16 {
+	if !tr.spec.Height {
+		return h
+	}
+	p := tr.progress()
+	if p >= 1 {
+		return h
+	}
+	return int16(float64(h)*p + 0.5)
+}
+
+// schedule asks for one more frame after transitionFrame.
+func (tr *transition) schedule() {
+	if tr.update == nil || !tr.pending.CompareAndSwap(false, true) {
+		return
+	}
+	update := tr.update
+	clock := tr.clock
+	if clock == nil {
+		clock = SystemClock
+	}
+	clock.AfterFunc(transitionFrame, func() {
+		tr.pending.Store(false)
+		update()
+	})
+}
+
+// dim marks a drawn Then branch dimmed while it is entering or leaving.
+func (tr *transition) dim(buf *Buffer, x, y, w, h int16) {
+	if !tr.spec.Dim || !tr.moving() {
+		return
+	}
+	for row := int(y); row < int(y+h); row++ {
+		for col := int(x); col < int(x+w); col++ {
+			if !buf.InBounds(col, row) {
+				continue
+			}
+			c := buf.Get(col, row)
+			c.Style.Attr |= AttrDim
+			buf.Set(col, row, c)
+		}
+	}
+}
+
+func b2f(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// ifShown applies op's transition, if any, to an evaluated condition.
+func (op *Op) ifShown(cond bool) bool {
+	if op.trans == nil {
+		return cond
+	}
+	return op.trans.observe(cond)
+}
+
+// ifHeight scales the Then branch height for an op's transition.
+func (op *Op) ifHeight(h int16) int16 {
+	if op.trans == nil {
+		return h
+	}
+	return op.trans.height(h)
+}
+
+// clipTo narrows an inherited vertical clip (0 = none) to bottom.
+func clipTo(clipMaxY, bottom int16) int16 {
+	if clipMaxY == 0 || bottom < clipMaxY {
+		return bottom
+	}
+	return clipMaxY
+}
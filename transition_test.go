@@ -0,0 +1,97 @@
+package glyph
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIfTransitionSlide(t *testing.T) {
+	show := false
+	panel := If(&show).Then(VBox(Text("a"), Text("b"), Text("c"), Text("d"))).Transition(SlideFade)
+	tmpl := Build(VBox(panel, Text("after")))
+	updated := wireLive(tmpl)
+
+	frame := func() *Buffer {
+		buf := NewBuffer(10, 6)
+		tmpl.Execute(buf, 10, 6)
+		return buf
+	}
+	line := func(buf *Buffer, y int) string { return strings.TrimSpace(buf.GetLine(y)) }
+
+	// hidden at first render, no animation
+	if buf := frame(); line(buf, 0) != "after" {
+		t.Fatalf("initial line 0 = %q, want after", line(buf, 0))
+	}
+
+	// entering: halfway through, two rows are revealed and dimmed
+	show = true
+	frame()
+	panel.trans.since = time.Now().Add(-SlideFade.Duration / 2)
+	buf := frame()
+	if line(buf, 0) != "a" || line(buf, 1) != "b" || line(buf, 2) != "after" {
+		t.Errorf("mid-enter lines = %q %q %q, want a b after", line(buf, 0), line(buf, 1), line(buf, 2))
+	}
+	if buf.Get(0, 0).Style.Attr&AttrDim == 0 {
+		t.Error("entering content should be dimmed")
+	}
+	waitUpdate(t, updated) // a follow-up frame was requested
+
+	// done: full height, normal style
+	panel.trans.since = time.Now().Add(-SlideFade.Duration)
+	buf = frame()
+	if line(buf, 3) != "d" || line(buf, 4) != "after" {
+		t.Errorf("shown lines 3,4 = %q %q, want d after", line(buf, 3), line(buf, 4))
+	}
+	if buf.Get(0, 0).Style.Attr&AttrDim != 0 {
+		t.Error("settled content should not be dimmed")
+	}
+
+	// leaving: content stays while shrinking, then disappears
+	show = false
+	frame()
+	panel.trans.since = time.Now().Add(-SlideFade.Duration / 4)
+	buf = frame()
+	if line(buf, 0) != "a" || line(buf, 3) != "after" {
+		t.Errorf("mid-exit lines 0,3 = %q %q, want a after", line(buf, 0), line(buf, 3))
+	}
+	panel.trans.since = time.Now().Add(-SlideFade.Duration)
+	if buf = frame(); line(buf, 0) != "after" {
+		t.Errorf("hidden line 0 = %q, want after", line(buf, 0))
+	}
+}
+
+func TestIfTransitionElseWaitsForExit(t *testing.T) {
+	show := true
+	cond := If(&show).Then(Text("then")).Else(Text("else")).Transition(Fade)
+	tmpl := Build(VBox(cond))
+	buf := NewBuffer(10, 2)
+	tmpl.Execute(buf, 10, 2)
+
+	show = false
+	buf.Clear()
+	tmpl.Execute(buf, 10, 2)
+	if got := strings.TrimSpace(buf.GetLine(0)); got != "then" {
+		t.Errorf("leaving = %q, want then", got)
+	}
+
+	cond.trans.since = time.Now().Add(-Fade.Duration)
+	buf.Clear()
+	tmpl.Execute(buf, 10, 2)
+	if got := strings.TrimSpace(buf.GetLine(0)); got != "else" {
+		t.Errorf("after exit = %q, want else", got)
+	}
+}
+
+func TestIfTransitionReverseMidway(t *testing.T) {
+	tr := &transition{spec: Transition{Duration: 100 * time.Millisecond, Height: true}}
+	tr.observe(false)
+	tr.observe(true)
+	tr.since = time.Now().Add(-30 * time.Millisecond)
+
+	// reversing keeps the current position instead of jumping
+	tr.observe(false)
+	if p := tr.progress(); p < 0.2 || p > 0.4 {
+		t.Errorf("progress after reversal = %.2f, want ~0.3", p)
+	}
+}
@@ -625,17 +625,44 @@ type ForEachNode struct {
 // Render is optional - if nil, items are rendered using fmt.Sprintf("%v", item).
 // Marker defaults to "> " if not specified.
 type SelectionList struct {
-	Items         any    // *[]T - pointer to slice of items
-	Selected      *int   // pointer to selected index
-	Marker        string // selection marker (default "> ", use " " for no visible marker)
-	MarkerStyle   Style  // style for marker text (merged with SelectedStyle.BG for selected rows)
-	Render        any    // func(*T) any - optional, renders each item
-	MaxVisible    int    // max items to show (0 = all)
-	Style         Style  // default style for non-selected rows (e.g., background)
-	SelectedStyle Style  // style for selected row (e.g., background color)
-	len           int    // cached length for bounds checking
-	offset        int    // scroll offset for windowing
-	onMove        func() // called after selection index changes
+	Items         any         // *[]T - pointer to slice of items
+	Selected      *int        // pointer to selected index
+	Marker        string      // selection marker (default "> ", use " " for no visible marker)
+	MarkerStyle   Style       // style for marker text (merged with SelectedStyle.BG for selected rows)
+	Render        any         // func(*T) any - optional, renders each item
+	MaxVisible    int         // max items to show (0 = all)
+	Style         Style       // default style for non-selected rows (e.g., background)
+	SelectedStyle Style       // style for selected row (e.g., background color)
+	OnVisible     func(i int) // called during render when item i scrolls into view
+	OnHidden      func(i int) // called during render when item i scrolls out of view
+	len           int         // cached length for bounds checking
+	offset        int         // scroll offset for windowing
+	onMove        func()      // called after selection index changes
+	shownStart    int         // visible window at the last render
+	shownEnd      int
+}
+
+// observeWindow records the items drawn this frame, calling OnHidden for
+// those that left the window and OnVisible for those that joined it.
+func (s *SelectionList) observeWindow(start, end int) {
+	if start == s.shownStart && end == s.shownEnd {
+		return
+	}
+	if s.OnHidden != nil {
+		for i := s.shownStart; i < s.shownEnd; i++ {
+			if i < start || i >= end {
+				s.OnHidden(i)
+			}
+		}
+	}
+	if s.OnVisible != nil {
+		for i := start; i < end; i++ {
+			if i < s.shownStart || i >= s.shownEnd {
+				s.OnVisible(i)
+			}
+		}
+	}
+	s.shownStart, s.shownEnd = start, end
 }
 
 // ensureVisible adjusts scroll offset so selected item is visible.
@@ -744,6 +771,7 @@ func (s *SelectionList) Last(m any) {
 type Span struct {
 	Text  string
 	Style Style
+	Jump  *SpanJump // makes this span a jump target; nil for plain text
 }
 
 // RichTextNode displays text with mixed inline styles.
@@ -754,8 +782,9 @@ type RichTextNode struct {
 	spanPtrs []*string // per-span *string pointers for Textf (nil = static text)
 }
 
-// Rich creates a RichText from a mix of strings and Spans.
-// Plain strings get default styling, Spans keep their styling.
+// Rich creates a RichText from a mix of strings, Spans, []Span and
+// *RichText builders. Plain strings get default styling, Spans keep their
+// styling.
 //
 // Example:
 //
@@ -768,6 +797,10 @@ func Rich(parts ...any) RichTextNode {
 			spans = append(spans, Span{Text: v})
 		case Span:
 			spans = append(spans, v)
+		case []Span:
+			spans = append(spans, v...)
+		case *RichText:
+			spans = append(spans, v.spans...)
 		}
 	}
 	return RichTextNode{Spans: spans}
@@ -874,6 +907,7 @@ type TextInput struct {
 	Style            Style  // Text style
 	PlaceholderStyle Style  // Placeholder style (zero = dim text)
 	CursorStyle      Style  // Cursor style (zero = reverse video)
+	Disabled         *bool  // Drawn in DefaultDisabledStyle, without the cursor, while true
 }
 
 // OverlayNode displays content floating above the main view.
@@ -916,6 +950,8 @@ type ThemeEx struct {
 	Accent Style // highlighted/important text
 	Error  Style // error messages
 	Border Style // border/divider style
+
+	Disabled Style // disabled widgets; see DefaultDisabledStyle
 }
 
 // Pre-defined themes
@@ -927,6 +963,8 @@ var ThemeDark = ThemeEx{
 	Accent: Style{FG: BrightCyan},
 	Error:  Style{FG: BrightRed},
 	Border: Style{FG: BrightBlack},
+
+	Disabled: Style{FG: BrightBlack, Attr: AttrDim},
 }
 
 // ThemeLight is a light theme with dark text on light background.
@@ -936,6 +974,8 @@ var ThemeLight = ThemeEx{
 	Accent: Style{FG: Blue},
 	Error:  Style{FG: Red},
 	Border: Style{FG: White},
+
+	Disabled: Style{FG: BrightBlack},
 }
 
 // ThemeMonochrome is a minimal theme using only attributes.
@@ -945,4 +985,6 @@ var ThemeMonochrome = ThemeEx{
 	Accent: Style{Attr: AttrBold},
 	Error:  Style{Attr: AttrBold | AttrUnderline},
 	Border: Style{Attr: AttrDim},
+
+	Disabled: Style{Attr: AttrDim},
 }
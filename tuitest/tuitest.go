@@ -0,0 +1,230 @@
+// Package tuitest has helpers for testing glyph views.
+//
+// Golden renders a view at a fixed size and compares it, runes and styles,
+// against a file checked in under testdata:
+//
+//	func TestSidebar(t *testing.T) {
+//	    tuitest.Golden(t, "sidebar", Sidebar(&state), 40, 12)
+//	}
+//
+// Run go test -update-golden to write or refresh the files after an
+// intended change, and review them in the diff like any other code.
+package tuitest
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kungfusheep/glyph"
+)
+
+var update = flag.Bool("update-golden", false, "rewrite tuitest golden files")
+
+// Render builds view and executes it into a new buffer of the given size.
+func Render(view any, width, height int) *glyph.Buffer {
+	buf := glyph.NewBuffer(width, height)
+	glyph.Build(view).Execute(buf, int16(width), int16(height))
+	return buf
+}
+
+// Golden renders view at width x height and compares it with
+// testdata/<name>.golden, failing t with a line-by-line diff on mismatch.
+func Golden(t testing.TB, name string, view any, width, height int) {
+	t.Helper()
+	GoldenBuffer(t, name, Render(view, width, height))
+}
+
+// GoldenBuffer compares an already rendered buffer with
+// testdata/<name>.golden.
+func GoldenBuffer(t testing.TB, name string, buf *glyph.Buffer) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	got := Serialize(buf)
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v (run go test -update-golden to create it)", err)
+	}
+	if d := Diff(string(want), got); d != "" {
+		t.Errorf("%s differs from golden (-want +got):\n%s\nrun go test -update-golden to accept", path, d)
+	}
+}
+
+// Serialize writes buf in the golden format: a size header, the text
+// grid, a style grid with one letter per cell ('.' for the default
+// style), and a legend describing each letter. Trailing blanks are
+// trimmed so the files stay readable.
+//
+//	size 12x2
+//	text:
+//	|ok <tag>
+//	|
+//	style:
+//	|aa
+//	|
+//	legend:
+//	a fg=2 bold
+func Serialize(buf *glyph.Buffer) string {
+	var text, style strings.Builder
+	letters := map[cellStyle]byte{}
+	var legend []string
+
+	for y := 0; y < buf.Height(); y++ {
+		var tl, sl []byte
+		tEnd, sEnd := 0, 0
+		for x := 0; x < buf.Width(); x++ {
+			c := buf.Get(x, y)
+			r := c.Rune
+			if r == 0 {
+				r = ' '
+			}
+			tl = append(tl, string(r)...)
+			if r != ' ' {
+				tEnd = len(tl)
+			}
+
+			cs := cellStyle{c.Style.FG, c.Style.BG, c.Style.Attr}
+			l := byte('.')
+			if cs != (cellStyle{}) {
+				var ok bool
+				if l, ok = letters[cs]; !ok {
+					l = styleLetter(len(letters))
+					letters[cs] = l
+					legend = append(legend, string(l)+" "+cs.String())
+				}
+				sEnd = len(sl) + 1
+			}
+			sl = append(sl, l)
+		}
+		text.WriteString("|" + string(tl[:tEnd]) + "\n")
+		style.WriteString("|" + string(sl[:sEnd]) + "\n")
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "size %dx%d\ntext:\n", buf.Width(), buf.Height())
+	sb.WriteString(text.String())
+	sb.WriteString("style:\n")
+	sb.WriteString(style.String())
+	sb.WriteString("legend:\n")
+	for _, l := range legend {
+		sb.WriteString(l + "\n")
+	}
+	return sb.String()
+}
+
+// styleLetter names the nth distinct style: a-z, then A-Z, then digits
+// and punctuation.
+func styleLetter(n int) byte {
+	const set = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789!#$%&*+-/<=>?@^_~"
+	if n < len(set) {
+		return set[n]
+	}
+	return '?'
+}
+
+// cellStyle is the part of a Style that reaches the terminal.
+type cellStyle struct {
+	fg, bg glyph.Color
+	attr   glyph.Attribute
+}
+
+var attrNames = []struct {
+	a    glyph.Attribute
+	name string
+}{
+	{glyph.AttrBold, "bold"},
+	{glyph.AttrDim, "dim"},
+	{glyph.AttrItalic, "italic"},
+	{glyph.AttrUnderline, "underline"},
+	{glyph.AttrBlink, "blink"},
+	{glyph.AttrInverse, "inverse"},
+	{glyph.AttrStrikethrough, "strike"},
+}
+
+func (s cellStyle) String() string {
+	var parts []string
+	if c := colorString(s.fg); c != "" {
+		parts = append(parts, "fg="+c)
+	}
+	if c := colorString(s.bg); c != "" {
+		parts = append(parts, "bg="+c)
+	}
+	for _, a := range attrNames {
+		if s.attr.Has(a.a) {
+			parts = append(parts, a.name)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+func colorString(c glyph.Color) string {
+	switch c.Mode {
+	case glyph.Color16:
+		return fmt.Sprint(c.Index)
+	case glyph.Color256:
+		return fmt.Sprintf("p%d", c.Index)
+	case glyph.ColorRGB:
+		return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+	}
+	return ""
+}
+
+// Diff compares two serialized frames line by line and returns the
+// differing lines with their section and row, or "" if they match.
+func Diff(want, got string) string {
+	if want == got {
+		return ""
+	}
+	wl := strings.Split(strings.TrimSuffix(want, "\n"), "\n")
+	gl := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+
+	var sb strings.Builder
+	section, row := "", 0
+	for i := 0; i < max(len(wl), len(gl)); i++ {
+		var w, g string
+		if i < len(wl) {
+			w = wl[i]
+		}
+		if i < len(gl) {
+			g = gl[i]
+		}
+		line := w
+		if line == "" {
+			line = g
+		}
+		if strings.HasSuffix(line, ":") && !strings.HasPrefix(line, "|") {
+			section, row = strings.TrimSuffix(line, ":"), 0
+		} else if strings.HasPrefix(line, "|") {
+			row++
+		}
+		if w == g {
+			continue
+		}
+		where := section
+		if strings.HasPrefix(line, "|") {
+			where = fmt.Sprintf("%s row %d", section, row-1)
+		}
+		fmt.Fprintf(&sb, "%s:\n", where)
+		if i < len(wl) {
+			fmt.Fprintf(&sb, "  - %s\n", w)
+		}
+		if i < len(gl) {
+			fmt.Fprintf(&sb, "  + %s\n", g)
+		}
+	}
+	return sb.String()
+}
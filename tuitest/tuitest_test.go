@@ -0,0 +1,46 @@
+package tuitest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kungfusheep/glyph"
+)
+
+func statusView() any {
+	return glyph.VBox(
+		glyph.Text("build").FG(glyph.Green).Bold(),
+		glyph.HBox.Gap(1)(glyph.Text("tests"), glyph.Text("ok").FG(glyph.Green).Bold()),
+	)
+}
+
+func TestGolden(t *testing.T) {
+	Golden(t, "status", statusView(), 12, 3)
+}
+
+func TestSerialize(t *testing.T) {
+	buf := glyph.NewBuffer(6, 2)
+	buf.WriteString(0, 0, "hi", glyph.Style{FG: glyph.Red})
+	buf.WriteString(3, 0, "x", glyph.Style{Attr: glyph.AttrUnderline})
+	buf.WriteString(0, 1, "yo", glyph.Style{FG: glyph.Red})
+
+	want := "size 6x2\n" +
+		"text:\n|hi x\n|yo\n" +
+		"style:\n|aa.b\n|aa\n" +
+		"legend:\na fg=1\nb underline\n"
+	if got := Serialize(buf); got != want {
+		t.Errorf("Serialize =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := Serialize(Render(glyph.Text("hello"), 8, 1))
+	b := Serialize(Render(glyph.Text("help"), 8, 1))
+	if d := Diff(a, a); d != "" {
+		t.Errorf("identical frames diff = %q", d)
+	}
+	d := Diff(a, b)
+	if !strings.Contains(d, "text row 0:\n  - |hello\n  + |help") {
+		t.Errorf("diff =\n%s", d)
+	}
+}
@@ -0,0 +1,119 @@
+package glyph
+
+import "unsafe"
+
+// ValidatedC shows an input's error or warning around it: the icon after
+// it, the message below and, with Border, the border in the state's
+// colour. The state comes from bound strings, where "" means none:
+//
+//	Validated(Input(&email).Bind()).Error(&emailErr).Warning(&emailWarn).Border(BorderRounded)
+//
+// Without Error, a child with its own validation, such as an Input with
+// Validate, shows its Err:
+//
+//	Validated(Input().Validate(VEmail, VOnChange).Bind())
+//
+// An error is shown over a warning. Form adorns its fields this way.
+type ValidatedC struct {
+	child     any
+	err       *string
+	warn      *string
+	border    BorderStyle
+	errStyle  Style
+	warnStyle Style
+	errIcon   string
+	warnIcon  string
+
+	// what's shown, worked out at the start of each frame
+	message string
+	icon    string
+	style   Style
+	color   Color
+}
+
+// Validated wraps child with error and warning adornments.
+func Validated(child any) *ValidatedC {
+	return &ValidatedC{
+		child:     child,
+		errStyle:  Style{FG: Red},
+		warnStyle: Style{FG: Yellow},
+		errIcon:   "✗",
+		warnIcon:  "!",
+	}
+}
+
+// Error binds the error message.
+func (v *ValidatedC) Error(msg *string) *ValidatedC {
+	v.err = msg
+	return v
+}
+
+// Warning binds the warning message.
+func (v *ValidatedC) Warning(msg *string) *ValidatedC {
+	v.warn = msg
+	return v
+}
+
+// Border draws a border around the child, coloured by the state.
+func (v *ValidatedC) Border(b BorderStyle) *ValidatedC {
+	v.border = b
+	return v
+}
+
+// ErrorStyle sets the style of errors. Red by default; its FG colours the
+// border.
+func (v *ValidatedC) ErrorStyle(s Style) *ValidatedC {
+	v.errStyle = s
+	return v
+}
+
+// WarningStyle sets the style of warnings. Yellow by default.
+func (v *ValidatedC) WarningStyle(s Style) *ValidatedC {
+	v.warnStyle = s
+	return v
+}
+
+// Icons sets the icons shown after the child. "✗" and "!" by default; ""
+// shows none.
+func (v *ValidatedC) Icons(err, warn string) *ValidatedC {
+	v.errIcon, v.warnIcon = err, warn
+	return v
+}
+
+// drain works out the state to show, before each frame.
+func (v *ValidatedC) drain() {
+	errMsg := ""
+	if v.err != nil {
+		errMsg = *v.err
+	} else if c, ok := v.child.(validatable); ok {
+		errMsg = c.Err()
+	}
+	switch {
+	case errMsg != "":
+		v.message, v.icon, v.style = errMsg, v.errIcon, v.errStyle
+	case v.warn != nil && *v.warn != "":
+		v.message, v.icon, v.style = *v.warn, v.warnIcon, v.warnStyle
+	default:
+		v.message, v.icon, v.style = "", "", Style{}
+	}
+	v.color = v.style.FG
+}
+
+func (t *Template) compileValidatedC(v *ValidatedC, parent int16, depth int, elemBase unsafe.Pointer, elemSize uintptr) int16 {
+	t.addLive(v)
+	// the field takes the width the icon leaves
+	field := VBox.Grow(1)(v.child)
+	var iconTop int16
+	if v.border.Horizontal != 0 {
+		field.border = v.border
+		field.borderFG = &v.color
+		iconTop = 1 // beside the child, not its border
+	}
+	adorned := VBox(
+		HBox(field, If(&v.icon).Then(
+			VBox.Width(1+int16(max(StringWidth(v.errIcon), StringWidth(v.warnIcon)))).CascadeStyle(&v.style)(
+				Text(&v.icon).MarginTRBL(iconTop, 0, 0, 1)))),
+		If(&v.message).Then(VBox.CascadeStyle(&v.style)(Text(&v.message))),
+	)
+	return t.compile(adorned, parent, depth, elemBase, elemSize)
+}
@@ -0,0 +1,69 @@
+package glyph
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidated(t *testing.T) {
+	value, errMsg, warn := "bob", "", ""
+	tmpl := Build(Validated(Input(&value).Width(10)).Error(&errMsg).Warning(&warn).Border(BorderSingle))
+	frame := func() *Buffer {
+		buf := NewBuffer(20, 4)
+		tmpl.Execute(buf, 20, 4)
+		return buf
+	}
+
+	buf := frame()
+	if buf.Get(0, 0).Style.FG.Mode != ColorDefault || strings.TrimSpace(buf.GetLine(3)) != "" {
+		t.Errorf("adorned with no state:\n%s", buf.String())
+	}
+
+	warn = "looks short"
+	buf = frame()
+	if got := strings.TrimSpace(buf.GetLine(3)); got != "looks short" {
+		t.Errorf("warning line = %q", got)
+	}
+	if buf.Get(0, 0).Style.FG != Yellow || buf.Get(0, 3).Style.FG != Yellow {
+		t.Error("warning not drawn in yellow")
+	}
+
+	errMsg = "taken"
+	buf = frame()
+	if got := strings.TrimSpace(buf.GetLine(3)); got != "taken" {
+		t.Errorf("error line = %q", got)
+	}
+	if got := buf.Get(19, 1); got.Rune != '✗' || got.Style.FG != Red {
+		t.Errorf("error icon = %q:\n%s", got.Rune, buf.String())
+	}
+	if buf.Get(0, 0).Style.FG != Red {
+		t.Error("border not red on error")
+	}
+	if buf.Get(17, 0).Style.FG != Red || buf.Get(1, 1).Style.FG == Red {
+		t.Errorf("error colour on the border only:\n%s", buf.String())
+	}
+}
+
+func TestValidatedChildErr(t *testing.T) {
+	in := Input().Validate(VRequired, VOnChange)
+	tmpl := Build(Validated(in))
+	in.runValidation()
+	buf := NewBuffer(20, 2)
+	tmpl.Execute(buf, 20, 2)
+	if got := strings.TrimSpace(buf.GetLine(1)); got != "required" {
+		t.Errorf("message = %q, want the input's own error", got)
+	}
+}
+
+func TestFormShowsErrorBelowControl(t *testing.T) {
+	name := ""
+	form := Form(Field("Name", Input(&name).Validate(VRequired, VOnSubmit)))
+	tmpl := Build(form)
+	form.ValidateAll()
+	buf := NewBuffer(30, 3)
+	tmpl.Execute(buf, 30, 3)
+	line := buf.GetLine(1)
+	if strings.TrimSpace(line) != "required" || !strings.HasPrefix(line, strings.Repeat(" ", StringWidth("▸Name: "))+"required") {
+		t.Errorf("error not under the control:\n%s", buf.String())
+	}
+}
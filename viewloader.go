@@ -0,0 +1,476 @@
+package glyph
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ViewLoader builds views from a declarative description, for dashboards
+// generated by tooling or kept in config files rather than compiled in:
+//
+//	{"type": "vbox", "border": "rounded", "title": "Build", "children": [
+//	    {"type": "text", "text": "$status", "bold": true},
+//	    {"type": "progress", "value": "$done", "width": 30},
+//	    "plain strings are text"
+//	]}
+//
+// A node has a type, its props and any children. A string prop of the
+// form "$name" is replaced by the value bound to name, usually a pointer
+// into app state so the view stays live; a leading "$$" stands for "$".
+// Types other than the built-in ones are added with Register.
+//
+// The built-in types and their props are:
+//
+//	vbox, hbox    children, border (single, rounded, double), title, gap,
+//	              grow, width, height, widthPct, fill, borderFG, margin
+//	text          text, width
+//	space         grow, width, height
+//	hrule, vrule
+//	progress      value, width
+//	leader        label, value, width
+//	sparkline     values, width, min, max
+//
+// Every type but space and the containers also takes fg, bg, bold, dim,
+// italic, underline and inverse, and margin, which is one number, [v, h] or
+// [top, right, bottom, left]. Colours are names such as "green" or
+// "brightblue", "#rrggbb" or a palette number.
+type ViewLoader struct {
+	types   map[string]ComponentFunc
+	data    map[string]any
+	formats map[string]func([]byte, any) error
+}
+
+// ComponentFunc builds a component from a node, given its children already
+// built. It's how Register adds a type to a ViewLoader.
+type ComponentFunc func(n ViewNode, children []any) (any, error)
+
+// ViewNode is a node of a view description, with its bindings resolved.
+type ViewNode struct {
+	Type  string
+	Props map[string]any
+	Path  string // where the node is, as in "view.children[2].children[0]"
+}
+
+// NewViewLoader returns a loader that knows the built-in types and reads
+// JSON files.
+func NewViewLoader() *ViewLoader {
+	l := &ViewLoader{
+		types:   map[string]ComponentFunc{},
+		data:    map[string]any{},
+		formats: map[string]func([]byte, any) error{".json": json.Unmarshal},
+	}
+	for name, fn := range builtinViewTypes {
+		l.types[name] = fn
+	}
+	return l
+}
+
+// Register adds a component type, or replaces a built-in one.
+func (l *ViewLoader) Register(typ string, fn ComponentFunc) *ViewLoader {
+	l.types[typ] = fn
+	return l
+}
+
+// Bind makes v available to descriptions as "$name".
+func (l *ViewLoader) Bind(name string, v any) *ViewLoader {
+	l.data[name] = v
+	return l
+}
+
+// Format sets how LoadFile decodes files with the extension ext, for
+// formats other than JSON:
+//
+//	loader.Format(".yaml", yaml.Unmarshal).Format(".yml", yaml.Unmarshal)
+func (l *ViewLoader) Format(ext string, unmarshal func(data []byte, v any) error) *ViewLoader {
+	l.formats[strings.ToLower(ext)] = unmarshal
+	return l
+}
+
+// Types returns the names of the types the loader knows, sorted.
+func (l *ViewLoader) Types() []string {
+	names := make([]string, 0, len(l.types))
+	for name := range l.types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadFile reads a view description from path, decoding it by the file's
+// extension.
+func (l *ViewLoader) LoadFile(path string) (any, error) {
+	ext := strings.ToLower(filepath.Ext(path))
+	unmarshal, ok := l.formats[ext]
+	if !ok {
+		return nil, fmt.Errorf("view: no format for %q files", ext)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var tree any
+	if err := unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	view, err := l.Load(tree)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return view, nil
+}
+
+// LoadJSON builds a view from a JSON description.
+func (l *ViewLoader) LoadJSON(data []byte) (any, error) {
+	var tree any
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("view: %w", err)
+	}
+	return l.Load(tree)
+}
+
+// Load builds a view from a description already decoded into maps, slices
+// and scalars, as encoding/json and YAML decoders produce.
+func (l *ViewLoader) Load(tree any) (any, error) {
+	return l.build(tree, "view")
+}
+
+func (l *ViewLoader) build(v any, path string) (any, error) {
+	if s, ok := v.(string); ok {
+		v = map[string]any{"type": "text", "text": s}
+	}
+	m, ok := stringKeys(v)
+	if !ok {
+		return nil, fmt.Errorf("%s: want a node or a string, got %T", path, v)
+	}
+	typ, _ := m["type"].(string)
+	fn, ok := l.types[typ]
+	if !ok {
+		if typ == "" {
+			return nil, fmt.Errorf("%s: node has no type", path)
+		}
+		return nil, fmt.Errorf("%s: unknown type %q", path, typ)
+	}
+
+	n := ViewNode{Type: typ, Props: map[string]any{}, Path: path}
+	var children []any
+	for key, val := range m {
+		switch key {
+		case "type":
+		case "children":
+			list, ok := val.([]any)
+			if !ok {
+				return nil, fmt.Errorf("%s: children should be a list", path)
+			}
+			for i, c := range list {
+				child, err := l.build(c, fmt.Sprintf("%s.children[%d]", path, i))
+				if err != nil {
+					return nil, err
+				}
+				children = append(children, child)
+			}
+		default:
+			resolved, err := l.resolve(val)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", path, key, err)
+			}
+			n.Props[key] = resolved
+		}
+	}
+	view, err := fn(n, children)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return view, nil
+}
+
+// resolve swaps "$name" strings for the values bound to them.
+func (l *ViewLoader) resolve(v any) (any, error) {
+	s, ok := v.(string)
+	if !ok || !strings.HasPrefix(s, "$") {
+		return v, nil
+	}
+	if strings.HasPrefix(s, "$$") {
+		return s[1:], nil
+	}
+	if bound, ok := l.data[s[1:]]; ok {
+		return bound, nil
+	}
+	return nil, fmt.Errorf("nothing bound to %q", s)
+}
+
+// stringKeys returns v as a map with string keys, converting the
+// map[any]any some YAML decoders produce.
+func stringKeys(v any) (map[string]any, bool) {
+	switch m := v.(type) {
+	case map[string]any:
+		return m, true
+	case map[any]any:
+		out := make(map[string]any, len(m))
+		for k, val := range m {
+			out[fmt.Sprint(k)] = val
+		}
+		return out, true
+	}
+	return nil, false
+}
+
+// Has reports whether the node sets prop.
+func (n ViewNode) Has(prop string) bool {
+	_, ok := n.Props[prop]
+	return ok
+}
+
+// String returns a string prop, or "".
+func (n ViewNode) String(prop string) string {
+	s, _ := n.Props[prop].(string)
+	return s
+}
+
+// Number returns a numeric prop, or 0.
+func (n ViewNode) Number(prop string) float64 {
+	f, _ := toFloat(n.Props[prop])
+	return f
+}
+
+// Bool returns a boolean prop, or false.
+func (n ViewNode) Bool(prop string) bool {
+	b, _ := n.Props[prop].(bool)
+	return b
+}
+
+// Style returns the style set by the fg, bg, bold, dim, italic, underline
+// and inverse props.
+func (n ViewNode) Style() (Style, error) {
+	var s Style
+	for prop, c := range map[string]*Color{"fg": &s.FG, "bg": &s.BG} {
+		if !n.Has(prop) {
+			continue
+		}
+		color, ok := parseMarkupColor(fmt.Sprint(n.Props[prop]))
+		if !ok {
+			return s, fmt.Errorf("%s: unknown colour %v", prop, n.Props[prop])
+		}
+		*c = color
+	}
+	for prop, attr := range map[string]Attribute{
+		"bold": AttrBold, "dim": AttrDim, "italic": AttrItalic,
+		"underline": AttrUnderline, "inverse": AttrInverse,
+	} {
+		if n.Bool(prop) {
+			s.Attr |= attr
+		}
+	}
+	return s, nil
+}
+
+// Margin returns the margin prop as top, right, bottom and left.
+func (n ViewNode) Margin() ([4]int16, error) {
+	var m [4]int16
+	switch v := n.Props["margin"].(type) {
+	case nil:
+	case []any:
+		var f []int16
+		for _, e := range v {
+			x, ok := toFloat(e)
+			if !ok {
+				return m, fmt.Errorf("margin: %v is not a number", e)
+			}
+			f = append(f, int16(x))
+		}
+		switch len(f) {
+		case 2:
+			m = [4]int16{f[0], f[1], f[0], f[1]}
+		case 4:
+			m = [4]int16(f)
+		default:
+			return m, fmt.Errorf("margin: want 1, 2 or 4 numbers")
+		}
+	default:
+		x, ok := toFloat(v)
+		if !ok {
+			return m, fmt.Errorf("margin: %v is not a number", v)
+		}
+		m = [4]int16{int16(x), int16(x), int16(x), int16(x)}
+	}
+	return m, nil
+}
+
+func toFloat(v any) (float64, bool) {
+	switch x := v.(type) {
+	case float64:
+		return x, true
+	case float32:
+		return float64(x), true
+	case int:
+		return float64(x), true
+	case int64:
+		return float64(x), true
+	case uint64:
+		return float64(x), true
+	}
+	return 0, false
+}
+
+// ============================================================================
+// Built-in types
+// ============================================================================
+
+var builtinViewTypes = map[string]ComponentFunc{
+	"vbox": func(n ViewNode, children []any) (any, error) {
+		f, err := boxProps(VBox, n)
+		return f(children...), err
+	},
+	"hbox": func(n ViewNode, children []any) (any, error) {
+		f, err := boxProps(HBox, n)
+		return f(children...), err
+	},
+	"text": func(n ViewNode, _ []any) (any, error) {
+		s, m, err := leafProps(n)
+		t := Text(n.Props["text"]).Style(s).MarginTRBL(m[0], m[1], m[2], m[3])
+		if n.Has("width") {
+			t = t.Width(int16(n.Number("width")))
+		}
+		return t, err
+	},
+	"space": func(n ViewNode, _ []any) (any, error) {
+		s := Space()
+		if n.Has("grow") {
+			s = s.Grow(float32(n.Number("grow")))
+		}
+		if n.Has("width") {
+			s = s.Width(int16(n.Number("width")))
+		}
+		if n.Has("height") {
+			s = s.Height(int16(n.Number("height")))
+		}
+		return s, nil
+	},
+	"hrule": func(n ViewNode, _ []any) (any, error) {
+		s, m, err := leafProps(n)
+		return HRule().Style(s).MarginTRBL(m[0], m[1], m[2], m[3]), err
+	},
+	"vrule": func(n ViewNode, _ []any) (any, error) {
+		s, _, err := leafProps(n)
+		return VRule().Style(s), err
+	},
+	"progress": func(n ViewNode, _ []any) (any, error) {
+		s, m, err := leafProps(n)
+		value := n.Props["value"]
+		if f, ok := toFloat(value); ok {
+			value = int(f)
+		}
+		p := Progress(value).Style(s).MarginTRBL(m[0], m[1], m[2], m[3])
+		if n.Has("width") {
+			p = p.Width(int16(n.Number("width")))
+		}
+		return p, err
+	},
+	"leader": func(n ViewNode, _ []any) (any, error) {
+		s, m, err := leafProps(n)
+		l := Leader(n.Props["label"], n.Props["value"]).Style(s).MarginTRBL(m[0], m[1], m[2], m[3])
+		if n.Has("width") {
+			l = l.Width(int16(n.Number("width")))
+		}
+		return l, err
+	},
+	"sparkline": func(n ViewNode, _ []any) (any, error) {
+		s, m, err := leafProps(n)
+		values := n.Props["values"]
+		if list, ok := values.([]any); ok {
+			fs := make([]float64, len(list))
+			for i, v := range list {
+				fs[i], _ = toFloat(v)
+			}
+			values = fs
+		}
+		sp := Sparkline(values).Style(s).MarginTRBL(m[0], m[1], m[2], m[3])
+		if n.Has("width") {
+			sp = sp.Width(int16(n.Number("width")))
+		}
+		if n.Has("min") || n.Has("max") {
+			sp = sp.Range(n.Number("min"), n.Number("max"))
+		}
+		return sp, err
+	},
+}
+
+// leafProps reads the style and margin props every leaf type takes.
+func leafProps(n ViewNode) (Style, [4]int16, error) {
+	s, err := n.Style()
+	if err != nil {
+		return s, [4]int16{}, err
+	}
+	m, err := n.Margin()
+	return s, m, err
+}
+
+var viewBorders = map[string]BorderStyle{
+	"single":  BorderSingle,
+	"rounded": BorderRounded,
+	"double":  BorderDouble,
+}
+
+// boxBuilder is the builder methods VBoxFn and HBoxFn share.
+type boxBuilder[F any] interface {
+	Border(BorderStyle) F
+	BorderFG(Color) F
+	Title(string) F
+	Gap(int8) F
+	Grow(float32) F
+	Width(int16) F
+	Height(int16) F
+	WidthPct(float32) F
+	Fill(Color) F
+	MarginTRBL(top, right, bottom, left int16) F
+}
+
+// boxProps applies a container node's props to VBox or HBox.
+func boxProps[F boxBuilder[F]](f F, n ViewNode) (F, error) {
+	if name := n.String("border"); name != "" {
+		b, ok := viewBorders[name]
+		if !ok {
+			return f, fmt.Errorf("border: unknown style %q", name)
+		}
+		f = f.Border(b)
+	}
+	if n.Has("title") {
+		f = f.Title(n.String("title"))
+	}
+	if n.Has("gap") {
+		f = f.Gap(int8(n.Number("gap")))
+	}
+	if n.Has("grow") {
+		f = f.Grow(float32(n.Number("grow")))
+	}
+	if n.Has("width") {
+		f = f.Width(int16(n.Number("width")))
+	}
+	if n.Has("height") {
+		f = f.Height(int16(n.Number("height")))
+	}
+	if n.Has("widthPct") {
+		f = f.WidthPct(float32(n.Number("widthPct") / 100))
+	}
+	for prop, set := range map[string]func(Color) F{"fill": f.Fill, "borderFG": f.BorderFG} {
+		if !n.Has(prop) {
+			continue
+		}
+		c, ok := parseMarkupColor(fmt.Sprint(n.Props[prop]))
+		if !ok {
+			return f, fmt.Errorf("%s: unknown colour %v", prop, n.Props[prop])
+		}
+		f = set(c)
+	}
+	m, err := n.Margin()
+	if err != nil {
+		return f, err
+	}
+	if m != [4]int16{} {
+		f = f.MarginTRBL(m[0], m[1], m[2], m[3])
+	}
+	return f, nil
+}
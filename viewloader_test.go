@@ -0,0 +1,93 @@
+package glyph
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestViewLoader(t *testing.T) {
+	status, done := "building", 50
+	l := NewViewLoader().Bind("status", &status).Bind("done", &done)
+	l.Register("badge", func(n ViewNode, _ []any) (any, error) {
+		return Text("[" + n.String("label") + "]"), nil
+	})
+
+	view, err := l.LoadJSON([]byte(`{"type": "vbox", "border": "rounded", "title": "CI", "children": [
+		{"type": "text", "text": "$status", "fg": "green", "bold": true},
+		{"type": "hbox", "gap": 1, "children": [
+			"$$3 each",
+			{"type": "badge", "label": "ok"}
+		]},
+		{"type": "progress", "value": "$done", "width": 10}
+	]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := Build(view)
+	lines := messageLines(tmpl, 16, 5)
+	want := []string{
+		"╭─ CI ─────────╮",
+		"│building      │",
+		"│$3 each [ok]  │",
+		"│█████         │",
+		"╰──────────────╯",
+	}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("got\n%s", strings.Join(lines, "\n"))
+	}
+
+	// bound values stay live
+	status = "done"
+	if got := messageLines(tmpl, 16, 5)[1]; got != "│done          │" {
+		t.Errorf("after update %q", got)
+	}
+	buf := NewBuffer(16, 5)
+	tmpl.Execute(buf, 16, 5)
+	if c := buf.Get(1, 1); c.Style.FG != Green || c.Style.Attr&AttrBold == 0 {
+		t.Errorf("style %+v", c.Style)
+	}
+}
+
+func TestViewLoaderErrors(t *testing.T) {
+	l := NewViewLoader()
+	for _, tc := range []struct{ src, want string }{
+		{`{"type": "vbox", "children": [{"type": "gauge"}]}`, `view.children[0]: unknown type "gauge"`},
+		{`{"type": "text", "text": "$missing"}`, `view.text: nothing bound to "$missing"`},
+		{`{"type": "vbox", "border": "wavy"}`, `view: border: unknown style "wavy"`},
+		{`{"type": "text", "fg": "mauve"}`, `view: fg: unknown colour mauve`},
+		{`{"text": "hi"}`, `view: node has no type`},
+		{`[1, 2]`, `view: want a node or a string, got []interface {}`},
+	} {
+		if _, err := l.LoadJSON([]byte(tc.src)); err == nil || err.Error() != tc.want {
+			t.Errorf("%s: got %v, want %s", tc.src, err, tc.want)
+		}
+	}
+}
+
+func TestViewLoaderFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "view.conf")
+	os.WriteFile(path, []byte("text=hello"), 0o644)
+
+	l := NewViewLoader()
+	if _, err := l.LoadFile(path); err == nil {
+		t.Fatal("loaded a file with no format")
+	}
+	// a made-up format standing in for YAML, decoding to map[any]any as
+	// some YAML decoders do
+	l.Format(".conf", func(data []byte, v any) error {
+		key, val, _ := strings.Cut(string(data), "=")
+		*v.(*any) = map[any]any{"type": "text", key: val}
+		return nil
+	})
+	view, err := l.LoadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := messageLines(Build(view), 10, 1)[0]; got != "hello" {
+		t.Errorf("got %q", got)
+	}
+}
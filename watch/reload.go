@@ -0,0 +1,111 @@
+package watch
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/kungfusheep/glyph"
+)
+
+// Reloader rebuilds an app's view when the files it watches change.
+type Reloader struct {
+	app   *glyph.App
+	name  string // the named view to update, or "" for the main one
+	build func() (any, error)
+
+	mu      sync.Mutex
+	onError func(error)
+	err     error
+	reloads int
+}
+
+// Reload builds the app's view with build and sets it, then builds and sets
+// it again each time the watched files change, until the app stops. A
+// build that fails or panics leaves the last good view up and goes to
+// OnError. Only the first build's error is returned.
+//
+// Each reload is a SetView, so leave SetViewLimit unset while reloading.
+func (w *Watcher) Reload(app *glyph.App, build func() (any, error)) (*Reloader, error) {
+	return w.reload(&Reloader{app: app, build: build})
+}
+
+// ReloadView is Reload for a view added with App.View, which it updates
+// with UpdateView.
+func (w *Watcher) ReloadView(app *glyph.App, name string, build func() (any, error)) (*Reloader, error) {
+	return w.reload(&Reloader{app: app, name: name, build: build})
+}
+
+func (w *Watcher) reload(r *Reloader) (*Reloader, error) {
+	view, err := r.call()
+	if err != nil {
+		return nil, err
+	}
+	r.apply(view)
+	w.Scan()
+	go w.Run(r.app.Context(), func(changed []string) {
+		r.app.Post(func() { r.rebuild(changed) })
+	})
+	return r, nil
+}
+
+// OnError sets a function called with the error when a reload fails.
+// It's called on the render goroutine.
+func (r *Reloader) OnError(fn func(error)) *Reloader {
+	r.mu.Lock()
+	r.onError = fn
+	r.mu.Unlock()
+	return r
+}
+
+// Err returns the error from the last reload, or nil once one succeeds.
+func (r *Reloader) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.err
+}
+
+// Reloads returns how many times the view has been rebuilt after a change.
+func (r *Reloader) Reloads() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.reloads
+}
+
+// rebuild runs build after changed files, with rendering held off.
+func (r *Reloader) rebuild(changed []string) {
+	view, err := r.call()
+	if err != nil {
+		err = fmt.Errorf("watch: reloading after %s changed: %w", changed[0], err)
+	} else {
+		r.apply(view)
+	}
+	r.mu.Lock()
+	r.err = err
+	if err == nil {
+		r.reloads++
+	}
+	onError := r.onError
+	r.mu.Unlock()
+	if err != nil && onError != nil {
+		onError(err)
+	}
+}
+
+// call runs build, turning a panic into an error so that a half-edited
+// view can't take the app down.
+func (r *Reloader) call() (view any, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("view build panicked: %v", p)
+		}
+	}()
+	return r.build()
+}
+
+func (r *Reloader) apply(view any) {
+	if r.name == "" {
+		r.app.SetView(view)
+	} else {
+		r.app.UpdateView(r.name, view)
+	}
+}
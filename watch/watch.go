@@ -0,0 +1,150 @@
+// Package watch notices when files change, and reloads glyph views built
+// from them while an app runs, for a quicker edit-run loop on UI work:
+//
+//	loader := glyph.NewViewLoader().Bind("cpu", &cpu)
+//	_, err := watch.Files("dashboard.json").Reload(app, func() (any, error) {
+//	    return loader.LoadFile("dashboard.json")
+//	})
+//
+// The build function runs again whenever a watched file changes and the
+// app switches to the view it returns. State lives on across reloads as
+// long as it's kept outside build, in values the view points into, as
+// glyph views do anyway. Changes to Go code still need a rebuild.
+//
+// Files are polled for changes to their modification time and size, so it
+// works the same everywhere, without cgo or platform notifications.
+package watch
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Watcher polls files and directories for changes.
+type Watcher struct {
+	paths    []string
+	interval time.Duration
+	match    []string
+	seen     map[string]stamp
+}
+
+// stamp is what a scan notes about a file to tell whether it has changed.
+type stamp struct {
+	mod  time.Time
+	size int64
+}
+
+// Files watches paths. A directory stands for every file under it, apart
+// from those in hidden directories such as .git.
+func Files(paths ...string) *Watcher {
+	return &Watcher{paths: paths, interval: 250 * time.Millisecond}
+}
+
+// Interval sets how often Run polls. 250ms by default.
+func (w *Watcher) Interval(d time.Duration) *Watcher {
+	w.interval = d
+	return w
+}
+
+// Match limits the files found in directories to those whose names match
+// one of the patterns, as in Match("*.go", "*.json"). Paths named directly
+// are always watched.
+func (w *Watcher) Match(patterns ...string) *Watcher {
+	w.match = patterns
+	return w
+}
+
+// Scan polls once and returns the files added, changed or removed since the
+// last Scan, sorted. The first Scan notes where things stand and returns
+// nothing.
+func (w *Watcher) Scan() []string {
+	now := w.stamps()
+	if w.seen == nil {
+		w.seen = now
+		return nil
+	}
+	var changed []string
+	for path, s := range now {
+		if old, ok := w.seen[path]; !ok || old != s {
+			changed = append(changed, path)
+		}
+	}
+	for path := range w.seen {
+		if _, ok := now[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	w.seen = now
+	slices.Sort(changed)
+	return changed
+}
+
+// Run polls until ctx is done, calling onChange with the files that changed
+// each time some have. It returns ctx's error. Changes are counted from the
+// last Scan, or from when Run starts if there hasn't been one.
+func (w *Watcher) Run(ctx context.Context, onChange func(changed []string)) error {
+	if w.seen == nil {
+		w.Scan()
+	}
+	tick := time.NewTicker(w.interval)
+	defer tick.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-tick.C:
+			if changed := w.Scan(); len(changed) > 0 {
+				onChange(changed)
+			}
+		}
+	}
+}
+
+func (w *Watcher) stamps() map[string]stamp {
+	out := map[string]stamp{}
+	for _, root := range w.paths {
+		info, err := os.Stat(root)
+		if err != nil {
+			continue // missing for now; it shows up as added later
+		}
+		if !info.IsDir() {
+			out[root] = stamp{info.ModTime(), info.Size()}
+			continue
+		}
+		filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			switch {
+			case err != nil:
+				return nil
+			case d.IsDir():
+				if path != root && strings.HasPrefix(d.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			case !w.matches(d.Name()):
+				return nil
+			}
+			if info, err := d.Info(); err == nil {
+				out[path] = stamp{info.ModTime(), info.Size()}
+			}
+			return nil
+		})
+	}
+	return out
+}
+
+func (w *Watcher) matches(name string) bool {
+	if len(w.match) == 0 {
+		return true
+	}
+	for _, p := range w.match {
+		if ok, _ := filepath.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
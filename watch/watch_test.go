@@ -0,0 +1,136 @@
+package watch
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kungfusheep/glyph"
+)
+
+func TestScan(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		os.MkdirAll(filepath.Dir(path), 0o755)
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	a := write("a.json", "{}")
+	b := write("views/b.json", "[]")
+	write("views/notes.txt", "x")
+	write(".git/HEAD", "ref")
+
+	w := Files(dir).Match("*.json")
+	if got := w.Scan(); got != nil {
+		t.Fatalf("first scan reported %q", got)
+	}
+	if got := w.Scan(); got != nil {
+		t.Fatalf("nothing changed, got %q", got)
+	}
+
+	write("a.json", `{"title": "x"}`)
+	c := write("views/c.json", "[1]")
+	write("views/notes.txt", "not matched")
+	write(".git/HEAD", "hidden")
+	if got, want := w.Scan(), []string{a, c}; !reflect.DeepEqual(got, want) {
+		t.Errorf("changed %q, want %q", got, want)
+	}
+
+	os.Remove(b)
+	if got := w.Scan(); !reflect.DeepEqual(got, []string{b}) {
+		t.Errorf("removed %q", got)
+	}
+
+	// a file named directly is watched before it exists
+	later := filepath.Join(dir, "later.yaml")
+	w = Files(later)
+	w.Scan()
+	write("later.yaml", "a: 1")
+	if got := w.Scan(); !reflect.DeepEqual(got, []string{later}) {
+		t.Errorf("created %q", got)
+	}
+}
+
+func TestReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "view.txt")
+	os.WriteFile(path, []byte("first"), 0o644)
+
+	status := "" // state kept outside build lives through reloads
+	build := func() (any, error) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		title := strings.TrimSpace(string(data))
+		if title == "panic" {
+			panic("half-edited")
+		}
+		if title == "" {
+			return nil, errors.New("empty view")
+		}
+		return glyph.VBox(glyph.Text(title), glyph.Text(&status)), nil
+	}
+
+	app, _ := glyph.NewApp()
+	be := glyph.NewBufferBackend(20, 3)
+	app.SetBackend(be)
+	t.Cleanup(app.Stop)
+
+	r, err := Files(path).Interval(5*time.Millisecond).Reload(app, build)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reported []error
+	r.OnError(func(err error) { reported = append(reported, err) })
+	status = "saved"
+
+	// renders until the line reads want, giving the poller time to notice
+	waitFor := func(want string) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for time.Now().Before(deadline) {
+			app.RenderNow()
+			if be.Frame().GetLine(0) == want {
+				return
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		t.Fatalf("line %q, want %q", be.Frame().GetLine(0), want)
+	}
+	waitFor("first")
+
+	os.WriteFile(path, []byte("second view"), 0o644)
+	waitFor("second view")
+	if got := be.Frame().GetLine(1); got != "saved" || r.Reloads() != 1 {
+		t.Errorf("status %q after %d reloads", got, r.Reloads())
+	}
+
+	// a broken build keeps the last good view up
+	os.WriteFile(path, []byte("panic"), 0o644)
+	deadline := time.Now().Add(2 * time.Second)
+	for r.Err() == nil && time.Now().Before(deadline) {
+		app.RenderNow()
+		time.Sleep(5 * time.Millisecond)
+	}
+	if r.Err() == nil || len(reported) != 1 || !strings.Contains(reported[0].Error(), "half-edited") {
+		t.Fatalf("err %v, reported %v", r.Err(), reported)
+	}
+	app.RenderNow()
+	if got := be.Frame().GetLine(0); got != "second view" {
+		t.Errorf("after failed reload %q", got)
+	}
+
+	os.WriteFile(path, []byte("fixed"), 0o644)
+	waitFor("fixed")
+	if r.Err() != nil {
+		t.Errorf("error not cleared: %v", r.Err())
+	}
+}
@@ -0,0 +1,408 @@
+package glyph
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WatchC runs a command every so often and shows its output, like
+// watch(1), highlighting the cells that changed since the run before:
+//
+//	Watch("kubectl", "get", "pods").Interval(5 * time.Second).
+//	    BindPause("p").BindInterval("+", "-").BindRefresh("r").Grow(1)
+//	Watch("sh", "-c", "df -h | grep disk")
+//
+// A header shows the command, the interval and the last run's exit code,
+// red when it failed. The next run starts an interval after the last one
+// finished, timed by the app's clock, and runs in the background so a
+// slow command doesn't hold up frames. Output is stdout and stderr
+// together, with escape sequences removed. A run still going when the app
+// stops, or the view is replaced, is killed.
+type WatchC struct {
+	ticking
+	name     string
+	args     []string
+	interval time.Duration
+	paused   bool
+	diff     bool
+	header   bool
+	layer    *Layer
+	grow     float32
+	height   int16
+
+	diffStyle   Style
+	headerStyle Style
+
+	mu      sync.Mutex
+	running bool
+	run     int                // bumped by each start and kill; a stale run's result is dropped
+	cancel  context.CancelFunc // kills the run under way
+	ready   *watchResult       // finished in the background, not yet shown
+
+	last    watchResult
+	ran     bool
+	nextRun time.Time
+	prev    [][]rune // the last output's cells, to diff the next against
+
+	declaredBindings []binding
+}
+
+// watchResult is the outcome of one run.
+type watchResult struct {
+	output string
+	code   int // -1 when the command couldn't be run
+	at     time.Time
+}
+
+// Watch creates a view of a command's output, run every two seconds. The
+// arguments are as for exec.Command; use "sh", "-c" for a pipeline.
+func Watch(name string, args ...string) *WatchC {
+	w := &WatchC{
+		name:        name,
+		args:        args,
+		interval:    2 * time.Second,
+		diff:        true,
+		header:      true,
+		layer:       NewLayer(),
+		diffStyle:   Style{Attr: AttrInverse},
+		headerStyle: Style{Attr: AttrDim},
+	}
+	w.layer.AlwaysRender = true
+	w.layer.Render = w.poll
+	return w
+}
+
+// Interval sets the time between the end of one run and the start of the
+// next.
+func (w *WatchC) Interval(d time.Duration) *WatchC {
+	w.interval = max(d, watchMinInterval)
+	return w
+}
+
+// Differences sets whether changed cells are highlighted, on by default.
+func (w *WatchC) Differences(on bool) *WatchC {
+	w.diff = on
+	return w
+}
+
+// DiffStyle sets how changed cells are highlighted, inverse by default.
+func (w *WatchC) DiffStyle(s Style) *WatchC {
+	w.diffStyle = s
+	return w
+}
+
+// Header sets whether the header line is shown, on by default.
+func (w *WatchC) Header(on bool) *WatchC {
+	w.header = on
+	return w
+}
+
+// Grow sets the flex grow factor.
+func (w *WatchC) Grow(g float32) *WatchC {
+	w.grow = g
+	return w
+}
+
+// Height sets a fixed height for the output.
+func (w *WatchC) Height(h int16) *WatchC {
+	w.height = h
+	return w
+}
+
+// Ref provides access to the component for external references.
+func (w *WatchC) Ref(f func(*WatchC)) *WatchC { f(w); return w }
+
+// BindPause registers a key that pauses and resumes the runs.
+func (w *WatchC) BindPause(key string) *WatchC {
+	w.declaredBindings = append(w.declaredBindings, binding{pattern: key, handler: w.TogglePause})
+	return w
+}
+
+// BindInterval registers keys that halve and double the interval.
+func (w *WatchC) BindInterval(faster, slower string) *WatchC {
+	w.declaredBindings = append(w.declaredBindings,
+		binding{pattern: faster, handler: func() { w.setInterval(w.interval / 2) }},
+		binding{pattern: slower, handler: func() { w.setInterval(w.interval * 2) }},
+	)
+	return w
+}
+
+// BindRefresh registers a key that runs the command now.
+func (w *WatchC) BindRefresh(key string) *WatchC {
+	w.declaredBindings = append(w.declaredBindings, binding{pattern: key, handler: w.Refresh})
+	return w
+}
+
+// BindScroll registers keys that scroll the output a line down and up.
+func (w *WatchC) BindScroll(down, up string) *WatchC {
+	w.declaredBindings = append(w.declaredBindings,
+		binding{pattern: down, handler: func() { w.layer.ScrollDown(1) }},
+		binding{pattern: up, handler: func() { w.layer.ScrollUp(1) }},
+	)
+	return w
+}
+
+func (w *WatchC) bindings() []binding { return w.declaredBindings }
+
+// watchMinInterval and watchMaxInterval bound the interval.
+const (
+	watchMinInterval = 100 * time.Millisecond
+	watchMaxInterval = time.Hour
+)
+
+func (w *WatchC) setInterval(d time.Duration) {
+	old := w.interval
+	w.interval = min(max(d, watchMinInterval), watchMaxInterval)
+	if w.ran {
+		w.nextRun = w.nextRun.Add(w.interval - old)
+	}
+}
+
+// Pause stops further runs until Resume. A run under way still finishes.
+func (w *WatchC) Pause() { w.paused = true }
+
+// Resume carries on running, at once if a run is overdue.
+func (w *WatchC) Resume() { w.paused = false }
+
+// TogglePause pauses if running and resumes if paused.
+func (w *WatchC) TogglePause() { w.paused = !w.paused }
+
+// Paused reports whether runs are paused.
+func (w *WatchC) Paused() bool { return w.paused }
+
+// Refresh runs the command on the next frame rather than waiting for the
+// interval, even while paused.
+func (w *WatchC) Refresh() {
+	w.nextRun = time.Time{}
+	if w.paused {
+		w.start()
+	}
+}
+
+// Output returns the last run's output.
+func (w *WatchC) Output() string { return w.last.output }
+
+// ExitCode returns the last run's exit code, -1 if the command couldn't be
+// run, and whether there's been a run.
+func (w *WatchC) ExitCode() (int, bool) { return w.last.code, w.ran }
+
+func (t *Template) compileWatchC(w *WatchC, parent int16, depth int) int16 {
+	t.collectUpdateHook(w)
+	t.collectClockUser(w)
+	t.collectBindings(w)
+	t.mounts = append(t.mounts, w)
+	body := LayerView(w.layer).Grow(1)
+	if w.height > 0 {
+		body = body.ViewHeight(w.height)
+	}
+	if !w.header {
+		return t.compileLayerViewC(body.Grow(w.grow), parent, depth)
+	}
+	return t.compile(VBox.Grow(w.grow)(Widget(w.measureHeader, w.renderHeader), body), parent, depth, nil, 0)
+}
+
+// poll shows a finished run and starts the next when it's due. It runs
+// from the header's measure, so the header shows the run the output does,
+// and before the output is drawn; the second call in a frame does nothing.
+func (w *WatchC) poll() {
+	w.mu.Lock()
+	ready, running := w.ready, w.running
+	w.ready = nil
+	w.mu.Unlock()
+	now := w.now()
+	if ready != nil {
+		w.show(*ready)
+		w.nextRun = now.Add(w.interval)
+	}
+	if running || w.paused {
+		return
+	}
+	if now.Before(w.nextRun) {
+		w.after(w.nextRun.Sub(now))
+		return
+	}
+	w.start()
+}
+
+// start runs the command in the background.
+func (w *WatchC) start() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.running {
+		return
+	}
+	w.running = true
+	w.run++
+	run := w.run
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	update := w.update
+	go func() {
+		cmd := exec.CommandContext(ctx, w.name, w.args...)
+		cmd.WaitDelay = time.Second // for children that keep the output open
+		out, err := cmd.CombinedOutput()
+		cancel()
+		result := watchResult{output: string(out), at: w.now()}
+		var exit *exec.ExitError
+		switch {
+		case errors.As(err, &exit):
+			result.code = exit.ExitCode()
+		case err != nil:
+			result.output, result.code = err.Error(), -1
+		}
+		w.mu.Lock()
+		if w.run != run {
+			w.mu.Unlock()
+			return
+		}
+		w.ready, w.running, w.cancel = &result, false, nil
+		w.mu.Unlock()
+		if update != nil {
+			update()
+		}
+	}()
+}
+
+// settle does nothing: a hidden Watch starts no runs, as it isn't polled,
+// and one under way may as well finish.
+func (w *WatchC) settle() {}
+
+// unmount kills the run under way, if any, so it doesn't outlive the app.
+func (w *WatchC) unmount() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.cancel != nil {
+		w.cancel()
+		w.cancel, w.running = nil, false
+		w.run++
+	}
+}
+
+// watchEscape matches terminal escape sequences, removed from output.
+var watchEscape = regexp.MustCompile(`\x1b(\[[0-9;?]*[ -/]*[@-~]|\][^\x07\x1b]*(\x07|\x1b\\)|[@-Z\\-_])`)
+
+// show draws a run's output, highlighting the cells that differ from the
+// run before.
+func (w *WatchC) show(r watchResult) {
+	first := !w.ran
+	w.last, w.ran = r, true
+	text := watchEscape.ReplaceAllString(strings.ReplaceAll(r.output, "\r\n", "\n"), "")
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	width := 1
+	for i, line := range lines {
+		lines[i] = expandTabsTo(line, func(col int) int { return col + 8 - col%8 })
+		width = max(width, StringWidth(lines[i]))
+	}
+	for _, row := range w.prev {
+		width = max(width, len(row))
+	}
+
+	buf := NewBuffer(width, len(lines))
+	for y, line := range lines {
+		buf.WriteSpans(0, y, []Span{{Text: line}}, width)
+	}
+	// cells keeps the 0 runes that follow wide characters, so a wide
+	// character replacing two narrow ones still counts as a change
+	cells := make([][]rune, len(lines))
+	for y := range cells {
+		cells[y] = make([]rune, width)
+		for x := range cells[y] {
+			cells[y][x] = buf.Get(x, y).Rune
+		}
+	}
+	if w.diff && !first {
+		for y, row := range cells {
+			for x, r := range row {
+				if r == 0 {
+					continue // styled with the character before it
+				}
+				old := ' '
+				if y < len(w.prev) && x < len(w.prev[y]) {
+					old = w.prev[y][x]
+				}
+				if r == old {
+					continue
+				}
+				end := x + 1
+				for end < width && row[end] == 0 {
+					end++
+				}
+				for i := x; i < end; i++ {
+					c := buf.Get(i, y)
+					c.Style = w.diffStyle
+					buf.SetFast(i, y, c)
+				}
+			}
+		}
+	}
+	w.prev = cells
+
+	scrollY := w.layer.ScrollY()
+	w.layer.SetBuffer(buf)
+	w.layer.ScrollTo(scrollY)
+}
+
+// command is the command as the header shows it.
+func (w *WatchC) command() string {
+	if (w.name == "sh" || w.name == "bash") && len(w.args) == 2 && w.args[0] == "-c" {
+		return w.args[1]
+	}
+	return strings.Join(append([]string{w.name}, w.args...), " ")
+}
+
+// badge returns the header's status badge and its style.
+func (w *WatchC) badge() (string, Style) {
+	switch {
+	case w.paused:
+		return " paused ", Style{FG: Black, BG: Yellow}
+	case !w.ran:
+		return " running ", Style{Attr: AttrDim}
+	case w.last.code == 0:
+		return " exit 0 ", Style{FG: Black, BG: Green}
+	case w.last.code < 0:
+		return " failed ", Style{FG: BrightWhite, BG: Red, Attr: AttrBold}
+	}
+	return " exit " + strconv.Itoa(w.last.code) + " ", Style{FG: BrightWhite, BG: Red, Attr: AttrBold}
+}
+
+func (w *WatchC) measureHeader(availW int16) (int16, int16) {
+	w.poll()
+	return availW, 1
+}
+
+func (w *WatchC) renderHeader(buf *Buffer, x, y, width, h int16) {
+	badge, badgeStyle := w.badge()
+	right := badge
+	if w.ran {
+		right = w.last.at.Format("15:04:05") + "  " + badge
+	}
+	rightX := int(x) + int(width) - StringWidth(right)
+	left := "every " + formatWatchInterval(w.interval) + ": " + w.command()
+	buf.WriteStringFast(int(x), int(y), TruncateEllipsis(left, max(rightX-int(x)-2, 0)), w.headerStyle, int(width))
+	if rightX < int(x) {
+		return
+	}
+	if w.ran {
+		buf.WriteStringFast(rightX, int(y), w.last.at.Format("15:04:05"), w.headerStyle, int(width))
+	}
+	badgeX := int(x) + int(width) - StringWidth(badge)
+	buf.WriteStringFast(badgeX, int(y), badge, badgeStyle, StringWidth(badge))
+}
+
+// formatWatchInterval shows an interval as "2s", "500ms" or "1m30s".
+func formatWatchInterval(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
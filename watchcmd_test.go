@@ -0,0 +1,178 @@
+package glyph
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	clock := NewFakeClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	path := filepath.Join(t.TempDir(), "out")
+	os.WriteFile(path, []byte("count 10\tok\n"), 0o644)
+	w := Watch("sh", "-c", "cat "+path+"; exit $(wc -l < "+path+" | tr -d ' ')").BindPause("p").BindInterval("+", "-")
+	tmpl := Build(VBox(w.Grow(1)))
+	tmpl.SetClock(clock)
+	var renders atomic.Int32 // the command's goroutine renders too
+	for _, u := range tmpl.pendingUpdates {
+		*u = func() { renders.Add(1) }
+	}
+	running := func() bool {
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		return w.running
+	}
+	frame := func() *Buffer {
+		buf := NewBuffer(40, 3)
+		tmpl.Execute(buf, 40, 3)
+		return buf
+	}
+	// run executes a frame that starts a run and one that shows it
+	run := func() *Buffer {
+		t.Helper()
+		frame()
+		for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(time.Millisecond) {
+			w.mu.Lock()
+			done := !w.running && w.ready != nil
+			w.mu.Unlock()
+			if done {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatal("command never finished")
+			}
+		}
+		return frame()
+	}
+
+	buf := run()
+	if got := buf.GetLine(1); got != "count 10        ok" {
+		t.Errorf("output = %q", got)
+	}
+	if got := buf.GetLine(0); got[len(got)-len("12:00:00   exit 1"):] != "12:00:00   exit 1" {
+		t.Errorf("header = %q", got)
+	}
+	if c := buf.Get(39, 0); c.Style.BG != Red {
+		t.Errorf("failed exit badge style = %+v", c.Style)
+	}
+	if code, ok := w.ExitCode(); code != 1 || !ok {
+		t.Errorf("ExitCode = %d, %v", code, ok)
+	}
+
+	// nothing runs until the interval is up
+	renders.Store(0)
+	if frame(); running() {
+		t.Fatal("ran again before the interval")
+	}
+	clock.Advance(2 * time.Second)
+	if n := renders.Load(); n != 1 {
+		t.Errorf("renders when the interval ran out = %d", n)
+	}
+
+	os.WriteFile(path, []byte("count 12\tok\n"), 0o644)
+	buf = run()
+	for x, want := range map[int]bool{6: false, 7: true, 8: false, 16: false} {
+		if got := buf.Get(x, 1).Style.Attr&AttrInverse != 0; got != want {
+			t.Errorf("cell %d highlighted = %v, want %v", x, got, want)
+		}
+	}
+
+	w.declaredBindings[1].handler.(func())() // faster
+	w.declaredBindings[0].handler.(func())() // pause
+	clock.Advance(time.Minute)
+	buf = frame()
+	if running() {
+		t.Error("ran while paused")
+	}
+	if got := buf.GetLine(0); got[:len("every 1s: cat")] != "every 1s: cat" || got[len(got)-len(" paused"):] != " paused" {
+		t.Errorf("paused header = %q", got)
+	}
+}
+
+func TestWatchWideChars(t *testing.T) {
+	w := Watch("true")
+	w.show(watchResult{output: "a中b 文1\n"})
+	w.show(watchResult{output: "a日b 文2\n"})
+	buf := w.layer.buffer
+	if got := buf.GetLine(0); got != "a日 b 文 2" { // GetLine shows the cell after a wide character as a space
+		t.Errorf("output = %q", got)
+	}
+	for x, want := range map[int]bool{0: false, 1: true, 2: true, 3: false, 4: false, 5: false, 6: false, 7: true} {
+		if got := buf.Get(x, 0).Style.Attr&AttrInverse != 0; got != want {
+			t.Errorf("cell %d highlighted = %v, want %v", x, got, want)
+		}
+	}
+	for _, x := range []int{2, 6} {
+		if r := buf.Get(x, 0).Rune; r != 0 {
+			t.Errorf("cell %d after a wide character = %q, want 0", x, r)
+		}
+	}
+}
+
+func TestWatchKilledOnUnmount(t *testing.T) {
+	pidFile := filepath.Join(t.TempDir(), "pid")
+	w := Watch("sh", "-c", "echo $$ > "+pidFile+"; exec sleep 30")
+	tmpl := Build(VBox(w.Grow(1)))
+	tmpl.Execute(NewBuffer(40, 3), 40, 3)
+	var pid int
+	for deadline := time.Now().Add(5 * time.Second); pid == 0; time.Sleep(time.Millisecond) {
+		if b, err := os.ReadFile(pidFile); err == nil && strings.HasSuffix(string(b), "\n") {
+			pid, _ = strconv.Atoi(strings.TrimSpace(string(b)))
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("command never started")
+		}
+	}
+
+	tmpl.unmountAll()
+	proc, _ := os.FindProcess(pid)
+	for deadline := time.Now().Add(5 * time.Second); proc.Signal(syscall.Signal(0)) == nil; time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatal("command still running after unmount")
+		}
+	}
+	time.Sleep(20 * time.Millisecond)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.running || w.ready != nil {
+		t.Errorf("after kill: running %v, ready %+v", w.running, w.ready)
+	}
+}
+
+func TestWatchCannotRun(t *testing.T) {
+	w := Watch(filepath.Join(t.TempDir(), "missing")).Header(false)
+	tmpl := Build(VBox(w.Grow(1)))
+	buf := NewBuffer(60, 2)
+	tmpl.Execute(buf, 60, 2)
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(time.Millisecond) {
+		w.mu.Lock()
+		done := w.ready != nil
+		w.mu.Unlock()
+		if done || time.Now().After(deadline) {
+			break
+		}
+	}
+	tmpl.Execute(buf, 60, 2)
+	if code, ok := w.ExitCode(); code != -1 || !ok || w.Output() == "" {
+		t.Errorf("ExitCode = %d, %v, output %q", code, ok, w.Output())
+	}
+}
+
+func TestFormatWatchInterval(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		500 * time.Millisecond: "500ms",
+		2 * time.Second:        "2s",
+		90 * time.Second:       "1m30s",
+		2 * time.Minute:        "2m",
+		time.Hour:              "1h",
+	} {
+		if got := formatWatchInterval(d); got != want {
+			t.Errorf("%v = %q, want %q", d, got, want)
+		}
+	}
+}
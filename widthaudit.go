@@ -0,0 +1,130 @@
+package glyph
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// WidthProblem is a way a row's cells and what a terminal draws for them
+// can disagree.
+type WidthProblem uint8
+
+const (
+	// WideWithoutSpacer is a double-width rune written into one cell,
+	// as WriteStringFast does, so the rest of the row draws a column to
+	// the right of where the buffer has it.
+	WideWithoutSpacer WidthProblem = iota
+	// WideAtEdge is a double-width rune in the last column, which the
+	// terminal wraps or cuts.
+	WideAtEdge
+	// ZeroWidthCell is a combining mark, joiner, variation selector or
+	// control character in a cell of its own, so the rest of the row
+	// draws a column to the left.
+	ZeroWidthCell
+	// StraySpacer is an empty spacer cell with no double-width rune
+	// before it. The screen skips it, so the row comes up short.
+	StraySpacer
+)
+
+func (p WidthProblem) String() string {
+	switch p {
+	case WideWithoutSpacer:
+		return "wide rune without a spacer cell"
+	case WideAtEdge:
+		return "wide rune at the right edge"
+	case ZeroWidthCell:
+		return "zero-width rune in its own cell"
+	case StraySpacer:
+		return "spacer cell without a wide rune"
+	}
+	return fmt.Sprintf("WidthProblem(%d)", p)
+}
+
+// WidthIssue is a cell that throws the rest of its row out of line.
+type WidthIssue struct {
+	X, Y    int
+	Rune    rune
+	Problem WidthProblem
+
+	// Component names what drew the cell, outermost first, as in
+	// "VBox > HBox > Text". Empty from AuditWidths, which only sees the
+	// buffer.
+	Component string
+}
+
+func (i WidthIssue) String() string {
+	s := fmt.Sprintf("%d,%d U+%04X: %s", i.X, i.Y, i.Rune, i.Problem)
+	if i.Component != "" {
+		s += " in " + i.Component
+	}
+	return s
+}
+
+// AuditWidths scans a rendered buffer for cells whose width doesn't match
+// what a terminal will draw, the usual cause of rows that come out ragged
+// when they hold emoji, CJK or combining characters. Text written with
+// WriteSpans, or cut with Truncate and the Pad functions, passes.
+func AuditWidths(buf *Buffer) []WidthIssue {
+	var issues []WidthIssue
+	for y := 0; y < buf.height; y++ {
+		row := buf.cells[y*buf.width : (y+1)*buf.width]
+		flag := func(x int, p WidthProblem) {
+			issues = append(issues, WidthIssue{X: x, Y: y, Rune: row[x].Rune, Problem: p})
+		}
+		for x := 0; x < len(row); x++ {
+			r := row[x].Rune
+			switch rw := runewidth.RuneWidth(r); {
+			case r == 0:
+				flag(x, StraySpacer) // a wide rune's own spacer is stepped over
+			case rw == 2 && x == len(row)-1:
+				flag(x, WideAtEdge)
+			case rw == 2 && row[x+1].Rune != 0:
+				flag(x, WideWithoutSpacer)
+			case rw == 2:
+				x++
+			case rw == 0:
+				flag(x, ZeroWidthCell)
+			}
+		}
+	}
+	return issues
+}
+
+// auditWidths audits the frame t just drew into buf, naming the
+// components at fault.
+func (t *Template) auditWidths(buf *Buffer) []WidthIssue {
+	issues := AuditWidths(buf)
+	if t.trace != nil {
+		for i := range issues {
+			issues[i].Component = t.trace.path(issues[i].X, issues[i].Y)
+		}
+	}
+	return issues
+}
+
+// widthAudit is the App's AuditWidths debug mode.
+type widthAudit struct {
+	report func([]WidthIssue)
+	last   []WidthIssue
+}
+
+var widthIssueStyle = Style{FG: BrightWhite, BG: Red}
+
+// check audits a frame, marks the cells at fault and reports the issues
+// when they've changed since the last frame.
+func (wa *widthAudit) check(t *Template, buf *Buffer) {
+	issues := t.auditWidths(buf)
+	for _, is := range issues {
+		c := Cell{Rune: is.Rune, Style: widthIssueStyle}
+		if is.Problem == ZeroWidthCell || is.Problem == StraySpacer {
+			c.Rune = '?' // something to see
+		}
+		buf.SetFast(is.X, is.Y, c)
+	}
+	if wa.report != nil && !slices.Equal(issues, wa.last) {
+		wa.report(issues)
+	}
+	wa.last = issues
+}
@@ -0,0 +1,71 @@
+package glyph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAuditWidths(t *testing.T) {
+	buf := NewBuffer(8, 4)
+	buf.WriteSpans(0, 0, []Span{{Text: "日本 ok"}}, 8) // spacers in place
+	buf.WriteStringFast(0, 1, "日本 ok", Style{}, 8)   // one cell each
+	buf.WriteStringFast(0, 2, "é x", Style{}, 8)
+	buf.SetFast(3, 3, Cell{})
+	buf.SetFast(7, 3, Cell{Rune: '日'})
+
+	type found struct {
+		x, y int
+		p    WidthProblem
+	}
+	var got []found
+	for _, is := range AuditWidths(buf) {
+		got = append(got, found{is.X, is.Y, is.Problem})
+	}
+	want := []found{
+		{0, 1, WideWithoutSpacer}, {1, 1, WideWithoutSpacer},
+		{1, 2, ZeroWidthCell},
+		{3, 3, StraySpacer}, {7, 3, WideAtEdge},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v\nwant %v", got, want)
+	}
+}
+
+func TestAppAuditWidths(t *testing.T) {
+	app, err := NewApp()
+	if err != nil {
+		t.Fatal(err)
+	}
+	be := NewBufferBackend(20, 3)
+	label := "fine"
+	var reports [][]WidthIssue
+	app.SetBackend(be).AuditWidths(func(is []WidthIssue) { reports = append(reports, is) })
+	app.SetView(VBox(Text("header"), HBox(Text("> "), Text(&label))))
+
+	app.RenderNow()
+	if len(reports) != 0 {
+		t.Fatalf("clean frame reported %v", reports)
+	}
+	label = "🚀 go"
+	app.RenderNow()
+	app.RenderNow() // unchanged, so not reported again
+	if len(reports) != 1 || len(reports[0]) != 1 {
+		t.Fatalf("reports %v", reports)
+	}
+	is := reports[0][0]
+	if is.X != 2 || is.Y != 1 || is.Rune != '🚀' || is.Component != "VBox > HBox > Text" {
+		t.Errorf("issue %v", is)
+	}
+	if c := be.Frame().Get(2, 1); c.Style != widthIssueStyle {
+		t.Errorf("cell not marked: %+v", c)
+	}
+	if got := is.String(); got != "2,1 U+1F680: wide rune without a spacer cell in VBox > HBox > Text" {
+		t.Errorf("String() = %q", got)
+	}
+
+	label = "fine again"
+	app.RenderNow()
+	if len(reports) != 2 || len(reports[1]) != 0 {
+		t.Errorf("fix not reported: %v", reports)
+	}
+}
@@ -0,0 +1,69 @@
+package work
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kungfusheep/glyph"
+)
+
+// Widget returns a live panel showing queue depth and one row per worker:
+//
+//	queued 3 · running 2 · done 14 · failed 1
+//	#1 index    ████████░░░░  66% src/app.go
+//	#2 idle
+func (p *Pool) Widget() glyph.Custom {
+	return glyph.Widget(
+		func(availW int16) (w, h int16) {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			return availW, int16(len(p.workers) + 1)
+		},
+		func(buf *glyph.Buffer, x, y, w, h int16) {
+			stats := p.Stats()
+			workers := p.Workers()
+
+			header := fmt.Sprintf("queued %d · running %d · done %d", stats.Queued, stats.Running, stats.Completed)
+			if stats.Failed > 0 {
+				header += fmt.Sprintf(" · failed %d", stats.Failed)
+			}
+			buf.WriteStringFast(int(x), int(y), header, glyph.Style{Attr: glyph.AttrBold}, int(w))
+
+			nameW := 0
+			for _, ws := range workers {
+				nameW = max(nameW, len(ws.Task))
+			}
+			nameW = min(max(nameW, 4), 16)
+			const barW = 12
+
+			for i, ws := range workers {
+				row := int(y) + 1 + i
+				if row >= int(y+h) {
+					break
+				}
+				id := fmt.Sprintf("#%-2d ", ws.ID)
+				if !ws.Busy() {
+					buf.WriteStringFast(int(x), row, id+"idle", glyph.Style{Attr: glyph.AttrDim}, int(w))
+					continue
+				}
+				filled := int(ws.Progress*barW + 0.5)
+				filled = max(min(filled, barW), 0)
+				buf.WriteSpans(int(x), row, []glyph.Span{
+					{Text: id},
+					{Text: fmt.Sprintf("%-*s ", nameW, truncate(ws.Task, nameW)), Style: glyph.Style{FG: glyph.Cyan}},
+					{Text: strings.Repeat("█", filled), Style: glyph.Style{FG: glyph.Green}},
+					{Text: strings.Repeat("░", barW-filled), Style: glyph.Style{FG: glyph.BrightBlack}},
+					{Text: fmt.Sprintf(" %3d%% ", int(ws.Progress*100))},
+					{Text: ws.Message, Style: glyph.Style{Attr: glyph.AttrDim}},
+				}, int(w))
+			}
+		},
+	)
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
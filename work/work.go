@@ -0,0 +1,380 @@
+// Package work provides a bounded worker pool for glyph apps. Tasks run on
+// background goroutines; their progress and completion callbacks are
+// delivered on the UI side via App.Post, and the pool shuts down with the
+// app.
+//
+//	pool := work.ForApp(app, 4)
+//	pool.Submit(work.NewTask("index", func(ctx context.Context, report work.Reporter) error {
+//	    for i := range files {
+//	        report(float64(i)/float64(len(files)), files[i])
+//	        ...
+//	    }
+//	    return nil
+//	}).OnDone(func(err error) { status = "indexed" }))
+//
+//	app.SetView(VBox(pool.Widget()))
+package work
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/kungfusheep/glyph"
+)
+
+// ErrClosed is returned by tasks submitted after the pool was closed.
+var ErrClosed = errors.New("work: pool closed")
+
+// Reporter is passed to a task to report progress (0..1) and a short message.
+type Reporter func(progress float64, message string)
+
+// Func is the body of a task. It should return promptly once ctx is done.
+type Func func(ctx context.Context, report Reporter) error
+
+// State is the lifecycle state of a task.
+type State uint8
+
+const (
+	Queued State = iota
+	Running
+	Done
+	Failed
+	Cancelled
+)
+
+func (s State) String() string {
+	switch s {
+	case Queued:
+		return "queued"
+	case Running:
+		return "running"
+	case Done:
+		return "done"
+	case Failed:
+		return "failed"
+	case Cancelled:
+		return "cancelled"
+	}
+	return "unknown"
+}
+
+// Task is a unit of work. Configure callbacks before submitting it.
+type Task struct {
+	name       string
+	fn         Func
+	onProgress func(progress float64, message string)
+	onDone     func(err error)
+
+	pool   atomic.Pointer[Pool] // set once, by Submit
+	cancel context.CancelFunc
+	state  State // guarded by pool.mu
+	err    error
+	done   chan struct{}
+}
+
+// NewTask creates a task named name running fn.
+func NewTask(name string, fn Func) *Task {
+	return &Task{name: name, fn: fn, done: make(chan struct{})}
+}
+
+// OnProgress sets a callback for progress reports, run on the UI side.
+func (t *Task) OnProgress(fn func(progress float64, message string)) *Task {
+	t.onProgress = fn
+	return t
+}
+
+// OnDone sets a callback for completion, run on the UI side. err is nil on
+// success and context.Canceled if the task was cancelled.
+func (t *Task) OnDone(fn func(err error)) *Task {
+	t.onDone = fn
+	return t
+}
+
+// Name returns the task name.
+func (t *Task) Name() string { return t.name }
+
+// State returns the current lifecycle state. A task not yet submitted is
+// Queued.
+func (t *Task) State() State {
+	p := t.pool.Load()
+	if p == nil {
+		return Queued
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return t.state
+}
+
+// Cancel cancels the task. A queued task never starts; a running task sees
+// its context cancelled. Cancelling a task not yet submitted does nothing.
+func (t *Task) Cancel() {
+	p := t.pool.Load()
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	switch t.state {
+	case Queued:
+		p.removeQueued(t)
+		p.finish(t, Cancelled, context.Canceled)
+		p.cond.Broadcast()
+	case Running:
+		t.cancel()
+	}
+	p.unlock()
+}
+
+// Wait blocks until the task has finished and returns its error.
+func (t *Task) Wait() error {
+	<-t.done
+	return t.err
+}
+
+// WorkerStatus is a snapshot of one worker.
+type WorkerStatus struct {
+	ID       int
+	Task     string // empty when idle
+	Progress float64
+	Message  string
+}
+
+// Busy reports whether the worker is running a task.
+func (w WorkerStatus) Busy() bool { return w.Task != "" }
+
+// Stats is a snapshot of the pool.
+type Stats struct {
+	Queued    int
+	Running   int
+	Completed int
+	Failed    int
+	Cancelled int
+}
+
+// Pool runs tasks on a fixed number of workers.
+type Pool struct {
+	ctx      context.Context
+	cancel   context.CancelFunc
+	post     func(func())
+	onChange func() // status changed (for RequestRender)
+
+	mu        sync.Mutex
+	cond      *sync.Cond
+	queue     []*Task
+	workers   []WorkerStatus
+	stats     Stats
+	closed    bool
+	callbacks []func() // queued under mu, posted after unlock
+	wg        sync.WaitGroup
+}
+
+// New creates a pool of n workers whose tasks are cancelled when ctx is.
+// Callbacks run directly on the worker goroutine; use OnUI or ForApp to
+// marshal them elsewhere.
+func New(ctx context.Context, n int) *Pool {
+	n = max(n, 1)
+	ctx, cancel := context.WithCancel(ctx)
+	p := &Pool{
+		ctx:     ctx,
+		cancel:  cancel,
+		post:    func(fn func()) { fn() },
+		workers: make([]WorkerStatus, n),
+	}
+	p.cond = sync.NewCond(&p.mu)
+	for i := range p.workers {
+		p.workers[i].ID = i + 1
+		p.wg.Add(1)
+		go p.work(i)
+	}
+	// wake idle workers so they exit when the context ends
+	go func() {
+		<-ctx.Done()
+		p.mu.Lock()
+		p.closed = true
+		p.cond.Broadcast()
+		p.mu.Unlock()
+	}()
+	return p
+}
+
+// ForApp creates a pool of n workers tied to app: callbacks run via
+// app.Post, every status change requests a render, and App.Stop cancels
+// all work.
+func ForApp(app *glyph.App, n int) *Pool {
+	return New(app.Context(), n).OnUI(app.Post).OnChange(app.RequestRender)
+}
+
+// OnUI sets how callbacks are marshaled to the UI, e.g. app.Post.
+func (p *Pool) OnUI(post func(func())) *Pool {
+	p.post = post
+	return p
+}
+
+// OnChange sets a function called whenever queue or worker status changes,
+// e.g. app.RequestRender. It must not block.
+func (p *Pool) OnChange(fn func()) *Pool {
+	p.onChange = fn
+	return p
+}
+
+// Submit queues t and returns it. Tasks submitted after Close fail with
+// ErrClosed. A task runs once: submitting it again, to this pool or
+// another, is ignored.
+func (p *Pool) Submit(t *Task) *Task {
+	if !t.pool.CompareAndSwap(nil, p) {
+		return t
+	}
+	p.mu.Lock()
+	if p.closed {
+		p.finish(t, Failed, ErrClosed)
+	} else {
+		t.state = Queued
+		p.queue = append(p.queue, t)
+		p.stats.Queued++
+		p.cond.Signal()
+		p.changed()
+	}
+	p.unlock()
+	return t
+}
+
+// Go submits a task with no callbacks.
+func (p *Pool) Go(name string, fn Func) *Task {
+	return p.Submit(NewTask(name, fn))
+}
+
+// Stats returns a snapshot of queue and completion counts.
+func (p *Pool) Stats() Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.stats
+}
+
+// Workers returns a snapshot of every worker.
+func (p *Pool) Workers() []WorkerStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	out := make([]WorkerStatus, len(p.workers))
+	copy(out, p.workers)
+	return out
+}
+
+// Close cancels queued and running tasks and waits for the workers to exit.
+func (p *Pool) Close() {
+	p.cancel()
+	p.wg.Wait()
+}
+
+// Wait blocks until the queue is empty and every worker is idle.
+func (p *Pool) Wait() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for len(p.queue) > 0 || p.stats.Running > 0 {
+		p.cond.Wait()
+	}
+}
+
+func (p *Pool) work(i int) {
+	defer p.wg.Done()
+	for {
+		p.mu.Lock()
+		for len(p.queue) == 0 && !p.closed {
+			p.cond.Wait()
+		}
+		if p.closed {
+			// drain anything left so waiters are released
+			for _, t := range p.queue {
+				p.stats.Queued--
+				p.finish(t, Cancelled, context.Canceled)
+			}
+			p.queue = nil
+			p.cond.Broadcast()
+			p.unlock()
+			return
+		}
+		t := p.queue[0]
+		p.queue = p.queue[1:]
+		p.stats.Queued--
+		p.stats.Running++
+		t.state = Running
+		ctx, cancel := context.WithCancel(p.ctx)
+		t.cancel = cancel
+		p.workers[i] = WorkerStatus{ID: i + 1, Task: t.name}
+		p.changed()
+		p.mu.Unlock()
+
+		err := t.fn(ctx, func(progress float64, message string) {
+			p.mu.Lock()
+			p.workers[i].Progress = progress
+			p.workers[i].Message = message
+			if t.onProgress != nil {
+				p.callbacks = append(p.callbacks, func() { t.onProgress(progress, message) })
+			}
+			p.changed()
+			p.unlock()
+		})
+		cancel()
+
+		p.mu.Lock()
+		p.stats.Running--
+		p.workers[i] = WorkerStatus{ID: i + 1}
+		switch {
+		case err == nil:
+			p.finish(t, Done, nil)
+		case errors.Is(err, context.Canceled):
+			p.finish(t, Cancelled, err)
+		default:
+			p.finish(t, Failed, err)
+		}
+		p.cond.Broadcast()
+		p.unlock()
+	}
+}
+
+// finish records the outcome of t. Called with mu held.
+func (p *Pool) finish(t *Task, s State, err error) {
+	t.state = s
+	t.err = err
+	switch s {
+	case Done:
+		p.stats.Completed++
+	case Failed:
+		p.stats.Failed++
+	case Cancelled:
+		p.stats.Cancelled++
+	}
+	close(t.done)
+	if t.onDone != nil {
+		p.callbacks = append(p.callbacks, func() { t.onDone(err) })
+	}
+	p.changed()
+}
+
+// removeQueued drops t from the queue. Called with mu held.
+func (p *Pool) removeQueued(t *Task) {
+	for i, q := range p.queue {
+		if q == t {
+			p.queue = append(p.queue[:i], p.queue[i+1:]...)
+			p.stats.Queued--
+			return
+		}
+	}
+}
+
+// unlock releases mu and then hands queued callbacks to the UI, so user
+// callbacks never run with the pool locked.
+func (p *Pool) unlock() {
+	fns := p.callbacks
+	p.callbacks = nil
+	p.mu.Unlock()
+	for _, fn := range fns {
+		p.post(fn)
+	}
+}
+
+func (p *Pool) changed() {
+	if p.onChange != nil {
+		p.onChange()
+	}
+}
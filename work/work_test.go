@@ -0,0 +1,209 @@
+package work
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kungfusheep/glyph"
+)
+
+func TestPoolRunsTasks(t *testing.T) {
+	p := New(context.Background(), 2)
+	defer p.Close()
+
+	var mu sync.Mutex
+	var progress []float64
+	var doneErr error = errors.New("unset")
+	task := p.Submit(NewTask("count", func(ctx context.Context, report Reporter) error {
+		for i := 1; i <= 4; i++ {
+			report(float64(i)/4, "step")
+		}
+		return nil
+	}).OnProgress(func(f float64, _ string) {
+		mu.Lock()
+		progress = append(progress, f)
+		mu.Unlock()
+	}).OnDone(func(err error) { doneErr = err }))
+
+	if err := task.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	p.Wait()
+	if task.State() != Done {
+		t.Errorf("expected done, got %v", task.State())
+	}
+	if len(progress) != 4 || progress[3] != 1 {
+		t.Errorf("unexpected progress reports %v", progress)
+	}
+	if doneErr != nil {
+		t.Errorf("expected OnDone(nil), got %v", doneErr)
+	}
+	if s := p.Stats(); s.Completed != 1 || s.Queued != 0 || s.Running != 0 {
+		t.Errorf("unexpected stats %+v", s)
+	}
+}
+
+func TestPoolBoundsConcurrency(t *testing.T) {
+	p := New(context.Background(), 2)
+	defer p.Close()
+
+	release := make(chan struct{})
+	started := make(chan struct{}, 3)
+	for range 3 {
+		p.Go("block", func(ctx context.Context, _ Reporter) error {
+			started <- struct{}{}
+			<-release
+			return nil
+		})
+	}
+	<-started
+	<-started
+	if s := p.Stats(); s.Running != 2 || s.Queued != 1 {
+		t.Errorf("expected 2 running and 1 queued, got %+v", s)
+	}
+	busy := 0
+	for _, w := range p.Workers() {
+		if w.Busy() {
+			busy++
+		}
+	}
+	if busy != 2 {
+		t.Errorf("expected 2 busy workers, got %d", busy)
+	}
+	close(release)
+	p.Wait()
+}
+
+func TestPoolCancellation(t *testing.T) {
+	ctx, stop := context.WithCancel(context.Background())
+	p := New(ctx, 1)
+
+	running := make(chan struct{})
+	first := p.Go("wait", func(ctx context.Context, _ Reporter) error {
+		close(running)
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	queued := p.Go("never", func(context.Context, Reporter) error {
+		t.Error("queued task should not run")
+		return nil
+	})
+	<-running
+
+	queued.Cancel()
+	if queued.State() != Cancelled {
+		t.Errorf("expected queued task cancelled, got %v", queued.State())
+	}
+
+	stop() // like App.Stop
+	if err := first.Wait(); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+	p.Close()
+
+	late := p.Go("late", func(context.Context, Reporter) error { return nil })
+	if err := late.Wait(); !errors.Is(err, ErrClosed) {
+		t.Errorf("expected ErrClosed, got %v", err)
+	}
+}
+
+func TestTaskUnsubmittedAndResubmitted(t *testing.T) {
+	runs := 0
+	task := NewTask("once", func(context.Context, Reporter) error {
+		runs++
+		return nil
+	})
+	if task.State() != Queued {
+		t.Errorf("unsubmitted state = %v, want Queued", task.State())
+	}
+	task.Cancel() // no pool yet: nothing to do
+
+	p := New(context.Background(), 1)
+	defer p.Close()
+	p.Submit(task)
+	p.Submit(task)
+	if err := task.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	p.Wait()
+	if runs != 1 {
+		t.Errorf("runs = %d, want 1", runs)
+	}
+}
+
+func TestTaskStateWhileSubmitting(t *testing.T) {
+	// run with -race: State reads the pool as another goroutine submits
+	p := New(context.Background(), 1)
+	defer p.Close()
+	task := NewTask("raced", func(context.Context, Reporter) error { return nil })
+	submitted := make(chan struct{})
+	go func() {
+		p.Submit(task)
+		close(submitted)
+	}()
+	task.State()
+	<-submitted
+	if err := task.Wait(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPoolCallbacksMarshaled(t *testing.T) {
+	var posted []func()
+	var mu sync.Mutex
+	p := New(context.Background(), 1).OnUI(func(fn func()) {
+		mu.Lock()
+		posted = append(posted, fn)
+		mu.Unlock()
+	})
+	defer p.Close()
+
+	called := false
+	p.Submit(NewTask("x", func(context.Context, Reporter) error { return nil }).
+		OnDone(func(error) { called = true })).Wait()
+	p.Wait()
+	if called {
+		t.Fatal("OnDone should wait for the UI to run it")
+	}
+	mu.Lock()
+	for _, fn := range posted {
+		fn()
+	}
+	mu.Unlock()
+	if !called {
+		t.Error("expected OnDone after running posted callbacks")
+	}
+}
+
+func TestWidgetRendersWorkers(t *testing.T) {
+	p := New(context.Background(), 2)
+	defer p.Close()
+
+	release := make(chan struct{})
+	reported := make(chan struct{})
+	p.Go("build", func(ctx context.Context, report Reporter) error {
+		report(0.5, "compiling")
+		close(reported)
+		<-release
+		return nil
+	})
+	<-reported
+
+	tmpl := glyph.Build(glyph.VBox(p.Widget()))
+	buf := glyph.NewBuffer(50, 3)
+	tmpl.Execute(buf, 50, 3)
+	close(release)
+
+	if got := buf.GetLine(0); !strings.HasPrefix(got, "queued 0 · running 1") {
+		t.Errorf("unexpected header %q", got)
+	}
+	if got := buf.GetLine(1); !strings.Contains(got, "build") || !strings.Contains(got, "50%") || !strings.Contains(got, "compiling") {
+		t.Errorf("unexpected worker row %q", got)
+	}
+	if got := buf.GetLine(2); !strings.Contains(got, "idle") {
+		t.Errorf("expected idle worker, got %q", got)
+	}
+}
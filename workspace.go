@@ -0,0 +1,299 @@
+package glyph
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/kungfusheep/riffkey"
+	"github.com/mattn/go-runewidth"
+)
+
+// Workspace is one named layout of a Workspaces set.
+type Workspace struct {
+	Name    string
+	Content any
+}
+
+// WorkspacesC holds several named layouts and shows one at a time, like
+// tmux windows. Each keeps its own split tree, focus and keys, so
+// switching away and back finds a workspace as it was left. Only the
+// shown workspace's keys are live; what it doesn't use falls through to
+// the view's own bindings.
+//
+//	ws := Workspaces(
+//	    Workspace{"edit", SplitPane(Pane("files", tree), Pane("editor", editor).Grow(3))},
+//	    Workspace{"logs", SplitPane(Pane("app", appLog), Pane("db", dbLog)).Vertical()},
+//	).BindNext("<C-n>").BindPrev("<C-p>").BindIndex("<A-%d>")
+//
+//	VBox(ws.Bar(), ws)
+type WorkspacesC struct {
+	spaces  []Workspace
+	current int
+
+	subs   []*Template
+	inputs []*riffkey.Input
+	layer  *Layer
+	tmpl   *Template
+	grow   float32
+
+	onSwitch    func(i int)
+	navBindings []binding
+	router      *riffkey.Router
+	nav         *riffkey.Input
+	base        *riffkey.Input
+}
+
+// Workspaces creates a set of workspaces, showing the first.
+func Workspaces(spaces ...Workspace) *WorkspacesC {
+	ws := &WorkspacesC{spaces: spaces, layer: NewLayer(), grow: 1}
+	ws.layer.AlwaysRender = true
+	ws.layer.Render = ws.sync
+	return ws
+}
+
+// Grow sets the flex grow factor. 1 by default.
+func (ws *WorkspacesC) Grow(g float32) *WorkspacesC {
+	ws.grow = g
+	return ws
+}
+
+// OnSwitch calls fn with the index of each workspace switched to.
+func (ws *WorkspacesC) OnSwitch(fn func(i int)) *WorkspacesC {
+	ws.onSwitch = fn
+	return ws
+}
+
+// BindNext binds a key that switches to the next workspace, wrapping.
+func (ws *WorkspacesC) BindNext(key string) *WorkspacesC {
+	ws.navBindings = append(ws.navBindings, binding{key, ws.Next})
+	return ws
+}
+
+// BindPrev binds a key that switches to the previous workspace, wrapping.
+func (ws *WorkspacesC) BindPrev(key string) *WorkspacesC {
+	ws.navBindings = append(ws.navBindings, binding{key, ws.Prev})
+	return ws
+}
+
+// BindIndex binds the first nine workspaces to pattern with %d replaced
+// by 1 to 9, so "<A-%d>" binds <A-1> to the first.
+func (ws *WorkspacesC) BindIndex(pattern string) *WorkspacesC {
+	for i := range min(len(ws.spaces), 9) {
+		key := strings.ReplaceAll(pattern, "%d", strconv.Itoa(i+1))
+		ws.navBindings = append(ws.navBindings, binding{key, func() { ws.Switch(i) }})
+	}
+	return ws
+}
+
+// Current returns the index of the shown workspace.
+func (ws *WorkspacesC) Current() int { return ws.current }
+
+// Name returns the name of the shown workspace.
+func (ws *WorkspacesC) Name() string {
+	if ws.current < len(ws.spaces) {
+		return ws.spaces[ws.current].Name
+	}
+	return ""
+}
+
+// Switch shows workspace i. Out of range does nothing.
+func (ws *WorkspacesC) Switch(i int) {
+	if i < 0 || i >= len(ws.spaces) || i == ws.current {
+		return
+	}
+	ws.current = i
+	if ws.onSwitch != nil {
+		ws.onSwitch(i)
+	}
+}
+
+// SwitchTo shows the workspace called name.
+func (ws *WorkspacesC) SwitchTo(name string) {
+	for i, s := range ws.spaces {
+		if s.Name == name {
+			ws.Switch(i)
+			return
+		}
+	}
+}
+
+// Next shows the next workspace, wrapping.
+func (ws *WorkspacesC) Next() {
+	if n := len(ws.spaces); n > 0 {
+		ws.Switch((ws.current + 1) % n)
+	}
+}
+
+// Prev shows the previous workspace, wrapping.
+func (ws *WorkspacesC) Prev() {
+	if n := len(ws.spaces); n > 0 {
+		ws.Switch((ws.current - 1 + n) % n)
+	}
+}
+
+func (t *Template) compileWorkspacesC(ws *WorkspacesC, parent int16, depth int) int16 {
+	ws.tmpl = t
+	ws.subs = ws.subs[:0]
+	for _, s := range ws.spaces {
+		sub := newSubTemplate(VBox(s.Content), nil, 0)
+		t.adoptLive(sub)
+		ws.subs = append(ws.subs, sub)
+	}
+	return t.compileLayerViewC(LayerView(ws.layer).Grow(ws.grow), parent, depth)
+}
+
+func (ws *WorkspacesC) sync() {
+	w := ws.layer.ViewportWidth()
+	h := ws.layer.ViewportHeight()
+	if w <= 0 || h <= 0 || ws.current >= len(ws.subs) {
+		return
+	}
+	buf := ws.layer.Buffer()
+	if buf == nil || buf.Width() != w || buf.Height() != h {
+		buf = NewBuffer(w, h)
+		ws.layer.SetBuffer(buf)
+	} else {
+		buf.Clear()
+	}
+	sub := ws.subs[ws.current]
+	if ws.tmpl != nil {
+		sub.app = ws.tmpl.app
+	}
+	sub.distributeWidths(int16(w), nil)
+	sub.layout(int16(h))
+	sub.distributeFlexGrow(int16(h))
+	sub.clipMaxY = int16(h)
+	sub.render(buf, 0, 0, int16(w))
+	sub.renderOverlays(buf, int16(w), int16(h))
+}
+
+// attachRouter gives each workspace an input of its own keys, over which
+// any splits inside it push their routers, and pushes one router that
+// sends keys to the shown workspace's.
+func (ws *WorkspacesC) attachRouter(base *riffkey.Router, push func(*riffkey.Router)) {
+	ws.base = riffkey.NewInput(base)
+
+	nav := riffkey.NewRouter().NoCounts()
+	for _, b := range ws.navBindings {
+		routeBinding(nav, b, func() {})
+	}
+	ws.nav = riffkey.NewInput(nav)
+
+	ws.inputs = ws.inputs[:0]
+	for _, sub := range ws.subs {
+		r := riffkey.NewRouter().NoCounts()
+		for _, b := range sub.pendingBindings {
+			routeBinding(r, b, func() {})
+		}
+		var text func(riffkey.Key) bool
+		if tib := sub.pendingTIB; tib != nil {
+			th := riffkey.NewTextHandler(tib.value, tib.cursor)
+			th.OnChange = tib.onChange
+			text = th.HandleKey
+		} else {
+			text = sub.pendingKeyHandler
+		}
+		r.HandleUnmatched(func(k riffkey.Key) bool {
+			if text != nil && text(k) {
+				return true
+			}
+			return ws.base.Dispatch(k)
+		})
+		in := riffkey.NewInput(r)
+		for _, s := range sub.pendingScopes {
+			s.attachRouter(r, in.Push)
+		}
+		ws.inputs = append(ws.inputs, in)
+	}
+
+	if ws.router == nil {
+		ws.router = riffkey.NewRouter().NoCounts()
+		ws.router.HandleUnmatched(ws.dispatch)
+		if push != nil {
+			push(ws.router)
+		}
+	}
+}
+
+// dispatch routes one key: the workspace keys first, then the shown
+// workspace, which falls back to the view.
+func (ws *WorkspacesC) dispatch(k riffkey.Key) bool {
+	if ws.nav != nil && ws.nav.Dispatch(k) {
+		return true
+	}
+	if ws.current < len(ws.inputs) {
+		return ws.inputs[ws.current].Dispatch(k)
+	}
+	return ws.base != nil && ws.base.Dispatch(k)
+}
+
+// WorkspaceBarC shows the workspaces of a set in a row, the current one
+// highlighted.
+type WorkspaceBarC struct {
+	ws          *WorkspacesC
+	style       Style
+	activeStyle Style
+	numbered    bool
+}
+
+// Bar returns an indicator of ws's workspaces, to place anywhere in the
+// view:
+//
+//	1 edit  2 logs  3 db
+func (ws *WorkspacesC) Bar() *WorkspaceBarC {
+	return &WorkspaceBarC{ws: ws, activeStyle: Style{Attr: AttrInverse}, numbered: true}
+}
+
+// Style sets the style of the bar and the workspaces not shown.
+func (b *WorkspaceBarC) Style(s Style) *WorkspaceBarC {
+	b.style = s
+	return b
+}
+
+// ActiveStyle sets the style of the current workspace. Inverse by default.
+func (b *WorkspaceBarC) ActiveStyle(s Style) *WorkspaceBarC {
+	b.activeStyle = s
+	return b
+}
+
+// Numbered sets whether names are preceded by their number, as BindIndex
+// uses. On by default.
+func (b *WorkspaceBarC) Numbered(on bool) *WorkspaceBarC {
+	b.numbered = on
+	return b
+}
+
+func (b *WorkspaceBarC) label(i int) string {
+	if b.numbered {
+		return " " + strconv.Itoa(i+1) + " " + b.ws.spaces[i].Name + " "
+	}
+	return " " + b.ws.spaces[i].Name + " "
+}
+
+func (t *Template) compileWorkspaceBarC(b *WorkspaceBarC, parent int16, depth int) int16 {
+	measure := func(availW int16) (int16, int16) {
+		if availW < 0 {
+			w := 0
+			for i := range b.ws.spaces {
+				w += runewidth.StringWidth(b.label(i))
+			}
+			return int16(w), 1
+		}
+		return -1, 1
+	}
+	return t.compileCustom(Widget(measure, b.render), parent, depth)
+}
+
+func (b *WorkspaceBarC) render(buf *Buffer, x, y, w, h int16) {
+	buf.FillRect(int(x), int(y), int(w), 1, Cell{Rune: ' ', Style: b.style})
+	cx := x
+	for i := range b.ws.spaces {
+		style := b.style
+		if i == b.ws.current {
+			style = b.activeStyle
+		}
+		label := b.label(i)
+		buf.WriteStringFast(int(cx), int(y), label, style, int(max(x+w-cx, 0)))
+		cx += int16(runewidth.StringWidth(label))
+	}
+}
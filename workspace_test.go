@@ -0,0 +1,116 @@
+package glyph
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kungfusheep/riffkey"
+)
+
+func TestWorkspacesSwitch(t *testing.T) {
+	var switched []int
+	ws := Workspaces(
+		Workspace{"edit", Text("editing")},
+		Workspace{"logs", Text("logging")},
+		Workspace{"db", Text("querying")},
+	).OnSwitch(func(i int) { switched = append(switched, i) })
+	tmpl := Build(VBox(ws))
+
+	if got := messageLines(tmpl, 10, 1)[0]; got != "editing" {
+		t.Errorf("first workspace shows %q", got)
+	}
+
+	ws.Prev()
+	if ws.Current() != 2 || ws.Name() != "db" {
+		t.Errorf("Prev from first = %d %q, want 2 db", ws.Current(), ws.Name())
+	}
+	ws.Next()
+	if ws.Current() != 0 {
+		t.Errorf("Next from last = %d, want 0", ws.Current())
+	}
+	ws.SwitchTo("logs")
+	if got := messageLines(tmpl, 10, 1)[0]; got != "logging" {
+		t.Errorf("after SwitchTo shows %q", got)
+	}
+	ws.Switch(7)
+	ws.Switch(1) // already shown
+	if ws.Current() != 1 {
+		t.Errorf("out of range switch moved to %d", ws.Current())
+	}
+	if want := []int{2, 0, 1}; len(switched) != 3 || switched[0] != 2 || switched[1] != 0 || switched[2] != 1 {
+		t.Errorf("OnSwitch saw %v, want %v", switched, want)
+	}
+}
+
+func TestWorkspacesRouting(t *testing.T) {
+	var a, b bool
+	ws := Workspaces(
+		Workspace{"a", Checkbox(&a, "a").BindToggle("x")},
+		Workspace{"b", Checkbox(&b, "b").BindToggle("x")},
+	).BindNext("<C-n>").BindIndex("<A-%d>")
+	tmpl := Build(VBox(ws))
+	messageLines(tmpl, 10, 2)
+
+	if len(tmpl.pendingScopes) != 1 {
+		t.Fatalf("workspaces not collected for router wiring")
+	}
+	for _, bd := range tmpl.pendingBindings {
+		if bd.pattern == "x" {
+			t.Errorf("workspace binding leaked into the view's bindings")
+		}
+	}
+
+	var got []string
+	base := riffkey.NewRouter()
+	base.Handle("q", func(riffkey.Match) { got = append(got, "view q") })
+	var pushed *riffkey.Router
+	ws.attachRouter(base, func(r *riffkey.Router) { pushed = r })
+	in := riffkey.NewInput(pushed)
+	x := riffkey.Key{Rune: 'x'}
+
+	in.Dispatch(x)
+	if !a || b {
+		t.Errorf("x on the first workspace: a=%v b=%v", a, b)
+	}
+	in.Dispatch(riffkey.Key{Rune: 'n', Mod: riffkey.ModCtrl})
+	in.Dispatch(x)
+	if !a || !b {
+		t.Errorf("x on the second workspace: a=%v b=%v", a, b)
+	}
+	in.Dispatch(riffkey.Key{Rune: '1', Mod: riffkey.ModAlt})
+	if ws.Current() != 0 {
+		t.Errorf("<A-1> switched to %d", ws.Current())
+	}
+	in.Dispatch(riffkey.Key{Rune: 'q'})
+	if strings.Join(got, ",") != "view q" {
+		t.Errorf("unused keys should reach the view: %q", got)
+	}
+}
+
+func TestWorkspaceBar(t *testing.T) {
+	ws := Workspaces(
+		Workspace{"edit", Text("")},
+		Workspace{"logs", Text("")},
+	)
+	ws.Switch(1)
+	tmpl := Build(VBox(ws.Bar(), ws))
+	buf := NewBuffer(20, 2)
+	tmpl.Execute(buf, 20, 2)
+
+	if got := buf.GetLine(0); got != " 1 edit  2 logs" {
+		t.Errorf("bar = %q", got)
+	}
+	if c := buf.Get(9, 0); c.Style.Attr&AttrInverse == 0 || c.Rune != '2' {
+		t.Errorf("current workspace not highlighted: %q %+v", c.Rune, c.Style)
+	}
+	if c := buf.Get(1, 0); c.Style.Attr&AttrInverse != 0 {
+		t.Errorf("other workspace highlighted")
+	}
+
+	tmpl = Build(VBox(ws.Bar().Numbered(false), ws))
+	buf = NewBuffer(20, 2)
+	tmpl.Execute(buf, 20, 2)
+	if got := buf.GetLine(0); got != " edit  logs" {
+		t.Errorf("unnumbered bar = %q", got)
+	}
+}
@@ -0,0 +1,414 @@
+package glyph
+
+import (
+	"math"
+)
+
+// MapMarker is a point plotted on a WorldMap.
+type MapMarker struct {
+	Lat, Lon float64
+	Label    string // drawn after the marker when there's room
+	Rune     rune   // '●' if zero
+	Style    Style
+}
+
+// MapRegion is the part of the world a map shows, in degrees.
+type MapRegion struct {
+	West, South, East, North float64
+}
+
+// Map regions to zoom to.
+var (
+	RegionWorld        = MapRegion{-180, -60, 180, 84}
+	RegionNorthAmerica = MapRegion{-170, 7, -50, 75}
+	RegionSouthAmerica = MapRegion{-85, -57, -33, 14}
+	RegionEurope       = MapRegion{-25, 34, 45, 72}
+	RegionAfrica       = MapRegion{-20, -36, 55, 38}
+	RegionAsia         = MapRegion{25, -12, 150, 78}
+	RegionOceania      = MapRegion{110, -50, 180, 0}
+)
+
+// WorldMapC plots markers on an outline of the world drawn in braille
+// dots, for dashboards of servers, regions or users:
+//
+//	WorldMap(&markers).
+//	    BindZoom("+", "-").
+//	    BindPan("h", "l", "k", "j").
+//	    BindRegion("1", RegionWorld).
+//	    BindRegion("2", RegionEurope).
+//	    Grow(1)
+//
+// Markers are read each frame, so updating the slice moves them. The
+// outline is coarse, a few hundred points, to stay legible at terminal
+// resolution; the map keeps the world's proportions, so a region may show
+// more than asked for on one axis.
+type WorldMapC struct {
+	layer   *Layer
+	markers *[]MapMarker
+	region  MapRegion
+
+	coastStyle Style
+
+	grow   float32
+	height int16
+
+	declaredBindings []binding
+}
+
+// WorldMap creates a map of the world plotting markers.
+func WorldMap(markers *[]MapMarker) *WorldMapC {
+	m := &WorldMapC{
+		layer:      NewLayer(),
+		markers:    markers,
+		region:     RegionWorld,
+		coastStyle: Style{FG: Green},
+	}
+	m.layer.AlwaysRender = true
+	m.layer.Render = m.sync
+	return m
+}
+
+// Ref provides access to the component for external references.
+func (m *WorldMapC) Ref(f func(*WorldMapC)) *WorldMapC { f(m); return m }
+
+// Region sets the part of the world shown.
+func (m *WorldMapC) Region(r MapRegion) *WorldMapC {
+	m.View(r)
+	return m
+}
+
+// CoastStyle sets the style of the coastlines.
+func (m *WorldMapC) CoastStyle(s Style) *WorldMapC {
+	m.coastStyle = s
+	return m
+}
+
+// Grow sets the flex grow factor.
+func (m *WorldMapC) Grow(g float32) *WorldMapC {
+	m.grow = g
+	return m
+}
+
+// Height sets a fixed viewport height.
+func (m *WorldMapC) Height(h int16) *WorldMapC {
+	m.height = h
+	return m
+}
+
+// BindZoom registers keys that zoom in and out by half around the middle.
+func (m *WorldMapC) BindZoom(in, out string) *WorldMapC {
+	m.declaredBindings = append(m.declaredBindings,
+		binding{pattern: in, handler: func() { m.Zoom(2) }},
+		binding{pattern: out, handler: func() { m.Zoom(0.5) }},
+	)
+	return m
+}
+
+// BindPan registers keys that move the view a quarter of its size.
+func (m *WorldMapC) BindPan(left, right, up, down string) *WorldMapC {
+	m.declaredBindings = append(m.declaredBindings,
+		binding{pattern: left, handler: func() { m.Pan(-0.25, 0) }},
+		binding{pattern: right, handler: func() { m.Pan(0.25, 0) }},
+		binding{pattern: up, handler: func() { m.Pan(0, 0.25) }},
+		binding{pattern: down, handler: func() { m.Pan(0, -0.25) }},
+	)
+	return m
+}
+
+// BindRegion registers a key that shows a region.
+func (m *WorldMapC) BindRegion(key string, r MapRegion) *WorldMapC {
+	m.declaredBindings = append(m.declaredBindings, binding{pattern: key, handler: func() { m.View(r) }})
+	return m
+}
+
+func (m *WorldMapC) bindings() []binding { return m.declaredBindings }
+
+// View shows a region, kept within the world.
+func (m *WorldMapC) View(r MapRegion) {
+	w := min(r.East-r.West, 360)
+	h := min(r.North-r.South, 180)
+	west := max(min(r.West, 180-w), -180)
+	south := max(min(r.South, 90-h), -90)
+	m.region = MapRegion{west, south, west + w, south + h}
+}
+
+// Viewed returns the region shown.
+func (m *WorldMapC) Viewed() MapRegion { return m.region }
+
+// Zoom scales the view about its middle: 2 shows half as much.
+func (m *WorldMapC) Zoom(factor float64) {
+	r := m.region
+	cx, cy := (r.West+r.East)/2, (r.South+r.North)/2
+	w, h := (r.East-r.West)/factor/2, (r.North-r.South)/factor/2
+	m.View(MapRegion{cx - w, cy - h, cx + w, cy + h})
+}
+
+// Pan moves the view by fractions of its width and height, east and north
+// positive.
+func (m *WorldMapC) Pan(dx, dy float64) {
+	r := m.region
+	x, y := dx*(r.East-r.West), dy*(r.North-r.South)
+	m.View(MapRegion{r.West + x, r.South + y, r.East + x, r.North + y})
+}
+
+// mapProjection places degrees in dots, equirectangular, with the same
+// scale on both axes and the region centered.
+type mapProjection struct {
+	scale       float64
+	west, north float64
+	offX, offY  float64
+}
+
+func newMapProjection(r MapRegion, dotsW, dotsH int) mapProjection {
+	scale := min(float64(dotsW)/(r.East-r.West), float64(dotsH)/(r.North-r.South))
+	return mapProjection{
+		scale: scale,
+		west:  r.West,
+		north: r.North,
+		offX:  (float64(dotsW) - (r.East-r.West)*scale) / 2,
+		offY:  (float64(dotsH) - (r.North-r.South)*scale) / 2,
+	}
+}
+
+func (p mapProjection) dot(lon, lat float64) (float64, float64) {
+	return p.offX + (lon-p.west)*p.scale, p.offY + (p.north-lat)*p.scale
+}
+
+func (m *WorldMapC) sync() {
+	w, h := m.layer.ViewportWidth(), m.layer.ViewportHeight()
+	if w <= 0 || h <= 0 {
+		return
+	}
+	out := NewBuffer(w, h)
+	m.draw(out, w, h)
+	m.layer.SetBuffer(out)
+}
+
+func (m *WorldMapC) draw(out *Buffer, w, h int) {
+	canvas := newBrailleCanvas(w, h)
+	proj := newMapProjection(m.region, w*2, h*4)
+	for _, coast := range worldCoasts {
+		n := len(coast) / 2
+		for i := range n {
+			j := (i + 1) % n
+			lon0, lat0, lon1, lat1 := coast[2*i], coast[2*i+1], coast[2*j], coast[2*j+1]
+			if math.Abs(lon1-lon0) > 180 {
+				continue // crosses the date line
+			}
+			x0, y0 := proj.dot(lon0, lat0)
+			x1, y1 := proj.dot(lon1, lat1)
+			canvas.line(x0, y0, x1, y1)
+		}
+	}
+	canvas.draw(out, m.coastStyle)
+
+	if m.markers == nil {
+		return
+	}
+	for _, mk := range *m.markers {
+		dx, dy := proj.dot(mk.Lon, mk.Lat)
+		x, y := int(dx/2), int(dy/4)
+		if dx < 0 || dy < 0 || x >= w || y >= h {
+			continue
+		}
+		r := mk.Rune
+		if r == 0 {
+			r = '●'
+		}
+		out.Set(x, y, Cell{Rune: r, Style: mk.Style})
+		if mk.Label != "" && x+2 < w {
+			out.WriteStringFast(x+2, y, mk.Label, mk.Style, w-x-2)
+		}
+	}
+}
+
+// brailleCanvas is a grid of dots drawn as braille characters, two dots
+// across and four down in each cell.
+type brailleCanvas struct {
+	w, h  int // in cells
+	cells []uint8
+}
+
+func newBrailleCanvas(w, h int) *brailleCanvas {
+	return &brailleCanvas{w: w, h: h, cells: make([]uint8, w*h)}
+}
+
+// brailleBits are the bits of each dot in a cell, by row then column.
+var brailleBits = [4][2]uint8{{0x01, 0x08}, {0x02, 0x10}, {0x04, 0x20}, {0x40, 0x80}}
+
+// set sets the dot at (x, y), ignoring dots off the canvas.
+func (c *brailleCanvas) set(x, y int) {
+	if x < 0 || y < 0 || x >= c.w*2 || y >= c.h*4 {
+		return
+	}
+	c.cells[y/4*c.w+x/2] |= brailleBits[y%4][x%2]
+}
+
+// line sets the dots from (x0, y0) to (x1, y1), clipped to the canvas
+// first so lines running far off it at high zoom cost nothing.
+func (c *brailleCanvas) line(x0, y0, x1, y1 float64) {
+	t0, t1 := 0.0, 1.0
+	dx, dy := x1-x0, y1-y0
+	// Liang-Barsky: narrow [t0, t1] to the part inside each edge
+	for _, e := range [4][2]float64{{-dx, x0}, {dx, float64(c.w*2) - x0}, {-dy, y0}, {dy, float64(c.h*4) - y0}} {
+		p, q := e[0], e[1]
+		switch {
+		case p == 0:
+			if q < 0 {
+				return
+			}
+		case p < 0:
+			t0 = max(t0, q/p)
+		default:
+			t1 = min(t1, q/p)
+		}
+	}
+	if t0 > t1 {
+		return
+	}
+	x0, y0, x1, y1 = x0+dx*t0, y0+dy*t0, x0+dx*t1, y0+dy*t1
+	steps := int(math.Ceil(max(math.Abs(x1-x0), math.Abs(y1-y0))))
+	for i := 0; i <= steps; i++ {
+		t := 0.0
+		if steps > 0 {
+			t = float64(i) / float64(steps)
+		}
+		c.set(int(math.Floor(x0+(x1-x0)*t)), int(math.Floor(y0+(y1-y0)*t)))
+	}
+}
+
+func (c *brailleCanvas) draw(out *Buffer, style Style) {
+	for i, bits := range c.cells {
+		if bits != 0 {
+			out.Set(i%c.w, i/c.w, Cell{Rune: 0x2800 + rune(bits), Style: style})
+		}
+	}
+}
+
+// worldCoasts are rough outlines of the continents and larger islands, as
+// longitude, latitude pairs.
+var worldCoasts = [][]float64{
+	// North America
+	{-166, 68.9, -156.8, 71.3, -141, 69.6, -128, 70.2, -115, 68, -96, 68, -94, 61, -94, 58.8,
+		-88, 56.5, -82, 55, -79.5, 51.5, -78.5, 55, -77, 60, -73, 62.5, -68, 61, -64.5, 60.3,
+		-61.5, 56, -56, 52, -59, 48, -64, 46, -66, 44.5, -70, 43.5, -70, 41.7, -74, 40.5, -76, 38,
+		-76, 35, -81, 31.5, -80, 27, -80.4, 25.2, -81.8, 26, -82.8, 28, -84, 30, -88, 30.3,
+		-90, 29.2, -94, 29.6, -97.2, 27.8, -97.5, 25, -97.8, 22, -96, 19, -94.5, 18.2, -91, 18.8,
+		-90.5, 21, -87, 21.5, -88, 18, -88.5, 16, -84, 15.8, -83.2, 14.9, -83.7, 11, -82, 9,
+		-79.5, 9.5, -77.5, 8.5, -80, 7.3, -83, 8.3, -85.7, 10, -87.5, 13, -91.5, 14, -94.5, 16,
+		-97, 15.8, -102, 18, -105.5, 20.5, -105.7, 23, -109, 26.5, -112.2, 29.5, -114.7, 31.7,
+		-112.8, 27.5, -110, 23, -112, 25, -114.5, 28, -116.5, 31.5, -117.2, 32.7, -120.6, 34.6,
+		-122.5, 37.8, -124.2, 40.4, -124, 46, -124.7, 48.4, -123, 49, -127, 50.5, -130.3, 54.5,
+		-133, 57, -137, 58.5, -140, 59.7, -146, 60.8, -150, 59.5, -154, 57, -158, 56, -162, 55,
+		-164.7, 54.4, -160, 56.5, -157.5, 58.5, -162, 58.6, -165, 60.5, -164.5, 63, -161, 64.5,
+		-166, 65.3},
+	// Greenland
+	{-73, 78, -60, 82, -40, 83.5, -20, 82, -18, 77, -22, 70.5, -32, 68, -40, 65, -43.5, 60,
+		-48, 61, -52, 65, -54, 69, -55, 72, -62, 76},
+	// Baffin, Victoria and Ellesmere islands
+	{-61.5, 66.5, -64.5, 63.5, -71, 62, -75, 64.5, -78, 70, -84, 73.5, -80, 73.7, -70, 71},
+	{-118, 71, -102, 73, -101, 69, -110, 68.5},
+	{-90, 77, -75, 79, -62, 82.5, -80, 83, -95, 81},
+	// Cuba and Hispaniola
+	{-84.9, 21.9, -82, 23.1, -77, 22.3, -74.2, 20.2, -77.7, 19.9, -80, 21.7},
+	{-74.4, 18.5, -72.7, 19.9, -69.9, 19.7, -68.4, 18.6, -71.5, 17.7},
+	// South America
+	{-77.5, 8.5, -75.5, 10.5, -71.5, 12.4, -68, 10.5, -62, 10.7, -60, 8.5, -57, 6, -52, 4.8,
+		-50, 1.8, -48.5, -1, -44, -2.5, -39, -3.5, -35, -5.5, -35, -9, -38.5, -13, -39, -17.5,
+		-40.5, -21, -43, -23, -48.5, -26, -48.5, -28.5, -51, -31, -53, -34, -57, -35, -57.5, -38.3,
+		-62, -39, -65, -42, -65.5, -45, -67.5, -46.5, -65.8, -47.8, -69, -51, -68.5, -52.5, -71, -54,
+		-74.5, -52.5, -75.5, -48, -74, -43, -73.5, -37, -71.6, -33, -71.5, -28, -70.5, -23,
+		-70.3, -18.3, -75, -15.5, -77, -12, -79.5, -7.5, -81.2, -5, -80, -2, -80, 1, -78.7, 2,
+		-77.3, 4, -77.5, 7},
+	// Eurasia
+	{-5.6, 36, -9, 37, -8.8, 42.5, -9.3, 43.2, -1.8, 43.4, -1.2, 46, -4.5, 47.9, -1.8, 48.7,
+		1.5, 50.2, 4, 51.5, 5, 53.2, 8.6, 53.9, 8.5, 57.1, 10.5, 57.7, 10.8, 56, 12, 54.2, 14.5, 54,
+		18.5, 54.6, 21, 55.5, 21.5, 57.5, 24, 57.2, 23.5, 59.2, 30, 59.9, 22.5, 60.3, 21.4, 61.5,
+		21.5, 63.5, 25.3, 65, 22, 65.8, 17.5, 62.5, 18.5, 60, 16.5, 57, 14.3, 55.5, 12.8, 56,
+		11.5, 58.5, 10.5, 59.5, 8, 58, 5.5, 58.9, 5, 61.5, 7, 62.8, 10.5, 64.5, 14, 67.5, 16, 68.8,
+		19.5, 70, 23.5, 71, 28, 71, 31, 70, 33, 69.3, 41, 67, 44, 68.5, 53, 68.5, 60, 69.8, 66, 69,
+		70, 73, 73, 68.5, 80, 72.5, 87, 74, 100, 76.5, 104, 77.7, 113, 73.7, 120, 73, 130, 71,
+		140, 72.5, 150, 71.5, 160, 69.6, 170, 70, 180, 68.9, -172, 66, -173, 64.3, 179, 62.5,
+		174, 61.8, 166, 60, 163, 56, 162, 54, 158, 51.5, 156.5, 51, 155.5, 55, 156, 57.5, 163, 62.3,
+		155, 59.3, 148, 59.3, 141, 58.5, 137, 54, 141, 52.5, 140, 48, 135, 43.5, 131, 42.6,
+		129.5, 41, 129.4, 37, 129.3, 35.2, 126.5, 34.4, 126.3, 36.8, 125, 38, 124.3, 39.9,
+		121.5, 39, 122, 40.5, 118, 39, 117.6, 38.6, 119, 37.2, 122.6, 37.4, 120.3, 36, 119.2, 34.5,
+		121, 32, 122, 30, 120, 26.5, 119, 25, 116.5, 23, 113.5, 22.2, 110.5, 21.2, 108, 21.5,
+		106.5, 20, 105.7, 19, 106.7, 17, 108.8, 15.5, 109.3, 12, 107, 10.5, 105, 8.6, 104.8, 10.4,
+		103, 11, 100.2, 13.5, 99.2, 10.5, 100.3, 7.5, 102.2, 6.2, 103.4, 4, 104.2, 1.4, 103.3, 1.5,
+		101.3, 2.9, 100.3, 5.5, 98.3, 8, 98.5, 12, 97.7, 16.5, 94.3, 16, 94.5, 19, 92.3, 20.7,
+		91.8, 22.5, 90.3, 21.8, 88.5, 21.7, 87, 21.5, 86.5, 20, 84, 18.3, 82.3, 16.6, 80.3, 15.8,
+		80, 13, 79.8, 10.3, 77.5, 8.1, 76.3, 9.8, 74.8, 12.8, 73.5, 15.8, 72.8, 19, 72.6, 21.3,
+		70, 22.7, 68.5, 23.5, 66.7, 25.4, 61.6, 25.2, 57.3, 25.8, 56.4, 27.1, 54.5, 26.6,
+		51.5, 27.9, 50, 30, 48, 30, 48.5, 28.4, 50, 26.5, 51.6, 24.3, 54, 24.1, 56, 26, 56.4, 24.5,
+		58.7, 23.5, 59.8, 22.5, 57.8, 19, 55.3, 17.4, 52, 16, 48.5, 14, 45, 12.8, 43.3, 12.7,
+		42.7, 16.4, 39.2, 21.5, 36.5, 26, 35, 28, 34.2, 31.3, 35, 33, 36, 34.8, 36, 36.8, 32, 36.3,
+		30.5, 36.5, 28, 36.7, 26.5, 38.5, 26.2, 40.3, 26, 40.8, 23, 40.5, 24, 38, 22.5, 36.5,
+		21.5, 37.5, 21, 39.5, 19.4, 41, 19.5, 42, 16, 43.5, 13.6, 45.7, 12.3, 45.3, 12.5, 44,
+		14, 42.5, 16, 41.4, 18.5, 40.2, 16.5, 39, 15.6, 38, 15.8, 40, 14, 41, 12.5, 41.8, 10.5, 43,
+		9, 44.4, 7.5, 43.8, 4.5, 43.4, 3, 43, 3.2, 41.9, 0.8, 41, -0.3, 39.5, 0, 38.8, -1, 37.5,
+		-2.1, 36.7, -4.5, 36.6},
+	// Black and Caspian seas
+	{27.5, 42.4, 28.6, 44.3, 30.8, 46.5, 33.5, 46, 32.5, 45.4, 33.5, 44.5, 36.5, 45.2, 38, 47,
+		39.5, 47, 37.5, 44.8, 40, 43.4, 41.6, 41.6, 38, 41, 33, 42, 29, 41.2},
+	{47, 45, 49, 46.5, 53, 47, 53, 45, 51, 44.5, 52.5, 42, 53.9, 40.5, 53, 37.5, 50, 37.2,
+		49, 38.5, 49.5, 40.3, 48, 42, 47.5, 43},
+	// Great Britain, Ireland and Iceland
+	{-5.7, 50, 1.4, 51.2, 1.7, 52.7, 0.3, 53.5, -1.5, 55, -2, 56, -1.8, 57.6, -3.5, 58.6,
+		-5, 58.6, -6.2, 57, -5.6, 55.3, -3, 54.8, -3.2, 53.4, -4.6, 53.2, -4.2, 52.2, -5.2, 51.7,
+		-3.2, 51.4},
+	{-6, 52.2, -6.2, 53.9, -5.9, 55.2, -8, 55.2, -10, 54.2, -10, 52, -8, 51.5},
+	{-22.5, 63.9, -24, 65.5, -22, 66.4, -16, 66.5, -13.6, 65.2, -15, 64.3, -18.7, 63.4},
+	// Japan, Sri Lanka and the Philippines
+	{129.7, 33.2, 130.2, 31.3, 131.5, 31.5, 132, 33.5, 133.5, 33.5, 135, 33.5, 136.9, 34.3,
+		139, 34.8, 140.8, 35.7, 141, 38.3, 142, 39.6, 141.3, 41.4, 143.3, 42, 145.6, 43.3,
+		144.3, 44.1, 141.8, 45.5, 141.3, 43.3, 140, 42.3, 140, 40.5, 139.8, 38.5, 137.5, 37.3,
+		136, 35.7, 133, 35.5, 131, 34.5},
+	{79.8, 8, 80.2, 9.8, 81.9, 7.5, 81, 6},
+	{120.6, 18.5, 122.2, 18.5, 122, 16, 121.6, 14, 123.8, 13, 120.6, 14.3, 120, 16.2},
+	// Africa and Madagascar
+	{-5.9, 35.8, -2, 35.1, 1, 36.5, 5, 36.8, 9.8, 37.3, 11, 37, 11, 35.2, 10, 34, 11.5, 33,
+		15.2, 32.3, 19.5, 30.5, 20, 32.1, 23, 32.6, 25, 31.7, 29, 30.9, 32.3, 31.3, 32.5, 29.9,
+		33.5, 27, 35.5, 23.5, 37.3, 21, 38.5, 18, 39.7, 15.5, 41.5, 13.5, 43.3, 11.6, 45, 10.4,
+		51.3, 11.8, 51, 10.4, 49.5, 6, 48, 4.3, 46, 2, 42, -1, 40, -3, 39.3, -5, 39.5, -8,
+		40.5, -10.5, 40.6, -15, 36.9, -17.8, 35, -20, 35.5, -23.7, 32.9, -25.9, 32.5, -28.5,
+		31, -30, 28, -32.8, 25.6, -34, 22, -34.2, 20, -34.8, 18.4, -34, 18, -32, 16.5, -28.5,
+		15, -26.5, 14.5, -22.8, 13, -20, 11.8, -17, 12.3, -13.5, 13.7, -10.7, 12.9, -7.5, 12.2, -6,
+		11, -3.9, 9, -1, 9.5, 2, 9.8, 3.8, 8.5, 4.5, 6, 4.3, 4.5, 6.3, 1.5, 6.2, -2, 4.8, -4.7, 5.2,
+		-7.5, 4.4, -9.5, 5.4, -11.5, 6.9, -13.3, 9, -15, 11, -16.7, 12.5, -17.5, 14.7, -16.5, 16.2,
+		-16, 19, -17, 21, -16.5, 23.5, -14.5, 26.1, -13, 27.6, -10, 29.5, -9.8, 31.5, -8.5, 33.3,
+		-6.8, 34},
+	{49.3, -12, 50.5, -15.5, 49.5, -17, 47.5, -24.7, 45.2, -25.5, 43.7, -22, 44.4, -16.2, 47.5, -14.5},
+	// Sumatra, Borneo, Java and New Guinea
+	{95.3, 5.6, 98, 4, 104, -2, 106, -5.9, 104.5, -5.9, 101, -2.5, 98.7, 1.7},
+	{109, -0.5, 110, -3, 114.5, -3.6, 116.5, -3.5, 116, -1, 117.6, 1, 119, 5, 117, 7, 115.5, 5.2,
+		113, 3.2, 111, 1.8, 109.6, 2},
+	{105.2, -6.8, 108, -7.8, 111, -8.2, 114.5, -8.7, 114.4, -7.7, 110.5, -6.8, 108.5, -6.3, 106, -5.9},
+	{131, -1.4, 134, -0.9, 137, -1.5, 141, -2.6, 145.8, -5, 147.6, -6.1, 147.2, -7.4, 150.2, -10.4,
+		147, -10, 144, -7.7, 141, -9.1, 138.5, -8.3, 137.7, -5.2, 135, -4.4, 132, -2.8},
+	// Australia, Tasmania and New Zealand
+	{113.5, -22, 114, -26.5, 115, -31.5, 115, -34.3, 118, -35, 123.5, -33.9, 126, -32.3,
+		131, -31.5, 134, -32.8, 136, -34.8, 138, -35.6, 139.6, -37.1, 141, -38.3, 144, -38.3,
+		146.3, -39.1, 148.2, -37.8, 150, -37.5, 151.2, -33.9, 153, -31, 153.5, -28.2, 153, -25.3,
+		150.8, -22.5, 149, -20.5, 146.3, -19, 145.3, -15, 143.5, -14, 142.5, -10.7, 141.6, -12.8,
+		141.5, -17, 139.5, -17.5, 137.8, -16, 135.5, -15, 136.8, -12.3, 133, -11.3, 130.2, -12.3,
+		129.5, -15, 127.5, -14, 125.5, -14.5, 122.2, -17.3, 121, -19.5, 116.7, -20.6},
+	{144.6, -40.7, 148.3, -40.9, 148, -43.2, 146.8, -43.6, 145.2, -42.2},
+	{172.7, -34.4, 174.5, -36.8, 175.9, -37.5, 178.5, -37.7, 177.9, -39.2, 176.9, -39.6,
+		175.2, -41.6, 174.6, -41.3, 174, -39.3, 174.6, -37.2},
+	{172.7, -40.5, 174.3, -41.7, 173, -43.8, 171.2, -44.5, 169.2, -46.6, 166.5, -46, 167.3, -44.5,
+		170.5, -42.9, 172.2, -41},
+	// Antarctica
+	{-180, -78, -160, -77, -140, -75, -120, -74, -100, -73, -75, -73, -62, -64, -57, -63.5,
+		-60, -70, -60, -75, -40, -78, -20, -73, 0, -70, 30, -69.5, 60, -67.5, 80, -67, 100, -66,
+		120, -66.5, 140, -66.5, 160, -70, 170, -72, 165, -78, 180, -78},
+}
+
+func (t *Template) compileWorldMapC(v *WorldMapC, parent int16, depth int) int16 {
+	t.collectBindings(v)
+	layerView := LayerView(v.layer).Grow(v.grow)
+	if v.height > 0 {
+		layerView = layerView.ViewHeight(v.height)
+	}
+	return t.compileLayerViewC(layerView, parent, depth)
+}
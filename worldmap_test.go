@@ -0,0 +1,60 @@
+package glyph
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWorldMap(t *testing.T) {
+	markers := []MapMarker{
+		{Lat: 0, Lon: 0, Label: "null island", Style: Style{FG: Red}},
+		{Lat: 51.5, Lon: -0.1, Rune: 'x'},
+	}
+	m := WorldMap(&markers).Region(MapRegion{-90, -40, 90, 40}).Height(20)
+	tmpl := Build(VBox(m))
+	buf := NewBuffer(90, 20)
+	tmpl.Execute(buf, 90, 20)
+
+	// 180° across 180 dots and 80° down 80: a dot a degree, the middle at 45,10
+	if c := buf.Get(45, 10); c.Rune != '●' || c.Style.FG != Red {
+		t.Errorf("marker at %q, %+v:\n%s", c.Rune, c.Style, buf.String())
+	}
+	if got := buf.GetLine(10); !strings.Contains(got, "● null island") {
+		t.Errorf("label missing: %q", got)
+	}
+	if strings.ContainsRune(buf.String(), 'x') {
+		t.Error("marker outside the region drawn")
+	}
+	coast := 0
+	for _, r := range buf.String() {
+		if r > 0x2800 && r <= 0x28ff {
+			coast++
+		}
+	}
+	if coast < 50 {
+		t.Errorf("only %d cells of coastline drawn", coast)
+	}
+
+	m.Zoom(2)
+	if r := m.Viewed(); r != (MapRegion{-45, -20, 45, 20}) {
+		t.Errorf("zoomed to %+v", r)
+	}
+	m.Zoom(0.1)
+	if r := m.Viewed(); r != (MapRegion{-180, -90, 180, 90}) {
+		t.Errorf("zoomed out past the world to %+v", r)
+	}
+	m.View(RegionEurope)
+	m.Pan(10, 0)
+	if r := m.Viewed(); r.East != 180 || r.West != 110 {
+		t.Errorf("panned off the world to %+v", r)
+	}
+}
+
+func TestBrailleCanvas(t *testing.T) {
+	c := newBrailleCanvas(2, 1)
+	c.line(0, 0, 0, 3)
+	c.line(-10, 3.5, 10, 3.5)
+	if c.cells[0] != 0x47|0x80 || c.cells[1] != 0xc0 {
+		t.Errorf("cells = %#x %#x", c.cells[0], c.cells[1])
+	}
+}